@@ -66,6 +66,10 @@ var (
 	prometheusMetricsPath     string
 	adapterClientRequestQPS   float32
 	adapterClientRequestBurst int
+	metricsStoreBackend       string
+	metricsStoreTTL           time.Duration
+	metricsStoreAddress       string
+	metricsStoreTLS           bool
 )
 
 func (a *Adapter) makeProvider(ctx context.Context, globalHTTPTimeout time.Duration, maxConcurrentReconciles int) (provider.MetricsProvider, <-chan struct{}, error) {
@@ -119,7 +123,19 @@ func (a *Adapter) makeProvider(ctx context.Context, globalHTTPTimeout time.Durat
 		return nil, nil, err
 	}
 
-	return kedaprovider.NewProvider(ctx, logger, handler, mgr.GetClient(), namespace, externalMetricsInfo, externalMetricsInfoLock), stopCh, nil
+	metricsStore, err := kedaprovider.NewMetricsStore(kedaprovider.MetricsStoreConfig{
+		Backend:   metricsStoreBackend,
+		TTL:       metricsStoreTTL,
+		Address:   metricsStoreAddress,
+		Password:  os.Getenv("KEDA_METRICS_STORE_PASSWORD"),
+		EnableTLS: metricsStoreTLS,
+	})
+	if err != nil {
+		logger.Error(err, "failed to create the metrics store")
+		return nil, nil, err
+	}
+
+	return kedaprovider.NewProvider(ctx, logger, handler, mgr.GetClient(), namespace, externalMetricsInfo, externalMetricsInfoLock, metricsStore), stopCh, nil
 }
 
 func runScaledObjectController(ctx context.Context, mgr manager.Manager, scaleHandler scaling.ScaleHandler, logger logr.Logger, externalMetricsInfo *[]provider.ExternalMetricInfo, externalMetricsInfoLock *sync.RWMutex, maxConcurrentReconciles int, stopCh chan<- struct{}) error {
@@ -183,6 +199,10 @@ func main() {
 	cmd.Flags().StringVar(&prometheusMetricsPath, "metrics-path", "/metrics", "Set the path for the prometheus metrics endpoint")
 	cmd.Flags().Float32Var(&adapterClientRequestQPS, "kube-api-qps", 20.0, "Set the QPS rate for throttling requests sent to the apiserver")
 	cmd.Flags().IntVar(&adapterClientRequestBurst, "kube-api-burst", 30, "Set the burst for throttling requests sent to the apiserver")
+	cmd.Flags().StringVar(&metricsStoreBackend, "metrics-store", kedaprovider.MetricsStoreNone, "Set where the metric values are stored to be shared between requests: none, memory or redis to share them between the replicas")
+	cmd.Flags().DurationVar(&metricsStoreTTL, "metrics-store-ttl", 10*time.Second, "Set how long the stored metric values are served before querying the scalers again")
+	cmd.Flags().StringVar(&metricsStoreAddress, "metrics-store-address", "", "Set the address of the Redis metrics store, its password is read from KEDA_METRICS_STORE_PASSWORD")
+	cmd.Flags().BoolVar(&metricsStoreTLS, "metrics-store-tls", false, "Set to connect to the Redis metrics store with TLS")
 	if err := cmd.Flags().Parse(os.Args); err != nil {
 		return
 	}
@@ -204,6 +224,13 @@ func main() {
 		return
 	}
 
+	if pricingFile := os.Getenv("KEDA_API_CALL_PRICING_FILE"); pricingFile != "" {
+		if err := prommetrics.LoadAPICallPricing(pricingFile); err != nil {
+			logger.Error(err, "Invalid KEDA_API_CALL_PRICING_FILE")
+			return
+		}
+	}
+
 	kedaProvider, stopCh, err := cmd.makeProvider(ctx, time.Duration(globalHTTPTimeoutMS)*time.Millisecond, controllerMaxReconciles)
 	if err != nil {
 		logger.Error(err, "making provider")
@@ -32,6 +32,9 @@ const (
 	ConditionActive ConditionType = "Active"
 	// ConditionFallback specifies that the resource has a fallback active.
 	ConditionFallback ConditionType = "Fallback"
+	// ConditionReplicasMismatch specifies that spec.replicas of the scale target doesn't follow its /scale subresource.
+	// Not initialized with the other conditions, it is only added to the ScaledObjects checking it.
+	ConditionReplicasMismatch ConditionType = "ReplicasMismatch"
 )
 
 const (
@@ -147,6 +150,14 @@ func (c *Conditions) SetFallbackCondition(status metav1.ConditionStatus, reason
 	c.setCondition(ConditionFallback, status, reason, message)
 }
 
+// SetReplicasMismatchCondition modifies ReplicasMismatch Condition according to input parameters, adding it if missing
+func (c *Conditions) SetReplicasMismatchCondition(status metav1.ConditionStatus, reason string, message string) {
+	if c.getCondition(ConditionReplicasMismatch).Type == "" {
+		*c = append(*c, Condition{Type: ConditionReplicasMismatch})
+	}
+	c.setCondition(ConditionReplicasMismatch, status, reason, message)
+}
+
 // GetActiveCondition returns Condition of type Active
 func (c *Conditions) GetActiveCondition() Condition {
 	if *c == nil {
@@ -171,6 +182,15 @@ func (c *Conditions) GetFallbackCondition() Condition {
 	return c.getCondition(ConditionFallback)
 }
 
+// GetReplicasMismatchCondition returns Condition of type ReplicasMismatch, Unknown if it was never set
+func (c *Conditions) GetReplicasMismatchCondition() Condition {
+	condition := c.getCondition(ConditionReplicasMismatch)
+	if condition.Type == "" {
+		return Condition{Type: ConditionReplicasMismatch, Status: metav1.ConditionUnknown}
+	}
+	return condition
+}
+
 func (c Conditions) getCondition(conditionType ConditionType) Condition {
 	for i := range c {
 		if c[i].Type == conditionType {
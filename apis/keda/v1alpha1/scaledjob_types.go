@@ -17,7 +17,11 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"encoding/json"
+	"reflect"
+
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -43,7 +47,7 @@ type ScaledJob struct {
 
 // ScaledJobSpec defines the desired state of ScaledJob
 type ScaledJobSpec struct {
-	JobTargetRef *batchv1.JobSpec `json:"jobTargetRef"`
+	JobTargetRef *JobTargetRef `json:"jobTargetRef"`
 	// +optional
 	PollingInterval *int32 `json:"pollingInterval,omitempty"`
 	// +optional
@@ -61,6 +65,44 @@ type ScaledJobSpec struct {
 	Triggers        []ScaleTriggers `json:"triggers"`
 }
 
+// JobTargetRef describes the Jobs created by the ScaledJob, the Job spec is either inline or taken from fromTemplateRef
+type JobTargetRef struct {
+	batchv1.JobSpec `json:",inline"`
+	// FromTemplateRef takes the Job spec from a ConfigMap or a CronJob of the namespace of the ScaledJob
+	// instead of the inline template, the Jobs created follow the updates of the referenced resource
+	// +optional
+	FromTemplateRef *JobTemplateRef `json:"fromTemplateRef,omitempty"`
+}
+
+// JobTemplateRef references the resource holding the template of the Jobs of a ScaledJob
+type JobTemplateRef struct {
+	// Kind of the resource, the Job manifest is read from a key of a ConfigMap or from spec.jobTemplate of a CronJob
+	// +kubebuilder:validation:Enum=ConfigMap;CronJob
+	Kind string `json:"kind"`
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Key of the ConfigMap holding the Job manifest, job.yaml if not set
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// MarshalJSON omits the empty inline template when the Job spec is taken from fromTemplateRef,
+// an empty template doesn't pass the validation of the JobSpec schema
+func (r JobTargetRef) MarshalJSON() ([]byte, error) {
+	type jobTargetRef JobTargetRef
+	data, err := json.Marshal(jobTargetRef(r))
+	if err != nil || r.FromTemplateRef == nil || !reflect.DeepEqual(r.Template, corev1.PodTemplateSpec{}) {
+		return data, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "template")
+	return json.Marshal(fields)
+}
+
 // ScaledJobStatus defines the observed state of ScaledJob
 // +optional
 type ScaledJobStatus struct {
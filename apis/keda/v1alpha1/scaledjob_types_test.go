@@ -0,0 +1,40 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestJobTargetRefMarshalJSON(t *testing.T) {
+	fromTemplate := JobTargetRef{FromTemplateRef: &JobTemplateRef{Kind: "CronJob", Name: "nightly"}}
+	data, err := json.Marshal(ScaledJobSpec{JobTargetRef: &fromTemplate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"template"`) {
+		t.Errorf("Expected no template with fromTemplateRef but got %s", data)
+	}
+
+	inline := JobTargetRef{JobSpec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "worker"}}},
+	}}}
+	data, err = json.Marshal(inline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"template"`) {
+		t.Errorf("Expected the inline template but got %s", data)
+	}
+
+	var roundTrip JobTargetRef
+	if err := json.Unmarshal(data, &roundTrip); err != nil {
+		t.Fatal(err)
+	}
+	if roundTrip.Template.Spec.Containers[0].Name != "worker" || roundTrip.FromTemplateRef != nil {
+		t.Errorf("Expected the inline JobSpec to round trip but got %+v", roundTrip)
+	}
+}
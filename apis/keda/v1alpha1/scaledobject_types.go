@@ -65,6 +65,17 @@ const (
 	HealthStatusFailing HealthStatusType = "Failing"
 )
 
+const (
+	// ScaleStrategyAuto updates the replicas through the /scale subresource and warns if spec.replicas doesn't follow
+	ScaleStrategyAuto = "auto"
+
+	// ScaleStrategyScale updates the replicas through the /scale subresource
+	ScaleStrategyScale = "scale"
+
+	// ScaleStrategyReplicas patches spec.replicas of the scale target directly
+	ScaleStrategyReplicas = "replicas"
+)
+
 // ScaledObjectSpec is the spec for a ScaledObject resource
 type ScaledObjectSpec struct {
 	ScaleTargetRef *ScaleTarget `json:"scaleTargetRef"`
@@ -98,6 +109,13 @@ type AdvancedConfig struct {
 	HorizontalPodAutoscalerConfig *HorizontalPodAutoscalerConfig `json:"horizontalPodAutoscalerConfig,omitempty"`
 	// +optional
 	RestoreToOriginalReplicaCount bool `json:"restoreToOriginalReplicaCount,omitempty"`
+	// ScaleStrategy is how KEDA changes the replicas of the scale target: auto (default), scale or replicas.
+	// KEDA must be granted to patch the scale targets of the replicas strategy, see config/rbac/scale_target_role.yaml
+	// +optional
+	ScaleStrategy string `json:"scaleStrategy,omitempty"`
+	// DeferScalingDuringRollout defers the replica changes made by KEDA while the target Deployment is paused or rolling out
+	// +optional
+	DeferScalingDuringRollout bool `json:"deferScalingDuringRollout,omitempty"`
 }
 
 // HorizontalPodAutoscalerConfig specifies horizontal scale config
@@ -121,14 +139,38 @@ type ScaleTarget struct {
 
 // ScaleTriggers reference the scaler that will be used
 type ScaleTriggers struct {
+	// +kubebuilder:validation:MinLength=1
 	Type string `json:"type"`
+	// Name identifies the trigger in the metric names instead of its position, events and Prometheus metrics
+	// use a name generated from the trigger definition if not set
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
 	// +optional
-	Name     string            `json:"name,omitempty"`
+	Name string `json:"name,omitempty"`
+	// Metadata holds the settings specific to the scaler
 	Metadata map[string]string `json:"metadata"`
 	// +optional
 	AuthenticationRef *ScaledObjectAuthRef `json:"authenticationRef,omitempty"`
+	// MetricNameOverride replaces the generated name of the metric of the trigger in the HPA,
+	// it must be unique among the triggers and the trigger must expose a single metric
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-_a-z0-9]*[a-z0-9])?$`
+	// +optional
+	MetricNameOverride string `json:"metricNameOverride,omitempty"`
+	// MetricType is the target type of the metrics of the trigger in the HPA, AverageValue if not set,
+	// Utilization is only supported by the cpu and memory triggers
+	// +kubebuilder:validation:Enum=AverageValue;Value;Utilization
 	// +optional
 	MetricType autoscalingv2beta2.MetricTargetType `json:"metricType,omitempty"`
+	// UseCachedMetrics serves the HPA the metric values read less than a polling interval ago
+	// instead of querying the scaler on every request of the HPA
+	// +kubebuilder:default=false
+	// +optional
+	UseCachedMetrics bool `json:"useCachedMetrics,omitempty"`
+	// Timeout of the requests of the scaler to the event source, the global HTTP timeout of KEDA if not set,
+	// it must be positive
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -144,6 +186,10 @@ type ScaledObjectStatus struct {
 	OriginalReplicaCount *int32 `json:"originalReplicaCount,omitempty"`
 	// +optional
 	LastActiveTime *metav1.Time `json:"lastActiveTime,omitempty"`
+	// LastScaleFromZeroDuration is the time the scale target took to have a ready replica after the last
+	// activation of the ScaledObject from zero replicas
+	// +optional
+	LastScaleFromZeroDuration *metav1.Duration `json:"lastScaleFromZeroDuration,omitempty"`
 	// +optional
 	ExternalMetricNames []string `json:"externalMetricNames,omitempty"`
 	// +optional
@@ -174,8 +220,19 @@ type ScaledObjectAuthRef struct {
 	// Kind of the resource being referred to. Defaults to TriggerAuthentication.
 	// +optional
 	Kind string `json:"kind,omitempty"`
+	// PodIdentity overrides the identity of the referenced pod identity provider for this trigger only
+	// +optional
+	PodIdentity *AuthPodIdentityOverride `json:"podIdentity,omitempty"`
 }
 
 func init() {
 	SchemeBuilder.Register(&ScaledObject{}, &ScaledObjectList{})
 }
+
+// GetScaleStrategy returns the strategy used to change the replicas of the scale target
+func (so *ScaledObject) GetScaleStrategy() string {
+	if so.Spec.Advanced == nil || so.Spec.Advanced.ScaleStrategy == "" {
+		return ScaleStrategyAuto
+	}
+	return so.Spec.Advanced.ScaleStrategy
+}
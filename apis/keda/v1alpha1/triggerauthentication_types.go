@@ -78,6 +78,12 @@ type TriggerAuthenticationSpec struct {
 
 	// +optional
 	AzureKeyVault *AzureKeyVault `json:"azureKeyVault,omitempty"`
+
+	// +optional
+	Conjur *Conjur `json:"conjur,omitempty"`
+
+	// +optional
+	ExternalSecretProviders []ExternalSecretProvider `json:"externalSecretProviders,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -117,6 +123,17 @@ type AuthPodIdentity struct {
 	Provider PodIdentityProvider `json:"provider"`
 	// +optional
 	IdentityID string `json:"identityId"`
+	// +optional
+	Audience string `json:"audience,omitempty"`
+}
+
+// AuthPodIdentityOverride allows a single trigger to use a different identity
+// of the pod identity provider set in the referenced TriggerAuthentication
+type AuthPodIdentityOverride struct {
+	// +optional
+	IdentityID string `json:"identityId,omitempty"`
+	// +optional
+	Audience string `json:"audience,omitempty"`
 }
 
 // AuthSecretTargetRef is used to authenticate using a reference to a secret
@@ -225,6 +242,70 @@ type AzureKeyVaultCloudInfo struct {
 	ActiveDirectoryEndpoint string `json:"activeDirectoryEndpoint"`
 }
 
+// Conjur is used to authenticate using CyberArk Conjur
+type Conjur struct {
+	Address        string               `json:"address"`
+	Account        string               `json:"account"`
+	Authentication ConjurAuthentication `json:"authentication"`
+	Credential     ConjurCredential     `json:"credential"`
+	Secrets        []ConjurSecret       `json:"secrets"`
+
+	// +optional
+	ServiceID string `json:"serviceId,omitempty"`
+
+	// +optional
+	CACert *ValueFromSecret `json:"caCert,omitempty"`
+}
+
+// ConjurAuthentication contains the list of Conjur authentication methods
+type ConjurAuthentication string
+
+// Client authenticating to Conjur
+const (
+	ConjurAuthenticationAPIKey ConjurAuthentication = "apiKey"
+	ConjurAuthenticationJWT    ConjurAuthentication = "jwt"
+)
+
+// ConjurCredential defines the Conjur credentials depending on the authentication method
+type ConjurCredential struct {
+	// +optional
+	Login string `json:"login,omitempty"`
+
+	// +optional
+	APIKey *ValueFromSecret `json:"apiKey,omitempty"`
+
+	// +optional
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+}
+
+// ConjurSecret defines the mapping between the Conjur variable and the parameter
+type ConjurSecret struct {
+	Parameter  string `json:"parameter"`
+	VariableID string `json:"variableId"`
+}
+
+// ExternalSecretProvider is used to authenticate using a secret provider registered in the operator
+type ExternalSecretProvider struct {
+	// Name of the registered provider, e.g. akeyless
+	Name    string           `json:"name"`
+	Secrets []ExternalSecret `json:"secrets"`
+
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+
+	// +optional
+	Credentials []AuthSecretTargetRef `json:"credentials,omitempty"`
+}
+
+// ExternalSecret defines the mapping between the key of the secret in the provider to the parameter
+type ExternalSecret struct {
+	Parameter string `json:"parameter"`
+	Key       string `json:"key"`
+
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
 func init() {
 	SchemeBuilder.Register(&ClusterTriggerAuthentication{}, &ClusterTriggerAuthenticationList{})
 	SchemeBuilder.Register(&TriggerAuthentication{}, &TriggerAuthenticationList{})
@@ -18,11 +18,15 @@ package v1alpha1
 
 import (
 	"fmt"
+	"hash/fnv"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/apis/duck"
 )
@@ -32,6 +36,8 @@ const (
 	defaultPollingInterval = 30
 )
 
+var metricNameOverrideRegexp = regexp.MustCompile(`^[a-z0-9]([-_a-z0-9]*[a-z0-9])?$`)
+
 // +kubebuilder:object:root=true
 
 // WithTriggers is a specification for a resource with triggers
@@ -93,3 +99,116 @@ func (t *WithTriggers) GetPollingInterval() time.Duration {
 func (t *WithTriggers) GenerateIdenitifier() string {
 	return strings.ToLower(fmt.Sprintf("%s.%s.%s", t.Kind, t.Namespace, t.Name))
 }
+
+// GetTriggerNames returns the name of each trigger, in the order of the triggers.
+// Triggers without a name get one generated from their type, authenticationRef and the metadata identifying
+// what they scale on, so the name doesn't change when the triggers are reordered or their targets are edited.
+func (t *WithTriggers) GetTriggerNames() ([]string, error) {
+	names := make([]string, len(t.Spec.Triggers))
+	used := make(map[string]bool, len(t.Spec.Triggers))
+
+	// explicit names first, so that a generated name never takes one of them
+	for i, trigger := range t.Spec.Triggers {
+		if trigger.Name == "" {
+			continue
+		}
+		if errs := validation.IsDNS1123Label(trigger.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("trigger name %s is invalid: %s", trigger.Name, strings.Join(errs, ", "))
+		}
+		if used[trigger.Name] {
+			return nil, fmt.Errorf("trigger name %s is used more than once", trigger.Name)
+		}
+		used[trigger.Name] = true
+		names[i] = trigger.Name
+	}
+
+	for i, trigger := range t.Spec.Triggers {
+		if trigger.Name != "" {
+			continue
+		}
+		name := generateTriggerName(trigger)
+		// identical triggers get a suffix, they are interchangeable so their order doesn't matter
+		for n := 1; used[name]; n++ {
+			name = fmt.Sprintf("%s-%d", generateTriggerName(trigger), n)
+		}
+		used[name] = true
+		names[i] = name
+	}
+
+	return names, nil
+}
+
+// ValidateMetricNameOverrides checks that the metricNameOverride of the triggers are valid metric names
+// and that no two triggers override their metric name with the same name.
+func (t *WithTriggers) ValidateMetricNameOverrides() error {
+	used := make(map[string]int, len(t.Spec.Triggers))
+	for i, trigger := range t.Spec.Triggers {
+		if trigger.MetricNameOverride == "" {
+			continue
+		}
+		if len(trigger.MetricNameOverride) > validation.DNS1123LabelMaxLength || !metricNameOverrideRegexp.MatchString(trigger.MetricNameOverride) {
+			return fmt.Errorf("metricNameOverride %s of trigger %d is invalid: it must consist of at most %d lower case alphanumeric characters, '-' or '_', and start and end with an alphanumeric character",
+				trigger.MetricNameOverride, i, validation.DNS1123LabelMaxLength)
+		}
+		if previous, ok := used[trigger.MetricNameOverride]; ok {
+			return fmt.Errorf("metricNameOverride %s is used by triggers %d and %d", trigger.MetricNameOverride, previous, i)
+		}
+		used[trigger.MetricNameOverride] = i
+	}
+	return nil
+}
+
+// ValidateTimeouts checks that the timeouts of the triggers are positive, a zero timeout disables the timeout of the
+// requests to the event source and could block the scale loop
+func (t *WithTriggers) ValidateTimeouts() error {
+	for i, trigger := range t.Spec.Triggers {
+		if trigger.Timeout != nil && trigger.Timeout.Duration <= 0 {
+			return fmt.Errorf("timeout %s of trigger %d is invalid: it must be positive", trigger.Timeout.Duration, i)
+		}
+	}
+	return nil
+}
+
+// generateTriggerName returns "<type>-<hash>", the hash being computed over the fields identifying the trigger:
+// its type, authenticationRef and metadata but the targets, e.g. queue or host but not threshold or lagThreshold
+func generateTriggerName(trigger ScaleTriggers) string {
+	keys := make([]string, 0, len(trigger.Metadata))
+	for key := range trigger.Metadata {
+		if !isTriggerTargetKey(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	hash := fnv.New32a()
+	fmt.Fprintf(hash, "%s;", trigger.Type)
+	for _, key := range keys {
+		fmt.Fprintf(hash, "%s=%s;", key, trigger.Metadata[key])
+	}
+	if trigger.AuthenticationRef != nil {
+		fmt.Fprintf(hash, "%s/%s", trigger.AuthenticationRef.Kind, trigger.AuthenticationRef.Name)
+	}
+
+	return fmt.Sprintf("%s-%08x", strings.ToLower(trigger.Type), hash.Sum32())
+}
+
+// isTriggerTargetKey returns whether the metadata key holds a target of the trigger rather than what it scales on,
+// e.g. threshold, lagThreshold, activationQueueLength, targetValue or value
+func isTriggerTargetKey(key string) bool {
+	key = strings.ToLower(key)
+	// values identifying the metric, e.g. the dimension of a CloudWatch metric or the path of a JSON value
+	if key == "dimensionvalue" || key == "jsonpathvalue" {
+		return false
+	}
+	for _, prefix := range []string{"activation", "target"} {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	for _, suffix := range []string{"threshold", "value", "length", "count", "replicas"} {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetTriggerNames(t *testing.T) {
+	first := ScaleTriggers{Type: "cron", Metadata: map[string]string{"timezone": "UTC", "start": "0 * * * *", "end": "1 * * * *"}}
+	second := ScaleTriggers{Type: "cron", Metadata: map[string]string{"timezone": "UTC", "start": "2 * * * *", "end": "3 * * * *"}}
+	named := ScaleTriggers{Type: "cpu", Name: "cpu", Metadata: map[string]string{"value": "50"}}
+
+	withTriggers := func(triggers ...ScaleTriggers) *WithTriggers {
+		return &WithTriggers{Spec: WithTriggersSpec{Triggers: triggers}}
+	}
+
+	names, err := withTriggers(first, second, named).GetTriggerNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names[2] != "cpu" {
+		t.Errorf("Expected the explicit name cpu but got %s", names[2])
+	}
+	if names[0] == names[1] {
+		t.Errorf("Expected different names for different triggers but got %s", names[0])
+	}
+
+	reordered, err := withTriggers(named, second, first).GetTriggerNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reordered[2] != names[0] || reordered[1] != names[1] {
+		t.Errorf("Expected names to follow the triggers when reordered, got %v and %v", names, reordered)
+	}
+
+	duplicates, err := withTriggers(first, first).GetTriggerNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if duplicates[0] == duplicates[1] {
+		t.Errorf("Expected identical triggers to get different names but got %v", duplicates)
+	}
+
+	if _, err := withTriggers(named, named).GetTriggerNames(); err == nil {
+		t.Error("Expected error for duplicated trigger names but got success")
+	}
+	if _, err := withTriggers(ScaleTriggers{Type: "cron", Name: "Invalid_Name"}).GetTriggerNames(); err == nil {
+		t.Error("Expected error for invalid trigger name but got success")
+	}
+}
+
+func TestGetTriggerNamesIgnoresTargets(t *testing.T) {
+	trigger := ScaleTriggers{
+		Type:              "kafka",
+		Metadata:          map[string]string{"bootstrapServers": "kafka:9092", "topic": "orders", "lagThreshold": "10", "activationLagThreshold": "1"},
+		AuthenticationRef: &ScaledObjectAuthRef{Name: "kafka-auth"},
+	}
+	edited := ScaleTriggers{
+		Type:              "kafka",
+		Metadata:          map[string]string{"bootstrapServers": "kafka:9092", "topic": "orders", "lagThreshold": "50", "activationLagThreshold": "5"},
+		MetricType:        "Value",
+		AuthenticationRef: &ScaledObjectAuthRef{Name: "kafka-auth"},
+	}
+	otherTopic := ScaleTriggers{
+		Type:              "kafka",
+		Metadata:          map[string]string{"bootstrapServers": "kafka:9092", "topic": "payments", "lagThreshold": "10"},
+		AuthenticationRef: &ScaledObjectAuthRef{Name: "kafka-auth"},
+	}
+	otherAuth := ScaleTriggers{
+		Type:              "kafka",
+		Metadata:          map[string]string{"bootstrapServers": "kafka:9092", "topic": "orders", "lagThreshold": "10"},
+		AuthenticationRef: &ScaledObjectAuthRef{Name: "other-auth"},
+	}
+
+	name := generateTriggerName(trigger)
+	if editedName := generateTriggerName(edited); editedName != name {
+		t.Errorf("Expected the name %s to be unchanged when editing the thresholds but got %s", name, editedName)
+	}
+	if generateTriggerName(otherTopic) == name {
+		t.Errorf("Expected a different name for a different topic but got %s", name)
+	}
+	if generateTriggerName(otherAuth) == name {
+		t.Errorf("Expected a different name for a different authenticationRef but got %s", name)
+	}
+}
+
+func TestValidateMetricNameOverrides(t *testing.T) {
+	testCases := []struct {
+		overrides []string
+		isError   bool
+	}{
+		{[]string{"", ""}, false},
+		{[]string{"sqs_orders_backlog", "sqs_payments_backlog"}, false},
+		{[]string{"sqs_orders_backlog", ""}, false},
+		{[]string{"sqs_orders_backlog", "sqs_orders_backlog"}, true},
+		{[]string{"Orders"}, true},
+		{[]string{"orders_"}, true},
+		{[]string{"orders.backlog"}, true},
+	}
+
+	for _, testCase := range testCases {
+		withTriggers := &WithTriggers{}
+		for _, override := range testCase.overrides {
+			withTriggers.Spec.Triggers = append(withTriggers.Spec.Triggers, ScaleTriggers{Type: "aws-sqs-queue", MetricNameOverride: override})
+		}
+		err := withTriggers.ValidateMetricNameOverrides()
+		if testCase.isError && err == nil {
+			t.Errorf("Expected error for %v but got success", testCase.overrides)
+		}
+		if !testCase.isError && err != nil {
+			t.Errorf("Expected success for %v but got error %s", testCase.overrides, err)
+		}
+	}
+}
+
+func TestValidateTimeouts(t *testing.T) {
+	testCases := []struct {
+		timeouts []*metav1.Duration
+		isError  bool
+	}{
+		{[]*metav1.Duration{nil, nil}, false},
+		{[]*metav1.Duration{{Duration: 5 * time.Second}, nil}, false},
+		{[]*metav1.Duration{{Duration: 5 * time.Second}, {Duration: 0}}, true},
+		{[]*metav1.Duration{{Duration: -time.Second}}, true},
+	}
+
+	for _, testCase := range testCases {
+		withTriggers := &WithTriggers{}
+		for _, timeout := range testCase.timeouts {
+			withTriggers.Spec.Triggers = append(withTriggers.Spec.Triggers, ScaleTriggers{Type: "prometheus", Timeout: timeout})
+		}
+		err := withTriggers.ValidateTimeouts()
+		if testCase.isError && err == nil {
+			t.Errorf("Expected error for %v but got success", testCase.timeouts)
+		}
+		if !testCase.isError && err != nil {
+			t.Errorf("Expected success for %v but got error %s", testCase.timeouts, err)
+		}
+	}
+}
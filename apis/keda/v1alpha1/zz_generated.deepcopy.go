@@ -23,7 +23,7 @@ package v1alpha1
 
 import (
 	"k8s.io/api/autoscaling/v2beta2"
-	"k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -77,6 +77,21 @@ func (in *AuthPodIdentity) DeepCopy() *AuthPodIdentity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthPodIdentityOverride) DeepCopyInto(out *AuthPodIdentityOverride) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthPodIdentityOverride.
+func (in *AuthPodIdentityOverride) DeepCopy() *AuthPodIdentityOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthPodIdentityOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthSecretTargetRef) DeepCopyInto(out *AuthSecretTargetRef) {
 	*out = *in
@@ -280,6 +295,67 @@ func (in Conditions) DeepCopy() Conditions {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Conjur) DeepCopyInto(out *Conjur) {
+	*out = *in
+	in.Credential.DeepCopyInto(&out.Credential)
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]ConjurSecret, len(*in))
+		copy(*out, *in)
+	}
+	if in.CACert != nil {
+		in, out := &in.CACert, &out.CACert
+		*out = new(ValueFromSecret)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Conjur.
+func (in *Conjur) DeepCopy() *Conjur {
+	if in == nil {
+		return nil
+	}
+	out := new(Conjur)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConjurCredential) DeepCopyInto(out *ConjurCredential) {
+	*out = *in
+	if in.APIKey != nil {
+		in, out := &in.APIKey, &out.APIKey
+		*out = new(ValueFromSecret)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConjurCredential.
+func (in *ConjurCredential) DeepCopy() *ConjurCredential {
+	if in == nil {
+		return nil
+	}
+	out := new(ConjurCredential)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConjurSecret) DeepCopyInto(out *ConjurSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConjurSecret.
+func (in *ConjurSecret) DeepCopy() *ConjurSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(ConjurSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Credential) DeepCopyInto(out *Credential) {
 	*out = *in
@@ -295,6 +371,53 @@ func (in *Credential) DeepCopy() *Credential {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecret) DeepCopyInto(out *ExternalSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecret.
+func (in *ExternalSecret) DeepCopy() *ExternalSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSecretProvider) DeepCopyInto(out *ExternalSecretProvider) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]ExternalSecret, len(*in))
+		copy(*out, *in)
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = make([]AuthSecretTargetRef, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSecretProvider.
+func (in *ExternalSecretProvider) DeepCopy() *ExternalSecretProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSecretProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Fallback) DeepCopyInto(out *Fallback) {
 	*out = *in
@@ -390,6 +513,42 @@ func (in *HorizontalPodAutoscalerConfig) DeepCopy() *HorizontalPodAutoscalerConf
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobTargetRef) DeepCopyInto(out *JobTargetRef) {
+	*out = *in
+	in.JobSpec.DeepCopyInto(&out.JobSpec)
+	if in.FromTemplateRef != nil {
+		in, out := &in.FromTemplateRef, &out.FromTemplateRef
+		*out = new(JobTemplateRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobTargetRef.
+func (in *JobTargetRef) DeepCopy() *JobTargetRef {
+	if in == nil {
+		return nil
+	}
+	out := new(JobTargetRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobTemplateRef) DeepCopyInto(out *JobTemplateRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobTemplateRef.
+func (in *JobTemplateRef) DeepCopy() *JobTemplateRef {
+	if in == nil {
+		return nil
+	}
+	out := new(JobTemplateRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScaleTarget) DeepCopyInto(out *ScaleTarget) {
 	*out = *in
@@ -418,6 +577,11 @@ func (in *ScaleTriggers) DeepCopyInto(out *ScaleTriggers) {
 	if in.AuthenticationRef != nil {
 		in, out := &in.AuthenticationRef, &out.AuthenticationRef
 		*out = new(ScaledObjectAuthRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
 		**out = **in
 	}
 }
@@ -496,7 +660,7 @@ func (in *ScaledJobSpec) DeepCopyInto(out *ScaledJobSpec) {
 	*out = *in
 	if in.JobTargetRef != nil {
 		in, out := &in.JobTargetRef, &out.JobTargetRef
-		*out = new(v1.JobSpec)
+		*out = new(JobTargetRef)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.PollingInterval != nil {
@@ -593,6 +757,11 @@ func (in *ScaledObject) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScaledObjectAuthRef) DeepCopyInto(out *ScaledObjectAuthRef) {
 	*out = *in
+	if in.PodIdentity != nil {
+		in, out := &in.PodIdentity, &out.PodIdentity
+		*out = new(AuthPodIdentityOverride)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaledObjectAuthRef.
@@ -716,6 +885,11 @@ func (in *ScaledObjectStatus) DeepCopyInto(out *ScaledObjectStatus) {
 		in, out := &in.LastActiveTime, &out.LastActiveTime
 		*out = (*in).DeepCopy()
 	}
+	if in.LastScaleFromZeroDuration != nil {
+		in, out := &in.LastScaleFromZeroDuration, &out.LastScaleFromZeroDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.ExternalMetricNames != nil {
 		in, out := &in.ExternalMetricNames, &out.ExternalMetricNames
 		*out = make([]string, len(*in))
@@ -881,6 +1055,18 @@ func (in *TriggerAuthenticationSpec) DeepCopyInto(out *TriggerAuthenticationSpec
 		*out = new(AzureKeyVault)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Conjur != nil {
+		in, out := &in.Conjur, &out.Conjur
+		*out = new(Conjur)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalSecretProviders != nil {
+		in, out := &in.ExternalSecretProviders, &out.ExternalSecretProviders
+		*out = make([]ExternalSecretProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TriggerAuthenticationSpec.
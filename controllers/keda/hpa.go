@@ -32,6 +32,7 @@ import (
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	kedacontrollerutil "github.com/kedacore/keda/v2/controllers/keda/util"
 	"github.com/kedacore/keda/v2/pkg/scaling/executor"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
 	version "github.com/kedacore/keda/v2/version"
 )
 
@@ -50,7 +51,9 @@ func (r *ScaledObjectReconciler) createAndDeployNewHPA(ctx context.Context, logg
 		return err
 	}
 
-	err = r.Client.Create(ctx, hpa)
+	err = kedautil.RetryWrite(ctx, func() error {
+		return r.Client.Create(ctx, hpa)
+	})
 	if err != nil {
 		logger.Error(err, "Failed to create new HPA in cluster", "HPA.Namespace", scaledObject.Namespace, "HPA.Name", hpaName)
 		return err
@@ -82,6 +85,9 @@ func (r *ScaledObjectReconciler) newHPAForScaledObject(ctx context.Context, logg
 	} else {
 		behavior = nil
 	}
+	if executor.IsScalingDisabled() && r.kubeVersion.MinorVersion >= 18 {
+		behavior = disabledHPABehavior(behavior)
+	}
 
 	// label can have max 63 chars
 	labelName := getHPAName(scaledObject)
@@ -156,9 +162,11 @@ func (r *ScaledObjectReconciler) updateHPAIfNeeded(ctx context.Context, logger l
 	}
 
 	// DeepDerivative ignores extra entries in arrays which makes removing the last trigger not update things, so trigger and update any time the metrics count is different.
-	if len(hpa.Spec.Metrics) != len(foundHpa.Spec.Metrics) || !equality.Semantic.DeepDerivative(hpa.Spec, foundHpa.Spec) {
+	// DeepDerivative ignores the unset behavior too, so the HPA is updated explicitly when the scaling is resumed
+	if len(hpa.Spec.Metrics) != len(foundHpa.Spec.Metrics) || isHPAScalingDisabled(hpa) != isHPAScalingDisabled(foundHpa) ||
+		!equality.Semantic.DeepDerivative(hpa.Spec, foundHpa.Spec) {
 		logger.V(1).Info("Found difference in the HPA spec accordint to ScaledObject", "currentHPA", foundHpa.Spec, "newHPA", hpa.Spec)
-		if err = r.Client.Update(ctx, hpa); err != nil {
+		if err = kedautil.RetryWrite(ctx, func() error { return r.Client.Update(ctx, hpa) }); err != nil {
 			foundHpa.Spec = hpa.Spec
 			logger.Error(err, "Failed to update HPA", "HPA.Namespace", foundHpa.Namespace, "HPA.Name", foundHpa.Name)
 			return err
@@ -171,7 +179,7 @@ func (r *ScaledObjectReconciler) updateHPAIfNeeded(ctx context.Context, logger l
 
 	if !equality.Semantic.DeepDerivative(hpa.ObjectMeta.Labels, foundHpa.ObjectMeta.Labels) {
 		logger.V(1).Info("Found difference in the HPA labels accordint to ScaledObject", "currentHPA", foundHpa.ObjectMeta.Labels, "newHPA", hpa.ObjectMeta.Labels)
-		if err = r.Client.Update(ctx, hpa); err != nil {
+		if err = kedautil.RetryWrite(ctx, func() error { return r.Client.Update(ctx, hpa) }); err != nil {
 			foundHpa.ObjectMeta.Labels = hpa.ObjectMeta.Labels
 			logger.Error(err, "Failed to update HPA", "HPA.Namespace", foundHpa.Namespace, "HPA.Name", foundHpa.Name)
 			return err
@@ -182,6 +190,40 @@ func (r *ScaledObjectReconciler) updateHPAIfNeeded(ctx context.Context, logger l
 	return nil
 }
 
+// disabledHPABehavior returns the behavior with the scale up and down disabled, the HPA keeps computing the desired
+// replicas from the metrics but doesn't change the replicas while the scaling is disabled cluster-wide
+func disabledHPABehavior(behavior *autoscalingv2beta2.HorizontalPodAutoscalerBehavior) *autoscalingv2beta2.HorizontalPodAutoscalerBehavior {
+	disabled := &autoscalingv2beta2.HorizontalPodAutoscalerBehavior{}
+	if behavior != nil {
+		disabled = behavior.DeepCopy()
+	}
+
+	disabledPolicy := autoscalingv2beta2.DisabledPolicySelect
+	for _, rules := range []**autoscalingv2beta2.HPAScalingRules{&disabled.ScaleUp, &disabled.ScaleDown} {
+		if *rules == nil {
+			*rules = &autoscalingv2beta2.HPAScalingRules{}
+		}
+		// the API server requires at least a policy, it has no effect as the scaling is disabled
+		if len((*rules).Policies) == 0 {
+			(*rules).Policies = []autoscalingv2beta2.HPAScalingPolicy{{Type: autoscalingv2beta2.PercentScalingPolicy, Value: 100, PeriodSeconds: 15}}
+		}
+		(*rules).SelectPolicy = &disabledPolicy
+	}
+	return disabled
+}
+
+// isHPAScalingDisabled returns true if both the scale up and down of the HPA are disabled
+func isHPAScalingDisabled(hpa *autoscalingv2beta2.HorizontalPodAutoscaler) bool {
+	behavior := hpa.Spec.Behavior
+	if behavior == nil || behavior.ScaleUp == nil || behavior.ScaleDown == nil {
+		return false
+	}
+	isDisabled := func(rules *autoscalingv2beta2.HPAScalingRules) bool {
+		return rules.SelectPolicy != nil && *rules.SelectPolicy == autoscalingv2beta2.DisabledPolicySelect
+	}
+	return isDisabled(behavior.ScaleUp) && isDisabled(behavior.ScaleDown)
+}
+
 // deleteAndCreateHpa delete old HPA and create new one
 func (r *ScaledObjectReconciler) renameHPA(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, foundHpa *autoscalingv2beta2.HorizontalPodAutoscaler, gvkr *kedav1alpha1.GroupVersionKindResource) error {
 	logger.Info("Deleting old HPA", "HPA.Namespace", scaledObject.Namespace, "HPA.Name", foundHpa.Name)
@@ -0,0 +1,316 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keda
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	kedacontrollerutil "github.com/kedacore/keda/v2/controllers/keda/util"
+)
+
+const (
+	// scaledObjectScaleTargetIndex indexes ScaledObjects by the "<kind>/<name>" of their scale target
+	scaledObjectScaleTargetIndex = "spec.scaleTargetRef"
+	// scaledObjectAuthRefIndex indexes ScaledObjects by the "<kind>/<name>" of the authentications referenced by their triggers
+	scaledObjectAuthRefIndex = "spec.triggers.authenticationRef"
+	// triggerAuthenticationSecretIndex indexes TriggerAuthentications and ClusterTriggerAuthentications by the secrets they read
+	triggerAuthenticationSecretIndex = "spec.secretTargetRef.name"
+	// scaledJobTemplateRefIndex indexes ScaledJobs by the "<kind>/<name>" of the resource holding their Job template
+	scaledJobTemplateRefIndex = "spec.jobTargetRef.fromTemplateRef"
+
+	defaultScaleTargetKind    = "Deployment"
+	defaultAuthenticationKind = "TriggerAuthentication"
+)
+
+// setupScaledObjectIndexes registers the field indexes used to find the ScaledObjects affected by a change
+// of their scale target, of a TriggerAuthentication or of a secret without listing all the ScaledObjects
+func setupScaledObjectIndexes(ctx context.Context, indexer client.FieldIndexer) error {
+	if err := indexer.IndexField(ctx, &kedav1alpha1.ScaledObject{}, scaledObjectScaleTargetIndex, indexScaledObjectByScaleTarget); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, &kedav1alpha1.ScaledObject{}, scaledObjectAuthRefIndex, indexScaledObjectByAuthRef); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, &kedav1alpha1.TriggerAuthentication{}, triggerAuthenticationSecretIndex, indexTriggerAuthenticationBySecret); err != nil {
+		return err
+	}
+	return indexer.IndexField(ctx, &kedav1alpha1.ClusterTriggerAuthentication{}, triggerAuthenticationSecretIndex, indexTriggerAuthenticationBySecret)
+}
+
+// setupScaledJobIndexes registers the field index used to find the ScaledJobs affected by a change of their Job template
+func setupScaledJobIndexes(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &kedav1alpha1.ScaledJob{}, scaledJobTemplateRefIndex, indexScaledJobByTemplateRef)
+}
+
+func scaleTargetIndexKey(kind, name string) string {
+	if kind == "" {
+		kind = defaultScaleTargetKind
+	}
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+func authRefIndexKey(kind, name string) string {
+	if kind == "" {
+		kind = defaultAuthenticationKind
+	}
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+func indexScaledObjectByScaleTarget(obj client.Object) []string {
+	scaledObject, ok := obj.(*kedav1alpha1.ScaledObject)
+	if !ok || scaledObject.Spec.ScaleTargetRef == nil {
+		return nil
+	}
+	return []string{scaleTargetIndexKey(scaledObject.Spec.ScaleTargetRef.Kind, scaledObject.Spec.ScaleTargetRef.Name)}
+}
+
+func indexScaledObjectByAuthRef(obj client.Object) []string {
+	scaledObject, ok := obj.(*kedav1alpha1.ScaledObject)
+	if !ok {
+		return nil
+	}
+
+	var keys []string
+	seen := map[string]bool{}
+	for _, trigger := range scaledObject.Spec.Triggers {
+		if trigger.AuthenticationRef == nil {
+			continue
+		}
+		key := authRefIndexKey(trigger.AuthenticationRef.Kind, trigger.AuthenticationRef.Name)
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func indexScaledJobByTemplateRef(obj client.Object) []string {
+	scaledJob, ok := obj.(*kedav1alpha1.ScaledJob)
+	if !ok || scaledJob.Spec.JobTargetRef == nil || scaledJob.Spec.JobTargetRef.FromTemplateRef == nil {
+		return nil
+	}
+	templateRef := scaledJob.Spec.JobTargetRef.FromTemplateRef
+	return []string{fmt.Sprintf("%s/%s", templateRef.Kind, templateRef.Name)}
+}
+
+func indexTriggerAuthenticationBySecret(obj client.Object) []string {
+	var spec *kedav1alpha1.TriggerAuthenticationSpec
+	switch triggerAuthentication := obj.(type) {
+	case *kedav1alpha1.TriggerAuthentication:
+		spec = &triggerAuthentication.Spec
+	case *kedav1alpha1.ClusterTriggerAuthentication:
+		spec = &triggerAuthentication.Spec
+	default:
+		return nil
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	for _, secretRef := range spec.SecretTargetRef {
+		if !seen[secretRef.Name] {
+			seen[secretRef.Name] = true
+			names = append(names, secretRef.Name)
+		}
+	}
+	return names
+}
+
+// scaledObjectsForScaleTarget returns the ScaledObjects of the namespace scaling the target
+func scaledObjectsForScaleTarget(ctx context.Context, c client.Reader, namespace, kind, name string) ([]kedav1alpha1.ScaledObject, error) {
+	scaledObjects := &kedav1alpha1.ScaledObjectList{}
+	if err := c.List(ctx, scaledObjects, client.InNamespace(namespace), client.MatchingFields{scaledObjectScaleTargetIndex: scaleTargetIndexKey(kind, name)}); err != nil {
+		return nil, err
+	}
+	return scaledObjects.Items, nil
+}
+
+// scaledObjectsForAuthentication returns the ScaledObjects referencing the TriggerAuthentication or ClusterTriggerAuthentication,
+// an empty namespace looks in every namespace as ClusterTriggerAuthentications can be referenced from all of them
+func scaledObjectsForAuthentication(ctx context.Context, c client.Reader, namespace, kind, name string) ([]kedav1alpha1.ScaledObject, error) {
+	scaledObjects := &kedav1alpha1.ScaledObjectList{}
+	if err := c.List(ctx, scaledObjects, client.InNamespace(namespace), client.MatchingFields{scaledObjectAuthRefIndex: authRefIndexKey(kind, name)}); err != nil {
+		return nil, err
+	}
+	return scaledObjects.Items, nil
+}
+
+// scaledObjectsForSecret returns the ScaledObjects reading the secret through a TriggerAuthentication of its namespace
+// or through a ClusterTriggerAuthentication
+func scaledObjectsForSecret(ctx context.Context, c client.Reader, namespace, name string) ([]kedav1alpha1.ScaledObject, error) {
+	var scaledObjects []kedav1alpha1.ScaledObject
+
+	triggerAuthentications := &kedav1alpha1.TriggerAuthenticationList{}
+	if err := c.List(ctx, triggerAuthentications, client.InNamespace(namespace), client.MatchingFields{triggerAuthenticationSecretIndex: name}); err != nil {
+		return nil, err
+	}
+	for _, triggerAuthentication := range triggerAuthentications.Items {
+		items, err := scaledObjectsForAuthentication(ctx, c, namespace, defaultAuthenticationKind, triggerAuthentication.Name)
+		if err != nil {
+			return nil, err
+		}
+		scaledObjects = append(scaledObjects, items...)
+	}
+
+	// ClusterTriggerAuthentications read their secrets from the cluster object namespace,
+	// matching on the name only may refresh a few more ScaledObjects than needed but never misses one
+	clusterTriggerAuthentications := &kedav1alpha1.ClusterTriggerAuthenticationList{}
+	if err := c.List(ctx, clusterTriggerAuthentications, client.MatchingFields{triggerAuthenticationSecretIndex: name}); err != nil {
+		return nil, err
+	}
+	for _, clusterTriggerAuthentication := range clusterTriggerAuthentications.Items {
+		items, err := scaledObjectsForAuthentication(ctx, c, "", "ClusterTriggerAuthentication", clusterTriggerAuthentication.Name)
+		if err != nil {
+			return nil, err
+		}
+		scaledObjects = append(scaledObjects, items...)
+	}
+
+	return scaledObjects, nil
+}
+
+// scaledJobsForTemplate returns the ScaledJobs of the namespace taking their Job template from the resource
+func scaledJobsForTemplate(ctx context.Context, c client.Reader, namespace, kind, name string) ([]kedav1alpha1.ScaledJob, error) {
+	scaledJobs := &kedav1alpha1.ScaledJobList{}
+	if err := c.List(ctx, scaledJobs, client.InNamespace(namespace), client.MatchingFields{scaledJobTemplateRefIndex: fmt.Sprintf("%s/%s", kind, name)}); err != nil {
+		return nil, err
+	}
+	return scaledJobs.Items, nil
+}
+
+// scaledObjectRequests turns the ScaledObjects into reconcile requests, once per ScaledObject
+func scaledObjectRequests(scaledObjects []kedav1alpha1.ScaledObject) []reconcile.Request {
+	var requests []reconcile.Request
+	seen := map[client.ObjectKey]bool{}
+	for i := range scaledObjects {
+		key := client.ObjectKeyFromObject(&scaledObjects[i])
+		if !seen[key] {
+			seen[key] = true
+			requests = append(requests, reconcile.Request{NamespacedName: key})
+		}
+	}
+	return requests
+}
+
+// scaledObjectMapper maps the changes of the objects the ScaledObjects depend on to reconcile requests of these ScaledObjects,
+// looking them up through the field indexes, refresh is called for each of them before they are enqueued
+type scaledObjectMapper struct {
+	client  client.Reader
+	logger  logr.Logger
+	refresh func(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject)
+}
+
+func (m *scaledObjectMapper) requests(ctx context.Context, scaledObjects []kedav1alpha1.ScaledObject, err error) []reconcile.Request {
+	if err != nil {
+		m.logger.Error(err, "error looking up the affected ScaledObjects")
+		return nil
+	}
+	if m.refresh != nil {
+		for i := range scaledObjects {
+			m.refresh(ctx, &scaledObjects[i])
+		}
+	}
+	return scaledObjectRequests(scaledObjects)
+}
+
+func (m *scaledObjectMapper) forSecret(obj client.Object) []reconcile.Request {
+	ctx := context.Background()
+	scaledObjects, err := scaledObjectsForSecret(ctx, m.client, obj.GetNamespace(), obj.GetName())
+	return m.requests(ctx, scaledObjects, err)
+}
+
+func (m *scaledObjectMapper) forAuthentication(kind string) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		ctx := context.Background()
+		scaledObjects, err := scaledObjectsForAuthentication(ctx, m.client, obj.GetNamespace(), kind, obj.GetName())
+		return m.requests(ctx, scaledObjects, err)
+	}
+}
+
+func (m *scaledObjectMapper) forScaleTarget(kind string) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		ctx := context.Background()
+		scaledObjects, err := scaledObjectsForScaleTarget(ctx, m.client, obj.GetNamespace(), kind, obj.GetName())
+		return m.requests(ctx, scaledObjects, err)
+	}
+}
+
+// watchScaledObjectDependencies makes the controller reconcile the ScaledObjects when their scale target,
+// their TriggerAuthentications or ClusterTriggerAuthentications, or the secrets these read change
+func watchScaledObjectDependencies(blder *builder.Builder, mapper *scaledObjectMapper) *builder.Builder {
+	return blder.
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(mapper.forSecret)).
+		Watches(&source.Kind{Type: &kedav1alpha1.TriggerAuthentication{}}, handler.EnqueueRequestsFromMapFunc(mapper.forAuthentication("TriggerAuthentication")),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &kedav1alpha1.ClusterTriggerAuthentication{}}, handler.EnqueueRequestsFromMapFunc(mapper.forAuthentication("ClusterTriggerAuthentication")),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &appsv1.Deployment{}}, handler.EnqueueRequestsFromMapFunc(mapper.forScaleTarget("Deployment")),
+			builder.WithPredicates(kedacontrollerutil.PodTemplateChangedPredicate{})).
+		Watches(&source.Kind{Type: &appsv1.StatefulSet{}}, handler.EnqueueRequestsFromMapFunc(mapper.forScaleTarget("StatefulSet")),
+			builder.WithPredicates(kedacontrollerutil.PodTemplateChangedPredicate{}))
+}
+
+// scaledJobMapper maps the changes of the Job templates to reconcile requests of the ScaledJobs using them,
+// refresh is called for each of them before they are enqueued
+type scaledJobMapper struct {
+	client  client.Reader
+	logger  logr.Logger
+	refresh func(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob)
+}
+
+func (m *scaledJobMapper) forTemplate(kind string) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		ctx := context.Background()
+		scaledJobs, err := scaledJobsForTemplate(ctx, m.client, obj.GetNamespace(), kind, obj.GetName())
+		if err != nil {
+			m.logger.Error(err, "error looking up the affected ScaledJobs")
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(scaledJobs))
+		for i := range scaledJobs {
+			if m.refresh != nil {
+				m.refresh(ctx, &scaledJobs[i])
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&scaledJobs[i])})
+		}
+		return requests
+	}
+}
+
+// watchScaledJobTemplates makes the controller reconcile the ScaledJobs when the ConfigMap or the CronJob
+// holding their Job template changes
+func watchScaledJobTemplates(blder *builder.Builder, mapper *scaledJobMapper) *builder.Builder {
+	return blder.
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(mapper.forTemplate("ConfigMap")),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&source.Kind{Type: &batchv1.CronJob{}}, handler.EnqueueRequestsFromMapFunc(mapper.forTemplate("CronJob")),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keda
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+func TestIndexScaledObjectByScaleTarget(t *testing.T) {
+	so := &v1alpha1.ScaledObject{Spec: v1alpha1.ScaledObjectSpec{ScaleTargetRef: &v1alpha1.ScaleTarget{Name: "app"}}}
+	assert.Equal(t, []string{"Deployment/app"}, indexScaledObjectByScaleTarget(so))
+
+	so.Spec.ScaleTargetRef.Kind = "StatefulSet"
+	assert.Equal(t, []string{"StatefulSet/app"}, indexScaledObjectByScaleTarget(so))
+
+	assert.Nil(t, indexScaledObjectByScaleTarget(&v1alpha1.ScaledObject{}))
+	assert.Nil(t, indexScaledObjectByScaleTarget(&v1alpha1.ScaledJob{}))
+}
+
+func TestIndexScaledObjectByAuthRef(t *testing.T) {
+	so := &v1alpha1.ScaledObject{Spec: v1alpha1.ScaledObjectSpec{Triggers: []v1alpha1.ScaleTriggers{
+		{Type: "cpu"},
+		{Type: "rabbitmq", AuthenticationRef: &v1alpha1.ScaledObjectAuthRef{Name: "rabbitmq-auth"}},
+		{Type: "rabbitmq", AuthenticationRef: &v1alpha1.ScaledObjectAuthRef{Name: "rabbitmq-auth", Kind: "TriggerAuthentication"}},
+		{Type: "kafka", AuthenticationRef: &v1alpha1.ScaledObjectAuthRef{Name: "kafka-auth", Kind: "ClusterTriggerAuthentication"}},
+	}}}
+
+	assert.Equal(t, []string{"TriggerAuthentication/rabbitmq-auth", "ClusterTriggerAuthentication/kafka-auth"}, indexScaledObjectByAuthRef(so))
+	assert.Nil(t, indexScaledObjectByAuthRef(&v1alpha1.ScaledObject{}))
+}
+
+func TestIndexScaledJobByTemplateRef(t *testing.T) {
+	sj := &v1alpha1.ScaledJob{Spec: v1alpha1.ScaledJobSpec{JobTargetRef: &v1alpha1.JobTargetRef{
+		FromTemplateRef: &v1alpha1.JobTemplateRef{Kind: "ConfigMap", Name: "job-templates"},
+	}}}
+	assert.Equal(t, []string{"ConfigMap/job-templates"}, indexScaledJobByTemplateRef(sj))
+
+	sj.Spec.JobTargetRef.FromTemplateRef.Kind = "CronJob"
+	assert.Equal(t, []string{"CronJob/job-templates"}, indexScaledJobByTemplateRef(sj))
+
+	assert.Nil(t, indexScaledJobByTemplateRef(&v1alpha1.ScaledJob{Spec: v1alpha1.ScaledJobSpec{JobTargetRef: &v1alpha1.JobTargetRef{}}}))
+	assert.Nil(t, indexScaledJobByTemplateRef(&v1alpha1.ScaledJob{}))
+	assert.Nil(t, indexScaledJobByTemplateRef(&v1alpha1.ScaledObject{}))
+}
+
+func TestIndexTriggerAuthenticationBySecret(t *testing.T) {
+	spec := v1alpha1.TriggerAuthenticationSpec{SecretTargetRef: []v1alpha1.AuthSecretTargetRef{
+		{Parameter: "host", Name: "rabbitmq-secret", Key: "host"},
+		{Parameter: "password", Name: "rabbitmq-secret", Key: "password"},
+		{Parameter: "ca", Name: "rabbitmq-ca", Key: "ca.crt"},
+	}}
+
+	assert.Equal(t, []string{"rabbitmq-secret", "rabbitmq-ca"}, indexTriggerAuthenticationBySecret(&v1alpha1.TriggerAuthentication{Spec: spec}))
+	assert.Equal(t, []string{"rabbitmq-secret", "rabbitmq-ca"}, indexTriggerAuthenticationBySecret(&v1alpha1.ClusterTriggerAuthentication{Spec: spec}))
+	assert.Nil(t, indexTriggerAuthenticationBySecret(&v1alpha1.TriggerAuthentication{}))
+	assert.Nil(t, indexTriggerAuthenticationBySecret(&v1alpha1.ScaledObject{}))
+}
+
+func TestScaledObjectRequests(t *testing.T) {
+	scaledObjects := []v1alpha1.ScaledObject{
+		{ObjectMeta: v1.ObjectMeta{Namespace: "a", Name: "so"}},
+		{ObjectMeta: v1.ObjectMeta{Namespace: "b", Name: "so"}},
+		{ObjectMeta: v1.ObjectMeta{Namespace: "a", Name: "so"}},
+	}
+
+	requests := scaledObjectRequests(scaledObjects)
+	assert.Len(t, requests, 2)
+	assert.Equal(t, types.NamespacedName{Namespace: "a", Name: "so"}, requests[0].NamespacedName)
+	assert.Equal(t, types.NamespacedName{Namespace: "b", Name: "so"}, requests[1].NamespacedName)
+}
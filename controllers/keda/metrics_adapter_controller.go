@@ -97,7 +97,17 @@ func (r *MetricsScaledObjectReconciler) Reconcile(ctx context.Context, req ctrl.
 }
 
 func (r *MetricsScaledObjectReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	if err := setupScaledObjectIndexes(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		return err
+	}
+
+	// the reconcile clears the scalers cache of the ScaledObjects affected by a change of their dependencies
+	mapper := &scaledObjectMapper{
+		client: mgr.GetClient(),
+		logger: mgr.GetLogger().WithName("metrics-scaledobject-mapper"),
+	}
+
+	return watchScaledObjectDependencies(ctrl.NewControllerManagedBy(mgr), mapper).
 		For(&kedav1alpha1.ScaledObject{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Owns(&kedav1alpha1.ScaledObject{}).
 		WithOptions(options).
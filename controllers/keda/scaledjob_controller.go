@@ -38,11 +38,14 @@ import (
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	kedacontrollerutil "github.com/kedacore/keda/v2/controllers/keda/util"
 	"github.com/kedacore/keda/v2/pkg/eventreason"
+	"github.com/kedacore/keda/v2/pkg/scalers"
 	"github.com/kedacore/keda/v2/pkg/scaling"
+	"github.com/kedacore/keda/v2/pkg/scaling/resolver"
 )
 
 // +kubebuilder:rbac:groups=keda.sh,resources=scaledjobs;scaledjobs/finalizers;scaledjobs/status,verbs="*"
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs="*"
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch
 
 // ScaledJobReconciler reconciles a ScaledJob object
 type ScaledJobReconciler struct {
@@ -50,6 +53,8 @@ type ScaledJobReconciler struct {
 	Scheme            *runtime.Scheme
 	GlobalHTTPTimeout time.Duration
 	Recorder          record.EventRecorder
+	// ScaleLoopStallFactor restarts the scale loops which didn't complete within this many pollingIntervals, 0 disables it
+	ScaleLoopStallFactor int
 
 	scaleHandler scaling.ScaleHandler
 }
@@ -57,8 +62,31 @@ type ScaledJobReconciler struct {
 // SetupWithManager initializes the ScaledJobReconciler instance and starts a new controller managed by the passed Manager instance.
 func (r *ScaledJobReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
 	r.scaleHandler = scaling.NewScaleHandler(mgr.GetClient(), nil, mgr.GetScheme(), r.GlobalHTTPTimeout, mgr.GetEventRecorderFor("scale-handler"))
+	if err := mgr.Add(scaling.NewShutdownRunnable(r.scaleHandler, scaleHandlerShutdownTimeout)); err != nil {
+		return err
+	}
+	if r.ScaleLoopStallFactor > 0 {
+		if err := mgr.Add(scaling.NewScaleLoopWatchdogRunnable(r.scaleHandler, r.ScaleLoopStallFactor)); err != nil {
+			return err
+		}
+	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	if err := setupScaledJobIndexes(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		return err
+	}
+
+	// the scalers of the ScaledJobs resolve the environment from the Job template, they are rebuilt when it changes
+	mapper := &scaledJobMapper{
+		client: mgr.GetClient(),
+		logger: mgr.GetLogger().WithName("scaledjob-mapper"),
+		refresh: func(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob) {
+			if err := r.scaleHandler.ClearScalersCache(ctx, scaledJob); err != nil {
+				log.Log.Error(err, "error clearing scalers cache", "scaledJob.Namespace", scaledJob.Namespace, "scaledJob.Name", scaledJob.Name)
+			}
+		},
+	}
+
+	return watchScaledJobTemplates(ctrl.NewControllerManagedBy(mgr), mapper).
 		WithOptions(options).
 		// Ignore updates to ScaledJob Status (in this case metadata.Generation does not change)
 		// so reconcile loop is not started on Status updates
@@ -117,6 +145,9 @@ func (r *ScaledJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	conditions := scaledJob.Status.Conditions.DeepCopy()
 	if err != nil {
 		reqLogger.Error(err, msg)
+		if scalers.IsPermanentError(err) {
+			msg = fmt.Sprintf("%s: %s", msg, err)
+		}
 		conditions.SetReadyCondition(metav1.ConditionFalse, "ScaledJobCheckFailed", msg)
 		conditions.SetActiveCondition(metav1.ConditionUnknown, "UnknownState", "ScaledJob check failed")
 		r.Recorder.Event(scaledJob, corev1.EventTypeWarning, eventreason.ScaledJobCheckFailed, msg)
@@ -132,11 +163,22 @@ func (r *ScaledJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	if err := kedacontrollerutil.SetStatusConditions(ctx, r.Client, reqLogger, scaledJob, &conditions); err != nil {
 		return ctrl.Result{}, err
 	}
+
+	if scalers.IsPermanentError(err) {
+		// the trigger configuration is invalid, retrying won't help until the spec changes
+		return ctrl.Result{}, nil
+	}
 	return ctrl.Result{}, err
 }
 
 // reconcileScaledJob implements reconciler logic for K8s Jobs based ScaledJob
 func (r *ScaledJobReconciler) reconcileScaledJob(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob) (string, error) {
+	// Check the Job template can be resolved
+	if _, err := resolver.ResolveJobSpec(ctx, r.Client, scaledJob); err != nil {
+		logger.Error(err, "Error resolving the Job template")
+		return "Failed to resolve the Job template of the ScaledJob", err
+	}
+
 	msg, err := r.deletePreviousVersionScaleJobs(ctx, logger, scaledJob)
 	if err != nil {
 		return msg, err
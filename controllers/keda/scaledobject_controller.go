@@ -48,7 +48,9 @@ import (
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	kedacontrollerutil "github.com/kedacore/keda/v2/controllers/keda/util"
 	"github.com/kedacore/keda/v2/pkg/eventreason"
+	"github.com/kedacore/keda/v2/pkg/scalers"
 	"github.com/kedacore/keda/v2/pkg/scaling"
+	"github.com/kedacore/keda/v2/pkg/scaling/resolver"
 	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
@@ -61,6 +63,10 @@ import (
 // +kubebuilder:rbac:groups="*",resources="*",verbs=get
 // +kubebuilder:rbac:groups="apps",resources=deployments;statefulsets,verbs=list;watch
 // +kubebuilder:rbac:groups="coordination.k8s.io",resources=leases,verbs="*"
+// +kubebuilder:rbac:groups="prow.k8s.io",resources=prowjobs,verbs=list;watch
+// +kubebuilder:rbac:groups="tekton.dev",resources=pipelineruns;taskruns,verbs=list;watch
+// +kubebuilder:rbac:groups="authentication.k8s.io",resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups="authorization.k8s.io",resources=subjectaccessreviews,verbs=create
 
 // ScaledObjectReconciler reconciles a ScaledObject object
 type ScaledObjectReconciler struct {
@@ -68,6 +74,13 @@ type ScaledObjectReconciler struct {
 	Scheme            *runtime.Scheme
 	GlobalHTTPTimeout time.Duration
 	Recorder          record.EventRecorder
+	// PersistState exports the paused state and the fallback counters of the ScaledObjects to a ConfigMap
+	// of their namespace and restores them when the ScaledObjects are recreated, see syncScaledObjectState
+	PersistState bool
+	// ScalingDisabled disables the scaling cluster-wide whatever the keda-scaling-switch ConfigMap says
+	ScalingDisabled bool
+	// ScaleLoopStallFactor restarts the scale loops which didn't complete within this many pollingIntervals, 0 disables it
+	ScaleLoopStallFactor int
 
 	scaleClient              scale.ScalesGetter
 	restMapper               meta.RESTMapper
@@ -76,6 +89,10 @@ type ScaledObjectReconciler struct {
 	kubeVersion              kedautil.K8sVersion
 }
 
+// scaleHandlerShutdownTimeout bounds the wait for the in-flight scale operations when the operator stops, it's below
+// the default graceful shutdown timeout of the manager
+const scaleHandlerShutdownTimeout = 25 * time.Second
+
 // A cache mapping "resource.group" to true or false if we know if this resource is scalable.
 var isScalableCache *sync.Map
 
@@ -86,6 +103,11 @@ func init() {
 	isScalableCache.Store("statefulsets.apps", true)
 }
 
+// ScaleHandler returns the ScaleHandler polling the scalers of the ScaledObjects, it's nil until SetupWithManager is called
+func (r *ScaledObjectReconciler) ScaleHandler() scaling.ScaleHandler {
+	return r.scaleHandler
+}
+
 // SetupWithManager initializes the ScaledObjectReconciler instance and starts a new controller managed by the passed Manager instance.
 func (r *ScaledObjectReconciler) SetupWithManager(mgr ctrl.Manager, options controller.Options) error {
 	setupLog := log.Log.WithName("setup")
@@ -115,9 +137,49 @@ func (r *ScaledObjectReconciler) SetupWithManager(mgr ctrl.Manager, options cont
 	r.restMapper = mgr.GetRESTMapper()
 	r.scaledObjectsGenerations = &sync.Map{}
 	r.scaleHandler = scaling.NewScaleHandler(mgr.GetClient(), r.scaleClient, mgr.GetScheme(), r.GlobalHTTPTimeout, r.Recorder)
+	if err := mgr.Add(scaling.NewShutdownRunnable(r.scaleHandler, scaleHandlerShutdownTimeout)); err != nil {
+		return err
+	}
+	if r.ScaleLoopStallFactor > 0 {
+		if err := mgr.Add(scaling.NewScaleLoopWatchdogRunnable(r.scaleHandler, r.ScaleLoopStallFactor)); err != nil {
+			return err
+		}
+	}
+
+	if err := setupScaledObjectIndexes(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		setupLog.Error(err, "Not able to set up the ScaledObject indexes")
+		return err
+	}
+
+	// the scalers of the ScaledObjects affected by a change of their dependencies are rebuilt on the next poll
+	mapper := &scaledObjectMapper{
+		client: mgr.GetClient(),
+		logger: mgr.GetLogger().WithName("scaledobject-mapper"),
+		refresh: func(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject) {
+			if err := r.scaleHandler.ClearScalersCache(ctx, scaledObject); err != nil {
+				setupLog.Error(err, "error clearing scalers cache", "scaledObject.Namespace", scaledObject.Namespace, "scaledObject.Name", scaledObject.Name)
+			}
+		},
+	}
+
+	// the switch ConfigMap is read in the namespace of the operator, the flag disables the scaling even without it
+	switchNamespace, err := resolver.GetClusterObjectNamespace()
+	if err != nil {
+		setupLog.Error(err, "Not able to get the namespace of the scaling switch ConfigMap")
+	}
+	scalingSwitch := &scalingSwitch{
+		client:    mgr.GetClient(),
+		namespace: switchNamespace,
+		forced:    r.ScalingDisabled,
+		logger:    mgr.GetLogger().WithName("scaling-switch"),
+	}
+	// the cache isn't started yet, the ConfigMap is read from the API server
+	if err := scalingSwitch.seed(context.Background(), mgr.GetAPIReader()); err != nil {
+		return err
+	}
 
 	// Start controller
-	return ctrl.NewControllerManagedBy(mgr).
+	return watchScalingSwitch(watchScaledObjectDependencies(ctrl.NewControllerManagedBy(mgr), mapper), scalingSwitch).
 		WithOptions(options).
 		// predicate.GenerationChangedPredicate{} ignore updates to ScaledObject Status
 		// (in this case metadata.Generation does not change)
@@ -187,6 +249,9 @@ func (r *ScaledObjectReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	conditions := scaledObject.Status.Conditions.DeepCopy()
 	if err != nil {
 		reqLogger.Error(err, msg)
+		if scalers.IsPermanentError(err) {
+			msg = fmt.Sprintf("%s: %s", msg, err)
+		}
 		conditions.SetReadyCondition(metav1.ConditionFalse, "ScaledObjectCheckFailed", msg)
 		conditions.SetActiveCondition(metav1.ConditionUnknown, "UnkownState", "ScaledObject check failed")
 		r.Recorder.Event(scaledObject, corev1.EventTypeWarning, eventreason.ScaledObjectCheckFailed, msg)
@@ -203,6 +268,11 @@ func (r *ScaledObjectReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, err
 	}
 
+	if scalers.IsPermanentError(err) {
+		// the trigger configuration is invalid, retrying won't help until the spec changes
+		return ctrl.Result{}, nil
+	}
+
 	return ctrl.Result{}, err
 }
 
@@ -220,6 +290,11 @@ func (r *ScaledObjectReconciler) reconcileScaledObject(ctx context.Context, logg
 		return "Failed to update ScaledObject with scaledObjectName label", err
 	}
 
+	// Export the state of the ScaledObject or restore it if it was recreated, before the paused status is updated
+	if err := r.syncScaledObjectState(ctx, logger, scaledObject); err != nil {
+		return "Failed to sync the persisted ScaledObject state", err
+	}
+
 	// Check if resource targeted for scaling exists and exposes /scale subresource
 	gvkr, err := r.checkTargetResourceIsScalable(ctx, logger, scaledObject)
 	if err != nil {
@@ -231,6 +306,11 @@ func (r *ScaledObjectReconciler) reconcileScaledObject(ctx context.Context, logg
 		return "ScaledObject doesn't have correct Idle/Min/Max Replica Counts specification", err
 	}
 
+	err = r.checkScaleStrategyIsValid(scaledObject)
+	if err != nil {
+		return "ScaledObject doesn't have correct scaleStrategy specification", err
+	}
+
 	// Create a new HPA or update existing one according to ScaledObject
 	newHPACreated, err := r.ensureHPAForScaledObjectExists(ctx, logger, scaledObject, &gvkr)
 	if err != nil {
@@ -362,6 +442,18 @@ func (r *ScaledObjectReconciler) checkReplicaCountBoundsAreValid(scaledObject *k
 	return nil
 }
 
+// checkScaleStrategyIsValid checks that the ScaleStrategy defined in ScaledObject is a known one
+func (r *ScaledObjectReconciler) checkScaleStrategyIsValid(scaledObject *kedav1alpha1.ScaledObject) error {
+	switch scaledObject.GetScaleStrategy() {
+	case kedav1alpha1.ScaleStrategyAuto, kedav1alpha1.ScaleStrategyScale, kedav1alpha1.ScaleStrategyReplicas:
+	default:
+		return fmt.Errorf("ScaleStrategy=%s must be one of %s, %s or %s", scaledObject.GetScaleStrategy(),
+			kedav1alpha1.ScaleStrategyAuto, kedav1alpha1.ScaleStrategyScale, kedav1alpha1.ScaleStrategyReplicas)
+	}
+
+	return nil
+}
+
 // ensureHPAForScaledObjectExists ensures that in cluster exist up-to-date HPA for specified ScaledObject, returns true if a new HPA was created
 func (r *ScaledObjectReconciler) ensureHPAForScaledObjectExists(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, gvkr *kedav1alpha1.GroupVersionKindResource) (bool, error) {
 	var hpaName string
@@ -98,7 +98,7 @@ var _ = Describe("ScaledObjectController", func() {
 						AuthParams:      nil,
 					}
 
-					s, err := scalers.NewPrometheusScaler(config)
+					s, err := scalers.NewPrometheusScaler(context.Background(), config)
 					if err != nil {
 						Fail(err.Error())
 					}
@@ -106,7 +106,7 @@ var _ = Describe("ScaledObjectController", func() {
 					testScalers = append(testScalers, cache.ScalerBuilder{
 						Scaler: s,
 						Factory: func() (scalers.Scaler, error) {
-							return scalers.NewPrometheusScaler(config)
+							return scalers.NewPrometheusScaler(context.Background(), config)
 						},
 					})
 					for _, metricSpec := range s.GetMetricSpecForScaling(context.Background()) {
@@ -148,7 +148,7 @@ var _ = Describe("ScaledObjectController", func() {
 					AuthParams:      nil,
 				}
 
-				s, err := scalers.NewPrometheusScaler(config)
+				s, err := scalers.NewPrometheusScaler(context.Background(), config)
 				if err != nil {
 					Fail(err.Error())
 				}
@@ -198,7 +198,7 @@ var _ = Describe("ScaledObjectController", func() {
 						AuthParams:      nil,
 					}
 
-					s, err := scalers.NewPrometheusScaler(config)
+					s, err := scalers.NewPrometheusScaler(context.Background(), config)
 					if err != nil {
 						Fail(err.Error())
 					}
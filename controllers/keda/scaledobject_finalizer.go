@@ -18,15 +18,21 @@ package keda
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	"github.com/kedacore/keda/v2/controllers/keda/util"
 	"github.com/kedacore/keda/v2/pkg/eventreason"
+	"github.com/kedacore/keda/v2/pkg/scaling/executor"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
 const (
@@ -50,6 +56,9 @@ func (r *ScaledObjectReconciler) finalizeScaledObject(ctx context.Context, logge
 			// Let's skip in this case.
 			if scaledObject.Status.ScaleTargetGVKR == nil {
 				logger.V(1).Info("Failed to restore scaleTarget's replica count back to the original, the scaling haven't been probably initialized yet.")
+			} else if scaledObject.GetScaleStrategy() == kedav1alpha1.ScaleStrategyReplicas {
+				// The scaleTarget is scaled through spec.replicas, restore it the same way.
+				r.restoreReplicasOnScaleTarget(ctx, logger, scaledObject)
 			} else {
 				// We have enough information about the scaleTarget, let's proceed.
 				scale, err := r.scaleClient.Scales(scaledObject.Namespace).Get(ctx, scaledObject.Status.ScaleTargetGVKR.GroupResource(), scaledObject.Spec.ScaleTargetRef.Name, metav1.GetOptions{})
@@ -70,6 +79,12 @@ func (r *ScaledObjectReconciler) finalizeScaledObject(ctx context.Context, logge
 			}
 		}
 
+		// the state is kept for a while, a ScaledObject deleted and created again gets it back
+		if err := r.markScaledObjectStateDeleted(ctx, scaledObject); err != nil {
+			logger.Error(err, "Failed to mark the persisted ScaledObject state as deleted")
+			return err
+		}
+
 		// Remove scaledObjectFinalizer. Once all finalizers have been
 		// removed, the object will be deleted.
 		scaledObject.SetFinalizers(util.Remove(scaledObject.GetFinalizers(), scaledObjectFinalizer))
@@ -84,6 +99,27 @@ func (r *ScaledObjectReconciler) finalizeScaledObject(ctx context.Context, logge
 	return nil
 }
 
+// restoreReplicasOnScaleTarget sets spec.replicas of the scaleTarget back to the original replica count
+func (r *ScaledObjectReconciler) restoreReplicasOnScaleTarget(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) {
+	unstruct := &unstructured.Unstructured{}
+	unstruct.SetGroupVersionKind(scaledObject.Status.ScaleTargetGVKR.GroupVersionKind())
+	unstruct.SetNamespace(scaledObject.Namespace)
+	unstruct.SetName(scaledObject.Spec.ScaleTargetRef.Name)
+	patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, *scaledObject.Status.OriginalReplicaCount)))
+	err := kedautil.RetryWrite(ctx, func() error { return r.Client.Patch(ctx, unstruct, patch) })
+	switch {
+	case err == nil:
+		logger.Info("Successfully restored scaleTarget's replica count back to the original", "replicaCount", *scaledObject.Status.OriginalReplicaCount)
+	case errors.IsNotFound(err):
+		logger.V(1).Info("Failed to restore scaleTarget's replica count, because it was probably deleted", "error", err)
+	case errors.IsForbidden(err):
+		logger.Error(err, "Failed to restore scaleTarget's replica count back to the original, the replicas scaleStrategy requires a ClusterRole granting patch on the scaleTarget",
+			"label", executor.ScaleTargetPatchRoleLabel, "finalizer", scaledObjectFinalizer)
+	default:
+		logger.Error(err, "Failed to restore scaleTarget's replica count back to the original", "finalizer", scaledObjectFinalizer)
+	}
+}
+
 // ensureFinalizer check there is finalizer present on the ScaledObject, if not it adds one
 func (r *ScaledObjectReconciler) ensureFinalizer(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) error {
 	if !util.Contains(scaledObject.GetFinalizers(), scaledObjectFinalizer) {
@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keda
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+func TestRestoreReplicasOnScaleTarget(t *testing.T) {
+	worker := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Worker",
+		"metadata":   map[string]interface{}{"name": "worker", "namespace": "default"},
+		"spec":       map[string]interface{}{"replicas": int64(0)},
+	}}
+	r := newScaledObjectStateTestReconciler(t, worker)
+
+	originalReplicaCount := int32(4)
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Name: "so", Namespace: "default"},
+		Spec: kedav1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: "worker"},
+			Advanced: &kedav1alpha1.AdvancedConfig{
+				RestoreToOriginalReplicaCount: true,
+				ScaleStrategy:                 kedav1alpha1.ScaleStrategyReplicas,
+			},
+		},
+		Status: kedav1alpha1.ScaledObjectStatus{
+			ScaleTargetGVKR:      &kedav1alpha1.GroupVersionKindResource{Group: "example.com", Version: "v1", Kind: "Worker", Resource: "workers"},
+			OriginalReplicaCount: &originalReplicaCount,
+		},
+	}
+
+	r.restoreReplicasOnScaleTarget(context.Background(), logr.Discard(), scaledObject)
+
+	restored := &unstructured.Unstructured{}
+	restored.SetGroupVersionKind(worker.GroupVersionKind())
+	assert.NoError(t, r.Client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "worker"}, restored))
+	replicas, _, err := unstructured.NestedInt64(restored.Object, "spec", "replicas")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), replicas)
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	kedacontrollerutil "github.com/kedacore/keda/v2/controllers/keda/util"
+	"github.com/kedacore/keda/v2/pkg/eventreason"
+	"github.com/kedacore/keda/v2/pkg/scaling/executor"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+// scaledObjectStateConfigMapName is the ConfigMap of each namespace where the state of its ScaledObjects
+// that isn't part of their manifests is exported, one key per ScaledObject
+const scaledObjectStateConfigMapName = "keda-scaledobject-state"
+
+// scaledObjectStateDeletedTTL is how long the exported state of a deleted ScaledObject is kept, a ScaledObject
+// deleted and created again within it, e.g. by a GitOps tool replacing it, gets its state back
+const scaledObjectStateDeletedTTL = 24 * time.Hour
+
+// scaledObjectState is the exported state of a ScaledObject
+type scaledObjectState struct {
+	PausedReplicaCount *int32                               `json:"pausedReplicaCount,omitempty"`
+	Health             map[string]kedav1alpha1.HealthStatus `json:"health,omitempty"`
+	// DeletedAt is the deletion time of the ScaledObject, the state is dropped scaledObjectStateDeletedTTL later
+	DeletedAt *metav1.Time `json:"deletedAt,omitempty"`
+}
+
+// expired returns whether the state belongs to a ScaledObject deleted more than scaledObjectStateDeletedTTL ago
+func (s *scaledObjectState) expired(now time.Time) bool {
+	return s != nil && s.DeletedAt != nil && now.Sub(s.DeletedAt.Time) > scaledObjectStateDeletedTTL
+}
+
+// syncScaledObjectState exports the paused replica count and the fallback counters of the ScaledObject to the
+// state ConfigMap of its namespace, so they can be backed up along with it, and restores them on a ScaledObject
+// recreated by a re-apply or a restore of the cluster which lost its annotations and its status.
+//
+// The state is merged this way:
+//   - the paused-replicas annotation, when present, always wins and is exported
+//   - a ScaledObject seen paused by KEDA that lost the annotation was resumed on purpose, the exported state is cleared
+//   - a ScaledObject never reconciled before (no status) without the annotation gets back the exported paused replica
+//     count as annotation, and the fallback counters into its status
+//   - deleting the ScaledObject keeps its exported state for scaledObjectStateDeletedTTL, so a ScaledObject deleted
+//     and created again gets it back, the expired state is dropped
+func (r *ScaledObjectReconciler) syncScaledObjectState(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject) error {
+	if !r.PersistState {
+		return nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, client.ObjectKey{Namespace: scaledObject.Namespace, Name: scaledObjectStateConfigMapName}, configMap)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	stored, err := getStoredScaledObjectState(configMap, scaledObject.Name)
+	if err != nil {
+		// a corrupted entry is overwritten by the current state
+		logger.Error(err, "Failed to read the persisted ScaledObject state")
+	}
+	expired := stored.expired(time.Now())
+	if expired {
+		stored = nil
+	}
+
+	_, paused := scaledObject.GetAnnotations()[kedacontrollerutil.PausedReplicasAnnotation]
+	neverReconciled := scaledObject.Status.ScaleTargetKind == "" && scaledObject.Status.OriginalReplicaCount == nil
+	if !paused && neverReconciled && stored != nil && stored.PausedReplicaCount != nil {
+		return r.restoreScaledObjectState(ctx, logger, scaledObject, stored)
+	}
+
+	current, err := getCurrentScaledObjectState(scaledObject)
+	if err != nil {
+		return err
+	}
+	if !expired && reflect.DeepEqual(stored, current) {
+		return nil
+	}
+	return r.storeScaledObjectState(ctx, configMap, exists, scaledObject, current)
+}
+
+// restoreScaledObjectState sets the paused-replicas annotation and the fallback counters from the exported state
+func (r *ScaledObjectReconciler) restoreScaledObjectState(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, state *scaledObjectState) error {
+	patch := client.MergeFrom(scaledObject.DeepCopy())
+	annotations := scaledObject.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[kedacontrollerutil.PausedReplicasAnnotation] = strconv.Itoa(int(*state.PausedReplicaCount))
+	scaledObject.SetAnnotations(annotations)
+	if err := kedautil.RetryWrite(ctx, func() error { return r.Client.Patch(ctx, scaledObject, patch) }); err != nil {
+		return err
+	}
+
+	if len(state.Health) > 0 && len(scaledObject.Status.Health) == 0 {
+		status := scaledObject.Status.DeepCopy()
+		status.Health = state.Health
+		if err := kedacontrollerutil.UpdateScaledObjectStatus(ctx, r.Client, logger, scaledObject, status); err != nil {
+			return err
+		}
+	}
+
+	msg := fmt.Sprintf("Restored the persisted paused replica count %d", *state.PausedReplicaCount)
+	logger.Info(msg)
+	r.Recorder.Event(scaledObject, corev1.EventTypeNormal, eventreason.ScaledObjectStateRestored, msg)
+	return nil
+}
+
+// storeScaledObjectState writes the state of the ScaledObject to the ConfigMap, a nil state removes its key.
+// A conflict is returned to requeue the ScaledObject, the next reconcile merges with the fresh ConfigMap
+func (r *ScaledObjectReconciler) storeScaledObjectState(ctx context.Context, configMap *corev1.ConfigMap, exists bool, scaledObject *kedav1alpha1.ScaledObject, state *scaledObjectState) error {
+	if state == nil {
+		if !exists {
+			return nil
+		}
+		delete(configMap.Data, scaledObject.Name)
+		return r.Client.Update(ctx, configMap)
+	}
+
+	value, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[scaledObject.Name] = string(value)
+
+	if !exists {
+		configMap.ObjectMeta = metav1.ObjectMeta{
+			Namespace: scaledObject.Namespace,
+			Name:      scaledObjectStateConfigMapName,
+			Labels:    map[string]string{"app.kubernetes.io/managed-by": "keda-operator"},
+		}
+		return r.Client.Create(ctx, configMap)
+	}
+	return r.Client.Update(ctx, configMap)
+}
+
+// markScaledObjectStateDeleted sets the deletion time of the exported state of the ScaledObject, the state is
+// restored if the ScaledObject is created again within scaledObjectStateDeletedTTL. The expired states of the other
+// deleted ScaledObjects of the namespace are dropped at the same time.
+func (r *ScaledObjectReconciler) markScaledObjectStateDeleted(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject) error {
+	if !r.PersistState {
+		return nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: scaledObject.Namespace, Name: scaledObjectStateConfigMapName}, configMap); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	now := time.Now()
+	changed := false
+	for name := range configMap.Data {
+		if name == scaledObject.Name {
+			continue
+		}
+		if state, err := getStoredScaledObjectState(configMap, name); err == nil && state.expired(now) {
+			delete(configMap.Data, name)
+			changed = true
+		}
+	}
+
+	state, err := getStoredScaledObjectState(configMap, scaledObject.Name)
+	switch {
+	case err != nil:
+		// a corrupted entry can't be restored
+		delete(configMap.Data, scaledObject.Name)
+		changed = true
+	case state != nil && state.DeletedAt == nil:
+		state.DeletedAt = &metav1.Time{Time: now}
+		value, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		configMap.Data[scaledObject.Name] = string(value)
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return r.Client.Update(ctx, configMap)
+}
+
+// getStoredScaledObjectState returns the exported state of the ScaledObject, nil if there is none
+func getStoredScaledObjectState(configMap *corev1.ConfigMap, name string) (*scaledObjectState, error) {
+	value, ok := configMap.Data[name]
+	if !ok {
+		return nil, nil
+	}
+	state := &scaledObjectState{}
+	if err := json.Unmarshal([]byte(value), state); err != nil {
+		return nil, fmt.Errorf("invalid state of ScaledObject %s in ConfigMap %s: %s", name, scaledObjectStateConfigMapName, err)
+	}
+	return state, nil
+}
+
+// getCurrentScaledObjectState returns the state of the ScaledObject to export, nil if it has nothing worth keeping:
+// it isn't paused and none of its triggers is failing
+func getCurrentScaledObjectState(scaledObject *kedav1alpha1.ScaledObject) (*scaledObjectState, error) {
+	pausedCount, err := executor.GetPausedReplicaCount(scaledObject)
+	if err != nil {
+		return nil, err
+	}
+
+	var health map[string]kedav1alpha1.HealthStatus
+	for metricName, status := range scaledObject.Status.Health {
+		if status.NumberOfFailures == nil || *status.NumberOfFailures == 0 {
+			continue
+		}
+		if health == nil {
+			health = map[string]kedav1alpha1.HealthStatus{}
+		}
+		health[metricName] = status
+	}
+
+	if pausedCount == nil && health == nil {
+		return nil, nil
+	}
+	return &scaledObjectState{PausedReplicaCount: pausedCount, Health: health}, nil
+}
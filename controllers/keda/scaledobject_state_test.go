@@ -0,0 +1,181 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keda
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	kedacontrollerutil "github.com/kedacore/keda/v2/controllers/keda/util"
+)
+
+func newScaledObjectStateTestReconciler(t *testing.T, objects ...client.Object) *ScaledObjectReconciler {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, kedav1alpha1.AddToScheme(scheme))
+	return &ScaledObjectReconciler{
+		Client:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Recorder:     record.NewFakeRecorder(10),
+		PersistState: true,
+	}
+}
+
+func getScaledObjectStateConfigMap(t *testing.T, r *ScaledObjectReconciler) *corev1.ConfigMap {
+	configMap := &corev1.ConfigMap{}
+	err := r.Client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: scaledObjectStateConfigMapName}, configMap)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	assert.NoError(t, err)
+	return configMap
+}
+
+func TestSyncScaledObjectStateExportsPausedState(t *testing.T) {
+	failures := int32(3)
+	so := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: map[string]string{kedacontrollerutil.PausedReplicasAnnotation: "2"}},
+		Status: kedav1alpha1.ScaledObjectStatus{
+			ScaleTargetKind: "apps/v1.Deployment",
+			Health: map[string]kedav1alpha1.HealthStatus{
+				"s0-queue":   {NumberOfFailures: &failures, Status: kedav1alpha1.HealthStatusFailing},
+				"s1-healthy": {Status: kedav1alpha1.HealthStatusHappy},
+			},
+		},
+	}
+	r := newScaledObjectStateTestReconciler(t, so)
+
+	assert.NoError(t, r.syncScaledObjectState(context.Background(), logr.Discard(), so))
+	configMap := getScaledObjectStateConfigMap(t, r)
+	assert.NotNil(t, configMap)
+	assert.JSONEq(t, `{"pausedReplicaCount":2,"health":{"s0-queue":{"numberOfFailures":3,"status":"Failing"}}}`, configMap.Data["app"])
+
+	// resuming the ScaledObject clears its state
+	so.Annotations = nil
+	so.Status.Health = nil
+	assert.NoError(t, r.syncScaledObjectState(context.Background(), logr.Discard(), so))
+	configMap = getScaledObjectStateConfigMap(t, r)
+	assert.NotContains(t, configMap.Data, "app")
+}
+
+func TestSyncScaledObjectStateRestoresPausedState(t *testing.T) {
+	so := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: scaledObjectStateConfigMapName, Namespace: "default"},
+		Data:       map[string]string{"app": `{"pausedReplicaCount":0,"health":{"s0-queue":{"numberOfFailures":1,"status":"Failing"}}}`},
+	}
+	r := newScaledObjectStateTestReconciler(t, so, configMap)
+
+	assert.NoError(t, r.syncScaledObjectState(context.Background(), logr.Discard(), so))
+
+	restored := &kedav1alpha1.ScaledObject{}
+	assert.NoError(t, r.Client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "app"}, restored))
+	assert.Equal(t, "0", restored.Annotations[kedacontrollerutil.PausedReplicasAnnotation])
+	assert.Equal(t, int32(1), *restored.Status.Health["s0-queue"].NumberOfFailures)
+}
+
+func TestSyncScaledObjectStateAnnotationWins(t *testing.T) {
+	so := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: map[string]string{kedacontrollerutil.PausedReplicasAnnotation: "5"}}}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: scaledObjectStateConfigMapName, Namespace: "default"},
+		Data:       map[string]string{"app": `{"pausedReplicaCount":0}`, "other": `{"pausedReplicaCount":1}`},
+	}
+	r := newScaledObjectStateTestReconciler(t, so, configMap)
+
+	assert.NoError(t, r.syncScaledObjectState(context.Background(), logr.Discard(), so))
+	configMap = getScaledObjectStateConfigMap(t, r)
+	assert.JSONEq(t, `{"pausedReplicaCount":5}`, configMap.Data["app"])
+
+}
+
+func TestSyncScaledObjectStateRestoresDeletedAndRecreated(t *testing.T) {
+	so := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: map[string]string{kedacontrollerutil.PausedReplicasAnnotation: "0"}},
+		Status:     kedav1alpha1.ScaledObjectStatus{ScaleTargetKind: "apps/v1.Deployment"},
+	}
+	expired := metav1.NewTime(time.Now().Add(-scaledObjectStateDeletedTTL - time.Minute))
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: scaledObjectStateConfigMapName, Namespace: "default"},
+		Data:       map[string]string{"gone": fmt.Sprintf(`{"pausedReplicaCount":1,"deletedAt":%q}`, expired.UTC().Format(time.RFC3339))},
+	}
+	r := newScaledObjectStateTestReconciler(t, configMap)
+	assert.NoError(t, r.Client.Create(context.Background(), so))
+	assert.NoError(t, r.syncScaledObjectState(context.Background(), logr.Discard(), so))
+
+	// the deletion keeps the state and drops the expired one
+	assert.NoError(t, r.markScaledObjectStateDeleted(context.Background(), so))
+	assert.NoError(t, r.Client.Delete(context.Background(), so))
+	configMap = getScaledObjectStateConfigMap(t, r)
+	assert.NotContains(t, configMap.Data, "gone")
+	stored, err := getStoredScaledObjectState(configMap, "app")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), *stored.PausedReplicaCount)
+	assert.NotNil(t, stored.DeletedAt)
+
+	// the recreated ScaledObject, without annotation nor status, gets the paused state back
+	recreated := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	assert.NoError(t, r.Client.Create(context.Background(), recreated))
+	assert.NoError(t, r.syncScaledObjectState(context.Background(), logr.Discard(), recreated))
+	restored := &kedav1alpha1.ScaledObject{}
+	assert.NoError(t, r.Client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "app"}, restored))
+	assert.Equal(t, "0", restored.Annotations[kedacontrollerutil.PausedReplicasAnnotation])
+
+	// the next sync exports the state of the recreated ScaledObject, which isn't deleted anymore
+	assert.NoError(t, r.syncScaledObjectState(context.Background(), logr.Discard(), restored))
+	configMap = getScaledObjectStateConfigMap(t, r)
+	assert.JSONEq(t, `{"pausedReplicaCount":0}`, configMap.Data["app"])
+}
+
+func TestSyncScaledObjectStateIgnoresExpiredState(t *testing.T) {
+	so := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	deletedAt := metav1.NewTime(time.Now().Add(-scaledObjectStateDeletedTTL - time.Minute))
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: scaledObjectStateConfigMapName, Namespace: "default"},
+		Data:       map[string]string{"app": fmt.Sprintf(`{"pausedReplicaCount":0,"deletedAt":%q}`, deletedAt.UTC().Format(time.RFC3339))},
+	}
+	r := newScaledObjectStateTestReconciler(t, so, configMap)
+
+	assert.NoError(t, r.syncScaledObjectState(context.Background(), logr.Discard(), so))
+
+	current := &kedav1alpha1.ScaledObject{}
+	assert.NoError(t, r.Client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "app"}, current))
+	assert.NotContains(t, current.Annotations, kedacontrollerutil.PausedReplicasAnnotation)
+	configMap = getScaledObjectStateConfigMap(t, r)
+	assert.NotContains(t, configMap.Data, "app")
+}
+
+func TestSyncScaledObjectStateDisabled(t *testing.T) {
+	so := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: map[string]string{kedacontrollerutil.PausedReplicasAnnotation: "0"}}}
+	r := newScaledObjectStateTestReconciler(t, so)
+	r.PersistState = false
+
+	assert.NoError(t, r.syncScaledObjectState(context.Background(), logr.Discard(), so))
+	assert.Nil(t, getScaledObjectStateConfigMap(t, r))
+}
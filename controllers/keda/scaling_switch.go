@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keda
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/kedacore/keda/v2/pkg/scaling/executor"
+)
+
+const (
+	// scalingSwitchConfigMapName is the ConfigMap of the namespace of the operator disabling the scaling cluster-wide
+	// when its scalingDisabled key is true
+	scalingSwitchConfigMapName = "keda-scaling-switch"
+	scalingDisabledKey         = "scalingDisabled"
+)
+
+// scalingSwitch is the cluster-wide emergency stop, it's disabled by the --scaling-disabled flag of the operator or
+// the keda-scaling-switch ConfigMap, all the ScaledObjects are reconciled when it flips to freeze or resume their HPAs
+type scalingSwitch struct {
+	client    client.Client
+	namespace string
+	// forced is set by the flag, the ConfigMap can't resume the scaling then
+	forced bool
+	logger logr.Logger
+}
+
+// seed sets the switch from the flag and the ConfigMap read with reader, it's called before the controllers and
+// the scale loops start so a restart of the operator during an incident doesn't scale until the ConfigMap is watched
+func (s *scalingSwitch) seed(ctx context.Context, reader client.Reader) error {
+	if s.forced || s.namespace == "" {
+		executor.SetScalingDisabled(s.forced)
+		return nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := reader.Get(ctx, client.ObjectKey{Namespace: s.namespace, Name: scalingSwitchConfigMapName}, configMap)
+	if errors.IsNotFound(err) {
+		executor.SetScalingDisabled(false)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading the scaling switch ConfigMap %s/%s: %s", s.namespace, scalingSwitchConfigMapName, err)
+	}
+
+	disabled, err := parseScalingDisabled(configMap)
+	if err != nil {
+		// a typo mustn't resume the scaling during an incident
+		s.logger.Error(err, "error reading the scaling switch, disabling the scaling until the ConfigMap is fixed")
+		disabled = true
+	}
+	executor.SetScalingDisabled(disabled)
+	return nil
+}
+
+// isDisabled returns true if the flag or the ConfigMap disable the scaling
+func (s *scalingSwitch) isDisabled(ctx context.Context) (bool, error) {
+	if s.forced {
+		return true, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := s.client.Get(ctx, client.ObjectKey{Namespace: s.namespace, Name: scalingSwitchConfigMapName}, configMap)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return parseScalingDisabled(configMap)
+}
+
+// parseScalingDisabled returns the scalingDisabled key of the switch ConfigMap, false if it's not set
+func parseScalingDisabled(configMap *corev1.ConfigMap) (bool, error) {
+	val, ok := configMap.Data[scalingDisabledKey]
+	if !ok || val == "" {
+		return false, nil
+	}
+	disabled, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s in ConfigMap %s/%s: %s", scalingDisabledKey, configMap.Namespace, configMap.Name, err)
+	}
+	return disabled, nil
+}
+
+// forConfigMap updates the switch on changes of the ConfigMap and returns all the ScaledObjects if it flipped
+func (s *scalingSwitch) forConfigMap(obj client.Object) []reconcile.Request {
+	ctx := context.Background()
+	disabled, err := s.isDisabled(ctx)
+	if err != nil {
+		// the switch keeps its state, a typo mustn't resume the scaling during an incident
+		s.logger.Error(err, "error reading the scaling switch, keeping its state", "scalingDisabled", executor.IsScalingDisabled())
+		return nil
+	}
+	if disabled == executor.IsScalingDisabled() {
+		return nil
+	}
+
+	executor.SetScalingDisabled(disabled)
+	if disabled {
+		s.logger.Info("Scaling is disabled cluster-wide")
+	} else {
+		s.logger.Info("Scaling is resumed cluster-wide")
+	}
+
+	scaledObjects := &kedav1alpha1.ScaledObjectList{}
+	if err := s.client.List(ctx, scaledObjects); err != nil {
+		s.logger.Error(err, "error listing the ScaledObjects to update their HPA")
+		return nil
+	}
+	return scaledObjectRequests(scaledObjects.Items)
+}
+
+// isSwitchConfigMap filters the events of the other ConfigMaps
+func (s *scalingSwitch) isSwitchConfigMap(obj client.Object) bool {
+	return obj.GetNamespace() == s.namespace && obj.GetName() == scalingSwitchConfigMapName
+}
+
+// watchScalingSwitch makes the controller reconcile all the ScaledObjects when the scaling switch flips
+func watchScalingSwitch(blder *builder.Builder, s *scalingSwitch) *builder.Builder {
+	if s.namespace == "" {
+		return blder
+	}
+	return blder.Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(s.forConfigMap),
+		builder.WithPredicates(predicate.NewPredicateFuncs(s.isSwitchConfigMap)))
+}
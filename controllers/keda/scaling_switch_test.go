@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keda
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/kedacore/keda/v2/pkg/scaling/executor"
+)
+
+func TestScalingSwitchFlipsOnConfigMapChanges(t *testing.T) {
+	defer executor.SetScalingDisabled(false)
+
+	switchConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: scalingSwitchConfigMapName, Namespace: "keda"},
+		Data:       map[string]string{scalingDisabledKey: "true"},
+	}
+	r := newScaledObjectStateTestReconciler(t,
+		switchConfigMap,
+		&kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}},
+		&kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "team-a"}},
+	)
+	s := &scalingSwitch{client: r.Client, namespace: "keda", logger: logr.Discard()}
+	allScaledObjects := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "default", Name: "app"}},
+		{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "worker"}},
+	}
+
+	// disabling the scaling reconciles all the ScaledObjects, once
+	assert.ElementsMatch(t, allScaledObjects, s.forConfigMap(switchConfigMap))
+	assert.True(t, executor.IsScalingDisabled())
+	assert.Empty(t, s.forConfigMap(switchConfigMap))
+
+	// an invalid value keeps the scaling disabled
+	switchConfigMap.Data[scalingDisabledKey] = "yes please"
+	assert.NoError(t, r.Client.Update(context.Background(), switchConfigMap))
+	assert.Empty(t, s.forConfigMap(switchConfigMap))
+	assert.True(t, executor.IsScalingDisabled())
+
+	switchConfigMap.Data[scalingDisabledKey] = "false"
+	assert.NoError(t, r.Client.Update(context.Background(), switchConfigMap))
+	assert.ElementsMatch(t, allScaledObjects, s.forConfigMap(switchConfigMap))
+	assert.False(t, executor.IsScalingDisabled())
+
+	// deleting the ConfigMap resumes the scaling, unless the flag disabled it
+	switchConfigMap.Data[scalingDisabledKey] = "true"
+	assert.NoError(t, r.Client.Update(context.Background(), switchConfigMap))
+	s.forConfigMap(switchConfigMap)
+	assert.NoError(t, r.Client.Delete(context.Background(), switchConfigMap))
+	assert.ElementsMatch(t, allScaledObjects, s.forConfigMap(switchConfigMap))
+	assert.False(t, executor.IsScalingDisabled())
+
+	s.forced = true
+	assert.ElementsMatch(t, allScaledObjects, s.forConfigMap(switchConfigMap))
+	assert.True(t, executor.IsScalingDisabled())
+
+	assert.True(t, s.isSwitchConfigMap(switchConfigMap))
+	assert.False(t, s.isSwitchConfigMap(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: scalingSwitchConfigMapName, Namespace: "default"}}))
+}
+
+func TestScalingSwitchSeededBeforeStart(t *testing.T) {
+	defer executor.SetScalingDisabled(false)
+
+	switchConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: scalingSwitchConfigMapName, Namespace: "keda"},
+		Data:       map[string]string{scalingDisabledKey: "true"},
+	}
+	r := newScaledObjectStateTestReconciler(t, switchConfigMap)
+	s := &scalingSwitch{client: r.Client, namespace: "keda", logger: logr.Discard()}
+
+	// the ConfigMap disables the scaling without the flag
+	assert.NoError(t, s.seed(context.Background(), r.Client))
+	assert.True(t, executor.IsScalingDisabled())
+
+	// an invalid value disables the scaling
+	executor.SetScalingDisabled(false)
+	switchConfigMap.Data[scalingDisabledKey] = "yes please"
+	assert.NoError(t, r.Client.Update(context.Background(), switchConfigMap))
+	assert.NoError(t, s.seed(context.Background(), r.Client))
+	assert.True(t, executor.IsScalingDisabled())
+
+	assert.NoError(t, r.Client.Delete(context.Background(), switchConfigMap))
+	assert.NoError(t, s.seed(context.Background(), r.Client))
+	assert.False(t, executor.IsScalingDisabled())
+
+	s.forced = true
+	assert.NoError(t, s.seed(context.Background(), r.Client))
+	assert.True(t, executor.IsScalingDisabled())
+}
+
+func TestDisabledHPABehavior(t *testing.T) {
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+	assert.False(t, isHPAScalingDisabled(hpa))
+
+	hpa.Spec.Behavior = disabledHPABehavior(nil)
+	assert.True(t, isHPAScalingDisabled(hpa))
+	assert.NotEmpty(t, hpa.Spec.Behavior.ScaleUp.Policies)
+	assert.NotEmpty(t, hpa.Spec.Behavior.ScaleDown.Policies)
+
+	// the behavior of the ScaledObject is kept, only the scaling is disabled
+	stabilizationWindow := int32(60)
+	behavior := &autoscalingv2beta2.HorizontalPodAutoscalerBehavior{
+		ScaleDown: &autoscalingv2beta2.HPAScalingRules{
+			StabilizationWindowSeconds: &stabilizationWindow,
+			Policies:                   []autoscalingv2beta2.HPAScalingPolicy{{Type: autoscalingv2beta2.PodsScalingPolicy, Value: 1, PeriodSeconds: 60}},
+		},
+	}
+	hpa.Spec.Behavior = disabledHPABehavior(behavior)
+	assert.True(t, isHPAScalingDisabled(hpa))
+	assert.Equal(t, behavior.ScaleDown.Policies, hpa.Spec.Behavior.ScaleDown.Policies)
+	assert.Equal(t, &stabilizationWindow, hpa.Spec.Behavior.ScaleDown.StabilizationWindowSeconds)
+	assert.Nil(t, behavior.ScaleUp, "the behavior of the ScaledObject is not modified")
+	assert.Nil(t, behavior.ScaleDown.SelectPolicy, "the behavior of the ScaledObject is not modified")
+}
@@ -1,6 +1,8 @@
 package util
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
@@ -61,3 +63,25 @@ func (ScaleObjectReadyConditionPredicate) Update(e event.UpdateEvent) bool {
 
 	return false
 }
+
+// PodTemplateChangedPredicate passes the updates of Deployments and StatefulSets changing their pod template,
+// so the scaling of the workload, which only changes its replicas, is ignored
+type PodTemplateChangedPredicate struct {
+	predicate.Funcs
+}
+
+func (PodTemplateChangedPredicate) Update(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return false
+	}
+
+	switch oldObj := e.ObjectOld.(type) {
+	case *appsv1.Deployment:
+		newObj, ok := e.ObjectNew.(*appsv1.Deployment)
+		return ok && !equality.Semantic.DeepEqual(oldObj.Spec.Template, newObj.Spec.Template)
+	case *appsv1.StatefulSet:
+		newObj, ok := e.ObjectNew.(*appsv1.StatefulSet)
+		return ok && !equality.Semantic.DeepEqual(oldObj.Spec.Template, newObj.Spec.Template)
+	}
+	return false
+}
@@ -24,6 +24,7 @@ import (
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
 // SetStatusConditions patches given object with passed list of conditions based on the object's type or returns an error.
@@ -44,7 +45,9 @@ func SetStatusConditions(ctx context.Context, client runtimeclient.StatusClient,
 		return err
 	}
 
-	err := client.Status().Patch(ctx, runtimeObj, patch)
+	err := kedautil.RetryWrite(ctx, func() error {
+		return client.Status().Patch(ctx, runtimeObj, patch)
+	})
 	if err != nil {
 		logger.Error(err, "Failed to patch Objects Status with Conditions")
 	}
@@ -55,7 +58,9 @@ func SetStatusConditions(ctx context.Context, client runtimeclient.StatusClient,
 func UpdateScaledObjectStatus(ctx context.Context, client runtimeclient.StatusClient, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, status *kedav1alpha1.ScaledObjectStatus) error {
 	patch := runtimeclient.MergeFrom(scaledObject.DeepCopy())
 	scaledObject.Status = *status
-	err := client.Status().Patch(ctx, scaledObject, patch)
+	err := kedautil.RetryWrite(ctx, func() error {
+		return client.Status().Patch(ctx, scaledObject, patch)
+	})
 	if err != nil {
 		logger.Error(err, "Failed to patch ScaledObjects Status")
 	}
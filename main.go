@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -31,10 +32,18 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	kedacontrollers "github.com/kedacore/keda/v2/controllers/keda"
+	prommetrics "github.com/kedacore/keda/v2/pkg/metrics"
+	"github.com/kedacore/keda/v2/pkg/otlp"
+	"github.com/kedacore/keda/v2/pkg/queryapi"
+	"github.com/kedacore/keda/v2/pkg/scaling/cache"
+	"github.com/kedacore/keda/v2/pkg/simulation"
+	"github.com/kedacore/keda/v2/pkg/statsd"
 	kedautil "github.com/kedacore/keda/v2/pkg/util"
+	"github.com/kedacore/keda/v2/pkg/webhook"
 	"github.com/kedacore/keda/v2/version"
 	//nolint:gci
 	//+kubebuilder:scaffold:imports
@@ -63,11 +72,39 @@ func getWatchNamespace() (string, error) {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == simulation.CommandName {
+		if err := simulation.Run(context.Background(), os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var queryAPIAddr string
+	var queryAPICertFile string
+	var queryAPIKeyFile string
+	var queryAPITokenAudience string
+	var otlpReceiverAddr string
+	var statsdAddr string
+	var webhookReceiverAddr string
+	var webhookReceiverCertFile string
+	var webhookReceiverKeyFile string
+	var scalingDisabled bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&queryAPIAddr, "query-api-bind-address", "", "The address the read-only query API of the ScaledObjects binds to, the API is disabled if empty.")
+	flag.StringVar(&queryAPICertFile, "query-api-tls-cert-file", "", "The TLS certificate file of the query API, required if the API is enabled.")
+	flag.StringVar(&queryAPIKeyFile, "query-api-tls-key-file", "", "The TLS private key file of the query API, required if the API is enabled.")
+	flag.StringVar(&queryAPITokenAudience, "query-api-token-audience", queryapi.DefaultTokenAudience, "The audience the bearer tokens of the query API callers must be issued for.")
+	flag.StringVar(&otlpReceiverAddr, "otlp-receiver-bind-address", "", "The address the OTLP/HTTP metrics receiver of the otlp scaler binds to, the receiver is disabled if empty.")
+	flag.StringVar(&statsdAddr, "statsd-bind-address", "", "The address the StatsD listener of the statsd scaler binds to, in UDP for the gauges and in TCP for the scaler, the listener is disabled if empty.")
+	flag.StringVar(&webhookReceiverAddr, "webhook-receiver-bind-address", "", "The address the webhook receiver of the webhook scaler binds to, the receiver is disabled if empty.")
+	flag.StringVar(&webhookReceiverCertFile, "webhook-receiver-tls-cert-file", "", "The TLS certificate file of the webhook receiver, required to enable it.")
+	flag.StringVar(&webhookReceiverKeyFile, "webhook-receiver-tls-key-file", "", "The TLS private key file of the webhook receiver, required to enable it.")
+	flag.BoolVar(&scalingDisabled, "scaling-disabled", false, "Disable the scaling of all the ScaledObjects and ScaledJobs, the emergency stop can also be toggled with the keda-scaling-switch ConfigMap.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -92,6 +129,9 @@ func main() {
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "operator.keda.sh",
 		Namespace:              namespace,
+
+		// the lease is released once the scale loops stopped, so the next leader starts right away
+		LeaderElectionReleaseOnCancel: true,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -117,23 +157,50 @@ func main() {
 		os.Exit(1)
 	}
 
+	persistScaledObjectState, err := kedautil.ResolveOsEnvBool("KEDA_PERSIST_SCALEDOBJECT_STATE", false)
+	if err != nil {
+		setupLog.Error(err, "Invalid KEDA_PERSIST_SCALEDOBJECT_STATE")
+		os.Exit(1)
+	}
+
+	scaleLoopStallFactor, err := kedautil.ResolveOsEnvInt("KEDA_SCALE_LOOP_STALL_FACTOR", 5)
+	if err != nil {
+		setupLog.Error(err, "Invalid KEDA_SCALE_LOOP_STALL_FACTOR")
+		os.Exit(1)
+	}
+
+	prommetrics.RegisterAPICallMetrics(ctrlmetrics.Registry)
+	prommetrics.RegisterScaleFromZeroMetrics(ctrlmetrics.Registry)
+	prommetrics.RegisterScaleLoopMetrics(ctrlmetrics.Registry)
+	if pricingFile := os.Getenv("KEDA_API_CALL_PRICING_FILE"); pricingFile != "" {
+		if err := prommetrics.LoadAPICallPricing(pricingFile); err != nil {
+			setupLog.Error(err, "Invalid KEDA_API_CALL_PRICING_FILE")
+			os.Exit(1)
+		}
+	}
+
 	globalHTTPTimeout := time.Duration(globalHTTPTimeoutMS) * time.Millisecond
 	eventRecorder := mgr.GetEventRecorderFor("keda-operator")
 
-	if err = (&kedacontrollers.ScaledObjectReconciler{
-		Client:            mgr.GetClient(),
-		Scheme:            mgr.GetScheme(),
-		GlobalHTTPTimeout: globalHTTPTimeout,
-		Recorder:          eventRecorder,
-	}).SetupWithManager(mgr, controller.Options{MaxConcurrentReconciles: scaledObjectMaxReconciles}); err != nil {
+	scaledObjectReconciler := &kedacontrollers.ScaledObjectReconciler{
+		Client:               mgr.GetClient(),
+		Scheme:               mgr.GetScheme(),
+		GlobalHTTPTimeout:    globalHTTPTimeout,
+		Recorder:             eventRecorder,
+		PersistState:         persistScaledObjectState,
+		ScalingDisabled:      scalingDisabled,
+		ScaleLoopStallFactor: scaleLoopStallFactor,
+	}
+	if err = scaledObjectReconciler.SetupWithManager(mgr, controller.Options{MaxConcurrentReconciles: scaledObjectMaxReconciles}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ScaledObject")
 		os.Exit(1)
 	}
 	if err = (&kedacontrollers.ScaledJobReconciler{
-		Client:            mgr.GetClient(),
-		Scheme:            mgr.GetScheme(),
-		GlobalHTTPTimeout: globalHTTPTimeout,
-		Recorder:          eventRecorder,
+		Client:               mgr.GetClient(),
+		Scheme:               mgr.GetScheme(),
+		GlobalHTTPTimeout:    globalHTTPTimeout,
+		Recorder:             eventRecorder,
+		ScaleLoopStallFactor: scaleLoopStallFactor,
 	}).SetupWithManager(mgr, controller.Options{MaxConcurrentReconciles: scaledJobMaxReconciles}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ScaledJob")
 		os.Exit(1)
@@ -156,6 +223,46 @@ func main() {
 	}
 	//+kubebuilder:scaffold:builder
 
+	if queryAPIAddr != "" {
+		queryAPIServer, err := queryapi.NewServer(queryAPIAddr, queryAPICertFile, queryAPIKeyFile, queryAPITokenAudience, mgr.GetClient(), scaledObjectReconciler.ScaleHandler())
+		if err != nil {
+			setupLog.Error(err, "unable to create the query API")
+			os.Exit(1)
+		}
+		// the query API serves the metric values read by the scale loops instead of querying the scalers
+		cache.SetRecordPollMetrics(true)
+		if err := mgr.Add(queryAPIServer); err != nil {
+			setupLog.Error(err, "unable to set up the query API")
+			os.Exit(1)
+		}
+	}
+
+	if otlpReceiverAddr != "" {
+		if err := mgr.Add(otlp.NewReceiver(otlpReceiverAddr)); err != nil {
+			setupLog.Error(err, "unable to set up the OTLP receiver")
+			os.Exit(1)
+		}
+	}
+
+	if statsdAddr != "" {
+		if err := mgr.Add(statsd.NewListener(statsdAddr)); err != nil {
+			setupLog.Error(err, "unable to set up the StatsD listener")
+			os.Exit(1)
+		}
+	}
+
+	if webhookReceiverAddr != "" {
+		webhookReceiver, err := webhook.NewReceiver(webhookReceiverAddr, webhookReceiverCertFile, webhookReceiverKeyFile, mgr.GetClient())
+		if err != nil {
+			setupLog.Error(err, "unable to create the webhook receiver")
+			os.Exit(1)
+		}
+		if err := mgr.Add(webhookReceiver); err != nil {
+			setupLog.Error(err, "unable to set up the webhook receiver")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -32,6 +32,9 @@ const (
 	// ScaledObjectDeleted is for event when ScaledObject is deleted
 	ScaledObjectDeleted = "ScaledObjectDeleted"
 
+	// ScaledObjectStateRestored is for event when the persisted paused state of a recreated ScaledObject is restored
+	ScaledObjectStateRestored = "ScaledObjectStateRestored"
+
 	// ScaledJobDeleted is for event when ScaledJob is deleted
 	ScaledJobDeleted = "ScaledJobDeleted"
 
@@ -44,6 +47,9 @@ const (
 	// KEDAScalerFailed is for event when a scaler fails for a ScaledJob or a ScaledObject
 	KEDAScalerFailed = "KEDAScalerFailed"
 
+	// KEDAScaleLoopStalled is for event when the scale loop of a ScaledObject or ScaledJob stalled and was restarted
+	KEDAScaleLoopStalled = "KEDAScaleLoopStalled"
+
 	// KEDAScaleTargetActivated is for event when the scale target of ScaledObject was activated
 	KEDAScaleTargetActivated = "KEDAScaleTargetActivated"
 
@@ -56,6 +62,9 @@ const (
 	// KEDAScaleTargetDeactivationFailed is for event when the deactivation of the scale target for ScaledObject fails
 	KEDAScaleTargetDeactivationFailed = "KEDAScaleTargetDeactivationFailed"
 
+	// KEDAScaleTargetReplicasMismatch is for event when spec.replicas of the scale target doesn't follow its /scale subresource
+	KEDAScaleTargetReplicasMismatch = "KEDAScaleTargetReplicasMismatch"
+
 	// KEDAJobsCreated is for event when jobs for ScaledJob are created
 	KEDAJobsCreated = "KEDAJobsCreated"
 
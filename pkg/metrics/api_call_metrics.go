@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	apiCallLabels  = []string{"scaler", "host"}
+	scalerAPICalls = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "keda",
+			Subsystem: "scaler",
+			Name:      "api_calls_total",
+			Help:      "Number of calls made by the scalers to their backend",
+		},
+		apiCallLabels,
+	)
+	scalerAPICallsCost = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "keda",
+			Subsystem: "scaler",
+			Name:      "api_calls_estimated_cost_dollars_total",
+			Help:      "Estimated cost in dollars of the calls made by the scalers to their backend, based on the API call pricing table",
+		},
+		apiCallLabels,
+	)
+
+	apiCallPricingLock sync.RWMutex
+	apiCallPricing     map[string]float64
+)
+
+// RegisterAPICallMetrics registers the scaler API call metrics, they are registered with the metrics adapter registry by default
+func RegisterAPICallMetrics(registerer prometheus.Registerer) {
+	registerer.MustRegister(scalerAPICalls)
+	registerer.MustRegister(scalerAPICallsCost)
+}
+
+// LoadAPICallPricing reads the pricing table used to estimate the cost of the scaler API calls,
+// a json object mapping a backend host or a scaler type to the price in dollars of a single call
+func LoadAPICallPricing(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	pricing := map[string]float64{}
+	if err := json.Unmarshal(data, &pricing); err != nil {
+		return fmt.Errorf("error parsing API call pricing table %s: %s", path, err)
+	}
+	for key, price := range pricing {
+		if price < 0 {
+			return fmt.Errorf("the price of %s in the API call pricing table must not be negative", key)
+		}
+	}
+
+	apiCallPricingLock.Lock()
+	defer apiCallPricingLock.Unlock()
+	apiCallPricing = pricing
+	return nil
+}
+
+// getAPICallPrice returns the price of a call, the host takes precedence over the scaler type
+func getAPICallPrice(scaler, host string) (float64, bool) {
+	apiCallPricingLock.RLock()
+	defer apiCallPricingLock.RUnlock()
+	if price, ok := apiCallPricing[host]; ok {
+		return price, true
+	}
+	price, ok := apiCallPricing[scaler]
+	return price, ok
+}
+
+// RecordScalerAPICall counts a call made by a scaler to its backend, and adds its estimated cost if it's priced
+func RecordScalerAPICall(scaler, host string) {
+	labels := prometheus.Labels{"scaler": scaler, "host": host}
+	scalerAPICalls.With(labels).Inc()
+	if price, ok := getAPICallPrice(scaler, host); ok {
+		scalerAPICallsCost.With(labels).Add(price)
+	}
+}
+
+// apiCallRoundTripper records every request going through it as a call of the scaler
+type apiCallRoundTripper struct {
+	scaler string
+	next   http.RoundTripper
+}
+
+func (rt *apiCallRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	RecordScalerAPICall(rt.scaler, req.URL.Host)
+	return rt.next.RoundTrip(req)
+}
+
+// InstrumentHTTPClient makes the client record its requests as API calls of the scaler
+func InstrumentHTTPClient(client *http.Client, scaler string) *http.Client {
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	client.Transport = &apiCallRoundTripper{scaler: scaler, next: next}
+	return client
+}
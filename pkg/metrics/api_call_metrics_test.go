@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumentHTTPClientRecordsAPICalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	pricingFile := filepath.Join(t.TempDir(), "pricing.json")
+	assert.NoError(t, ioutil.WriteFile(pricingFile, []byte(`{"metrics-api": 0.5, "`+serverURL.Host+`": 0.25}`), 0600))
+	assert.NoError(t, LoadAPICallPricing(pricingFile))
+	defer func() { apiCallPricing = nil }()
+
+	client := InstrumentHTTPClient(&http.Client{}, "metrics-api")
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	labels := prometheus.Labels{"scaler": "metrics-api", "host": serverURL.Host}
+	assert.Equal(t, float64(2), testutil.ToFloat64(scalerAPICalls.With(labels)))
+	// the price of the host takes precedence over the price of the scaler
+	assert.Equal(t, 0.5, testutil.ToFloat64(scalerAPICallsCost.With(labels)))
+
+	RecordScalerAPICall("metrics-api", "other-host")
+	assert.Equal(t, 0.5, testutil.ToFloat64(scalerAPICallsCost.With(prometheus.Labels{"scaler": "metrics-api", "host": "other-host"})))
+
+	RecordScalerAPICall("prometheus", "other-host")
+	assert.Equal(t, float64(1), testutil.ToFloat64(scalerAPICalls.With(prometheus.Labels{"scaler": "prometheus", "host": "other-host"})))
+	assert.Equal(t, float64(0), testutil.ToFloat64(scalerAPICallsCost.With(prometheus.Labels{"scaler": "prometheus", "host": "other-host"})))
+}
+
+func TestLoadAPICallPricing(t *testing.T) {
+	dir := t.TempDir()
+
+	invalid := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, ioutil.WriteFile(invalid, []byte(`["aws-sqs-queue"]`), 0600))
+	assert.Error(t, LoadAPICallPricing(invalid))
+
+	negative := filepath.Join(dir, "negative.json")
+	assert.NoError(t, ioutil.WriteFile(negative, []byte(`{"aws-sqs-queue": -1}`), 0600))
+	assert.Error(t, LoadAPICallPricing(negative))
+
+	assert.Error(t, LoadAPICallPricing(filepath.Join(dir, "missing.json")))
+}
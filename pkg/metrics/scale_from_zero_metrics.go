@@ -0,0 +1,44 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var scaleFromZeroDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "keda",
+		Subsystem: "scaled_object",
+		Name:      "scale_from_zero_duration_seconds",
+		Help:      "Duration between the activation of a ScaledObject scaled to zero and its scale target having a ready replica",
+		Buckets:   []float64{1, 2, 5, 10, 15, 30, 60, 120, 300, 600},
+	},
+	[]string{"namespace", "scaledObject"},
+)
+
+// RegisterScaleFromZeroMetrics registers the scale from zero metrics, they are recorded by the operator
+func RegisterScaleFromZeroMetrics(registerer prometheus.Registerer) {
+	registerer.MustRegister(scaleFromZeroDuration)
+}
+
+// RecordScaleFromZeroDuration observes the duration of a scale from zero of the ScaledObject
+func RecordScaleFromZeroDuration(namespace string, scaledObject string, duration time.Duration) {
+	scaleFromZeroDuration.With(prometheus.Labels{"namespace": namespace, "scaledObject": scaledObject}).Observe(duration.Seconds())
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var scaleLoopStalledTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "keda",
+		Subsystem: "scale_loop",
+		Name:      "stalled_total",
+		Help:      "Total number of scale loops of the ScaledObjects and ScaledJobs restarted by the watchdog because they stalled",
+	},
+	[]string{"type", "namespace", "name"},
+)
+
+// RegisterScaleLoopMetrics registers the scale loop metrics, they are recorded by the operator
+func RegisterScaleLoopMetrics(registerer prometheus.Registerer) {
+	registerer.MustRegister(scaleLoopStalledTotal)
+}
+
+// RecordScaleLoopStalled counts a restart of the stalled scale loop of a ScaledObject or a ScaledJob
+func RecordScaleLoopStalled(kind string, namespace string, name string) {
+	scaleLoopStalledTotal.With(prometheus.Labels{"type": kind, "namespace": namespace, "name": name}).Inc()
+}
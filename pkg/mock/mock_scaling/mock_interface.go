@@ -91,3 +91,27 @@ func (mr *MockScaleHandlerMockRecorder) HandleScalableObject(ctx, scalableObject
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleScalableObject", reflect.TypeOf((*MockScaleHandler)(nil).HandleScalableObject), ctx, scalableObject)
 }
+
+// Shutdown mocks base method.
+func (m *MockScaleHandler) Shutdown(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Shutdown", ctx)
+}
+
+// Shutdown indicates an expected call of Shutdown.
+func (mr *MockScaleHandlerMockRecorder) Shutdown(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockScaleHandler)(nil).Shutdown), ctx)
+}
+
+// WatchScaleLoops mocks base method.
+func (m *MockScaleHandler) WatchScaleLoops(ctx context.Context, stallFactor int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "WatchScaleLoops", ctx, stallFactor)
+}
+
+// WatchScaleLoops indicates an expected call of WatchScaleLoops.
+func (mr *MockScaleHandlerMockRecorder) WatchScaleLoops(ctx, stallFactor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchScaleLoops", reflect.TypeOf((*MockScaleHandler)(nil).WatchScaleLoops), ctx, stallFactor)
+}
@@ -0,0 +1,364 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otlp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The requests are decoded by hand rather than with the generated OTLP types: the version of the OTLP protos
+// compiled in for the tracing of the metrics server predates the metric attributes of OTLP 1.0.
+// Only the gauge and sum metrics are decoded, the other kinds of metrics can't be reduced to a single value.
+
+// field numbers of the OTLP 1.0 metrics protos
+const (
+	exportRequestResourceMetrics = 1
+
+	resourceMetricsResource                     = 1
+	resourceMetricsScopeMetrics                 = 2
+	resourceMetricsInstrumentationLibraryMetric = 1000
+
+	resourceAttributes = 1
+
+	scopeMetricsMetrics = 2
+
+	metricName  = 1
+	metricGauge = 5
+	metricSum   = 7
+
+	gaugeOrSumDataPoints = 1
+
+	dataPointTime       = 3
+	dataPointAsDouble   = 4
+	dataPointAsInt      = 6
+	dataPointAttributes = 7
+
+	keyValueKey   = 1
+	keyValueValue = 2
+
+	anyValueString = 1
+	anyValueBool   = 2
+	anyValueInt    = 3
+	anyValueDouble = 4
+)
+
+// protoField is a field of a protobuf message, with its value according to its wire type
+type protoField struct {
+	number protowire.Number
+	typ    protowire.Type
+	bytes  []byte
+	varint uint64
+	fixed  uint64
+}
+
+// decodeFields calls f for each field of the protobuf message b
+func decodeFields(b []byte, f func(field protoField) error) error {
+	for len(b) > 0 {
+		number, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		field := protoField{number: number, typ: typ}
+		switch typ {
+		case protowire.BytesType:
+			field.bytes, n = protowire.ConsumeBytes(b)
+		case protowire.VarintType:
+			field.varint, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed64Type:
+			field.fixed, n = protowire.ConsumeFixed64(b)
+		default:
+			n = protowire.ConsumeFieldValue(number, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if err := f(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeProtobuf returns the gauge and sum data points of an ExportMetricsServiceRequest in the protobuf encoding
+func DecodeProtobuf(b []byte) ([]DataPoint, error) {
+	var points []DataPoint
+	err := decodeFields(b, func(field protoField) error {
+		if field.number != exportRequestResourceMetrics || field.typ != protowire.BytesType {
+			return nil
+		}
+		resourcePoints, err := decodeResourceMetrics(field.bytes)
+		points = append(points, resourcePoints...)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP metrics request: %s", err)
+	}
+	return points, nil
+}
+
+func decodeResourceMetrics(b []byte) ([]DataPoint, error) {
+	resourceAttrs := map[string]string{}
+	var scopes [][]byte
+	err := decodeFields(b, func(field protoField) error {
+		if field.typ != protowire.BytesType {
+			return nil
+		}
+		switch field.number {
+		case resourceMetricsResource:
+			return decodeFields(field.bytes, func(field protoField) error {
+				if field.number == resourceAttributes && field.typ == protowire.BytesType {
+					return decodeKeyValue(field.bytes, resourceAttrs)
+				}
+				return nil
+			})
+		case resourceMetricsScopeMetrics, resourceMetricsInstrumentationLibraryMetric:
+			scopes = append(scopes, field.bytes)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var points []DataPoint
+	for _, scope := range scopes {
+		err := decodeFields(scope, func(field protoField) error {
+			if field.number != scopeMetricsMetrics || field.typ != protowire.BytesType {
+				return nil
+			}
+			metricPoints, err := decodeMetric(field.bytes, resourceAttrs)
+			points = append(points, metricPoints...)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+func decodeMetric(b []byte, resourceAttrs map[string]string) ([]DataPoint, error) {
+	name := ""
+	var dataPoints [][]byte
+	err := decodeFields(b, func(field protoField) error {
+		if field.typ != protowire.BytesType {
+			return nil
+		}
+		switch field.number {
+		case metricName:
+			name = string(field.bytes)
+		case metricGauge, metricSum:
+			return decodeFields(field.bytes, func(field protoField) error {
+				if field.number == gaugeOrSumDataPoints && field.typ == protowire.BytesType {
+					dataPoints = append(dataPoints, field.bytes)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]DataPoint, 0, len(dataPoints))
+	for _, dataPoint := range dataPoints {
+		point := DataPoint{Name: name, Attributes: copyAttributes(resourceAttrs), Time: time.Now()}
+		err := decodeFields(dataPoint, func(field protoField) error {
+			switch {
+			case field.number == dataPointTime && field.typ == protowire.Fixed64Type:
+				point.Time = unixNano(field.fixed)
+			case field.number == dataPointAsDouble && field.typ == protowire.Fixed64Type:
+				point.Value = math.Float64frombits(field.fixed)
+			case field.number == dataPointAsInt && field.typ == protowire.Fixed64Type:
+				point.Value = float64(int64(field.fixed))
+			case field.number == dataPointAttributes && field.typ == protowire.BytesType:
+				return decodeKeyValue(field.bytes, point.Attributes)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// decodeKeyValue adds the KeyValue to attributes, the values which aren't scalars are ignored
+func decodeKeyValue(b []byte, attributes map[string]string) error {
+	key := ""
+	value := ""
+	hasValue := false
+	err := decodeFields(b, func(field protoField) error {
+		switch {
+		case field.number == keyValueKey && field.typ == protowire.BytesType:
+			key = string(field.bytes)
+		case field.number == keyValueValue && field.typ == protowire.BytesType:
+			return decodeFields(field.bytes, func(field protoField) error {
+				switch {
+				case field.number == anyValueString && field.typ == protowire.BytesType:
+					value, hasValue = string(field.bytes), true
+				case field.number == anyValueBool && field.typ == protowire.VarintType:
+					value, hasValue = strconv.FormatBool(field.varint != 0), true
+				case field.number == anyValueInt && field.typ == protowire.VarintType:
+					value, hasValue = strconv.FormatInt(int64(field.varint), 10), true
+				case field.number == anyValueDouble && field.typ == protowire.Fixed64Type:
+					value, hasValue = strconv.FormatFloat(math.Float64frombits(field.fixed), 'g', -1, 64), true
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err == nil && hasValue {
+		attributes[key] = value
+	}
+	return err
+}
+
+// JSON encoding of the OTLP metrics protos, the 64 bits integers are strings according to the protobuf JSON mapping
+// but some clients send numbers
+type jsonExportRequest struct {
+	ResourceMetrics []struct {
+		Resource struct {
+			Attributes []jsonKeyValue `json:"attributes"`
+		} `json:"resource"`
+		ScopeMetrics                  []jsonScopeMetrics `json:"scopeMetrics"`
+		InstrumentationLibraryMetrics []jsonScopeMetrics `json:"instrumentationLibraryMetrics"`
+	} `json:"resourceMetrics"`
+}
+
+type jsonScopeMetrics struct {
+	Metrics []struct {
+		Name  string            `json:"name"`
+		Gauge *jsonNumberPoints `json:"gauge"`
+		Sum   *jsonNumberPoints `json:"sum"`
+	} `json:"metrics"`
+}
+
+type jsonNumberPoints struct {
+	DataPoints []struct {
+		Attributes   []jsonKeyValue `json:"attributes"`
+		TimeUnixNano json.Number    `json:"timeUnixNano"`
+		AsDouble     *json.Number   `json:"asDouble"`
+		AsInt        *json.Number   `json:"asInt"`
+	} `json:"dataPoints"`
+}
+
+type jsonKeyValue struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue *string      `json:"stringValue"`
+		BoolValue   *bool        `json:"boolValue"`
+		IntValue    *json.Number `json:"intValue"`
+		DoubleValue *json.Number `json:"doubleValue"`
+	} `json:"value"`
+}
+
+// DecodeJSON returns the gauge and sum data points of an ExportMetricsServiceRequest in the JSON encoding
+func DecodeJSON(b []byte) ([]DataPoint, error) {
+	var request jsonExportRequest
+	if err := json.Unmarshal(b, &request); err != nil {
+		return nil, fmt.Errorf("invalid OTLP metrics request: %s", err)
+	}
+
+	var points []DataPoint
+	for _, resourceMetrics := range request.ResourceMetrics {
+		resourceAttrs := jsonAttributes(resourceMetrics.Resource.Attributes, map[string]string{})
+		scopes := make([]jsonScopeMetrics, 0, len(resourceMetrics.ScopeMetrics)+len(resourceMetrics.InstrumentationLibraryMetrics))
+		scopes = append(scopes, resourceMetrics.ScopeMetrics...)
+		scopes = append(scopes, resourceMetrics.InstrumentationLibraryMetrics...)
+		for _, scope := range scopes {
+			for _, metric := range scope.Metrics {
+				for _, numberPoints := range []*jsonNumberPoints{metric.Gauge, metric.Sum} {
+					if numberPoints == nil {
+						continue
+					}
+					for _, dataPoint := range numberPoints.DataPoints {
+						point := DataPoint{Name: metric.Name, Attributes: jsonAttributes(dataPoint.Attributes, copyAttributes(resourceAttrs)), Time: time.Now()}
+						if dataPoint.TimeUnixNano != "" {
+							timeUnixNano, err := strconv.ParseUint(string(dataPoint.TimeUnixNano), 10, 64)
+							if err != nil {
+								return nil, fmt.Errorf("invalid timeUnixNano of metric %s: %s", metric.Name, err)
+							}
+							point.Time = unixNano(timeUnixNano)
+						}
+						var value *json.Number
+						switch {
+						case dataPoint.AsDouble != nil:
+							value = dataPoint.AsDouble
+						case dataPoint.AsInt != nil:
+							value = dataPoint.AsInt
+						}
+						if value != nil {
+							v, err := value.Float64()
+							if err != nil {
+								return nil, fmt.Errorf("invalid value of metric %s: %s", metric.Name, err)
+							}
+							point.Value = v
+						}
+						points = append(points, point)
+					}
+				}
+			}
+		}
+	}
+	return points, nil
+}
+
+func jsonAttributes(keyValues []jsonKeyValue, attributes map[string]string) map[string]string {
+	for _, kv := range keyValues {
+		switch {
+		case kv.Value.StringValue != nil:
+			attributes[kv.Key] = *kv.Value.StringValue
+		case kv.Value.BoolValue != nil:
+			attributes[kv.Key] = strconv.FormatBool(*kv.Value.BoolValue)
+		case kv.Value.IntValue != nil:
+			attributes[kv.Key] = kv.Value.IntValue.String()
+		case kv.Value.DoubleValue != nil:
+			attributes[kv.Key] = kv.Value.DoubleValue.String()
+		}
+	}
+	return attributes
+}
+
+func copyAttributes(attributes map[string]string) map[string]string {
+	c := make(map[string]string, len(attributes))
+	for key, value := range attributes {
+		c[key] = value
+	}
+	return c
+}
+
+// unixNano returns the time of a data point, the points without time are stamped as received
+func unixNano(ns uint64) time.Time {
+	if ns == 0 {
+		return time.Now()
+	}
+	return time.Unix(0, int64(ns))
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otlp
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// MetricsPath is the path the OTLP/HTTP exporters push the metrics to
+	MetricsPath = "/v1/metrics"
+	// QueryPath is the path the otlp scaler queries the latest values at
+	QueryPath = "/api/v1/query"
+
+	// SeriesRetention is how long a series is kept after its last data point
+	SeriesRetention = time.Hour
+
+	maxRequestSize  = 16 << 20
+	evictInterval   = time.Minute
+	shutdownTimeout = 10 * time.Second
+
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeJSON     = "application/json"
+)
+
+// QueryResponse is the response of the query endpoint
+type QueryResponse struct {
+	// Value is the sum of the latest values of the matching series
+	Value float64 `json:"value"`
+	// Series is the number of matching series
+	Series int `json:"series"`
+}
+
+// Receiver is an OTLP/HTTP metrics receiver keeping the latest value of each series, which the otlp scaler queries
+type Receiver struct {
+	address string
+	store   *Store
+	logger  logr.Logger
+}
+
+// NewReceiver creates the Receiver listening on address
+func NewReceiver(address string) *Receiver {
+	return &Receiver{
+		address: address,
+		store:   NewStore(),
+		logger:  logf.Log.WithName("otlp_receiver"),
+	}
+}
+
+// Start serves the receiver until the context is done, it implements manager.Runnable
+func (r *Receiver) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:    r.address,
+		Handler: r.handler(),
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		r.logger.Info("Starting OTLP receiver", "address", r.address)
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+		close(errs)
+	}()
+
+	ticker := time.NewTicker(evictInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-errs:
+			return err
+		case <-ticker.C:
+			r.store.Evict(time.Now().Add(-SeriesRetention))
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			return srv.Shutdown(shutdownCtx)
+		}
+	}
+}
+
+// NeedLeaderElection returns false, the metrics are pushed to all the replicas of the operator
+func (r *Receiver) NeedLeaderElection() bool {
+	return false
+}
+
+func (r *Receiver) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(MetricsPath, r.handleMetrics)
+	mux.Handle(QueryPath, QueryHandler(r.store, r.logger))
+	return mux
+}
+
+// handleMetrics implements the OTLP/HTTP export of metrics, in the protobuf or JSON encoding
+func (r *Receiver) handleMetrics(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	contentType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || (contentType != contentTypeProtobuf && contentType != contentTypeJSON) {
+		http.Error(w, fmt.Sprintf("unsupported content type, expected %s or %s", contentTypeProtobuf, contentTypeJSON), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var body io.Reader = http.MaxBytesReader(w, req.Body, maxRequestSize)
+	if strings.EqualFold(req.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer gz.Close()
+		body = io.LimitReader(gz, maxRequestSize)
+	}
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var points []DataPoint
+	if contentType == contentTypeProtobuf {
+		points, err = DecodeProtobuf(b)
+	} else {
+		points, err = DecodeJSON(b)
+	}
+	if err != nil {
+		r.logger.V(1).Info("Rejecting OTLP metrics request", "error", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, point := range points {
+		r.store.Record(point)
+	}
+
+	// an empty ExportMetricsServiceResponse, in the encoding of the request
+	w.Header().Set("Content-Type", contentType)
+	if contentType == contentTypeJSON {
+		_, _ = w.Write([]byte("{}"))
+	}
+}
+
+// QueryHandler serves the latest value of a metric of the store, the query parameters being the name of the metric,
+// its attributes as key=value and optionally the maxAge of the data points
+func QueryHandler(store *Store, logger logr.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := req.URL.Query()
+		name := query.Get("name")
+		if name == "" {
+			http.Error(w, "no name given", http.StatusBadRequest)
+			return
+		}
+
+		attributes := map[string]string{}
+		for _, attribute := range query["attribute"] {
+			kv := strings.SplitN(attribute, "=", 2)
+			if len(kv) != 2 || kv[0] == "" {
+				http.Error(w, fmt.Sprintf("invalid attribute %q, expected key=value", attribute), http.StatusBadRequest)
+				return
+			}
+			attributes[kv[0]] = kv[1]
+		}
+
+		maxAge := SeriesRetention
+		if val := query.Get("maxAge"); val != "" {
+			d, err := time.ParseDuration(val)
+			if err != nil || d <= 0 {
+				http.Error(w, fmt.Sprintf("invalid maxAge %q", val), http.StatusBadRequest)
+				return
+			}
+			maxAge = d
+		}
+
+		value, series := store.Query(name, attributes, time.Now().Add(-maxAge))
+		w.Header().Set("Content-Type", contentTypeJSON)
+		if err := json.NewEncoder(w).Encode(QueryResponse{Value: value, Series: series}); err != nil {
+			logger.Error(err, "cannot write the query response")
+		}
+	}
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func appendMessage(b []byte, number protowire.Number, message []byte) []byte {
+	b = protowire.AppendTag(b, number, protowire.BytesType)
+	return protowire.AppendBytes(b, message)
+}
+
+func stringKeyValue(key, value string) []byte {
+	var anyValue []byte
+	anyValue = appendMessage(anyValue, anyValueString, []byte(value))
+	var kv []byte
+	kv = appendMessage(kv, keyValueKey, []byte(key))
+	return appendMessage(kv, keyValueValue, anyValue)
+}
+
+func intKeyValue(key string, value int64) []byte {
+	var anyValue []byte
+	anyValue = protowire.AppendTag(anyValue, anyValueInt, protowire.VarintType)
+	anyValue = protowire.AppendVarint(anyValue, uint64(value))
+	var kv []byte
+	kv = appendMessage(kv, keyValueKey, []byte(key))
+	return appendMessage(kv, keyValueValue, anyValue)
+}
+
+// protobufRequest encodes an ExportMetricsServiceRequest with a gauge of a double and a sum of an int
+func protobufRequest(ts time.Time) []byte {
+	var doublePoint []byte
+	doublePoint = appendMessage(doublePoint, dataPointAttributes, stringKeyValue("queue", "orders"))
+	doublePoint = protowire.AppendTag(doublePoint, dataPointTime, protowire.Fixed64Type)
+	doublePoint = protowire.AppendFixed64(doublePoint, uint64(ts.UnixNano()))
+	doublePoint = protowire.AppendTag(doublePoint, dataPointAsDouble, protowire.Fixed64Type)
+	doublePoint = protowire.AppendFixed64(doublePoint, math.Float64bits(2.5))
+
+	var intPoint []byte
+	intPoint = appendMessage(intPoint, dataPointAttributes, intKeyValue("shard", 3))
+	intPoint = protowire.AppendTag(intPoint, dataPointAsInt, protowire.Fixed64Type)
+	intPoint = protowire.AppendFixed64(intPoint, uint64(42))
+
+	var gauge, sum []byte
+	gauge = appendMessage(gauge, gaugeOrSumDataPoints, doublePoint)
+	sum = appendMessage(sum, gaugeOrSumDataPoints, intPoint)
+
+	var gaugeMetric, sumMetric []byte
+	gaugeMetric = appendMessage(gaugeMetric, metricName, []byte("queue_depth"))
+	gaugeMetric = appendMessage(gaugeMetric, metricGauge, gauge)
+	sumMetric = appendMessage(sumMetric, metricName, []byte("processed"))
+	sumMetric = appendMessage(sumMetric, metricSum, sum)
+
+	var scope []byte
+	scope = appendMessage(scope, scopeMetricsMetrics, gaugeMetric)
+	scope = appendMessage(scope, scopeMetricsMetrics, sumMetric)
+
+	var resource []byte
+	resource = appendMessage(resource, resourceAttributes, stringKeyValue("service.name", "worker"))
+
+	var resourceMetrics []byte
+	resourceMetrics = appendMessage(resourceMetrics, resourceMetricsResource, resource)
+	resourceMetrics = appendMessage(resourceMetrics, resourceMetricsScopeMetrics, scope)
+
+	return appendMessage(nil, exportRequestResourceMetrics, resourceMetrics)
+}
+
+func TestDecodeProtobuf(t *testing.T) {
+	ts := time.Unix(1650000000, 0)
+	points, err := DecodeProtobuf(protobufRequest(ts))
+	assert.NoError(t, err)
+	assert.Equal(t, []DataPoint{
+		{Name: "queue_depth", Attributes: map[string]string{"service.name": "worker", "queue": "orders"}, Value: 2.5, Time: ts},
+		{Name: "processed", Attributes: map[string]string{"service.name": "worker", "shard": "3"}, Value: 42, Time: points[1].Time},
+	}, points)
+	assert.WithinDuration(t, time.Now(), points[1].Time, time.Minute)
+
+	_, err = DecodeProtobuf([]byte{0x0a, 0x10, 0x01})
+	assert.Error(t, err)
+}
+
+func TestDecodeJSON(t *testing.T) {
+	request := `{"resourceMetrics":[{"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"worker"}}]},
+"scopeMetrics":[{"metrics":[
+  {"name":"queue_depth","gauge":{"dataPoints":[{"attributes":[{"key":"queue","value":{"stringValue":"orders"}}],"timeUnixNano":"1650000000000000000","asDouble":2.5}]}},
+  {"name":"processed","sum":{"dataPoints":[{"attributes":[{"key":"shard","value":{"intValue":"3"}}],"timeUnixNano":1650000000000000000,"asInt":"42"}]}},
+  {"name":"latency","histogram":{"dataPoints":[{"count":"3"}]}}
+]}]}]}`
+
+	ts := time.Unix(1650000000, 0)
+	points, err := DecodeJSON([]byte(request))
+	assert.NoError(t, err)
+	assert.Equal(t, []DataPoint{
+		{Name: "queue_depth", Attributes: map[string]string{"service.name": "worker", "queue": "orders"}, Value: 2.5, Time: ts},
+		{Name: "processed", Attributes: map[string]string{"service.name": "worker", "shard": "3"}, Value: 42, Time: ts},
+	}, points)
+
+	_, err = DecodeJSON([]byte(`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"x","gauge":{"dataPoints":[{"asInt":"abc"}]}}]}]}]}`))
+	assert.Error(t, err)
+}
+
+func TestStoreQuery(t *testing.T) {
+	now := time.Now()
+	store := NewStore()
+	store.Record(DataPoint{Name: "queue_depth", Attributes: map[string]string{"queue": "orders", "pod": "a"}, Value: 3, Time: now})
+	store.Record(DataPoint{Name: "queue_depth", Attributes: map[string]string{"queue": "orders", "pod": "b"}, Value: 4, Time: now})
+	store.Record(DataPoint{Name: "queue_depth", Attributes: map[string]string{"queue": "payments", "pod": "a"}, Value: 10, Time: now})
+	store.Record(DataPoint{Name: "queue_depth", Attributes: map[string]string{"queue": "stale", "pod": "a"}, Value: 20, Time: now.Add(-time.Hour)})
+	// an older data point doesn't replace the latest one
+	store.Record(DataPoint{Name: "queue_depth", Attributes: map[string]string{"pod": "b", "queue": "orders"}, Value: 100, Time: now.Add(-time.Second)})
+
+	testCases := []struct {
+		attributes map[string]string
+		value      float64
+		series     int
+	}{
+		{map[string]string{"queue": "orders"}, 7, 2},
+		{map[string]string{"queue": "orders", "pod": "b"}, 4, 1},
+		{map[string]string{}, 17, 3},
+		{map[string]string{"queue": "unknown"}, 0, 0},
+		{map[string]string{"queue": "stale"}, 0, 0},
+	}
+	for _, testCase := range testCases {
+		value, series := store.Query("queue_depth", testCase.attributes, now.Add(-time.Minute))
+		assert.Equal(t, testCase.value, value, "attributes %v", testCase.attributes)
+		assert.Equal(t, testCase.series, series, "attributes %v", testCase.attributes)
+	}
+
+	store.Evict(now.Add(-time.Minute))
+	_, series := store.Query("queue_depth", map[string]string{"queue": "stale"}, now.Add(-2*time.Hour))
+	assert.Equal(t, 0, series)
+}
+
+func TestReceiver(t *testing.T) {
+	server := httptest.NewServer(NewReceiver("").handler())
+	defer server.Close()
+
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	_, _ = gz.Write(protobufRequest(time.Now()))
+	assert.NoError(t, gz.Close())
+
+	req, err := http.NewRequest("POST", server.URL+MetricsPath, &gzipped)
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Post(server.URL+MetricsPath, "application/json",
+		bytes.NewBufferString(`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"queue_depth","gauge":{"dataPoints":[{"attributes":[{"key":"queue","value":{"stringValue":"payments"}}],"asInt":"5"}]}}]}]}]}`))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Post(server.URL+MetricsPath, "text/plain", bytes.NewBufferString("queue_depth 5"))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	testCases := []struct {
+		query  url.Values
+		status int
+		value  float64
+		series int
+	}{
+		{url.Values{"name": {"queue_depth"}, "attribute": {"queue=orders"}}, http.StatusOK, 2.5, 1},
+		{url.Values{"name": {"queue_depth"}, "attribute": {"service.name=worker"}}, http.StatusOK, 2.5, 1},
+		{url.Values{"name": {"queue_depth"}}, http.StatusOK, 7.5, 2},
+		{url.Values{"name": {"queue_depth"}, "maxAge": {"5m"}}, http.StatusOK, 7.5, 2},
+		{url.Values{"name": {"processed"}, "attribute": {"shard=3"}}, http.StatusOK, 42, 1},
+		{url.Values{"name": {"unknown"}}, http.StatusOK, 0, 0},
+		{url.Values{}, http.StatusBadRequest, 0, 0},
+		{url.Values{"name": {"queue_depth"}, "attribute": {"queue"}}, http.StatusBadRequest, 0, 0},
+		{url.Values{"name": {"queue_depth"}, "maxAge": {"-1s"}}, http.StatusBadRequest, 0, 0},
+	}
+	for _, testCase := range testCases {
+		resp, err := http.Get(fmt.Sprintf("%s%s?%s", server.URL, QueryPath, testCase.query.Encode()))
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.status, resp.StatusCode, "query %v", testCase.query)
+		if testCase.status == http.StatusOK {
+			var response QueryResponse
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+			assert.Equal(t, QueryResponse{Value: testCase.value, Series: testCase.series}, response, "query %v", testCase.query)
+		}
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otlp
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DataPoint is a value of a metric pushed over OTLP, with the attributes of its resource and its own attributes
+type DataPoint struct {
+	Name       string
+	Attributes map[string]string
+	Value      float64
+	Time       time.Time
+}
+
+// Store keeps the latest data point of each series, a series being a metric name plus an attribute set
+type Store struct {
+	lock   sync.RWMutex
+	series map[string]DataPoint
+}
+
+// NewStore creates an empty Store
+func NewStore() *Store {
+	return &Store{series: map[string]DataPoint{}}
+}
+
+// Record stores the data point unless its series has a more recent one
+func (s *Store) Record(point DataPoint) {
+	key := seriesKey(point.Name, point.Attributes)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if latest, ok := s.series[key]; ok && latest.Time.After(point.Time) {
+		return
+	}
+	s.series[key] = point
+}
+
+// Latest returns the latest data point of the series of the metric with exactly the given attributes
+func (s *Store) Latest(name string, attributes map[string]string) (DataPoint, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	point, ok := s.series[seriesKey(name, attributes)]
+	return point, ok
+}
+
+// Query returns the sum of the latest values of the series of the metric having all the given attributes and updated
+// since notBefore, along with the number of matching series
+func (s *Store) Query(name string, attributes map[string]string, notBefore time.Time) (float64, int) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	value := 0.0
+	count := 0
+	for _, point := range s.series {
+		if point.Name != name || point.Time.Before(notBefore) || !hasAttributes(point.Attributes, attributes) {
+			continue
+		}
+		value += point.Value
+		count++
+	}
+	return value, count
+}
+
+// Evict removes the series not updated since before
+func (s *Store) Evict(before time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for key, point := range s.series {
+		if point.Time.Before(before) {
+			delete(s.series, key)
+		}
+	}
+}
+
+func hasAttributes(attributes, selector map[string]string) bool {
+	for key, value := range selector {
+		if v, ok := attributes[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+func seriesKey(name string, attributes map[string]string) string {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(name)
+	for _, key := range keys {
+		b.WriteByte(0)
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(attributes[key])
+	}
+	return b.String()
+}
@@ -27,6 +27,7 @@ import (
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
 func isFallbackEnabled(scaledObject *kedav1alpha1.ScaledObject, metricSpec v2beta2.MetricSpec) bool {
@@ -120,7 +121,9 @@ func (p *KedaProvider) updateStatus(ctx context.Context, scaledObject *kedav1alp
 	}
 
 	scaledObject.Status = *status
-	err := p.client.Status().Patch(ctx, scaledObject, patch)
+	err := kedautil.RetryWrite(ctx, func() error {
+		return p.client.Status().Patch(ctx, scaledObject, patch)
+	})
 	if err != nil {
 		logger.Error(err, "Failed to patch ScaledObjects Status")
 	}
@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+const (
+	// MetricsStoreNone doesn't store the metric values, every request queries the scalers
+	MetricsStoreNone = "none"
+	// MetricsStoreMemory stores the metric values in the memory of the replica
+	MetricsStoreMemory = "memory"
+	// MetricsStoreRedis shares the metric values between the replicas of the metrics server through Redis
+	MetricsStoreRedis = "redis"
+
+	metricsStoreKeyPrefix = "keda:metrics"
+)
+
+// MetricsStore holds the metric values read from the scalers for a ttl, a replica of the metrics server answering
+// a request for a metric stored by any replica doesn't query the scaler again
+type MetricsStore interface {
+	// Get returns the values stored for the key, false if there are none or they expired
+	Get(ctx context.Context, key string) ([]external_metrics.ExternalMetricValue, bool, error)
+	Set(ctx context.Context, key string, values []external_metrics.ExternalMetricValue) error
+	Close() error
+}
+
+// MetricsStoreConfig configures the backend of the MetricsStore
+type MetricsStoreConfig struct {
+	Backend string
+	TTL     time.Duration
+
+	// Redis backend
+	Address   string
+	Password  string
+	EnableTLS bool
+}
+
+// NewMetricsStore returns the MetricsStore of the backend, nil for the none backend
+func NewMetricsStore(config MetricsStoreConfig) (MetricsStore, error) {
+	if config.Backend == "" || config.Backend == MetricsStoreNone {
+		return nil, nil
+	}
+	if config.TTL <= 0 {
+		return nil, fmt.Errorf("the ttl of the %s metrics store must be positive", config.Backend)
+	}
+
+	switch config.Backend {
+	case MetricsStoreMemory:
+		return newMemoryMetricsStore(config.TTL), nil
+	case MetricsStoreRedis:
+		if config.Address == "" {
+			return nil, errors.New("no address given for the redis metrics store")
+		}
+		options := &redis.Options{
+			Addr:     config.Address,
+			Password: config.Password,
+		}
+		if config.EnableTLS {
+			options.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		return &redisMetricsStore{client: redis.NewClient(options), ttl: config.TTL}, nil
+	default:
+		return nil, fmt.Errorf("unknown metrics store %s, %s, %s or %s expected", config.Backend, MetricsStoreNone, MetricsStoreMemory, MetricsStoreRedis)
+	}
+}
+
+// metricsStoreKey identifies a metric of a ScaledObject for a metric selector, the generation drops the values read
+// with an outdated spec
+func metricsStoreKey(scaledObject *kedav1alpha1.ScaledObject, metricName string, metricSelector labels.Selector) string {
+	selector := ""
+	if metricSelector != nil {
+		selector = metricSelector.String()
+	}
+	return fmt.Sprintf("%s:%s:%s:%d:%s:%s", metricsStoreKeyPrefix, scaledObject.Namespace, scaledObject.Name, scaledObject.Generation, strings.ToLower(metricName), selector)
+}
+
+type storedMetricValues struct {
+	values    []external_metrics.ExternalMetricValue
+	expiresAt time.Time
+}
+
+// memoryMetricsStore keeps the metric values in a map, the expired values are dropped once per ttl
+type memoryMetricsStore struct {
+	ttl       time.Duration
+	lock      sync.Mutex
+	values    map[string]storedMetricValues
+	lastSweep time.Time
+}
+
+func newMemoryMetricsStore(ttl time.Duration) *memoryMetricsStore {
+	return &memoryMetricsStore{
+		ttl:       ttl,
+		values:    map[string]storedMetricValues{},
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *memoryMetricsStore) Get(_ context.Context, key string) ([]external_metrics.ExternalMetricValue, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stored, ok := s.values[key]
+	if !ok || !time.Now().Before(stored.expiresAt) {
+		return nil, false, nil
+	}
+	return append([]external_metrics.ExternalMetricValue{}, stored.values...), true, nil
+}
+
+func (s *memoryMetricsStore) Set(_ context.Context, key string, values []external_metrics.ExternalMetricValue) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= s.ttl {
+		for k, stored := range s.values {
+			if !now.Before(stored.expiresAt) {
+				delete(s.values, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	s.values[key] = storedMetricValues{
+		values:    append([]external_metrics.ExternalMetricValue{}, values...),
+		expiresAt: now.Add(s.ttl),
+	}
+	return nil
+}
+
+func (s *memoryMetricsStore) Close() error {
+	return nil
+}
+
+// redisMetricsStore keeps the metric values as JSON in Redis keys expiring after the ttl
+type redisMetricsStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func (s *redisMetricsStore) Get(ctx context.Context, key string) ([]external_metrics.ExternalMetricValue, bool, error) {
+	data, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var values []external_metrics.ExternalMetricValue
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, false, fmt.Errorf("error parsing the stored metric values: %s", err)
+	}
+	return values, true, nil
+}
+
+func (s *redisMetricsStore) Set(ctx context.Context, key string, values []external_metrics.ExternalMetricValue) error {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, key, data, s.ttl).Err()
+}
+
+func (s *redisMetricsStore) Close() error {
+	return s.client.Close()
+}
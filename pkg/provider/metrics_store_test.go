@@ -0,0 +1,231 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	mock_scalers "github.com/kedacore/keda/v2/pkg/mock/mock_scaler"
+	"github.com/kedacore/keda/v2/pkg/scaling/cache"
+)
+
+func testMetricValues(value int64) []external_metrics.ExternalMetricValue {
+	return []external_metrics.ExternalMetricValue{{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(value, resource.DecimalSI),
+		Timestamp:  metav1.Now(),
+	}}
+}
+
+func TestNewMetricsStore(t *testing.T) {
+	tests := []struct {
+		config  MetricsStoreConfig
+		isNil   bool
+		isError bool
+	}{
+		{MetricsStoreConfig{}, true, false},
+		{MetricsStoreConfig{Backend: MetricsStoreNone}, true, false},
+		{MetricsStoreConfig{Backend: MetricsStoreMemory, TTL: time.Second}, false, false},
+		{MetricsStoreConfig{Backend: MetricsStoreRedis, TTL: time.Second, Address: "redis:6379"}, false, false},
+		{MetricsStoreConfig{Backend: MetricsStoreMemory}, true, true},
+		{MetricsStoreConfig{Backend: MetricsStoreRedis, TTL: time.Second}, true, true},
+		{MetricsStoreConfig{Backend: "etcd", TTL: time.Second}, true, true},
+	}
+
+	for _, test := range tests {
+		store, err := NewMetricsStore(test.config)
+		if test.isError {
+			assert.Error(t, err, "config %+v", test.config)
+		} else {
+			assert.NoError(t, err, "config %+v", test.config)
+		}
+		assert.Equal(t, test.isNil, store == nil, "config %+v", test.config)
+		if store != nil {
+			assert.NoError(t, store.Close())
+		}
+	}
+}
+
+func TestMetricsStoreKey(t *testing.T) {
+	so := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "orders", Generation: 3}}
+	assert.Equal(t, "keda:metrics:default:orders:3:s0-rabbitmq-orders:", metricsStoreKey(so, "s0-RabbitMQ-orders", nil))
+	assert.Equal(t, "keda:metrics:default:orders:3:s0-rabbitmq-orders:", metricsStoreKey(so, "s0-RabbitMQ-orders", labels.Everything()))
+
+	so.Generation = 4
+	assert.Equal(t, "keda:metrics:default:orders:4:s0-rabbitmq-orders:", metricsStoreKey(so, "s0-rabbitmq-orders", nil))
+
+	selector := labels.SelectorFromSet(labels.Set{"scaledobject.keda.sh/name": "orders"})
+	assert.Equal(t, "keda:metrics:default:orders:4:s0-rabbitmq-orders:scaledobject.keda.sh/name=orders", metricsStoreKey(so, "s0-rabbitmq-orders", selector))
+}
+
+func TestMemoryMetricsStore(t *testing.T) {
+	store := newMemoryMetricsStore(50 * time.Millisecond)
+	ctx := context.Background()
+
+	_, found, err := store.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, store.Set(ctx, "a", testMetricValues(5)))
+	values, found, err := store.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	value, _ := values[0].Value.AsInt64()
+	assert.Equal(t, int64(5), value)
+
+	time.Sleep(60 * time.Millisecond)
+	_, found, err = store.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	// the expired values are dropped by the next write
+	assert.NoError(t, store.Set(ctx, "b", testMetricValues(1)))
+	assert.Len(t, store.values, 1)
+}
+
+// startRedisTestServer answers the GET and SET commands of the redis protocol from a map, ignoring the expiration
+func startRedisTestServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	var lock sync.Mutex
+	data := map[string]string{}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					args, err := readRedisTestCommand(reader)
+					if err != nil {
+						return
+					}
+					lock.Lock()
+					switch strings.ToUpper(args[0]) {
+					case "GET":
+						if value, ok := data[args[1]]; ok {
+							fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+						} else {
+							fmt.Fprint(conn, "$-1\r\n")
+						}
+					case "SET":
+						data[args[1]] = args[2]
+						fmt.Fprint(conn, "+OK\r\n")
+					default:
+						fmt.Fprint(conn, "-ERR unknown command\r\n")
+					}
+					lock.Unlock()
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func readRedisTestCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, strings.TrimSuffix(arg, "\r\n"))
+	}
+	return args, nil
+}
+
+func TestRedisMetricsStore(t *testing.T) {
+	store := &redisMetricsStore{client: redis.NewClient(&redis.Options{Addr: startRedisTestServer(t)}), ttl: time.Minute}
+	defer store.Close()
+	ctx := context.Background()
+
+	_, found, err := store.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, store.Set(ctx, "a", testMetricValues(7)))
+	values, found, err := store.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, metricName, values[0].MetricName)
+	value, _ := values[0].Value.AsInt64()
+	assert.Equal(t, int64(7), value)
+}
+
+func TestGetMetricsForScalerUsesMetricsStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger = logr.Discard()
+
+	scaler := mock_scalers.NewMockScaler(ctrl)
+	scaler.EXPECT().GetMetrics(gomock.Any(), gomock.Eq(metricName), gomock.Any()).Return(testMetricValues(5), nil).Times(1)
+	scalersCache := &cache.ScalersCache{Scalers: []cache.ScalerBuilder{{Scaler: scaler}}}
+	so := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "orders", Generation: 1}}
+
+	// the replicas share the store, only the first request queries the scaler
+	store := newMemoryMetricsStore(time.Minute)
+	for i := 0; i < 2; i++ {
+		p := &KedaProvider{metricsStore: store}
+		metrics, err := p.getMetricsForScaler(context.Background(), scalersCache, 0, so, metricName, nil)
+		assert.NoError(t, err)
+		value, _ := metrics[0].Value.AsInt64()
+		assert.Equal(t, int64(5), value)
+	}
+
+	// a new generation of the ScaledObject queries the scaler again
+	so.Generation = 2
+	scaler.EXPECT().GetMetrics(gomock.Any(), gomock.Eq(metricName), gomock.Any()).Return(testMetricValues(6), nil).Times(1)
+	metrics, err := (&KedaProvider{metricsStore: store}).getMetricsForScaler(context.Background(), scalersCache, 0, so, metricName, nil)
+	assert.NoError(t, err)
+	value, _ := metrics[0].Value.AsInt64()
+	assert.Equal(t, int64(6), value)
+}
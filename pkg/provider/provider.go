@@ -34,6 +34,7 @@ import (
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	prommetrics "github.com/kedacore/keda/v2/pkg/metrics"
 	"github.com/kedacore/keda/v2/pkg/scaling"
+	"github.com/kedacore/keda/v2/pkg/scaling/cache"
 )
 
 // KedaProvider implements External Metrics Provider
@@ -44,6 +45,8 @@ type KedaProvider struct {
 	ctx                     context.Context
 	externalMetricsInfo     *[]provider.ExternalMetricInfo
 	externalMetricsInfoLock *sync.RWMutex
+	// metricsStore holds the metric values read by the replicas of the metrics server, nil if they aren't stored
+	metricsStore MetricsStore
 }
 
 var (
@@ -52,7 +55,7 @@ var (
 )
 
 // NewProvider returns an instance of KedaProvider
-func NewProvider(ctx context.Context, adapterLogger logr.Logger, scaleHandler scaling.ScaleHandler, client client.Client, watchedNamespace string, externalMetricsInfo *[]provider.ExternalMetricInfo, externalMetricsInfoLock *sync.RWMutex) provider.MetricsProvider {
+func NewProvider(ctx context.Context, adapterLogger logr.Logger, scaleHandler scaling.ScaleHandler, client client.Client, watchedNamespace string, externalMetricsInfo *[]provider.ExternalMetricInfo, externalMetricsInfoLock *sync.RWMutex, metricsStore MetricsStore) provider.MetricsProvider {
 	provider := &KedaProvider{
 		client:                  client,
 		scaleHandler:            scaleHandler,
@@ -60,6 +63,7 @@ func NewProvider(ctx context.Context, adapterLogger logr.Logger, scaleHandler sc
 		ctx:                     ctx,
 		externalMetricsInfo:     externalMetricsInfo,
 		externalMetricsInfoLock: externalMetricsInfoLock,
+		metricsStore:            metricsStore,
 	}
 	logger = adapterLogger.WithName("provider")
 	logger.Info("starting")
@@ -105,8 +109,12 @@ func (p *KedaProvider) GetExternalMetric(ctx context.Context, namespace string,
 
 	scalerError := false
 
-	for scalerIndex, scaler := range cache.GetScalers() {
-		metricSpecs := scaler.GetMetricSpecForScaling(ctx)
+	for scalerIndex, scalerBuilder := range cache.Scalers {
+		scaler := scalerBuilder.Scaler
+		metricSpecs, err := cache.GetMetricSpecForScalingForScaler(ctx, scalerIndex)
+		if err != nil {
+			return nil, err
+		}
 		scalerName := strings.Replace(fmt.Sprintf("%T", scaler), "*scalers.", "", 1)
 
 		for _, metricSpec := range metricSpecs {
@@ -116,7 +124,7 @@ func (p *KedaProvider) GetExternalMetric(ctx context.Context, namespace string,
 			}
 			// Filter only the desired metric
 			if strings.EqualFold(metricSpec.External.Metric.Name, info.Metric) {
-				metrics, err := cache.GetMetricsForScaler(ctx, scalerIndex, info.Metric, metricSelector)
+				metrics, err := p.getMetricsForScaler(ctx, cache, scalerIndex, scaledObject, info.Metric, metricSelector)
 				metrics, err = p.getMetricsWithFallback(ctx, metrics, err, info.Metric, scaledObject, metricSpec)
 
 				if err != nil {
@@ -125,11 +133,11 @@ func (p *KedaProvider) GetExternalMetric(ctx context.Context, namespace string,
 				} else {
 					for _, metric := range metrics {
 						metricValue, _ := metric.Value.AsInt64()
-						metricsServer.RecordHPAScalerMetric(namespace, scaledObject.Name, scalerName, scalerIndex, metric.MetricName, metricValue)
+						metricsServer.RecordHPAScalerMetric(namespace, scaledObject.Name, scalerName, scalerIndex, scalerBuilder.TriggerName, metric.MetricName, metricValue)
 					}
 					matchingMetrics = append(matchingMetrics, metrics...)
 				}
-				metricsServer.RecordHPAScalerError(namespace, scaledObject.Name, scalerName, scalerIndex, info.Metric, err)
+				metricsServer.RecordHPAScalerError(namespace, scaledObject.Name, scalerName, scalerIndex, scalerBuilder.TriggerName, info.Metric, err)
 			}
 		}
 	}
@@ -153,6 +161,32 @@ func (p *KedaProvider) GetExternalMetric(ctx context.Context, namespace string,
 	}, nil
 }
 
+// getMetricsForScaler returns the metric values stored by any replica if there are some, otherwise they are read
+// from the scaler and stored for the other requests
+func (p *KedaProvider) getMetricsForScaler(ctx context.Context, scalersCache *cache.ScalersCache, scalerIndex int, scaledObject *kedav1alpha1.ScaledObject, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	if p.metricsStore == nil {
+		return scalersCache.GetMetricsForScaler(ctx, scalerIndex, metricName, metricSelector)
+	}
+
+	key := metricsStoreKey(scaledObject, metricName, metricSelector)
+	metrics, found, err := p.metricsStore.Get(ctx, key)
+	if err != nil {
+		// the scaler is queried when the store isn't available
+		logger.Error(err, "error reading the metrics store", "key", key)
+	} else if found {
+		return metrics, nil
+	}
+
+	metrics, err = scalersCache.GetMetricsForScaler(ctx, scalerIndex, metricName, metricSelector)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.metricsStore.Set(ctx, key, metrics); err != nil {
+		logger.Error(err, "error writing the metrics store", "key", key)
+	}
+	return metrics, nil
+}
+
 // ListAllExternalMetrics returns the supported external metrics for this provider
 func (p *KedaProvider) ListAllExternalMetrics() []provider.ExternalMetricInfo {
 	logger.V(1).Info("KEDA Metrics Server received request for list of all provided external metrics names")
@@ -0,0 +1,243 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queryapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/kedacore/keda/v2/pkg/scaling"
+)
+
+const (
+	// scaledObjectPathPrefix is followed by <namespace>/scaledobjects/<name>
+	scaledObjectPathPrefix = "/api/v1/namespaces/"
+
+	shutdownTimeout = 10 * time.Second
+
+	// DefaultTokenAudience is the audience the bearer tokens of the callers are issued for by default,
+	// e.g. with kubectl create token --audience keda-query-api
+	DefaultTokenAudience = "keda-query-api"
+)
+
+// Server serves the current trigger values and desired replicas of the ScaledObjects,
+// the callers authenticate over TLS with a Kubernetes bearer token issued for the audience of the API
+// and allowed to get the ScaledObject
+type Server struct {
+	address      string
+	certFile     string
+	keyFile      string
+	client       client.Client
+	scaleHandler scaling.ScaleHandler
+	reviewer     accessReviewer
+	logger       logr.Logger
+}
+
+// accessReviewer checks the bearer token of a request is allowed to get a ScaledObject
+type accessReviewer interface {
+	review(ctx context.Context, token string, namespace string, name string) (authenticated bool, allowed bool, err error)
+}
+
+// NewServer creates the query API Server serving TLS on address, the bearer tokens must be issued for audience.
+// The certificate and the key are required as the callers send their bearer tokens.
+func NewServer(address, certFile, keyFile, audience string, client client.Client, scaleHandler scaling.ScaleHandler) (*Server, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("the query API requires a TLS certificate and key file")
+	}
+	if audience == "" {
+		return nil, errors.New("the query API requires a token audience")
+	}
+	return &Server{
+		address:      address,
+		certFile:     certFile,
+		keyFile:      keyFile,
+		client:       client,
+		scaleHandler: scaleHandler,
+		reviewer:     &kubeAccessReviewer{client: client, audience: audience},
+		logger:       logf.Log.WithName("queryapi"),
+	}, nil
+}
+
+// Start serves the API until the context is done, it implements manager.Runnable
+func (s *Server) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:    s.address,
+		Handler: s.handler(),
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		s.logger.Info("Starting query API server", "address", s.address)
+		err := srv.ListenAndServeTLS(s.certFile, s.keyFile)
+		if !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+		close(errs)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// NeedLeaderElection returns false, the API is served by all the replicas of the operator
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(scaledObjectPathPrefix, s.handleScaledObject)
+	return mux
+}
+
+// handleScaledObject serves GET /api/v1/namespaces/<namespace>/scaledobjects/<name>
+func (s *Server) handleScaledObject(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "only GET is supported")
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, scaledObjectPathPrefix), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] != "scaledobjects" || parts[2] == "" {
+		writeError(w, http.StatusNotFound, "path must be /api/v1/namespaces/<namespace>/scaledobjects/<name>")
+		return
+	}
+	namespace, name := parts[0], parts[2]
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || token == r.Header.Get("Authorization") {
+		writeError(w, http.StatusUnauthorized, "a bearer token is required")
+		return
+	}
+	authenticated, allowed, err := s.reviewer.review(r.Context(), token, namespace, name)
+	switch {
+	case err != nil:
+		s.logger.Error(err, "error reviewing the access to the ScaledObject", "scaledObject.Namespace", namespace, "scaledObject.Name", name)
+		writeError(w, http.StatusInternalServerError, "error reviewing the access to the ScaledObject")
+		return
+	case !authenticated:
+		writeError(w, http.StatusUnauthorized, "invalid bearer token")
+		return
+	case !allowed:
+		writeError(w, http.StatusForbidden, fmt.Sprintf("not allowed to get ScaledObject %s/%s", namespace, name))
+		return
+	}
+
+	scaledObject := &kedav1alpha1.ScaledObject{}
+	if err := s.client.Get(r.Context(), types.NamespacedName{Namespace: namespace, Name: name}, scaledObject); err != nil {
+		if apierrors.IsNotFound(err) {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("ScaledObject %s/%s not found", namespace, name))
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	status, err := getScaledObjectStatus(r.Context(), s.scaleHandler, scaledObject)
+	if err != nil {
+		s.logger.Error(err, "error getting the scalers of the ScaledObject", "scaledObject.Namespace", namespace, "scaledObject.Name", name)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"error": message})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// kubeAccessReviewer authenticates the tokens issued for audience with TokenReviews and authorizes them
+// with SubjectAccessReviews
+type kubeAccessReviewer struct {
+	client   client.Client
+	audience string
+}
+
+func (k *kubeAccessReviewer) review(ctx context.Context, token string, namespace string, name string) (bool, bool, error) {
+	tokenReview := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: []string{k.audience},
+		},
+	}
+	if err := k.client.Create(ctx, tokenReview); err != nil {
+		return false, false, err
+	}
+	if !tokenReview.Status.Authenticated || !hasAudience(tokenReview.Status.Audiences, k.audience) {
+		return false, false, nil
+	}
+
+	user := tokenReview.Status.User
+	extra := map[string]authorizationv1.ExtraValue{}
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	accessReview := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Group:     kedav1alpha1.GroupVersion.Group,
+				Resource:  "scaledobjects",
+				Name:      name,
+			},
+		},
+	}
+	if err := k.client.Create(ctx, accessReview); err != nil {
+		return true, false, err
+	}
+	return true, accessReview.Status.Allowed, nil
+}
+
+// hasAudience returns whether the audiences of an authenticated token contain audience
+func hasAudience(audiences []string, audience string) bool {
+	for _, a := range audiences {
+		if a == audience {
+			return true
+		}
+	}
+	return false
+}
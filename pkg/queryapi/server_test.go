@@ -0,0 +1,211 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queryapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	mock_scalers "github.com/kedacore/keda/v2/pkg/mock/mock_scaler"
+	"github.com/kedacore/keda/v2/pkg/mock/mock_scaling"
+	"github.com/kedacore/keda/v2/pkg/scalers"
+	"github.com/kedacore/keda/v2/pkg/scaling/cache"
+)
+
+type fakeAccessReviewer struct {
+	tokens map[string]bool
+}
+
+func (f *fakeAccessReviewer) review(_ context.Context, token string, _ string, _ string) (bool, bool, error) {
+	allowed, authenticated := f.tokens[token]
+	return authenticated, allowed, nil
+}
+
+func newTestServer(t *testing.T, ctrl *gomock.Controller, scaledObject *kedav1alpha1.ScaledObject) (*Server, *mock_scaling.MockScaleHandler) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, kedav1alpha1.AddToScheme(scheme))
+
+	scaleHandler := mock_scaling.NewMockScaleHandler(ctrl)
+	return &Server{
+		client:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(scaledObject).Build(),
+		scaleHandler: scaleHandler,
+		reviewer:     &fakeAccessReviewer{tokens: map[string]bool{"allowed": true, "denied": false}},
+		logger:       logr.Discard(),
+	}, scaleHandler
+}
+
+func newTestScaler(ctrl *gomock.Controller, metricName string, value int64, target int64, isActive bool) *mock_scalers.MockScaler {
+	scaler := mock_scalers.NewMockScaler(ctrl)
+	targetQuantity := resource.NewQuantity(target, resource.DecimalSI)
+	scaler.EXPECT().IsActive(gomock.Any()).Return(isActive, nil).AnyTimes()
+	scaler.EXPECT().GetMetricSpecForScaling(gomock.Any()).Return([]v2beta2.MetricSpec{{
+		Type: v2beta2.ExternalMetricSourceType,
+		External: &v2beta2.ExternalMetricSource{
+			Metric: v2beta2.MetricIdentifier{Name: metricName},
+			Target: v2beta2.MetricTarget{Type: v2beta2.AverageValueMetricType, AverageValue: targetQuantity},
+		},
+	}}).AnyTimes()
+	scaler.EXPECT().GetMetrics(gomock.Any(), metricName, gomock.Any()).Return([]external_metrics.ExternalMetricValue{{
+		MetricName: metricName,
+		Value:      *resource.NewQuantity(value, resource.DecimalSI),
+	}}, nil).AnyTimes()
+	return scaler
+}
+
+func getStatus(t *testing.T, s *Server, path string, token string) (int, *ScaledObjectStatus) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		return w.Code, nil
+	}
+	status := &ScaledObjectStatus{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), status))
+	return w.Code, status
+}
+
+func TestQueryScaledObjectStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	maxReplicaCount := int32(8)
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Name: "so", Namespace: "default"},
+		Spec: kedav1alpha1.ScaledObjectSpec{
+			ScaleTargetRef:  &kedav1alpha1.ScaleTarget{Name: "deployment"},
+			MaxReplicaCount: &maxReplicaCount,
+			Triggers:        []kedav1alpha1.ScaleTriggers{{Type: "rabbitmq"}, {Type: "kafka", Name: "lag"}},
+		},
+	}
+	s, scaleHandler := newTestServer(t, ctrl, scaledObject)
+
+	failingScaler := mock_scalers.NewMockScaler(ctrl)
+	failingScaler.EXPECT().IsActive(gomock.Any()).Return(false, errors.New("connection refused")).Times(2)
+	failingScaler.EXPECT().Close(gomock.Any())
+	scalersCache := &cache.ScalersCache{
+		Scalers: []cache.ScalerBuilder{
+			{Scaler: newTestScaler(ctrl, "s0-queue", 25, 5, true), ScalerIndex: 0},
+			{Scaler: failingScaler, ScalerIndex: 1, TriggerName: "lag", Factory: func() (scalers.Scaler, error) {
+				return failingScaler, nil
+			}},
+		},
+		Logger:   logr.Discard(),
+		Recorder: record.NewFakeRecorder(10),
+	}
+	scaleHandler.EXPECT().GetScalersCache(gomock.Any(), gomock.Any()).Return(scalersCache, nil).Times(2)
+
+	// the triggers are only reported once polled by the scale loop
+	code, status := getStatus(t, s, "/api/v1/namespaces/default/scaledobjects/so", "allowed")
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, status.IsActive)
+	assert.Len(t, status.Triggers, 2)
+	assert.Nil(t, status.Triggers[0].PolledAt)
+	assert.Nil(t, status.Triggers[0].Metrics)
+
+	cache.SetRecordPollMetrics(true)
+	defer cache.SetRecordPollMetrics(false)
+	scalersCache.IsScaledObjectActive(context.Background(), scaledObject)
+
+	code, status = getStatus(t, s, "/api/v1/namespaces/default/scaledobjects/so", "allowed")
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, status.IsActive)
+	assert.NotNil(t, status.Triggers[0].PolledAt)
+	assert.Equal(t, int32(5), status.DesiredReplicas)
+	assert.Equal(t, int32(8), status.MaxReplicaCount)
+	assert.Len(t, status.Triggers, 2)
+	assert.Equal(t, "rabbitmq", status.Triggers[0].Type)
+	assert.Equal(t, []MetricStatus{{MetricName: "s0-queue", Value: 25, Target: 5, TargetType: "AverageValue"}}, status.Triggers[0].Metrics)
+	assert.Equal(t, "kafka", status.Triggers[1].Type)
+	assert.Equal(t, "lag", status.Triggers[1].Name)
+	assert.Equal(t, "connection refused", status.Triggers[1].Error)
+}
+
+func TestQueryScaledObjectStatusAccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scaledObject := &kedav1alpha1.ScaledObject{ObjectMeta: metav1.ObjectMeta{Name: "so", Namespace: "default"}}
+	s, _ := newTestServer(t, ctrl, scaledObject)
+
+	code, _ := getStatus(t, s, "/api/v1/namespaces/default/scaledobjects/so", "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = getStatus(t, s, "/api/v1/namespaces/default/scaledobjects/so", "unknown")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = getStatus(t, s, "/api/v1/namespaces/default/scaledobjects/so", "denied")
+	assert.Equal(t, http.StatusForbidden, code)
+	code, _ = getStatus(t, s, "/api/v1/namespaces/default/scaledobjects/other", "allowed")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = getStatus(t, s, "/api/v1/namespaces/default/so", "allowed")
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestNewServerRequiresTLS(t *testing.T) {
+	_, err := NewServer(":8443", "", "", DefaultTokenAudience, nil, nil)
+	assert.Error(t, err)
+	_, err = NewServer(":8443", "tls.crt", "tls.key", "", nil, nil)
+	assert.Error(t, err)
+	s, err := NewServer(":8443", "tls.crt", "tls.key", DefaultTokenAudience, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultTokenAudience, s.reviewer.(*kubeAccessReviewer).audience)
+}
+
+func TestHasAudience(t *testing.T) {
+	assert.True(t, hasAudience([]string{"https://kubernetes.default.svc", DefaultTokenAudience}, DefaultTokenAudience))
+	assert.False(t, hasAudience([]string{"https://kubernetes.default.svc"}, DefaultTokenAudience))
+	assert.False(t, hasAudience(nil, DefaultTokenAudience))
+}
+
+func TestGetDesiredReplicas(t *testing.T) {
+	minReplicaCount := int32(2)
+	idleReplicaCount := int32(0)
+	scaledObject := &kedav1alpha1.ScaledObject{}
+	status := &ScaledObjectStatus{MinReplicaCount: 0, MaxReplicaCount: 10}
+
+	assert.Equal(t, int32(0), getDesiredReplicas(scaledObject, status, nil))
+	status.IsActive = true
+	assert.Equal(t, int32(1), getDesiredReplicas(scaledObject, status, []float64{0.5}))
+	assert.Equal(t, int32(4), getDesiredReplicas(scaledObject, status, []float64{3.2, 1}))
+	assert.Equal(t, int32(10), getDesiredReplicas(scaledObject, status, []float64{1e12}))
+
+	scaledObject.Spec.MinReplicaCount = &minReplicaCount
+	scaledObject.Spec.IdleReplicaCount = &idleReplicaCount
+	status.MinReplicaCount = minReplicaCount
+	assert.Equal(t, int32(2), getDesiredReplicas(scaledObject, status, []float64{0.5}))
+	status.IsActive = false
+	assert.Equal(t, int32(0), getDesiredReplicas(scaledObject, status, nil))
+}
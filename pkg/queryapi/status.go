@@ -0,0 +1,181 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queryapi
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"k8s.io/api/autoscaling/v2beta2"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	kedacontrollerutil "github.com/kedacore/keda/v2/controllers/keda/util"
+	"github.com/kedacore/keda/v2/pkg/scaling"
+)
+
+const (
+	defaultMinReplicaCount int32 = 0
+	defaultMaxReplicaCount int32 = 100
+)
+
+// ScaledObjectStatus is the current state of the triggers of a ScaledObject
+type ScaledObjectStatus struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	IsActive  bool   `json:"isActive"`
+	Paused    bool   `json:"paused"`
+	// DesiredReplicas is estimated from the metrics with an AverageValue target,
+	// the HPA may compute a different count for the other metrics
+	DesiredReplicas int32           `json:"desiredReplicas"`
+	MinReplicaCount int32           `json:"minReplicaCount"`
+	MaxReplicaCount int32           `json:"maxReplicaCount"`
+	Triggers        []TriggerStatus `json:"triggers"`
+}
+
+// TriggerStatus is the state of a trigger of a ScaledObject at the last poll of its scale loop
+type TriggerStatus struct {
+	Index int    `json:"index"`
+	Type  string `json:"type"`
+	Name  string `json:"name,omitempty"`
+	// PolledAt is when the scale loop polled the trigger, it's empty if it wasn't polled yet
+	PolledAt *time.Time     `json:"polledAt,omitempty"`
+	IsActive bool           `json:"isActive"`
+	Error    string         `json:"error,omitempty"`
+	Metrics  []MetricStatus `json:"metrics,omitempty"`
+}
+
+// MetricStatus is the current value of an external metric of a trigger, named as exposed to the HPA
+type MetricStatus struct {
+	MetricName string  `json:"metricName"`
+	Value      float64 `json:"value"`
+	Target     float64 `json:"target"`
+	TargetType string  `json:"targetType"`
+}
+
+// getScaledObjectStatus returns the results of the last poll of the scalers of the ScaledObject by its scale loop,
+// the scalers aren't queried. The metric values are only recorded by the scale loops with cache.SetRecordPollMetrics.
+func getScaledObjectStatus(ctx context.Context, scaleHandler scaling.ScaleHandler, scaledObject *kedav1alpha1.ScaledObject) (*ScaledObjectStatus, error) {
+	scalersCache, err := scaleHandler.GetScalersCache(ctx, scaledObject)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &ScaledObjectStatus{
+		Namespace:       scaledObject.Namespace,
+		Name:            scaledObject.Name,
+		MinReplicaCount: defaultMinReplicaCount,
+		MaxReplicaCount: defaultMaxReplicaCount,
+		Triggers:        []TriggerStatus{},
+	}
+	if scaledObject.Spec.MinReplicaCount != nil {
+		status.MinReplicaCount = *scaledObject.Spec.MinReplicaCount
+	}
+	if scaledObject.Spec.MaxReplicaCount != nil {
+		status.MaxReplicaCount = *scaledObject.Spec.MaxReplicaCount
+	}
+
+	var replicaRatios []float64
+	for i, sb := range scalersCache.Scalers {
+		trigger := TriggerStatus{Index: i, Name: sb.TriggerName}
+		if sb.ScalerIndex < len(scaledObject.Spec.Triggers) {
+			trigger.Type = scaledObject.Spec.Triggers[sb.ScalerIndex].Type
+		}
+
+		pollResult, ok := scalersCache.GetPollResult(i)
+		if !ok {
+			status.Triggers = append(status.Triggers, trigger)
+			continue
+		}
+		polledAt := pollResult.PolledAt
+		trigger.PolledAt = &polledAt
+		trigger.IsActive = pollResult.IsActive
+		status.IsActive = status.IsActive || pollResult.IsActive
+		if pollResult.Error != nil {
+			trigger.Error = pollResult.Error.Error()
+		}
+
+		if len(pollResult.Metrics) == 0 {
+			status.Triggers = append(status.Triggers, trigger)
+			continue
+		}
+		metricSpecs, err := scalersCache.GetMetricSpecForScalingForScaler(ctx, i)
+		if err != nil {
+			return nil, err
+		}
+		for _, metricSpec := range metricSpecs {
+			// cpu/memory resource metrics are read by the HPA from the metrics server
+			if metricSpec.External == nil {
+				continue
+			}
+			for _, metric := range pollResult.Metrics[metricSpec.External.Metric.Name] {
+				metricStatus := MetricStatus{
+					MetricName: metric.MetricName,
+					Value:      metric.Value.AsApproximateFloat64(),
+					TargetType: string(metricSpec.External.Target.Type),
+				}
+				switch {
+				case metricSpec.External.Target.AverageValue != nil:
+					metricStatus.Target = metricSpec.External.Target.AverageValue.AsApproximateFloat64()
+				case metricSpec.External.Target.Value != nil:
+					metricStatus.Target = metricSpec.External.Target.Value.AsApproximateFloat64()
+				}
+				if metricSpec.External.Target.Type == v2beta2.AverageValueMetricType && metricStatus.Target > 0 {
+					replicaRatios = append(replicaRatios, metricStatus.Value/metricStatus.Target)
+				}
+				trigger.Metrics = append(trigger.Metrics, metricStatus)
+			}
+		}
+		status.Triggers = append(status.Triggers, trigger)
+	}
+
+	if val, ok := scaledObject.GetAnnotations()[kedacontrollerutil.PausedReplicasAnnotation]; ok {
+		pausedReplicaCount, err := strconv.ParseInt(val, 10, 32)
+		if err == nil {
+			status.Paused = true
+			status.DesiredReplicas = int32(pausedReplicaCount)
+			return status, nil
+		}
+	}
+	status.DesiredReplicas = getDesiredReplicas(scaledObject, status, replicaRatios)
+	return status, nil
+}
+
+// getDesiredReplicas estimates the replicas like the scale loop and the HPA: the idle or min replica count while
+// the ScaledObject isn't active, otherwise the largest metric to target ratio within the min and max replica counts
+func getDesiredReplicas(scaledObject *kedav1alpha1.ScaledObject, status *ScaledObjectStatus, replicaRatios []float64) int32 {
+	if !status.IsActive {
+		if scaledObject.Spec.IdleReplicaCount != nil {
+			return *scaledObject.Spec.IdleReplicaCount
+		}
+		return status.MinReplicaCount
+	}
+
+	minReplicas := status.MinReplicaCount
+	if minReplicas < 1 {
+		minReplicas = 1
+	}
+	desired := float64(minReplicas)
+	for _, ratio := range replicaRatios {
+		desired = math.Max(desired, math.Ceil(ratio))
+	}
+	if desired > float64(status.MaxReplicaCount) {
+		return status.MaxReplicaCount
+	}
+	return int32(desired)
+}
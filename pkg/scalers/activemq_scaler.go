@@ -56,14 +56,14 @@ var activeMQLog = logf.Log.WithName("activeMQ_scaler")
 func NewActiveMQScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseActiveMQMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing ActiveMQ metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing ActiveMQ metadata: %s", err))
 	}
-	httpClient := kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, false)
+	httpClient := createHTTPClient(config, config.GlobalHTTPTimeout, false)
 
 	return &activeMQScaler{
 		metricType: metricType,
@@ -0,0 +1,348 @@
+package scalers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	airflowModeAPI      = "api"
+	airflowModeDatabase = "database"
+
+	airflowDriverPostgreSQL = "postgresql"
+	airflowDriverMySQL      = "mysql"
+
+	airflowDefaultStates = "queued"
+	// airflowQueueLengthDefault matches the default worker_concurrency of the Celery executor
+	airflowQueueLengthDefault = 16
+)
+
+// airflowTaskStates are the states of the task instances which can be waiting for a worker
+var airflowTaskStates = map[string]bool{
+	"queued":            true,
+	"scheduled":         true,
+	"up_for_retry":      true,
+	"up_for_reschedule": true,
+	"deferred":          true,
+	"running":           true,
+}
+
+type airflowScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *airflowMetadata
+	httpClient *http.Client
+	connection *sql.DB
+}
+
+type airflowMetadata struct {
+	mode        string
+	states      []string
+	pool        string
+	queue       string
+	queueLength float64
+	scalerIndex int
+
+	// api mode
+	url         string
+	username    string
+	password    string
+	bearerToken string
+	unsafeSsl   bool
+
+	// database mode
+	driver     string
+	connection string
+}
+
+// airflowTaskInstanceCollection holds the fields of the task instances API used by the scaler
+type airflowTaskInstanceCollection struct {
+	TotalEntries int64 `json:"total_entries"`
+}
+
+var airflowLog = logf.Log.WithName("airflow_scaler")
+
+// NewAirflowScaler creates a new airflowScaler
+func NewAirflowScaler(ctx context.Context, config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseAirflowMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing airflow metadata: %s", err))
+	}
+
+	if meta.mode == airflowModeAPI {
+		return &airflowScaler{
+			metricType: metricType,
+			metadata:   meta,
+			httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, meta.unsafeSsl),
+		}, nil
+	}
+
+	driverName := "postgres"
+	if meta.driver == airflowDriverMySQL {
+		driverName = "mysql"
+	}
+	db, err := sql.Open(driverName, meta.connection)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s connection: %s", meta.driver, err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error establishing %s connection: %s", meta.driver, err)
+	}
+
+	return &airflowScaler{
+		metricType: metricType,
+		metadata:   meta,
+		connection: db,
+	}, nil
+}
+
+func parseAirflowMetadata(config *ScalerConfig) (*airflowMetadata, error) {
+	meta := airflowMetadata{}
+
+	meta.mode = airflowModeAPI
+	if val, ok := config.TriggerMetadata["mode"]; ok && val != "" {
+		meta.mode = val
+	}
+
+	switch meta.mode {
+	case airflowModeAPI:
+		switch {
+		case config.AuthParams["url"] != "":
+			meta.url = config.AuthParams["url"]
+		case config.TriggerMetadata["url"] != "":
+			meta.url = config.TriggerMetadata["url"]
+		case config.TriggerMetadata["urlFromEnv"] != "":
+			meta.url = config.ResolvedEnv[config.TriggerMetadata["urlFromEnv"]]
+		default:
+			return nil, errors.New("no url given")
+		}
+		meta.url = strings.TrimSuffix(meta.url, "/")
+
+		meta.username = config.AuthParams["username"]
+		meta.password = config.AuthParams["password"]
+		meta.bearerToken = config.AuthParams["bearerToken"]
+		if meta.username != "" && meta.password == "" {
+			return nil, errors.New("no password given")
+		}
+
+		if val, ok := config.TriggerMetadata["unsafeSsl"]; ok {
+			unsafeSsl, err := strconv.ParseBool(val)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing unsafeSsl: %s", err)
+			}
+			meta.unsafeSsl = unsafeSsl
+		}
+	case airflowModeDatabase:
+		meta.driver = config.TriggerMetadata["driver"]
+		if meta.driver != airflowDriverPostgreSQL && meta.driver != airflowDriverMySQL {
+			return nil, fmt.Errorf("driver must be either %s or %s, got %q", airflowDriverPostgreSQL, airflowDriverMySQL, meta.driver)
+		}
+
+		switch {
+		case config.AuthParams["connection"] != "":
+			meta.connection = config.AuthParams["connection"]
+		case config.TriggerMetadata["connectionFromEnv"] != "":
+			meta.connection = config.ResolvedEnv[config.TriggerMetadata["connectionFromEnv"]]
+		default:
+			return nil, errors.New("no connection given")
+		}
+	default:
+		return nil, fmt.Errorf("mode must be either %s or %s, got %s", airflowModeAPI, airflowModeDatabase, meta.mode)
+	}
+
+	states := airflowDefaultStates
+	if val, ok := config.TriggerMetadata["states"]; ok && val != "" {
+		states = val
+	}
+	for _, state := range splitAndTrim(states) {
+		if !airflowTaskStates[state] {
+			return nil, fmt.Errorf("unsupported task state %s", state)
+		}
+		meta.states = append(meta.states, state)
+	}
+
+	meta.pool = config.TriggerMetadata["pool"]
+	meta.queue = config.TriggerMetadata["queue"]
+
+	meta.queueLength = airflowQueueLengthDefault
+	if val, ok := config.TriggerMetadata["queueLength"]; ok {
+		queueLength, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("queueLength parsing error %s", err.Error())
+		}
+		if queueLength <= 0 {
+			return nil, errors.New("queueLength must be greater than 0")
+		}
+		meta.queueLength = queueLength
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// Close disposes of the database connection
+func (s *airflowScaler) Close(context.Context) error {
+	if s.connection == nil {
+		return nil
+	}
+	if err := s.connection.Close(); err != nil {
+		airflowLog.Error(err, "error closing database connection")
+		return err
+	}
+	return nil
+}
+
+// IsActive returns true if there are task instances in the states
+func (s *airflowScaler) IsActive(ctx context.Context) (bool, error) {
+	count, err := s.getTaskCount(ctx)
+	if err != nil {
+		airflowLog.Error(err, "error counting airflow task instances")
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *airflowScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	metricName := "airflow"
+	if s.metadata.pool != "" {
+		metricName = fmt.Sprintf("%s-%s", metricName, s.metadata.pool)
+	}
+	if s.metadata.queue != "" {
+		metricName = fmt.Sprintf("%s-%s", metricName, s.metadata.queue)
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(metricName)),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.queueLength),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of task instances in the states
+func (s *airflowScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	count, err := s.getTaskCount(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error counting airflow task instances: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(count))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+func (s *airflowScaler) getTaskCount(ctx context.Context) (int64, error) {
+	if s.metadata.mode == airflowModeDatabase {
+		return s.getTaskCountFromDatabase(ctx)
+	}
+	return s.getTaskCountFromAPI(ctx)
+}
+
+// getTaskCountFromAPI reads the total of the task instances of all the DAG runs in the states,
+// only one instance is requested as the total is computed by Airflow
+func (s *airflowScaler) getTaskCountFromAPI(ctx context.Context) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", getAirflowTaskInstancesURL(s.metadata), nil)
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Accept", "application/json")
+	switch {
+	case s.metadata.bearerToken != "":
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", s.metadata.bearerToken))
+	case s.metadata.username != "":
+		req.SetBasicAuth(s.metadata.username, s.metadata.password)
+	}
+
+	r, err := s.httpClient.Do(req)
+	if err != nil {
+		return -1, err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(r.Body)
+		return -1, fmt.Errorf("error requesting airflow API status: %s, response: %s", r.Status, body)
+	}
+
+	var collection airflowTaskInstanceCollection
+	if err := json.NewDecoder(r.Body).Decode(&collection); err != nil {
+		return -1, err
+	}
+	return collection.TotalEntries, nil
+}
+
+func getAirflowTaskInstancesURL(meta *airflowMetadata) string {
+	query := url.Values{}
+	for _, state := range meta.states {
+		query.Add("state", state)
+	}
+	if meta.pool != "" {
+		query.Set("pool", meta.pool)
+	}
+	if meta.queue != "" {
+		query.Set("queue", meta.queue)
+	}
+	query.Set("limit", "1")
+	// ~ stands for all the DAGs and all their runs
+	return fmt.Sprintf("%s/api/v1/dags/~/dagRuns/~/taskInstances?%s", meta.url, query.Encode())
+}
+
+func (s *airflowScaler) getTaskCountFromDatabase(ctx context.Context) (int64, error) {
+	query, args := buildAirflowTaskCountQuery(s.metadata)
+
+	var count int64
+	if err := s.connection.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return -1, err
+	}
+	return count, nil
+}
+
+// buildAirflowTaskCountQuery counts the rows of the task_instance table of the metadata database,
+// every value is bound as a parameter
+func buildAirflowTaskCountQuery(meta *airflowMetadata) (string, []interface{}) {
+	var args []interface{}
+	placeholder := func(value string) string {
+		args = append(args, value)
+		if meta.driver == airflowDriverMySQL {
+			return "?"
+		}
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	states := make([]string, 0, len(meta.states))
+	for _, state := range meta.states {
+		states = append(states, placeholder(state))
+	}
+	query := fmt.Sprintf("SELECT COUNT(*) FROM task_instance WHERE state IN (%s)", strings.Join(states, ", "))
+	if meta.pool != "" {
+		query += fmt.Sprintf(" AND pool = %s", placeholder(meta.pool))
+	}
+	if meta.queue != "" {
+		query += fmt.Sprintf(" AND queue = %s", placeholder(meta.queue))
+	}
+	return query, args
+}
@@ -60,16 +60,16 @@ func NewArtemisQueueScaler(config *ScalerConfig) (Scaler, error) {
 	// do we need to guarantee this timeout for a specific
 	// reason? if not, we can have buildScaler pass in
 	// the global client
-	httpClient := kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, false)
+	httpClient := createHTTPClient(config, config.GlobalHTTPTimeout, false)
 
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	artemisMetadata, err := parseArtemisMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing artemis metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing artemis metadata: %s", err))
 	}
 
 	return &artemisScaler{
@@ -0,0 +1,229 @@
+package scalers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	asynqDefaultQueue         = "default"
+	asynqQueueLengthDefault   = 5
+	asynqPendingListSuffix    = "pending"
+	asynqScheduledSetSuffix   = "scheduled"
+	asynqRetrySetSuffix       = "retry"
+	asynqKeyPrefix            = "asynq"
+	asynqQueueKeyFormatString = "%s:{%s}:%s"
+)
+
+type asynqScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *asynqMetadata
+	client     redis.UniversalClient
+}
+
+type asynqMetadata struct {
+	queues           []string
+	queueLength      float64
+	includeScheduled bool
+	includeRetry     bool
+	databaseIndex    int
+	connectionInfo   redisConnectionInfo
+	scalerIndex      int
+}
+
+var asynqLog = logf.Log.WithName("asynq_scaler")
+
+// NewAsynqScaler creates a new asynqScaler
+func NewAsynqScaler(ctx context.Context, isClustered, isSentinel bool, config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	switch {
+	case isClustered:
+		meta, err := parseAsynqMetadata(config, parseRedisClusterAddress)
+		if err != nil {
+			return nil, NewPermanentError(fmt.Errorf("error parsing asynq metadata: %s", err))
+		}
+		client, err := getRedisClusterClient(ctx, meta.connectionInfo)
+		if err != nil {
+			return nil, fmt.Errorf("connection to redis cluster failed: %s", err)
+		}
+		return &asynqScaler{metricType: metricType, metadata: meta, client: client}, nil
+	case isSentinel:
+		meta, err := parseAsynqMetadata(config, parseRedisSentinelAddress)
+		if err != nil {
+			return nil, NewPermanentError(fmt.Errorf("error parsing asynq metadata: %s", err))
+		}
+		client, err := getRedisSentinelClient(ctx, meta.connectionInfo, meta.databaseIndex)
+		if err != nil {
+			return nil, fmt.Errorf("connection to redis sentinel failed: %s", err)
+		}
+		return &asynqScaler{metricType: metricType, metadata: meta, client: client}, nil
+	}
+
+	meta, err := parseAsynqMetadata(config, parseRedisAddress)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing asynq metadata: %s", err))
+	}
+	client, err := getRedisClient(ctx, meta.connectionInfo, meta.databaseIndex)
+	if err != nil {
+		return nil, fmt.Errorf("connection to redis failed: %s", err)
+	}
+	return &asynqScaler{metricType: metricType, metadata: meta, client: client}, nil
+}
+
+func parseAsynqMetadata(config *ScalerConfig, parserFn redisAddressParser) (*asynqMetadata, error) {
+	connInfo, err := parserFn(config.TriggerMetadata, config.ResolvedEnv, config.AuthParams)
+	if err != nil {
+		return nil, err
+	}
+	meta := asynqMetadata{
+		connectionInfo: connInfo,
+	}
+
+	meta.queues = []string{asynqDefaultQueue}
+	if val, ok := config.TriggerMetadata["queues"]; ok && val != "" {
+		meta.queues = splitAndTrim(val)
+	}
+
+	meta.queueLength = asynqQueueLengthDefault
+	if val, ok := config.TriggerMetadata["queueLength"]; ok {
+		queueLength, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("queueLength parsing error %s", err.Error())
+		}
+		if queueLength <= 0 {
+			return nil, errors.New("queueLength must be greater than 0")
+		}
+		meta.queueLength = queueLength
+	}
+
+	meta.includeScheduled = true
+	if val, ok := config.TriggerMetadata["includeScheduled"]; ok {
+		includeScheduled, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("includeScheduled parsing error %s", err.Error())
+		}
+		meta.includeScheduled = includeScheduled
+	}
+
+	meta.includeRetry = true
+	if val, ok := config.TriggerMetadata["includeRetry"]; ok {
+		includeRetry, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("includeRetry parsing error %s", err.Error())
+		}
+		meta.includeRetry = includeRetry
+	}
+
+	meta.databaseIndex = defaultDBIdx
+	if val, ok := config.TriggerMetadata["databaseIndex"]; ok {
+		dbIndex, err := strconv.ParseInt(val, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("databaseIndex: parsing error %s", err.Error())
+		}
+		meta.databaseIndex = int(dbIndex)
+	}
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// IsActive checks if there are tasks waiting in any of the queues
+func (s *asynqScaler) IsActive(ctx context.Context) (bool, error) {
+	tasks, err := s.getTaskCount(ctx)
+	if err != nil {
+		asynqLog.Error(err, "error inspecting asynq queues")
+		return false, err
+	}
+
+	return tasks > 0, nil
+}
+
+func (s *asynqScaler) Close(context.Context) error {
+	if err := s.client.Close(); err != nil {
+		asynqLog.Error(err, "error closing redis client")
+		return err
+	}
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *asynqScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	metricName := kedautil.NormalizeString(fmt.Sprintf("asynq-%s", strings.Join(s.metadata.queues, "-")))
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, metricName),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.queueLength),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of tasks waiting in the queues
+func (s *asynqScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	tasks, err := s.getTaskCount(ctx)
+	if err != nil {
+		asynqLog.Error(err, "error inspecting asynq queues")
+		return []external_metrics.ExternalMetricValue{}, err
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(tasks))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// getTaskCount sums the pending tasks of the queues, plus their scheduled and retry tasks if requested
+func (s *asynqScaler) getTaskCount(ctx context.Context) (int64, error) {
+	var tasks int64
+	for _, queue := range s.metadata.queues {
+		list, sets := getAsynqQueueKeys(s.metadata, queue)
+
+		length, err := s.client.LLen(ctx, list).Result()
+		if err != nil {
+			return -1, err
+		}
+		tasks += length
+
+		for _, set := range sets {
+			length, err := s.client.ZCard(ctx, set).Result()
+			if err != nil {
+				return -1, err
+			}
+			tasks += length
+		}
+	}
+	return tasks, nil
+}
+
+// getAsynqQueueKeys returns the pending list and the sorted sets of the queue holding the tasks to count,
+// asynq wraps the queue name in a hash tag so all the keys of a queue live in the same redis cluster slot
+func getAsynqQueueKeys(meta *asynqMetadata, queue string) (string, []string) {
+	key := func(suffix string) string {
+		return fmt.Sprintf(asynqQueueKeyFormatString, asynqKeyPrefix, queue, suffix)
+	}
+
+	var sets []string
+	if meta.includeScheduled {
+		sets = append(sets, key(asynqScheduledSetSuffix))
+	}
+	if meta.includeRetry {
+		sets = append(sets, key(asynqRetrySetSuffix))
+	}
+	return key(asynqPendingListSuffix), sets
+}
@@ -0,0 +1,98 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseAsynqMetadataTestData struct {
+	metadata   map[string]string
+	isError    bool
+	authParams map[string]string
+}
+
+type asynqMetricIdentifier struct {
+	metadataTestData *parseAsynqMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testAsynqMetadata = []parseAsynqMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true, map[string]string{}},
+	// only address, default queue
+	{map[string]string{"address": "redis:6379"}, false, map[string]string{}},
+	// all options
+	{map[string]string{"address": "redis:6379", "queues": "critical, low", "queueLength": "10", "includeScheduled": "false", "includeRetry": "false", "databaseIndex": "1"}, false, map[string]string{}},
+	// address from authParams
+	{map[string]string{"queues": "default"}, false, map[string]string{"address": "redis:6379", "password": "secret"}},
+	// improperly formed queueLength
+	{map[string]string{"address": "redis:6379", "queueLength": "AA"}, true, map[string]string{}},
+	// zero queueLength
+	{map[string]string{"address": "redis:6379", "queueLength": "0"}, true, map[string]string{}},
+	// improperly formed includeScheduled
+	{map[string]string{"address": "redis:6379", "includeScheduled": "yes"}, true, map[string]string{}},
+	// improperly formed includeRetry
+	{map[string]string{"address": "redis:6379", "includeRetry": "yes"}, true, map[string]string{}},
+	// improperly formed databaseIndex
+	{map[string]string{"address": "redis:6379", "databaseIndex": "AA"}, true, map[string]string{}},
+}
+
+var asynqMetricIdentifiers = []asynqMetricIdentifier{
+	{&testAsynqMetadata[1], 0, "s0-asynq-default"},
+	{&testAsynqMetadata[2], 1, "s1-asynq-critical-low"},
+}
+
+func TestAsynqParseMetadata(t *testing.T) {
+	for idx, testData := range testAsynqMetadata {
+		_, err := parseAsynqMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams}, parseRedisAddress)
+		if err != nil && !testData.isError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+	}
+}
+
+func TestAsynqParseClusterAndSentinelMetadata(t *testing.T) {
+	meta, err := parseAsynqMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"addresses": "redis-0:6379, redis-1:6379"}}, parseRedisClusterAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"redis-0:6379", "redis-1:6379"}, meta.connectionInfo.addresses)
+
+	meta, err = parseAsynqMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"addresses": "sentinel:26379", "sentinelMaster": "mymaster"}}, parseRedisSentinelAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, "mymaster", meta.connectionInfo.sentinelMaster)
+}
+
+func TestAsynqGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range asynqMetricIdentifiers {
+		meta, err := parseAsynqMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex}, parseRedisAddress)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockAsynqScaler := asynqScaler{"", meta, nil}
+
+		metricSpec := mockAsynqScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestGetAsynqQueueKeys(t *testing.T) {
+	meta, err := parseAsynqMetadata(&ScalerConfig{TriggerMetadata: testAsynqMetadata[1].metadata}, parseRedisAddress)
+	assert.NoError(t, err)
+	list, sets := getAsynqQueueKeys(meta, "default")
+	assert.Equal(t, "asynq:{default}:pending", list)
+	assert.Equal(t, []string{"asynq:{default}:scheduled", "asynq:{default}:retry"}, sets)
+
+	meta, err = parseAsynqMetadata(&ScalerConfig{TriggerMetadata: testAsynqMetadata[2].metadata}, parseRedisAddress)
+	assert.NoError(t, err)
+	list, sets = getAsynqQueueKeys(meta, "critical")
+	assert.Equal(t, "asynq:{critical}:pending", list)
+	assert.Empty(t, sets)
+}
@@ -0,0 +1,83 @@
+package authentication
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+)
+
+const (
+	// SpiffeEndpointSocketEnv is the standard env var pointing to the SPIFFE Workload API socket,
+	// e.g. unix:///run/spire/sockets/agent.sock
+	SpiffeEndpointSocketEnv = "SPIFFE_ENDPOINT_SOCKET"
+	// SpiffeServerIDKey is the trigger metadata key with the SPIFFE ID expected from the backend
+	SpiffeServerIDKey = "spiffeServerID"
+)
+
+var (
+	spiffeSource     *workloadapi.X509Source
+	spiffeSourceLock sync.Mutex
+)
+
+// getSpiffeX509Source returns the X509Source shared by all scalers. The source keeps a single
+// streaming connection to the Workload API and rotates the SVID in the background, so it is
+// created once and lives as long as the operator does.
+func getSpiffeX509Source(ctx context.Context) (*workloadapi.X509Source, error) {
+	spiffeSourceLock.Lock()
+	defer spiffeSourceLock.Unlock()
+
+	if spiffeSource != nil {
+		return spiffeSource, nil
+	}
+
+	var clientOptions []workloadapi.ClientOption
+	if addr := os.Getenv(SpiffeEndpointSocketEnv); addr != "" {
+		clientOptions = append(clientOptions, workloadapi.WithAddr(addr))
+	}
+
+	source, err := workloadapi.NewX509Source(ctx, workloadapi.WithClientOptions(clientOptions...))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching X509-SVID from the SPIFFE Workload API: %s", err)
+	}
+	spiffeSource = source
+	return spiffeSource, nil
+}
+
+// NewSpiffeTLSConfig returns a mTLS client config presenting the operator's X509-SVID.
+// If serverID is set, only a backend presenting exactly that SPIFFE ID is accepted,
+// otherwise any backend from the operator's own trust domain is accepted.
+func NewSpiffeTLSConfig(ctx context.Context, serverID string) (*tls.Config, error) {
+	source, err := getSpiffeX509Source(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	authorizer, err := getSpiffeAuthorizer(source, serverID)
+	if err != nil {
+		return nil, err
+	}
+
+	return tlsconfig.MTLSClientConfig(source, source, authorizer), nil
+}
+
+func getSpiffeAuthorizer(source *workloadapi.X509Source, serverID string) (tlsconfig.Authorizer, error) {
+	if serverID != "" {
+		id, err := spiffeid.FromString(serverID)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", SpiffeServerIDKey, err)
+		}
+		return tlsconfig.AuthorizeID(id), nil
+	}
+
+	svid, err := source.GetX509SVID()
+	if err != nil {
+		return nil, err
+	}
+	return tlsconfig.AuthorizeMemberOf(svid.ID.TrustDomain()), nil
+}
@@ -0,0 +1,349 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	amazonMQEngineActiveMQ = "activemq"
+	amazonMQEngineRabbitMQ = "rabbitmq"
+
+	amazonMQSourceCloudwatch    = "cloudwatch"
+	amazonMQSourceBrokerConsole = "brokerConsole"
+
+	amazonMQCloudwatchNamespace = "AWS/AmazonMQ"
+	// Amazon MQ publishes the queue metrics every minute, the last datapoint of the window is used
+	amazonMQMetricStatPeriod     = 60
+	amazonMQMetricCollectionTime = 300
+
+	defaultAmazonMQTargetQueueSize = 5
+	defaultAmazonMQVirtualHost     = "/"
+)
+
+var amazonMQLog = logf.Log.WithName("aws_amazonmq_scaler")
+
+type awsAmazonMQScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *awsAmazonMQMetadata
+	// cloudwatch reads the queue metric of the broker, when the source is cloudwatch
+	cloudwatch *awsCloudwatchScaler
+	httpClient *http.Client
+}
+
+type awsAmazonMQMetadata struct {
+	brokerName  string
+	engine      string
+	queueName   string
+	virtualHost string
+	source      string
+
+	targetQueueSize           float64
+	activationTargetQueueSize float64
+
+	// consoleURL is the endpoint of the ActiveMQ web console or of the RabbitMQ management API of the broker
+	consoleURL string
+	username   string
+	password   string
+	unsafeSsl  bool
+
+	awsRegion        string
+	awsEndpoint      string
+	awsAuthorization awsAuthorizationMetadata
+
+	scalerIndex int
+}
+
+// amazonMQActiveMQQueueSize is the response of the Jolokia read of the QueueSize attribute of a queue
+type amazonMQActiveMQQueueSize struct {
+	Value  float64 `json:"value"`
+	Status int     `json:"status"`
+}
+
+// amazonMQRabbitMQQueue is the response of the RabbitMQ management API for a queue
+type amazonMQRabbitMQQueue struct {
+	Messages float64 `json:"messages"`
+}
+
+// NewAwsAmazonMQScaler creates a new awsAmazonMQScaler, scaling on the queue size of an Amazon MQ broker read from
+// CloudWatch or from the broker web console
+func NewAwsAmazonMQScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseAwsAmazonMQMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing Amazon MQ metadata: %s", err))
+	}
+
+	scaler := &awsAmazonMQScaler{
+		metricType: metricType,
+		metadata:   meta,
+	}
+	if meta.source == amazonMQSourceCloudwatch {
+		cloudwatchMeta := amazonMQCloudwatchMetadata(meta)
+		scaler.cloudwatch = &awsCloudwatchScaler{
+			metricType: metricType,
+			metadata:   cloudwatchMeta,
+			cwClient:   createCloudwatchClient(cloudwatchMeta),
+		}
+	} else {
+		scaler.httpClient = createHTTPClient(config, config.GlobalHTTPTimeout, meta.unsafeSsl)
+	}
+	return scaler, nil
+}
+
+func parseAwsAmazonMQMetadata(config *ScalerConfig) (*awsAmazonMQMetadata, error) {
+	meta := awsAmazonMQMetadata{
+		engine:          amazonMQEngineActiveMQ,
+		source:          amazonMQSourceCloudwatch,
+		virtualHost:     defaultAmazonMQVirtualHost,
+		targetQueueSize: defaultAmazonMQTargetQueueSize,
+	}
+
+	if val, ok := config.TriggerMetadata["brokerName"]; ok && val != "" {
+		meta.brokerName = val
+	} else {
+		return nil, errors.New("no brokerName given")
+	}
+
+	if val, ok := config.TriggerMetadata["queueName"]; ok && val != "" {
+		meta.queueName = val
+	} else {
+		return nil, errors.New("no queueName given")
+	}
+
+	if val, ok := config.TriggerMetadata["engine"]; ok && val != "" {
+		switch val {
+		case amazonMQEngineActiveMQ, amazonMQEngineRabbitMQ:
+			meta.engine = val
+		default:
+			return nil, fmt.Errorf("err incorrect value for engine is given: %s", val)
+		}
+	}
+
+	if val, ok := config.TriggerMetadata["virtualHost"]; ok && val != "" {
+		if meta.engine != amazonMQEngineRabbitMQ {
+			return nil, errors.New("virtualHost is only supported by the rabbitmq engine")
+		}
+		meta.virtualHost = val
+	}
+
+	if val, ok := config.TriggerMetadata["targetQueueSize"]; ok && val != "" {
+		targetQueueSize, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetQueueSize parsing error %s", err.Error())
+		}
+		meta.targetQueueSize = targetQueueSize
+	}
+
+	if val, ok := config.TriggerMetadata["activationTargetQueueSize"]; ok && val != "" {
+		activationTargetQueueSize, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationTargetQueueSize parsing error %s", err.Error())
+		}
+		meta.activationTargetQueueSize = activationTargetQueueSize
+	}
+
+	if val, ok := config.TriggerMetadata["source"]; ok && val != "" {
+		switch val {
+		case amazonMQSourceCloudwatch, amazonMQSourceBrokerConsole:
+			meta.source = val
+		default:
+			return nil, fmt.Errorf("err incorrect value for source is given: %s", val)
+		}
+	}
+
+	if meta.source == amazonMQSourceBrokerConsole {
+		if err := parseAmazonMQBrokerConsole(config, &meta); err != nil {
+			return nil, err
+		}
+	} else {
+		if val, ok := config.TriggerMetadata["awsRegion"]; ok && val != "" {
+			meta.awsRegion = val
+		} else {
+			return nil, errors.New("no awsRegion given")
+		}
+
+		meta.awsEndpoint = config.TriggerMetadata["awsEndpoint"]
+
+		auth, err := getAwsAuthorization(config.AuthParams, config.TriggerMetadata, config.ResolvedEnv)
+		if err != nil {
+			return nil, err
+		}
+		meta.awsAuthorization = auth
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// parseAmazonMQBrokerConsole parses the endpoint and the credentials of the broker web console
+func parseAmazonMQBrokerConsole(config *ScalerConfig, meta *awsAmazonMQMetadata) error {
+	consoleURL, err := GetFromAuthOrMeta(config, "consoleURL")
+	if err != nil {
+		return err
+	}
+	if _, err := url.ParseRequestURI(consoleURL); err != nil {
+		return fmt.Errorf("consoleURL parsing error %s", err.Error())
+	}
+	meta.consoleURL = strings.TrimSuffix(consoleURL, "/")
+
+	meta.username = config.AuthParams["username"]
+	if meta.username == "" {
+		return errors.New("no username given")
+	}
+	meta.password = config.AuthParams["password"]
+	if meta.password == "" {
+		return errors.New("no password given")
+	}
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok && val != "" {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("unsafeSsl parsing error %s", err.Error())
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+	return nil
+}
+
+// amazonMQCloudwatchMetadata returns the CloudWatch query of the queue metric, QueueSize for ActiveMQ and
+// MessageCount for RabbitMQ. With an active/standby ActiveMQ deployment, brokerName is the name of the instance
+// like MyBroker-1.
+func amazonMQCloudwatchMetadata(meta *awsAmazonMQMetadata) *awsCloudwatchMetadata {
+	cloudwatchMeta := &awsCloudwatchMetadata{
+		namespace:            amazonMQCloudwatchNamespace,
+		metricStat:           "Maximum",
+		metricStatPeriod:     amazonMQMetricStatPeriod,
+		metricCollectionTime: amazonMQMetricCollectionTime,
+		targetMetricValue:    meta.targetQueueSize,
+		awsRegion:            meta.awsRegion,
+		awsEndpoint:          meta.awsEndpoint,
+		awsAuthorization:     meta.awsAuthorization,
+		scalerIndex:          meta.scalerIndex,
+	}
+	if meta.engine == amazonMQEngineRabbitMQ {
+		cloudwatchMeta.metricsName = "MessageCount"
+		cloudwatchMeta.dimensionName = []string{"Broker", "VirtualHost", "Queue"}
+		cloudwatchMeta.dimensionValue = []string{meta.brokerName, meta.virtualHost, meta.queueName}
+	} else {
+		cloudwatchMeta.metricsName = "QueueSize"
+		cloudwatchMeta.dimensionName = []string{"Broker", "Queue"}
+		cloudwatchMeta.dimensionValue = []string{meta.brokerName, meta.queueName}
+	}
+	return cloudwatchMeta
+}
+
+// getQueueSize returns the number of messages of the queue
+func (s *awsAmazonMQScaler) getQueueSize(ctx context.Context) (float64, error) {
+	if s.metadata.source == amazonMQSourceCloudwatch {
+		return s.cloudwatch.GetCloudwatchMetrics()
+	}
+	if s.metadata.engine == amazonMQEngineRabbitMQ {
+		return s.getRabbitMQQueueSize(ctx)
+	}
+	return s.getActiveMQQueueSize(ctx)
+}
+
+func (s *awsAmazonMQScaler) getActiveMQQueueSize(ctx context.Context) (float64, error) {
+	consoleURL := fmt.Sprintf("%s/api/jolokia/read/org.apache.activemq:type=Broker,brokerName=%s,destinationType=Queue,destinationName=%s/QueueSize",
+		s.metadata.consoleURL, url.PathEscape(s.metadata.brokerName), url.PathEscape(s.metadata.queueName))
+
+	var queueSize amazonMQActiveMQQueueSize
+	// Jolokia rejects the requests without an Origin
+	if err := s.getBrokerConsole(ctx, consoleURL, map[string]string{"Origin": s.metadata.consoleURL}, &queueSize); err != nil {
+		return 0, err
+	}
+	if queueSize.Status != http.StatusOK {
+		return 0, fmt.Errorf("ActiveMQ web console response error code: %d", queueSize.Status)
+	}
+	return queueSize.Value, nil
+}
+
+func (s *awsAmazonMQScaler) getRabbitMQQueueSize(ctx context.Context) (float64, error) {
+	consoleURL := fmt.Sprintf("%s/api/queues/%s/%s", s.metadata.consoleURL, url.PathEscape(s.metadata.virtualHost), url.PathEscape(s.metadata.queueName))
+
+	var queue amazonMQRabbitMQQueue
+	if err := s.getBrokerConsole(ctx, consoleURL, nil, &queue); err != nil {
+		return 0, err
+	}
+	return queue.Messages, nil
+}
+
+func (s *awsAmazonMQScaler) getBrokerConsole(ctx context.Context, consoleURL string, headers map[string]string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, consoleURL, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.metadata.username, s.metadata.password)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("broker console response error code: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// IsActive returns true if the queue size is over activationTargetQueueSize
+func (s *awsAmazonMQScaler) IsActive(ctx context.Context) (bool, error) {
+	queueSize, err := s.getQueueSize(ctx)
+	if err != nil {
+		amazonMQLog.Error(err, "error getting Amazon MQ queue size")
+		return false, err
+	}
+
+	return queueSize > s.metadata.activationTargetQueueSize, nil
+}
+
+func (s *awsAmazonMQScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *awsAmazonMQScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("aws-amazonmq-%s-%s", s.metadata.brokerName, s.metadata.queueName))),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetQueueSize),
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the queue size of the broker
+func (s *awsAmazonMQScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	queueSize, err := s.getQueueSize(ctx)
+	if err != nil {
+		amazonMQLog.Error(err, "Error getting queue size")
+		return []external_metrics.ExternalMetricValue{}, err
+	}
+
+	metric := GenerateMetricInMili(metricName, queueSize)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,186 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"github.com/stretchr/testify/assert"
+)
+
+var testAWSAmazonMQAuthentication = map[string]string{
+	"awsAccessKeyID":     "none",
+	"awsSecretAccessKey": "none",
+}
+
+type parseAWSAmazonMQMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+	comment    string
+}
+
+type awsAmazonMQMetricIdentifier struct {
+	metadataTestData *parseAWSAmazonMQMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testAWSAmazonMQMetadata = []parseAWSAmazonMQMetadataTestData{
+	{map[string]string{"brokerName": "orders-broker-1", "queueName": "orders", "awsRegion": "eu-west-1"}, testAWSAmazonMQAuthentication, false, "properly formed cloudwatch activemq"},
+	{map[string]string{"brokerName": "orders-broker", "engine": "rabbitmq", "queueName": "orders", "virtualHost": "shop", "targetQueueSize": "10", "activationTargetQueueSize": "2", "awsRegion": "eu-west-1", "identityOwner": "operator"}, map[string]string{}, false, "properly formed cloudwatch rabbitmq"},
+	{map[string]string{"brokerName": "orders-broker", "queueName": "orders", "source": "brokerConsole", "consoleURL": "https://b-1234.mq.eu-west-1.amazonaws.com:8162", "unsafeSsl": "true"}, map[string]string{"username": "keda", "password": "s3cret"}, false, "properly formed broker console"},
+	{map[string]string{"queueName": "orders", "awsRegion": "eu-west-1"}, testAWSAmazonMQAuthentication, true, "missing brokerName"},
+	{map[string]string{"brokerName": "orders-broker", "awsRegion": "eu-west-1"}, testAWSAmazonMQAuthentication, true, "missing queueName"},
+	{map[string]string{"brokerName": "orders-broker", "queueName": "orders"}, testAWSAmazonMQAuthentication, true, "missing awsRegion"},
+	{map[string]string{"brokerName": "orders-broker", "queueName": "orders", "awsRegion": "eu-west-1"}, map[string]string{}, true, "missing credentials"},
+	{map[string]string{"brokerName": "orders-broker", "queueName": "orders", "engine": "kafka", "awsRegion": "eu-west-1"}, testAWSAmazonMQAuthentication, true, "invalid engine"},
+	{map[string]string{"brokerName": "orders-broker", "queueName": "orders", "source": "jmx", "awsRegion": "eu-west-1"}, testAWSAmazonMQAuthentication, true, "invalid source"},
+	{map[string]string{"brokerName": "orders-broker", "queueName": "orders", "virtualHost": "shop", "awsRegion": "eu-west-1"}, testAWSAmazonMQAuthentication, true, "virtualHost with activemq"},
+	{map[string]string{"brokerName": "orders-broker", "queueName": "orders", "targetQueueSize": "a", "awsRegion": "eu-west-1"}, testAWSAmazonMQAuthentication, true, "invalid targetQueueSize"},
+	{map[string]string{"brokerName": "orders-broker", "queueName": "orders", "activationTargetQueueSize": "a", "awsRegion": "eu-west-1"}, testAWSAmazonMQAuthentication, true, "invalid activationTargetQueueSize"},
+	{map[string]string{"brokerName": "orders-broker", "queueName": "orders", "source": "brokerConsole"}, map[string]string{"username": "keda", "password": "s3cret"}, true, "missing consoleURL"},
+	{map[string]string{"brokerName": "orders-broker", "queueName": "orders", "source": "brokerConsole", "consoleURL": "b-1234"}, map[string]string{"username": "keda", "password": "s3cret"}, true, "invalid consoleURL"},
+	{map[string]string{"brokerName": "orders-broker", "queueName": "orders", "source": "brokerConsole", "consoleURL": "https://b-1234.mq.eu-west-1.amazonaws.com:8162"}, map[string]string{"username": "keda"}, true, "missing password"},
+	{map[string]string{"brokerName": "orders-broker", "queueName": "orders", "source": "brokerConsole", "consoleURL": "https://b-1234.mq.eu-west-1.amazonaws.com:8162", "unsafeSsl": "a"}, map[string]string{"username": "keda", "password": "s3cret"}, true, "invalid unsafeSsl"},
+}
+
+var awsAmazonMQMetricIdentifiers = []awsAmazonMQMetricIdentifier{
+	{&testAWSAmazonMQMetadata[0], 0, "s0-aws-amazonmq-orders-broker-1-orders"},
+	{&testAWSAmazonMQMetadata[1], 1, "s1-aws-amazonmq-orders-broker-orders"},
+}
+
+// mockAmazonMQCloudwatch records the query of the queue metric
+type mockAmazonMQCloudwatch struct {
+	cloudwatchiface.CloudWatchAPI
+	input *cloudwatch.GetMetricDataInput
+}
+
+func (m *mockAmazonMQCloudwatch) GetMetricData(input *cloudwatch.GetMetricDataInput) (*cloudwatch.GetMetricDataOutput, error) {
+	m.input = input
+	return &cloudwatch.GetMetricDataOutput{
+		MetricDataResults: []*cloudwatch.MetricDataResult{
+			{
+				Values: []*float64{aws.Float64(42)},
+			},
+		},
+	}, nil
+}
+
+func TestAWSAmazonMQParseMetadata(t *testing.T) {
+	for _, testData := range testAWSAmazonMQMetadata {
+		_, err := parseAwsAmazonMQMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Errorf("%s: Expected success but got error %s", testData.comment, err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("%s: Expected error but got success", testData.comment)
+		}
+	}
+}
+
+func TestAWSAmazonMQGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range awsAmazonMQMetricIdentifiers {
+		meta, err := parseAwsAmazonMQMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockAWSAmazonMQScaler := awsAmazonMQScaler{metadata: meta}
+
+		metricSpec := mockAWSAmazonMQScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestAWSAmazonMQGetCloudwatchQueueSize(t *testing.T) {
+	testCases := []struct {
+		metadataTestData *parseAWSAmazonMQMetadataTestData
+		metricName       string
+		dimensions       map[string]string
+	}{
+		{&testAWSAmazonMQMetadata[0], "QueueSize", map[string]string{"Broker": "orders-broker-1", "Queue": "orders"}},
+		{&testAWSAmazonMQMetadata[1], "MessageCount", map[string]string{"Broker": "orders-broker", "VirtualHost": "shop", "Queue": "orders"}},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parseAwsAmazonMQMetadata(&ScalerConfig{TriggerMetadata: testCase.metadataTestData.metadata, AuthParams: testCase.metadataTestData.authParams})
+		assert.NoError(t, err)
+		mock := &mockAmazonMQCloudwatch{}
+		s := awsAmazonMQScaler{metadata: meta, cloudwatch: &awsCloudwatchScaler{metadata: amazonMQCloudwatchMetadata(meta), cwClient: mock}}
+
+		value, err := s.getQueueSize(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, float64(42), value)
+
+		metric := mock.input.MetricDataQueries[0].MetricStat.Metric
+		assert.Equal(t, "AWS/AmazonMQ", *metric.Namespace)
+		assert.Equal(t, testCase.metricName, *metric.MetricName)
+		dimensions := map[string]string{}
+		for _, dimension := range metric.Dimensions {
+			dimensions[*dimension.Name] = *dimension.Value
+		}
+		assert.Equal(t, testCase.dimensions, dimensions)
+	}
+}
+
+func TestAWSAmazonMQGetBrokerConsoleQueueSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "keda" || password != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.EscapedPath() {
+		case "/api/jolokia/read/org.apache.activemq:type=Broker,brokerName=orders-broker,destinationType=Queue,destinationName=orders/QueueSize":
+			if r.Header.Get("Origin") == "" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			fmt.Fprint(w, `{"request":{"mbean":"org.apache.activemq:brokerName=orders-broker,destinationName=orders,destinationType=Queue,type=Broker","attribute":"QueueSize","type":"read"},"value":12,"timestamp":1654077600,"status":200}`)
+		case "/api/jolokia/read/org.apache.activemq:type=Broker,brokerName=orders-broker,destinationType=Queue,destinationName=missing/QueueSize":
+			fmt.Fprint(w, `{"error_type":"javax.management.InstanceNotFoundException","status":404}`)
+		case "/api/queues/%2F/orders":
+			fmt.Fprint(w, `{"name":"orders","vhost":"/","messages":7,"messages_ready":5,"messages_unacknowledged":2}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		metadata map[string]string
+		password string
+		value    float64
+		isError  bool
+	}{
+		{map[string]string{"queueName": "orders"}, "s3cret", 12, false},
+		{map[string]string{"queueName": "missing"}, "s3cret", 0, true},
+		{map[string]string{"queueName": "orders", "engine": "rabbitmq"}, "s3cret", 7, false},
+		{map[string]string{"queueName": "missing", "engine": "rabbitmq"}, "s3cret", 0, true},
+		{map[string]string{"queueName": "orders"}, "wrong", 0, true},
+	}
+
+	for _, testCase := range testCases {
+		testCase.metadata["brokerName"] = "orders-broker"
+		testCase.metadata["source"] = "brokerConsole"
+		testCase.metadata["consoleURL"] = server.URL
+		meta, err := parseAwsAmazonMQMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, AuthParams: map[string]string{"username": "keda", "password": testCase.password}})
+		assert.NoError(t, err)
+		s := awsAmazonMQScaler{metadata: meta, httpClient: http.DefaultClient}
+
+		value, err := s.getQueueSize(context.Background())
+		if testCase.isError {
+			assert.Error(t, err, "metadata %v", testCase.metadata)
+			continue
+		}
+		assert.NoError(t, err, "metadata %v", testCase.metadata)
+		assert.Equal(t, testCase.value, value, "metadata %v", testCase.metadata)
+	}
+}
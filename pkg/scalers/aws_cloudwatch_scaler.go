@@ -3,6 +3,7 @@ package scalers
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"k8s.io/metrics/pkg/apis/external_metrics"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	prommetrics "github.com/kedacore/keda/v2/pkg/metrics"
 	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
@@ -50,7 +52,8 @@ type awsCloudwatchMetadata struct {
 	metricStatPeriod     int64
 	metricEndTimeOffset  int64
 
-	awsRegion string
+	awsRegion   string
+	awsEndpoint string
 
 	awsAuthorization awsAuthorizationMetadata
 
@@ -63,12 +66,12 @@ var cloudwatchLog = logf.Log.WithName("aws_cloudwatch_scaler")
 func NewAwsCloudwatchScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseAwsCloudwatchMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing cloudwatch metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing cloudwatch metadata: %s", err))
 	}
 
 	return &awsCloudwatchScaler{
@@ -112,7 +115,9 @@ func getFloatMetadataValue(metadata map[string]string, key string, required bool
 
 func createCloudwatchClient(metadata *awsCloudwatchMetadata) *cloudwatch.CloudWatch {
 	sess := session.Must(session.NewSession(&aws.Config{
-		Region: aws.String(metadata.awsRegion),
+		Region:     aws.String(metadata.awsRegion),
+		Endpoint:   aws.String(metadata.awsEndpoint),
+		HTTPClient: prommetrics.InstrumentHTTPClient(&http.Client{}, "aws-cloudwatch"),
 	}))
 
 	var cloudwatchClient *cloudwatch.CloudWatch
@@ -228,6 +233,8 @@ func parseAwsCloudwatchMetadata(config *ScalerConfig) (*awsCloudwatchMetadata, e
 		return nil, fmt.Errorf("no awsRegion given")
 	}
 
+	meta.awsEndpoint = config.TriggerMetadata["awsEndpoint"]
+
 	meta.awsAuthorization, err = getAwsAuthorization(config.AuthParams, config.TriggerMetadata, config.ResolvedEnv)
 	if err != nil {
 		return nil, err
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -19,6 +20,7 @@ import (
 	"k8s.io/metrics/pkg/apis/external_metrics"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	prommetrics "github.com/kedacore/keda/v2/pkg/metrics"
 	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
@@ -31,6 +33,7 @@ type awsDynamoDBScaler struct {
 type awsDynamoDBMetadata struct {
 	tableName                 string
 	awsRegion                 string
+	awsEndpoint               string
 	keyConditionExpression    string
 	expressionAttributeNames  map[string]*string
 	expressionAttributeValues map[string]*dynamodb.AttributeValue
@@ -45,12 +48,12 @@ var dynamoDBLog = logf.Log.WithName("aws_dynamodb_scaler")
 func NewAwsDynamoDBScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseAwsDynamoDBMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing DynamoDb metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing DynamoDb metadata: %s", err))
 	}
 
 	return &awsDynamoDBScaler{
@@ -75,6 +78,8 @@ func parseAwsDynamoDBMetadata(config *ScalerConfig) (*awsDynamoDBMetadata, error
 		return nil, fmt.Errorf("no awsRegion given")
 	}
 
+	meta.awsEndpoint = config.TriggerMetadata["awsEndpoint"]
+
 	if val, ok := config.TriggerMetadata["keyConditionExpression"]; ok && val != "" {
 		meta.keyConditionExpression = val
 	} else {
@@ -132,7 +137,9 @@ func parseAwsDynamoDBMetadata(config *ScalerConfig) (*awsDynamoDBMetadata, error
 
 func createDynamoDBClient(meta *awsDynamoDBMetadata) *dynamodb.DynamoDB {
 	sess := session.Must(session.NewSession(&aws.Config{
-		Region: aws.String(meta.awsRegion),
+		Region:     aws.String(meta.awsRegion),
+		Endpoint:   aws.String(meta.awsEndpoint),
+		HTTPClient: prommetrics.InstrumentHTTPClient(&http.Client{}, "aws-dynamodb"),
 	}))
 
 	var dbClient *dynamodb.DynamoDB
@@ -3,6 +3,7 @@ package scalers
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strconv"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -20,6 +21,7 @@ import (
 	"k8s.io/metrics/pkg/apis/external_metrics"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	prommetrics "github.com/kedacore/keda/v2/pkg/metrics"
 	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
@@ -38,6 +40,7 @@ type awsDynamoDBStreamsMetadata struct {
 	targetShardCount int64
 	tableName        string
 	awsRegion        string
+	awsEndpoint      string
 	awsAuthorization awsAuthorizationMetadata
 	scalerIndex      int
 }
@@ -48,12 +51,12 @@ var dynamodbStreamLog = logf.Log.WithName("aws_dynamodb_streams_scaler")
 func NewAwsDynamoDBStreamsScaler(ctx context.Context, config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseAwsDynamoDBStreamsMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing dynamodb stream metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing dynamodb stream metadata: %s", err))
 	}
 
 	dbClient, dbStreamClient := createClientsForDynamoDBStreamsScaler(meta)
@@ -81,6 +84,8 @@ func parseAwsDynamoDBStreamsMetadata(config *ScalerConfig) (*awsDynamoDBStreamsM
 		return nil, fmt.Errorf("no awsRegion given")
 	}
 
+	meta.awsEndpoint = config.TriggerMetadata["awsEndpoint"]
+
 	if val, ok := config.TriggerMetadata["tableName"]; ok && val != "" {
 		meta.tableName = val
 	} else {
@@ -110,7 +115,9 @@ func parseAwsDynamoDBStreamsMetadata(config *ScalerConfig) (*awsDynamoDBStreamsM
 
 func createClientsForDynamoDBStreamsScaler(metadata *awsDynamoDBStreamsMetadata) (*dynamodb.DynamoDB, *dynamodbstreams.DynamoDBStreams) {
 	sess := session.Must(session.NewSession(&aws.Config{
-		Region: aws.String(metadata.awsRegion),
+		Region:     aws.String(metadata.awsRegion),
+		Endpoint:   aws.String(metadata.awsEndpoint),
+		HTTPClient: prommetrics.InstrumentHTTPClient(&http.Client{}, "aws-dynamodb-streams"),
 	}))
 
 	var dbClient *dynamodb.DynamoDB
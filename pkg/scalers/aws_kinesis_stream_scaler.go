@@ -3,6 +3,7 @@ package scalers
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strconv"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -16,6 +17,7 @@ import (
 	"k8s.io/metrics/pkg/apis/external_metrics"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	prommetrics "github.com/kedacore/keda/v2/pkg/metrics"
 	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
@@ -33,6 +35,7 @@ type awsKinesisStreamMetadata struct {
 	targetShardCount int64
 	streamName       string
 	awsRegion        string
+	awsEndpoint      string
 	awsAuthorization awsAuthorizationMetadata
 	scalerIndex      int
 }
@@ -43,12 +46,12 @@ var kinesisStreamLog = logf.Log.WithName("aws_kinesis_stream_scaler")
 func NewAwsKinesisStreamScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseAwsKinesisStreamMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing Kinesis stream metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing Kinesis stream metadata: %s", err))
 	}
 
 	return &awsKinesisStreamScaler{
@@ -84,6 +87,8 @@ func parseAwsKinesisStreamMetadata(config *ScalerConfig) (*awsKinesisStreamMetad
 		return nil, fmt.Errorf("no awsRegion given")
 	}
 
+	meta.awsEndpoint = config.TriggerMetadata["awsEndpoint"]
+
 	auth, err := getAwsAuthorization(config.AuthParams, config.TriggerMetadata, config.ResolvedEnv)
 	if err != nil {
 		return nil, err
@@ -98,7 +103,9 @@ func parseAwsKinesisStreamMetadata(config *ScalerConfig) (*awsKinesisStreamMetad
 
 func createKinesisClient(metadata *awsKinesisStreamMetadata) *kinesis.Kinesis {
 	sess := session.Must(session.NewSession(&aws.Config{
-		Region: aws.String(metadata.awsRegion),
+		Region:     aws.String(metadata.awsRegion),
+		Endpoint:   aws.String(metadata.awsEndpoint),
+		HTTPClient: prommetrics.InstrumentHTTPClient(&http.Client{}, "aws-kinesis-stream"),
 	}))
 
 	var kinesisClinent *kinesis.Kinesis
@@ -2,71 +2,124 @@ package scalers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/gobwas/glob"
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/metrics/pkg/apis/external_metrics"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	prommetrics "github.com/kedacore/keda/v2/pkg/metrics"
 	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
 const (
 	targetQueueLengthDefault = 5
 	defaultScaleOnInFlight   = true
+	defaultScaleOnDelayed    = false
+
+	defaultTargetOldestMessageAge = 60
+	sqsCloudwatchNamespace        = "AWS/SQS"
+	sqsMetricStatPeriod           = 60
+	sqsMetricCollectionTime       = 300
+	// sqsPeekMaxNumberOfMessages is the most messages received by a call, the oldest is picked among them
+	sqsPeekMaxNumberOfMessages = 10
 )
 
-var (
-	awsSqsQueueMetricNames = []string{
-		"ApproximateNumberOfMessages",
-		"ApproximateNumberOfMessagesNotVisible",
-	}
-	sqsQueueLog = logf.Log.WithName("aws_sqs_queue_scaler")
+const (
+	awsSqsScaleOnQueueLength      = "queueLength"
+	awsSqsScaleOnOldestMessageAge = "oldestMessageAge"
+
+	awsSqsOldestMessageAgeSourceCloudwatch = "cloudwatch"
+	awsSqsOldestMessageAgeSourcePeek       = "peek"
+
+	awsSqsDeadLetterQueueExclude = "exclude"
+	awsSqsDeadLetterQueueInclude = "include"
+	awsSqsDeadLetterQueueOnly    = "only"
 )
 
+const (
+	awsSqsQueueVisibleMessages  = "ApproximateNumberOfMessages"
+	awsSqsQueueInFlightMessages = "ApproximateNumberOfMessagesNotVisible"
+	awsSqsQueueDelayedMessages  = "ApproximateNumberOfMessagesDelayed"
+)
+
+var sqsQueueLog = logf.Log.WithName("aws_sqs_queue_scaler")
+
 type awsSqsQueueScaler struct {
 	metricType v2beta2.MetricTargetType
 	metadata   *awsSqsQueueMetadata
 	sqsClient  sqsiface.SQSAPI
+	// cwClient reads the ApproximateAgeOfOldestMessage metric of the queues, when scaling on it from cloudwatch
+	cwClient cloudwatchiface.CloudWatchAPI
 }
 
 type awsSqsQueueMetadata struct {
 	targetQueueLength int64
-	queueURL          string
-	queueName         string
-	awsRegion         string
-	awsAuthorization  awsAuthorizationMetadata
-	scalerIndex       int
-	scaleOnInFlight   bool
+	queueURLs         []string
+	// queueNamePattern is the glob pattern of the names of the queues, listed from the prefix of the pattern
+	queueNamePattern glob.Glob
+	queueNamePrefix  string
+	// queueName names the queues in the metric name
+	queueName string
+	// operation aggregates the lengths of the queues
+	operation        string
+	awsRegion        string
+	awsEndpoint      string
+	awsAuthorization awsAuthorizationMetadata
+	scalerIndex      int
+	// scaleOnInFlight counts the messages received but not deleted yet, being processed
+	scaleOnInFlight bool
+	// scaleOnDelayed counts the messages sent with a delay, not available yet
+	scaleOnDelayed bool
+	// scaleOn is the metric of the queues, their length or the age in seconds of their oldest message
+	scaleOn                string
+	targetOldestMessageAge int64
+	// oldestMessageAgeSource reads the age of the oldest message from the CloudWatch metric or from the
+	// messages received without hiding them
+	oldestMessageAgeSource string
+	// deadLetterQueue adds the dead-letter queues of the redrive policies of the queues to the queues, or
+	// replaces the queues with them to scale a deployment processing the failed messages
+	deadLetterQueue string
 }
 
 // NewAwsSqsQueueScaler creates a new awsSqsQueueScaler
 func NewAwsSqsQueueScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseAwsSqsQueueMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing SQS queue metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing SQS queue metadata: %s", err))
 	}
 
-	return &awsSqsQueueScaler{
+	scaler := &awsSqsQueueScaler{
 		metricType: metricType,
 		metadata:   meta,
 		sqsClient:  createSqsClient(meta),
-	}, nil
+	}
+	if meta.scaleOn == awsSqsScaleOnOldestMessageAge && meta.oldestMessageAgeSource == awsSqsOldestMessageAgeSourceCloudwatch {
+		scaler.cwClient = createCloudwatchClient(sqsCloudwatchMetadata(meta, ""))
+	}
+	return scaler, nil
 }
 
 func parseAwsSqsQueueMetadata(config *ScalerConfig) (*awsSqsQueueMetadata, error) {
@@ -94,30 +147,110 @@ func parseAwsSqsQueueMetadata(config *ScalerConfig) (*awsSqsQueueMetadata, error
 		}
 	}
 
-	if !meta.scaleOnInFlight {
-		awsSqsQueueMetricNames = []string{
-			"ApproximateNumberOfMessages",
+	meta.scaleOnDelayed = defaultScaleOnDelayed
+	if val, ok := config.TriggerMetadata["scaleOnDelayed"]; ok && val != "" {
+		scaleOnDelayed, err := strconv.ParseBool(val)
+		if err != nil {
+			sqsQueueLog.Error(err, "Error parsing SQS queue metadata scaleOnDelayed, using default", "default", defaultScaleOnDelayed)
+		} else {
+			meta.scaleOnDelayed = scaleOnDelayed
 		}
 	}
 
-	if val, ok := config.TriggerMetadata["queueURL"]; ok && val != "" {
-		meta.queueURL = val
-	} else {
-		return nil, fmt.Errorf("no queueURL given")
+	queueURLs, queueNamePattern := config.TriggerMetadata["queueURL"], config.TriggerMetadata["queueNamePattern"]
+	switch {
+	case queueURLs != "" && queueNamePattern != "":
+		return nil, fmt.Errorf("queueURL and queueNamePattern can't be both given")
+	case queueURLs != "":
+		// a comma separated list of queues
+		queueNames := []string{}
+		for _, val := range strings.Split(queueURLs, ",") {
+			val = strings.TrimSpace(val)
+			queueName, err := getAwsSqsQueueName(val)
+			if err != nil {
+				return nil, err
+			}
+			meta.queueURLs = append(meta.queueURLs, val)
+			queueNames = append(queueNames, queueName)
+		}
+		meta.queueName = strings.Join(queueNames, "-")
+	case queueNamePattern != "":
+		pattern, err := glob.Compile(queueNamePattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid queueNamePattern: %s", err)
+		}
+		meta.queueNamePattern = pattern
+		// the queues are listed by the prefix of their name before the first special character of the pattern
+		if i := strings.IndexAny(queueNamePattern, "*?[{\\"); i >= 0 {
+			meta.queueNamePrefix = queueNamePattern[:i]
+		} else {
+			meta.queueNamePrefix = queueNamePattern
+		}
+		// the characters of the pattern not allowed in the queue names are replaced in the metric name
+		meta.queueName = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' {
+				return r
+			}
+			return '-'
+		}, queueNamePattern)
+	default:
+		return nil, fmt.Errorf("no queueURL or queueNamePattern given")
 	}
 
-	queueURL, err := url.ParseRequestURI(meta.queueURL)
-	if err != nil {
-		// queueURL is not a valid URL, using it as queueName
-		meta.queueName = meta.queueURL
-	} else {
-		queueURLPath := queueURL.Path
-		queueURLPathParts := strings.Split(queueURLPath, "/")
-		if len(queueURLPathParts) != 3 || len(queueURLPathParts[2]) == 0 {
-			return nil, fmt.Errorf("cannot get queueName from queueURL")
+	meta.scaleOn = awsSqsScaleOnQueueLength
+	if val, ok := config.TriggerMetadata["scaleOn"]; ok && val != "" {
+		switch val {
+		case awsSqsScaleOnQueueLength, awsSqsScaleOnOldestMessageAge:
+			meta.scaleOn = val
+		default:
+			return nil, fmt.Errorf("err incorrect value for scaleOn is given: %s", val)
+		}
+	}
+
+	meta.targetOldestMessageAge = defaultTargetOldestMessageAge
+	if val, ok := config.TriggerMetadata["targetOldestMessageAge"]; ok && val != "" {
+		targetOldestMessageAge, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing targetOldestMessageAge: %s", err)
+		}
+		meta.targetOldestMessageAge = targetOldestMessageAge
+	}
+
+	meta.oldestMessageAgeSource = awsSqsOldestMessageAgeSourceCloudwatch
+	if val, ok := config.TriggerMetadata["oldestMessageAgeSource"]; ok && val != "" {
+		switch val {
+		case awsSqsOldestMessageAgeSourceCloudwatch, awsSqsOldestMessageAgeSourcePeek:
+			meta.oldestMessageAgeSource = val
+		default:
+			return nil, fmt.Errorf("err incorrect value for oldestMessageAgeSource is given: %s", val)
 		}
+	}
+
+	meta.deadLetterQueue = awsSqsDeadLetterQueueExclude
+	if val, ok := config.TriggerMetadata["deadLetterQueue"]; ok && val != "" {
+		switch val {
+		case awsSqsDeadLetterQueueExclude, awsSqsDeadLetterQueueInclude, awsSqsDeadLetterQueueOnly:
+			meta.deadLetterQueue = val
+		default:
+			return nil, fmt.Errorf("err incorrect value for deadLetterQueue is given: %s", val)
+		}
+	}
+	if meta.deadLetterQueue == awsSqsDeadLetterQueueOnly {
+		meta.queueName += "-dlq"
+	}
 
-		meta.queueName = queueURLPathParts[2]
+	// the ages of the oldest messages of the queues are not summed by default
+	meta.operation = sumOperation
+	if meta.scaleOn == awsSqsScaleOnOldestMessageAge {
+		meta.operation = maxOperation
+	}
+	if val, ok := config.TriggerMetadata["operation"]; ok && val != "" {
+		switch val {
+		case sumOperation, avgOperation, maxOperation:
+			meta.operation = val
+		default:
+			return nil, fmt.Errorf("operation %s must be one of %s, %s, %s", val, sumOperation, avgOperation, maxOperation)
+		}
 	}
 
 	if val, ok := config.TriggerMetadata["awsRegion"]; ok && val != "" {
@@ -126,6 +259,8 @@ func parseAwsSqsQueueMetadata(config *ScalerConfig) (*awsSqsQueueMetadata, error
 		return nil, fmt.Errorf("no awsRegion given")
 	}
 
+	meta.awsEndpoint = config.TriggerMetadata["awsEndpoint"]
+
 	auth, err := getAwsAuthorization(config.AuthParams, config.TriggerMetadata, config.ResolvedEnv)
 	if err != nil {
 		return nil, err
@@ -138,9 +273,26 @@ func parseAwsSqsQueueMetadata(config *ScalerConfig) (*awsSqsQueueMetadata, error
 	return &meta, nil
 }
 
+// getAwsSqsQueueName returns the name of a queue given by URL or by name
+func getAwsSqsQueueName(queueURL string) (string, error) {
+	parsedURL, err := url.ParseRequestURI(queueURL)
+	if err != nil {
+		// queueURL is not a valid URL, using it as queueName
+		return queueURL, nil
+	}
+
+	queueURLPathParts := strings.Split(parsedURL.Path, "/")
+	if len(queueURLPathParts) != 3 || len(queueURLPathParts[2]) == 0 {
+		return "", fmt.Errorf("cannot get queueName from queueURL")
+	}
+	return queueURLPathParts[2], nil
+}
+
 func createSqsClient(metadata *awsSqsQueueMetadata) *sqs.SQS {
 	sess := session.Must(session.NewSession(&aws.Config{
-		Region: aws.String(metadata.awsRegion),
+		Region:     aws.String(metadata.awsRegion),
+		Endpoint:   aws.String(metadata.awsEndpoint),
+		HTTPClient: prommetrics.InstrumentHTTPClient(&http.Client{}, "aws-sqs-queue"),
 	}))
 
 	var sqsClient *sqs.SQS
@@ -183,7 +335,7 @@ func (s *awsSqsQueueScaler) GetMetricSpecForScaling(context.Context) []v2beta2.M
 		Metric: v2beta2.MetricIdentifier{
 			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("aws-sqs-%s", s.metadata.queueName))),
 		},
-		Target: GetMetricTarget(s.metricType, s.metadata.targetQueueLength),
+		Target: GetMetricTarget(s.metricType, s.metadata.target()),
 	}
 	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
 	return []v2beta2.MetricSpec{metricSpec}
@@ -198,16 +350,242 @@ func (s *awsSqsQueueScaler) GetMetrics(ctx context.Context, metricName string, m
 		return []external_metrics.ExternalMetricValue{}, err
 	}
 
-	metric := GenerateMetricInMili(metricName, float64(queuelen))
+	metric := GenerateMetricInMili(metricName, queuelen)
 
 	return append([]external_metrics.ExternalMetricValue{}, metric), nil
 }
 
-// Get SQS Queue Length
-func (s *awsSqsQueueScaler) getAwsSqsQueueLength() (int64, error) {
+// target returns the target of the metric, the length of the queues or the age of their oldest message
+func (m *awsSqsQueueMetadata) target() int64 {
+	if m.scaleOn == awsSqsScaleOnOldestMessageAge {
+		return m.targetOldestMessageAge
+	}
+	return m.targetQueueLength
+}
+
+// attributeNames returns the attributes of the queue summed in the queue length, the visible messages
+// and optionally the in-flight and delayed messages
+func (m *awsSqsQueueMetadata) attributeNames() []string {
+	attributeNames := []string{awsSqsQueueVisibleMessages}
+	if m.scaleOnInFlight {
+		attributeNames = append(attributeNames, awsSqsQueueInFlightMessages)
+	}
+	if m.scaleOnDelayed {
+		attributeNames = append(attributeNames, awsSqsQueueDelayedMessages)
+	}
+	return attributeNames
+}
+
+// Get SQS Queue Length, the lengths or the ages of the oldest messages of the queues aggregated with the operation
+func (s *awsSqsQueueScaler) getAwsSqsQueueLength() (float64, error) {
+	queueURLs, err := s.getAwsSqsQueueURLs()
+	if err != nil {
+		return -1, err
+	}
+	if len(queueURLs) == 0 {
+		sqsQueueLog.V(1).Info("No queue matching the queueNamePattern or dead-letter queue", "queueNamePrefix", s.metadata.queueNamePrefix)
+		return 0, nil
+	}
+
+	var sum, max float64
+	for _, queueURL := range queueURLs {
+		value, err := s.getAwsSqsQueueURLValue(queueURL)
+		if err != nil {
+			return -1, err
+		}
+		sum += value
+		if value > max {
+			max = value
+		}
+	}
+
+	switch s.metadata.operation {
+	case avgOperation:
+		return sum / float64(len(queueURLs)), nil
+	case maxOperation:
+		return max, nil
+	default:
+		return sum, nil
+	}
+}
+
+// getAwsSqsQueueURLs returns the URLs of the queues given or of the queues matching the queueNamePattern, with
+// or replaced by their dead-letter queues
+func (s *awsSqsQueueScaler) getAwsSqsQueueURLs() ([]string, error) {
+	queueURLs, err := s.getAwsSqsSourceQueueURLs()
+	if err != nil {
+		return nil, err
+	}
+	if s.metadata.deadLetterQueue != awsSqsDeadLetterQueueInclude && s.metadata.deadLetterQueue != awsSqsDeadLetterQueueOnly {
+		return queueURLs, nil
+	}
+
+	// the queues may share a dead-letter queue, counted once
+	seen := map[string]bool{}
+	deadLetterQueueURLs := []string{}
+	for _, queueURL := range queueURLs {
+		deadLetterQueueURL, err := s.getAwsSqsDeadLetterQueueURL(queueURL)
+		if err != nil {
+			return nil, err
+		}
+		if deadLetterQueueURL == "" {
+			sqsQueueLog.V(1).Info("No redrive policy for the queue", "queueURL", queueURL)
+			continue
+		}
+		if !seen[deadLetterQueueURL] {
+			seen[deadLetterQueueURL] = true
+			deadLetterQueueURLs = append(deadLetterQueueURLs, deadLetterQueueURL)
+		}
+	}
+
+	if s.metadata.deadLetterQueue == awsSqsDeadLetterQueueOnly {
+		return deadLetterQueueURLs, nil
+	}
+	return append(append([]string{}, queueURLs...), deadLetterQueueURLs...), nil
+}
+
+// getAwsSqsDeadLetterQueueURL returns the URL of the dead-letter queue of the redrive policy of a queue, empty
+// without redrive policy
+func (s *awsSqsQueueScaler) getAwsSqsDeadLetterQueueURL(queueURL string) (string, error) {
+	output, err := s.sqsClient.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameRedrivePolicy}),
+		QueueUrl:       aws.String(queueURL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	redrivePolicy, ok := output.Attributes[sqs.QueueAttributeNameRedrivePolicy]
+	if !ok || redrivePolicy == nil || *redrivePolicy == "" {
+		return "", nil
+	}
+	var policy struct {
+		DeadLetterTargetArn string `json:"deadLetterTargetArn"`
+	}
+	if err := json.Unmarshal([]byte(*redrivePolicy), &policy); err != nil {
+		return "", fmt.Errorf("error parsing the redrive policy of the queue: %s", err)
+	}
+	deadLetterTargetArn, err := arn.Parse(policy.DeadLetterTargetArn)
+	if err != nil {
+		return "", fmt.Errorf("error parsing the deadLetterTargetArn of the queue: %s", err)
+	}
+
+	// the dead-letter queue is in the region of the queue, maybe of another account
+	urlOutput, err := s.sqsClient.GetQueueUrl(&sqs.GetQueueUrlInput{
+		QueueName:              aws.String(deadLetterTargetArn.Resource),
+		QueueOwnerAWSAccountId: aws.String(deadLetterTargetArn.AccountID),
+	})
+	if err != nil {
+		return "", err
+	}
+	return *urlOutput.QueueUrl, nil
+}
+
+// getAwsSqsSourceQueueURLs returns the URLs of the queues given or of the queues matching the queueNamePattern
+func (s *awsSqsQueueScaler) getAwsSqsSourceQueueURLs() ([]string, error) {
+	if s.metadata.queueNamePattern == nil {
+		return s.metadata.queueURLs, nil
+	}
+
+	queueURLs := []string{}
+	input := &sqs.ListQueuesInput{
+		QueueNamePrefix: aws.String(s.metadata.queueNamePrefix),
+	}
+	err := s.sqsClient.ListQueuesPages(input, func(output *sqs.ListQueuesOutput, lastPage bool) bool {
+		for _, queueURL := range output.QueueUrls {
+			queueName, err := getAwsSqsQueueName(*queueURL)
+			if err == nil && s.metadata.queueNamePattern.Match(queueName) {
+				queueURLs = append(queueURLs, *queueURL)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return queueURLs, nil
+}
+
+// getAwsSqsQueueURLValue returns the metric of a queue, its length or the age of its oldest message
+func (s *awsSqsQueueScaler) getAwsSqsQueueURLValue(queueURL string) (float64, error) {
+	if s.metadata.scaleOn != awsSqsScaleOnOldestMessageAge {
+		length, err := s.getAwsSqsQueueURLLength(queueURL)
+		return float64(length), err
+	}
+	if s.metadata.oldestMessageAgeSource == awsSqsOldestMessageAgeSourcePeek {
+		return s.peekAwsSqsQueueURLOldestMessageAge(queueURL)
+	}
+
+	queueName, err := getAwsSqsQueueName(queueURL)
+	if err != nil {
+		return -1, err
+	}
+	cloudwatchScaler := &awsCloudwatchScaler{
+		metadata: sqsCloudwatchMetadata(s.metadata, queueName),
+		cwClient: s.cwClient,
+	}
+	return cloudwatchScaler.GetCloudwatchMetrics()
+}
+
+// sqsCloudwatchMetadata returns the CloudWatch query of the ApproximateAgeOfOldestMessage metric of a queue.
+// awsEndpoint is the endpoint of SQS, CloudWatch is reached through the default endpoint of the region.
+func sqsCloudwatchMetadata(meta *awsSqsQueueMetadata, queueName string) *awsCloudwatchMetadata {
+	return &awsCloudwatchMetadata{
+		namespace:            sqsCloudwatchNamespace,
+		metricsName:          "ApproximateAgeOfOldestMessage",
+		dimensionName:        []string{"QueueName"},
+		dimensionValue:       []string{queueName},
+		metricStat:           "Maximum",
+		metricStatPeriod:     sqsMetricStatPeriod,
+		metricCollectionTime: sqsMetricCollectionTime,
+		targetMetricValue:    float64(meta.targetOldestMessageAge),
+		awsRegion:            meta.awsRegion,
+		awsAuthorization:     meta.awsAuthorization,
+		scalerIndex:          meta.scalerIndex,
+	}
+}
+
+// peekAwsSqsQueueURLOldestMessageAge returns the age in seconds of the oldest of the messages received from a
+// queue, 0 without messages. The messages are received with a visibility timeout of 0 to stay visible to the
+// consumers, this increases their receive count though, count it in the maxReceiveCount of a redrive policy.
+// A standard queue returns a sample of its messages, the age is an approximation like the CloudWatch metric.
+func (s *awsSqsQueueScaler) peekAwsSqsQueueURLOldestMessageAge(queueURL string) (float64, error) {
+	input := &sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(queueURL),
+		AttributeNames:      aws.StringSlice([]string{sqs.MessageSystemAttributeNameSentTimestamp}),
+		MaxNumberOfMessages: aws.Int64(sqsPeekMaxNumberOfMessages),
+		VisibilityTimeout:   aws.Int64(0),
+		WaitTimeSeconds:     aws.Int64(0),
+	}
+
+	output, err := s.sqsClient.ReceiveMessage(input)
+	if err != nil {
+		return -1, err
+	}
+
+	var age float64
+	for _, message := range output.Messages {
+		sentTimestamp, ok := message.Attributes[sqs.MessageSystemAttributeNameSentTimestamp]
+		if !ok || sentTimestamp == nil {
+			return -1, fmt.Errorf("attribute %s not returned for the message", sqs.MessageSystemAttributeNameSentTimestamp)
+		}
+		sentMillis, err := strconv.ParseInt(*sentTimestamp, 10, 64)
+		if err != nil {
+			return -1, err
+		}
+		if messageAge := time.Since(time.UnixMilli(sentMillis)).Seconds(); messageAge > age {
+			age = messageAge
+		}
+	}
+
+	return age, nil
+}
+
+func (s *awsSqsQueueScaler) getAwsSqsQueueURLLength(queueURL string) (int64, error) {
+	attributeNames := s.metadata.attributeNames()
 	input := &sqs.GetQueueAttributesInput{
-		AttributeNames: aws.StringSlice(awsSqsQueueMetricNames),
-		QueueUrl:       aws.String(s.metadata.queueURL),
+		AttributeNames: aws.StringSlice(attributeNames),
+		QueueUrl:       aws.String(queueURL),
 	}
 
 	output, err := s.sqsClient.GetQueueAttributes(input)
@@ -216,8 +594,12 @@ func (s *awsSqsQueueScaler) getAwsSqsQueueLength() (int64, error) {
 	}
 
 	var approximateNumberOfMessages int64
-	for _, awsSqsQueueMetric := range awsSqsQueueMetricNames {
-		metricValue, err := strconv.ParseInt(*output.Attributes[awsSqsQueueMetric], 10, 32)
+	for _, awsSqsQueueMetric := range attributeNames {
+		attribute, ok := output.Attributes[awsSqsQueueMetric]
+		if !ok || attribute == nil {
+			return -1, fmt.Errorf("attribute %s not returned for the queue", awsSqsQueueMetric)
+		}
+		metricValue, err := strconv.ParseInt(*attribute, 10, 32)
 		if err != nil {
 			return -1, err
 		}
@@ -3,12 +3,18 @@ package scalers
 import (
 	"context"
 	"errors"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/stretchr/testify/assert"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
@@ -45,11 +51,68 @@ type awsSQSMetricIdentifier struct {
 	name             string
 }
 
+// testAWSSQSTenantQueues are the queues listed by the mock, with their visible messages
+var testAWSSQSTenantQueues = map[string]string{
+	"https://sqs.eu-west-1.amazonaws.com/account_id/tenant-a-jobs":    "10",
+	"https://sqs.eu-west-1.amazonaws.com/account_id/tenant-b-jobs":    "30",
+	"https://sqs.eu-west-1.amazonaws.com/account_id/tenant-b-reports": "5",
+	// the dead-letter queue of the jobs queues, in another account
+	"https://sqs.eu-west-1.amazonaws.com/123456789012/tenant-jobs-dlq": "7",
+}
+
+// testAWSSQSRedrivePolicies are the redrive policies of the queues
+var testAWSSQSRedrivePolicies = map[string]string{
+	"https://sqs.eu-west-1.amazonaws.com/account_id/tenant-a-jobs": `{"deadLetterTargetArn":"arn:aws:sqs:eu-west-1:123456789012:tenant-jobs-dlq","maxReceiveCount":5}`,
+	"https://sqs.eu-west-1.amazonaws.com/account_id/tenant-b-jobs": `{"deadLetterTargetArn":"arn:aws:sqs:eu-west-1:123456789012:tenant-jobs-dlq","maxReceiveCount":3}`,
+	testAWSSQSProperQueueURL:  `{"deadLetterTargetArn":"BadArn","maxReceiveCount":5}`,
+	testAWSSQSBadDataQueueURL: `NotJson`,
+}
+
 type mockSqs struct {
 	sqsiface.SQSAPI
 }
 
+// ListQueuesPages returns the tenant queues matching the prefix, a queue per page
+func (m *mockSqs) ListQueuesPages(input *sqs.ListQueuesInput, fn func(*sqs.ListQueuesOutput, bool) bool) error {
+	if *input.QueueNamePrefix == "Error" {
+		return errors.New("some error")
+	}
+	queueURLs := []string{}
+	for queueURL := range testAWSSQSTenantQueues {
+		if strings.HasPrefix(queueURL, "https://sqs.eu-west-1.amazonaws.com/account_id/"+*input.QueueNamePrefix) {
+			queueURLs = append(queueURLs, queueURL)
+		}
+	}
+	for i, queueURL := range queueURLs {
+		if !fn(&sqs.ListQueuesOutput{QueueUrls: aws.StringSlice([]string{queueURL})}, i == len(queueURLs)-1) {
+			break
+		}
+	}
+	return nil
+}
+
 func (m *mockSqs) GetQueueAttributes(input *sqs.GetQueueAttributesInput) (*sqs.GetQueueAttributesOutput, error) {
+	if *input.AttributeNames[0] == "RedrivePolicy" {
+		if *input.QueueUrl == testAWSSQSErrorQueueURL {
+			return nil, errors.New("some error")
+		}
+		attributes := map[string]*string{}
+		if redrivePolicy, ok := testAWSSQSRedrivePolicies[*input.QueueUrl]; ok {
+			attributes["RedrivePolicy"] = aws.String(redrivePolicy)
+		}
+		return &sqs.GetQueueAttributesOutput{Attributes: attributes}, nil
+	}
+
+	if visible, ok := testAWSSQSTenantQueues[*input.QueueUrl]; ok {
+		return &sqs.GetQueueAttributesOutput{
+			Attributes: map[string]*string{
+				"ApproximateNumberOfMessages":           aws.String(visible),
+				"ApproximateNumberOfMessagesNotVisible": aws.String("0"),
+				"ApproximateNumberOfMessagesDelayed":    aws.String("0"),
+			},
+		}, nil
+	}
+
 	switch *input.QueueUrl {
 	case testAWSSQSErrorQueueURL:
 		return nil, errors.New("some error")
@@ -66,6 +129,60 @@ func (m *mockSqs) GetQueueAttributes(input *sqs.GetQueueAttributesInput) (*sqs.G
 		Attributes: map[string]*string{
 			"ApproximateNumberOfMessages":           aws.String("200"),
 			"ApproximateNumberOfMessagesNotVisible": aws.String("100"),
+			"ApproximateNumberOfMessagesDelayed":    aws.String("50"),
+		},
+	}, nil
+}
+
+func (m *mockSqs) GetQueueUrl(input *sqs.GetQueueUrlInput) (*sqs.GetQueueUrlOutput, error) {
+	queueURL := "https://sqs.eu-west-1.amazonaws.com/" + *input.QueueOwnerAWSAccountId + "/" + *input.QueueName
+	if _, ok := testAWSSQSTenantQueues[queueURL]; !ok {
+		return nil, errors.New("AWS.SimpleQueueService.NonExistentQueue")
+	}
+	return &sqs.GetQueueUrlOutput{QueueUrl: aws.String(queueURL)}, nil
+}
+
+// ReceiveMessage returns messages sent 30 and 120 seconds ago from the tenant-a-jobs queue and no message from
+// the other queues
+func (m *mockSqs) ReceiveMessage(input *sqs.ReceiveMessageInput) (*sqs.ReceiveMessageOutput, error) {
+	switch *input.QueueUrl {
+	case testAWSSQSErrorQueueURL:
+		return nil, errors.New("some error")
+	case testAWSSQSBadDataQueueURL:
+		return &sqs.ReceiveMessageOutput{
+			Messages: []*sqs.Message{{Attributes: map[string]*string{"SentTimestamp": aws.String("NotInt")}}},
+		}, nil
+	case "https://sqs.eu-west-1.amazonaws.com/account_id/tenant-a-jobs":
+		if *input.VisibilityTimeout != 0 {
+			return nil, errors.New("messages hidden from the consumers")
+		}
+		messages := []*sqs.Message{}
+		for _, age := range []time.Duration{30 * time.Second, 120 * time.Second} {
+			sentTimestamp := strconv.FormatInt(time.Now().Add(-age).UnixMilli(), 10)
+			messages = append(messages, &sqs.Message{Attributes: map[string]*string{"SentTimestamp": aws.String(sentTimestamp)}})
+		}
+		return &sqs.ReceiveMessageOutput{Messages: messages}, nil
+	}
+	return &sqs.ReceiveMessageOutput{}, nil
+}
+
+// mockSqsCloudwatch returns the age of the oldest message of the queues, the length of their name in minutes
+type mockSqsCloudwatch struct {
+	cloudwatchiface.CloudWatchAPI
+}
+
+func (m *mockSqsCloudwatch) GetMetricData(input *cloudwatch.GetMetricDataInput) (*cloudwatch.GetMetricDataOutput, error) {
+	metric := input.MetricDataQueries[0].MetricStat.Metric
+	if *metric.Namespace != "AWS/SQS" || *metric.MetricName != "ApproximateAgeOfOldestMessage" || *metric.Dimensions[0].Name != "QueueName" {
+		return nil, errors.New("unexpected metric")
+	}
+	queueName := *metric.Dimensions[0].Value
+	if queueName == "Error" {
+		return nil, errors.New("some error")
+	}
+	return &cloudwatch.GetMetricDataOutput{
+		MetricDataResults: []*cloudwatch.MetricDataResult{
+			{Values: []*float64{aws.Float64(float64(len(queueName) * 60))}},
 		},
 	}, nil
 }
@@ -223,17 +340,119 @@ var testAWSSQSMetadata = []parseAWSSQSMetadataTestData{
 		testAWSSQSAuthentication,
 		false,
 		"properly formed queue and region"},
+	{map[string]string{
+		"queueURL":        testAWSSimpleQueueURL,
+		"queueLength":     "1",
+		"awsRegion":       "eu-west-1",
+		"scaleOnInFlight": "false",
+		"scaleOnDelayed":  "true"},
+		testAWSSQSAuthentication,
+		false,
+		"properly formed queue and region, scaling on the delayed messages"},
+	{map[string]string{
+		"queueURL":  testAWSSQSProperQueueURL + ", " + testAWSSimpleQueueURL,
+		"operation": "max",
+		"awsRegion": "eu-west-1"},
+		testAWSSQSAuthentication,
+		false,
+		"list of queues"},
+	{map[string]string{
+		"queueURL":  testAWSSQSProperQueueURL + "," + testAWSSQSImproperQueueURL1,
+		"awsRegion": "eu-west-1"},
+		testAWSSQSAuthentication,
+		true,
+		"list of queues, improperly formed queue"},
+	{map[string]string{
+		"queueNamePattern": "tenant-*-jobs",
+		"operation":        "avg",
+		"awsRegion":        "eu-west-1"},
+		testAWSSQSAuthentication,
+		false,
+		"queue name pattern"},
+	{map[string]string{
+		"queueNamePattern": "tenant-[a",
+		"awsRegion":        "eu-west-1"},
+		testAWSSQSAuthentication,
+		true,
+		"invalid queue name pattern"},
+	{map[string]string{
+		"queueURL":         testAWSSQSProperQueueURL,
+		"queueNamePattern": "tenant-*",
+		"awsRegion":        "eu-west-1"},
+		testAWSSQSAuthentication,
+		true,
+		"queueURL and queue name pattern"},
+	{map[string]string{
+		"queueURL":  testAWSSQSProperQueueURL,
+		"operation": "min",
+		"awsRegion": "eu-west-1"},
+		testAWSSQSAuthentication,
+		true,
+		"invalid operation"},
+	{map[string]string{
+		"queueURL":               testAWSSQSProperQueueURL,
+		"scaleOn":                "oldestMessageAge",
+		"targetOldestMessageAge": "300",
+		"oldestMessageAgeSource": "peek",
+		"awsRegion":              "eu-west-1"},
+		testAWSSQSAuthentication,
+		false,
+		"scaling on the age of the oldest message"},
+	{map[string]string{
+		"queueURL":  testAWSSQSProperQueueURL,
+		"scaleOn":   "oldestMessageCount",
+		"awsRegion": "eu-west-1"},
+		testAWSSQSAuthentication,
+		true,
+		"invalid scaleOn"},
+	{map[string]string{
+		"queueURL":               testAWSSQSProperQueueURL,
+		"scaleOn":                "oldestMessageAge",
+		"targetOldestMessageAge": "a",
+		"awsRegion":              "eu-west-1"},
+		testAWSSQSAuthentication,
+		true,
+		"invalid targetOldestMessageAge"},
+	{map[string]string{
+		"queueURL":               testAWSSQSProperQueueURL,
+		"scaleOn":                "oldestMessageAge",
+		"oldestMessageAgeSource": "console",
+		"awsRegion":              "eu-west-1"},
+		testAWSSQSAuthentication,
+		true,
+		"invalid oldestMessageAgeSource"},
+	{map[string]string{
+		"queueNamePattern": "tenant-*-jobs",
+		"deadLetterQueue":  "only",
+		"awsRegion":        "eu-west-1"},
+		testAWSSQSAuthentication,
+		false,
+		"scaling on the dead-letter queues"},
+	{map[string]string{
+		"queueURL":        testAWSSQSProperQueueURL,
+		"deadLetterQueue": "all",
+		"awsRegion":       "eu-west-1"},
+		testAWSSQSAuthentication,
+		true,
+		"invalid deadLetterQueue"},
 }
 
 var awsSQSMetricIdentifiers = []awsSQSMetricIdentifier{
 	{&testAWSSQSMetadata[1], 0, "s0-aws-sqs-DeleteArtifactQ"},
 	{&testAWSSQSMetadata[1], 1, "s1-aws-sqs-DeleteArtifactQ"},
+	{&testAWSSQSMetadata[19], 2, "s2-aws-sqs-DeleteArtifactQ-my-queue"},
+	{&testAWSSQSMetadata[21], 3, "s3-aws-sqs-tenant---jobs"},
+	{&testAWSSQSMetadata[29], 4, "s4-aws-sqs-tenant---jobs-dlq"},
 }
 
 var awsSQSGetMetricTestData = []*awsSqsQueueMetadata{
-	{queueURL: testAWSSQSProperQueueURL},
-	{queueURL: testAWSSQSErrorQueueURL},
-	{queueURL: testAWSSQSBadDataQueueURL},
+	{queueURLs: []string{testAWSSQSProperQueueURL}},
+	{queueURLs: []string{testAWSSQSProperQueueURL}, scaleOnInFlight: true},
+	{queueURLs: []string{testAWSSQSProperQueueURL}, scaleOnDelayed: true},
+	{queueURLs: []string{testAWSSQSProperQueueURL}, scaleOnInFlight: true, scaleOnDelayed: true},
+	{queueURLs: []string{testAWSSQSErrorQueueURL}},
+	{queueURLs: []string{testAWSSQSBadDataQueueURL}},
+	{queueURLs: []string{testAWSSQSBadDataQueueURL}, scaleOnDelayed: true},
 }
 
 func TestSQSParseMetadata(t *testing.T) {
@@ -255,7 +474,7 @@ func TestAWSSQSGetMetricSpecForScaling(t *testing.T) {
 		if err != nil {
 			t.Fatal("Could not parse metadata:", err)
 		}
-		mockAWSSQSScaler := awsSqsQueueScaler{"", meta, &mockSqs{}}
+		mockAWSSQSScaler := awsSqsQueueScaler{metadata: meta, sqsClient: &mockSqs{}}
 
 		metricSpec := mockAWSSQSScaler.GetMetricSpecForScaling(ctx)
 		metricName := metricSpec[0].External.Metric.Name
@@ -268,19 +487,144 @@ func TestAWSSQSGetMetricSpecForScaling(t *testing.T) {
 func TestAWSSQSScalerGetMetrics(t *testing.T) {
 	var selector labels.Selector
 	for _, meta := range awsSQSGetMetricTestData {
-		scaler := awsSqsQueueScaler{"", meta, &mockSqs{}}
+		scaler := awsSqsQueueScaler{metadata: meta, sqsClient: &mockSqs{}}
 		value, err := scaler.GetMetrics(context.Background(), "MetricName", selector)
-		switch meta.queueURL {
+		switch meta.queueURLs[0] {
 		case testAWSSQSErrorQueueURL:
 			assert.Error(t, err, "expect error because of sqs api error")
 		case testAWSSQSBadDataQueueURL:
 			assert.Error(t, err, "expect error because of bad data return from sqs")
 		default:
+			expected := int64(200)
 			if meta.scaleOnInFlight {
-				assert.EqualValues(t, int64(300.0), value[0].Value.Value())
-			} else {
-				assert.EqualValues(t, int64(200.0), value[0].Value.Value())
+				expected += 100
 			}
+			if meta.scaleOnDelayed {
+				expected += 50
+			}
+			assert.EqualValues(t, expected, value[0].Value.Value())
+		}
+	}
+}
+
+func TestAWSSQSScalerGetMultipleQueuesLength(t *testing.T) {
+	testCases := []struct {
+		metadata map[string]string
+		expected float64
+		isError  bool
+	}{
+		{map[string]string{"queueNamePattern": "tenant-*-jobs"}, 40, false},
+		{map[string]string{"queueNamePattern": "tenant-*-jobs", "operation": "avg"}, 20, false},
+		{map[string]string{"queueNamePattern": "tenant-*-jobs", "operation": "max"}, 30, false},
+		{map[string]string{"queueNamePattern": "tenant-b-*"}, 35, false},
+		{map[string]string{"queueNamePattern": "other-*"}, 0, false},
+		{map[string]string{"queueNamePattern": "Error*"}, 0, true},
+		{map[string]string{"queueURL": "https://sqs.eu-west-1.amazonaws.com/account_id/tenant-a-jobs," + testAWSSQSProperQueueURL}, 210, false},
+		{map[string]string{"queueURL": "https://sqs.eu-west-1.amazonaws.com/account_id/tenant-a-jobs," + testAWSSQSErrorQueueURL}, 0, true},
+	}
+
+	for _, testCase := range testCases {
+		testCase.metadata["awsRegion"] = "eu-west-1"
+		testCase.metadata["scaleOnInFlight"] = "false"
+		meta, err := parseAwsSqsQueueMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, AuthParams: testAWSSQSAuthentication})
+		assert.NoError(t, err)
+		scaler := awsSqsQueueScaler{metadata: meta, sqsClient: &mockSqs{}}
+
+		value, err := scaler.getAwsSqsQueueLength()
+		if testCase.isError {
+			assert.Error(t, err, "metadata %v", testCase.metadata)
+			continue
+		}
+		assert.NoError(t, err, "metadata %v", testCase.metadata)
+		assert.Equal(t, testCase.expected, value, "metadata %v", testCase.metadata)
+	}
+}
+
+func TestAWSSQSScalerGetDeadLetterQueueLength(t *testing.T) {
+	testCases := []struct {
+		metadata map[string]string
+		expected float64
+		isError  bool
+	}{
+		{map[string]string{"queueNamePattern": "tenant-*-jobs", "deadLetterQueue": "include"}, 47, false},
+		{map[string]string{"queueNamePattern": "tenant-*", "deadLetterQueue": "include", "operation": "max"}, 30, false},
+		{map[string]string{"queueNamePattern": "tenant-*-jobs", "deadLetterQueue": "only"}, 7, false},
+		{map[string]string{"queueURL": "https://sqs.eu-west-1.amazonaws.com/account_id/tenant-b-reports", "deadLetterQueue": "only"}, 0, false},
+		{map[string]string{"queueURL": "https://sqs.eu-west-1.amazonaws.com/account_id/tenant-b-reports", "deadLetterQueue": "include"}, 5, false},
+		{map[string]string{"queueURL": testAWSSQSErrorQueueURL, "deadLetterQueue": "include"}, 0, true},
+		{map[string]string{"queueURL": testAWSSQSBadDataQueueURL, "deadLetterQueue": "only"}, 0, true},
+		{map[string]string{"queueURL": testAWSSQSProperQueueURL, "deadLetterQueue": "only"}, 0, true},
+	}
+
+	for _, testCase := range testCases {
+		testCase.metadata["awsRegion"] = "eu-west-1"
+		testCase.metadata["scaleOnInFlight"] = "false"
+		meta, err := parseAwsSqsQueueMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, AuthParams: testAWSSQSAuthentication})
+		assert.NoError(t, err)
+		scaler := awsSqsQueueScaler{metadata: meta, sqsClient: &mockSqs{}}
+
+		value, err := scaler.getAwsSqsQueueLength()
+		if testCase.isError {
+			assert.Error(t, err, "metadata %v", testCase.metadata)
+			continue
+		}
+		assert.NoError(t, err, "metadata %v", testCase.metadata)
+		assert.Equal(t, testCase.expected, value, "metadata %v", testCase.metadata)
+	}
+}
+
+func TestAWSSQSScalerGetOldestMessageAge(t *testing.T) {
+	testCases := []struct {
+		metadata map[string]string
+		expected float64
+		isError  bool
+	}{
+		{map[string]string{"queueNamePattern": "tenant-*", "oldestMessageAgeSource": "peek"}, 120, false},
+		{map[string]string{"queueURL": "https://sqs.eu-west-1.amazonaws.com/account_id/tenant-b-jobs", "oldestMessageAgeSource": "peek"}, 0, false},
+		{map[string]string{"queueURL": testAWSSQSErrorQueueURL, "oldestMessageAgeSource": "peek"}, 0, true},
+		{map[string]string{"queueURL": testAWSSQSBadDataQueueURL, "oldestMessageAgeSource": "peek"}, 0, true},
+		{map[string]string{"queueNamePattern": "tenant-*"}, 960, false},
+		{map[string]string{"queueNamePattern": "tenant-*-jobs", "operation": "avg"}, 780, false},
+		{map[string]string{"queueURL": testAWSSQSProperQueueURL + "," + testAWSSQSErrorQueueURL}, 0, true},
+	}
+
+	for _, testCase := range testCases {
+		testCase.metadata["awsRegion"] = "eu-west-1"
+		testCase.metadata["scaleOn"] = "oldestMessageAge"
+		meta, err := parseAwsSqsQueueMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, AuthParams: testAWSSQSAuthentication})
+		assert.NoError(t, err)
+		scaler := awsSqsQueueScaler{metadata: meta, sqsClient: &mockSqs{}, cwClient: &mockSqsCloudwatch{}}
+
+		value, err := scaler.getAwsSqsQueueLength()
+		if testCase.isError {
+			assert.Error(t, err, "metadata %v", testCase.metadata)
+			continue
 		}
+		assert.NoError(t, err, "metadata %v", testCase.metadata)
+		assert.InDelta(t, testCase.expected, value, 5, "metadata %v", testCase.metadata)
+	}
+
+	meta, err := parseAwsSqsQueueMetadata(&ScalerConfig{TriggerMetadata: testAWSSQSMetadata[25].metadata, AuthParams: testAWSSQSAuthentication})
+	assert.NoError(t, err)
+	scaler := awsSqsQueueScaler{metricType: v2beta2.AverageValueMetricType, metadata: meta, sqsClient: &mockSqs{}}
+	metricSpec := scaler.GetMetricSpecForScaling(context.Background())
+	assert.EqualValues(t, 300, metricSpec[0].External.Target.AverageValue.Value())
+}
+
+func TestAWSSQSEndpoint(t *testing.T) {
+	t.Setenv("AWS_CA_BUNDLE", "")
+	testCases := []struct {
+		metadata map[string]string
+		endpoint string
+	}{
+		{map[string]string{"queueURL": testAWSSQSProperQueueURL, "awsRegion": "eu-west-1"}, "https://sqs.eu-west-1.amazonaws.com"},
+		{map[string]string{"queueURL": "http://localstack:4566/000000000000/jobs", "awsRegion": "eu-west-1", "awsEndpoint": "http://localstack:4566"}, "http://localstack:4566"},
+		{map[string]string{"queueURL": testAWSSQSProperQueueURL, "awsRegion": "eu-west-1", "awsEndpoint": "https://vpce-0123-abcd.sqs.eu-west-1.vpce.amazonaws.com"}, "https://vpce-0123-abcd.sqs.eu-west-1.vpce.amazonaws.com"},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parseAwsSqsQueueMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, AuthParams: testAWSSQSAuthentication})
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.endpoint, createSqsClient(meta).Endpoint)
 	}
 }
@@ -16,7 +16,11 @@ limitations under the License.
 
 package azure
 
-import "time"
+import (
+	"time"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
 
 // AADToken is the token from Azure AD
 type AADToken struct {
@@ -31,3 +35,12 @@ type AADToken struct {
 	GrantedScopes       []string  `json:"grantedScopes"`
 	DeclinedScopes      []string  `json:"DeclinedScopes"`
 }
+
+// GetPodIdentityResource returns the audience set on the pod identity if any,
+// otherwise the default resource of the scaler
+func GetPodIdentityResource(podIdentity kedav1alpha1.AuthPodIdentity, resource string) string {
+	if podIdentity.Audience != "" {
+		return podIdentity.Audience
+	}
+	return resource
+}
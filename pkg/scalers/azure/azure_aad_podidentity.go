@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	"github.com/kedacore/keda/v2/pkg/util"
 )
 
@@ -17,8 +18,14 @@ const (
 	MSIURLWithClientID = "http://169.254.169.254/metadata/identity/oauth2/token?api-version=2018-02-01&resource=%s&client_id=%s"
 )
 
-// GetAzureADPodIdentityToken returns the AADToken for resource
+// GetAzureADPodIdentityToken returns the AADToken for resource, tokens are shared between callers until they expire
 func GetAzureADPodIdentityToken(ctx context.Context, httpClient util.HTTPDoer, identityID, audience string) (AADToken, error) {
+	return tokenCache.getOrFetch(aadTokenCacheKey(string(kedav1alpha1.PodIdentityProviderAzure), identityID, audience), func() (AADToken, error) {
+		return getAzureADPodIdentityToken(ctx, httpClient, identityID, audience)
+	})
+}
+
+func getAzureADPodIdentityToken(ctx context.Context, httpClient util.HTTPDoer, identityID, audience string) (AADToken, error) {
 	var token AADToken
 
 	var urlStr string
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// aadTokenRefreshMargin is how long before its expiration a cached token is considered stale,
+// so a scaler never gets a token that expires while its request is in flight
+const aadTokenRefreshMargin = 5 * time.Minute
+
+// aadTokenCache shares the AAD tokens between all the scalers using the same identity for the
+// same audience, so every scaler doesn't perform its own token exchange on each polling interval
+type aadTokenCache struct {
+	lock    sync.Mutex
+	entries map[string]*aadTokenCacheEntry
+}
+
+type aadTokenCacheEntry struct {
+	// lock is held while the token is fetched, so concurrent scalers wait for a single exchange
+	lock  sync.Mutex
+	token AADToken
+}
+
+var tokenCache = &aadTokenCache{entries: map[string]*aadTokenCacheEntry{}}
+
+func aadTokenCacheKey(provider, identityID, audience string) string {
+	return fmt.Sprintf("%s/%s/%s", provider, identityID, audience)
+}
+
+// getOrFetch returns the cached token for key if it is still valid, otherwise it calls fetch and caches the result
+func (c *aadTokenCache) getOrFetch(key string, fetch func() (AADToken, error)) (AADToken, error) {
+	c.lock.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &aadTokenCacheEntry{}
+		c.entries[key] = entry
+	}
+	c.lock.Unlock()
+
+	entry.lock.Lock()
+	defer entry.lock.Unlock()
+
+	if expiresOn, ok := getAADTokenExpiration(entry.token); ok && time.Now().Add(aadTokenRefreshMargin).Before(expiresOn) {
+		return entry.token, nil
+	}
+
+	token, err := fetch()
+	if err != nil {
+		return token, err
+	}
+	entry.token = token
+	return token, nil
+}
+
+// getAADTokenExpiration returns the expiration of the token, and false if it is unknown
+func getAADTokenExpiration(token AADToken) (time.Time, bool) {
+	if token.AccessToken == "" {
+		return time.Time{}, false
+	}
+	if !token.ExpiresOnTimeObject.IsZero() {
+		return token.ExpiresOnTimeObject, true
+	}
+	expiresOn, err := strconv.ParseInt(token.ExpiresOn, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(expiresOn, 0), true
+}
@@ -0,0 +1,105 @@
+package azure
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAADTokenCacheReusesValidToken(t *testing.T) {
+	cache := &aadTokenCache{entries: map[string]*aadTokenCacheEntry{}}
+	calls := 0
+	fetch := func() (AADToken, error) {
+		calls++
+		return AADToken{AccessToken: "token", ExpiresOnTimeObject: time.Now().Add(time.Hour)}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.getOrFetch(aadTokenCacheKey("azure-workload", "id", "audience"), fetch); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Expected a single token exchange but got %d", calls)
+	}
+}
+
+func TestAADTokenCacheKeyedByIdentityAndAudience(t *testing.T) {
+	cache := &aadTokenCache{entries: map[string]*aadTokenCacheEntry{}}
+	calls := 0
+	fetch := func() (AADToken, error) {
+		calls++
+		return AADToken{AccessToken: "token", ExpiresOnTimeObject: time.Now().Add(time.Hour)}, nil
+	}
+
+	for _, key := range []string{
+		aadTokenCacheKey("azure-workload", "id", "audience"),
+		aadTokenCacheKey("azure-workload", "other-id", "audience"),
+		aadTokenCacheKey("azure-workload", "id", "other-audience"),
+		aadTokenCacheKey("azure", "id", "audience"),
+	} {
+		if _, err := cache.getOrFetch(key, fetch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if calls != 4 {
+		t.Errorf("Expected 4 token exchanges but got %d", calls)
+	}
+}
+
+func TestAADTokenCacheRefreshesExpiringToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		token AADToken
+	}{
+		{"expiring time object", AADToken{AccessToken: "token", ExpiresOnTimeObject: time.Now().Add(time.Minute)}},
+		{"expiring unix timestamp", AADToken{AccessToken: "token", ExpiresOn: strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)}},
+		{"unknown expiration", AADToken{AccessToken: "token"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cache := &aadTokenCache{entries: map[string]*aadTokenCacheEntry{}}
+			calls := 0
+			fetch := func() (AADToken, error) {
+				calls++
+				return test.token, nil
+			}
+
+			for i := 0; i < 2; i++ {
+				if _, err := cache.getOrFetch("key", fetch); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if calls != 2 {
+				t.Errorf("Expected 2 token exchanges but got %d", calls)
+			}
+		})
+	}
+}
+
+func TestAADTokenCacheDoesNotCacheErrors(t *testing.T) {
+	cache := &aadTokenCache{entries: map[string]*aadTokenCacheEntry{}}
+	if _, err := cache.getOrFetch("key", func() (AADToken, error) {
+		return AADToken{}, errors.New("exchange failed")
+	}); err == nil {
+		t.Fatal("Expected error but got success")
+	}
+
+	token, err := cache.getOrFetch("key", func() (AADToken, error) {
+		return AADToken{AccessToken: "token", ExpiresOnTimeObject: time.Now().Add(time.Hour)}, nil
+	})
+	if err != nil || token.AccessToken != "token" {
+		t.Errorf("Expected a fresh token but got %v, %v", token, err)
+	}
+}
@@ -28,6 +28,8 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/confidential"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 )
 
 // Azure AD Workload Identity Webhook will inject the following environment variables.
@@ -43,8 +45,14 @@ const (
 	azureAuthrityHostEnv       = "AZURE_AUTHORITY_HOST"
 )
 
-// GetAzureADWorkloadIdentityToken returns the AADToken for resource
+// GetAzureADWorkloadIdentityToken returns the AADToken for resource, tokens are shared between callers until they expire
 func GetAzureADWorkloadIdentityToken(ctx context.Context, identityID, resource string) (AADToken, error) {
+	return tokenCache.getOrFetch(aadTokenCacheKey(string(kedav1alpha1.PodIdentityProviderAzureWorkload), identityID, resource), func() (AADToken, error) {
+		return getAzureADWorkloadIdentityToken(ctx, identityID, resource)
+	})
+}
+
+func getAzureADWorkloadIdentityToken(ctx context.Context, identityID, resource string) (AADToken, error) {
 	clientID := os.Getenv(azureClientIDEnv)
 	tenantID := os.Getenv(azureTenantIDEnv)
 	tokenFilePath := os.Getenv(azureFederatedTokenFileEnv)
@@ -68,11 +68,11 @@ func getAuthConfig(ctx context.Context, info AppInsightsInfo, podIdentity kedav1
 		return config
 	case kedav1alpha1.PodIdentityProviderAzure:
 		config := auth.NewMSIConfig()
-		config.Resource = info.AppInsightsResourceURL
+		config.Resource = GetPodIdentityResource(podIdentity, info.AppInsightsResourceURL)
 		config.ClientID = podIdentity.IdentityID
 		return config
 	case kedav1alpha1.PodIdentityProviderAzureWorkload:
-		return NewAzureADWorkloadIdentityConfig(ctx, podIdentity.IdentityID, info.AppInsightsResourceURL)
+		return NewAzureADWorkloadIdentityConfig(ctx, podIdentity.IdentityID, GetPodIdentityResource(podIdentity, info.AppInsightsResourceURL))
 	}
 	return nil
 }
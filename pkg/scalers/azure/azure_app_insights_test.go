@@ -109,6 +109,23 @@ func TestAzAppInfoGetAuthConfig(t *testing.T) {
 	}
 }
 
+func TestAzAppInfoGetAuthConfigAudience(t *testing.T) {
+	info := AppInsightsInfo{AppInsightsResourceURL: DefaultAppInsightsResourceURL}
+
+	msi := getAuthConfig(context.TODO(), info, kedav1alpha1.AuthPodIdentity{Provider: kedav1alpha1.PodIdentityProviderAzure, Audience: "https://api.applicationinsights.azure.us"})
+	if resource := msi.(auth.MSIConfig).Resource; resource != "https://api.applicationinsights.azure.us" {
+		t.Errorf("Expected the audience of the pod identity as resource but got %s", resource)
+	}
+	workload := getAuthConfig(context.TODO(), info, kedav1alpha1.AuthPodIdentity{Provider: kedav1alpha1.PodIdentityProviderAzureWorkload, Audience: "https://api.applicationinsights.azure.us"})
+	if resource := workload.(ADWorkloadIdentityConfig).Resource; resource != "https://api.applicationinsights.azure.us" {
+		t.Errorf("Expected the audience of the workload identity as resource but got %s", resource)
+	}
+	msi = getAuthConfig(context.TODO(), info, kedav1alpha1.AuthPodIdentity{Provider: kedav1alpha1.PodIdentityProviderAzure})
+	if resource := msi.(auth.MSIConfig).Resource; resource != DefaultAppInsightsResourceURL {
+		t.Errorf("Expected the default resource without audience but got %s", resource)
+	}
+}
+
 type toISO8601TestData struct {
 	testName      string
 	isError       bool
@@ -24,26 +24,70 @@ var activeDirectoryEndpointProvider = func(env az.Environment) (string, error) {
 	return env.ActiveDirectoryEndpoint, nil
 }
 
+// CloudEnvironment is the Azure cloud of a trigger, selected through its cloud metadata, along with the
+// metadata which can override the endpoints of the cloud
+type CloudEnvironment struct {
+	// Environment is the well-known cloud, it is empty for a private cloud
+	Environment az.Environment
+	Private     bool
+
+	metadata map[string]string
+}
+
+// ParseCloudEnvironment resolves the cloud of the trigger, the public cloud if cloud isn't set. The names
+// of the well-known clouds are case insensitive and the Cloud suffix is optional, e.g. AzureUSGovernment
+func ParseCloudEnvironment(metadata map[string]string) (*CloudEnvironment, error) {
+	cloud := &CloudEnvironment{Environment: az.PublicCloud, metadata: metadata}
+
+	val, ok := metadata["cloud"]
+	if !ok || val == "" {
+		return cloud, nil
+	}
+	if strings.EqualFold(val, PrivateCloud) {
+		return &CloudEnvironment{Private: true, metadata: metadata}, nil
+	}
+
+	env, err := az.EnvironmentFromName(val)
+	if err != nil && !strings.HasSuffix(strings.ToUpper(val), "CLOUD") {
+		env, err = az.EnvironmentFromName(val + "Cloud")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid cloud environment %s", val)
+	}
+	cloud.Environment = env
+	return cloud, nil
+}
+
+// Endpoint returns the endpoint set in the metadata under propertyKey if any, so a custom FQDN such as a private
+// link can be used in every cloud, otherwise the one of the cloud environment. It must be set for a private cloud.
+func (c *CloudEnvironment) Endpoint(propertyKey string, envPropertyProvider EnvironmentPropertyProvider) (string, error) {
+	if val, ok := c.metadata[propertyKey]; ok && val != "" {
+		return val, nil
+	}
+	if c.Private {
+		return "", fmt.Errorf("%s must be provided for %s cloud type", propertyKey, PrivateCloud)
+	}
+	return envPropertyProvider(c.Environment)
+}
+
 // ParseEnvironmentProperty parses cloud metadata and returns the resolved property
 func ParseEnvironmentProperty(metadata map[string]string, propertyKey string, envPropertyProvider EnvironmentPropertyProvider) (string, error) {
-	if val, ok := metadata["cloud"]; ok && val != "" {
-		if strings.EqualFold(val, PrivateCloud) {
-			if val, ok := metadata[propertyKey]; ok && val != "" {
-				return val, nil
-			}
-			return "", fmt.Errorf("%s must be provided for %s cloud type", propertyKey, PrivateCloud)
-		}
+	cloud, err := ParseCloudEnvironment(metadata)
+	if err != nil {
+		return "", err
+	}
+	return cloud.Endpoint(propertyKey, envPropertyProvider)
+}
 
-		env, err := az.EnvironmentFromName(val)
-		if err != nil {
-			return "", fmt.Errorf("invalid cloud environment %s", val)
+// ResourceURLInCloudProvider returns the provider of a resource URL which differs between the well-known clouds,
+// resourceURLs being keyed by the upper case name of the clouds
+func ResourceURLInCloudProvider(resourceURLs map[string]string) EnvironmentPropertyProvider {
+	return func(env az.Environment) (string, error) {
+		if resourceURL, ok := resourceURLs[strings.ToUpper(env.Name)]; ok {
+			return resourceURL, nil
 		}
-
-		return envPropertyProvider(env)
+		return "", fmt.Errorf("there is no cloud environment matching the name %s", env.Name)
 	}
-
-	// Use public cloud suffix if `cloud` isn't specified
-	return envPropertyProvider(az.PublicCloud)
 }
 
 func ParseActiveDirectoryEndpoint(metadata map[string]string) (string, error) {
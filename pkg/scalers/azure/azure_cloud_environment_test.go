@@ -29,7 +29,10 @@ var parseEnvironmentPropertyTestDataset = []parseEnvironmentPropertyTestData{
 	{map[string]string{"cloud": "AzureGermanCloud"}, "AzureGermanCloud.suffix", DefaultEndpointSuffixKey, testPropertyProvider, false},
 	{map[string]string{"cloud": "Private"}, "", DefaultEndpointSuffixKey, testPropertyProvider, true},
 	{map[string]string{"cloud": "Private", "endpointSuffix": "suffix.private.cloud"}, "suffix.private.cloud", DefaultEndpointSuffixKey, testPropertyProvider, false},
-	{map[string]string{"endpointSuffix": "ignored"}, "AzurePublicCloud.suffix", DefaultEndpointSuffixKey, testPropertyProvider, false},
+	{map[string]string{"endpointSuffix": "privatelink.suffix"}, "privatelink.suffix", DefaultEndpointSuffixKey, testPropertyProvider, false},
+	{map[string]string{"cloud": "AzureGermanCloud", "endpointSuffix": "privatelink.suffix"}, "privatelink.suffix", DefaultEndpointSuffixKey, testPropertyProvider, false},
+	{map[string]string{"cloud": "azurechina"}, "AzureChinaCloud.suffix", DefaultEndpointSuffixKey, testPropertyProvider, false},
+	{map[string]string{"cloud": "AzureUSGovernment"}, "", DefaultEndpointSuffixKey, testPropertyProvider, true},
 	{map[string]string{"cloud": "Private", "endpointSuffixDiff": "suffix.private.cloud"}, "suffix.private.cloud", "endpointSuffixDiff", testPropertyProvider, false},
 }
 
@@ -89,3 +92,21 @@ func TestParseActiveDirectoryEndpoint(t *testing.T) {
 		}
 	}
 }
+
+func TestResourceURLInCloudProvider(t *testing.T) {
+	provider := ResourceURLInCloudProvider(AppInsightsResourceURLInCloud)
+
+	resourceURL, err := ParseEnvironmentProperty(map[string]string{"cloud": "AzureUSGovernment"}, "appInsightsResourceURL", provider)
+	if err != nil || resourceURL != "https://api.applicationinsights.us" {
+		t.Error("Expected the US Government resource URL but got", resourceURL, err)
+	}
+
+	resourceURL, err = ParseEnvironmentProperty(map[string]string{"cloud": "AzureGermanCloud", "appInsightsResourceURL": "https://custom"}, "appInsightsResourceURL", provider)
+	if err != nil || resourceURL != "https://custom" {
+		t.Error("Expected the resource URL of the metadata but got", resourceURL, err)
+	}
+
+	if _, err = ParseEnvironmentProperty(map[string]string{"cloud": "AzureGermanCloud"}, "appInsightsResourceURL", provider); err == nil {
+		t.Error("Expected error for a cloud without resource URL but got success")
+	}
+}
@@ -81,7 +81,7 @@ func getDataExplorerAuthConfig(ctx context.Context, metadata *DataExplorerMetada
 		}
 	case kedav1alpha1.PodIdentityProviderAzure:
 		config := auth.NewMSIConfig()
-		config.Resource = metadata.Endpoint
+		config.Resource = GetPodIdentityResource(metadata.PodIdentity, metadata.Endpoint)
 		config.ClientID = metadata.PodIdentity.IdentityID
 		azureDataExplorerLogger.V(1).Info("Creating Azure Data Explorer Client using Pod Identity")
 
@@ -89,7 +89,7 @@ func getDataExplorerAuthConfig(ctx context.Context, metadata *DataExplorerMetada
 		return authConfig, nil
 	case kedav1alpha1.PodIdentityProviderAzureWorkload:
 		azureDataExplorerLogger.V(1).Info("Creating Azure Data Explorer Client using Workload Identity")
-		authConfig = NewAzureADWorkloadIdentityConfig(ctx, metadata.PodIdentity.IdentityID, metadata.Endpoint)
+		authConfig = NewAzureADWorkloadIdentityConfig(ctx, metadata.PodIdentity.IdentityID, GetPodIdentityResource(metadata.PodIdentity, metadata.Endpoint))
 		return authConfig, nil
 	}
 
@@ -48,7 +48,7 @@ func GetEventHubClient(ctx context.Context, info EventHubInfo) (*eventhub.Hub, e
 		// Since there is no connectionstring, then user wants to use AAD Pod identity
 		// Internally, the JWTProvider will use Managed Service Identity to authenticate if no Service Principal info supplied
 		envJWTProviderOption := aad.JWTProviderWithAzureEnvironment(&env)
-		resourceURLJWTProviderOption := aad.JWTProviderWithResourceURI(info.EventHubResourceURL)
+		resourceURLJWTProviderOption := aad.JWTProviderWithResourceURI(GetPodIdentityResource(info.PodIdentity, info.EventHubResourceURL))
 		clientIDJWTProviderOption := func(config *aad.TokenProviderConfiguration) error {
 			config.ClientID = info.PodIdentity.IdentityID
 			return nil
@@ -65,7 +65,7 @@ func GetEventHubClient(ctx context.Context, info EventHubInfo) (*eventhub.Hub, e
 		// User wants to use AAD Workload Identity
 		env := azure.Environment{ActiveDirectoryEndpoint: info.ActiveDirectoryEndpoint, ServiceBusEndpointSuffix: info.ServiceBusEndpointSuffix}
 		hubEnvOptions := eventhub.HubWithEnvironment(env)
-		provider := NewAzureADWorkloadIdentityTokenProvider(ctx, info.PodIdentity.IdentityID, info.EventHubResourceURL)
+		provider := NewAzureADWorkloadIdentityTokenProvider(ctx, info.PodIdentity.IdentityID, GetPodIdentityResource(info.PodIdentity, info.EventHubResourceURL))
 
 		return eventhub.NewHub(info.Namespace, info.EventHubName, provider, hubEnvOptions)
 	}
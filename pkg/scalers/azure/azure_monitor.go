@@ -88,12 +88,12 @@ func createMetricsClient(ctx context.Context, info MonitorInfo, podIdentity keda
 		authConfig = config
 	case kedav1alpha1.PodIdentityProviderAzure:
 		config := auth.NewMSIConfig()
-		config.Resource = info.AzureResourceManagerEndpoint
+		config.Resource = GetPodIdentityResource(podIdentity, info.AzureResourceManagerEndpoint)
 		config.ClientID = podIdentity.IdentityID
 
 		authConfig = config
 	case kedav1alpha1.PodIdentityProviderAzureWorkload:
-		authConfig = NewAzureADWorkloadIdentityConfig(ctx, podIdentity.IdentityID, info.AzureResourceManagerEndpoint)
+		authConfig = NewAzureADWorkloadIdentityConfig(ctx, podIdentity.IdentityID, GetPodIdentityResource(podIdentity, info.AzureResourceManagerEndpoint))
 	}
 
 	authorizer, _ := authConfig.Authorizer()
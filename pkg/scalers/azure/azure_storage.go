@@ -197,11 +197,12 @@ func parseAcessTokenAndEndpoint(ctx context.Context, httpClient util.HTTPDoer, a
 	var token AADToken
 	var err error
 
+	resource := GetPodIdentityResource(podIdentity, storageResource)
 	switch podIdentity.Provider {
 	case kedav1alpha1.PodIdentityProviderAzure:
-		token, err = GetAzureADPodIdentityToken(ctx, httpClient, podIdentity.IdentityID, storageResource)
+		token, err = GetAzureADPodIdentityToken(ctx, httpClient, podIdentity.IdentityID, resource)
 	case kedav1alpha1.PodIdentityProviderAzureWorkload:
-		token, err = GetAzureADWorkloadIdentityToken(ctx, podIdentity.IdentityID, storageResource)
+		token, err = GetAzureADWorkloadIdentityToken(ctx, podIdentity.IdentityID, resource)
 	}
 
 	if err != nil {
@@ -74,7 +74,7 @@ var parseAzureStorageEndpointSuffixTestDataset = []parseAzureStorageEndpointSuff
 	{map[string]string{"cloud": "AzureUSGovernmentCloud"}, "queue.core.usgovcloudapi.net", QueueEndpoint, false},
 	{map[string]string{"cloud": "Private"}, "", BlobEndpoint, true},
 	{map[string]string{"cloud": "Private", "endpointSuffix": "blob.core.private.cloud"}, "blob.core.private.cloud", BlobEndpoint, false},
-	{map[string]string{"endpointSuffix": "ignored"}, "blob.core.windows.net", BlobEndpoint, false},
+	{map[string]string{"endpointSuffix": "privatelink.blob.core.windows.net"}, "privatelink.blob.core.windows.net", BlobEndpoint, false},
 }
 
 func TestParseAzureStorageEndpointSuffix(t *testing.T) {
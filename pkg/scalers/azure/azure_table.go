@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	tableServiceVersion = "2019-02-02"
+	// tablePageSize is the maximum number of entities returned by a query of the Table service
+	tablePageSize = "1000"
+)
+
+type TableMetadata struct {
+	TableName      string
+	Filter         string
+	Connection     string
+	AccountName    string
+	EndpointSuffix string
+}
+
+// tableCredential authorizes the requests to the Table service, with the shared key of the account or an access token
+type tableCredential struct {
+	accountName string
+	accountKey  []byte
+	token       string
+}
+
+// GetAzureTableEntityCount returns the count of the entities of a table matching the OData filter, paging through
+// the query. The tables of the storage accounts and of the Cosmos DB Table API accounts are supported.
+func GetAzureTableEntityCount(ctx context.Context, httpClient util.HTTPDoer, podIdentity kedav1alpha1.AuthPodIdentity, meta *TableMetadata) (int64, error) {
+	credential, endpoint, err := parseAzureStorageTableConnection(ctx, httpClient, podIdentity, meta.Connection, meta.AccountName, meta.EndpointSuffix)
+	if err != nil {
+		return -1, err
+	}
+
+	queryURL := *endpoint
+	queryURL.Path = fmt.Sprintf("%s/%s()", strings.TrimSuffix(queryURL.Path, "/"), meta.TableName)
+	query := url.Values{}
+	if meta.Filter != "" {
+		query.Set("$filter", meta.Filter)
+	}
+	query.Set("$select", "PartitionKey")
+	query.Set("$top", tablePageSize)
+
+	var count int64
+	for {
+		queryURL.RawQuery = query.Encode()
+		entities, nextPartitionKey, nextRowKey, err := queryAzureTable(ctx, httpClient, credential, &queryURL)
+		if err != nil {
+			return -1, err
+		}
+		count += entities
+
+		if nextPartitionKey == "" && nextRowKey == "" {
+			return count, nil
+		}
+		query.Set("NextPartitionKey", nextPartitionKey)
+		query.Set("NextRowKey", nextRowKey)
+	}
+}
+
+// parseAzureStorageTableConnection parses table connection string and returns credential and resource url
+func parseAzureStorageTableConnection(ctx context.Context, httpClient util.HTTPDoer, podIdentity kedav1alpha1.AuthPodIdentity, connectionString, accountName, endpointSuffix string) (*tableCredential, *url.URL, error) {
+	switch podIdentity.Provider {
+	case kedav1alpha1.PodIdentityProviderAzure, kedav1alpha1.PodIdentityProviderAzureWorkload:
+		token, endpoint, err := parseAcessTokenAndEndpoint(ctx, httpClient, accountName, endpointSuffix, podIdentity)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &tableCredential{token: token}, endpoint, nil
+	case "", kedav1alpha1.PodIdentityProviderNone:
+		endpoint, accountName, accountKey, err := parseAzureStorageConnectionString(connectionString, TableEndpoint)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key, err := base64.StdEncoding.DecodeString(accountKey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &tableCredential{accountName: accountName, accountKey: key}, endpoint, nil
+	default:
+		return nil, nil, fmt.Errorf("azure tables doesn't support %s pod identity type", podIdentity)
+	}
+}
+
+// queryAzureTable returns the count of the entities of a page of the query and the continuation of the query
+func queryAzureTable(ctx context.Context, httpClient util.HTTPDoer, credential *tableCredential, queryURL *url.URL) (int64, string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, queryURL.String(), nil)
+	if err != nil {
+		return -1, "", "", err
+	}
+	date := time.Now().UTC().Format(http.TimeFormat)
+	req.Header.Set("x-ms-date", date)
+	req.Header.Set("x-ms-version", tableServiceVersion)
+	req.Header.Set("Accept", "application/json;odata=nometadata")
+	req.Header.Set("DataServiceVersion", "3.0;NetFx")
+	req.Header.Set("MaxDataServiceVersion", "3.0;NetFx")
+	req.Header.Set("Authorization", credential.authorization(date, queryURL.EscapedPath()))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return -1, "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return -1, "", "", fmt.Errorf("error querying azure table: %s %s", resp.Status, body)
+	}
+
+	var page struct {
+		Value []json.RawMessage `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return -1, "", "", err
+	}
+
+	return int64(len(page.Value)), resp.Header.Get("x-ms-continuation-NextPartitionKey"), resp.Header.Get("x-ms-continuation-NextRowKey"), nil
+}
+
+// authorization returns the Authorization header of a request, signed with the Shared Key Lite scheme of the Table
+// service when the account key is given
+func (c *tableCredential) authorization(date, path string) string {
+	if c.token != "" {
+		return fmt.Sprintf("Bearer %s", c.token)
+	}
+
+	stringToSign := fmt.Sprintf("%s\n/%s%s", date, c.accountName, path)
+	mac := hmac.New(sha256.New, c.accountKey)
+	mac.Write([]byte(stringToSign))
+	return fmt.Sprintf("SharedKeyLite %s:%s", c.accountName, base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+}
@@ -0,0 +1,102 @@
+package azure
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+func TestGetAzureTableEntityCountConnectionErrors(t *testing.T) {
+	count, err := GetAzureTableEntityCount(context.TODO(), http.DefaultClient, kedav1alpha1.AuthPodIdentity{}, &TableMetadata{TableName: "ledger"})
+	assert.Equal(t, int64(-1), count)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "parse storage connection string")
+
+	count, err = GetAzureTableEntityCount(context.TODO(), http.DefaultClient, kedav1alpha1.AuthPodIdentity{},
+		&TableMetadata{TableName: "ledger", Connection: "DefaultEndpointsProtocol=https;AccountName=name;AccountKey=key==;EndpointSuffix=core.windows.net"})
+	assert.Equal(t, int64(-1), count)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "illegal base64")
+}
+
+func TestGetAzureTableEntityCount(t *testing.T) {
+	accountKey := base64.StdEncoding.EncodeToString([]byte("key"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mac := hmac.New(sha256.New, []byte("key"))
+		mac.Write([]byte(fmt.Sprintf("%s\n/name%s", r.Header.Get("x-ms-date"), r.URL.EscapedPath())))
+		if r.Header.Get("Authorization") != "SharedKeyLite name:"+base64.StdEncoding.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/ledger()" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"odata.error":{"code":"TableNotFound"}}`)
+			return
+		}
+
+		query := r.URL.Query()
+		if query.Get("$filter") != "PartitionKey eq 'pending'" || query.Get("$select") != "PartitionKey" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// two pages of entities
+		if query.Get("NextPartitionKey") == "" {
+			w.Header().Set("x-ms-continuation-NextPartitionKey", "1!12!cGVuZGluZw--")
+			w.Header().Set("x-ms-continuation-NextRowKey", "1!8!MDAwMw--")
+			fmt.Fprint(w, `{"value":[{"PartitionKey":"pending"},{"PartitionKey":"pending"},{"PartitionKey":"pending"}]}`)
+			return
+		}
+		if query.Get("NextRowKey") != "1!8!MDAwMw--" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"value":[{"PartitionKey":"pending"},{"PartitionKey":"pending"}]}`)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		tableName string
+		key       string
+		count     int64
+		isError   bool
+	}{
+		{"ledger", accountKey, 5, false},
+		{"missing", accountKey, -1, true},
+		{"ledger", base64.StdEncoding.EncodeToString([]byte("wrong")), -1, true},
+	}
+
+	for _, testCase := range testCases {
+		meta := &TableMetadata{
+			TableName:  testCase.tableName,
+			Filter:     "PartitionKey eq 'pending'",
+			Connection: fmt.Sprintf("DefaultEndpointsProtocol=http;AccountName=name;AccountKey=%s;TableEndpoint=%s/", testCase.key, server.URL),
+		}
+		count, err := GetAzureTableEntityCount(context.TODO(), http.DefaultClient, kedav1alpha1.AuthPodIdentity{}, meta)
+		assert.Equal(t, testCase.count, count)
+		if testCase.isError {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestAzureTableCredential(t *testing.T) {
+	credential, endpoint, err := parseAzureStorageTableConnection(context.TODO(), http.DefaultClient, kedav1alpha1.AuthPodIdentity{Provider: kedav1alpha1.PodIdentityProviderAwsEKS}, "", "name", "table.core.windows.net")
+	assert.Nil(t, credential)
+	assert.Nil(t, endpoint)
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "doesn't support"))
+
+	assert.Equal(t, "Bearer token", (&tableCredential{token: "token"}).authorization("", "/ledger()"))
+}
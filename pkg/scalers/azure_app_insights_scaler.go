@@ -44,12 +44,12 @@ type azureAppInsightsScaler struct {
 func NewAzureAppInsightsScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseAzureAppInsightsMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing azure app insights metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing azure app insights metadata: %s", err))
 	}
 
 	return &azureAppInsightsScaler{
@@ -102,21 +102,11 @@ func parseAzureAppInsightsMetadata(config *ScalerConfig) (*azureAppInsightsMetad
 		meta.azureAppInsightsInfo.Filter = ""
 	}
 
-	meta.azureAppInsightsInfo.AppInsightsResourceURL = azure.DefaultAppInsightsResourceURL
-
-	if cloud, ok := config.TriggerMetadata["cloud"]; ok {
-		if strings.EqualFold(cloud, azure.PrivateCloud) {
-			if resource, ok := config.TriggerMetadata["appInsightsResourceURL"]; ok && resource != "" {
-				meta.azureAppInsightsInfo.AppInsightsResourceURL = resource
-			} else {
-				return nil, fmt.Errorf("appInsightsResourceURL must be provided for %s cloud type", azure.PrivateCloud)
-			}
-		} else if resource, ok := azure.AppInsightsResourceURLInCloud[strings.ToUpper(cloud)]; ok {
-			meta.azureAppInsightsInfo.AppInsightsResourceURL = resource
-		} else {
-			return nil, fmt.Errorf("there is no cloud environment matching the name %s", cloud)
-		}
+	appInsightsResourceURL, err := azure.ParseEnvironmentProperty(config.TriggerMetadata, "appInsightsResourceURL", azure.ResourceURLInCloudProvider(azure.AppInsightsResourceURLInCloud))
+	if err != nil {
+		return nil, err
 	}
+	meta.azureAppInsightsInfo.AppInsightsResourceURL = appInsightsResourceURL
 
 	activeDirectoryEndpoint, err := azure.ParseActiveDirectoryEndpoint(config.TriggerMetadata)
 	if err != nil {
@@ -53,19 +53,19 @@ var azureBlobLog = logf.Log.WithName("azure_blob_scaler")
 func NewAzureBlobScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, podIdentity, err := parseAzureBlobMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing azure blob metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing azure blob metadata: %s", err))
 	}
 
 	return &azureBlobScaler{
 		metricType:  metricType,
 		metadata:    meta,
 		podIdentity: podIdentity,
-		httpClient:  kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, false),
+		httpClient:  createHTTPClient(config, config.GlobalHTTPTimeout, false),
 	}, nil
 }
 
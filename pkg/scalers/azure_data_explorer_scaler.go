@@ -47,12 +47,12 @@ var dataExplorerLogger = logf.Log.WithName("azure_data_explorer_scaler")
 func NewAzureDataExplorerScaler(ctx context.Context, config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	metadata, err := parseAzureDataExplorerMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse azure data explorer metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("failed to parse azure data explorer metadata: %s", err))
 	}
 
 	client, err := azure.CreateAzureDataExplorerClient(ctx, metadata)
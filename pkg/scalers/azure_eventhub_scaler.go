@@ -23,7 +23,6 @@ import (
 	"math"
 	"net/http"
 	"strconv"
-	"strings"
 
 	eventhub "github.com/Azure/azure-event-hubs-go/v3"
 	"github.com/Azure/azure-storage-blob-go/azblob"
@@ -66,12 +65,12 @@ type eventHubMetadata struct {
 func NewAzureEventHubScaler(ctx context.Context, config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	parsedMetadata, err := parseAzureEventHubMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get eventhub metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("unable to get eventhub metadata: %s", err))
 	}
 
 	hub, err := azure.GetEventHubClient(ctx, parsedMetadata.eventHubInfo)
@@ -83,7 +82,7 @@ func NewAzureEventHubScaler(ctx context.Context, config *ScalerConfig) (Scaler,
 		metricType: metricType,
 		metadata:   parsedMetadata,
 		client:     hub,
-		httpClient: kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, false),
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, false),
 	}, nil
 }
 
@@ -128,16 +127,15 @@ func parseAzureEventHubMetadata(config *ScalerConfig) (*eventHubMetadata, error)
 		meta.eventHubInfo.BlobContainer = val
 	}
 
-	meta.eventHubInfo.EventHubResourceURL = azure.DefaultEventhubResourceURL
-	if val, ok := config.TriggerMetadata["cloud"]; ok {
-		if strings.EqualFold(val, azure.PrivateCloud) {
-			if resourceURL, ok := config.TriggerMetadata["eventHubResourceURL"]; ok {
-				meta.eventHubInfo.EventHubResourceURL = resourceURL
-			} else {
-				return nil, fmt.Errorf("eventHubResourceURL must be provided for %s cloud type", azure.PrivateCloud)
-			}
-		}
+	// Event Hubs resource id is "https://eventhubs.azure.net/" in all cloud environments
+	eventHubResourceURLProvider := func(env az.Environment) (string, error) {
+		return azure.DefaultEventhubResourceURL, nil
+	}
+	eventHubResourceURL, err := azure.ParseEnvironmentProperty(config.TriggerMetadata, "eventHubResourceURL", eventHubResourceURLProvider)
+	if err != nil {
+		return nil, err
 	}
+	meta.eventHubInfo.EventHubResourceURL = eventHubResourceURL
 
 	serviceBusEndpointSuffixProvider := func(env az.Environment) (string, error) {
 		return env.ServiceBusEndpointSuffix, nil
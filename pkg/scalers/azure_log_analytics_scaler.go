@@ -43,9 +43,8 @@ import (
 )
 
 const (
-	aadTokenEndpoint               = "%s/%s/oauth2/token"
-	laQueryEndpoint                = "%s/v1/workspaces/%s/query"
-	defaultLogAnalyticsResourceURL = "https://api.loganalytics.io"
+	aadTokenEndpoint = "%s/%s/oauth2/token"
+	laQueryEndpoint  = "%s/v1/workspaces/%s/query"
 )
 
 type azureLogAnalyticsScaler struct {
@@ -120,12 +119,12 @@ var logAnalyticsResourceURLInCloud = map[string]string{
 func NewAzureLogAnalyticsScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	azureLogAnalyticsMetadata, err := parseAzureLogAnalyticsMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize Log Analytics scaler. Scaled object: %s. Namespace: %s. Inner Error: %v", config.Name, config.Namespace, err)
+		return nil, NewPermanentError(fmt.Errorf("failed to initialize Log Analytics scaler. Scaled object: %s. Namespace: %s. Inner Error: %v", config.Name, config.Namespace, err))
 	}
 
 	return &azureLogAnalyticsScaler{
@@ -134,7 +133,7 @@ func NewAzureLogAnalyticsScaler(config *ScalerConfig) (Scaler, error) {
 		cache:      &sessionCache{metricValue: -1, metricThreshold: -1},
 		name:       config.Name,
 		namespace:  config.Namespace,
-		httpClient: kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, false),
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, false),
 	}, nil
 }
 
@@ -204,20 +203,11 @@ func parseAzureLogAnalyticsMetadata(config *ScalerConfig) (*azureLogAnalyticsMet
 
 	meta.scalerIndex = config.ScalerIndex
 
-	meta.logAnalyticsResourceURL = defaultLogAnalyticsResourceURL
-	if cloud, ok := config.TriggerMetadata["cloud"]; ok {
-		if strings.EqualFold(cloud, azure.PrivateCloud) {
-			if resource, ok := config.TriggerMetadata["logAnalyticsResourceURL"]; ok && resource != "" {
-				meta.logAnalyticsResourceURL = resource
-			} else {
-				return nil, fmt.Errorf("logAnalyticsResourceURL must be provided for %s cloud type", azure.PrivateCloud)
-			}
-		} else if resource, ok := logAnalyticsResourceURLInCloud[strings.ToUpper(cloud)]; ok {
-			meta.logAnalyticsResourceURL = resource
-		} else {
-			return nil, fmt.Errorf("there is no cloud environment matching the name %s", cloud)
-		}
+	logAnalyticsResourceURL, err := azure.ParseEnvironmentProperty(config.TriggerMetadata, "logAnalyticsResourceURL", azure.ResourceURLInCloudProvider(logAnalyticsResourceURLInCloud))
+	if err != nil {
+		return nil, err
 	}
+	meta.logAnalyticsResourceURL = logAnalyticsResourceURL
 
 	activeDirectoryEndpoint, err := azure.ParseActiveDirectoryEndpoint(config.TriggerMetadata)
 	if err != nil {
@@ -323,6 +313,16 @@ func (s *azureLogAnalyticsScaler) getMetricData(ctx context.Context) (metricsDat
 	return metricsInfo, nil
 }
 
+// podIdentityResource returns the resource of the pod identity tokens, the audience of the trigger overrides it
+func (s *azureLogAnalyticsScaler) podIdentityResource() string {
+	return azure.GetPodIdentityResource(s.metadata.podIdentity, s.metadata.logAnalyticsResourceURL)
+}
+
+// podIdentityCacheKey separates the cached pod identity tokens of the identities and the audiences of the triggers
+func (s *azureLogAnalyticsScaler) podIdentityCacheKey() string {
+	return fmt.Sprintf("%s|%s", s.metadata.podIdentity.IdentityID, s.podIdentityResource())
+}
+
 func (s *azureLogAnalyticsScaler) getAccessToken(ctx context.Context) (tokenData, error) {
 	// if there is no token yet or it will be expired in less, that 30 secs
 	currentTimeSec := time.Now().Unix()
@@ -332,7 +332,7 @@ func (s *azureLogAnalyticsScaler) getAccessToken(ctx context.Context) (tokenData
 	case "", kedav1alpha1.PodIdentityProviderNone:
 		tokenInfo, _ = getTokenFromCache(s.metadata.clientID, s.metadata.clientSecret)
 	case kedav1alpha1.PodIdentityProviderAzure, kedav1alpha1.PodIdentityProviderAzureWorkload:
-		tokenInfo, _ = getTokenFromCache(string(s.metadata.podIdentity.Provider), string(s.metadata.podIdentity.Provider))
+		tokenInfo, _ = getTokenFromCache(string(s.metadata.podIdentity.Provider), s.podIdentityCacheKey())
 	}
 
 	if currentTimeSec+30 > tokenInfo.ExpiresOn {
@@ -347,7 +347,7 @@ func (s *azureLogAnalyticsScaler) getAccessToken(ctx context.Context) (tokenData
 			_ = setTokenInCache(s.metadata.clientID, s.metadata.clientSecret, newTokenInfo)
 		case kedav1alpha1.PodIdentityProviderAzure, kedav1alpha1.PodIdentityProviderAzureWorkload:
 			logAnalyticsLog.V(1).Info("Token for Pod Identity has been refreshed", "type", s.metadata.podIdentity, "scaler name", s.name, "namespace", s.namespace)
-			_ = setTokenInCache(string(s.metadata.podIdentity.Provider), string(s.metadata.podIdentity.Provider), newTokenInfo)
+			_ = setTokenInCache(string(s.metadata.podIdentity.Provider), s.podIdentityCacheKey(), newTokenInfo)
 		}
 
 		return newTokenInfo, nil
@@ -376,7 +376,7 @@ func (s *azureLogAnalyticsScaler) executeQuery(ctx context.Context, query string
 			_ = setTokenInCache(s.metadata.clientID, s.metadata.clientSecret, tokenInfo)
 		case kedav1alpha1.PodIdentityProviderAzure, kedav1alpha1.PodIdentityProviderAzureWorkload:
 			logAnalyticsLog.V(1).Info("Token for Pod Identity has been refreshed", "type", s.metadata.podIdentity, "scaler name", s.name, "namespace", s.namespace)
-			_ = setTokenInCache(string(s.metadata.podIdentity.Provider), string(s.metadata.podIdentity.Provider), tokenInfo)
+			_ = setTokenInCache(string(s.metadata.podIdentity.Provider), s.podIdentityCacheKey(), tokenInfo)
 		}
 
 		if err == nil {
@@ -498,7 +498,7 @@ func (s *azureLogAnalyticsScaler) getAuthorizationToken(ctx context.Context) (to
 
 	switch s.metadata.podIdentity.Provider {
 	case kedav1alpha1.PodIdentityProviderAzureWorkload:
-		aadToken, err := azure.GetAzureADWorkloadIdentityToken(ctx, s.metadata.podIdentity.IdentityID, s.metadata.logAnalyticsResourceURL)
+		aadToken, err := azure.GetAzureADWorkloadIdentityToken(ctx, s.metadata.podIdentity.IdentityID, s.podIdentityResource())
 		if err != nil {
 			return tokenData{}, nil
 		}
@@ -512,7 +512,7 @@ func (s *azureLogAnalyticsScaler) getAuthorizationToken(ctx context.Context) (to
 			TokenType:               string(auth.CBSTokenTypeJWT),
 			AccessToken:             aadToken.AccessToken,
 			ExpiresOn:               expiresOn,
-			Resource:                s.metadata.logAnalyticsResourceURL,
+			Resource:                s.podIdentityResource(),
 			IsWorkloadIdentityToken: true,
 		}
 
@@ -584,9 +584,9 @@ func (s *azureLogAnalyticsScaler) executeAADApicall(ctx context.Context) ([]byte
 func (s *azureLogAnalyticsScaler) executeIMDSApicall(ctx context.Context) ([]byte, int, error) {
 	var urlStr string
 	if s.metadata.podIdentity.IdentityID == "" {
-		urlStr = fmt.Sprintf(azure.MSIURL, s.metadata.logAnalyticsResourceURL)
+		urlStr = fmt.Sprintf(azure.MSIURL, s.podIdentityResource())
 	} else {
-		urlStr = fmt.Sprintf(azure.MSIURLWithClientID, s.metadata.logAnalyticsResourceURL, url.QueryEscape(s.metadata.podIdentity.IdentityID))
+		urlStr = fmt.Sprintf(azure.MSIURLWithClientID, s.podIdentityResource(), url.QueryEscape(s.metadata.podIdentity.IdentityID))
 	}
 
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
@@ -239,3 +239,20 @@ func TestLogAnalyticsParseMetadataMetricName(t *testing.T) {
 		}
 	}
 }
+
+func TestLogAnalyticsPodIdentityAudience(t *testing.T) {
+	podIdentity := kedav1alpha1.AuthPodIdentity{Provider: kedav1alpha1.PodIdentityProviderAzureWorkload, IdentityID: "identity"}
+	scaler := &azureLogAnalyticsScaler{metadata: &azureLogAnalyticsMetadata{podIdentity: podIdentity, logAnalyticsResourceURL: "https://api.loganalytics.io"}}
+	if resource := scaler.podIdentityResource(); resource != "https://api.loganalytics.io" {
+		t.Errorf("Expected the default resource without audience but got %s", resource)
+	}
+
+	podIdentity.Audience = "https://api.loganalytics.us"
+	overridden := &azureLogAnalyticsScaler{metadata: &azureLogAnalyticsMetadata{podIdentity: podIdentity, logAnalyticsResourceURL: "https://api.loganalytics.io"}}
+	if resource := overridden.podIdentityResource(); resource != "https://api.loganalytics.us" {
+		t.Errorf("Expected the audience of the pod identity as resource but got %s", resource)
+	}
+	if overridden.podIdentityCacheKey() == scaler.podIdentityCacheKey() {
+		t.Error("Expected the tokens of different audiences to be cached separately")
+	}
+}
@@ -56,12 +56,12 @@ var azureMonitorLog = logf.Log.WithName("azure_monitor_scaler")
 func NewAzureMonitorScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseAzureMonitorMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing azure monitor metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing azure monitor metadata: %s", err))
 	}
 
 	return &azureMonitorScaler{
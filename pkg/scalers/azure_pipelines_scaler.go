@@ -50,16 +50,16 @@ var azurePipelinesLog = logf.Log.WithName("azure_pipelines_scaler")
 
 // NewAzurePipelinesScaler creates a new AzurePipelinesScaler
 func NewAzurePipelinesScaler(ctx context.Context, config *ScalerConfig) (Scaler, error) {
-	httpClient := kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, false)
+	httpClient := createHTTPClient(config, config.GlobalHTTPTimeout, false)
 
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseAzurePipelinesMetadata(ctx, config, httpClient)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing azure Pipelines metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing azure Pipelines metadata: %s", err))
 	}
 
 	return &azurePipelinesScaler{
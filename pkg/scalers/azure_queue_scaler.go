@@ -60,19 +60,19 @@ var azureQueueLog = logf.Log.WithName("azure_queue_scaler")
 func NewAzureQueueScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, podIdentity, err := parseAzureQueueMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing azure queue metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing azure queue metadata: %s", err))
 	}
 
 	return &azureQueueScaler{
 		metricType:  metricType,
 		metadata:    meta,
 		podIdentity: podIdentity,
-		httpClient:  kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, false),
+		httpClient:  createHTTPClient(config, config.GlobalHTTPTimeout, false),
 	}, nil
 }
 
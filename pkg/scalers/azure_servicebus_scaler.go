@@ -73,12 +73,12 @@ type azureServiceBusMetadata struct {
 func NewAzureServiceBusScaler(ctx context.Context, config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseAzureServiceBusMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing azure service bus metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing azure service bus metadata: %s", err))
 	}
 
 	return &azureServiceBusScaler{
@@ -86,7 +86,7 @@ func NewAzureServiceBusScaler(ctx context.Context, config *ScalerConfig) (Scaler
 		metricType:  metricType,
 		metadata:    meta,
 		podIdentity: config.PodIdentity,
-		httpClient:  kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, false),
+		httpClient:  createHTTPClient(config, config.GlobalHTTPTimeout, false),
 	}, nil
 }
 
@@ -232,11 +232,12 @@ func (a azureTokenProvider) GetToken(uri string) (*auth.Token, error) {
 	var token azure.AADToken
 	var err error
 
+	resource := azure.GetPodIdentityResource(a.podIdentity, serviceBusResource)
 	switch a.podIdentity.Provider {
 	case kedav1alpha1.PodIdentityProviderAzure:
-		token, err = azure.GetAzureADPodIdentityToken(ctx, a.httpClient, a.podIdentity.IdentityID, serviceBusResource)
+		token, err = azure.GetAzureADPodIdentityToken(ctx, a.httpClient, a.podIdentity.IdentityID, resource)
 	case kedav1alpha1.PodIdentityProviderAzureWorkload:
-		token, err = azure.GetAzureADWorkloadIdentityToken(ctx, a.podIdentity.IdentityID, serviceBusResource)
+		token, err = azure.GetAzureADWorkloadIdentityToken(ctx, a.podIdentity.IdentityID, resource)
 	default:
 		err = fmt.Errorf("unknown pod identity provider")
 	}
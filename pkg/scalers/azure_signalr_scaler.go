@@ -0,0 +1,222 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	az "github.com/Azure/go-autorest/autorest/azure"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/kedacore/keda/v2/pkg/scalers/azure"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	azureSignalRResourceType    = "Microsoft.SignalRService/SignalR"
+	azureSignalRConnectionCount = "ConnectionCount"
+
+	defaultAzureSignalRTargetConnections   = 1000
+	defaultAzureSignalRAggregationInterval = "0:5:0"
+
+	azureSignalREndpointClient = "client"
+	azureSignalREndpointServer = "server"
+	azureSignalREndpointAll    = "all"
+)
+
+type azureSignalRScaler struct {
+	metricType  v2beta2.MetricTargetType
+	metadata    *azureSignalRMetadata
+	podIdentity kedav1alpha1.AuthPodIdentity
+}
+
+type azureSignalRMetadata struct {
+	azureMonitorInfo            azure.MonitorInfo
+	signalRName                 string
+	endpoint                    string
+	targetConnections           float64
+	activationTargetConnections float64
+	scalerIndex                 int
+}
+
+var azureSignalRLog = logf.Log.WithName("azure_signalr_scaler")
+
+// NewAzureSignalRScaler creates a new azureSignalRScaler, reading the connections of an Azure SignalR Service from
+// the ConnectionCount metric of Azure Monitor
+func NewAzureSignalRScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseAzureSignalRMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing azure signalr metadata: %s", err))
+	}
+
+	return &azureSignalRScaler{
+		metricType:  metricType,
+		metadata:    meta,
+		podIdentity: config.PodIdentity,
+	}, nil
+}
+
+func parseAzureSignalRMetadata(config *ScalerConfig) (*azureSignalRMetadata, error) {
+	meta := azureSignalRMetadata{
+		endpoint:          azureSignalREndpointClient,
+		targetConnections: defaultAzureSignalRTargetConnections,
+		azureMonitorInfo: azure.MonitorInfo{
+			Name:                azureSignalRConnectionCount,
+			AggregationType:     "Maximum",
+			AggregationInterval: defaultAzureSignalRAggregationInterval,
+		},
+	}
+
+	if val, ok := config.TriggerMetadata["signalRName"]; ok && val != "" {
+		meta.signalRName = val
+	} else {
+		return nil, fmt.Errorf("no signalRName given")
+	}
+	meta.azureMonitorInfo.ResourceURI = fmt.Sprintf("%s/%s", azureSignalRResourceType, meta.signalRName)
+
+	if val, ok := config.TriggerMetadata["resourceGroupName"]; ok && val != "" {
+		meta.azureMonitorInfo.ResourceGroupName = val
+	} else {
+		return nil, fmt.Errorf("no resourceGroupName given")
+	}
+
+	if val, ok := config.TriggerMetadata["subscriptionId"]; ok && val != "" {
+		meta.azureMonitorInfo.SubscriptionID = val
+	} else {
+		return nil, fmt.Errorf("no subscriptionId given")
+	}
+
+	if val, ok := config.TriggerMetadata["tenantId"]; ok && val != "" {
+		meta.azureMonitorInfo.TenantID = val
+	} else {
+		return nil, fmt.Errorf("no tenantId given")
+	}
+
+	// the connections of the clients or of the app servers, a dimension of ConnectionCount
+	if val, ok := config.TriggerMetadata["endpoint"]; ok && val != "" {
+		meta.endpoint = strings.ToLower(strings.TrimSpace(val))
+	}
+	switch meta.endpoint {
+	case azureSignalREndpointClient:
+		meta.azureMonitorInfo.Filter = "Endpoint eq 'Client'"
+	case azureSignalREndpointServer:
+		meta.azureMonitorInfo.Filter = "Endpoint eq 'Server'"
+	case azureSignalREndpointAll:
+	default:
+		return nil, fmt.Errorf("err incorrect value for endpoint is given: %s", meta.endpoint)
+	}
+
+	if val, ok := config.TriggerMetadata["metricAggregationInterval"]; ok && val != "" {
+		if len(strings.Split(val, ":")) != 3 {
+			return nil, fmt.Errorf("metricAggregationInterval not in the correct format. Should be hh:mm:ss")
+		}
+		meta.azureMonitorInfo.AggregationInterval = val
+	}
+
+	if val, ok := config.TriggerMetadata["targetConnections"]; ok && val != "" {
+		targetConnections, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetConnections parsing error %s", err.Error())
+		}
+		meta.targetConnections = targetConnections
+	}
+
+	if val, ok := config.TriggerMetadata["activationTargetConnections"]; ok && val != "" {
+		activationTargetConnections, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationTargetConnections parsing error %s", err.Error())
+		}
+		meta.activationTargetConnections = activationTargetConnections
+	}
+
+	clientID, clientPassword, err := parseAzurePodIdentityParams(config)
+	if err != nil {
+		return nil, err
+	}
+	meta.azureMonitorInfo.ClientID = clientID
+	meta.azureMonitorInfo.ClientPassword = clientPassword
+
+	azureResourceManagerEndpointProvider := func(env az.Environment) (string, error) {
+		return env.ResourceManagerEndpoint, nil
+	}
+	azureResourceManagerEndpoint, err := azure.ParseEnvironmentProperty(config.TriggerMetadata, "azureResourceManagerEndpoint", azureResourceManagerEndpointProvider)
+	if err != nil {
+		return nil, err
+	}
+	meta.azureMonitorInfo.AzureResourceManagerEndpoint = azureResourceManagerEndpoint
+
+	activeDirectoryEndpoint, err := azure.ParseActiveDirectoryEndpoint(config.TriggerMetadata)
+	if err != nil {
+		return nil, err
+	}
+	meta.azureMonitorInfo.ActiveDirectoryEndpoint = activeDirectoryEndpoint
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// IsActive returns true if there are more connections than activationTargetConnections
+func (s *azureSignalRScaler) IsActive(ctx context.Context) (bool, error) {
+	connections, err := azure.GetAzureMetricValue(ctx, s.metadata.azureMonitorInfo, s.podIdentity)
+	if err != nil {
+		azureSignalRLog.Error(err, "error getting azure signalr connections")
+		return false, err
+	}
+
+	return connections > s.metadata.activationTargetConnections, nil
+}
+
+func (s *azureSignalRScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *azureSignalRScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("azure-signalr-%s-%s", s.metadata.signalRName, s.metadata.endpoint))),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetConnections),
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the connections of the service
+func (s *azureSignalRScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	connections, err := azure.GetAzureMetricValue(ctx, s.metadata.azureMonitorInfo, s.podIdentity)
+	if err != nil {
+		azureSignalRLog.Error(err, "error getting azure signalr connections")
+		return []external_metrics.ExternalMetricValue{}, err
+	}
+
+	metric := GenerateMetricInMili(metricName, connections)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scalers
+
+import (
+	"context"
+	"testing"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+type parseAzureSignalRMetadataTestData struct {
+	metadata    map[string]string
+	isError     bool
+	authParams  map[string]string
+	podIdentity kedav1alpha1.PodIdentityProvider
+}
+
+type azureSignalRMetricIdentifier struct {
+	metadataTestData *parseAzureSignalRMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testAzureSignalRCredentials = map[string]string{"activeDirectoryClientId": "CLIENT_ID", "activeDirectoryClientPassword": "CLIENT_PASSWORD"}
+
+var testParseAzureSignalRMetadata = []parseAzureSignalRMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true, map[string]string{}, ""},
+	// only required properties
+	{map[string]string{"signalRName": "chat", "resourceGroupName": "rg", "subscriptionId": "456", "tenantId": "123"}, false, testAzureSignalRCredentials, ""},
+	// all properties
+	{map[string]string{"signalRName": "chat", "resourceGroupName": "rg", "subscriptionId": "456", "tenantId": "123", "endpoint": "Server", "metricAggregationInterval": "0:1:0", "targetConnections": "500", "activationTargetConnections": "10", "cloud": "AzureChinaCloud"},
+		false, testAzureSignalRCredentials, ""},
+	// workload identity
+	{map[string]string{"signalRName": "chat", "resourceGroupName": "rg", "subscriptionId": "456", "tenantId": "123", "endpoint": "all"}, false, map[string]string{}, kedav1alpha1.PodIdentityProviderAzureWorkload},
+	// pod identity
+	{map[string]string{"signalRName": "chat", "resourceGroupName": "rg", "subscriptionId": "456", "tenantId": "123"}, false, map[string]string{}, kedav1alpha1.PodIdentityProviderAzure},
+	// unsupported pod identity
+	{map[string]string{"signalRName": "chat", "resourceGroupName": "rg", "subscriptionId": "456", "tenantId": "123"}, true, map[string]string{}, kedav1alpha1.PodIdentityProviderAwsEKS},
+	// missing credentials
+	{map[string]string{"signalRName": "chat", "resourceGroupName": "rg", "subscriptionId": "456", "tenantId": "123"}, true, map[string]string{}, ""},
+	// missing signalRName
+	{map[string]string{"resourceGroupName": "rg", "subscriptionId": "456", "tenantId": "123"}, true, testAzureSignalRCredentials, ""},
+	// missing resourceGroupName
+	{map[string]string{"signalRName": "chat", "subscriptionId": "456", "tenantId": "123"}, true, testAzureSignalRCredentials, ""},
+	// missing subscriptionId
+	{map[string]string{"signalRName": "chat", "resourceGroupName": "rg", "tenantId": "123"}, true, testAzureSignalRCredentials, ""},
+	// missing tenantId
+	{map[string]string{"signalRName": "chat", "resourceGroupName": "rg", "subscriptionId": "456"}, true, testAzureSignalRCredentials, ""},
+	// invalid endpoint
+	{map[string]string{"signalRName": "chat", "resourceGroupName": "rg", "subscriptionId": "456", "tenantId": "123", "endpoint": "hub"}, true, testAzureSignalRCredentials, ""},
+	// invalid metricAggregationInterval
+	{map[string]string{"signalRName": "chat", "resourceGroupName": "rg", "subscriptionId": "456", "tenantId": "123", "metricAggregationInterval": "0:1"}, true, testAzureSignalRCredentials, ""},
+	// invalid connections
+	{map[string]string{"signalRName": "chat", "resourceGroupName": "rg", "subscriptionId": "456", "tenantId": "123", "targetConnections": "a"}, true, testAzureSignalRCredentials, ""},
+	{map[string]string{"signalRName": "chat", "resourceGroupName": "rg", "subscriptionId": "456", "tenantId": "123", "activationTargetConnections": "a"}, true, testAzureSignalRCredentials, ""},
+	// invalid cloud
+	{map[string]string{"signalRName": "chat", "resourceGroupName": "rg", "subscriptionId": "456", "tenantId": "123", "cloud": "Mars"}, true, testAzureSignalRCredentials, ""},
+}
+
+var azureSignalRMetricIdentifiers = []azureSignalRMetricIdentifier{
+	{&testParseAzureSignalRMetadata[1], 0, "s0-azure-signalr-chat-client"},
+	{&testParseAzureSignalRMetadata[2], 1, "s1-azure-signalr-chat-server"},
+}
+
+func TestAzureSignalRParseMetadata(t *testing.T) {
+	for _, testData := range testParseAzureSignalRMetadata {
+		_, err := parseAzureSignalRMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams,
+			PodIdentity: kedav1alpha1.AuthPodIdentity{Provider: testData.podIdentity}})
+		if err != nil && !testData.isError {
+			t.Errorf("Expected success but got error %s for %v", err, testData.metadata)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for %v", testData.metadata)
+		}
+	}
+}
+
+func TestAzureSignalRGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range azureSignalRMetricIdentifiers {
+		meta, err := parseAzureSignalRMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams,
+			PodIdentity: kedav1alpha1.AuthPodIdentity{Provider: testData.metadataTestData.podIdentity}, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockAzureSignalRScaler := azureSignalRScaler{metadata: meta}
+
+		metricSpec := mockAzureSignalRScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
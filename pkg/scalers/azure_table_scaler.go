@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/kedacore/keda/v2/pkg/scalers/azure"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	defaultTargetEntityCount = 5
+)
+
+type azureTableScaler struct {
+	metricType  v2beta2.MetricTargetType
+	metadata    *azureTableMetadata
+	podIdentity kedav1alpha1.AuthPodIdentity
+	httpClient  *http.Client
+}
+
+type azureTableMetadata struct {
+	table                       azure.TableMetadata
+	targetEntityCount           int64
+	activationTargetEntityCount int64
+	metricName                  string
+	scalerIndex                 int
+}
+
+var azureTableLog = logf.Log.WithName("azure_table_scaler")
+
+// NewAzureTableScaler creates a new scaler for the entities of a table of a storage or Cosmos DB Table API account
+func NewAzureTableScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, podIdentity, err := parseAzureTableMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing azure table metadata: %s", err))
+	}
+
+	return &azureTableScaler{
+		metricType:  metricType,
+		metadata:    meta,
+		podIdentity: podIdentity,
+		httpClient:  createHTTPClient(config, config.GlobalHTTPTimeout, false),
+	}, nil
+}
+
+func parseAzureTableMetadata(config *ScalerConfig) (*azureTableMetadata, kedav1alpha1.AuthPodIdentity, error) {
+	meta := azureTableMetadata{}
+	meta.targetEntityCount = defaultTargetEntityCount
+
+	if val, ok := config.TriggerMetadata["targetEntityCount"]; ok && val != "" {
+		targetEntityCount, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, kedav1alpha1.AuthPodIdentity{}, fmt.Errorf("error parsing azure table metadata targetEntityCount: %s", err.Error())
+		}
+		meta.targetEntityCount = targetEntityCount
+	}
+
+	if val, ok := config.TriggerMetadata["activationTargetEntityCount"]; ok && val != "" {
+		activationTargetEntityCount, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, kedav1alpha1.AuthPodIdentity{}, fmt.Errorf("error parsing azure table metadata activationTargetEntityCount: %s", err.Error())
+		}
+		meta.activationTargetEntityCount = activationTargetEntityCount
+	}
+
+	endpointSuffix, err := azure.ParseAzureStorageEndpointSuffix(config.TriggerMetadata, azure.TableEndpoint)
+	if err != nil {
+		return nil, kedav1alpha1.AuthPodIdentity{}, err
+	}
+	meta.table.EndpointSuffix = endpointSuffix
+
+	if val, ok := config.TriggerMetadata["tableName"]; ok && val != "" {
+		meta.table.TableName = val
+	} else {
+		return nil, kedav1alpha1.AuthPodIdentity{}, fmt.Errorf("no tableName given")
+	}
+
+	// OData filter of the entities, like PartitionKey eq 'pending', all the entities are counted without it
+	meta.table.Filter = config.TriggerMetadata["filter"]
+
+	switch config.PodIdentity.Provider {
+	case "", kedav1alpha1.PodIdentityProviderNone:
+		// Azure Table Scaler expects a "connection" parameter in the metadata
+		// of the scaler or in a TriggerAuthentication object
+		if config.AuthParams["connection"] != "" {
+			meta.table.Connection = config.AuthParams["connection"]
+		} else if config.TriggerMetadata["connectionFromEnv"] != "" {
+			meta.table.Connection = config.ResolvedEnv[config.TriggerMetadata["connectionFromEnv"]]
+		}
+
+		if len(meta.table.Connection) == 0 {
+			return nil, kedav1alpha1.AuthPodIdentity{}, fmt.Errorf("no connection setting given")
+		}
+	case kedav1alpha1.PodIdentityProviderAzure, kedav1alpha1.PodIdentityProviderAzureWorkload:
+		// the tables of the storage accounts only, Cosmos DB doesn't authorize the Table API requests with Azure AD
+		if val, ok := config.TriggerMetadata["accountName"]; ok && val != "" {
+			meta.table.AccountName = val
+		} else {
+			return nil, kedav1alpha1.AuthPodIdentity{}, fmt.Errorf("no accountName given")
+		}
+	default:
+		return nil, kedav1alpha1.AuthPodIdentity{}, fmt.Errorf("pod identity %s not supported for azure storage tables", config.PodIdentity)
+	}
+
+	if val, ok := config.TriggerMetadata["metricName"]; ok && val != "" {
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("azure-table-%s", val))
+	} else {
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("azure-table-%s", meta.table.TableName))
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+
+	return &meta, config.PodIdentity, nil
+}
+
+// IsActive determines whether this scaler is currently active
+func (s *azureTableScaler) IsActive(ctx context.Context) (bool, error) {
+	count, err := azure.GetAzureTableEntityCount(ctx, s.httpClient, s.podIdentity, &s.metadata.table)
+	if err != nil {
+		azureTableLog.Error(err, "error getting entity count")
+		return false, err
+	}
+
+	return count > s.metadata.activationTargetEntityCount, nil
+}
+
+func (s *azureTableScaler) Close(context.Context) error {
+	return nil
+}
+
+func (s *azureTableScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, s.metadata.metricName),
+		},
+		Target: GetMetricTarget(s.metricType, s.metadata.targetEntityCount),
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *azureTableScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	count, err := azure.GetAzureTableEntityCount(ctx, s.httpClient, s.podIdentity, &s.metadata.table)
+	if err != nil {
+		azureTableLog.Error(err, "error getting entity count")
+		return []external_metrics.ExternalMetricValue{}, err
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(count))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scalers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+var testAzTableResolvedEnv = map[string]string{
+	"CONNECTION": "SAMPLE",
+}
+
+type parseAzTableMetadataTestData struct {
+	metadata    map[string]string
+	isError     bool
+	resolvedEnv map[string]string
+	authParams  map[string]string
+	podIdentity kedav1alpha1.PodIdentityProvider
+}
+
+type azTableMetricIdentifier struct {
+	metadataTestData *parseAzTableMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testAzTableMetadata = []parseAzTableMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true, testAzTableResolvedEnv, map[string]string{}, ""},
+	// properly formed
+	{map[string]string{"connectionFromEnv": "CONNECTION", "tableName": "ledger", "targetEntityCount": "10", "activationTargetEntityCount": "1"}, false, testAzTableResolvedEnv, map[string]string{}, ""},
+	// with filter and metric name
+	{map[string]string{"connectionFromEnv": "CONNECTION", "tableName": "ledger", "filter": "PartitionKey eq 'pending'", "metricName": "pending"}, false, testAzTableResolvedEnv, map[string]string{}, ""},
+	// empty tableName
+	{map[string]string{"connectionFromEnv": "CONNECTION", "tableName": ""}, true, testAzTableResolvedEnv, map[string]string{}, ""},
+	// improperly formed targetEntityCount
+	{map[string]string{"connectionFromEnv": "CONNECTION", "tableName": "ledger", "targetEntityCount": "AA"}, true, testAzTableResolvedEnv, map[string]string{}, ""},
+	// improperly formed activationTargetEntityCount
+	{map[string]string{"connectionFromEnv": "CONNECTION", "tableName": "ledger", "activationTargetEntityCount": "AA"}, true, testAzTableResolvedEnv, map[string]string{}, ""},
+	// missing connection
+	{map[string]string{"tableName": "ledger"}, true, testAzTableResolvedEnv, map[string]string{}, ""},
+	// connection from authParams
+	{map[string]string{"tableName": "ledger"}, false, testAzTableResolvedEnv, map[string]string{"connection": "value"}, kedav1alpha1.PodIdentityProviderNone},
+	// podIdentity = azure with account name
+	{map[string]string{"accountName": "sample_acc", "tableName": "ledger"}, false, testAzTableResolvedEnv, map[string]string{}, kedav1alpha1.PodIdentityProviderAzure},
+	// podIdentity = azure without account name
+	{map[string]string{"accountName": "", "tableName": "ledger"}, true, testAzTableResolvedEnv, map[string]string{}, kedav1alpha1.PodIdentityProviderAzure},
+	// podIdentity = azure with invalid cloud
+	{map[string]string{"accountName": "sample_acc", "tableName": "ledger", "cloud": "InvalidCloud"}, true, testAzTableResolvedEnv, map[string]string{}, kedav1alpha1.PodIdentityProviderAzure},
+	// podIdentity = azure with private cloud and endpoint suffix
+	{map[string]string{"accountName": "sample_acc", "tableName": "ledger", "cloud": "Private", "endpointSuffix": "table.core.private.cloud"}, false, testAzTableResolvedEnv, map[string]string{}, kedav1alpha1.PodIdentityProviderAzure},
+	// podIdentity = azure-workload with account name
+	{map[string]string{"accountName": "sample_acc", "tableName": "ledger"}, false, testAzTableResolvedEnv, map[string]string{}, kedav1alpha1.PodIdentityProviderAzureWorkload},
+	// podIdentity = aws-eks not supported
+	{map[string]string{"accountName": "sample_acc", "tableName": "ledger"}, true, testAzTableResolvedEnv, map[string]string{}, kedav1alpha1.PodIdentityProviderAwsEKS},
+}
+
+var azTableMetricIdentifiers = []azTableMetricIdentifier{
+	{&testAzTableMetadata[1], 0, "s0-azure-table-ledger"},
+	{&testAzTableMetadata[2], 1, "s1-azure-table-pending"},
+}
+
+func TestAzTableParseMetadata(t *testing.T) {
+	for _, testData := range testAzTableMetadata {
+		_, podIdentity, err := parseAzureTableMetadata(&ScalerConfig{TriggerMetadata: testData.metadata,
+			ResolvedEnv: testData.resolvedEnv, AuthParams: testData.authParams,
+			PodIdentity: kedav1alpha1.AuthPodIdentity{Provider: testData.podIdentity}})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success. testData: %v", testData)
+		}
+		if testData.podIdentity != "" && testData.podIdentity != podIdentity.Provider && err == nil {
+			t.Error("Expected success but got error: podIdentity value is not returned as expected")
+		}
+	}
+}
+
+func TestAzTableGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range azTableMetricIdentifiers {
+		meta, podIdentity, err := parseAzureTableMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata,
+			ResolvedEnv: testData.metadataTestData.resolvedEnv, AuthParams: testData.metadataTestData.authParams,
+			PodIdentity: kedav1alpha1.AuthPodIdentity{Provider: testData.metadataTestData.podIdentity}, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockAzTableScaler := azureTableScaler{
+			metadata:    meta,
+			podIdentity: podIdentity,
+			httpClient:  http.DefaultClient,
+		}
+
+		metricSpec := mockAzTableScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package scalers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	beanstalkdJobCountDefault   = 10
+	beanstalkdDefaultTimeout    = 5 * time.Second
+	beanstalkdJobsReadyStat     = "current-jobs-ready"
+	beanstalkdJobsReservedStat  = "current-jobs-reserved"
+	beanstalkdJobsDelayedStat   = "current-jobs-delayed"
+	beanstalkdTubeNotFoundReply = "NOT_FOUND"
+)
+
+type beanstalkdScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *beanstalkdMetadata
+	timeout    time.Duration
+}
+
+type beanstalkdMetadata struct {
+	address            string
+	tube               string
+	jobCount           float64
+	activationJobCount float64
+	includeReserved    bool
+	includeDelayed     bool
+	metricName         string
+	scalerIndex        int
+}
+
+var beanstalkdLog = logf.Log.WithName("beanstalkd_scaler")
+
+// NewBeanstalkdScaler creates a new beanstalkd scaler
+func NewBeanstalkdScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseBeanstalkdMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing beanstalkd metadata: %s", err))
+	}
+
+	timeout := config.GlobalHTTPTimeout
+	if timeout <= 0 {
+		timeout = beanstalkdDefaultTimeout
+	}
+
+	return &beanstalkdScaler{
+		metricType: metricType,
+		metadata:   meta,
+		timeout:    timeout,
+	}, nil
+}
+
+func parseBeanstalkdMetadata(config *ScalerConfig) (*beanstalkdMetadata, error) {
+	meta := beanstalkdMetadata{}
+
+	address, err := GetFromAuthOrMeta(config, "address")
+	if err != nil {
+		return nil, err
+	}
+	meta.address = address
+
+	if val, ok := config.TriggerMetadata["tube"]; ok && val != "" {
+		meta.tube = val
+	} else {
+		return nil, errors.New("no tube given")
+	}
+
+	meta.jobCount = beanstalkdJobCountDefault
+	if val, ok := config.TriggerMetadata["jobCount"]; ok {
+		jobCount, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("jobCount parsing error %s", err.Error())
+		}
+		if jobCount <= 0 {
+			return nil, errors.New("jobCount must be greater than 0")
+		}
+		meta.jobCount = jobCount
+	}
+
+	if val, ok := config.TriggerMetadata["activationJobCount"]; ok {
+		activationJobCount, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationJobCount parsing error %s", err.Error())
+		}
+		meta.activationJobCount = activationJobCount
+	}
+
+	if val, ok := config.TriggerMetadata["includeReserved"]; ok {
+		includeReserved, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("includeReserved parsing error %s", err.Error())
+		}
+		meta.includeReserved = includeReserved
+	}
+
+	if val, ok := config.TriggerMetadata["includeDelayed"]; ok {
+		includeDelayed, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("includeDelayed parsing error %s", err.Error())
+		}
+		meta.includeDelayed = includeDelayed
+	}
+
+	meta.metricName = kedautil.NormalizeString(fmt.Sprintf("beanstalkd-%s", meta.tube))
+	meta.scalerIndex = config.ScalerIndex
+
+	return &meta, nil
+}
+
+// Close does nothing in case of beanstalkdScaler, a connection is opened for each read
+func (s *beanstalkdScaler) Close(context.Context) error {
+	return nil
+}
+
+// IsActive returns true if there are more jobs in the tube than activationJobCount
+func (s *beanstalkdScaler) IsActive(ctx context.Context) (bool, error) {
+	jobs, err := s.getJobCount(ctx)
+	if err != nil {
+		beanstalkdLog.Error(err, "error inspecting beanstalkd")
+		return false, err
+	}
+	return jobs > s.metadata.activationJobCount, nil
+}
+
+// getJobCount sums the ready jobs of the tube, and the reserved and delayed ones if requested
+func (s *beanstalkdScaler) getJobCount(ctx context.Context) (float64, error) {
+	stats, err := s.getTubeStats(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if stats == nil {
+		// beanstalkd drops tubes which aren't used by any client, so there are no jobs
+		return 0, nil
+	}
+
+	names := []string{beanstalkdJobsReadyStat}
+	if s.metadata.includeReserved {
+		names = append(names, beanstalkdJobsReservedStat)
+	}
+	if s.metadata.includeDelayed {
+		names = append(names, beanstalkdJobsDelayedStat)
+	}
+
+	var jobs float64
+	for _, name := range names {
+		val, ok := stats[name]
+		if !ok {
+			return 0, fmt.Errorf("stat %s not found for tube %s", name, s.metadata.tube)
+		}
+		count, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return 0, fmt.Errorf("stat %s is not numeric: %s", name, err)
+		}
+		jobs += count
+	}
+	return jobs, nil
+}
+
+// getTubeStats sends stats-tube and returns the stats of the tube, or nil if the tube doesn't exist
+func (s *beanstalkdScaler) getTubeStats(ctx context.Context) (map[string]string, error) {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.metadata.address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		return nil, err
+	}
+
+	if _, err := fmt.Fprintf(conn, "stats-tube %s\r\n", s.metadata.tube); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	reply, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("error reading beanstalkd reply: %s", err)
+	}
+	reply = strings.TrimSpace(reply)
+	if reply == beanstalkdTubeNotFoundReply {
+		return nil, nil
+	}
+
+	// the reply is "OK <bytes>" followed by a yaml dictionary of <bytes> length and a trailing \r\n
+	var length int
+	if _, err := fmt.Sscanf(reply, "OK %d", &length); err != nil {
+		return nil, fmt.Errorf("unexpected beanstalkd reply: %s", reply)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return nil, fmt.Errorf("error reading beanstalkd stats: %s", err)
+	}
+
+	return parseBeanstalkdStats(string(body)), nil
+}
+
+// parseBeanstalkdStats reads the flat "key: value" yaml dictionary returned by the stats commands
+func parseBeanstalkdStats(body string) map[string]string {
+	stats := map[string]string{}
+	for _, line := range strings.Split(body, "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		stats[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return stats
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *beanstalkdScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, s.metadata.metricName),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.jobCount),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *beanstalkdScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	jobs, err := s.getJobCount(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting beanstalkd: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, jobs)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
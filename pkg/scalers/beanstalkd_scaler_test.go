@@ -0,0 +1,156 @@
+package scalers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+type parseBeanstalkdMetadataTestData struct {
+	metadata    map[string]string
+	authParams  map[string]string
+	raisesError bool
+}
+
+type beanstalkdMetricIdentifier struct {
+	metadataTestData *parseBeanstalkdMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testBeanstalkdMetadata = []parseBeanstalkdMetadataTestData{
+	// nothing passed
+	{map[string]string{}, map[string]string{}, true},
+	// properly formed
+	{map[string]string{"address": "beanstalkd:11300", "tube": "emails", "jobCount": "5"}, map[string]string{}, false},
+	// all options
+	{map[string]string{"address": "beanstalkd:11300", "tube": "default", "jobCount": "5", "activationJobCount": "2", "includeReserved": "true", "includeDelayed": "true"}, map[string]string{}, false},
+	// address from authParams and default jobCount
+	{map[string]string{"tube": "emails"}, map[string]string{"address": "beanstalkd:11300"}, false},
+	// no address
+	{map[string]string{"tube": "emails"}, map[string]string{}, true},
+	// no tube
+	{map[string]string{"address": "beanstalkd:11300"}, map[string]string{}, true},
+	// invalid jobCount
+	{map[string]string{"address": "beanstalkd:11300", "tube": "emails", "jobCount": "a"}, map[string]string{}, true},
+	// zero jobCount
+	{map[string]string{"address": "beanstalkd:11300", "tube": "emails", "jobCount": "0"}, map[string]string{}, true},
+	// invalid activationJobCount
+	{map[string]string{"address": "beanstalkd:11300", "tube": "emails", "activationJobCount": "a"}, map[string]string{}, true},
+	// invalid includeDelayed
+	{map[string]string{"address": "beanstalkd:11300", "tube": "emails", "includeDelayed": "yes please"}, map[string]string{}, true},
+	// invalid includeReserved
+	{map[string]string{"address": "beanstalkd:11300", "tube": "emails", "includeReserved": "yes please"}, map[string]string{}, true},
+}
+
+var beanstalkdMetricIdentifiers = []beanstalkdMetricIdentifier{
+	{&testBeanstalkdMetadata[1], 0, "s0-beanstalkd-emails"},
+	{&testBeanstalkdMetadata[2], 1, "s1-beanstalkd-default"},
+}
+
+func TestParseBeanstalkdMetadata(t *testing.T) {
+	for idx, testData := range testBeanstalkdMetadata {
+		_, err := parseBeanstalkdMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.raisesError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if err == nil && testData.raisesError {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+	}
+}
+
+func TestBeanstalkdGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range beanstalkdMetricIdentifiers {
+		meta, err := parseBeanstalkdMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockBeanstalkdScaler := beanstalkdScaler{"", meta, time.Second}
+
+		metricSpec := mockBeanstalkdScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+// startBeanstalkdTestServer answers stats-tube for the emails tube and NOT_FOUND for any other tube
+func startBeanstalkdTestServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	stats := "---\nname: emails\ncurrent-urgent-jobs: 0\ncurrent-jobs-ready: 7\ncurrent-jobs-reserved: 2\ncurrent-jobs-delayed: 3\ncurrent-jobs-buried: 1\n"
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				command, err := bufio.NewReader(conn).ReadString('\n')
+				if err != nil {
+					return
+				}
+				if strings.TrimSpace(command) == "stats-tube emails" {
+					fmt.Fprintf(conn, "OK %d\r\n%s\r\n", len(stats), stats)
+				} else {
+					fmt.Fprint(conn, "NOT_FOUND\r\n")
+				}
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestBeanstalkdGetJobCount(t *testing.T) {
+	address := startBeanstalkdTestServer(t)
+
+	tests := []struct {
+		metadata map[string]string
+		expected float64
+		active   bool
+	}{
+		{map[string]string{"address": address, "tube": "emails"}, 7, true},
+		{map[string]string{"address": address, "tube": "emails", "includeReserved": "true"}, 9, true},
+		{map[string]string{"address": address, "tube": "emails", "includeDelayed": "true"}, 10, true},
+		{map[string]string{"address": address, "tube": "emails", "includeReserved": "true", "includeDelayed": "true"}, 12, true},
+		{map[string]string{"address": address, "tube": "emails", "activationJobCount": "7"}, 7, false},
+		{map[string]string{"address": address, "tube": "unused"}, 0, false},
+	}
+
+	for idx, test := range tests {
+		meta, err := parseBeanstalkdMetadata(&ScalerConfig{TriggerMetadata: test.metadata})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		scaler := beanstalkdScaler{metadata: meta, timeout: time.Second}
+
+		jobs, err := scaler.getJobCount(context.Background())
+		if err != nil {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if jobs != test.expected {
+			t.Errorf("Expected %f jobs but got %f for test %d", test.expected, jobs, idx)
+		}
+
+		active, err := scaler.IsActive(context.Background())
+		if err != nil {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if active != test.active {
+			t.Errorf("Expected active %t but got %t for test %d", test.active, active, idx)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	buildkiteDefaultAgentAPIURL   = "https://agent.buildkite.com/v3"
+	buildkiteDefaultRESTAPIURL    = "https://api.buildkite.com/v2"
+	buildkiteDefaultQueue         = "default"
+	buildkiteDefaultTargetJobs    = 1
+	buildkiteRESTPageSize         = 100
+	buildkiteJobStateScheduled    = "scheduled"
+	buildkiteQueueAgentQueryRule  = "queue="
+	buildkiteMetricNamePrefix     = "buildkite"
+	buildkiteAuthModeAgentToken   = "agentToken"
+	buildkiteAuthModeRESTAPIToken = "apiToken"
+)
+
+// buildkiteNextPageLink extracts the URL of the next page from the Link header of the REST API
+var buildkiteNextPageLink = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+type buildkiteScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *buildkiteMetadata
+	httpClient *http.Client
+}
+
+type buildkiteMetadata struct {
+	apiURL       string
+	queue        string
+	targetJobs   float64
+	organization string
+
+	// auth, the agent token reads the Agent Metrics API, the REST API token lists the scheduled builds
+	authMode string
+	token    string
+
+	scalerIndex int
+}
+
+type buildkiteAgentMetrics struct {
+	Jobs struct {
+		Queues map[string]struct {
+			Scheduled int64 `json:"scheduled"`
+		} `json:"queues"`
+	} `json:"jobs"`
+}
+
+type buildkiteBuild struct {
+	Jobs []buildkiteJob `json:"jobs"`
+}
+
+type buildkiteJob struct {
+	Type            string   `json:"type"`
+	State           string   `json:"state"`
+	AgentQueryRules []string `json:"agent_query_rules"`
+}
+
+var buildkiteLog = logf.Log.WithName("buildkite_scaler")
+
+// NewBuildkiteScaler creates a new buildkiteScaler
+func NewBuildkiteScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseBuildkiteMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing buildkite metadata: %s", err))
+	}
+
+	return &buildkiteScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, false),
+	}, nil
+}
+
+func parseBuildkiteMetadata(config *ScalerConfig) (*buildkiteMetadata, error) {
+	meta := buildkiteMetadata{}
+
+	switch {
+	case config.AuthParams["agentToken"] != "":
+		meta.authMode = buildkiteAuthModeAgentToken
+		meta.token = config.AuthParams["agentToken"]
+		meta.apiURL = buildkiteDefaultAgentAPIURL
+	case config.AuthParams["apiToken"] != "":
+		meta.authMode = buildkiteAuthModeRESTAPIToken
+		meta.token = config.AuthParams["apiToken"]
+		meta.apiURL = buildkiteDefaultRESTAPIURL
+		meta.organization = config.TriggerMetadata["organizationSlug"]
+		if meta.organization == "" {
+			return nil, errors.New("no organizationSlug given, it's required with an apiToken")
+		}
+	default:
+		return nil, errors.New("no agentToken or apiToken given")
+	}
+
+	if val, ok := config.TriggerMetadata["buildkiteAPIURL"]; ok && val != "" {
+		meta.apiURL = strings.TrimSuffix(val, "/")
+	}
+
+	meta.queue = buildkiteDefaultQueue
+	if val, ok := config.TriggerMetadata["queue"]; ok && val != "" {
+		meta.queue = val
+	}
+
+	meta.targetJobs = buildkiteDefaultTargetJobs
+	if val, ok := config.TriggerMetadata["targetScheduledJobs"]; ok {
+		targetJobs, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetScheduledJobs parsing error %s", err.Error())
+		}
+		if targetJobs <= 0 {
+			return nil, errors.New("targetScheduledJobs must be greater than 0")
+		}
+		meta.targetJobs = targetJobs
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// IsActive returns true if there are scheduled jobs in the queue
+func (s *buildkiteScaler) IsActive(ctx context.Context) (bool, error) {
+	jobs, err := s.getScheduledJobCount(ctx)
+	if err != nil {
+		buildkiteLog.Error(err, "error getting buildkite scheduled jobs")
+		return false, err
+	}
+	return jobs > 0, nil
+}
+
+func (s *buildkiteScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *buildkiteScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("%s-%s", buildkiteMetricNamePrefix, s.metadata.queue))),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetJobs),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of scheduled jobs in the queue
+func (s *buildkiteScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	jobs, err := s.getScheduledJobCount(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting buildkite scheduled jobs: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(jobs))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+func (s *buildkiteScaler) getScheduledJobCount(ctx context.Context) (int64, error) {
+	if s.metadata.authMode == buildkiteAuthModeAgentToken {
+		return s.getAgentMetricsScheduledJobs(ctx)
+	}
+	return s.getRESTScheduledJobs(ctx)
+}
+
+// getAgentMetricsScheduledJobs reads the scheduled jobs of the queue from the Agent Metrics API,
+// the agent token belongs to a single organization
+func (s *buildkiteScaler) getAgentMetricsScheduledJobs(ctx context.Context) (int64, error) {
+	body, _, err := s.get(ctx, s.metadata.apiURL+"/metrics")
+	if err != nil {
+		return -1, err
+	}
+	var metrics buildkiteAgentMetrics
+	if err := json.Unmarshal(body, &metrics); err != nil {
+		return -1, err
+	}
+	return metrics.Jobs.Queues[s.metadata.queue].Scheduled, nil
+}
+
+// getRESTScheduledJobs counts the scheduled jobs of the queue in the scheduled builds of the organization
+func (s *buildkiteScaler) getRESTScheduledJobs(ctx context.Context) (int64, error) {
+	var count int64
+	next := fmt.Sprintf("%s/organizations/%s/builds?state=%s&per_page=%d", s.metadata.apiURL, url.PathEscape(s.metadata.organization), buildkiteJobStateScheduled, buildkiteRESTPageSize)
+	for next != "" {
+		body, header, err := s.get(ctx, next)
+		if err != nil {
+			return -1, err
+		}
+		var builds []buildkiteBuild
+		if err := json.Unmarshal(body, &builds); err != nil {
+			return -1, err
+		}
+		for _, build := range builds {
+			count += countBuildkiteScheduledJobs(build.Jobs, s.metadata.queue)
+		}
+
+		next = ""
+		if match := buildkiteNextPageLink.FindStringSubmatch(header.Get("Link")); match != nil {
+			next = match[1]
+		}
+	}
+	return count, nil
+}
+
+func (s *buildkiteScaler) get(ctx context.Context, url string) ([]byte, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.metadata.authMode == buildkiteAuthModeAgentToken {
+		req.Header.Set("Authorization", "Token "+s.metadata.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+s.metadata.token)
+	}
+
+	r, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer r.Body.Close()
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("error requesting buildkite API status: %s, response: %s", r.Status, body)
+	}
+	return body, r.Header, nil
+}
+
+// countBuildkiteScheduledJobs counts the scheduled command jobs targeting the queue,
+// the jobs without queue rule run on the default queue
+func countBuildkiteScheduledJobs(jobs []buildkiteJob, queue string) int64 {
+	var count int64
+	for _, job := range jobs {
+		if job.Type != "script" || job.State != buildkiteJobStateScheduled {
+			continue
+		}
+		jobQueue := buildkiteDefaultQueue
+		for _, rule := range job.AgentQueryRules {
+			if strings.HasPrefix(rule, buildkiteQueueAgentQueryRule) {
+				jobQueue = strings.TrimPrefix(rule, buildkiteQueueAgentQueryRule)
+			}
+		}
+		if jobQueue == queue {
+			count++
+		}
+	}
+	return count
+}
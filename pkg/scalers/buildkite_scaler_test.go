@@ -0,0 +1,124 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseBuildkiteMetadataTestData struct {
+	metadata   map[string]string
+	isError    bool
+	authParams map[string]string
+}
+
+type buildkiteMetricIdentifier struct {
+	metadataTestData *parseBuildkiteMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testBuildkiteMetadata = []parseBuildkiteMetadataTestData{
+	// agent token
+	{map[string]string{}, false, map[string]string{"agentToken": "token"}},
+	// REST API token with all properties
+	{map[string]string{"organizationSlug": "kedacore", "queue": "linux-large", "targetScheduledJobs": "2", "buildkiteAPIURL": "https://buildkite.example.com/v2/"}, false, map[string]string{"apiToken": "token"}},
+	// no token
+	{map[string]string{}, true, map[string]string{}},
+	// REST API token without organization
+	{map[string]string{}, true, map[string]string{"apiToken": "token"}},
+	// invalid targetScheduledJobs
+	{map[string]string{"targetScheduledJobs": "a"}, true, map[string]string{"agentToken": "token"}},
+	{map[string]string{"targetScheduledJobs": "0"}, true, map[string]string{"agentToken": "token"}},
+}
+
+var buildkiteMetricIdentifiers = []buildkiteMetricIdentifier{
+	{&testBuildkiteMetadata[0], 0, "s0-buildkite-default"},
+	{&testBuildkiteMetadata[1], 1, "s1-buildkite-linux-large"},
+}
+
+func TestBuildkiteParseMetadata(t *testing.T) {
+	for _, testData := range testBuildkiteMetadata {
+		_, err := parseBuildkiteMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestBuildkiteGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range buildkiteMetricIdentifiers {
+		meta, err := parseBuildkiteMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockBuildkiteScaler := buildkiteScaler{metadata: meta}
+
+		metricSpec := mockBuildkiteScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestCountBuildkiteScheduledJobs(t *testing.T) {
+	jobs := []buildkiteJob{
+		{Type: "script", State: "scheduled"},
+		{Type: "script", State: "scheduled", AgentQueryRules: []string{"queue=linux"}},
+		{Type: "script", State: "scheduled", AgentQueryRules: []string{"os=linux", "queue=linux"}},
+		{Type: "script", State: "running", AgentQueryRules: []string{"queue=linux"}},
+		{Type: "waiter", State: "scheduled"},
+	}
+
+	assert.Equal(t, int64(1), countBuildkiteScheduledJobs(jobs, "default"))
+	assert.Equal(t, int64(2), countBuildkiteScheduledJobs(jobs, "linux"))
+	assert.Equal(t, int64(0), countBuildkiteScheduledJobs(jobs, "windows"))
+}
+
+func TestBuildkiteGetScheduledJobCount(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/metrics":
+			assert.Equal(t, "Token agent-token", r.Header.Get("Authorization"))
+			fmt.Fprint(w, `{"jobs":{"scheduled":7,"queues":{"default":{"scheduled":3},"linux":{"scheduled":4}}}}`)
+		case "/organizations/kedacore/builds":
+			assert.Equal(t, "Bearer api-token", r.Header.Get("Authorization"))
+			assert.Equal(t, "scheduled", r.URL.Query().Get("state"))
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/organizations/kedacore/builds?state=scheduled&per_page=100&page=2>; rel="next"`, server.URL))
+				fmt.Fprint(w, `[{"jobs":[{"type":"script","state":"scheduled","agent_query_rules":["queue=linux"]},{"type":"script","state":"scheduled"}]}]`)
+				return
+			}
+			fmt.Fprint(w, `[{"jobs":[{"type":"script","state":"scheduled","agent_query_rules":["queue=linux"]}]}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		authParams map[string]string
+		expected   int64
+	}{
+		{map[string]string{"agentToken": "agent-token"}, 4},
+		{map[string]string{"apiToken": "api-token"}, 2},
+	}
+	for _, testCase := range testCases {
+		meta, err := parseBuildkiteMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"buildkiteAPIURL": server.URL, "organizationSlug": "kedacore", "queue": "linux"}, AuthParams: testCase.authParams})
+		assert.NoError(t, err)
+		s := buildkiteScaler{metadata: meta, httpClient: http.DefaultClient}
+
+		count, err := s.getScheduledJobCount(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.expected, count)
+	}
+}
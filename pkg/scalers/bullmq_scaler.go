@@ -0,0 +1,235 @@
+package scalers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	bullMQDefaultPrefix        = "bull"
+	bullMQJobCountDefault      = 5
+	bullMQWaitListSuffix       = "wait"
+	bullMQActiveListSuffix     = "active"
+	bullMQDelayedSetSuffix     = "delayed"
+	bullMQPrioritizedSetSuffix = "prioritized"
+)
+
+type bullMQScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *bullMQMetadata
+	client     redis.UniversalClient
+}
+
+type bullMQMetadata struct {
+	queueName      string
+	prefix         string
+	jobCount       float64
+	includeDelayed bool
+	includeActive  bool
+	databaseIndex  int
+	connectionInfo redisConnectionInfo
+	scalerIndex    int
+}
+
+var bullMQLog = logf.Log.WithName("bullmq_scaler")
+
+// NewBullMQScaler creates a new bullMQScaler
+func NewBullMQScaler(ctx context.Context, isClustered, isSentinel bool, config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	switch {
+	case isClustered:
+		meta, err := parseBullMQMetadata(config, parseRedisClusterAddress)
+		if err != nil {
+			return nil, NewPermanentError(fmt.Errorf("error parsing bullmq metadata: %s", err))
+		}
+		client, err := getRedisClusterClient(ctx, meta.connectionInfo)
+		if err != nil {
+			return nil, fmt.Errorf("connection to redis cluster failed: %s", err)
+		}
+		return &bullMQScaler{metricType: metricType, metadata: meta, client: client}, nil
+	case isSentinel:
+		meta, err := parseBullMQMetadata(config, parseRedisSentinelAddress)
+		if err != nil {
+			return nil, NewPermanentError(fmt.Errorf("error parsing bullmq metadata: %s", err))
+		}
+		client, err := getRedisSentinelClient(ctx, meta.connectionInfo, meta.databaseIndex)
+		if err != nil {
+			return nil, fmt.Errorf("connection to redis sentinel failed: %s", err)
+		}
+		return &bullMQScaler{metricType: metricType, metadata: meta, client: client}, nil
+	}
+
+	meta, err := parseBullMQMetadata(config, parseRedisAddress)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing bullmq metadata: %s", err))
+	}
+	client, err := getRedisClient(ctx, meta.connectionInfo, meta.databaseIndex)
+	if err != nil {
+		return nil, fmt.Errorf("connection to redis failed: %s", err)
+	}
+	return &bullMQScaler{metricType: metricType, metadata: meta, client: client}, nil
+}
+
+func parseBullMQMetadata(config *ScalerConfig, parserFn redisAddressParser) (*bullMQMetadata, error) {
+	connInfo, err := parserFn(config.TriggerMetadata, config.ResolvedEnv, config.AuthParams)
+	if err != nil {
+		return nil, err
+	}
+	meta := bullMQMetadata{
+		connectionInfo: connInfo,
+	}
+
+	if val, ok := config.TriggerMetadata["queueName"]; ok && val != "" {
+		meta.queueName = val
+	} else {
+		return nil, errors.New("no queueName given")
+	}
+
+	// the prefix may contain a hash tag, like {bull}, to keep the keys of a queue in the same redis cluster slot
+	meta.prefix = bullMQDefaultPrefix
+	if val, ok := config.TriggerMetadata["prefix"]; ok && val != "" {
+		meta.prefix = val
+	}
+
+	meta.jobCount = bullMQJobCountDefault
+	if val, ok := config.TriggerMetadata["jobCount"]; ok {
+		jobCount, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("jobCount parsing error %s", err.Error())
+		}
+		if jobCount <= 0 {
+			return nil, errors.New("jobCount must be greater than 0")
+		}
+		meta.jobCount = jobCount
+	}
+
+	meta.includeDelayed = true
+	if val, ok := config.TriggerMetadata["includeDelayed"]; ok {
+		includeDelayed, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("includeDelayed parsing error %s", err.Error())
+		}
+		meta.includeDelayed = includeDelayed
+	}
+
+	if val, ok := config.TriggerMetadata["includeActive"]; ok {
+		includeActive, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("includeActive parsing error %s", err.Error())
+		}
+		meta.includeActive = includeActive
+	}
+
+	meta.databaseIndex = defaultDBIdx
+	if val, ok := config.TriggerMetadata["databaseIndex"]; ok {
+		dbIndex, err := strconv.ParseInt(val, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("databaseIndex: parsing error %s", err.Error())
+		}
+		meta.databaseIndex = int(dbIndex)
+	}
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// IsActive checks if there are jobs waiting in the queue
+func (s *bullMQScaler) IsActive(ctx context.Context) (bool, error) {
+	jobs, err := s.getJobCount(ctx)
+	if err != nil {
+		bullMQLog.Error(err, "error inspecting bullmq queue")
+		return false, err
+	}
+
+	return jobs > 0, nil
+}
+
+func (s *bullMQScaler) Close(context.Context) error {
+	if err := s.client.Close(); err != nil {
+		bullMQLog.Error(err, "error closing redis client")
+		return err
+	}
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *bullMQScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	metricName := kedautil.NormalizeString(fmt.Sprintf("bullmq-%s", s.metadata.queueName))
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, metricName),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.jobCount),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of jobs waiting in the queue
+func (s *bullMQScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	jobs, err := s.getJobCount(ctx)
+	if err != nil {
+		bullMQLog.Error(err, "error inspecting bullmq queue")
+		return []external_metrics.ExternalMetricValue{}, err
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(jobs))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// getJobCount sums the lengths of the lists and the cardinalities of the sorted sets holding the jobs to count
+func (s *bullMQScaler) getJobCount(ctx context.Context) (int64, error) {
+	lists, sets := getBullMQKeys(s.metadata)
+
+	var jobs int64
+	for _, list := range lists {
+		length, err := s.client.LLen(ctx, list).Result()
+		if err != nil {
+			return -1, err
+		}
+		jobs += length
+	}
+	for _, set := range sets {
+		length, err := s.client.ZCard(ctx, set).Result()
+		if err != nil {
+			return -1, err
+		}
+		jobs += length
+	}
+	return jobs, nil
+}
+
+// getBullMQKeys returns the lists and sorted sets of the queue holding the jobs to count, following the "<prefix>:<queue>:<state>" convention.
+// Bull keeps prioritized jobs in the wait list, BullMQ moves them to the prioritized set which is just empty with Bull.
+func getBullMQKeys(meta *bullMQMetadata) ([]string, []string) {
+	key := func(suffix string) string {
+		return fmt.Sprintf("%s:%s:%s", meta.prefix, meta.queueName, suffix)
+	}
+
+	lists := []string{key(bullMQWaitListSuffix)}
+	if meta.includeActive {
+		lists = append(lists, key(bullMQActiveListSuffix))
+	}
+
+	sets := []string{key(bullMQPrioritizedSetSuffix)}
+	if meta.includeDelayed {
+		sets = append(sets, key(bullMQDelayedSetSuffix))
+	}
+	return lists, sets
+}
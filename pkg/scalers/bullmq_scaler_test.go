@@ -0,0 +1,102 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseBullMQMetadataTestData struct {
+	metadata   map[string]string
+	isError    bool
+	authParams map[string]string
+}
+
+type bullMQMetricIdentifier struct {
+	metadataTestData *parseBullMQMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testBullMQMetadata = []parseBullMQMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true, map[string]string{}},
+	// properly formed
+	{map[string]string{"address": "redis:6379", "queueName": "emails", "jobCount": "10"}, false, map[string]string{}},
+	// all options
+	{map[string]string{"address": "redis:6379", "queueName": "video.transcode", "prefix": "{bull}", "includeDelayed": "false", "includeActive": "true", "databaseIndex": "1"}, false, map[string]string{}},
+	// address from authParams
+	{map[string]string{"queueName": "emails"}, false, map[string]string{"address": "redis:6379", "password": "secret"}},
+	// no address
+	{map[string]string{"queueName": "emails"}, true, map[string]string{}},
+	// no queueName
+	{map[string]string{"address": "redis:6379"}, true, map[string]string{}},
+	// improperly formed jobCount
+	{map[string]string{"address": "redis:6379", "queueName": "emails", "jobCount": "AA"}, true, map[string]string{}},
+	// zero jobCount
+	{map[string]string{"address": "redis:6379", "queueName": "emails", "jobCount": "0"}, true, map[string]string{}},
+	// improperly formed includeDelayed
+	{map[string]string{"address": "redis:6379", "queueName": "emails", "includeDelayed": "yes"}, true, map[string]string{}},
+	// improperly formed includeActive
+	{map[string]string{"address": "redis:6379", "queueName": "emails", "includeActive": "yes"}, true, map[string]string{}},
+	// improperly formed databaseIndex
+	{map[string]string{"address": "redis:6379", "queueName": "emails", "databaseIndex": "AA"}, true, map[string]string{}},
+}
+
+var bullMQMetricIdentifiers = []bullMQMetricIdentifier{
+	{&testBullMQMetadata[1], 0, "s0-bullmq-emails"},
+	{&testBullMQMetadata[2], 1, "s1-bullmq-video-transcode"},
+}
+
+func TestBullMQParseMetadata(t *testing.T) {
+	for idx, testData := range testBullMQMetadata {
+		_, err := parseBullMQMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams}, parseRedisAddress)
+		if err != nil && !testData.isError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+	}
+}
+
+func TestBullMQParseClusterAndSentinelMetadata(t *testing.T) {
+	meta, err := parseBullMQMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"addresses": "redis-0:6379, redis-1:6379", "queueName": "emails"}}, parseRedisClusterAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"redis-0:6379", "redis-1:6379"}, meta.connectionInfo.addresses)
+
+	meta, err = parseBullMQMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"addresses": "sentinel:26379", "sentinelMaster": "mymaster", "queueName": "emails"}}, parseRedisSentinelAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, "mymaster", meta.connectionInfo.sentinelMaster)
+}
+
+func TestBullMQGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range bullMQMetricIdentifiers {
+		meta, err := parseBullMQMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex}, parseRedisAddress)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockBullMQScaler := bullMQScaler{"", meta, nil}
+
+		metricSpec := mockBullMQScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestGetBullMQKeys(t *testing.T) {
+	meta, err := parseBullMQMetadata(&ScalerConfig{TriggerMetadata: testBullMQMetadata[1].metadata}, parseRedisAddress)
+	assert.NoError(t, err)
+	lists, sets := getBullMQKeys(meta)
+	assert.Equal(t, []string{"bull:emails:wait"}, lists)
+	assert.Equal(t, []string{"bull:emails:prioritized", "bull:emails:delayed"}, sets)
+
+	meta, err = parseBullMQMetadata(&ScalerConfig{TriggerMetadata: testBullMQMetadata[2].metadata}, parseRedisAddress)
+	assert.NoError(t, err)
+	lists, sets = getBullMQKeys(meta)
+	assert.Equal(t, []string{"{bull}:video.transcode:wait", "{bull}:video.transcode:active"}, lists)
+	assert.Equal(t, []string{"{bull}:video.transcode:prioritized"}, sets)
+}
@@ -43,12 +43,12 @@ var cassandraLog = logf.Log.WithName("cassandra_scaler")
 func NewCassandraScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := ParseCassandraMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing cassandra metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing cassandra metadata: %s", err))
 	}
 
 	session, err := NewCassandraSession(meta)
@@ -0,0 +1,318 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	celeryBrokerRedis        = "redis"
+	celeryBrokerRabbitMQ     = "rabbitmq"
+	celeryDefaultQueue       = "celery"
+	celeryQueueLengthDefault = 5
+	celeryRedisUnackedKey    = "unacked"
+	celeryRedisPrioritySep   = "\x06\x16"
+	celeryRabbitMQRootVhost  = "%2F"
+)
+
+// celeryRedisPrioritySteps are the priorities kombu maps to a dedicated list, priority 0 uses the queue name itself
+var celeryRedisPrioritySteps = []int{3, 6, 9}
+
+type celeryScaler struct {
+	metricType  v2beta2.MetricTargetType
+	metadata    *celeryMetadata
+	redisClient *redis.Client
+	httpClient  *http.Client
+}
+
+type celeryMetadata struct {
+	host           string
+	broker         string
+	queueName      string
+	queueLength    float64
+	includeUnacked bool
+	vhostName      string
+	metricName     string
+	scalerIndex    int
+}
+
+// celeryRabbitMQQueueInfo holds the fields of the RabbitMQ management API queue used by the scaler
+type celeryRabbitMQQueueInfo struct {
+	MessagesReady          int64 `json:"messages_ready"`
+	MessagesUnacknowledged int64 `json:"messages_unacknowledged"`
+}
+
+var celeryLog = logf.Log.WithName("celery_scaler")
+
+// NewCeleryScaler creates a new celeryScaler
+func NewCeleryScaler(ctx context.Context, config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseCeleryMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing celery metadata: %s", err))
+	}
+
+	s := &celeryScaler{
+		metricType: metricType,
+		metadata:   meta,
+	}
+
+	if meta.broker == celeryBrokerRedis {
+		options, err := redis.ParseURL(meta.host)
+		if err != nil {
+			return nil, NewPermanentError(fmt.Errorf("error parsing redis broker url: %s", err))
+		}
+		client := redis.NewClient(options)
+		if err := client.Ping(ctx).Err(); err != nil {
+			return nil, fmt.Errorf("connection to redis failed: %s", err)
+		}
+		s.redisClient = client
+	} else {
+		s.httpClient = createHTTPClient(config, config.GlobalHTTPTimeout, false)
+	}
+
+	return s, nil
+}
+
+func parseCeleryMetadata(config *ScalerConfig) (*celeryMetadata, error) {
+	meta := celeryMetadata{}
+
+	switch {
+	case config.AuthParams["host"] != "":
+		meta.host = config.AuthParams["host"]
+	case config.TriggerMetadata["host"] != "":
+		meta.host = config.TriggerMetadata["host"]
+	case config.TriggerMetadata["hostFromEnv"] != "":
+		meta.host = config.ResolvedEnv[config.TriggerMetadata["hostFromEnv"]]
+	default:
+		return nil, errors.New("no host given")
+	}
+
+	// the broker is detected from the scheme, like Celery does with broker_url
+	hostURL, err := url.Parse(meta.host)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse host: %s", err)
+	}
+	switch hostURL.Scheme {
+	case "redis", "rediss":
+		meta.broker = celeryBrokerRedis
+	case "http", "https":
+		meta.broker = celeryBrokerRabbitMQ
+	default:
+		return nil, fmt.Errorf("host must be a redis:// or rediss:// broker url, or the http:// or https:// url of the RabbitMQ management API, got %s", hostURL.Scheme)
+	}
+
+	meta.queueName = celeryDefaultQueue
+	if val, ok := config.TriggerMetadata["queueName"]; ok && val != "" {
+		meta.queueName = val
+	}
+
+	meta.queueLength = celeryQueueLengthDefault
+	if val, ok := config.TriggerMetadata["queueLength"]; ok {
+		queueLength, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("queueLength parsing error %s", err.Error())
+		}
+		if queueLength <= 0 {
+			return nil, errors.New("queueLength must be greater than 0")
+		}
+		meta.queueLength = queueLength
+	}
+
+	meta.includeUnacked = true
+	if val, ok := config.TriggerMetadata["includeUnacked"]; ok {
+		includeUnacked, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("includeUnacked parsing error %s", err.Error())
+		}
+		meta.includeUnacked = includeUnacked
+	}
+
+	if val, ok := config.TriggerMetadata["vhostName"]; ok {
+		if meta.broker != celeryBrokerRabbitMQ {
+			return nil, errors.New("vhostName can only be used with a RabbitMQ broker")
+		}
+		meta.vhostName = val
+	}
+
+	meta.metricName = kedautil.NormalizeString(fmt.Sprintf("celery-%s", meta.queueName))
+	meta.scalerIndex = config.ScalerIndex
+
+	return &meta, nil
+}
+
+// Close closes the connection to the redis broker
+func (s *celeryScaler) Close(context.Context) error {
+	if s.redisClient == nil {
+		return nil
+	}
+	if err := s.redisClient.Close(); err != nil {
+		celeryLog.Error(err, "error closing redis client")
+		return err
+	}
+	return nil
+}
+
+// IsActive returns true if there are pending tasks in the queue
+func (s *celeryScaler) IsActive(ctx context.Context) (bool, error) {
+	tasks, err := s.getPendingTasks(ctx)
+	if err != nil {
+		celeryLog.Error(err, "error inspecting celery queue")
+		return false, err
+	}
+	return tasks > 0, nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *celeryScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, s.metadata.metricName),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.queueLength),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *celeryScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	tasks, err := s.getPendingTasks(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting celery queue: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(tasks))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// getPendingTasks returns the ready tasks of the queue, plus the unacked ones if includeUnacked is set
+func (s *celeryScaler) getPendingTasks(ctx context.Context) (int64, error) {
+	if s.metadata.broker == celeryBrokerRedis {
+		return s.getPendingTasksFromRedis(ctx)
+	}
+	return s.getPendingTasksFromRabbitMQ(ctx)
+}
+
+func (s *celeryScaler) getPendingTasksFromRedis(ctx context.Context) (int64, error) {
+	var tasks int64
+	for _, key := range getCeleryRedisQueueKeys(s.metadata.queueName) {
+		length, err := s.redisClient.LLen(ctx, key).Result()
+		if err != nil {
+			return -1, err
+		}
+		tasks += length
+	}
+
+	if s.metadata.includeUnacked {
+		// the unacked hash is shared by all the queues
+		unacked, err := s.redisClient.HVals(ctx, celeryRedisUnackedKey).Result()
+		if err != nil {
+			return -1, err
+		}
+		tasks += countCeleryRedisUnacked(unacked, s.metadata.queueName)
+	}
+
+	return tasks, nil
+}
+
+func (s *celeryScaler) getPendingTasksFromRabbitMQ(ctx context.Context) (int64, error) {
+	hostURL, err := url.Parse(s.metadata.host)
+	if err != nil {
+		return -1, err
+	}
+
+	vhost := strings.TrimPrefix(hostURL.Path, "/")
+	if s.metadata.vhostName != "" {
+		vhost = s.metadata.vhostName
+	}
+	if vhost == "" || vhost == "/" {
+		vhost = celeryRabbitMQRootVhost
+	} else {
+		vhost = url.PathEscape(vhost)
+	}
+	hostURL.Path = ""
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/queues/%s/%s", hostURL.String(), vhost, url.PathEscape(s.metadata.queueName)), nil)
+	if err != nil {
+		return -1, err
+	}
+
+	r, err := s.httpClient.Do(req)
+	if err != nil {
+		return -1, err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode == http.StatusNotFound {
+		// Celery declares the queue when the first task is sent or a worker starts
+		return 0, nil
+	}
+	if r.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(r.Body)
+		return -1, fmt.Errorf("error requesting RabbitMQ API status: %s, response: %s", r.Status, body)
+	}
+
+	info := celeryRabbitMQQueueInfo{}
+	if err := json.NewDecoder(r.Body).Decode(&info); err != nil {
+		return -1, err
+	}
+
+	tasks := info.MessagesReady
+	if s.metadata.includeUnacked {
+		tasks += info.MessagesUnacknowledged
+	}
+	return tasks, nil
+}
+
+// getCeleryRedisQueueKeys returns the lists kombu uses for the queue, one per priority step
+func getCeleryRedisQueueKeys(queueName string) []string {
+	keys := []string{queueName}
+	for _, priority := range celeryRedisPrioritySteps {
+		keys = append(keys, fmt.Sprintf("%s%s%d", queueName, celeryRedisPrioritySep, priority))
+	}
+	return keys
+}
+
+// countCeleryRedisUnacked counts the entries of the unacked hash delivered from the queue,
+// each one is a json array of the message, the exchange and the routing key
+func countCeleryRedisUnacked(entries []string, queueName string) int64 {
+	var count int64
+	for _, entry := range entries {
+		var fields []json.RawMessage
+		if err := json.Unmarshal([]byte(entry), &fields); err != nil || len(fields) != 3 {
+			celeryLog.V(1).Info("skipping unreadable unacked celery task")
+			continue
+		}
+		var routingKey string
+		if err := json.Unmarshal(fields[2], &routingKey); err != nil {
+			continue
+		}
+		if routingKey == queueName {
+			count++
+		}
+	}
+	return count
+}
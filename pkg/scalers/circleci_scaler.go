@@ -0,0 +1,182 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	circleciDefaultRunnerAPIURL      = "https://runner.circleci.com/api/v3"
+	circleciDefaultTargetQueueLength = 1
+)
+
+type circleciScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *circleciMetadata
+	httpClient *http.Client
+}
+
+type circleciMetadata struct {
+	runnerAPIURL      string
+	resourceClass     string
+	targetQueueLength float64
+
+	// auth, a personal API token is sent in the Circle-Token header, a resource class token as a bearer token
+	personalAPIToken   string
+	resourceClassToken string
+
+	scalerIndex int
+}
+
+type circleciUnclaimedTasks struct {
+	UnclaimedTaskCount int64 `json:"unclaimed_task_count"`
+}
+
+var circleciLog = logf.Log.WithName("circleci_scaler")
+
+// NewCircleCIScaler creates a new circleciScaler
+func NewCircleCIScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseCircleCIMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing circleci metadata: %s", err))
+	}
+
+	return &circleciScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, false),
+	}, nil
+}
+
+func parseCircleCIMetadata(config *ScalerConfig) (*circleciMetadata, error) {
+	meta := circleciMetadata{}
+
+	meta.runnerAPIURL = circleciDefaultRunnerAPIURL
+	if val, ok := config.TriggerMetadata["runnerAPIURL"]; ok && val != "" {
+		meta.runnerAPIURL = strings.TrimSuffix(val, "/")
+	}
+
+	meta.resourceClass = config.TriggerMetadata["resourceClass"]
+	if meta.resourceClass == "" {
+		return nil, errors.New("no resourceClass given")
+	}
+	if parts := strings.Split(meta.resourceClass, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("resourceClass must be <namespace>/<resource class>, got %s", meta.resourceClass)
+	}
+
+	meta.targetQueueLength = circleciDefaultTargetQueueLength
+	if val, ok := config.TriggerMetadata["targetQueueLength"]; ok {
+		targetQueueLength, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetQueueLength parsing error %s", err.Error())
+		}
+		if targetQueueLength <= 0 {
+			return nil, errors.New("targetQueueLength must be greater than 0")
+		}
+		meta.targetQueueLength = targetQueueLength
+	}
+
+	meta.personalAPIToken = config.AuthParams["personalAPIToken"]
+	meta.resourceClassToken = config.AuthParams["resourceClassToken"]
+	switch {
+	case meta.personalAPIToken == "" && meta.resourceClassToken == "":
+		return nil, errors.New("no personalAPIToken or resourceClassToken given")
+	case meta.personalAPIToken != "" && meta.resourceClassToken != "":
+		return nil, errors.New("only one of personalAPIToken or resourceClassToken can be given")
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// IsActive returns true if there are unclaimed tasks for the resource class
+func (s *circleciScaler) IsActive(ctx context.Context) (bool, error) {
+	tasks, err := s.getUnclaimedTaskCount(ctx)
+	if err != nil {
+		circleciLog.Error(err, "error getting circleci unclaimed tasks")
+		return false, err
+	}
+	return tasks > 0, nil
+}
+
+func (s *circleciScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *circleciScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("circleci-%s", s.metadata.resourceClass))),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetQueueLength),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of unclaimed tasks for the resource class
+func (s *circleciScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	tasks, err := s.getUnclaimedTaskCount(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting circleci unclaimed tasks: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(tasks))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// getUnclaimedTaskCount returns the jobs queued for the resource class and not yet claimed by a runner
+func (s *circleciScaler) getUnclaimedTaskCount(ctx context.Context) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/runner/unclaimed?resource-class=%s", s.metadata.runnerAPIURL, url.QueryEscape(s.metadata.resourceClass)), nil)
+	if err != nil {
+		return -1, err
+	}
+	if s.metadata.personalAPIToken != "" {
+		req.Header.Set("Circle-Token", s.metadata.personalAPIToken)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+s.metadata.resourceClassToken)
+	}
+
+	r, err := s.httpClient.Do(req)
+	if err != nil {
+		return -1, err
+	}
+	defer r.Body.Close()
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return -1, err
+	}
+	if r.StatusCode != http.StatusOK {
+		return -1, fmt.Errorf("error requesting circleci runner API status: %s, response: %s", r.Status, body)
+	}
+
+	var tasks circleciUnclaimedTasks
+	if err := json.Unmarshal(body, &tasks); err != nil {
+		return -1, err
+	}
+	return tasks.UnclaimedTaskCount, nil
+}
@@ -0,0 +1,109 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseCircleCIMetadataTestData struct {
+	metadata   map[string]string
+	isError    bool
+	authParams map[string]string
+}
+
+type circleciMetricIdentifier struct {
+	metadataTestData *parseCircleCIMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testCircleCIAuth = map[string]string{"personalAPIToken": "token"}
+
+var testCircleCIMetadata = []parseCircleCIMetadataTestData{
+	// only resource class
+	{map[string]string{"resourceClass": "kedacore/linux"}, false, testCircleCIAuth},
+	// all properties with a resource class token
+	{map[string]string{"resourceClass": "kedacore/linux-large", "targetQueueLength": "2", "runnerAPIURL": "https://runner.example.com/api/v3/"}, false, map[string]string{"resourceClassToken": "token"}},
+	// missing resource class
+	{map[string]string{}, true, testCircleCIAuth},
+	// resource class without namespace
+	{map[string]string{"resourceClass": "linux"}, true, testCircleCIAuth},
+	// invalid targetQueueLength
+	{map[string]string{"resourceClass": "kedacore/linux", "targetQueueLength": "a"}, true, testCircleCIAuth},
+	{map[string]string{"resourceClass": "kedacore/linux", "targetQueueLength": "0"}, true, testCircleCIAuth},
+	// no token
+	{map[string]string{"resourceClass": "kedacore/linux"}, true, map[string]string{}},
+	// both tokens
+	{map[string]string{"resourceClass": "kedacore/linux"}, true, map[string]string{"personalAPIToken": "token", "resourceClassToken": "token"}},
+}
+
+var circleciMetricIdentifiers = []circleciMetricIdentifier{
+	{&testCircleCIMetadata[0], 0, "s0-circleci-kedacore-linux"},
+	{&testCircleCIMetadata[1], 1, "s1-circleci-kedacore-linux-large"},
+}
+
+func TestCircleCIParseMetadata(t *testing.T) {
+	for _, testData := range testCircleCIMetadata {
+		_, err := parseCircleCIMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestCircleCIGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range circleciMetricIdentifiers {
+		meta, err := parseCircleCIMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockCircleCIScaler := circleciScaler{metadata: meta}
+
+		metricSpec := mockCircleCIScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestCircleCIGetUnclaimedTaskCount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/runner/unclaimed", r.URL.Path)
+		if r.Header.Get("Circle-Token") != "pat" && r.Header.Get("Authorization") != "Bearer resource-class-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Query().Get("resource-class") {
+		case "kedacore/linux":
+			fmt.Fprint(w, `{"unclaimed_task_count":3}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	for _, authParams := range []map[string]string{{"personalAPIToken": "pat"}, {"resourceClassToken": "resource-class-token"}} {
+		meta, err := parseCircleCIMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"runnerAPIURL": server.URL, "resourceClass": "kedacore/linux"}, AuthParams: authParams})
+		assert.NoError(t, err)
+		s := circleciScaler{metadata: meta, httpClient: http.DefaultClient}
+
+		count, err := s.getUnclaimedTaskCount(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	}
+
+	meta, err := parseCircleCIMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"runnerAPIURL": server.URL, "resourceClass": "kedacore/linux"}, AuthParams: map[string]string{"personalAPIToken": "invalid"}})
+	assert.NoError(t, err)
+	s := circleciScaler{metadata: meta, httpClient: http.DefaultClient}
+	_, err = s.getUnclaimedTaskCount(context.Background())
+	assert.Error(t, err)
+}
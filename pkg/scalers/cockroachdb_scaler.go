@@ -0,0 +1,339 @@
+package scalers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	defaultCockroachDBPort       = "26257"
+	defaultCockroachDBSSLMode    = "verify-full"
+	defaultCockroachDBMaxRetries = 3
+
+	// cockroachDBRetryBackoff is multiplied by the attempt to wait before retrying a restarted transaction
+	cockroachDBRetryBackoff = 100 * time.Millisecond
+)
+
+// cockroachDBRetryableCodes are the SQLSTATE of the errors asking the client to retry the transaction: the
+// transaction restarts, RETRY_SERIALIZABLE or RETRY_WRITE_TOO_OLD among others, and the ambiguous results
+var cockroachDBRetryableCodes = map[pq.ErrorCode]bool{
+	"40001": true,
+	"40003": true,
+}
+
+var cockroachDBLog = logf.Log.WithName("cockroachdb_scaler")
+
+type cockroachDBScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *cockroachDBMetadata
+	connection *sql.DB
+}
+
+type cockroachDBMetadata struct {
+	connection                 string
+	query                      string
+	targetQueryValue           float64
+	activationTargetQueryValue float64
+	// asOfSystemTime is the AS OF SYSTEM TIME expression of the query transaction, to read from the followers
+	asOfSystemTime string
+	maxRetries     int
+	metricName     string
+	scalerIndex    int
+}
+
+// NewCockroachDBScaler creates a new cockroachDBScaler, scaling on the result of a query to CockroachDB, optionally
+// a follower read, retried when CockroachDB restarts the transaction
+func NewCockroachDBScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseCockroachDBMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing cockroachdb metadata: %s", err))
+	}
+
+	conn, err := getConnection(meta.connection)
+	if err != nil {
+		return nil, fmt.Errorf("error establishing cockroachdb connection: %s", err)
+	}
+	return &cockroachDBScaler{
+		metricType: metricType,
+		metadata:   meta,
+		connection: conn,
+	}, nil
+}
+
+func parseCockroachDBMetadata(config *ScalerConfig) (*cockroachDBMetadata, error) {
+	meta := cockroachDBMetadata{
+		maxRetries: defaultCockroachDBMaxRetries,
+	}
+
+	if val, ok := config.TriggerMetadata["query"]; ok && val != "" {
+		meta.query = val
+	} else {
+		return nil, errors.New("no query given")
+	}
+
+	if val, ok := config.TriggerMetadata["targetQueryValue"]; ok && val != "" {
+		targetQueryValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetQueryValue parsing error %s", err.Error())
+		}
+		meta.targetQueryValue = targetQueryValue
+	} else {
+		return nil, errors.New("no targetQueryValue given")
+	}
+
+	if val, ok := config.TriggerMetadata["activationTargetQueryValue"]; ok && val != "" {
+		activationTargetQueryValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationTargetQueryValue parsing error %s", err.Error())
+		}
+		meta.activationTargetQueryValue = activationTargetQueryValue
+	}
+
+	if val, ok := config.TriggerMetadata["followerRead"]; ok && val != "" {
+		followerRead, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("followerRead parsing error %s", err.Error())
+		}
+		if followerRead {
+			meta.asOfSystemTime = "follower_read_timestamp()"
+		}
+	}
+	// a negative interval like -10s, bounded staleness reads served by the closest replica
+	if val, ok := config.TriggerMetadata["asOfSystemTime"]; ok && val != "" {
+		if meta.asOfSystemTime != "" {
+			return nil, errors.New("followerRead and asOfSystemTime can't be both given")
+		}
+		interval, err := time.ParseDuration(val)
+		if err != nil || interval >= 0 {
+			return nil, fmt.Errorf("asOfSystemTime must be a negative duration like -10s: %s", val)
+		}
+		meta.asOfSystemTime = fmt.Sprintf("'%s'", val)
+	}
+
+	if val, ok := config.TriggerMetadata["maxRetries"]; ok && val != "" {
+		maxRetries, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("maxRetries parsing error %s", err.Error())
+		}
+		if maxRetries < 0 {
+			return nil, errors.New("maxRetries must be positive")
+		}
+		meta.maxRetries = maxRetries
+	}
+
+	connection, dbName, err := parseCockroachDBConnection(config)
+	if err != nil {
+		return nil, err
+	}
+	meta.connection = connection
+
+	if val, ok := config.TriggerMetadata["metricName"]; ok && val != "" {
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("cockroachdb-%s", val))
+	} else {
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("cockroachdb-%s", dbName))
+	}
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// parseCockroachDBConnection returns the connection string of the connection parameters, given as a whole or by
+// parts, and the database name. The client certificate of the user replaces the password, the certificates are
+// given inline.
+func parseCockroachDBConnection(config *ScalerConfig) (string, string, error) {
+	switch {
+	case config.AuthParams["connection"] != "":
+		return config.AuthParams["connection"], "cockroachdb", nil
+	case config.TriggerMetadata["connectionFromEnv"] != "":
+		return config.ResolvedEnv[config.TriggerMetadata["connectionFromEnv"]], "cockroachdb", nil
+	}
+
+	host, err := GetFromAuthOrMeta(config, "host")
+	if err != nil {
+		return "", "", err
+	}
+
+	port := defaultCockroachDBPort
+	if val, ok := config.TriggerMetadata["port"]; ok && val != "" {
+		if _, err := strconv.Atoi(val); err != nil {
+			return "", "", fmt.Errorf("port parsing error %s", err.Error())
+		}
+		port = val
+	}
+
+	userName, err := GetFromAuthOrMeta(config, "userName")
+	if err != nil {
+		return "", "", err
+	}
+
+	dbName, err := GetFromAuthOrMeta(config, "dbName")
+	if err != nil {
+		return "", "", err
+	}
+
+	sslmode := defaultCockroachDBSSLMode
+	if val, ok := config.TriggerMetadata["sslmode"]; ok && val != "" {
+		sslmode = val
+	}
+
+	options := [][2]string{
+		{"host", host},
+		{"port", port},
+		{"user", userName},
+		{"dbname", dbName},
+		{"sslmode", sslmode},
+	}
+
+	var password string
+	if config.AuthParams["password"] != "" {
+		password = config.AuthParams["password"]
+	} else if config.TriggerMetadata["passwordFromEnv"] != "" {
+		password = config.ResolvedEnv[config.TriggerMetadata["passwordFromEnv"]]
+	}
+	if password != "" {
+		options = append(options, [2]string{"password", password})
+	}
+
+	ca, cert, key := config.AuthParams["ca"], config.AuthParams["cert"], config.AuthParams["key"]
+	if (cert == "") != (key == "") {
+		return "", "", errors.New("both cert and key must be given")
+	}
+	if password == "" && cert == "" {
+		return "", "", errors.New("no password or client certificate given")
+	}
+	if ca != "" || cert != "" {
+		options = append(options, [2]string{"sslinline", "true"})
+	}
+	if ca != "" {
+		options = append(options, [2]string{"sslrootcert", ca})
+	}
+	if cert != "" {
+		options = append(options, [2]string{"sslcert", cert}, [2]string{"sslkey", key})
+	}
+
+	parts := make([]string, 0, len(options))
+	for _, option := range options {
+		parts = append(parts, fmt.Sprintf("%s=%s", option[0], quoteCockroachDBConnectionValue(option[1])))
+	}
+	return strings.Join(parts, " "), dbName, nil
+}
+
+// quoteCockroachDBConnectionValue quotes a value of a key/value connection string, like the PEM certificates
+func quoteCockroachDBConnectionValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return fmt.Sprintf("'%s'", value)
+}
+
+// getQueryResult runs the query, in a read-only transaction at asOfSystemTime if given, the transactions restarted by
+// CockroachDB being retried up to maxRetries times
+func (s *cockroachDBScaler) getQueryResult(ctx context.Context) (float64, error) {
+	var err error
+	for attempt := 0; attempt <= s.metadata.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(attempt) * cockroachDBRetryBackoff):
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+		}
+
+		var value float64
+		value, err = s.queryOnce(ctx)
+		if err == nil {
+			return value, nil
+		}
+		var pqErr *pq.Error
+		if !errors.As(err, &pqErr) || !cockroachDBRetryableCodes[pqErr.Code] {
+			break
+		}
+		cockroachDBLog.V(1).Info("Retrying the restarted query transaction", "attempt", attempt+1, "error", err.Error())
+	}
+	return 0, fmt.Errorf("could not query cockroachdb: %s", err)
+}
+
+func (s *cockroachDBScaler) queryOnce(ctx context.Context) (float64, error) {
+	var value float64
+	if s.metadata.asOfSystemTime == "" {
+		err := s.connection.QueryRowContext(ctx, s.metadata.query).Scan(&value)
+		return value, err
+	}
+
+	tx, err := s.connection.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET TRANSACTION AS OF SYSTEM TIME %s", s.metadata.asOfSystemTime)); err != nil {
+		return 0, err
+	}
+	if err := tx.QueryRowContext(ctx, s.metadata.query).Scan(&value); err != nil {
+		return 0, err
+	}
+	return value, tx.Commit()
+}
+
+// Close disposes of the cockroachdb connections
+func (s *cockroachDBScaler) Close(context.Context) error {
+	if err := s.connection.Close(); err != nil {
+		cockroachDBLog.Error(err, "error closing cockroachdb connection")
+		return err
+	}
+	return nil
+}
+
+// IsActive returns true if the result of the query is over activationTargetQueryValue
+func (s *cockroachDBScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getQueryResult(ctx)
+	if err != nil {
+		cockroachDBLog.Error(err, "error getting cockroachdb query result")
+		return false, err
+	}
+
+	return value > s.metadata.activationTargetQueryValue, nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *cockroachDBScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, s.metadata.metricName),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetQueryValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the result of the query
+func (s *cockroachDBScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getQueryResult(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting cockroachdb: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, value)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,246 @@
+package scalers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+type parseCockroachDBMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type cockroachDBMetricIdentifier struct {
+	metadataTestData *parseCockroachDBMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testCockroachDBMetadata = []parseCockroachDBMetadataTestData{
+	// connection string
+	{map[string]string{"query": "SELECT count(*) FROM jobs", "targetQueryValue": "10"}, map[string]string{"connection": "postgresql://keda@cockroachdb:26257/queue?sslmode=verify-full"}, false},
+	// password, follower read and metric name
+	{map[string]string{"query": "SELECT count(*) FROM jobs", "targetQueryValue": "10", "activationTargetQueryValue": "2", "host": "cockroachdb", "userName": "keda", "dbName": "queue", "followerRead": "true", "maxRetries": "5", "metricName": "jobs"}, map[string]string{"password": "s3cret"}, false},
+	// client certificate and bounded staleness
+	{map[string]string{"query": "SELECT count(*) FROM jobs", "targetQueryValue": "10", "host": "cockroachdb", "port": "26258", "userName": "keda", "dbName": "queue", "asOfSystemTime": "-10s"}, map[string]string{"ca": "ca", "cert": "cert", "key": "key"}, false},
+	// missing query
+	{map[string]string{"targetQueryValue": "10"}, map[string]string{"connection": "postgresql://keda@cockroachdb:26257/queue"}, true},
+	// missing targetQueryValue
+	{map[string]string{"query": "SELECT count(*) FROM jobs"}, map[string]string{"connection": "postgresql://keda@cockroachdb:26257/queue"}, true},
+	// invalid values
+	{map[string]string{"query": "SELECT count(*) FROM jobs", "targetQueryValue": "a"}, map[string]string{"connection": "postgresql://keda@cockroachdb:26257/queue"}, true},
+	{map[string]string{"query": "SELECT count(*) FROM jobs", "targetQueryValue": "10", "activationTargetQueryValue": "a"}, map[string]string{"connection": "postgresql://keda@cockroachdb:26257/queue"}, true},
+	{map[string]string{"query": "SELECT count(*) FROM jobs", "targetQueryValue": "10", "followerRead": "a"}, map[string]string{"connection": "postgresql://keda@cockroachdb:26257/queue"}, true},
+	{map[string]string{"query": "SELECT count(*) FROM jobs", "targetQueryValue": "10", "maxRetries": "-1"}, map[string]string{"connection": "postgresql://keda@cockroachdb:26257/queue"}, true},
+	{map[string]string{"query": "SELECT count(*) FROM jobs", "targetQueryValue": "10", "asOfSystemTime": "10s"}, map[string]string{"connection": "postgresql://keda@cockroachdb:26257/queue"}, true},
+	{map[string]string{"query": "SELECT count(*) FROM jobs", "targetQueryValue": "10", "asOfSystemTime": "-10s'; DROP TABLE jobs; --"}, map[string]string{"connection": "postgresql://keda@cockroachdb:26257/queue"}, true},
+	// followerRead and asOfSystemTime
+	{map[string]string{"query": "SELECT count(*) FROM jobs", "targetQueryValue": "10", "followerRead": "true", "asOfSystemTime": "-10s"}, map[string]string{"connection": "postgresql://keda@cockroachdb:26257/queue"}, true},
+	// no password nor client certificate
+	{map[string]string{"query": "SELECT count(*) FROM jobs", "targetQueryValue": "10", "host": "cockroachdb", "userName": "keda", "dbName": "queue"}, map[string]string{}, true},
+	// cert without key
+	{map[string]string{"query": "SELECT count(*) FROM jobs", "targetQueryValue": "10", "host": "cockroachdb", "userName": "keda", "dbName": "queue"}, map[string]string{"cert": "cert"}, true},
+}
+
+var cockroachDBMetricIdentifiers = []cockroachDBMetricIdentifier{
+	{&testCockroachDBMetadata[0], 0, "s0-cockroachdb-cockroachdb"},
+	{&testCockroachDBMetadata[1], 1, "s1-cockroachdb-jobs"},
+	{&testCockroachDBMetadata[2], 2, "s2-cockroachdb-queue"},
+}
+
+func TestCockroachDBParseMetadata(t *testing.T) {
+	for _, testData := range testCockroachDBMetadata {
+		_, err := parseCockroachDBMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestCockroachDBParseConnection(t *testing.T) {
+	meta, err := parseCockroachDBMetadata(&ScalerConfig{TriggerMetadata: testCockroachDBMetadata[1].metadata, AuthParams: testCockroachDBMetadata[1].authParams})
+	assert.NoError(t, err)
+	assert.Equal(t, "host='cockroachdb' port='26257' user='keda' dbname='queue' sslmode='verify-full' password='s3cret'", meta.connection)
+	assert.Equal(t, "follower_read_timestamp()", meta.asOfSystemTime)
+	assert.Equal(t, 5, meta.maxRetries)
+
+	meta, err = parseCockroachDBMetadata(&ScalerConfig{TriggerMetadata: testCockroachDBMetadata[2].metadata,
+		AuthParams: map[string]string{"ca": "-----BEGIN CERTIFICATE-----\nca\n-----END CERTIFICATE-----", "cert": "cert", "key": `it's\key`}})
+	assert.NoError(t, err)
+	assert.Equal(t, "host='cockroachdb' port='26258' user='keda' dbname='queue' sslmode='verify-full' sslinline='true' "+
+		"sslrootcert='-----BEGIN CERTIFICATE-----\nca\n-----END CERTIFICATE-----' sslcert='cert' sslkey='it\\'s\\\\key'", meta.connection)
+	assert.Equal(t, "'-10s'", meta.asOfSystemTime)
+}
+
+func TestCockroachDBGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range cockroachDBMetricIdentifiers {
+		meta, err := parseCockroachDBMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockCockroachDBScaler := cockroachDBScaler{metadata: meta}
+
+		metricSpec := mockCockroachDBScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+// fakeCockroachDBDriver fails the first queries with the given errors and records the statements
+type fakeCockroachDBDriver struct {
+	lock       sync.Mutex
+	errs       []error
+	statements []string
+}
+
+func (d *fakeCockroachDBDriver) Open(string) (driver.Conn, error) {
+	return &fakeCockroachDBConn{driver: d}, nil
+}
+
+type fakeCockroachDBConn struct {
+	driver *fakeCockroachDBDriver
+}
+
+func (c *fakeCockroachDBConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeCockroachDBStmt{driver: c.driver, query: query}, nil
+}
+
+func (c *fakeCockroachDBConn) Close() error {
+	return nil
+}
+
+func (c *fakeCockroachDBConn) Begin() (driver.Tx, error) {
+	c.driver.record("BEGIN")
+	return c, nil
+}
+
+func (c *fakeCockroachDBConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if opts.ReadOnly {
+		c.driver.record("BEGIN READ ONLY")
+	} else {
+		c.driver.record("BEGIN")
+	}
+	return c, nil
+}
+
+func (c *fakeCockroachDBConn) Commit() error {
+	c.driver.record("COMMIT")
+	return nil
+}
+
+func (c *fakeCockroachDBConn) Rollback() error {
+	return nil
+}
+
+func (d *fakeCockroachDBDriver) record(statement string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.statements = append(d.statements, statement)
+}
+
+type fakeCockroachDBStmt struct {
+	driver *fakeCockroachDBDriver
+	query  string
+}
+
+func (s *fakeCockroachDBStmt) Close() error {
+	return nil
+}
+
+func (s *fakeCockroachDBStmt) NumInput() int {
+	return 0
+}
+
+func (s *fakeCockroachDBStmt) Exec([]driver.Value) (driver.Result, error) {
+	s.driver.record(s.query)
+	return driver.RowsAffected(0), nil
+}
+
+func (s *fakeCockroachDBStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.driver.record(s.query)
+	s.driver.lock.Lock()
+	defer s.driver.lock.Unlock()
+	if len(s.driver.errs) > 0 {
+		err := s.driver.errs[0]
+		s.driver.errs = s.driver.errs[1:]
+		return nil, err
+	}
+	return &fakeCockroachDBRows{}, nil
+}
+
+type fakeCockroachDBRows struct {
+	done bool
+}
+
+func (r *fakeCockroachDBRows) Columns() []string {
+	return []string{"count"}
+}
+
+func (r *fakeCockroachDBRows) Close() error {
+	return nil
+}
+
+func (r *fakeCockroachDBRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(42)
+	return nil
+}
+
+func TestCockroachDBGetQueryResult(t *testing.T) {
+	restart := &pq.Error{Code: "40001", Message: "restart transaction: TransactionRetryWithProtoRefreshError"}
+	testCases := []struct {
+		name       string
+		metadata   map[string]string
+		errs       []error
+		statements []string
+		isError    bool
+	}{
+		{"no error", map[string]string{}, nil, []string{"SELECT count(*) FROM jobs"}, false},
+		{"retried restarts", map[string]string{"maxRetries": "2"}, []error{restart, restart}, []string{"SELECT count(*) FROM jobs", "SELECT count(*) FROM jobs", "SELECT count(*) FROM jobs"}, false},
+		{"too many restarts", map[string]string{"maxRetries": "1"}, []error{restart, restart}, []string{"SELECT count(*) FROM jobs", "SELECT count(*) FROM jobs"}, true},
+		{"not retried", map[string]string{}, []error{&pq.Error{Code: "42P01", Message: "relation \"jobs\" does not exist"}}, []string{"SELECT count(*) FROM jobs"}, true},
+		{"follower read", map[string]string{"followerRead": "true"}, nil, []string{"BEGIN READ ONLY", "SET TRANSACTION AS OF SYSTEM TIME follower_read_timestamp()", "SELECT count(*) FROM jobs", "COMMIT"}, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fakeDriver := &fakeCockroachDBDriver{errs: testCase.errs}
+			driverName := "fake-cockroachdb-" + testCase.name
+			sql.Register(driverName, fakeDriver)
+			db, err := sql.Open(driverName, "")
+			assert.NoError(t, err)
+			defer db.Close()
+
+			testCase.metadata["query"] = "SELECT count(*) FROM jobs"
+			testCase.metadata["targetQueryValue"] = "10"
+			meta, err := parseCockroachDBMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, AuthParams: map[string]string{"connection": "postgresql://keda@cockroachdb:26257/queue"}})
+			assert.NoError(t, err)
+			s := cockroachDBScaler{metadata: meta, connection: db}
+
+			value, err := s.getQueryResult(context.Background())
+			if testCase.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, float64(42), value)
+			}
+			assert.Equal(t, testCase.statements, fakeDriver.statements)
+		})
+	}
+}
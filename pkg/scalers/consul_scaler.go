@@ -0,0 +1,269 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const consulTokenHeader = "X-Consul-Token"
+
+type consulScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *consulMetadata
+	httpClient *http.Client
+}
+
+type consulMetadata struct {
+	address     string
+	kvKey       string
+	service     string
+	tag         string
+	datacenter  string
+	namespace   string
+	targetValue float64
+	metricName  string
+	scalerIndex int
+
+	token string
+
+	enableTLS bool
+	cert      string
+	key       string
+	ca        string
+}
+
+var consulLog = logf.Log.WithName("consul_scaler")
+
+// NewConsulScaler creates a new consul scaler
+func NewConsulScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseConsulMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing consul metadata: %s", err))
+	}
+
+	httpClient := createHTTPClient(config, config.GlobalHTTPTimeout, false)
+	if meta.enableTLS {
+		tlsConfig, err := kedautil.NewTLSConfig(meta.cert, meta.key, meta.ca)
+		if err != nil {
+			return nil, err
+		}
+		httpClient.Transport = &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment}
+	}
+
+	return &consulScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: httpClient,
+	}, nil
+}
+
+func parseConsulMetadata(config *ScalerConfig) (*consulMetadata, error) {
+	meta := consulMetadata{}
+
+	address, err := GetFromAuthOrMeta(config, "address")
+	if err != nil {
+		return nil, err
+	}
+	meta.address = strings.TrimSuffix(address, "/")
+
+	meta.kvKey = config.TriggerMetadata["key"]
+	meta.service = config.TriggerMetadata["service"]
+	switch {
+	case meta.kvKey == "" && meta.service == "":
+		return nil, errors.New("no key or service given")
+	case meta.kvKey != "" && meta.service != "":
+		return nil, errors.New("key and service are mutually exclusive")
+	}
+	meta.tag = config.TriggerMetadata["tag"]
+	if meta.tag != "" && meta.service == "" {
+		return nil, errors.New("tag can only be used with service")
+	}
+
+	if val, ok := config.TriggerMetadata["targetValue"]; ok {
+		targetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetValue parsing error %s", err.Error())
+		}
+		meta.targetValue = targetValue
+	} else {
+		return nil, errors.New("no targetValue given")
+	}
+
+	meta.datacenter = config.TriggerMetadata["datacenter"]
+	meta.namespace = config.TriggerMetadata["namespace"]
+
+	if config.AuthParams["token"] != "" {
+		meta.token = config.AuthParams["token"]
+	} else if config.TriggerMetadata["tokenFromEnv"] != "" {
+		meta.token = config.ResolvedEnv[config.TriggerMetadata["tokenFromEnv"]]
+	}
+
+	if val, ok := config.AuthParams["tls"]; ok {
+		val = strings.TrimSpace(val)
+
+		if val == "enable" {
+			certGiven := config.AuthParams["cert"] != ""
+			keyGiven := config.AuthParams["key"] != ""
+			if certGiven && !keyGiven {
+				return nil, errors.New("key must be provided with cert")
+			}
+			if keyGiven && !certGiven {
+				return nil, errors.New("cert must be provided with key")
+			}
+			meta.ca = config.AuthParams["ca"]
+			meta.cert = config.AuthParams["cert"]
+			meta.key = config.AuthParams["key"]
+			meta.enableTLS = true
+		} else if val != "disable" {
+			return nil, fmt.Errorf("err incorrect value for TLS given: %s", val)
+		}
+	}
+
+	if meta.kvKey != "" {
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("consul-kv-%s", meta.kvKey))
+	} else {
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("consul-service-%s", meta.service))
+	}
+	meta.scalerIndex = config.ScalerIndex
+
+	return &meta, nil
+}
+
+// Close does nothing in case of consulScaler
+func (s *consulScaler) Close(context.Context) error {
+	return nil
+}
+
+// IsActive returns true if the value or the number of healthy instances is greater than zero
+func (s *consulScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		consulLog.Error(err, "error inspecting consul")
+		return false, err
+	}
+	return value > 0, nil
+}
+
+func (s *consulScaler) getValue(ctx context.Context) (float64, error) {
+	if s.metadata.kvKey != "" {
+		return s.getKVValue(ctx)
+	}
+	return s.getHealthyInstances(ctx)
+}
+
+// buildURL returns the url of the given API path, scoped to the configured datacenter and namespace
+func (s *consulScaler) buildURL(path string, query url.Values) string {
+	if s.metadata.datacenter != "" {
+		query.Set("dc", s.metadata.datacenter)
+	}
+	if s.metadata.namespace != "" {
+		query.Set("ns", s.metadata.namespace)
+	}
+	return fmt.Sprintf("%s%s?%s", s.metadata.address, path, query.Encode())
+}
+
+func (s *consulScaler) doRequest(ctx context.Context, requestURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.metadata.token != "" {
+		req.Header.Set(consulTokenHeader, s.metadata.token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("consul returned %d: %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+// getKVValue returns the numeric value stored under the key
+func (s *consulScaler) getKVValue(ctx context.Context) (float64, error) {
+	query := url.Values{}
+	query.Set("raw", "")
+	body, err := s.doRequest(ctx, s.buildURL("/v1/kv/"+strings.TrimPrefix(s.metadata.kvKey, "/"), query))
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(string(body)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("value of key %s is not numeric: %s", s.metadata.kvKey, err)
+	}
+	return value, nil
+}
+
+// getHealthyInstances returns the number of service instances passing all their health checks
+func (s *consulScaler) getHealthyInstances(ctx context.Context) (float64, error) {
+	query := url.Values{}
+	query.Set("passing", "true")
+	if s.metadata.tag != "" {
+		query.Set("tag", s.metadata.tag)
+	}
+	body, err := s.doRequest(ctx, s.buildURL("/v1/health/service/"+url.PathEscape(s.metadata.service), query))
+	if err != nil {
+		return 0, err
+	}
+
+	var instances []json.RawMessage
+	if err := json.Unmarshal(body, &instances); err != nil {
+		return 0, fmt.Errorf("error decoding consul health response: %s", err)
+	}
+	return float64(len(instances)), nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *consulScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, s.metadata.metricName),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *consulScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting consul: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, value)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,143 @@
+package scalers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type parseConsulMetadataTestData struct {
+	metadata    map[string]string
+	authParams  map[string]string
+	raisesError bool
+}
+
+type consulMetricIdentifier struct {
+	metadataTestData *parseConsulMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testConsulMetadata = []parseConsulMetadataTestData{
+	// nothing passed
+	{map[string]string{}, map[string]string{}, true},
+	// properly formed kv
+	{map[string]string{"address": "http://consul:8500", "key": "queues/jobs/length", "targetValue": "10"}, map[string]string{}, false},
+	// properly formed service with tag, datacenter, namespace and token
+	{map[string]string{"address": "http://consul:8500", "service": "worker", "tag": "v2", "datacenter": "dc2", "namespace": "team", "targetValue": "2"}, map[string]string{"token": "secret"}, false},
+	// no address
+	{map[string]string{"key": "queues/jobs/length", "targetValue": "10"}, map[string]string{}, true},
+	// neither key nor service
+	{map[string]string{"address": "http://consul:8500", "targetValue": "10"}, map[string]string{}, true},
+	// both key and service
+	{map[string]string{"address": "http://consul:8500", "key": "queues/jobs/length", "service": "worker", "targetValue": "10"}, map[string]string{}, true},
+	// tag without service
+	{map[string]string{"address": "http://consul:8500", "key": "queues/jobs/length", "tag": "v2", "targetValue": "10"}, map[string]string{}, true},
+	// no targetValue
+	{map[string]string{"address": "http://consul:8500", "key": "queues/jobs/length"}, map[string]string{}, true},
+	// invalid targetValue
+	{map[string]string{"address": "http://consul:8500", "key": "queues/jobs/length", "targetValue": "a"}, map[string]string{}, true},
+	// mTLS
+	{map[string]string{"address": "https://consul:8501", "key": "queues/jobs/length", "targetValue": "10"}, map[string]string{"tls": "enable", "ca": "caaa", "cert": "ceert", "key": "keey"}, false},
+	// invalid tls value
+	{map[string]string{"address": "https://consul:8501", "key": "queues/jobs/length", "targetValue": "10"}, map[string]string{"tls": "yes"}, true},
+}
+
+var consulMetricIdentifiers = []consulMetricIdentifier{
+	{&testConsulMetadata[1], 0, "s0-consul-kv-queues-jobs-length"},
+	{&testConsulMetadata[2], 1, "s1-consul-service-worker"},
+}
+
+func TestParseConsulMetadata(t *testing.T) {
+	for idx, testData := range testConsulMetadata {
+		_, err := parseConsulMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.raisesError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if err == nil && testData.raisesError {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+	}
+}
+
+func TestConsulGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range consulMetricIdentifiers {
+		meta, err := parseConsulMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockConsulScaler := consulScaler{"", meta, nil}
+
+		metricSpec := mockConsulScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestConsulGetValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(consulTokenHeader) != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/v1/kv/queues/jobs/length":
+			if _, ok := query["raw"]; !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte("12"))
+		case "/v1/kv/queues/jobs/name":
+			_, _ = w.Write([]byte("jobs"))
+		case "/v1/health/service/worker":
+			if query.Get("passing") != "true" || query.Get("dc") != "dc2" || query.Get("ns") != "team" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if query.Get("tag") == "v2" {
+				_, _ = w.Write([]byte(`[{"Service":{"ID":"worker-1"}}]`))
+				return
+			}
+			_, _ = w.Write([]byte(`[{"Service":{"ID":"worker-1"}},{"Service":{"ID":"worker-2"}},{"Service":{"ID":"worker-3"}}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		metadata    map[string]string
+		expected    float64
+		raisesError bool
+	}{
+		{map[string]string{"address": server.URL, "key": "queues/jobs/length", "targetValue": "1"}, 12, false},
+		{map[string]string{"address": server.URL, "key": "/queues/jobs/length", "targetValue": "1"}, 12, false},
+		{map[string]string{"address": server.URL, "key": "queues/jobs/name", "targetValue": "1"}, 0, true},
+		{map[string]string{"address": server.URL, "key": "queues/missing", "targetValue": "1"}, 0, true},
+		{map[string]string{"address": server.URL, "service": "worker", "datacenter": "dc2", "namespace": "team", "targetValue": "1"}, 3, false},
+		{map[string]string{"address": server.URL, "service": "worker", "tag": "v2", "datacenter": "dc2", "namespace": "team", "targetValue": "1"}, 1, false},
+	}
+
+	for idx, test := range tests {
+		meta, err := parseConsulMetadata(&ScalerConfig{TriggerMetadata: test.metadata, AuthParams: map[string]string{"token": "secret"}})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		scaler := consulScaler{metadata: meta, httpClient: http.DefaultClient}
+
+		value, err := scaler.getValue(context.Background())
+		if err != nil && !test.raisesError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if err == nil && test.raisesError {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+		if value != test.expected {
+			t.Errorf("Expected %f but got %f for test %d", test.expected, value, idx)
+		}
+	}
+}
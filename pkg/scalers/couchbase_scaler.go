@@ -0,0 +1,334 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+type couchbaseMode string
+
+const (
+	couchbaseModeQuery           couchbaseMode = "query"
+	couchbaseModeBucketItemCount couchbaseMode = "bucketItemCount"
+)
+
+type couchbaseScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *couchbaseMetadata
+	httpClient *http.Client
+}
+
+type couchbaseMetadata struct {
+	mode        couchbaseMode
+	queryURL    string
+	query       string
+	adminURL    string
+	bucket      string
+	targetValue float64
+	metricName  string
+	scalerIndex int
+
+	username string
+	password string
+
+	enableTLS bool
+	cert      string
+	key       string
+	ca        string
+}
+
+type couchbaseQueryResponse struct {
+	Status  string            `json:"status"`
+	Results []json.RawMessage `json:"results"`
+	Errors  []struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	} `json:"errors"`
+}
+
+type couchbaseBucketResponse struct {
+	BasicStats struct {
+		ItemCount float64 `json:"itemCount"`
+	} `json:"basicStats"`
+}
+
+var couchbaseLog = logf.Log.WithName("couchbase_scaler")
+
+// NewCouchbaseScaler creates a new couchbase scaler
+func NewCouchbaseScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseCouchbaseMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing couchbase metadata: %s", err))
+	}
+
+	httpClient := createHTTPClient(config, config.GlobalHTTPTimeout, false)
+	if meta.enableTLS {
+		tlsConfig, err := kedautil.NewTLSConfig(meta.cert, meta.key, meta.ca)
+		if err != nil {
+			return nil, err
+		}
+		httpClient.Transport = &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment}
+	}
+
+	return &couchbaseScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: httpClient,
+	}, nil
+}
+
+func parseCouchbaseMetadata(config *ScalerConfig) (*couchbaseMetadata, error) {
+	meta := couchbaseMetadata{}
+
+	meta.mode = couchbaseModeQuery
+	if val, ok := config.TriggerMetadata["mode"]; ok && val != "" {
+		mode := couchbaseMode(val)
+		if mode != couchbaseModeQuery && mode != couchbaseModeBucketItemCount {
+			return nil, fmt.Errorf("invalid mode %s, must be either %s or %s", val, couchbaseModeQuery, couchbaseModeBucketItemCount)
+		}
+		meta.mode = mode
+	}
+
+	switch meta.mode {
+	case couchbaseModeQuery:
+		queryURL, err := GetFromAuthOrMeta(config, "queryURL")
+		if err != nil {
+			return nil, err
+		}
+		meta.queryURL = queryURL
+
+		if val, ok := config.TriggerMetadata["query"]; ok && val != "" {
+			meta.query = val
+		} else {
+			return nil, fmt.Errorf("no query given")
+		}
+	case couchbaseModeBucketItemCount:
+		adminURL, err := GetFromAuthOrMeta(config, "adminURL")
+		if err != nil {
+			return nil, err
+		}
+		meta.adminURL = adminURL
+
+		if val, ok := config.TriggerMetadata["bucket"]; ok && val != "" {
+			meta.bucket = val
+		} else {
+			return nil, fmt.Errorf("no bucket given")
+		}
+	}
+
+	if val, ok := config.TriggerMetadata["targetValue"]; ok {
+		targetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetValue parsing error %s", err.Error())
+		}
+		meta.targetValue = targetValue
+	} else {
+		return nil, fmt.Errorf("no targetValue given")
+	}
+
+	meta.username = config.AuthParams["username"]
+	if config.AuthParams["password"] != "" {
+		meta.password = config.AuthParams["password"]
+	} else if config.TriggerMetadata["passwordFromEnv"] != "" {
+		meta.password = config.ResolvedEnv[config.TriggerMetadata["passwordFromEnv"]]
+	}
+
+	if val, ok := config.AuthParams["tls"]; ok {
+		val = strings.TrimSpace(val)
+
+		if val == "enable" {
+			certGiven := config.AuthParams["cert"] != ""
+			keyGiven := config.AuthParams["key"] != ""
+			if certGiven && !keyGiven {
+				return nil, errors.New("key must be provided with cert")
+			}
+			if keyGiven && !certGiven {
+				return nil, errors.New("cert must be provided with key")
+			}
+			meta.ca = config.AuthParams["ca"]
+			meta.cert = config.AuthParams["cert"]
+			meta.key = config.AuthParams["key"]
+			meta.enableTLS = true
+		} else if val != "disable" {
+			return nil, fmt.Errorf("err incorrect value for TLS given: %s", val)
+		}
+	}
+
+	// couchbase authenticates either with credentials or with a client certificate
+	if meta.username == "" && meta.cert == "" {
+		return nil, errors.New("no username or client certificate given")
+	}
+	if meta.username != "" && meta.password == "" {
+		return nil, errors.New("no password given")
+	}
+
+	if val, ok := config.TriggerMetadata["metricName"]; ok && val != "" {
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("couchbase-%s", val))
+	} else if meta.mode == couchbaseModeBucketItemCount {
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("couchbase-%s", meta.bucket))
+	} else {
+		meta.metricName = kedautil.NormalizeString("couchbase")
+	}
+	meta.scalerIndex = config.ScalerIndex
+
+	return &meta, nil
+}
+
+// Close does nothing in case of couchbaseScaler
+func (s *couchbaseScaler) Close(context.Context) error {
+	return nil
+}
+
+// IsActive returns true if there are pending items to be processed
+func (s *couchbaseScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		couchbaseLog.Error(err, "error inspecting couchbase")
+		return false, err
+	}
+	return value > 0, nil
+}
+
+func (s *couchbaseScaler) getValue(ctx context.Context) (float64, error) {
+	if s.metadata.mode == couchbaseModeBucketItemCount {
+		return s.getBucketItemCount(ctx)
+	}
+	return s.getQueryResult(ctx)
+}
+
+func (s *couchbaseScaler) doRequest(req *http.Request) ([]byte, error) {
+	if s.metadata.username != "" {
+		req.SetBasicAuth(s.metadata.username, s.metadata.password)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("couchbase returned %d: %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+// getQueryResult executes the N1QL query and returns its single numeric result
+func (s *couchbaseScaler) getQueryResult(ctx context.Context) (float64, error) {
+	form := url.Values{}
+	form.Set("statement", s.metadata.query)
+
+	queryServiceURL := strings.TrimSuffix(s.metadata.queryURL, "/") + "/query/service"
+	req, err := http.NewRequestWithContext(ctx, "POST", queryServiceURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := s.doRequest(req)
+	if err != nil {
+		return 0, err
+	}
+
+	var response couchbaseQueryResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return 0, fmt.Errorf("error decoding couchbase query response: %s", err)
+	}
+	if len(response.Errors) > 0 {
+		return 0, fmt.Errorf("couchbase query failed: %s", response.Errors[0].Msg)
+	}
+
+	return parseCouchbaseQueryResults(response.Results)
+}
+
+// parseCouchbaseQueryResults accepts either a RAW numeric result (SELECT RAW COUNT(*) ...)
+// or a single row with exactly one numeric field (SELECT COUNT(*) AS c ...)
+func parseCouchbaseQueryResults(results []json.RawMessage) (float64, error) {
+	if len(results) == 0 {
+		return 0, errors.New("couchbase query returned no results")
+	}
+
+	var value float64
+	if err := json.Unmarshal(results[0], &value); err == nil {
+		return value, nil
+	}
+
+	var row map[string]float64
+	if err := json.Unmarshal(results[0], &row); err != nil || len(row) != 1 {
+		return 0, fmt.Errorf("couchbase query must return a single numeric value, got: %s", string(results[0]))
+	}
+	for _, v := range row {
+		value = v
+	}
+	return value, nil
+}
+
+// getBucketItemCount returns the bucket item count from the cluster management API
+func (s *couchbaseScaler) getBucketItemCount(ctx context.Context) (float64, error) {
+	bucketURL := fmt.Sprintf("%s/pools/default/buckets/%s", strings.TrimSuffix(s.metadata.adminURL, "/"), url.PathEscape(s.metadata.bucket))
+	req, err := http.NewRequestWithContext(ctx, "GET", bucketURL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	body, err := s.doRequest(req)
+	if err != nil {
+		return 0, err
+	}
+
+	var response couchbaseBucketResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return 0, fmt.Errorf("error decoding couchbase bucket response: %s", err)
+	}
+	return response.BasicStats.ItemCount, nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *couchbaseScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, s.metadata.metricName),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *couchbaseScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting couchbase: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, value)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
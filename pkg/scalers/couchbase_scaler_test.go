@@ -0,0 +1,147 @@
+package scalers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type parseCouchbaseMetadataTestData struct {
+	metadata    map[string]string
+	authParams  map[string]string
+	raisesError bool
+}
+
+type couchbaseMetricIdentifier struct {
+	metadataTestData *parseCouchbaseMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testCouchbaseAuthParams = map[string]string{"username": "admin", "password": "password"}
+
+var testCouchbaseMetadata = []parseCouchbaseMetadataTestData{
+	// nothing passed
+	{map[string]string{}, testCouchbaseAuthParams, true},
+	// query mode
+	{map[string]string{"queryURL": "http://cb:8093", "query": "SELECT RAW COUNT(*) FROM jobs", "targetValue": "10"}, testCouchbaseAuthParams, false},
+	// query mode with metricName
+	{map[string]string{"queryURL": "http://cb:8093", "query": "SELECT RAW COUNT(*) FROM jobs", "targetValue": "10", "metricName": "jobs"}, testCouchbaseAuthParams, false},
+	// query mode without query
+	{map[string]string{"queryURL": "http://cb:8093", "targetValue": "10"}, testCouchbaseAuthParams, true},
+	// query mode without queryURL
+	{map[string]string{"query": "SELECT RAW COUNT(*) FROM jobs", "targetValue": "10"}, testCouchbaseAuthParams, true},
+	// bucket mode
+	{map[string]string{"mode": "bucketItemCount", "adminURL": "http://cb:8091", "bucket": "jobs", "targetValue": "10"}, testCouchbaseAuthParams, false},
+	// bucket mode without bucket
+	{map[string]string{"mode": "bucketItemCount", "adminURL": "http://cb:8091", "targetValue": "10"}, testCouchbaseAuthParams, true},
+	// invalid mode
+	{map[string]string{"mode": "foo", "adminURL": "http://cb:8091", "bucket": "jobs", "targetValue": "10"}, testCouchbaseAuthParams, true},
+	// invalid targetValue
+	{map[string]string{"queryURL": "http://cb:8093", "query": "SELECT RAW COUNT(*) FROM jobs", "targetValue": "a"}, testCouchbaseAuthParams, true},
+	// no credentials
+	{map[string]string{"queryURL": "http://cb:8093", "query": "SELECT RAW COUNT(*) FROM jobs", "targetValue": "10"}, map[string]string{}, true},
+	// username without password
+	{map[string]string{"queryURL": "http://cb:8093", "query": "SELECT RAW COUNT(*) FROM jobs", "targetValue": "10"}, map[string]string{"username": "admin"}, true},
+	// client certificate auth
+	{map[string]string{"queryURL": "https://cb:18093", "query": "SELECT RAW COUNT(*) FROM jobs", "targetValue": "10"}, map[string]string{"tls": "enable", "cert": "ceert", "key": "keey", "ca": "caaa"}, false},
+	// cert without key
+	{map[string]string{"queryURL": "https://cb:18093", "query": "SELECT RAW COUNT(*) FROM jobs", "targetValue": "10"}, map[string]string{"tls": "enable", "cert": "ceert"}, true},
+	// invalid tls value
+	{map[string]string{"queryURL": "https://cb:18093", "query": "SELECT RAW COUNT(*) FROM jobs", "targetValue": "10"}, map[string]string{"username": "admin", "password": "password", "tls": "yes"}, true},
+}
+
+var couchbaseMetricIdentifiers = []couchbaseMetricIdentifier{
+	{&testCouchbaseMetadata[1], 0, "s0-couchbase"},
+	{&testCouchbaseMetadata[2], 1, "s1-couchbase-jobs"},
+	{&testCouchbaseMetadata[5], 2, "s2-couchbase-jobs"},
+}
+
+func TestParseCouchbaseMetadata(t *testing.T) {
+	for idx, testData := range testCouchbaseMetadata {
+		_, err := parseCouchbaseMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.raisesError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if err == nil && testData.raisesError {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+	}
+}
+
+func TestCouchbaseGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range couchbaseMetricIdentifiers {
+		meta, err := parseCouchbaseMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockCouchbaseScaler := couchbaseScaler{"", meta, nil}
+
+		metricSpec := mockCouchbaseScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestCouchbaseGetValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "admin" || pass != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/query/service":
+			switch r.FormValue("statement") {
+			case "raw":
+				_, _ = w.Write([]byte(`{"status":"success","results":[7]}`))
+			case "object":
+				_, _ = w.Write([]byte(`{"status":"success","results":[{"count":5}]}`))
+			case "multi":
+				_, _ = w.Write([]byte(`{"status":"success","results":[{"a":5,"b":6}]}`))
+			default:
+				_, _ = w.Write([]byte(`{"status":"fatal","errors":[{"code":3000,"msg":"syntax error"}]}`))
+			}
+		case "/pools/default/buckets/jobs":
+			_, _ = w.Write([]byte(`{"name":"jobs","basicStats":{"itemCount":42}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		metadata    map[string]string
+		expected    float64
+		raisesError bool
+	}{
+		{map[string]string{"queryURL": server.URL, "query": "raw", "targetValue": "1"}, 7, false},
+		{map[string]string{"queryURL": server.URL, "query": "object", "targetValue": "1"}, 5, false},
+		{map[string]string{"queryURL": server.URL, "query": "multi", "targetValue": "1"}, 0, true},
+		{map[string]string{"queryURL": server.URL, "query": "invalid", "targetValue": "1"}, 0, true},
+		{map[string]string{"mode": "bucketItemCount", "adminURL": server.URL, "bucket": "jobs", "targetValue": "1"}, 42, false},
+		{map[string]string{"mode": "bucketItemCount", "adminURL": server.URL, "bucket": "missing", "targetValue": "1"}, 0, true},
+	}
+
+	for idx, test := range tests {
+		meta, err := parseCouchbaseMetadata(&ScalerConfig{TriggerMetadata: test.metadata, AuthParams: testCouchbaseAuthParams})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		scaler := couchbaseScaler{metadata: meta, httpClient: http.DefaultClient}
+
+		value, err := scaler.getValue(context.Background())
+		if err != nil && !test.raisesError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if err == nil && test.raisesError {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+		if value != test.expected {
+			t.Errorf("Expected %f but got %f for test %d", test.expected, value, idx)
+		}
+	}
+}
@@ -30,7 +30,7 @@ var cpuMemoryLog = logf.Log.WithName("cpu_memory_scaler")
 func NewCPUMemoryScaler(resourceName v1.ResourceName, config *ScalerConfig) (Scaler, error) {
 	meta, parseErr := parseResourceMetadata(config)
 	if parseErr != nil {
-		return nil, fmt.Errorf("error parsing %s metadata: %s", resourceName, parseErr)
+		return nil, NewPermanentError(fmt.Errorf("error parsing %s metadata: %s", resourceName, parseErr))
 	}
 
 	return &cpuMemoryScaler{
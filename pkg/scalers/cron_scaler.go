@@ -40,12 +40,12 @@ var cronLog = logf.Log.WithName("cron_scaler")
 func NewCronScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, parseErr := parseCronMetadata(config)
 	if parseErr != nil {
-		return nil, fmt.Errorf("error parsing cron metadata: %s", parseErr)
+		return nil, NewPermanentError(fmt.Errorf("error parsing cron metadata: %s", parseErr))
 	}
 
 	return &cronScaler{
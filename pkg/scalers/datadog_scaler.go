@@ -3,9 +3,11 @@ package scalers
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	datadog "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
@@ -17,9 +19,20 @@ import (
 	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
+const (
+	// defaultDatadogRateLimitBackoff is the back off after a 429 without X-Ratelimit-Reset header
+	defaultDatadogRateLimitBackoff = time.Minute
+)
+
 type datadogScaler struct {
 	metadata  *datadogMetadata
 	apiClient *datadog.APIClient
+
+	// the last value returned by Datadog, returned while the queries back off from the rate limit
+	lock         sync.Mutex
+	lastValue    float64
+	hasLastValue bool
+	backoffUntil time.Time
 }
 
 type datadogMetadata struct {
@@ -47,7 +60,7 @@ func init() {
 func NewDatadogScaler(ctx context.Context, config *ScalerConfig) (Scaler, error) {
 	meta, err := parseDatadogMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing Datadog metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing Datadog metadata: %s", err))
 	}
 
 	apiClient, err := newDatadogConnection(ctx, meta, config)
@@ -187,7 +200,7 @@ func newDatadogConnection(ctx context.Context, meta *datadogMetadata, config *Sc
 		})
 
 	configuration := datadog.NewConfiguration()
-	configuration.HTTPClient = kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, false)
+	configuration.HTTPClient = createHTTPClient(config, config.GlobalHTTPTimeout, false)
 	apiClient := datadog.NewAPIClient(configuration)
 
 	_, _, err := apiClient.AuthenticationApi.Validate(ctx) //nolint:bodyclose
@@ -214,8 +227,29 @@ func (s *datadogScaler) IsActive(ctx context.Context) (bool, error) {
 	return num > 0, nil
 }
 
-// getQueryResult returns result of the scaler query
+// getQueryResult returns result of the scaler query. Once Datadog rate limits the queries, they back off until the
+// limit resets and the last value is returned meanwhile.
 func (s *datadogScaler) getQueryResult(ctx context.Context) (float64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if time.Now().Before(s.backoffUntil) {
+		if s.hasLastValue {
+			datadogLog.V(1).Info("Datadog rate limit reached, returning the last value", "query", s.metadata.query, "backoffUntil", s.backoffUntil)
+			return s.lastValue, nil
+		}
+		return -1, fmt.Errorf("your Datadog account reached its rate limit, next query will happen after %s", s.backoffUntil.Format(time.RFC3339))
+	}
+
+	value, err := s.queryDatadog(ctx)
+	if err != nil {
+		return value, err
+	}
+	s.lastValue, s.hasLastValue = value, true
+	return value, nil
+}
+
+func (s *datadogScaler) queryDatadog(ctx context.Context) (float64, error) {
 	ctx = context.WithValue(
 		ctx,
 		datadog.ContextAPIKeys,
@@ -236,16 +270,25 @@ func (s *datadogScaler) getQueryResult(ctx context.Context) (float64, error) {
 		})
 
 	resp, r, err := s.apiClient.MetricsApi.QueryMetrics(ctx, time.Now().Unix()-int64(s.metadata.age), time.Now().Unix(), s.metadata.query) //nolint:bodyclose
-	if err != nil {
-		return -1, fmt.Errorf("error when retrieving Datadog metrics: %s", err)
-	}
-
-	if r.StatusCode == 429 {
+	if r != nil && r.StatusCode == http.StatusTooManyRequests {
 		rateLimit := r.Header.Get("X-Ratelimit-Limit")
 		rateLimitReset := r.Header.Get("X-Ratelimit-Reset")
 
+		backoff := defaultDatadogRateLimitBackoff
+		if reset, err := strconv.Atoi(rateLimitReset); err == nil && reset > 0 {
+			backoff = time.Duration(reset) * time.Second
+		}
+		s.backoffUntil = time.Now().Add(backoff)
+
+		if s.hasLastValue {
+			datadogLog.Info("Datadog rate limit reached, returning the last value until the limit resets", "query", s.metadata.query, "rateLimit", rateLimit, "backoff", backoff.String())
+			return s.lastValue, nil
+		}
 		return -1, fmt.Errorf("your Datadog account reached the %s queries per hour rate limit, next limit reset will happen in %s seconds", rateLimit, rateLimitReset)
 	}
+	if err != nil {
+		return -1, fmt.Errorf("error when retrieving Datadog metrics: %s", err)
+	}
 
 	if r.StatusCode != 200 {
 		return -1, fmt.Errorf("error when retrieving Datadog metrics")
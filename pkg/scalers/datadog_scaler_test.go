@@ -2,8 +2,15 @@ package scalers
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	datadog "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	"github.com/stretchr/testify/assert"
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
 )
 
@@ -129,3 +136,59 @@ func TestDatadogGetMetricSpecForScaling(t *testing.T) {
 		}
 	}
 }
+
+func TestDatadogRateLimitBackoff(t *testing.T) {
+	var queries int32
+	var rateLimited int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&queries, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.LoadInt32(&rateLimited) == 1 {
+			w.Header().Set("X-Ratelimit-Limit", "1600")
+			w.Header().Set("X-Ratelimit-Reset", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"errors":["Too many requests"]}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"ok","series":[{"pointlist":[[1653900000000,3],[1653900060000,7]]}]}`)
+	}))
+	defer server.Close()
+
+	configuration := datadog.NewConfiguration()
+	configuration.Servers = datadog.ServerConfigurations{{URL: server.URL}}
+	meta, err := parseDatadogMetadata(&ScalerConfig{TriggerMetadata: testDatadogMetadata[1].metadata, AuthParams: testDatadogMetadata[1].authParams, MetricType: testDatadogMetadata[1].metricType})
+	assert.NoError(t, err)
+
+	// rate limited before any value
+	s := datadogScaler{metadata: meta, apiClient: datadog.NewAPIClient(configuration)}
+	atomic.StoreInt32(&rateLimited, 1)
+	_, err = s.getQueryResult(context.Background())
+	assert.Error(t, err)
+	_, err = s.getQueryResult(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&queries), "the queries must back off")
+
+	// rate limited after a value
+	s = datadogScaler{metadata: meta, apiClient: datadog.NewAPIClient(configuration)}
+	atomic.StoreInt32(&rateLimited, 0)
+	value, err := s.getQueryResult(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, float64(7), value)
+
+	atomic.StoreInt32(&rateLimited, 1)
+	for i := 0; i < 2; i++ {
+		value, err = s.getQueryResult(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, float64(7), value)
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&queries), "the queries must back off")
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), s.backoffUntil, 5*time.Second)
+
+	// the queries resume once the limit resets
+	s.backoffUntil = time.Now().Add(-time.Second)
+	atomic.StoreInt32(&rateLimited, 0)
+	value, err = s.getQueryResult(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, float64(7), value)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&queries))
+}
@@ -0,0 +1,347 @@
+package scalers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/expfmt"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	debeziumLagSourceDebezium   = "debezium"
+	debeziumLagSourcePostgreSQL = "postgresql"
+
+	debeziumMetricsFormatPrometheus = "prometheus"
+	debeziumMetricsFormatJSON       = "json"
+
+	// defaultDebeziumMetricName is MilliSecondsBehindSource of the streaming metrics, as exported by the Prometheus
+	// JMX exporter configuration of the Debezium images
+	defaultDebeziumMetricName = "debezium_metrics_MilliSecondsBehindSource"
+	// defaultDebeziumValueLocation is the value of an attribute read through the Jolokia REST API
+	defaultDebeziumValueLocation = "value"
+
+	defaultDebeziumTargetLag = 10000
+)
+
+// debeziumSlotRetainedBytesQuery returns the WAL bytes the server retains for a replication slot
+const debeziumSlotRetainedBytesQuery = `SELECT COALESCE(pg_wal_lsn_diff(pg_current_wal_lsn(), restart_lsn), 0) FROM pg_replication_slots WHERE slot_name = $1`
+
+var debeziumLog = logf.Log.WithName("debezium_scaler")
+
+type debeziumScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *debeziumMetadata
+	httpClient *http.Client
+	connection *sql.DB
+}
+
+type debeziumMetadata struct {
+	lagSource           string
+	targetLag           float64
+	activationTargetLag float64
+	scalerIndex         int
+
+	// lag reported by Debezium
+	metricsURL    string
+	metricsFormat string
+	metricName    string
+	valueLocation string
+	serverName    string
+	unsafeSsl     bool
+	username      string
+	password      string
+
+	// WAL retained by the replication slot of PostgreSQL
+	connection string
+	slotName   string
+}
+
+// NewDebeziumScaler creates a new debeziumScaler, reading the lag of a CDC pipeline either from the
+// MilliSecondsBehindSource of Debezium or from the WAL bytes PostgreSQL retains for its replication slot
+func NewDebeziumScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseDebeziumMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing debezium metadata: %s", err))
+	}
+
+	scaler := &debeziumScaler{
+		metricType: metricType,
+		metadata:   meta,
+	}
+	if meta.lagSource == debeziumLagSourcePostgreSQL {
+		conn, err := getConnection(meta.connection)
+		if err != nil {
+			return nil, fmt.Errorf("error establishing postgreSQL connection: %s", err)
+		}
+		scaler.connection = conn
+	} else {
+		scaler.httpClient = createHTTPClient(config, config.GlobalHTTPTimeout, meta.unsafeSsl)
+	}
+	return scaler, nil
+}
+
+func parseDebeziumMetadata(config *ScalerConfig) (*debeziumMetadata, error) {
+	meta := debeziumMetadata{
+		lagSource:     debeziumLagSourceDebezium,
+		metricsFormat: debeziumMetricsFormatPrometheus,
+		metricName:    defaultDebeziumMetricName,
+		valueLocation: defaultDebeziumValueLocation,
+		targetLag:     defaultDebeziumTargetLag,
+	}
+
+	if val, ok := config.TriggerMetadata["lagSource"]; ok && val != "" {
+		meta.lagSource = strings.TrimSpace(val)
+	}
+	switch meta.lagSource {
+	case debeziumLagSourceDebezium:
+		if err := parseDebeziumMetricsMetadata(config, &meta); err != nil {
+			return nil, err
+		}
+	case debeziumLagSourcePostgreSQL:
+		if val, ok := config.TriggerMetadata["slotName"]; ok && val != "" {
+			meta.slotName = val
+		} else {
+			return nil, errors.New("no slotName given")
+		}
+		connection, err := parsePostgreSQLConnection(config)
+		if err != nil {
+			return nil, err
+		}
+		meta.connection = connection
+	default:
+		return nil, fmt.Errorf("err incorrect value for lagSource is given: %s", meta.lagSource)
+	}
+
+	if val, ok := config.TriggerMetadata["targetLag"]; ok && val != "" {
+		targetLag, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetLag parsing error %s", err.Error())
+		}
+		meta.targetLag = targetLag
+	}
+
+	if val, ok := config.TriggerMetadata["activationTargetLag"]; ok && val != "" {
+		activationTargetLag, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationTargetLag parsing error %s", err.Error())
+		}
+		meta.activationTargetLag = activationTargetLag
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+func parseDebeziumMetricsMetadata(config *ScalerConfig, meta *debeziumMetadata) error {
+	if val, ok := config.TriggerMetadata["metricsURL"]; ok && val != "" {
+		if _, err := url.ParseRequestURI(val); err != nil {
+			return fmt.Errorf("invalid metricsURL: %s", err)
+		}
+		meta.metricsURL = val
+	} else {
+		return errors.New("no metricsURL given")
+	}
+
+	if val, ok := config.TriggerMetadata["metricsFormat"]; ok && val != "" {
+		meta.metricsFormat = strings.TrimSpace(val)
+	}
+	switch meta.metricsFormat {
+	case debeziumMetricsFormatPrometheus, debeziumMetricsFormatJSON:
+	default:
+		return fmt.Errorf("err incorrect value for metricsFormat is given: %s", meta.metricsFormat)
+	}
+
+	if val, ok := config.TriggerMetadata["metricName"]; ok && val != "" {
+		meta.metricName = val
+	}
+	if val, ok := config.TriggerMetadata["valueLocation"]; ok && val != "" {
+		meta.valueLocation = val
+	}
+	meta.serverName = config.TriggerMetadata["serverName"]
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok && val != "" {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("unsafeSsl parsing error %s", err.Error())
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	meta.username = config.AuthParams["username"]
+	meta.password = config.AuthParams["password"]
+	if meta.username != "" && meta.password == "" {
+		return errors.New("no password given")
+	}
+	return nil
+}
+
+// getDebeziumLag returns the MilliSecondsBehindSource of the streaming metrics, the highest one of the servers
+// unless serverName is given
+func (s *debeziumScaler) getDebeziumLag(ctx context.Context) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.metadata.metricsURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	if s.metadata.username != "" {
+		req.SetBasicAuth(s.metadata.username, s.metadata.password)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("debezium metrics returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if s.metadata.metricsFormat == debeziumMetricsFormatJSON {
+		return GetValueFromResponse(body, s.metadata.valueLocation)
+	}
+
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(strings.NewReader(string(body)))
+	if err != nil {
+		return 0, fmt.Errorf("error parsing the metrics: %s", err)
+	}
+	family, ok := families[s.metadata.metricName]
+	if !ok {
+		return 0, fmt.Errorf("metric %s not found", s.metadata.metricName)
+	}
+
+	lag, found := 0.0, false
+	for _, metric := range family.GetMetric() {
+		matches := s.metadata.serverName == ""
+		for _, label := range metric.GetLabel() {
+			if label.GetName() == "context" && label.GetValue() != "streaming" {
+				matches = false
+				break
+			}
+			if label.GetName() == "name" && label.GetValue() == s.metadata.serverName {
+				matches = true
+			}
+		}
+		if !matches {
+			continue
+		}
+
+		var value float64
+		switch {
+		case metric.GetGauge() != nil:
+			value = metric.GetGauge().GetValue()
+		case metric.GetUntyped() != nil:
+			value = metric.GetUntyped().GetValue()
+		}
+		if !found || value > lag {
+			lag, found = value, true
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("no sample of metric %s found for server %s", s.metadata.metricName, s.metadata.serverName)
+	}
+	// Debezium reports -1 before it streams its first event
+	if lag < 0 {
+		lag = 0
+	}
+	return lag, nil
+}
+
+// getSlotRetainedBytes returns the WAL bytes retained by the replication slot
+func (s *debeziumScaler) getSlotRetainedBytes(ctx context.Context) (float64, error) {
+	var retained float64
+	err := s.connection.QueryRowContext(ctx, debeziumSlotRetainedBytesQuery, s.metadata.slotName).Scan(&retained)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("replication slot %s not found", s.metadata.slotName)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not query postgreSQL: %s", err)
+	}
+	return retained, nil
+}
+
+func (s *debeziumScaler) getLag(ctx context.Context) (float64, error) {
+	if s.metadata.lagSource == debeziumLagSourcePostgreSQL {
+		return s.getSlotRetainedBytes(ctx)
+	}
+	return s.getDebeziumLag(ctx)
+}
+
+// IsActive returns true if the lag is over activationTargetLag
+func (s *debeziumScaler) IsActive(ctx context.Context) (bool, error) {
+	lag, err := s.getLag(ctx)
+	if err != nil {
+		debeziumLog.Error(err, "error getting debezium lag")
+		return false, err
+	}
+
+	return lag > s.metadata.activationTargetLag, nil
+}
+
+// Close closes the connection to PostgreSQL
+func (s *debeziumScaler) Close(context.Context) error {
+	if s.connection != nil {
+		if err := s.connection.Close(); err != nil {
+			debeziumLog.Error(err, "error closing postgreSQL connection")
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *debeziumScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	var metricName string
+	if s.metadata.lagSource == debeziumLagSourcePostgreSQL {
+		metricName = fmt.Sprintf("debezium-slot-%s", s.metadata.slotName)
+	} else {
+		metricName = "debezium-lag"
+		if s.metadata.serverName != "" {
+			metricName = fmt.Sprintf("debezium-%s-lag", s.metadata.serverName)
+		}
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(metricName)),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetLag),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the lag in milliseconds or in bytes of the slot
+func (s *debeziumScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	lag, err := s.getLag(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting debezium lag: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, lag)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -46,12 +46,12 @@ var elasticsearchLog = logf.Log.WithName("elasticsearch_scaler")
 func NewElasticsearchScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseElasticsearchMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing elasticsearch metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing elasticsearch metadata: %s", err))
 	}
 
 	esClient, err := newElasticsearchClient(meta)
@@ -0,0 +1,306 @@
+package scalers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	defaultEnvoyTargetValue = 100
+	defaultEnvoyStatsPath   = "/stats/prometheus"
+
+	envoyMetricRequestRate     = "requestRate"
+	envoyMetricPendingRequests = "pendingRequests"
+	envoyMetricActiveRequests  = "activeRequests"
+
+	// envoyRateSampleInterval is the interval between the two samples of the first rate, without a previous sample
+	envoyRateSampleInterval = time.Second
+	// envoyRateMaxSampleAge is the age after which the previous sample is too old to compute a current rate
+	envoyRateMaxSampleAge = 5 * time.Minute
+)
+
+// envoyClusterLabels are the labels of the upstream cluster of the Envoy stats, envoy_cluster_name with the default
+// stats tags of Envoy and cluster_name with the ones of Istio
+var envoyClusterLabels = []string{"envoy_cluster_name", "cluster_name"}
+
+var envoyStatNames = map[string]string{
+	envoyMetricRequestRate:     "envoy_cluster_upstream_rq_total",
+	envoyMetricPendingRequests: "envoy_cluster_upstream_rq_pending_active",
+	envoyMetricActiveRequests:  "envoy_cluster_upstream_rq_active",
+}
+
+var envoyLog = logf.Log.WithName("envoy_scaler")
+
+type envoyScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *envoyMetadata
+	httpClient *http.Client
+
+	// the previous sample of the request counter, to compute the rate
+	sampleLock     sync.Mutex
+	lastSample     float64
+	lastSampleTime time.Time
+	lastRate       float64
+}
+
+type envoyMetadata struct {
+	statsURL              string
+	clusterName           string
+	metric                string
+	targetValue           float64
+	activationTargetValue float64
+	unsafeSsl             bool
+	scalerIndex           int
+}
+
+// NewEnvoyScaler creates a new envoyScaler, reading the requests to an upstream cluster from the stats of an Envoy
+// proxy, like an Istio sidecar or gateway
+func NewEnvoyScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseEnvoyMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing envoy metadata: %s", err))
+	}
+
+	return &envoyScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, meta.unsafeSsl),
+	}, nil
+}
+
+func parseEnvoyMetadata(config *ScalerConfig) (*envoyMetadata, error) {
+	meta := envoyMetadata{
+		metric:      envoyMetricRequestRate,
+		targetValue: defaultEnvoyTargetValue,
+	}
+
+	if val, ok := config.TriggerMetadata["adminURL"]; ok && val != "" {
+		adminURL, err := url.ParseRequestURI(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid adminURL: %s", err)
+		}
+		// the admin address is given, e.g. http://localhost:15000, or the stats endpoint itself
+		if adminURL.Path == "" || adminURL.Path == "/" {
+			adminURL.Path = defaultEnvoyStatsPath
+		}
+		meta.statsURL = adminURL.String()
+	} else {
+		return nil, errors.New("no adminURL given")
+	}
+
+	if val, ok := config.TriggerMetadata["clusterName"]; ok && val != "" {
+		meta.clusterName = val
+	} else {
+		return nil, errors.New("no clusterName given")
+	}
+
+	if val, ok := config.TriggerMetadata["metric"]; ok && val != "" {
+		meta.metric = strings.TrimSpace(val)
+	}
+	if _, ok := envoyStatNames[meta.metric]; !ok {
+		return nil, fmt.Errorf("err incorrect value for metric is given: %s", meta.metric)
+	}
+
+	if val, ok := config.TriggerMetadata["targetValue"]; ok && val != "" {
+		targetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetValue parsing error %s", err.Error())
+		}
+		meta.targetValue = targetValue
+	}
+
+	if val, ok := config.TriggerMetadata["activationTargetValue"]; ok && val != "" {
+		activationTargetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationTargetValue parsing error %s", err.Error())
+		}
+		meta.activationTargetValue = activationTargetValue
+	}
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok && val != "" {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("unsafeSsl parsing error %s", err.Error())
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// getStat sums the samples of the stat of the metric for the upstream cluster
+func (s *envoyScaler) getStat(ctx context.Context) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.metadata.statsURL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("envoy stats returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(strings.NewReader(string(body)))
+	if err != nil {
+		return 0, fmt.Errorf("error parsing the stats: %s", err)
+	}
+	statName := envoyStatNames[s.metadata.metric]
+	family, ok := families[statName]
+	if !ok {
+		return 0, fmt.Errorf("stat %s not found", statName)
+	}
+
+	value, found := 0.0, false
+	for _, metric := range family.GetMetric() {
+		if !envoyMetricHasCluster(metric.GetLabel(), s.metadata.clusterName) {
+			continue
+		}
+		found = true
+		switch {
+		case metric.GetCounter() != nil:
+			value += metric.GetCounter().GetValue()
+		case metric.GetGauge() != nil:
+			value += metric.GetGauge().GetValue()
+		case metric.GetUntyped() != nil:
+			value += metric.GetUntyped().GetValue()
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("no stat %s found for cluster %s", statName, s.metadata.clusterName)
+	}
+	return value, nil
+}
+
+func envoyMetricHasCluster(labelPairs []*dto.LabelPair, clusterName string) bool {
+	for _, label := range labelPairs {
+		for _, clusterLabel := range envoyClusterLabels {
+			if label.GetName() == clusterLabel && label.GetValue() == clusterName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getRequestRate returns the requests per second since the previous sample. Without a recent previous sample, the
+// rate is computed from two samples taken envoyRateSampleInterval apart, the rate computed less than
+// envoyRateSampleInterval ago is returned as is.
+func (s *envoyScaler) getRequestRate(ctx context.Context) (float64, error) {
+	s.sampleLock.Lock()
+	defer s.sampleLock.Unlock()
+
+	if !s.lastSampleTime.IsZero() && time.Since(s.lastSampleTime) < envoyRateSampleInterval {
+		return s.lastRate, nil
+	}
+	if s.lastSampleTime.IsZero() || time.Since(s.lastSampleTime) > envoyRateMaxSampleAge {
+		sample, err := s.getStat(ctx)
+		if err != nil {
+			return 0, err
+		}
+		s.lastSample, s.lastSampleTime = sample, time.Now()
+
+		select {
+		case <-time.After(envoyRateSampleInterval):
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	sample, err := s.getStat(ctx)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+
+	increase := sample - s.lastSample
+	// the counter was reset by a restart of the proxy
+	if increase < 0 {
+		increase = sample
+	}
+	rate := increase / now.Sub(s.lastSampleTime).Seconds()
+	s.lastSample, s.lastSampleTime, s.lastRate = sample, now, rate
+	return rate, nil
+}
+
+func (s *envoyScaler) getValue(ctx context.Context) (float64, error) {
+	if s.metadata.metric == envoyMetricRequestRate {
+		return s.getRequestRate(ctx)
+	}
+	return s.getStat(ctx)
+}
+
+// IsActive returns true if the value of the metric is over activationTargetValue
+func (s *envoyScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		envoyLog.Error(err, "error getting envoy stats")
+		return false, err
+	}
+
+	return value > s.metadata.activationTargetValue, nil
+}
+
+func (s *envoyScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *envoyScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	// the Istio cluster names are like outbound|8080||reviews.default.svc.cluster.local
+	clusterName := strings.ReplaceAll(s.metadata.clusterName, "|", "-")
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("envoy-%s-%s", clusterName, s.metadata.metric))),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the request rate or the requests in progress of the upstream cluster
+func (s *envoyScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting envoy stats: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, value)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
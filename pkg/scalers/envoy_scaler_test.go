@@ -0,0 +1,170 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testEnvoyStats = `# TYPE envoy_cluster_upstream_rq_total counter
+envoy_cluster_upstream_rq_total{envoy_cluster_name="backend"} %d
+envoy_cluster_upstream_rq_total{envoy_cluster_name="other"} 10
+# TYPE envoy_cluster_upstream_rq_pending_active gauge
+envoy_cluster_upstream_rq_pending_active{envoy_cluster_name="backend"} 4
+# TYPE envoy_cluster_upstream_rq_active gauge
+envoy_cluster_upstream_rq_active{cluster_name="outbound|8080||reviews.default.svc.cluster.local"} 12
+envoy_cluster_upstream_rq_active{envoy_cluster_name="backend"} 7
+`
+
+type parseEnvoyMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+type envoyMetricIdentifier struct {
+	metadataTestData *parseEnvoyMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testEnvoyMetadata = []parseEnvoyMetadataTestData{
+	// only required properties
+	{map[string]string{"adminURL": "http://localhost:15000", "clusterName": "backend"}, false},
+	// all properties
+	{map[string]string{"adminURL": "https://istio-ingressgateway:15090/stats/prometheus", "clusterName": "outbound|8080||reviews.default.svc.cluster.local", "metric": "pendingRequests", "targetValue": "5", "activationTargetValue": "1", "unsafeSsl": "true"}, false},
+	// missing adminURL
+	{map[string]string{"clusterName": "backend"}, true},
+	// invalid adminURL
+	{map[string]string{"adminURL": "localhost", "clusterName": "backend"}, true},
+	// missing clusterName
+	{map[string]string{"adminURL": "http://localhost:15000"}, true},
+	// invalid metric
+	{map[string]string{"adminURL": "http://localhost:15000", "clusterName": "backend", "metric": "latency"}, true},
+	// invalid values
+	{map[string]string{"adminURL": "http://localhost:15000", "clusterName": "backend", "targetValue": "a"}, true},
+	{map[string]string{"adminURL": "http://localhost:15000", "clusterName": "backend", "activationTargetValue": "a"}, true},
+	{map[string]string{"adminURL": "http://localhost:15000", "clusterName": "backend", "unsafeSsl": "a"}, true},
+}
+
+var envoyMetricIdentifiers = []envoyMetricIdentifier{
+	{&testEnvoyMetadata[0], 0, "s0-envoy-backend-requestRate"},
+	{&testEnvoyMetadata[1], 1, "s1-envoy-outbound-8080--reviews-default-svc-cluster-local-pendingRequests"},
+}
+
+func TestEnvoyParseMetadata(t *testing.T) {
+	for _, testData := range testEnvoyMetadata {
+		_, err := parseEnvoyMetadata(&ScalerConfig{TriggerMetadata: testData.metadata})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+
+	meta, err := parseEnvoyMetadata(&ScalerConfig{TriggerMetadata: testEnvoyMetadata[0].metadata})
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:15000/stats/prometheus", meta.statsURL)
+}
+
+func TestEnvoyGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range envoyMetricIdentifiers {
+		meta, err := parseEnvoyMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockEnvoyScaler := envoyScaler{metadata: meta}
+
+		metricSpec := mockEnvoyScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestEnvoyGetValue(t *testing.T) {
+	var requests int64 = 1000
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stats/prometheus" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, testEnvoyStats, atomic.LoadInt64(&requests))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		metadata map[string]string
+		value    float64
+		isActive bool
+		isError  bool
+	}{
+		{map[string]string{"adminURL": server.URL, "clusterName": "backend", "metric": "pendingRequests"}, 4, true, false},
+		{map[string]string{"adminURL": server.URL, "clusterName": "backend", "metric": "activeRequests", "activationTargetValue": "7"}, 7, false, false},
+		{map[string]string{"adminURL": server.URL + "/stats/prometheus", "clusterName": "outbound|8080||reviews.default.svc.cluster.local", "metric": "activeRequests"}, 12, true, false},
+		{map[string]string{"adminURL": server.URL, "clusterName": "missing", "metric": "activeRequests"}, 0, false, true},
+		{map[string]string{"adminURL": server.URL, "clusterName": "other", "metric": "pendingRequests"}, 0, false, true},
+		{map[string]string{"adminURL": server.URL + "/stats", "clusterName": "backend"}, 0, false, true},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parseEnvoyMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata})
+		assert.NoError(t, err)
+		s := envoyScaler{metadata: meta, httpClient: http.DefaultClient}
+
+		value, err := s.getValue(context.Background())
+		if testCase.isError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.value, value, "metadata %v", testCase.metadata)
+
+		isActive, err := s.IsActive(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.isActive, isActive, "metadata %v", testCase.metadata)
+	}
+}
+
+func TestEnvoyGetRequestRate(t *testing.T) {
+	var requests int64 = 1000
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, testEnvoyStats, atomic.LoadInt64(&requests))
+	}))
+	defer server.Close()
+
+	meta, err := parseEnvoyMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"adminURL": server.URL, "clusterName": "backend"}})
+	assert.NoError(t, err)
+	s := envoyScaler{metadata: meta, httpClient: http.DefaultClient}
+
+	// without a previous sample, the rate is computed over envoyRateSampleInterval
+	rate, err := s.getRequestRate(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, rate)
+
+	s.lastSampleTime = time.Now().Add(-10 * time.Second)
+	atomic.StoreInt64(&requests, 1500)
+	rate, err = s.getRequestRate(context.Background())
+	assert.NoError(t, err)
+	assert.InDelta(t, 50, rate, 1)
+
+	// the rate of a recent sample is reused
+	atomic.StoreInt64(&requests, 2000)
+	rate, err = s.getRequestRate(context.Background())
+	assert.NoError(t, err)
+	assert.InDelta(t, 50, rate, 1)
+
+	// the proxy restarted
+	s.lastSampleTime = time.Now().Add(-10 * time.Second)
+	atomic.StoreInt64(&requests, 100)
+	rate, err = s.getRequestRate(context.Background())
+	assert.NoError(t, err)
+	assert.InDelta(t, 10, rate, 1)
+}
@@ -0,0 +1,329 @@
+package scalers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	etcdDialTimeout                        = 5 * time.Second
+	defaultEtcdWatchProgressNotifyInterval = 600
+)
+
+type etcdScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *etcdMetadata
+	client     *clientv3.Client
+}
+
+type etcdMetadata struct {
+	endpoints   []string
+	watchKey    string
+	watchPrefix string
+	targetValue float64
+	metricName  string
+	scalerIndex int
+
+	watchProgressNotifyInterval int
+
+	username string
+	password string
+
+	enableTLS bool
+	cert      string
+	key       string
+	ca        string
+}
+
+var etcdLog = logf.Log.WithName("etcd_scaler")
+
+// NewEtcdScaler creates a new etcd scaler
+func NewEtcdScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseEtcdMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing etcd metadata: %s", err))
+	}
+
+	client, err := getEtcdClient(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return &etcdScaler{
+		metricType: metricType,
+		metadata:   meta,
+		client:     client,
+	}, nil
+}
+
+func parseEtcdMetadata(config *ScalerConfig) (*etcdMetadata, error) {
+	meta := etcdMetadata{}
+
+	endpoints, err := GetFromAuthOrMeta(config, "endpoints")
+	if err != nil {
+		return nil, err
+	}
+	for _, endpoint := range strings.Split(endpoints, ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			meta.endpoints = append(meta.endpoints, endpoint)
+		}
+	}
+	if len(meta.endpoints) == 0 {
+		return nil, errors.New("no endpoints given")
+	}
+
+	meta.watchKey = config.TriggerMetadata["watchKey"]
+	meta.watchPrefix = config.TriggerMetadata["watchPrefix"]
+	switch {
+	case meta.watchKey == "" && meta.watchPrefix == "":
+		return nil, errors.New("no watchKey or watchPrefix given")
+	case meta.watchKey != "" && meta.watchPrefix != "":
+		return nil, errors.New("watchKey and watchPrefix are mutually exclusive")
+	}
+
+	if val, ok := config.TriggerMetadata["value"]; ok {
+		targetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("value parsing error %s", err.Error())
+		}
+		meta.targetValue = targetValue
+	} else {
+		return nil, errors.New("no value given")
+	}
+
+	meta.watchProgressNotifyInterval = defaultEtcdWatchProgressNotifyInterval
+	if val, ok := config.TriggerMetadata["watchProgressNotifyInterval"]; ok {
+		interval, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("watchProgressNotifyInterval parsing error %s", err.Error())
+		}
+		if interval <= 0 {
+			return nil, errors.New("watchProgressNotifyInterval must be greater than 0")
+		}
+		meta.watchProgressNotifyInterval = interval
+	}
+
+	meta.username = config.AuthParams["username"]
+	meta.password = config.AuthParams["password"]
+	if meta.username != "" && meta.password == "" {
+		return nil, errors.New("no password given")
+	}
+
+	if val, ok := config.AuthParams["tls"]; ok {
+		val = strings.TrimSpace(val)
+
+		if val == "enable" {
+			certGiven := config.AuthParams["cert"] != ""
+			keyGiven := config.AuthParams["key"] != ""
+			if certGiven && !keyGiven {
+				return nil, errors.New("key must be provided with cert")
+			}
+			if keyGiven && !certGiven {
+				return nil, errors.New("cert must be provided with key")
+			}
+			meta.ca = config.AuthParams["ca"]
+			meta.cert = config.AuthParams["cert"]
+			meta.key = config.AuthParams["key"]
+			meta.enableTLS = true
+		} else if val != "disable" {
+			return nil, fmt.Errorf("err incorrect value for TLS given: %s", val)
+		}
+	}
+
+	if meta.watchKey != "" {
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("etcd-%s", meta.watchKey))
+	} else {
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("etcd-%s", meta.watchPrefix))
+	}
+	meta.scalerIndex = config.ScalerIndex
+
+	return &meta, nil
+}
+
+func getEtcdClient(meta *etcdMetadata) (*clientv3.Client, error) {
+	config := clientv3.Config{
+		Endpoints:   meta.endpoints,
+		DialTimeout: etcdDialTimeout,
+		Username:    meta.username,
+		Password:    meta.password,
+	}
+
+	if meta.enableTLS {
+		tlsConfig, err := kedautil.NewTLSConfig(meta.cert, meta.key, meta.ca)
+		if err != nil {
+			return nil, err
+		}
+		config.TLS = tlsConfig
+	}
+
+	client, err := clientv3.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to etcd server: %s", err)
+	}
+	return client, nil
+}
+
+// Close closes the etcd client
+func (s *etcdScaler) Close(context.Context) error {
+	if s.client != nil {
+		return s.client.Close()
+	}
+	return nil
+}
+
+// IsActive returns true if the watched value or key count is greater than zero
+func (s *etcdScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		etcdLog.Error(err, "error inspecting etcd")
+		return false, err
+	}
+	return value > 0, nil
+}
+
+// getValue returns the numeric value of watchKey, or the number of keys under watchPrefix
+func (s *etcdScaler) getValue(ctx context.Context) (float64, error) {
+	if s.metadata.watchPrefix != "" {
+		resp, err := s.client.Get(ctx, s.metadata.watchPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+		if err != nil {
+			return 0, err
+		}
+		return float64(resp.Count), nil
+	}
+
+	resp, err := s.client.Get(ctx, s.metadata.watchKey)
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.Kvs) == 0 {
+		return 0, fmt.Errorf("watchKey %s doesn't exist", s.metadata.watchKey)
+	}
+
+	value, err := strconv.ParseFloat(string(resp.Kvs[0].Value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("value of watchKey %s is not numeric: %s", s.metadata.watchKey, err)
+	}
+	return value, nil
+}
+
+// Run watches the key or prefix and reports the activity as soon as it changes,
+// so a scale from zero doesn't have to wait for the next polling interval
+func (s *etcdScaler) Run(ctx context.Context, active chan<- bool) {
+	defer close(active)
+
+	// retry on a broken watch starting by 2 sec backing off * 2 with a max of 1 minute
+	retryDuration := time.Second * 2
+	for {
+		if err := s.watch(ctx, active); err != nil {
+			etcdLog.Error(err, "error watching etcd")
+		}
+
+		backoffTimer := time.NewTimer(retryDuration)
+		select {
+		case <-ctx.Done():
+			backoffTimer.Stop()
+			return
+		case <-backoffTimer.C:
+			backoffTimer.Stop()
+		}
+
+		retryDuration *= 2
+		if retryDuration > time.Minute*1 {
+			retryDuration = time.Minute * 1
+		}
+	}
+}
+
+// watch blocks until the watch channel is closed or ctx is cancelled
+func (s *etcdScaler) watch(ctx context.Context, active chan<- bool) error {
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the progress notify requests keep the watch alive through compactions and
+	// let a silently broken stream be detected
+	key := s.metadata.watchKey
+	opts := []clientv3.OpOption{clientv3.WithProgressNotify()}
+	if s.metadata.watchPrefix != "" {
+		key = s.metadata.watchPrefix
+		opts = append(opts, clientv3.WithPrefix())
+	}
+
+	progressTicker := time.NewTicker(time.Duration(s.metadata.watchProgressNotifyInterval) * time.Second)
+	defer progressTicker.Stop()
+
+	watchChan := s.client.Watch(watchCtx, key, opts...)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-progressTicker.C:
+			if err := s.client.RequestProgress(watchCtx); err != nil {
+				return err
+			}
+		case resp, ok := <-watchChan:
+			if !ok {
+				return errors.New("etcd watch channel closed")
+			}
+			if err := resp.Err(); err != nil {
+				return err
+			}
+			if len(resp.Events) == 0 {
+				continue
+			}
+
+			value, err := s.getValue(ctx)
+			if err != nil {
+				etcdLog.Error(err, "error inspecting etcd")
+				continue
+			}
+			select {
+			case active <- value > 0:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *etcdScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, s.metadata.metricName),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *etcdScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting etcd: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, value)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
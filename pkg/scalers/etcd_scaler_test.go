@@ -0,0 +1,84 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+)
+
+type parseEtcdMetadataTestData struct {
+	metadata    map[string]string
+	authParams  map[string]string
+	raisesError bool
+}
+
+type etcdMetricIdentifier struct {
+	metadataTestData *parseEtcdMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testEtcdMetadata = []parseEtcdMetadataTestData{
+	// nothing passed
+	{map[string]string{}, map[string]string{}, true},
+	// properly formed watchKey
+	{map[string]string{"endpoints": "http://localhost:2379", "watchKey": "length", "value": "10"}, map[string]string{}, false},
+	// properly formed watchPrefix with several endpoints
+	{map[string]string{"endpoints": "http://etcd-0:2379, http://etcd-1:2379", "watchPrefix": "/jobs/", "value": "5"}, map[string]string{}, false},
+	// no endpoints
+	{map[string]string{"watchKey": "length", "value": "10"}, map[string]string{}, true},
+	// empty endpoints
+	{map[string]string{"endpoints": " , ", "watchKey": "length", "value": "10"}, map[string]string{}, true},
+	// no watchKey nor watchPrefix
+	{map[string]string{"endpoints": "http://localhost:2379", "value": "10"}, map[string]string{}, true},
+	// both watchKey and watchPrefix
+	{map[string]string{"endpoints": "http://localhost:2379", "watchKey": "length", "watchPrefix": "/jobs/", "value": "10"}, map[string]string{}, true},
+	// no value
+	{map[string]string{"endpoints": "http://localhost:2379", "watchKey": "length"}, map[string]string{}, true},
+	// invalid value
+	{map[string]string{"endpoints": "http://localhost:2379", "watchKey": "length", "value": "a"}, map[string]string{}, true},
+	// invalid watchProgressNotifyInterval
+	{map[string]string{"endpoints": "http://localhost:2379", "watchKey": "length", "value": "10", "watchProgressNotifyInterval": "0"}, map[string]string{}, true},
+	// username and password
+	{map[string]string{"endpoints": "http://localhost:2379", "watchKey": "length", "value": "10"}, map[string]string{"username": "root", "password": "admin"}, false},
+	// username without password
+	{map[string]string{"endpoints": "http://localhost:2379", "watchKey": "length", "value": "10"}, map[string]string{"username": "root"}, true},
+	// mTLS
+	{map[string]string{"endpoints": "https://localhost:2379", "watchKey": "length", "value": "10"}, map[string]string{"tls": "enable", "ca": "caaa", "cert": "ceert", "key": "keey"}, false},
+	// cert without key
+	{map[string]string{"endpoints": "https://localhost:2379", "watchKey": "length", "value": "10"}, map[string]string{"tls": "enable", "cert": "ceert"}, true},
+	// invalid tls value
+	{map[string]string{"endpoints": "https://localhost:2379", "watchKey": "length", "value": "10"}, map[string]string{"tls": "yes"}, true},
+}
+
+var etcdMetricIdentifiers = []etcdMetricIdentifier{
+	{&testEtcdMetadata[1], 0, "s0-etcd-length"},
+	{&testEtcdMetadata[2], 1, "s1-etcd--jobs-"},
+}
+
+func TestParseEtcdMetadata(t *testing.T) {
+	for idx, testData := range testEtcdMetadata {
+		_, err := parseEtcdMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.raisesError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if err == nil && testData.raisesError {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+	}
+}
+
+func TestEtcdGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range etcdMetricIdentifiers {
+		meta, err := parseEtcdMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockEtcdScaler := etcdScaler{"", meta, nil}
+
+		metricSpec := mockEtcdScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package scalers
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -17,6 +18,8 @@ import (
 	"k8s.io/metrics/pkg/apis/external_metrics"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/kedacore/keda/v2/pkg/scalers/authentication"
 	pb "github.com/kedacore/keda/v2/pkg/scalers/externalscaler"
 )
 
@@ -33,6 +36,8 @@ type externalPushScaler struct {
 type externalScalerMetadata struct {
 	scalerAddress    string
 	tlsCertFile      string
+	enableSpiffe     bool
+	spiffeServerID   string
 	originalMetadata map[string]string
 	scalerIndex      int
 }
@@ -56,7 +61,7 @@ func NewExternalScaler(config *ScalerConfig) (Scaler, error) {
 
 	meta, err := parseExternalScalerMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing external scaler metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing external scaler metadata: %s", err))
 	}
 
 	return &externalScaler{
@@ -79,7 +84,7 @@ func NewExternalPushScaler(config *ScalerConfig) (PushScaler, error) {
 
 	meta, err := parseExternalScalerMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing external scaler metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing external scaler metadata: %s", err))
 	}
 
 	return &externalPushScaler{
@@ -111,6 +116,14 @@ func parseExternalScalerMetadata(config *ScalerConfig) (externalScalerMetadata,
 		meta.tlsCertFile = val
 	}
 
+	if config.PodIdentity.Provider == kedav1alpha1.PodIdentityProviderSpiffe {
+		if meta.tlsCertFile != "" {
+			return meta, fmt.Errorf("tlsCertFile can't be set together with spiffe pod identity")
+		}
+		meta.enableSpiffe = true
+		meta.spiffeServerID = config.TriggerMetadata[authentication.SpiffeServerIDKey]
+	}
+
 	meta.originalMetadata = make(map[string]string)
 
 	// Add elements to metadata
@@ -130,7 +143,7 @@ func parseExternalScalerMetadata(config *ScalerConfig) (externalScalerMetadata,
 
 // IsActive checks if there are any messages in the subscription
 func (s *externalScaler) IsActive(ctx context.Context) (bool, error) {
-	grpcClient, err := getClientForConnectionPool(s.metadata)
+	grpcClient, err := getClientForConnectionPool(ctx, s.metadata)
 	if err != nil {
 		return false, err
 	}
@@ -152,7 +165,7 @@ func (s *externalScaler) Close(context.Context) error {
 func (s *externalScaler) GetMetricSpecForScaling(ctx context.Context) []v2beta2.MetricSpec {
 	var result []v2beta2.MetricSpec
 
-	grpcClient, err := getClientForConnectionPool(s.metadata)
+	grpcClient, err := getClientForConnectionPool(ctx, s.metadata)
 	if err != nil {
 		externalLog.Error(err, "error building grpc connection")
 		return result
@@ -187,7 +200,7 @@ func (s *externalScaler) GetMetricSpecForScaling(ctx context.Context) []v2beta2.
 // GetMetrics connects calls the gRPC interface to get the metrics with a specific name
 func (s *externalScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
 	var metrics []external_metrics.ExternalMetricValue
-	grpcClient, err := getClientForConnectionPool(s.metadata)
+	grpcClient, err := getClientForConnectionPool(ctx, s.metadata)
 	if err != nil {
 		return metrics, err
 	}
@@ -222,7 +235,7 @@ func (s *externalPushScaler) Run(ctx context.Context, active chan<- bool) {
 	defer close(active)
 	// It's possible for the connection to get terminated anytime, we need to run this in a retry loop
 	runWithLog := func() {
-		grpcClient, err := getClientForConnectionPool(s.metadata)
+		grpcClient, err := getClientForConnectionPool(ctx, s.metadata)
 		if err != nil {
 			externalLog.Error(err, "error running internalRun")
 			return
@@ -283,11 +296,19 @@ var connectionPoolMutex sync.Mutex
 
 // getClientForConnectionPool returns a grpcClient and a done() Func. The done() function must be called once the client is no longer
 // in use to clean up the shared grpc.ClientConn
-func getClientForConnectionPool(metadata externalScalerMetadata) (pb.ExternalScalerClient, error) {
+func getClientForConnectionPool(ctx context.Context, metadata externalScalerMetadata) (pb.ExternalScalerClient, error) {
 	connectionPoolMutex.Lock()
 	defer connectionPoolMutex.Unlock()
 
 	buildGRPCConnection := func(metadata externalScalerMetadata) (*grpc.ClientConn, error) {
+		if metadata.enableSpiffe {
+			tlsConfig, err := authentication.NewSpiffeTLSConfig(ctx, metadata.spiffeServerID)
+			if err != nil {
+				return nil, err
+			}
+			return grpc.Dial(metadata.scalerAddress, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+		}
+
 		if metadata.tlsCertFile != "" {
 			creds, err := credentials.NewClientTLSFromFile(metadata.tlsCertFile, "")
 			if err != nil {
@@ -301,7 +322,7 @@ func getClientForConnectionPool(metadata externalScalerMetadata) (pb.ExternalSca
 
 	// create a unique key per-metadata. If scaledObjects share the same connection properties
 	// in the metadata, they will share the same grpc.ClientConn
-	key, err := hashstructure.Hash(metadata.scalerAddress, nil)
+	key, err := hashstructure.Hash([]string{metadata.scalerAddress, metadata.spiffeServerID, strconv.FormatBool(metadata.enableSpiffe)}, nil)
 	if err != nil {
 		return nil, err
 	}
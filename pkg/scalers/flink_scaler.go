@@ -0,0 +1,255 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	flinkDefaultMetricName  = "busyTimeMsPerSecond"
+	flinkAggregationSum     = "sum"
+	flinkAggregationMax     = "max"
+	flinkAggregationAvg     = "avg"
+	flinkAggregationMin     = "min"
+	flinkDefaultAggregation = flinkAggregationSum
+)
+
+type flinkScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *flinkMetadata
+	httpClient *http.Client
+}
+
+type flinkMetadata struct {
+	jobManagerURL string
+	jobID         string
+	vertexID      string
+	// metricName is a subtask metric of the vertex, busyTimeMsPerSecond, backPressuredTimeMsPerSecond
+	// or a source metric like the pendingRecords of a Kafka source operator
+	metricName  string
+	aggregation string
+	targetValue float64
+	username    string
+	password    string
+	unsafeSsl   bool
+	scalerIndex int
+}
+
+// flinkAggregatedMetric is an aggregated subtask metric of the Flink REST API
+type flinkAggregatedMetric struct {
+	ID  string   `json:"id"`
+	Min *float64 `json:"min"`
+	Max *float64 `json:"max"`
+	Avg *float64 `json:"avg"`
+	Sum *float64 `json:"sum"`
+}
+
+var flinkLog = logf.Log.WithName("flink_scaler")
+
+// NewFlinkScaler creates a new flinkScaler
+func NewFlinkScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseFlinkMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing flink metadata: %s", err))
+	}
+
+	return &flinkScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, meta.unsafeSsl),
+	}, nil
+}
+
+func parseFlinkMetadata(config *ScalerConfig) (*flinkMetadata, error) {
+	meta := flinkMetadata{}
+
+	switch {
+	case config.AuthParams["jobManagerURL"] != "":
+		meta.jobManagerURL = config.AuthParams["jobManagerURL"]
+	case config.TriggerMetadata["jobManagerURL"] != "":
+		meta.jobManagerURL = config.TriggerMetadata["jobManagerURL"]
+	case config.TriggerMetadata["jobManagerURLFromEnv"] != "":
+		meta.jobManagerURL = config.ResolvedEnv[config.TriggerMetadata["jobManagerURLFromEnv"]]
+	default:
+		return nil, errors.New("no jobManagerURL given")
+	}
+	meta.jobManagerURL = strings.TrimSuffix(meta.jobManagerURL, "/")
+
+	if val, ok := config.TriggerMetadata["jobID"]; ok && val != "" {
+		meta.jobID = val
+	} else {
+		return nil, errors.New("no jobID given")
+	}
+
+	if val, ok := config.TriggerMetadata["vertexID"]; ok && val != "" {
+		meta.vertexID = val
+	} else {
+		return nil, errors.New("no vertexID given")
+	}
+
+	meta.metricName = flinkDefaultMetricName
+	if val, ok := config.TriggerMetadata["metricName"]; ok && val != "" {
+		meta.metricName = val
+	}
+
+	meta.aggregation = flinkDefaultAggregation
+	if val, ok := config.TriggerMetadata["aggregation"]; ok && val != "" {
+		meta.aggregation = val
+	}
+	switch meta.aggregation {
+	case flinkAggregationSum, flinkAggregationMax, flinkAggregationAvg, flinkAggregationMin:
+	default:
+		return nil, fmt.Errorf("aggregation must be one of %s, %s, %s or %s, got %s",
+			flinkAggregationSum, flinkAggregationMax, flinkAggregationAvg, flinkAggregationMin, meta.aggregation)
+	}
+
+	if val, ok := config.TriggerMetadata["targetValue"]; ok && val != "" {
+		targetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetValue parsing error %s", err.Error())
+		}
+		if targetValue <= 0 {
+			return nil, errors.New("targetValue must be greater than 0")
+		}
+		meta.targetValue = targetValue
+	} else {
+		return nil, errors.New("no targetValue given")
+	}
+
+	meta.username = config.AuthParams["username"]
+	meta.password = config.AuthParams["password"]
+	if meta.username != "" && meta.password == "" {
+		return nil, errors.New("no password given")
+	}
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing unsafeSsl: %s", err)
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// IsActive returns true if the aggregated metric of the vertex is greater than 0
+func (s *flinkScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getMetricValue(ctx)
+	if err != nil {
+		flinkLog.Error(err, "error getting flink vertex metric")
+		return false, err
+	}
+	return value > 0, nil
+}
+
+func (s *flinkScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *flinkScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	metricName := kedautil.NormalizeString(fmt.Sprintf("flink-%s-%s-%s", s.metadata.jobID, s.metadata.vertexID, s.metadata.metricName))
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, metricName),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the aggregated metric of the subtasks of the vertex
+func (s *flinkScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getMetricValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting flink vertex metric: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, value)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+func (s *flinkScaler) getMetricValue(ctx context.Context) (float64, error) {
+	query := url.Values{}
+	query.Set("get", s.metadata.metricName)
+	query.Set("agg", s.metadata.aggregation)
+	endpoint := fmt.Sprintf("%s/jobs/%s/vertices/%s/subtasks/metrics?%s",
+		s.metadata.jobManagerURL, url.PathEscape(s.metadata.jobID), url.PathEscape(s.metadata.vertexID), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return -1, err
+	}
+	if s.metadata.username != "" {
+		req.SetBasicAuth(s.metadata.username, s.metadata.password)
+	}
+
+	r, err := s.httpClient.Do(req)
+	if err != nil {
+		return -1, err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(r.Body)
+		return -1, fmt.Errorf("error requesting flink API status: %s, response: %s", r.Status, body)
+	}
+
+	var metrics []flinkAggregatedMetric
+	if err := json.NewDecoder(r.Body).Decode(&metrics); err != nil {
+		return -1, err
+	}
+	return getFlinkAggregatedValue(metrics, s.metadata)
+}
+
+// getFlinkAggregatedValue picks the aggregation of the metric, the list is empty when no subtask reports the metric,
+// e.g. the job isn't running or the metric name doesn't exist for the vertex
+func getFlinkAggregatedValue(metrics []flinkAggregatedMetric, meta *flinkMetadata) (float64, error) {
+	for _, metric := range metrics {
+		if metric.ID != meta.metricName {
+			continue
+		}
+
+		var value *float64
+		switch meta.aggregation {
+		case flinkAggregationMax:
+			value = metric.Max
+		case flinkAggregationAvg:
+			value = metric.Avg
+		case flinkAggregationMin:
+			value = metric.Min
+		default:
+			value = metric.Sum
+		}
+		if value == nil {
+			return -1, fmt.Errorf("no %s aggregation returned for metric %s", meta.aggregation, meta.metricName)
+		}
+		return *value, nil
+	}
+	return -1, fmt.Errorf("metric %s not reported by the subtasks of vertex %s of job %s", meta.metricName, meta.vertexID, meta.jobID)
+}
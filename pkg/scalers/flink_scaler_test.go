@@ -0,0 +1,141 @@
+package scalers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testFlinkJobID    = "bc764cd8ddf7a0cff126f51c16239658"
+	testFlinkVertexID = "cbc357ccb763df2852fee8c4fc7d55f2"
+)
+
+type parseFlinkMetadataTestData struct {
+	metadata   map[string]string
+	isError    bool
+	authParams map[string]string
+}
+
+type flinkMetricIdentifier struct {
+	metadataTestData *parseFlinkMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testFlinkMetadata = []parseFlinkMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true, map[string]string{}},
+	// properly formed
+	{map[string]string{"jobManagerURL": "http://flink-jobmanager:8081", "jobID": testFlinkJobID, "vertexID": testFlinkVertexID, "targetValue": "800"}, false, map[string]string{}},
+	// kafka source lag with max aggregation and basic auth
+	{map[string]string{"jobManagerURL": "https://flink-jobmanager:8081/", "jobID": testFlinkJobID, "vertexID": testFlinkVertexID, "metricName": "Source__KafkaSource.pendingRecords", "aggregation": "max", "targetValue": "1000", "unsafeSsl": "true"}, false, map[string]string{"username": "user", "password": "pass"}},
+	// jobManagerURL from authParams
+	{map[string]string{"jobID": testFlinkJobID, "vertexID": testFlinkVertexID, "targetValue": "800"}, false, map[string]string{"jobManagerURL": "http://flink-jobmanager:8081"}},
+	// no jobID
+	{map[string]string{"jobManagerURL": "http://flink-jobmanager:8081", "vertexID": testFlinkVertexID, "targetValue": "800"}, true, map[string]string{}},
+	// no vertexID
+	{map[string]string{"jobManagerURL": "http://flink-jobmanager:8081", "jobID": testFlinkJobID, "targetValue": "800"}, true, map[string]string{}},
+	// no targetValue
+	{map[string]string{"jobManagerURL": "http://flink-jobmanager:8081", "jobID": testFlinkJobID, "vertexID": testFlinkVertexID}, true, map[string]string{}},
+	// improperly formed targetValue
+	{map[string]string{"jobManagerURL": "http://flink-jobmanager:8081", "jobID": testFlinkJobID, "vertexID": testFlinkVertexID, "targetValue": "AA"}, true, map[string]string{}},
+	// unknown aggregation
+	{map[string]string{"jobManagerURL": "http://flink-jobmanager:8081", "jobID": testFlinkJobID, "vertexID": testFlinkVertexID, "targetValue": "800", "aggregation": "p99"}, true, map[string]string{}},
+	// username without password
+	{map[string]string{"jobManagerURL": "http://flink-jobmanager:8081", "jobID": testFlinkJobID, "vertexID": testFlinkVertexID, "targetValue": "800"}, true, map[string]string{"username": "user"}},
+	// improperly formed unsafeSsl
+	{map[string]string{"jobManagerURL": "http://flink-jobmanager:8081", "jobID": testFlinkJobID, "vertexID": testFlinkVertexID, "targetValue": "800", "unsafeSsl": "AA"}, true, map[string]string{}},
+}
+
+var flinkMetricIdentifiers = []flinkMetricIdentifier{
+	{&testFlinkMetadata[1], 0, "s0-flink-bc764cd8ddf7a0cff126f51c16239658-cbc357ccb763df2852fee8c4fc7d55f2-busyTimeMsPerSecond"},
+	{&testFlinkMetadata[2], 1, "s1-flink-bc764cd8ddf7a0cff126f51c16239658-cbc357ccb763df2852fee8c4fc7d55f2-Source__KafkaSource-pendingRecords"},
+}
+
+func TestFlinkParseMetadata(t *testing.T) {
+	for idx, testData := range testFlinkMetadata {
+		_, err := parseFlinkMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+	}
+}
+
+func TestFlinkGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range flinkMetricIdentifiers {
+		meta, err := parseFlinkMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockFlinkScaler := flinkScaler{metadata: meta}
+
+		metricSpec := mockFlinkScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestFlinkGetMetricValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/jobs/"+testFlinkJobID+"/vertices/"+testFlinkVertexID+"/subtasks/metrics", r.URL.Path)
+		switch r.URL.Query().Get("get") {
+		case "busyTimeMsPerSecond":
+			assert.Equal(t, "max", r.URL.Query().Get("agg"))
+			_, _ = w.Write([]byte(`[{"id": "busyTimeMsPerSecond", "max": 950.0}]`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		name       string
+		metricName string
+		want       float64
+		wantErr    bool
+	}{
+		{name: "busy time", metricName: "busyTimeMsPerSecond", want: 950},
+		{name: "metric not reported", metricName: "Source__KafkaSource.pendingRecords", wantErr: true},
+	}
+
+	for _, testCase := range cases {
+		c := testCase
+		t.Run(c.name, func(t *testing.T) {
+			meta, err := parseFlinkMetadata(&ScalerConfig{TriggerMetadata: map[string]string{
+				"jobManagerURL": server.URL, "jobID": testFlinkJobID, "vertexID": testFlinkVertexID,
+				"metricName": c.metricName, "aggregation": "max", "targetValue": "800",
+			}})
+			assert.NoError(t, err)
+			s := flinkScaler{metadata: meta, httpClient: server.Client()}
+
+			value, err := s.getMetricValue(context.Background())
+			if c.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.want, value)
+		})
+	}
+}
+
+func TestGetFlinkAggregatedValueMissingAggregation(t *testing.T) {
+	sum := 10.0
+	metrics := []flinkAggregatedMetric{{ID: "busyTimeMsPerSecond", Sum: &sum}}
+
+	value, err := getFlinkAggregatedValue(metrics, &flinkMetadata{metricName: "busyTimeMsPerSecond", aggregation: flinkAggregationSum})
+	assert.NoError(t, err)
+	assert.Equal(t, sum, value)
+
+	_, err = getFlinkAggregatedValue(metrics, &flinkMetadata{metricName: "busyTimeMsPerSecond", aggregation: flinkAggregationMax})
+	assert.Error(t, err)
+}
@@ -2,6 +2,9 @@ package scalers
 
 import (
 	"fmt"
+	"strings"
+
+	"google.golang.org/api/option"
 
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 )
@@ -40,3 +43,41 @@ func getGcpAuthorization(config *ScalerConfig, resolvedEnv map[string]string) (*
 	}
 	return &meta, nil
 }
+
+// gcpClientMetadata overrides where the calls of the GCP clients are sent and which project is billed for them
+type gcpClientMetadata struct {
+	// apiEndpoint replaces the global endpoint of the API, e.g. a regional, restricted (VPC-SC)
+	// or Private Service Connect endpoint, as host:port
+	apiEndpoint string
+	// quotaProjectID is the project used for the quota and billing of the calls instead of the resource project
+	quotaProjectID string
+}
+
+func getGcpClientMetadata(config *ScalerConfig) (*gcpClientMetadata, error) {
+	meta := gcpClientMetadata{}
+
+	if val, ok := config.TriggerMetadata["apiEndpoint"]; ok && val != "" {
+		if strings.Contains(val, "://") {
+			return nil, fmt.Errorf("apiEndpoint must be a host:port without scheme, got %s", val)
+		}
+		meta.apiEndpoint = val
+	}
+	meta.quotaProjectID = config.TriggerMetadata["quotaProjectId"]
+
+	return &meta, nil
+}
+
+// clientOptions returns the options of the GCP clients for the overrides
+func (m *gcpClientMetadata) clientOptions() []option.ClientOption {
+	var opts []option.ClientOption
+	if m == nil {
+		return opts
+	}
+	if m.apiEndpoint != "" {
+		opts = append(opts, option.WithEndpoint(m.apiEndpoint))
+	}
+	if m.quotaProjectID != "" {
+		opts = append(opts, option.WithQuotaProject(m.quotaProjectID))
+	}
+	return opts
+}
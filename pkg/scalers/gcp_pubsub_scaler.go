@@ -41,6 +41,7 @@ type pubsubMetadata struct {
 
 	subscriptionName string
 	gcpAuthorization *gcpAuthorizationMetadata
+	gcpClient        *gcpClientMetadata
 	scalerIndex      int
 }
 
@@ -50,12 +51,12 @@ var gcpPubSubLog = logf.Log.WithName("gcp_pub_sub_scaler")
 func NewPubSubScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parsePubSubMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing PubSub metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing PubSub metadata: %s", err))
 	}
 
 	return &pubsubScaler{
@@ -120,6 +121,12 @@ func parsePubSubMetadata(config *ScalerConfig) (*pubsubMetadata, error) {
 		return nil, err
 	}
 	meta.gcpAuthorization = auth
+
+	meta.gcpClient, err = getGcpClientMetadata(config)
+	if err != nil {
+		return nil, err
+	}
+
 	meta.scalerIndex = config.ScalerIndex
 	return &meta, nil
 }
@@ -205,9 +212,9 @@ func (s *pubsubScaler) setStackdriverClient(ctx context.Context) error {
 	var client *StackDriverClient
 	var err error
 	if s.metadata.gcpAuthorization.podIdentityProviderEnabled {
-		client, err = NewStackDriverClientPodIdentity(ctx)
+		client, err = NewStackDriverClientPodIdentity(ctx, s.metadata.gcpClient.clientOptions()...)
 	} else {
-		client, err = NewStackDriverClient(ctx, s.metadata.gcpAuthorization.GoogleApplicationCredentials)
+		client, err = NewStackDriverClient(ctx, s.metadata.gcpAuthorization.GoogleApplicationCredentials, s.metadata.gcpClient.clientOptions()...)
 	}
 
 	if err != nil {
@@ -31,6 +31,7 @@ type stackdriverMetadata struct {
 	metricName  string
 
 	gcpAuthorization *gcpAuthorizationMetadata
+	gcpClient        *gcpClientMetadata
 	aggregation      *monitoringpb.Aggregation
 }
 
@@ -40,15 +41,15 @@ var gcpStackdriverLog = logf.Log.WithName("gcp_stackdriver_scaler")
 func NewStackdriverScaler(ctx context.Context, config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseStackdriverMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing Stackdriver metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing Stackdriver metadata: %s", err))
 	}
 
-	client, err := initializeStackdriverClient(ctx, meta.gcpAuthorization)
+	client, err := initializeStackdriverClient(ctx, meta.gcpAuthorization, meta.gcpClient)
 	if err != nil {
 		gcpStackdriverLog.Error(err, "Failed to create stack driver client")
 		return nil, err
@@ -104,6 +105,11 @@ func parseStackdriverMetadata(config *ScalerConfig) (*stackdriverMetadata, error
 	}
 	meta.gcpAuthorization = auth
 
+	meta.gcpClient, err = getGcpClientMetadata(config)
+	if err != nil {
+		return nil, err
+	}
+
 	meta.aggregation, err = parseAggregation(config)
 	if err != nil {
 		return nil, err
@@ -134,13 +140,13 @@ func parseAggregation(config *ScalerConfig) (*monitoringpb.Aggregation, error) {
 	return nil, nil
 }
 
-func initializeStackdriverClient(ctx context.Context, gcpAuthorization *gcpAuthorizationMetadata) (*StackDriverClient, error) {
+func initializeStackdriverClient(ctx context.Context, gcpAuthorization *gcpAuthorizationMetadata, gcpClient *gcpClientMetadata) (*StackDriverClient, error) {
 	var client *StackDriverClient
 	var err error
 	if gcpAuthorization.podIdentityProviderEnabled {
-		client, err = NewStackDriverClientPodIdentity(ctx)
+		client, err = NewStackDriverClientPodIdentity(ctx, gcpClient.clientOptions()...)
 	} else {
-		client, err = NewStackDriverClient(ctx, gcpAuthorization.GoogleApplicationCredentials)
+		client, err = NewStackDriverClient(ctx, gcpAuthorization.GoogleApplicationCredentials, gcpClient.clientOptions()...)
 	}
 
 	if err != nil {
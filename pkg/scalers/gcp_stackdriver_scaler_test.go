@@ -49,6 +49,10 @@ var testStackdriverMetadata = []parseStackdriverMetadataTestData{
 	{nil, map[string]string{"projectId": "myProject", "filter": sdFilter, "credentialsFromEnv": "SAMPLE_CREDS", "alignmentPeriodSeconds": "30"}, true},
 	// With bad alignment period
 	{nil, map[string]string{"projectId": "myProject", "filter": sdFilter, "credentialsFromEnv": "SAMPLE_CREDS", "alignmentPeriodSeconds": "a"}, true},
+	// With regional endpoint and quota project
+	{nil, map[string]string{"projectId": "myProject", "filter": sdFilter, "credentialsFromEnv": "SAMPLE_CREDS", "apiEndpoint": "monitoring.restricted.googleapis.com:443", "quotaProjectId": "billingProject"}, false},
+	// With endpoint as URL
+	{nil, map[string]string{"projectId": "myProject", "filter": sdFilter, "credentialsFromEnv": "SAMPLE_CREDS", "apiEndpoint": "https://monitoring.restricted.googleapis.com"}, true},
 }
 
 var gcpStackdriverMetricIdentifiers = []gcpStackdriverMetricIdentifier{
@@ -83,3 +87,21 @@ func TestGcpStackdriverGetMetricSpecForScaling(t *testing.T) {
 		}
 	}
 }
+
+func TestGcpClientOptions(t *testing.T) {
+	meta, err := parseStackdriverMetadata(&ScalerConfig{TriggerMetadata: testStackdriverMetadata[len(testStackdriverMetadata)-2].metadata, ResolvedEnv: testStackdriverResolvedEnv})
+	if err != nil {
+		t.Fatal("Could not parse metadata:", err)
+	}
+	if meta.gcpClient.apiEndpoint != "monitoring.restricted.googleapis.com:443" || meta.gcpClient.quotaProjectID != "billingProject" {
+		t.Error("Wrong client overrides:", meta.gcpClient)
+	}
+	if opts := meta.gcpClient.clientOptions(); len(opts) != 2 {
+		t.Error("Expected 2 client options but got", len(opts))
+	}
+
+	var noOverrides *gcpClientMetadata
+	if opts := noOverrides.clientOptions(); len(opts) != 0 {
+		t.Error("Expected no client option but got", len(opts))
+	}
+}
@@ -45,12 +45,12 @@ var gcsLog = logf.Log.WithName("gcp_storage_scaler")
 func NewGcsScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseGcsMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing GCP storage metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing GCP storage metadata: %s", err))
 	}
 
 	ctx := context.Background()
@@ -0,0 +1,201 @@
+package scalers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	gearmanQueueLengthDefault = 5
+	gearmanDefaultTimeout     = 5 * time.Second
+)
+
+type gearmanScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *gearmanMetadata
+	timeout    time.Duration
+}
+
+type gearmanMetadata struct {
+	address        string
+	function       string
+	queueLength    float64
+	includeRunning bool
+	metricName     string
+	scalerIndex    int
+}
+
+var gearmanLog = logf.Log.WithName("gearman_scaler")
+
+// NewGearmanScaler creates a new gearman scaler
+func NewGearmanScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseGearmanMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing gearman metadata: %s", err))
+	}
+
+	timeout := config.GlobalHTTPTimeout
+	if timeout <= 0 {
+		timeout = gearmanDefaultTimeout
+	}
+
+	return &gearmanScaler{
+		metricType: metricType,
+		metadata:   meta,
+		timeout:    timeout,
+	}, nil
+}
+
+func parseGearmanMetadata(config *ScalerConfig) (*gearmanMetadata, error) {
+	meta := gearmanMetadata{}
+
+	address, err := GetFromAuthOrMeta(config, "address")
+	if err != nil {
+		return nil, err
+	}
+	meta.address = address
+
+	if val, ok := config.TriggerMetadata["function"]; ok && val != "" {
+		meta.function = val
+	} else {
+		return nil, errors.New("no function given")
+	}
+
+	meta.queueLength = gearmanQueueLengthDefault
+	if val, ok := config.TriggerMetadata["queueLength"]; ok {
+		queueLength, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("queueLength parsing error %s", err.Error())
+		}
+		if queueLength <= 0 {
+			return nil, errors.New("queueLength must be greater than 0")
+		}
+		meta.queueLength = queueLength
+	}
+
+	if val, ok := config.TriggerMetadata["includeRunning"]; ok {
+		includeRunning, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("includeRunning parsing error %s", err.Error())
+		}
+		meta.includeRunning = includeRunning
+	}
+
+	meta.metricName = kedautil.NormalizeString(fmt.Sprintf("gearman-%s", meta.function))
+	meta.scalerIndex = config.ScalerIndex
+
+	return &meta, nil
+}
+
+// Close does nothing in case of gearmanScaler, a connection is opened for each read
+func (s *gearmanScaler) Close(context.Context) error {
+	return nil
+}
+
+// IsActive returns true if there are jobs queued for the function
+func (s *gearmanScaler) IsActive(ctx context.Context) (bool, error) {
+	jobs, err := s.getQueueLength(ctx)
+	if err != nil {
+		gearmanLog.Error(err, "error inspecting gearman")
+		return false, err
+	}
+	return jobs > 0, nil
+}
+
+// getQueueLength sends the admin status command and returns the number of jobs waiting for a worker,
+// the running jobs are added if includeRunning is set
+func (s *gearmanScaler) getQueueLength(ctx context.Context) (float64, error) {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.metadata.address)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		return 0, err
+	}
+
+	if _, err := fmt.Fprint(conn, "status\n"); err != nil {
+		return 0, err
+	}
+
+	// one "FUNCTION\tTOTAL\tRUNNING\tAVAILABLE_WORKERS" line per function, terminated by a "." line
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "." {
+			// the function isn't registered, no job has been submitted for it and no worker can handle it
+			return 0, nil
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			return 0, fmt.Errorf("unexpected gearman status line: %s", line)
+		}
+		if fields[0] != s.metadata.function {
+			continue
+		}
+
+		total, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing total jobs of %s: %s", s.metadata.function, err)
+		}
+		if s.metadata.includeRunning {
+			return total, nil
+		}
+		running, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing running jobs of %s: %s", s.metadata.function, err)
+		}
+		return total - running, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("error reading gearman status: %s", err)
+	}
+	return 0, errors.New("gearman status ended unexpectedly")
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *gearmanScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, s.metadata.metricName),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.queueLength),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *gearmanScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	jobs, err := s.getQueueLength(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting gearman: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, jobs)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
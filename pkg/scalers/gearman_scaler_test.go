@@ -0,0 +1,126 @@
+package scalers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+type parseGearmanMetadataTestData struct {
+	metadata    map[string]string
+	authParams  map[string]string
+	raisesError bool
+}
+
+type gearmanMetricIdentifier struct {
+	metadataTestData *parseGearmanMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testGearmanMetadata = []parseGearmanMetadataTestData{
+	// nothing passed
+	{map[string]string{}, map[string]string{}, true},
+	// properly formed
+	{map[string]string{"address": "gearmand:4730", "function": "resize_image", "queueLength": "10"}, map[string]string{}, false},
+	// includeRunning and default queueLength
+	{map[string]string{"address": "gearmand:4730", "function": "send.email", "includeRunning": "true"}, map[string]string{}, false},
+	// address from authParams
+	{map[string]string{"function": "resize_image"}, map[string]string{"address": "gearmand:4730"}, false},
+	// no address
+	{map[string]string{"function": "resize_image"}, map[string]string{}, true},
+	// no function
+	{map[string]string{"address": "gearmand:4730"}, map[string]string{}, true},
+	// invalid queueLength
+	{map[string]string{"address": "gearmand:4730", "function": "resize_image", "queueLength": "a"}, map[string]string{}, true},
+	// zero queueLength
+	{map[string]string{"address": "gearmand:4730", "function": "resize_image", "queueLength": "0"}, map[string]string{}, true},
+	// invalid includeRunning
+	{map[string]string{"address": "gearmand:4730", "function": "resize_image", "includeRunning": "maybe"}, map[string]string{}, true},
+}
+
+var gearmanMetricIdentifiers = []gearmanMetricIdentifier{
+	{&testGearmanMetadata[1], 0, "s0-gearman-resize_image"},
+	{&testGearmanMetadata[2], 1, "s1-gearman-send-email"},
+}
+
+func TestParseGearmanMetadata(t *testing.T) {
+	for idx, testData := range testGearmanMetadata {
+		_, err := parseGearmanMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.raisesError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if err == nil && testData.raisesError {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+	}
+}
+
+func TestGearmanGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range gearmanMetricIdentifiers {
+		meta, err := parseGearmanMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockGearmanScaler := gearmanScaler{"", meta, time.Second}
+
+		metricSpec := mockGearmanScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestGearmanGetQueueLength(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				if _, err := bufio.NewReader(conn).ReadString('\n'); err != nil {
+					return
+				}
+				fmt.Fprint(conn, "send_email\t3\t1\t2\nresize_image\t12\t4\t4\n.\n")
+			}(conn)
+		}
+	}()
+
+	tests := []struct {
+		metadata map[string]string
+		expected float64
+	}{
+		{map[string]string{"address": listener.Addr().String(), "function": "resize_image"}, 8},
+		{map[string]string{"address": listener.Addr().String(), "function": "resize_image", "includeRunning": "true"}, 12},
+		{map[string]string{"address": listener.Addr().String(), "function": "send_email"}, 2},
+		{map[string]string{"address": listener.Addr().String(), "function": "unknown"}, 0},
+	}
+
+	for idx, test := range tests {
+		meta, err := parseGearmanMetadata(&ScalerConfig{TriggerMetadata: test.metadata})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		scaler := gearmanScaler{metadata: meta, timeout: time.Second}
+
+		jobs, err := scaler.getQueueLength(context.Background())
+		if err != nil {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if jobs != test.expected {
+			t.Errorf("Expected %f jobs but got %f for test %d", test.expected, jobs, idx)
+		}
+	}
+}
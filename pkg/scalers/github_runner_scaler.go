@@ -0,0 +1,472 @@
+package scalers
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	githubRunnerDefaultAPIURL      = "https://api.github.com"
+	githubRunnerScopeOrg           = "org"
+	githubRunnerScopeRepo          = "repo"
+	githubRunnerDefaultTargetJobs  = 1
+	githubRunnerPageSize           = 100
+	githubRunnerDefaultRateBackoff = time.Minute
+	// the installation tokens are valid 1 hour, they are renewed a bit before
+	githubRunnerTokenRenewalMargin = 5 * time.Minute
+)
+
+type githubRunnerScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *githubRunnerMetadata
+	httpClient *http.Client
+
+	lock sync.Mutex
+	// installation token of the GitHub App
+	appToken          string
+	appTokenExpiresAt time.Time
+	// no API call is made until rateLimitedUntil, the last count is returned instead
+	rateLimitedUntil time.Time
+	lastQueuedJobs   int64
+}
+
+type githubRunnerMetadata struct {
+	apiURL      string
+	owner       string
+	runnerScope string
+	repos       []string
+	labels      []string
+	runnerGroup string
+	targetJobs  float64
+
+	// auth, a personal access token or a GitHub App
+	personalAccessToken string
+	applicationID       string
+	installationID      string
+	appKey              *rsa.PrivateKey
+
+	scalerIndex int
+}
+
+type githubWorkflowRuns struct {
+	TotalCount   int                 `json:"total_count"`
+	WorkflowRuns []githubWorkflowRun `json:"workflow_runs"`
+}
+
+type githubWorkflowRun struct {
+	ID int64 `json:"id"`
+}
+
+type githubWorkflowJobs struct {
+	TotalCount int                 `json:"total_count"`
+	Jobs       []githubWorkflowJob `json:"jobs"`
+}
+
+type githubWorkflowJob struct {
+	Status          string   `json:"status"`
+	Labels          []string `json:"labels"`
+	RunnerGroupName string   `json:"runner_group_name"`
+}
+
+type githubRepository struct {
+	Name     string `json:"name"`
+	Archived bool   `json:"archived"`
+}
+
+type githubInstallationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// githubRateLimitError is returned when the API rejects a request because of a rate limit
+type githubRateLimitError struct {
+	resetAt time.Time
+}
+
+func (e *githubRateLimitError) Error() string {
+	return fmt.Sprintf("github API rate limit exceeded until %s", e.resetAt.Format(time.RFC3339))
+}
+
+var githubRunnerLog = logf.Log.WithName("github_runner_scaler")
+
+// NewGitHubRunnerScaler creates a new githubRunnerScaler
+func NewGitHubRunnerScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseGitHubRunnerMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing github runner metadata: %s", err))
+	}
+
+	return &githubRunnerScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, false),
+	}, nil
+}
+
+func parseGitHubRunnerMetadata(config *ScalerConfig) (*githubRunnerMetadata, error) {
+	meta := githubRunnerMetadata{}
+
+	meta.apiURL = githubRunnerDefaultAPIURL
+	if val, ok := config.TriggerMetadata["githubAPIURL"]; ok && val != "" {
+		meta.apiURL = strings.TrimSuffix(val, "/")
+	}
+
+	switch {
+	case config.TriggerMetadata["owner"] != "":
+		meta.owner = config.TriggerMetadata["owner"]
+	case config.TriggerMetadata["ownerFromEnv"] != "":
+		meta.owner = config.ResolvedEnv[config.TriggerMetadata["ownerFromEnv"]]
+	default:
+		return nil, errors.New("no owner given")
+	}
+
+	meta.runnerScope = githubRunnerScopeOrg
+	if val, ok := config.TriggerMetadata["runnerScope"]; ok && val != "" {
+		meta.runnerScope = strings.ToLower(val)
+	}
+	if meta.runnerScope != githubRunnerScopeOrg && meta.runnerScope != githubRunnerScopeRepo {
+		return nil, fmt.Errorf("runnerScope must be either %s or %s, got %s", githubRunnerScopeOrg, githubRunnerScopeRepo, meta.runnerScope)
+	}
+
+	if val, ok := config.TriggerMetadata["repos"]; ok && val != "" {
+		meta.repos = splitAndTrim(val)
+	}
+	if meta.runnerScope == githubRunnerScopeRepo && len(meta.repos) != 1 {
+		return nil, fmt.Errorf("exactly one repository must be given in repos for the %s scope", githubRunnerScopeRepo)
+	}
+
+	if val, ok := config.TriggerMetadata["labels"]; ok && val != "" {
+		meta.labels = splitAndTrim(val)
+	}
+	meta.runnerGroup = config.TriggerMetadata["runnerGroup"]
+	if meta.runnerGroup != "" && meta.runnerScope != githubRunnerScopeOrg {
+		return nil, fmt.Errorf("runnerGroup is only supported for the %s scope", githubRunnerScopeOrg)
+	}
+
+	meta.targetJobs = githubRunnerDefaultTargetJobs
+	if val, ok := config.TriggerMetadata["targetWorkflowQueueLength"]; ok {
+		targetJobs, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetWorkflowQueueLength parsing error %s", err.Error())
+		}
+		if targetJobs <= 0 {
+			return nil, errors.New("targetWorkflowQueueLength must be greater than 0")
+		}
+		meta.targetJobs = targetJobs
+	}
+
+	meta.personalAccessToken = config.AuthParams["personalAccessToken"]
+	meta.applicationID = config.AuthParams["applicationID"]
+	meta.installationID = config.AuthParams["installationID"]
+	appKey := config.AuthParams["appKey"]
+	switch {
+	case meta.personalAccessToken != "":
+	case meta.applicationID != "" || meta.installationID != "" || appKey != "":
+		if meta.applicationID == "" || meta.installationID == "" || appKey == "" {
+			return nil, errors.New("applicationID, installationID and appKey must be given together for GitHub App authentication")
+		}
+		key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(appKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid appKey: %s", err)
+		}
+		meta.appKey = key
+	default:
+		return nil, errors.New("no personalAccessToken or GitHub App (applicationID, installationID and appKey) given")
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// IsActive returns true if there are queued jobs for the runners
+func (s *githubRunnerScaler) IsActive(ctx context.Context) (bool, error) {
+	jobs, err := s.getQueuedJobCount(ctx)
+	if err != nil {
+		githubRunnerLog.Error(err, "error getting github queued jobs")
+		return false, err
+	}
+	return jobs > 0, nil
+}
+
+func (s *githubRunnerScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *githubRunnerScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	metricName := fmt.Sprintf("github-runner-%s", s.metadata.owner)
+	if s.metadata.runnerScope == githubRunnerScopeRepo {
+		metricName = fmt.Sprintf("%s-%s", metricName, s.metadata.repos[0])
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(metricName)),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetJobs),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of queued jobs for the runners
+func (s *githubRunnerScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	jobs, err := s.getQueuedJobCount(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting github queued jobs: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(jobs))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// getQueuedJobCount counts the queued jobs of the queued and in progress workflow runs of the repositories.
+// While the API is rate limited the last count is returned, so the runners aren't scaled in on a rate limit.
+func (s *githubRunnerScaler) getQueuedJobCount(ctx context.Context) (int64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if time.Now().Before(s.rateLimitedUntil) {
+		githubRunnerLog.V(1).Info("github API rate limited, returning the last queued job count", "until", s.rateLimitedUntil)
+		return s.lastQueuedJobs, nil
+	}
+
+	count, err := s.countQueuedJobs(ctx)
+	var rateLimitErr *githubRateLimitError
+	if errors.As(err, &rateLimitErr) {
+		s.rateLimitedUntil = rateLimitErr.resetAt
+		githubRunnerLog.Info("github API rate limited, backing off", "until", s.rateLimitedUntil)
+		return s.lastQueuedJobs, nil
+	}
+	if err != nil {
+		return -1, err
+	}
+	s.lastQueuedJobs = count
+	return count, nil
+}
+
+func (s *githubRunnerScaler) countQueuedJobs(ctx context.Context) (int64, error) {
+	repos := s.metadata.repos
+	if len(repos) == 0 {
+		var err error
+		repos, err = s.getOrgRepos(ctx)
+		if err != nil {
+			return -1, err
+		}
+	}
+
+	var count int64
+	for _, repo := range repos {
+		for _, status := range []string{"queued", "in_progress"} {
+			runs, err := s.getWorkflowRuns(ctx, repo, status)
+			if err != nil {
+				return -1, err
+			}
+			for _, run := range runs {
+				jobs, err := s.getWorkflowJobs(ctx, repo, run.ID)
+				if err != nil {
+					return -1, err
+				}
+				count += countGitHubQueuedJobs(jobs, s.metadata)
+			}
+		}
+	}
+	return count, nil
+}
+
+func (s *githubRunnerScaler) getOrgRepos(ctx context.Context) ([]string, error) {
+	var repos []string
+	for page := 1; ; page++ {
+		var pageRepos []githubRepository
+		path := fmt.Sprintf("/orgs/%s/repos?per_page=%d&page=%d", url.PathEscape(s.metadata.owner), githubRunnerPageSize, page)
+		if err := s.getJSON(ctx, path, &pageRepos); err != nil {
+			return nil, err
+		}
+		for _, repo := range pageRepos {
+			if !repo.Archived {
+				repos = append(repos, repo.Name)
+			}
+		}
+		if len(pageRepos) < githubRunnerPageSize {
+			return repos, nil
+		}
+	}
+}
+
+func (s *githubRunnerScaler) getWorkflowRuns(ctx context.Context, repo, status string) ([]githubWorkflowRun, error) {
+	var runs []githubWorkflowRun
+	for page := 1; ; page++ {
+		var pageRuns githubWorkflowRuns
+		path := fmt.Sprintf("/repos/%s/%s/actions/runs?status=%s&per_page=%d&page=%d", url.PathEscape(s.metadata.owner), url.PathEscape(repo), status, githubRunnerPageSize, page)
+		if err := s.getJSON(ctx, path, &pageRuns); err != nil {
+			return nil, err
+		}
+		runs = append(runs, pageRuns.WorkflowRuns...)
+		if len(pageRuns.WorkflowRuns) < githubRunnerPageSize || len(runs) >= pageRuns.TotalCount {
+			return runs, nil
+		}
+	}
+}
+
+func (s *githubRunnerScaler) getWorkflowJobs(ctx context.Context, repo string, runID int64) ([]githubWorkflowJob, error) {
+	var jobs []githubWorkflowJob
+	for page := 1; ; page++ {
+		var pageJobs githubWorkflowJobs
+		path := fmt.Sprintf("/repos/%s/%s/actions/runs/%d/jobs?filter=latest&per_page=%d&page=%d", url.PathEscape(s.metadata.owner), url.PathEscape(repo), runID, githubRunnerPageSize, page)
+		if err := s.getJSON(ctx, path, &pageJobs); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, pageJobs.Jobs...)
+		if len(pageJobs.Jobs) < githubRunnerPageSize || len(jobs) >= pageJobs.TotalCount {
+			return jobs, nil
+		}
+	}
+}
+
+// countGitHubQueuedJobs counts the queued jobs the runners can pick: all the labels of the job are runner labels
+// and, when a runner group is set, the job isn't assigned to another group
+func countGitHubQueuedJobs(jobs []githubWorkflowJob, meta *githubRunnerMetadata) int64 {
+	runnerLabels := map[string]bool{}
+	for _, label := range meta.labels {
+		runnerLabels[strings.ToLower(label)] = true
+	}
+
+	var count int64
+	for _, job := range jobs {
+		if job.Status != "queued" {
+			continue
+		}
+		if meta.runnerGroup != "" && job.RunnerGroupName != "" && !strings.EqualFold(job.RunnerGroupName, meta.runnerGroup) {
+			continue
+		}
+		matches := true
+		for _, label := range job.Labels {
+			if len(runnerLabels) > 0 && !runnerLabels[strings.ToLower(label)] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *githubRunnerScaler) getJSON(ctx context.Context, path string, v interface{}) error {
+	token, err := s.getToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", s.metadata.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	r, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	if err := checkGitHubResponse(r, time.Now()); err != nil {
+		return err
+	}
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// checkGitHubResponse returns a githubRateLimitError when the primary or a secondary rate limit is exceeded
+func checkGitHubResponse(r *http.Response, now time.Time) error {
+	if r.StatusCode == http.StatusOK || r.StatusCode == http.StatusCreated {
+		return nil
+	}
+
+	if r.StatusCode == http.StatusForbidden || r.StatusCode == http.StatusTooManyRequests {
+		if retryAfter, err := strconv.Atoi(r.Header.Get("Retry-After")); err == nil {
+			return &githubRateLimitError{resetAt: now.Add(time.Duration(retryAfter) * time.Second)}
+		}
+		if r.Header.Get("X-RateLimit-Remaining") == "0" {
+			resetAt := now.Add(githubRunnerDefaultRateBackoff)
+			if reset, err := strconv.ParseInt(r.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+				resetAt = time.Unix(reset, 0)
+			}
+			return &githubRateLimitError{resetAt: resetAt}
+		}
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	return fmt.Errorf("error requesting github API status: %s, response: %s", r.Status, body)
+}
+
+// getToken returns the personal access token or an installation token of the GitHub App, renewed before it expires
+func (s *githubRunnerScaler) getToken(ctx context.Context) (string, error) {
+	if s.metadata.personalAccessToken != "" {
+		return s.metadata.personalAccessToken, nil
+	}
+	if s.appToken != "" && time.Now().Add(githubRunnerTokenRenewalMargin).Before(s.appTokenExpiresAt) {
+		return s.appToken, nil
+	}
+
+	now := time.Now()
+	appJWT, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		// backdated to allow for clock drift
+		IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(9 * time.Minute)),
+		Issuer:    s.metadata.applicationID,
+	}).SignedString(s.metadata.appKey)
+	if err != nil {
+		return "", fmt.Errorf("error signing github app JWT: %s", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/app/installations/%s/access_tokens", s.metadata.apiURL, url.PathEscape(s.metadata.installationID)), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+appJWT)
+
+	r, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer r.Body.Close()
+
+	if err := checkGitHubResponse(r, now); err != nil {
+		return "", err
+	}
+	var token githubInstallationToken
+	if err := json.NewDecoder(r.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	s.appToken = token.Token
+	s.appTokenExpiresAt = token.ExpiresAt
+	return s.appToken, nil
+}
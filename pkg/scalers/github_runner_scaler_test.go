@@ -0,0 +1,193 @@
+package scalers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseGitHubRunnerMetadataTestData struct {
+	metadata   map[string]string
+	isError    bool
+	authParams map[string]string
+}
+
+type githubRunnerMetricIdentifier struct {
+	metadataTestData *parseGitHubRunnerMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testGitHubRunnerAppKey = func() string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}()
+
+var testGitHubRunnerPAT = map[string]string{"personalAccessToken": "pat"}
+
+var testGitHubRunnerMetadata = []parseGitHubRunnerMetadataTestData{
+	// org scope with personal access token
+	{map[string]string{"owner": "kedacore"}, false, testGitHubRunnerPAT},
+	// repo scope with all properties
+	{map[string]string{"githubAPIURL": "https://github.example.com/api/v3/", "owner": "kedacore", "runnerScope": "repo", "repos": "keda", "labels": "self-hosted, linux", "targetWorkflowQueueLength": "2"}, false, testGitHubRunnerPAT},
+	// org scope with repos and runner group
+	{map[string]string{"owner": "kedacore", "repos": "keda,http-add-on", "runnerGroup": "build"}, false, testGitHubRunnerPAT},
+	// GitHub App
+	{map[string]string{"owner": "kedacore"}, false, map[string]string{"applicationID": "1", "installationID": "2", "appKey": testGitHubRunnerAppKey}},
+	// missing owner
+	{map[string]string{}, true, testGitHubRunnerPAT},
+	// invalid scope
+	{map[string]string{"owner": "kedacore", "runnerScope": "enterprise"}, true, testGitHubRunnerPAT},
+	// repo scope without repository
+	{map[string]string{"owner": "kedacore", "runnerScope": "repo"}, true, testGitHubRunnerPAT},
+	// repo scope with several repositories
+	{map[string]string{"owner": "kedacore", "runnerScope": "repo", "repos": "keda,http-add-on"}, true, testGitHubRunnerPAT},
+	// runner group with repo scope
+	{map[string]string{"owner": "kedacore", "runnerScope": "repo", "repos": "keda", "runnerGroup": "build"}, true, testGitHubRunnerPAT},
+	// invalid targetWorkflowQueueLength
+	{map[string]string{"owner": "kedacore", "targetWorkflowQueueLength": "a"}, true, testGitHubRunnerPAT},
+	{map[string]string{"owner": "kedacore", "targetWorkflowQueueLength": "0"}, true, testGitHubRunnerPAT},
+	// no auth
+	{map[string]string{"owner": "kedacore"}, true, map[string]string{}},
+	// incomplete GitHub App
+	{map[string]string{"owner": "kedacore"}, true, map[string]string{"applicationID": "1", "appKey": testGitHubRunnerAppKey}},
+	// invalid GitHub App key
+	{map[string]string{"owner": "kedacore"}, true, map[string]string{"applicationID": "1", "installationID": "2", "appKey": "key"}},
+}
+
+var githubRunnerMetricIdentifiers = []githubRunnerMetricIdentifier{
+	{&testGitHubRunnerMetadata[0], 0, "s0-github-runner-kedacore"},
+	{&testGitHubRunnerMetadata[1], 1, "s1-github-runner-kedacore-keda"},
+}
+
+func TestGitHubRunnerParseMetadata(t *testing.T) {
+	for _, testData := range testGitHubRunnerMetadata {
+		_, err := parseGitHubRunnerMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestGitHubRunnerGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range githubRunnerMetricIdentifiers {
+		meta, err := parseGitHubRunnerMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockGitHubRunnerScaler := githubRunnerScaler{metadata: meta}
+
+		metricSpec := mockGitHubRunnerScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestCountGitHubQueuedJobs(t *testing.T) {
+	jobs := []githubWorkflowJob{
+		{Status: "queued", Labels: []string{"self-hosted", "linux"}},
+		{Status: "queued", Labels: []string{"self-hosted", "Linux", "gpu"}},
+		{Status: "queued", Labels: []string{"ubuntu-latest"}},
+		{Status: "queued", Labels: []string{"self-hosted"}, RunnerGroupName: "deploy"},
+		{Status: "in_progress", Labels: []string{"self-hosted", "linux"}},
+		{Status: "completed", Labels: []string{"self-hosted"}},
+	}
+
+	assert.Equal(t, int64(4), countGitHubQueuedJobs(jobs, &githubRunnerMetadata{}))
+	assert.Equal(t, int64(2), countGitHubQueuedJobs(jobs, &githubRunnerMetadata{labels: []string{"self-hosted", "linux"}}))
+	assert.Equal(t, int64(3), countGitHubQueuedJobs(jobs, &githubRunnerMetadata{labels: []string{"self-hosted", "linux", "gpu"}}))
+	assert.Equal(t, int64(3), countGitHubQueuedJobs(jobs, &githubRunnerMetadata{runnerGroup: "build"}))
+}
+
+func newGitHubRunnerTestServer(t *testing.T, rateLimited *bool) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/app/installations/2/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Contains(t, r.Header.Get("Authorization"), "Bearer ey")
+		fmt.Fprintf(w, `{"token":"installation-token","expires_at":"%s"}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	mux.HandleFunc("/orgs/kedacore/repos", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"name":"keda"},{"name":"old","archived":true}]`)
+	})
+	mux.HandleFunc("/repos/kedacore/keda/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+		if *rateLimited {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		auth := r.Header.Get("Authorization")
+		assert.True(t, auth == "Bearer pat" || auth == "Bearer installation-token", auth)
+		switch r.URL.Query().Get("status") {
+		case "queued":
+			fmt.Fprint(w, `{"total_count":1,"workflow_runs":[{"id":1}]}`)
+		case "in_progress":
+			fmt.Fprint(w, `{"total_count":1,"workflow_runs":[{"id":2}]}`)
+		}
+	})
+	mux.HandleFunc("/repos/kedacore/keda/actions/runs/1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"total_count":2,"jobs":[{"status":"queued","labels":["self-hosted"]},{"status":"queued","labels":["self-hosted"]}]}`)
+	})
+	mux.HandleFunc("/repos/kedacore/keda/actions/runs/2/jobs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"total_count":2,"jobs":[{"status":"in_progress","labels":["self-hosted"]},{"status":"queued","labels":["self-hosted"]}]}`)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestGitHubRunnerGetQueuedJobCount(t *testing.T) {
+	rateLimited := false
+	server := newGitHubRunnerTestServer(t, &rateLimited)
+	defer server.Close()
+
+	for _, authParams := range []map[string]string{testGitHubRunnerPAT, {"applicationID": "1", "installationID": "2", "appKey": testGitHubRunnerAppKey}} {
+		rateLimited = false
+		meta, err := parseGitHubRunnerMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"githubAPIURL": server.URL, "owner": "kedacore"}, AuthParams: authParams})
+		assert.NoError(t, err)
+		s := githubRunnerScaler{metadata: meta, httpClient: http.DefaultClient}
+
+		count, err := s.getQueuedJobCount(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+
+		// the last count is returned while rate limited
+		rateLimited = true
+		count, err = s.getQueuedJobCount(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+		assert.True(t, s.rateLimitedUntil.After(time.Now().Add(30*time.Minute)))
+	}
+}
+
+func TestCheckGitHubResponse(t *testing.T) {
+	now := time.Now()
+
+	r := &http.Response{StatusCode: http.StatusForbidden, Header: http.Header{"Retry-After": []string{"30"}}, Body: http.NoBody}
+	err := checkGitHubResponse(r, now)
+	var rateLimitErr *githubRateLimitError
+	assert.ErrorAs(t, err, &rateLimitErr)
+	assert.Equal(t, now.Add(30*time.Second), rateLimitErr.resetAt)
+
+	r = &http.Response{StatusCode: http.StatusForbidden, Status: "403 Forbidden", Header: http.Header{}, Body: http.NoBody}
+	err = checkGitHubResponse(r, now)
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &rateLimitErr))
+}
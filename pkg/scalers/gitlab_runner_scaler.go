@@ -0,0 +1,306 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	gitlabRunnerDefaultAPIURL     = "https://gitlab.com"
+	gitlabRunnerScopeProject      = "project"
+	gitlabRunnerScopeGroup        = "group"
+	gitlabRunnerScopeInstance     = "instance"
+	gitlabRunnerDefaultTargetJobs = 1
+	gitlabRunnerPageSize          = 100
+)
+
+type gitlabRunnerScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *gitlabRunnerMetadata
+	httpClient *http.Client
+}
+
+type gitlabRunnerMetadata struct {
+	apiURL      string
+	scope       string
+	projectID   string
+	groupID     string
+	tags        []string
+	runUntagged bool
+	targetJobs  float64
+	accessToken string
+	scalerIndex int
+}
+
+type gitlabJob struct {
+	ID      int64    `json:"id"`
+	TagList []string `json:"tag_list"`
+}
+
+type gitlabProject struct {
+	ID       int64 `json:"id"`
+	Archived bool  `json:"archived"`
+}
+
+var gitlabRunnerLog = logf.Log.WithName("gitlab_runner_scaler")
+
+// NewGitLabRunnerScaler creates a new gitlabRunnerScaler
+func NewGitLabRunnerScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseGitLabRunnerMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing gitlab runner metadata: %s", err))
+	}
+
+	return &gitlabRunnerScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, false),
+	}, nil
+}
+
+func parseGitLabRunnerMetadata(config *ScalerConfig) (*gitlabRunnerMetadata, error) {
+	meta := gitlabRunnerMetadata{}
+
+	meta.apiURL = gitlabRunnerDefaultAPIURL
+	if val, ok := config.TriggerMetadata["gitlabAPIURL"]; ok && val != "" {
+		meta.apiURL = strings.TrimSuffix(val, "/")
+	}
+
+	meta.scope = gitlabRunnerScopeProject
+	if val, ok := config.TriggerMetadata["scope"]; ok && val != "" {
+		meta.scope = strings.ToLower(val)
+	}
+	switch meta.scope {
+	case gitlabRunnerScopeProject:
+		meta.projectID = config.TriggerMetadata["projectID"]
+		if meta.projectID == "" {
+			return nil, fmt.Errorf("no projectID given for the %s scope", gitlabRunnerScopeProject)
+		}
+	case gitlabRunnerScopeGroup:
+		meta.groupID = config.TriggerMetadata["groupID"]
+		if meta.groupID == "" {
+			return nil, fmt.Errorf("no groupID given for the %s scope", gitlabRunnerScopeGroup)
+		}
+	case gitlabRunnerScopeInstance:
+	default:
+		return nil, fmt.Errorf("scope must be one of %s, %s or %s, got %s", gitlabRunnerScopeProject, gitlabRunnerScopeGroup, gitlabRunnerScopeInstance, meta.scope)
+	}
+
+	if val, ok := config.TriggerMetadata["tags"]; ok && val != "" {
+		meta.tags = splitAndTrim(val)
+	}
+
+	// like the runners, the untagged jobs are only run by default when the runners have no tag
+	meta.runUntagged = len(meta.tags) == 0
+	if val, ok := config.TriggerMetadata["runUntagged"]; ok && val != "" {
+		runUntagged, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("runUntagged parsing error %s", err.Error())
+		}
+		meta.runUntagged = runUntagged
+	}
+
+	meta.targetJobs = gitlabRunnerDefaultTargetJobs
+	if val, ok := config.TriggerMetadata["targetPendingJobs"]; ok {
+		targetJobs, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetPendingJobs parsing error %s", err.Error())
+		}
+		if targetJobs <= 0 {
+			return nil, errors.New("targetPendingJobs must be greater than 0")
+		}
+		meta.targetJobs = targetJobs
+	}
+
+	// personal, group and project access tokens are all sent the same way
+	meta.accessToken = config.AuthParams["accessToken"]
+	if meta.accessToken == "" {
+		return nil, errors.New("no accessToken given")
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// IsActive returns true if there are pending jobs for the runners
+func (s *gitlabRunnerScaler) IsActive(ctx context.Context) (bool, error) {
+	jobs, err := s.getPendingJobCount(ctx)
+	if err != nil {
+		gitlabRunnerLog.Error(err, "error getting gitlab pending jobs")
+		return false, err
+	}
+	return jobs > 0, nil
+}
+
+func (s *gitlabRunnerScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *gitlabRunnerScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	metricName := "gitlab-runner-" + s.metadata.scope
+	switch s.metadata.scope {
+	case gitlabRunnerScopeProject:
+		metricName = fmt.Sprintf("%s-%s", metricName, s.metadata.projectID)
+	case gitlabRunnerScopeGroup:
+		metricName = fmt.Sprintf("%s-%s", metricName, s.metadata.groupID)
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(metricName)),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetJobs),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of pending jobs for the runners
+func (s *gitlabRunnerScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	jobs, err := s.getPendingJobCount(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting gitlab pending jobs: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(jobs))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// getPendingJobCount counts the pending jobs of the projects in the scope the runners can pick
+func (s *gitlabRunnerScaler) getPendingJobCount(ctx context.Context) (int64, error) {
+	projects := []string{s.metadata.projectID}
+	if s.metadata.scope != gitlabRunnerScopeProject {
+		var err error
+		projects, err = s.getProjects(ctx)
+		if err != nil {
+			return -1, err
+		}
+	}
+
+	var count int64
+	for _, project := range projects {
+		path := fmt.Sprintf("/api/v4/projects/%s/jobs?scope[]=pending", url.PathEscape(project))
+		err := s.getPages(ctx, path, func(body []byte) (int, error) {
+			var jobs []gitlabJob
+			if err := json.Unmarshal(body, &jobs); err != nil {
+				return 0, err
+			}
+			count += countGitLabPendingJobs(jobs, s.metadata)
+			return len(jobs), nil
+		})
+		if err != nil {
+			return -1, err
+		}
+	}
+	return count, nil
+}
+
+// getProjects returns the ids of the projects of the group, its subgroups included, or of the instance
+func (s *gitlabRunnerScaler) getProjects(ctx context.Context) ([]string, error) {
+	path := "/api/v4/projects?simple=true&archived=false"
+	if s.metadata.scope == gitlabRunnerScopeGroup {
+		path = fmt.Sprintf("/api/v4/groups/%s/projects?simple=true&archived=false&include_subgroups=true", url.PathEscape(s.metadata.groupID))
+	}
+
+	var projects []string
+	err := s.getPages(ctx, path, func(body []byte) (int, error) {
+		var page []gitlabProject
+		if err := json.Unmarshal(body, &page); err != nil {
+			return 0, err
+		}
+		for _, project := range page {
+			if !project.Archived {
+				projects = append(projects, strconv.FormatInt(project.ID, 10))
+			}
+		}
+		return len(page), nil
+	})
+	return projects, err
+}
+
+// getPages requests all the pages of a list, following the X-Next-Page header, handlePage returns the number of items
+func (s *gitlabRunnerScaler) getPages(ctx context.Context, path string, handlePage func(body []byte) (int, error)) error {
+	page := "1"
+	for page != "" {
+		req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s%s&per_page=%d&page=%s", s.metadata.apiURL, path, gitlabRunnerPageSize, page), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("PRIVATE-TOKEN", s.metadata.accessToken)
+
+		r, err := s.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return err
+		}
+		if r.StatusCode != http.StatusOK {
+			return fmt.Errorf("error requesting gitlab API status: %s, response: %s", r.Status, body)
+		}
+
+		items, err := handlePage(body)
+		if err != nil {
+			return err
+		}
+		page = r.Header.Get("X-Next-Page")
+		if items < gitlabRunnerPageSize {
+			page = ""
+		}
+	}
+	return nil
+}
+
+// countGitLabPendingJobs counts the jobs the runners can pick: the runners have all the tags of the job,
+// and the untagged jobs only when they run untagged jobs
+func countGitLabPendingJobs(jobs []gitlabJob, meta *gitlabRunnerMetadata) int64 {
+	runnerTags := map[string]bool{}
+	for _, tag := range meta.tags {
+		runnerTags[tag] = true
+	}
+
+	var count int64
+	for _, job := range jobs {
+		if len(job.TagList) == 0 {
+			if meta.runUntagged {
+				count++
+			}
+			continue
+		}
+		matches := true
+		for _, tag := range job.TagList {
+			if !runnerTags[tag] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,138 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseGitLabRunnerMetadataTestData struct {
+	metadata   map[string]string
+	isError    bool
+	authParams map[string]string
+}
+
+type gitlabRunnerMetricIdentifier struct {
+	metadataTestData *parseGitLabRunnerMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testGitLabRunnerAuth = map[string]string{"accessToken": "token"}
+
+var testGitLabRunnerMetadata = []parseGitLabRunnerMetadataTestData{
+	// project scope
+	{map[string]string{"projectID": "kedacore/keda"}, false, testGitLabRunnerAuth},
+	// group scope with all properties
+	{map[string]string{"gitlabAPIURL": "https://gitlab.example.com/", "scope": "group", "groupID": "kedacore", "tags": "docker, linux", "runUntagged": "true", "targetPendingJobs": "2"}, false, testGitLabRunnerAuth},
+	// instance scope
+	{map[string]string{"scope": "instance"}, false, testGitLabRunnerAuth},
+	// missing projectID
+	{map[string]string{}, true, testGitLabRunnerAuth},
+	// missing groupID
+	{map[string]string{"scope": "group"}, true, testGitLabRunnerAuth},
+	// invalid scope
+	{map[string]string{"scope": "namespace"}, true, testGitLabRunnerAuth},
+	// invalid runUntagged
+	{map[string]string{"projectID": "1", "runUntagged": "a"}, true, testGitLabRunnerAuth},
+	// invalid targetPendingJobs
+	{map[string]string{"projectID": "1", "targetPendingJobs": "a"}, true, testGitLabRunnerAuth},
+	{map[string]string{"projectID": "1", "targetPendingJobs": "0"}, true, testGitLabRunnerAuth},
+	// missing accessToken
+	{map[string]string{"projectID": "1"}, true, map[string]string{}},
+}
+
+var gitlabRunnerMetricIdentifiers = []gitlabRunnerMetricIdentifier{
+	{&testGitLabRunnerMetadata[0], 0, "s0-gitlab-runner-project-kedacore-keda"},
+	{&testGitLabRunnerMetadata[1], 1, "s1-gitlab-runner-group-kedacore"},
+	{&testGitLabRunnerMetadata[2], 2, "s2-gitlab-runner-instance"},
+}
+
+func TestGitLabRunnerParseMetadata(t *testing.T) {
+	for _, testData := range testGitLabRunnerMetadata {
+		_, err := parseGitLabRunnerMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestGitLabRunnerGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range gitlabRunnerMetricIdentifiers {
+		meta, err := parseGitLabRunnerMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockGitLabRunnerScaler := gitlabRunnerScaler{metadata: meta}
+
+		metricSpec := mockGitLabRunnerScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestCountGitLabPendingJobs(t *testing.T) {
+	jobs := []gitlabJob{
+		{TagList: []string{"docker"}},
+		{TagList: []string{"docker", "linux"}},
+		{TagList: []string{"windows"}},
+		{},
+	}
+
+	assert.Equal(t, int64(1), countGitLabPendingJobs(jobs, &gitlabRunnerMetadata{runUntagged: true}))
+	assert.Equal(t, int64(2), countGitLabPendingJobs(jobs, &gitlabRunnerMetadata{tags: []string{"docker", "linux"}}))
+	assert.Equal(t, int64(3), countGitLabPendingJobs(jobs, &gitlabRunnerMetadata{tags: []string{"docker", "linux"}, runUntagged: true}))
+}
+
+func TestGitLabRunnerGetPendingJobCount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token", r.Header.Get("PRIVATE-TOKEN"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		switch r.URL.Path {
+		case "/api/v4/groups/kedacore/projects":
+			assert.Equal(t, "true", r.URL.Query().Get("include_subgroups"))
+			fmt.Fprint(w, `[{"id":1},{"id":2,"archived":true},{"id":3}]`)
+		case "/api/v4/projects/1/jobs", "/api/v4/projects/3/jobs":
+			assert.Equal(t, "pending", r.URL.Query().Get("scope[]"))
+			// project 1 has a full first page and a second one
+			if r.URL.Path == "/api/v4/projects/1/jobs" && page == 1 {
+				w.Header().Set("X-Next-Page", "2")
+				jobs := "["
+				for i := 0; i < gitlabRunnerPageSize; i++ {
+					if i > 0 {
+						jobs += ","
+					}
+					jobs += fmt.Sprintf(`{"id":%d,"tag_list":["docker"]}`, i)
+				}
+				fmt.Fprint(w, jobs+"]")
+				return
+			}
+			fmt.Fprint(w, `[{"id":1000,"tag_list":["docker"]},{"id":1001,"tag_list":["windows"]}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	meta, err := parseGitLabRunnerMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"gitlabAPIURL": server.URL, "scope": "group", "groupID": "kedacore", "tags": "docker"}, AuthParams: testGitLabRunnerAuth})
+	assert.NoError(t, err)
+	s := gitlabRunnerScaler{metadata: meta, httpClient: http.DefaultClient}
+
+	count, err := s.getPendingJobCount(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(gitlabRunnerPageSize+2), count)
+
+	s.metadata.groupID = "unknown"
+	_, err = s.getPendingJobCount(context.Background())
+	assert.Error(t, err)
+}
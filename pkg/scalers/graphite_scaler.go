@@ -23,6 +23,7 @@ const (
 	grapQuery         = "query"
 	grapThreshold     = "threshold"
 	grapQueryTime     = "queryTime"
+	grapQueryUntil    = "queryUntil"
 )
 
 type graphiteScaler struct {
@@ -37,6 +38,7 @@ type graphiteMetadata struct {
 	query         string
 	threshold     float64
 	from          string
+	until         string
 
 	// basic auth
 	enableBasicAuth bool
@@ -57,15 +59,15 @@ var graphiteLog = logf.Log.WithName("graphite_scaler")
 func NewGraphiteScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseGraphiteMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing graphite metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing graphite metadata: %s", err))
 	}
 
-	httpClient := kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, false)
+	httpClient := createHTTPClient(config, config.GlobalHTTPTimeout, false)
 
 	return &graphiteScaler{
 		metricType: metricType,
@@ -101,6 +103,11 @@ func parseGraphiteMetadata(config *ScalerConfig) (*graphiteMetadata, error) {
 		return nil, fmt.Errorf("no %s given", grapQueryTime)
 	}
 
+	// the end of the window, e.g. -1min to skip the last minute while its datapoints are still being aggregated
+	if val, ok := config.TriggerMetadata[grapQueryUntil]; ok && val != "" {
+		meta.until = val
+	}
+
 	if val, ok := config.TriggerMetadata[grapThreshold]; ok && val != "" {
 		t, err := strconv.ParseFloat(val, 64)
 		if err != nil {
@@ -162,8 +169,14 @@ func (s *graphiteScaler) GetMetricSpecForScaling(context.Context) []v2beta2.Metr
 }
 
 func (s *graphiteScaler) executeGrapQuery(ctx context.Context) (float64, error) {
-	queryEscaped := url_pkg.QueryEscape(s.metadata.query)
-	url := fmt.Sprintf("%s/render?from=%s&target=%s&format=json", s.metadata.serverAddress, s.metadata.from, queryEscaped)
+	query := url_pkg.Values{}
+	query.Set("from", s.metadata.from)
+	if s.metadata.until != "" {
+		query.Set("until", s.metadata.until)
+	}
+	query.Set("target", s.metadata.query)
+	query.Set("format", "json")
+	url := fmt.Sprintf("%s/render?%s", s.metadata.serverAddress, query.Encode())
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return -1, err
@@ -182,6 +195,10 @@ func (s *graphiteScaler) executeGrapQuery(ctx context.Context) (float64, error)
 	}
 	r.Body.Close()
 
+	if r.StatusCode != http.StatusOK {
+		return -1, fmt.Errorf("graphite render API returned status %d: %s", r.StatusCode, string(b))
+	}
+
 	var result grapQueryResult
 	err = json.Unmarshal(b, &result)
 	if err != nil {
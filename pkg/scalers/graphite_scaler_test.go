@@ -35,6 +35,8 @@ var testGrapMetadata = []parseGraphiteMetadataTestData{
 	{map[string]string{"serverAddress": "http://localhost:81", "metricName": "request-count", "threshold": "100", "query": "", "queryTime": "-30Seconds", "disableScaleToZero": "true"}, true},
 	// missing queryTime
 	{map[string]string{"serverAddress": "http://localhost:81", "metricName": "request-count", "threshold": "100", "query": "stats.counters.http.hello-world.request.count.count", "queryTime": ""}, true},
+	// queryUntil
+	{map[string]string{"serverAddress": "http://localhost:81", "metricName": "request-count", "threshold": "100", "query": "stats.counters.http.hello-world.request.count.count", "queryTime": "-5min", "queryUntil": "-1min"}, false},
 }
 
 var graphiteMetricIdentifiers = []graphiteMetricIdentifier{
@@ -190,3 +192,24 @@ func TestGrapScalerExecuteGrapQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestGrapScalerQueryWindow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/render", request.URL.Path)
+		assert.Equal(t, "-5min", request.URL.Query().Get("from"))
+		assert.Equal(t, "-1min", request.URL.Query().Get("until"))
+		assert.Equal(t, "sumSeries(stats.counters.http.*.request.count)", request.URL.Query().Get("target"))
+		assert.Equal(t, "json", request.URL.Query().Get("format"))
+		_, _ = writer.Write([]byte(`[{"target":"sumSeries(stats.counters.http.*.request.count)","datapoints":[[4,10000000],[null,10000010]]}]`))
+	}))
+	defer server.Close()
+
+	meta, err := parseGraphiteMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"serverAddress": server.URL, "metricName": "request-count", "threshold": "100",
+		"query": "sumSeries(stats.counters.http.*.request.count)", "queryTime": "-5min", "queryUntil": "-1min"}})
+	assert.NoError(t, err)
+	scaler := graphiteScaler{metadata: meta, httpClient: http.DefaultClient}
+
+	value, err := scaler.executeGrapQuery(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, float64(4), value)
+}
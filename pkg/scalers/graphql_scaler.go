@@ -0,0 +1,186 @@
+package scalers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+type graphqlScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *graphqlMetadata
+	client     *http.Client
+}
+
+// graphqlMetadata is the metadata of the metrics-api scaler, the url being the GraphQL endpoint and valueLocation
+// pointing to the value in the response, plus the GraphQL request
+type graphqlMetadata struct {
+	*metricsAPIScalerMetadata
+
+	query         string
+	variables     json.RawMessage
+	operationName string
+}
+
+// graphqlRequest is the body of a GraphQL request over HTTP
+type graphqlRequest struct {
+	Query         string          `json:"query"`
+	Variables     json.RawMessage `json:"variables,omitempty"`
+	OperationName string          `json:"operationName,omitempty"`
+}
+
+var graphqlLog = logf.Log.WithName("graphql_scaler")
+
+// NewGraphQLScaler creates a new graphqlScaler
+func NewGraphQLScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseGraphQLMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing graphql metadata: %s", err))
+	}
+
+	httpClient := createHTTPClient(config, config.GlobalHTTPTimeout, false)
+
+	if meta.enableTLS || len(meta.ca) > 0 {
+		config, err := kedautil.NewTLSConfig(meta.cert, meta.key, meta.ca)
+		if err != nil {
+			return nil, err
+		}
+
+		httpClient.Transport = &http.Transport{TLSClientConfig: config}
+	}
+
+	return &graphqlScaler{
+		metricType: metricType,
+		metadata:   meta,
+		client:     httpClient,
+	}, nil
+}
+
+func parseGraphQLMetadata(config *ScalerConfig) (*graphqlMetadata, error) {
+	apiMeta, err := parseMetricsAPIMetadata(config)
+	if err != nil {
+		return nil, err
+	}
+	meta := graphqlMetadata{metricsAPIScalerMetadata: apiMeta}
+
+	if val, ok := config.TriggerMetadata["query"]; ok && strings.TrimSpace(val) != "" {
+		meta.query = val
+	} else {
+		return nil, errors.New("no query given in metadata")
+	}
+
+	if val, ok := config.TriggerMetadata["variables"]; ok && val != "" {
+		var variables map[string]interface{}
+		if err := json.Unmarshal([]byte(val), &variables); err != nil {
+			return nil, fmt.Errorf("variables must be a JSON object: %s", err)
+		}
+		meta.variables = json.RawMessage(val)
+	}
+
+	meta.operationName = config.TriggerMetadata["operationName"]
+
+	return &meta, nil
+}
+
+func (s *graphqlScaler) getMetricValue(ctx context.Context) (float64, error) {
+	body, err := json.Marshal(graphqlRequest{
+		Query:         s.metadata.query,
+		Variables:     s.metadata.variables,
+		OperationName: s.metadata.operationName,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	request, err := newMetricsAPIRequest(ctx, s.metadata.metricsAPIScalerMetadata, "POST", bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	r, err := s.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Body.Close()
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return 0, err
+	}
+	if r.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("%s: graphql endpoint returned %d, response: %s", r.Request.URL.Path, r.StatusCode, b)
+	}
+
+	// a GraphQL endpoint answers 200 even if the query failed, with the errors in the response
+	if errs := gjson.GetBytes(b, "errors.#.message"); len(errs.Array()) > 0 {
+		messages := make([]string, 0, len(errs.Array()))
+		for _, message := range errs.Array() {
+			messages = append(messages, message.String())
+		}
+		return 0, fmt.Errorf("graphql query returned errors: %s", strings.Join(messages, "; "))
+	}
+
+	return GetValueFromResponse(b, s.metadata.valueLocation)
+}
+
+// Close does nothing in case of graphqlScaler
+func (s *graphqlScaler) Close(context.Context) error {
+	return nil
+}
+
+// IsActive returns true if the value returned by the query is greater than 0
+func (s *graphqlScaler) IsActive(ctx context.Context) (bool, error) {
+	v, err := s.getMetricValue(ctx)
+	if err != nil {
+		graphqlLog.Error(err, "error getting graphql metric value")
+		return false, err
+	}
+
+	return v > 0.0, nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *graphqlScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("graphql-%s", s.metadata.valueLocation))),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the value returned by the query
+func (s *graphqlScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	val, err := s.getMetricValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error requesting graphql endpoint: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, val)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,133 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseGraphQLMetadataTestData struct {
+	metadata   map[string]string
+	isError    bool
+	authParams map[string]string
+}
+
+type graphqlMetricIdentifier struct {
+	metadataTestData *parseGraphQLMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testGraphQLMetadata = []parseGraphQLMetadataTestData{
+	// only required properties
+	{map[string]string{"url": "http://dummy:4000/graphql", "query": "{ queue { size } }", "valueLocation": "data.queue.size", "targetValue": "10"}, false, map[string]string{}},
+	// variables, operation name and bearer auth
+	{map[string]string{"url": "http://dummy:4000/graphql", "query": "query Queue($name: String!) { queue(name: $name) { size } }", "variables": `{"name": "orders"}`,
+		"operationName": "Queue", "valueLocation": "data.queue.size", "targetValue": "10", "authMode": "bearer"}, false, map[string]string{"token": "token"}},
+	// missing query
+	{map[string]string{"url": "http://dummy:4000/graphql", "valueLocation": "data.queue.size", "targetValue": "10"}, true, map[string]string{}},
+	{map[string]string{"url": "http://dummy:4000/graphql", "query": " ", "valueLocation": "data.queue.size", "targetValue": "10"}, true, map[string]string{}},
+	// variables not a JSON object
+	{map[string]string{"url": "http://dummy:4000/graphql", "query": "{ queue { size } }", "variables": `["orders"]`, "valueLocation": "data.queue.size", "targetValue": "10"}, true, map[string]string{}},
+	// missing url
+	{map[string]string{"query": "{ queue { size } }", "valueLocation": "data.queue.size", "targetValue": "10"}, true, map[string]string{}},
+	// missing valueLocation
+	{map[string]string{"url": "http://dummy:4000/graphql", "query": "{ queue { size } }", "targetValue": "10"}, true, map[string]string{}},
+	// missing targetValue
+	{map[string]string{"url": "http://dummy:4000/graphql", "query": "{ queue { size } }", "valueLocation": "data.queue.size"}, true, map[string]string{}},
+	// bearer auth without token
+	{map[string]string{"url": "http://dummy:4000/graphql", "query": "{ queue { size } }", "valueLocation": "data.queue.size", "targetValue": "10", "authMode": "bearer"}, true, map[string]string{}},
+}
+
+var graphqlMetricIdentifiers = []graphqlMetricIdentifier{
+	{&testGraphQLMetadata[0], 0, "s0-graphql-data-queue-size"},
+	{&testGraphQLMetadata[1], 1, "s1-graphql-data-queue-size"},
+}
+
+func TestGraphQLParseMetadata(t *testing.T) {
+	for _, testData := range testGraphQLMetadata {
+		_, err := parseGraphQLMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestGraphQLGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range graphqlMetricIdentifiers {
+		meta, err := parseGraphQLMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockGraphQLScaler := graphqlScaler{metadata: meta}
+
+		metricSpec := mockGraphQLScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestGraphQLGetMetricValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var request graphqlRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		var variables map[string]string
+		assert.NoError(t, json.Unmarshal(request.Variables, &variables))
+		switch variables["name"] {
+		case "orders":
+			fmt.Fprint(w, `{"data":{"queue":{"size":7}}}`)
+		case "quantity":
+			fmt.Fprint(w, `{"data":{"queue":{"size":"1500m"}}}`)
+		default:
+			fmt.Fprint(w, `{"data":null,"errors":[{"message":"queue not found"}]}`)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		queue   string
+		token   string
+		value   float64
+		isError bool
+	}{
+		{"orders", "token", 7, false},
+		{"quantity", "token", 1.5, false},
+		{"unknown", "token", 0, true},
+		{"orders", "invalid", 0, true},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parseGraphQLMetadata(&ScalerConfig{
+			TriggerMetadata: map[string]string{"url": server.URL, "query": "query Queue($name: String!) { queue(name: $name) { size } }",
+				"variables": fmt.Sprintf(`{"name": "%s"}`, testCase.queue), "valueLocation": "data.queue.size", "targetValue": "10", "authMode": "bearer"},
+			AuthParams: map[string]string{"token": testCase.token},
+		})
+		assert.NoError(t, err)
+		s := graphqlScaler{metadata: meta, client: http.DefaultClient}
+
+		value, err := s.getMetricValue(context.Background())
+		if testCase.isError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.value, value)
+	}
+}
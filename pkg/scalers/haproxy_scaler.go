@@ -0,0 +1,280 @@
+package scalers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	defaultHaproxyTargetSessions = 100
+
+	haproxyBackendServerName = "BACKEND"
+	haproxyShowStatCommand   = "show stat\n"
+)
+
+var haproxyLog = logf.Log.WithName("haproxy_scaler")
+
+type haproxyScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *haproxyMetadata
+	httpClient *http.Client
+	timeout    time.Duration
+}
+
+type haproxyMetadata struct {
+	// statsURL is the http(s) url of the stats page, or the tcp:// or unix:// address of the stats socket
+	statsURL           *url.URL
+	backend            string
+	targetSessions     int64
+	activationSessions int64
+	unsafeSsl          bool
+	scalerIndex        int
+
+	// authentication of the stats page
+	username string
+	password string
+}
+
+// NewHaproxyScaler creates a new haproxyScaler, reading the current sessions of a backend from the HAProxy stats
+func NewHaproxyScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseHaproxyMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing haproxy metadata: %s", err))
+	}
+
+	return &haproxyScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, meta.unsafeSsl),
+		timeout:    config.GlobalHTTPTimeout,
+	}, nil
+}
+
+func parseHaproxyMetadata(config *ScalerConfig) (*haproxyMetadata, error) {
+	meta := haproxyMetadata{
+		targetSessions: defaultHaproxyTargetSessions,
+	}
+
+	if val, ok := config.TriggerMetadata["statsURL"]; ok && val != "" {
+		statsURL, err := url.Parse(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid statsURL: %s", err)
+		}
+		switch statsURL.Scheme {
+		case "http", "https", "tcp":
+			if statsURL.Host == "" {
+				return nil, fmt.Errorf("invalid statsURL: no host given")
+			}
+		case "unix":
+			if statsURL.Path == "" {
+				return nil, fmt.Errorf("invalid statsURL: no socket path given")
+			}
+		default:
+			return nil, fmt.Errorf("invalid statsURL: unsupported scheme %s, http, https, tcp or unix expected", statsURL.Scheme)
+		}
+		meta.statsURL = statsURL
+	} else {
+		return nil, fmt.Errorf("no statsURL given")
+	}
+
+	if val, ok := config.TriggerMetadata["backend"]; ok && val != "" {
+		meta.backend = val
+	} else {
+		return nil, fmt.Errorf("no backend given")
+	}
+
+	if val, ok := config.TriggerMetadata["targetSessions"]; ok && val != "" {
+		targetSessions, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetSessions parsing error %s", err.Error())
+		}
+		meta.targetSessions = targetSessions
+	}
+
+	if val, ok := config.TriggerMetadata["activationSessions"]; ok && val != "" {
+		activationSessions, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationSessions parsing error %s", err.Error())
+		}
+		meta.activationSessions = activationSessions
+	}
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok && val != "" {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("unsafeSsl parsing error %s", err.Error())
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	// the stats page is protected with the stats auth of HAProxy, if any
+	meta.username = config.AuthParams["username"]
+	meta.password = config.AuthParams["password"]
+	if meta.username != "" && meta.password == "" {
+		return nil, errors.New("no password given")
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// readStats returns the stats in the CSV format, from the stats page or with the show stat command of the stats socket
+func (s *haproxyScaler) readStats(ctx context.Context) ([]byte, error) {
+	switch s.metadata.statsURL.Scheme {
+	case "tcp", "unix":
+		address := s.metadata.statsURL.Host
+		if s.metadata.statsURL.Scheme == "unix" {
+			address = s.metadata.statsURL.Path
+		}
+		dialer := net.Dialer{Timeout: s.timeout}
+		conn, err := dialer.DialContext(ctx, s.metadata.statsURL.Scheme, address)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+
+		if deadline, ok := ctx.Deadline(); ok {
+			_ = conn.SetDeadline(deadline)
+		} else if s.timeout > 0 {
+			_ = conn.SetDeadline(time.Now().Add(s.timeout))
+		}
+		if _, err := io.WriteString(conn, haproxyShowStatCommand); err != nil {
+			return nil, err
+		}
+		// HAProxy closes the connection once the command is answered
+		return ioutil.ReadAll(conn)
+	default:
+		// the stats page returns the CSV format with the ;csv suffix
+		statsURL := *s.metadata.statsURL
+		if !strings.HasSuffix(statsURL.Path, ";csv") {
+			statsURL.Path += ";csv"
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, statsURL.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		if s.metadata.username != "" {
+			req.SetBasicAuth(s.metadata.username, s.metadata.password)
+		}
+
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("haproxy stats returned status %d: %s", resp.StatusCode, string(body))
+		}
+		return body, nil
+	}
+}
+
+// getCurrentSessions returns the current sessions (scur) of the backend
+func (s *haproxyScaler) getCurrentSessions(ctx context.Context) (int64, error) {
+	stats, err := s.readStats(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// the first line is the header, prefixed with "# "
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(string(stats), "# ")))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return 0, fmt.Errorf("error parsing haproxy stats: %s", err)
+	}
+	if len(records) == 0 {
+		return 0, errors.New("haproxy stats are empty")
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[name] = i
+	}
+	pxname, okPxname := columns["pxname"]
+	svname, okSvname := columns["svname"]
+	scur, okScur := columns["scur"]
+	if !okPxname || !okSvname || !okScur {
+		return 0, errors.New("haproxy stats have no pxname, svname or scur column")
+	}
+
+	for _, record := range records[1:] {
+		if len(record) <= scur || record[pxname] != s.metadata.backend || record[svname] != haproxyBackendServerName {
+			continue
+		}
+		sessions, err := strconv.ParseInt(record[scur], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing the current sessions of backend %s: %s", s.metadata.backend, err)
+		}
+		return sessions, nil
+	}
+	return 0, fmt.Errorf("backend %s not found in the haproxy stats", s.metadata.backend)
+}
+
+// IsActive returns true if the backend has more current sessions than activationSessions
+func (s *haproxyScaler) IsActive(ctx context.Context) (bool, error) {
+	sessions, err := s.getCurrentSessions(ctx)
+	if err != nil {
+		haproxyLog.Error(err, "error getting haproxy sessions", "backend", s.metadata.backend)
+		return false, err
+	}
+
+	return sessions > s.metadata.activationSessions, nil
+}
+
+func (s *haproxyScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *haproxyScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("haproxy-%s", s.metadata.backend))),
+		},
+		Target: GetMetricTarget(s.metricType, s.metadata.targetSessions),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the current sessions of the backend
+func (s *haproxyScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	sessions, err := s.getCurrentSessions(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting haproxy sessions: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(sessions))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,173 @@
+package scalers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testHaproxyStats = `# pxname,svname,qcur,qmax,scur,smax,slim,stot,bin,bout,
+stats,FRONTEND,,,1,2,3000,5,1000,2000,
+api,FRONTEND,,,40,50,3000,500,1000,2000,
+api,api-1,0,0,20,25,,250,500,1000,
+api,api-2,0,0,17,25,,250,500,1000,
+api,BACKEND,0,0,37,50,300,500,1000,2000,
+web,BACKEND,0,0,0,10,300,50,100,200,
+`
+
+type parseHaproxyMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type haproxyMetricIdentifier struct {
+	metadataTestData *parseHaproxyMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testHaproxyMetadata = []parseHaproxyMetadataTestData{
+	// only required properties
+	{map[string]string{"statsURL": "http://haproxy:8404/stats", "backend": "api"}, map[string]string{}, false},
+	// all properties with the stats socket
+	{map[string]string{"statsURL": "tcp://haproxy:9999", "backend": "web_backend", "targetSessions": "50", "activationSessions": "5", "unsafeSsl": "true"}, map[string]string{}, false},
+	// unix socket
+	{map[string]string{"statsURL": "unix:///var/run/haproxy.sock", "backend": "api"}, map[string]string{}, false},
+	// stats auth
+	{map[string]string{"statsURL": "https://haproxy:8404/stats", "backend": "api"}, map[string]string{"username": "admin", "password": "secret"}, false},
+	// missing password
+	{map[string]string{"statsURL": "https://haproxy:8404/stats", "backend": "api"}, map[string]string{"username": "admin"}, true},
+	// missing statsURL
+	{map[string]string{"backend": "api"}, map[string]string{}, true},
+	// unsupported scheme
+	{map[string]string{"statsURL": "udp://haproxy:9999", "backend": "api"}, map[string]string{}, true},
+	// no host
+	{map[string]string{"statsURL": "tcp:///haproxy", "backend": "api"}, map[string]string{}, true},
+	// no socket path
+	{map[string]string{"statsURL": "unix://", "backend": "api"}, map[string]string{}, true},
+	// missing backend
+	{map[string]string{"statsURL": "http://haproxy:8404/stats"}, map[string]string{}, true},
+	// invalid counts
+	{map[string]string{"statsURL": "http://haproxy:8404/stats", "backend": "api", "targetSessions": "a"}, map[string]string{}, true},
+	{map[string]string{"statsURL": "http://haproxy:8404/stats", "backend": "api", "activationSessions": "a"}, map[string]string{}, true},
+	// invalid unsafeSsl
+	{map[string]string{"statsURL": "http://haproxy:8404/stats", "backend": "api", "unsafeSsl": "a"}, map[string]string{}, true},
+}
+
+var haproxyMetricIdentifiers = []haproxyMetricIdentifier{
+	{&testHaproxyMetadata[0], 0, "s0-haproxy-api"},
+	{&testHaproxyMetadata[1], 1, "s1-haproxy-web_backend"},
+}
+
+func TestHaproxyParseMetadata(t *testing.T) {
+	for _, testData := range testHaproxyMetadata {
+		_, err := parseHaproxyMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestHaproxyGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range haproxyMetricIdentifiers {
+		meta, err := parseHaproxyMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockHaproxyScaler := haproxyScaler{metadata: meta}
+
+		metricSpec := mockHaproxyScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+// startHaproxyTestSocket answers the show stat command like the stats socket of HAProxy
+func startHaproxyTestSocket(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			command, err := bufio.NewReader(conn).ReadString('\n')
+			if err == nil && command == haproxyShowStatCommand {
+				fmt.Fprint(conn, testHaproxyStats)
+			} else {
+				fmt.Fprint(conn, "Unknown command.\n")
+			}
+			conn.Close()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestHaproxyGetCurrentSessions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/stats;csv" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, testHaproxyStats)
+	}))
+	defer server.Close()
+	socketAddr := startHaproxyTestSocket(t)
+
+	authParams := map[string]string{"username": "admin", "password": "secret"}
+	testCases := []struct {
+		metadata   map[string]string
+		authParams map[string]string
+		sessions   int64
+		isActive   bool
+		isError    bool
+	}{
+		{map[string]string{"statsURL": server.URL + "/stats", "backend": "api"}, authParams, 37, true, false},
+		{map[string]string{"statsURL": server.URL + "/stats;csv", "backend": "web"}, authParams, 0, false, false},
+		{map[string]string{"statsURL": "tcp://" + socketAddr, "backend": "api", "activationSessions": "37"}, map[string]string{}, 37, false, false},
+		{map[string]string{"statsURL": "tcp://" + socketAddr, "backend": "web"}, map[string]string{}, 0, false, false},
+		// unknown backend
+		{map[string]string{"statsURL": "tcp://" + socketAddr, "backend": "stats"}, map[string]string{}, 0, false, true},
+		// invalid credentials
+		{map[string]string{"statsURL": server.URL + "/stats", "backend": "api"}, map[string]string{"username": "admin", "password": "wrong"}, 0, false, true},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parseHaproxyMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, AuthParams: testCase.authParams})
+		assert.NoError(t, err)
+		s := haproxyScaler{metadata: meta, httpClient: http.DefaultClient, timeout: 5 * time.Second}
+
+		sessions, err := s.getCurrentSessions(context.Background())
+		if testCase.isError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.sessions, sessions, "metadata %v", testCase.metadata)
+
+		isActive, err := s.IsActive(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.isActive, isActive, "metadata %v", testCase.metadata)
+	}
+}
@@ -0,0 +1,303 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	defaultHarborTargetJobCount = 5
+
+	harborJobTypeReplication = "replication"
+	harborJobTypeScan        = "scan"
+
+	harborExecutionsPageSize = 100
+)
+
+var harborLog = logf.Log.WithName("harbor_scaler")
+
+type harborScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *harborMetadata
+	httpClient *http.Client
+}
+
+type harborMetadata struct {
+	harborURL          string
+	jobType            string
+	policyID           string
+	targetJobCount     int64
+	activationJobCount int64
+	unsafeSsl          bool
+	scalerIndex        int
+
+	// authentication with a robot account
+	username string
+	password string
+}
+
+// harborReplicationExecution is an execution of a replication policy, with the number of its tasks by status
+type harborReplicationExecution struct {
+	ID         int64  `json:"id"`
+	Status     string `json:"status"`
+	Total      int64  `json:"total"`
+	Failed     int64  `json:"failed"`
+	Succeed    int64  `json:"succeed"`
+	InProgress int64  `json:"in_progress"`
+	Stopped    int64  `json:"stopped"`
+}
+
+// harborScanMetrics is the progress of the scan of all the artifacts, metrics holds the number of scans by status
+type harborScanMetrics struct {
+	Total     int64            `json:"total"`
+	Completed int64            `json:"completed"`
+	Metrics   map[string]int64 `json:"metrics"`
+	Ongoing   bool             `json:"ongoing"`
+}
+
+// harborErrors is the error returned by the Harbor API
+type harborErrors struct {
+	Errors []struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// NewHarborScaler creates a new harborScaler, counting the pending replication tasks or scans of Harbor
+func NewHarborScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseHarborMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing harbor metadata: %s", err))
+	}
+
+	return &harborScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, meta.unsafeSsl),
+	}, nil
+}
+
+func parseHarborMetadata(config *ScalerConfig) (*harborMetadata, error) {
+	meta := harborMetadata{
+		targetJobCount: defaultHarborTargetJobCount,
+		jobType:        harborJobTypeReplication,
+	}
+
+	if val, ok := config.TriggerMetadata["harborURL"]; ok && val != "" {
+		if _, err := url.ParseRequestURI(val); err != nil {
+			return nil, fmt.Errorf("invalid harborURL: %s", err)
+		}
+		meta.harborURL = strings.TrimSuffix(val, "/")
+	} else {
+		return nil, fmt.Errorf("no harborURL given")
+	}
+
+	if val, ok := config.TriggerMetadata["jobType"]; ok && val != "" {
+		meta.jobType = strings.TrimSpace(val)
+	}
+	switch meta.jobType {
+	case harborJobTypeReplication:
+		if val, ok := config.TriggerMetadata["policyID"]; ok && val != "" {
+			if _, err := strconv.ParseInt(val, 10, 64); err != nil {
+				return nil, fmt.Errorf("policyID parsing error %s", err.Error())
+			}
+			meta.policyID = val
+		}
+	case harborJobTypeScan:
+		if config.TriggerMetadata["policyID"] != "" {
+			return nil, fmt.Errorf("policyID is only supported with the %s jobType", harborJobTypeReplication)
+		}
+	default:
+		return nil, fmt.Errorf("err incorrect value for jobType is given: %s", meta.jobType)
+	}
+
+	if val, ok := config.TriggerMetadata["targetJobCount"]; ok && val != "" {
+		targetJobCount, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetJobCount parsing error %s", err.Error())
+		}
+		meta.targetJobCount = targetJobCount
+	}
+
+	if val, ok := config.TriggerMetadata["activationJobCount"]; ok && val != "" {
+		activationJobCount, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationJobCount parsing error %s", err.Error())
+		}
+		meta.activationJobCount = activationJobCount
+	}
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok && val != "" {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("unsafeSsl parsing error %s", err.Error())
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	// the robot accounts are named robot$<name>, or robot$<project>+<name> for project robot accounts
+	meta.username = config.AuthParams["username"]
+	if meta.username == "" {
+		meta.username = config.TriggerMetadata["username"]
+	}
+	if meta.username == "" {
+		return nil, errors.New("no username given")
+	}
+	if len(config.AuthParams["password"]) == 0 {
+		return nil, errors.New("no password given")
+	}
+	meta.password = config.AuthParams["password"]
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+func (s *harborScaler) get(ctx context.Context, path string, query url.Values, result interface{}) error {
+	apiURL := fmt.Sprintf("%s/api/v2.0%s", s.metadata.harborURL, path)
+	if len(query) > 0 {
+		apiURL = fmt.Sprintf("%s?%s", apiURL, query.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.metadata.username, s.metadata.password)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErrors harborErrors
+		if err := json.Unmarshal(body, &apiErrors); err == nil && len(apiErrors.Errors) > 0 {
+			return fmt.Errorf("harbor returned %s: %s", apiErrors.Errors[0].Code, apiErrors.Errors[0].Message)
+		}
+		return fmt.Errorf("harbor returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("error parsing harbor response: %s", err)
+	}
+	return nil
+}
+
+// getReplicationTaskCount returns the number of tasks of the running replication executions which are not done yet
+func (s *harborScaler) getReplicationTaskCount(ctx context.Context) (int64, error) {
+	query := url.Values{
+		"status":    {"InProgress"},
+		"page_size": {strconv.Itoa(harborExecutionsPageSize)},
+	}
+	if s.metadata.policyID != "" {
+		query.Set("policy_id", s.metadata.policyID)
+	}
+
+	var count int64
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var executions []harborReplicationExecution
+		if err := s.get(ctx, "/replication/executions", query, &executions); err != nil {
+			return 0, err
+		}
+		for _, execution := range executions {
+			// the tasks which aren't created yet are part of the total only
+			if pending := execution.Total - execution.Succeed - execution.Failed - execution.Stopped; pending > 0 {
+				count += pending
+			}
+		}
+		if len(executions) < harborExecutionsPageSize {
+			return count, nil
+		}
+	}
+}
+
+// getScanCount returns the number of pending and running scans
+func (s *harborScaler) getScanCount(ctx context.Context) (int64, error) {
+	var metrics harborScanMetrics
+	if err := s.get(ctx, "/scans/all/metrics", nil, &metrics); err != nil {
+		return 0, err
+	}
+	return metrics.Metrics["Pending"] + metrics.Metrics["Running"], nil
+}
+
+func (s *harborScaler) getJobCount(ctx context.Context) (int64, error) {
+	if s.metadata.jobType == harborJobTypeScan {
+		return s.getScanCount(ctx)
+	}
+	return s.getReplicationTaskCount(ctx)
+}
+
+// IsActive returns true if there are more pending jobs than activationJobCount
+func (s *harborScaler) IsActive(ctx context.Context) (bool, error) {
+	count, err := s.getJobCount(ctx)
+	if err != nil {
+		harborLog.Error(err, "error getting harbor job count", "jobType", s.metadata.jobType)
+		return false, err
+	}
+
+	return count > s.metadata.activationJobCount, nil
+}
+
+func (s *harborScaler) Close(context.Context) error {
+	if s.httpClient != nil {
+		s.httpClient.CloseIdleConnections()
+	}
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *harborScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	metricName := fmt.Sprintf("harbor-%s", s.metadata.jobType)
+	if s.metadata.policyID != "" {
+		metricName = fmt.Sprintf("%s-%s", metricName, s.metadata.policyID)
+	}
+
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(metricName)),
+		},
+		Target: GetMetricTarget(s.metricType, s.metadata.targetJobCount),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of pending replication tasks or scans
+func (s *harborScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	count, err := s.getJobCount(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting harbor job count: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(count))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,159 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseHarborMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type harborMetricIdentifier struct {
+	metadataTestData *parseHarborMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testHarborAuthParams = map[string]string{"username": "robot$keda", "password": "secret"}
+
+var testHarborMetadata = []parseHarborMetadataTestData{
+	// only required properties
+	{map[string]string{"harborURL": "https://harbor.example.com"}, testHarborAuthParams, false},
+	// all properties of replication
+	{map[string]string{"harborURL": "https://harbor.example.com/", "jobType": "replication", "policyID": "3", "targetJobCount": "10", "activationJobCount": "2", "unsafeSsl": "true"},
+		testHarborAuthParams, false},
+	// scan with the username in metadata
+	{map[string]string{"harborURL": "https://harbor.example.com", "jobType": "scan", "username": "robot$keda"}, map[string]string{"password": "secret"}, false},
+	// policyID with scan
+	{map[string]string{"harborURL": "https://harbor.example.com", "jobType": "scan", "policyID": "3"}, testHarborAuthParams, true},
+	// invalid policyID
+	{map[string]string{"harborURL": "https://harbor.example.com", "policyID": "a"}, testHarborAuthParams, true},
+	// invalid jobType
+	{map[string]string{"harborURL": "https://harbor.example.com", "jobType": "gc"}, testHarborAuthParams, true},
+	// missing harborURL
+	{map[string]string{}, testHarborAuthParams, true},
+	// invalid harborURL
+	{map[string]string{"harborURL": "harbor"}, testHarborAuthParams, true},
+	// missing username
+	{map[string]string{"harborURL": "https://harbor.example.com"}, map[string]string{"password": "secret"}, true},
+	// missing password
+	{map[string]string{"harborURL": "https://harbor.example.com"}, map[string]string{"username": "robot$keda"}, true},
+	// invalid counts
+	{map[string]string{"harborURL": "https://harbor.example.com", "targetJobCount": "a"}, testHarborAuthParams, true},
+	{map[string]string{"harborURL": "https://harbor.example.com", "activationJobCount": "a"}, testHarborAuthParams, true},
+	// invalid unsafeSsl
+	{map[string]string{"harborURL": "https://harbor.example.com", "unsafeSsl": "a"}, testHarborAuthParams, true},
+}
+
+var harborMetricIdentifiers = []harborMetricIdentifier{
+	{&testHarborMetadata[0], 0, "s0-harbor-replication"},
+	{&testHarborMetadata[1], 1, "s1-harbor-replication-3"},
+	{&testHarborMetadata[2], 2, "s2-harbor-scan"},
+}
+
+func TestHarborParseMetadata(t *testing.T) {
+	for _, testData := range testHarborMetadata {
+		_, err := parseHarborMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestHarborGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range harborMetricIdentifiers {
+		meta, err := parseHarborMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockHarborScaler := harborScaler{metadata: meta}
+
+		metricSpec := mockHarborScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestHarborGetJobCount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "robot$keda" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"errors":[{"code":"UNAUTHORIZED","message":"unauthorized"}]}`)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v2.0/replication/executions":
+			assert.Equal(t, "InProgress", r.URL.Query().Get("status"))
+			switch {
+			case r.URL.Query().Get("policy_id") == "2":
+				fmt.Fprint(w, `[{"id":3,"policy_id":2,"status":"InProgress","total":4,"failed":1,"succeed":1,"in_progress":2,"stopped":0}]`)
+			case r.URL.Query().Get("page") == "1":
+				// a full page, the executions are listed until a page isn't full
+				executions := "["
+				for i := 0; i < harborExecutionsPageSize; i++ {
+					if i > 0 {
+						executions += ","
+					}
+					executions += fmt.Sprintf(`{"id":%d,"status":"InProgress","total":1,"failed":0,"succeed":0,"in_progress":1,"stopped":0}`, i)
+				}
+				fmt.Fprint(w, executions+"]")
+			default:
+				fmt.Fprint(w, `[{"id":200,"status":"InProgress","total":10,"failed":2,"succeed":3,"in_progress":1,"stopped":0}]`)
+			}
+		case "/api/v2.0/scans/all/metrics":
+			fmt.Fprint(w, `{"total":20,"completed":12,"metrics":{"Pending":5,"Running":3,"Success":12},"ongoing":true,"trigger":"Manual"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		metadata   map[string]string
+		authParams map[string]string
+		count      int64
+		isActive   bool
+		isError    bool
+	}{
+		{map[string]string{}, testHarborAuthParams, 105, true, false},
+		{map[string]string{"policyID": "2"}, testHarborAuthParams, 2, true, false},
+		{map[string]string{"policyID": "2", "activationJobCount": "2"}, testHarborAuthParams, 2, false, false},
+		{map[string]string{"jobType": "scan"}, testHarborAuthParams, 8, true, false},
+		// invalid credentials
+		{map[string]string{}, map[string]string{"username": "robot$keda", "password": "wrong"}, 0, false, true},
+	}
+
+	for _, testCase := range testCases {
+		testCase.metadata["harborURL"] = server.URL
+		meta, err := parseHarborMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, AuthParams: testCase.authParams})
+		assert.NoError(t, err)
+		s := harborScaler{metadata: meta, httpClient: http.DefaultClient}
+
+		count, err := s.getJobCount(context.Background())
+		if testCase.isError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.count, count, "metadata %v", testCase.metadata)
+
+		isActive, err := s.IsActive(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.isActive, isActive, "metadata %v", testCase.metadata)
+	}
+}
@@ -0,0 +1,351 @@
+package scalers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	krbclient "github.com/jcmturner/gokrb5/v8/client"
+	krbconfig "github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	defaultHdfsTargetFileCount = 5
+
+	hdfsAuthModeSimple          = "simple"
+	hdfsAuthModeKerberos        = "kerberos"
+	hdfsAuthModeDelegationToken = "delegationToken"
+)
+
+var hdfsLog = logf.Log.WithName("hdfs_scaler")
+
+type hdfsScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *hdfsMetadata
+	httpClient *http.Client
+	// krbClient and spnegoClient are only set with the kerberos authMode
+	krbClient    *krbclient.Client
+	spnegoClient *spnego.Client
+}
+
+type hdfsMetadata struct {
+	namenodeURL         string
+	directory           string
+	glob                string
+	targetFileCount     int64
+	activationFileCount int64
+	unsafeSsl           bool
+	scalerIndex         int
+
+	// authentication
+	authMode             string
+	username             string
+	delegationToken      string
+	realm                string
+	password             string
+	keytab               []byte
+	krb5Conf             string
+	servicePrincipalName string
+}
+
+// webHdfsListStatus is the response of the LISTSTATUS operation of WebHDFS
+type webHdfsListStatus struct {
+	FileStatuses struct {
+		FileStatus []struct {
+			PathSuffix string `json:"pathSuffix"`
+			Type       string `json:"type"`
+		} `json:"FileStatus"`
+	} `json:"FileStatuses"`
+}
+
+// webHdfsRemoteException is the error returned by WebHDFS
+type webHdfsRemoteException struct {
+	RemoteException struct {
+		Exception string `json:"exception"`
+		Message   string `json:"message"`
+	} `json:"RemoteException"`
+}
+
+// NewHdfsScaler creates a new hdfsScaler, counting the files of an HDFS directory with WebHDFS
+func NewHdfsScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseHdfsMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing hdfs metadata: %s", err))
+	}
+
+	scaler := &hdfsScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, meta.unsafeSsl),
+	}
+
+	if meta.authMode == hdfsAuthModeKerberos {
+		scaler.krbClient, err = newHdfsKerberosClient(meta)
+		if err != nil {
+			return nil, NewPermanentError(fmt.Errorf("error creating hdfs kerberos client: %s", err))
+		}
+		// the SPNEGO client sets a cookie jar on the http client, holding the hadoop.auth token once negotiated
+		spnegoHTTPClient := *scaler.httpClient
+		scaler.spnegoClient = spnego.NewClient(scaler.krbClient, &spnegoHTTPClient, meta.servicePrincipalName)
+	}
+
+	return scaler, nil
+}
+
+func parseHdfsMetadata(config *ScalerConfig) (*hdfsMetadata, error) {
+	meta := hdfsMetadata{
+		targetFileCount: defaultHdfsTargetFileCount,
+		authMode:        hdfsAuthModeSimple,
+	}
+
+	if val, ok := config.TriggerMetadata["namenodeURL"]; ok && val != "" {
+		if _, err := url.ParseRequestURI(val); err != nil {
+			return nil, fmt.Errorf("invalid namenodeURL: %s", err)
+		}
+		meta.namenodeURL = strings.TrimSuffix(val, "/")
+	} else {
+		return nil, fmt.Errorf("no namenodeURL given")
+	}
+
+	if val, ok := config.TriggerMetadata["directory"]; ok && val != "" {
+		if !strings.HasPrefix(val, "/") {
+			return nil, fmt.Errorf("directory must be an absolute path")
+		}
+		meta.directory = val
+	} else {
+		return nil, fmt.Errorf("no directory given")
+	}
+
+	if val, ok := config.TriggerMetadata["glob"]; ok && val != "" {
+		if _, err := path.Match(val, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %s: %s", val, err)
+		}
+		meta.glob = val
+	}
+
+	if val, ok := config.TriggerMetadata["targetFileCount"]; ok && val != "" {
+		targetFileCount, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetFileCount parsing error %s", err.Error())
+		}
+		meta.targetFileCount = targetFileCount
+	}
+
+	if val, ok := config.TriggerMetadata["activationFileCount"]; ok && val != "" {
+		activationFileCount, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationFileCount parsing error %s", err.Error())
+		}
+		meta.activationFileCount = activationFileCount
+	}
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok && val != "" {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("unsafeSsl parsing error %s", err.Error())
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	if val, ok := config.TriggerMetadata["authMode"]; ok && val != "" {
+		meta.authMode = strings.TrimSpace(val)
+	}
+
+	switch meta.authMode {
+	case hdfsAuthModeSimple:
+		// the user is optional, WebHDFS uses the static user of the cluster without it
+		meta.username = config.AuthParams["username"]
+		if meta.username == "" {
+			meta.username = config.TriggerMetadata["username"]
+		}
+	case hdfsAuthModeDelegationToken:
+		if len(config.AuthParams["delegationToken"]) == 0 {
+			return nil, errors.New("no delegationToken given")
+		}
+		meta.delegationToken = config.AuthParams["delegationToken"]
+	case hdfsAuthModeKerberos:
+		if len(config.AuthParams["username"]) == 0 {
+			return nil, errors.New("no username given")
+		}
+		meta.username = config.AuthParams["username"]
+
+		if len(config.AuthParams["realm"]) == 0 {
+			return nil, errors.New("no realm given")
+		}
+		meta.realm = config.AuthParams["realm"]
+
+		if len(config.AuthParams["krb5Conf"]) == 0 {
+			return nil, errors.New("no krb5Conf given")
+		}
+		meta.krb5Conf = config.AuthParams["krb5Conf"]
+
+		switch {
+		case len(config.AuthParams["keytab"]) > 0:
+			keytab, err := base64.StdEncoding.DecodeString(config.AuthParams["keytab"])
+			if err != nil {
+				return nil, fmt.Errorf("keytab must be base64 encoded: %s", err)
+			}
+			meta.keytab = keytab
+		case len(config.AuthParams["password"]) > 0:
+			meta.password = config.AuthParams["password"]
+		default:
+			return nil, errors.New("no keytab or password given")
+		}
+
+		// the SPN is derived from the host of the namenode if not set, HTTP/<host>
+		meta.servicePrincipalName = config.TriggerMetadata["servicePrincipalName"]
+	default:
+		return nil, fmt.Errorf("err incorrect value for authMode is given: %s", meta.authMode)
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+func newHdfsKerberosClient(meta *hdfsMetadata) (*krbclient.Client, error) {
+	cfg, err := krbconfig.NewFromString(meta.krb5Conf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid krb5Conf: %s", err)
+	}
+
+	if meta.keytab == nil {
+		return krbclient.NewWithPassword(meta.username, meta.realm, meta.password, cfg, krbclient.DisablePAFXFAST(true)), nil
+	}
+
+	kt := keytab.New()
+	if err := kt.Unmarshal(meta.keytab); err != nil {
+		return nil, fmt.Errorf("invalid keytab: %s", err)
+	}
+	return krbclient.NewWithKeytab(meta.username, meta.realm, kt, cfg, krbclient.DisablePAFXFAST(true)), nil
+}
+
+// getFileCount returns the number of files directly in the directory, matching the glob if there is one
+func (s *hdfsScaler) getFileCount(ctx context.Context) (int64, error) {
+	query := url.Values{"op": {"LISTSTATUS"}}
+	switch s.metadata.authMode {
+	case hdfsAuthModeSimple:
+		if s.metadata.username != "" {
+			query.Set("user.name", s.metadata.username)
+		}
+	case hdfsAuthModeDelegationToken:
+		query.Set("delegation", s.metadata.delegationToken)
+	}
+	listURL := fmt.Sprintf("%s/webhdfs/v1%s?%s", s.metadata.namenodeURL, (&url.URL{Path: s.metadata.directory}).EscapedPath(), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, listURL, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var resp *http.Response
+	if s.spnegoClient != nil {
+		if err := s.krbClient.AffirmLogin(); err != nil {
+			return 0, err
+		}
+		resp, err = s.spnegoClient.Do(req)
+	} else {
+		resp, err = s.httpClient.Do(req)
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var remoteException webHdfsRemoteException
+		if err := json.Unmarshal(body, &remoteException); err == nil && remoteException.RemoteException.Exception != "" {
+			return 0, fmt.Errorf("webhdfs returned %s: %s", remoteException.RemoteException.Exception, remoteException.RemoteException.Message)
+		}
+		return 0, fmt.Errorf("webhdfs returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var listStatus webHdfsListStatus
+	if err := json.Unmarshal(body, &listStatus); err != nil {
+		return 0, fmt.Errorf("error parsing webhdfs response: %s", err)
+	}
+
+	var count int64
+	for _, status := range listStatus.FileStatuses.FileStatus {
+		if status.Type != "FILE" {
+			continue
+		}
+		if s.metadata.glob != "" {
+			// the glob was validated when parsing the metadata
+			if matched, _ := path.Match(s.metadata.glob, status.PathSuffix); !matched {
+				continue
+			}
+		}
+		count++
+	}
+	return count, nil
+}
+
+// IsActive returns true if there are more files in the directory than activationFileCount
+func (s *hdfsScaler) IsActive(ctx context.Context) (bool, error) {
+	count, err := s.getFileCount(ctx)
+	if err != nil {
+		hdfsLog.Error(err, "error counting hdfs files")
+		return false, err
+	}
+
+	return count > s.metadata.activationFileCount, nil
+}
+
+// Close destroys the kerberos sessions, if any
+func (s *hdfsScaler) Close(context.Context) error {
+	if s.krbClient != nil {
+		s.krbClient.Destroy()
+	}
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *hdfsScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("hdfs-%s", strings.Trim(s.metadata.directory, "/")))),
+		},
+		Target: GetMetricTarget(s.metricType, s.metadata.targetFileCount),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of files in the directory
+func (s *hdfsScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	count, err := s.getFileCount(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error counting hdfs files: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(count))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,191 @@
+package scalers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jcmturner/gokrb5/v8/iana/etypeID"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/stretchr/testify/assert"
+)
+
+const testHdfsKrb5Conf = `[libdefaults]
+  default_realm = EXAMPLE.COM
+[realms]
+  EXAMPLE.COM = {
+    kdc = kdc.example.com:88
+  }
+`
+
+type parseHdfsMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type hdfsMetricIdentifier struct {
+	metadataTestData *parseHdfsMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testHdfsMetadata = []parseHdfsMetadataTestData{
+	// only required properties
+	{map[string]string{"namenodeURL": "http://namenode:9870", "directory": "/ingest"}, map[string]string{}, false},
+	// all properties with a simple user
+	{map[string]string{"namenodeURL": "https://namenode:9871/", "directory": "/data/ingest/", "glob": "*.parquet", "targetFileCount": "10", "activationFileCount": "2",
+		"unsafeSsl": "true", "authMode": "simple", "username": "keda"}, map[string]string{}, false},
+	// delegation token
+	{map[string]string{"namenodeURL": "http://namenode:9870", "directory": "/ingest", "authMode": "delegationToken"}, map[string]string{"delegationToken": "token"}, false},
+	{map[string]string{"namenodeURL": "http://namenode:9870", "directory": "/ingest", "authMode": "delegationToken"}, map[string]string{}, true},
+	// kerberos with password
+	{map[string]string{"namenodeURL": "http://namenode:9870", "directory": "/ingest", "authMode": "kerberos", "servicePrincipalName": "HTTP/namenode"},
+		map[string]string{"username": "keda", "realm": "EXAMPLE.COM", "krb5Conf": testHdfsKrb5Conf, "password": "secret"}, false},
+	// kerberos with invalid keytab
+	{map[string]string{"namenodeURL": "http://namenode:9870", "directory": "/ingest", "authMode": "kerberos"},
+		map[string]string{"username": "keda", "realm": "EXAMPLE.COM", "krb5Conf": testHdfsKrb5Conf, "keytab": "%%%"}, true},
+	// kerberos without credentials
+	{map[string]string{"namenodeURL": "http://namenode:9870", "directory": "/ingest", "authMode": "kerberos"},
+		map[string]string{"username": "keda", "realm": "EXAMPLE.COM", "krb5Conf": testHdfsKrb5Conf}, true},
+	// kerberos without krb5Conf
+	{map[string]string{"namenodeURL": "http://namenode:9870", "directory": "/ingest", "authMode": "kerberos"},
+		map[string]string{"username": "keda", "realm": "EXAMPLE.COM", "password": "secret"}, true},
+	// kerberos without realm
+	{map[string]string{"namenodeURL": "http://namenode:9870", "directory": "/ingest", "authMode": "kerberos"},
+		map[string]string{"username": "keda", "krb5Conf": testHdfsKrb5Conf, "password": "secret"}, true},
+	// kerberos without username
+	{map[string]string{"namenodeURL": "http://namenode:9870", "directory": "/ingest", "authMode": "kerberos"},
+		map[string]string{"realm": "EXAMPLE.COM", "krb5Conf": testHdfsKrb5Conf, "password": "secret"}, true},
+	// invalid authMode
+	{map[string]string{"namenodeURL": "http://namenode:9870", "directory": "/ingest", "authMode": "basic"}, map[string]string{}, true},
+	// missing namenodeURL
+	{map[string]string{"directory": "/ingest"}, map[string]string{}, true},
+	// invalid namenodeURL
+	{map[string]string{"namenodeURL": "namenode", "directory": "/ingest"}, map[string]string{}, true},
+	// missing directory
+	{map[string]string{"namenodeURL": "http://namenode:9870"}, map[string]string{}, true},
+	// relative directory
+	{map[string]string{"namenodeURL": "http://namenode:9870", "directory": "ingest"}, map[string]string{}, true},
+	// invalid glob
+	{map[string]string{"namenodeURL": "http://namenode:9870", "directory": "/ingest", "glob": "[a"}, map[string]string{}, true},
+	// invalid counts
+	{map[string]string{"namenodeURL": "http://namenode:9870", "directory": "/ingest", "targetFileCount": "a"}, map[string]string{}, true},
+	{map[string]string{"namenodeURL": "http://namenode:9870", "directory": "/ingest", "activationFileCount": "a"}, map[string]string{}, true},
+	// invalid unsafeSsl
+	{map[string]string{"namenodeURL": "http://namenode:9870", "directory": "/ingest", "unsafeSsl": "a"}, map[string]string{}, true},
+}
+
+var hdfsMetricIdentifiers = []hdfsMetricIdentifier{
+	{&testHdfsMetadata[0], 0, "s0-hdfs-ingest"},
+	{&testHdfsMetadata[1], 1, "s1-hdfs-data-ingest"},
+}
+
+func TestHdfsParseMetadata(t *testing.T) {
+	for _, testData := range testHdfsMetadata {
+		_, err := parseHdfsMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestHdfsGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range hdfsMetricIdentifiers {
+		meta, err := parseHdfsMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockHdfsScaler := hdfsScaler{metadata: meta}
+
+		metricSpec := mockHdfsScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestHdfsKerberosClientWithKeytab(t *testing.T) {
+	kt := keytab.New()
+	assert.NoError(t, kt.AddEntry("keda", "EXAMPLE.COM", "secret", time.Now(), 1, etypeID.AES256_CTS_HMAC_SHA1_96))
+	ktBytes, err := kt.Marshal()
+	assert.NoError(t, err)
+
+	s, err := NewHdfsScaler(&ScalerConfig{
+		TriggerMetadata: map[string]string{"namenodeURL": "http://namenode:9870", "directory": "/ingest", "authMode": "kerberos"},
+		AuthParams:      map[string]string{"username": "keda", "realm": "EXAMPLE.COM", "krb5Conf": testHdfsKrb5Conf, "keytab": base64.StdEncoding.EncodeToString(ktBytes)},
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, s.(*hdfsScaler).spnegoClient)
+	assert.NoError(t, s.Close(context.Background()))
+
+	_, err = NewHdfsScaler(&ScalerConfig{
+		TriggerMetadata: map[string]string{"namenodeURL": "http://namenode:9870", "directory": "/ingest", "authMode": "kerberos"},
+		AuthParams:      map[string]string{"username": "keda", "realm": "EXAMPLE.COM", "krb5Conf": testHdfsKrb5Conf, "keytab": base64.StdEncoding.EncodeToString([]byte("keytab"))},
+	})
+	assert.Error(t, err)
+}
+
+func TestHdfsGetFileCount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "LISTSTATUS", r.URL.Query().Get("op"))
+		switch r.URL.Path {
+		case "/webhdfs/v1/ingest":
+			assert.Equal(t, "keda", r.URL.Query().Get("user.name"))
+			fmt.Fprint(w, `{"FileStatuses":{"FileStatus":[
+				{"pathSuffix":"a.parquet","type":"FILE"},
+				{"pathSuffix":"b.parquet","type":"FILE"},
+				{"pathSuffix":"c.csv","type":"FILE"},
+				{"pathSuffix":"_tmp","type":"DIRECTORY"}
+			]}}`)
+		case "/webhdfs/v1/secured":
+			assert.Equal(t, "token", r.URL.Query().Get("delegation"))
+			fmt.Fprint(w, `{"FileStatuses":{"FileStatus":[{"pathSuffix":"a.parquet","type":"FILE"}]}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"RemoteException":{"exception":"FileNotFoundException","javaClassName":"java.io.FileNotFoundException","message":"File does not exist"}}`)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		metadata   map[string]string
+		authParams map[string]string
+		count      int64
+		isActive   bool
+		isError    bool
+	}{
+		{map[string]string{"directory": "/ingest", "username": "keda"}, map[string]string{}, 3, true, false},
+		{map[string]string{"directory": "/ingest", "username": "keda", "glob": "*.parquet"}, map[string]string{}, 2, true, false},
+		{map[string]string{"directory": "/ingest", "username": "keda", "glob": "*.parquet", "activationFileCount": "2"}, map[string]string{}, 2, false, false},
+		{map[string]string{"directory": "/secured", "authMode": "delegationToken"}, map[string]string{"delegationToken": "token"}, 1, true, false},
+		{map[string]string{"directory": "/missing"}, map[string]string{}, 0, false, true},
+	}
+
+	for _, testCase := range testCases {
+		testCase.metadata["namenodeURL"] = server.URL
+		meta, err := parseHdfsMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, AuthParams: testCase.authParams})
+		assert.NoError(t, err)
+		s := hdfsScaler{metadata: meta, httpClient: http.DefaultClient}
+
+		count, err := s.getFileCount(context.Background())
+		if testCase.isError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.count, count, "metadata %v", testCase.metadata)
+
+		isActive, err := s.IsActive(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.isActive, isActive, "metadata %v", testCase.metadata)
+	}
+}
@@ -76,12 +76,12 @@ var cloudeyeLog = logf.Log.WithName("huawei_cloudeye_scaler")
 func NewHuaweiCloudeyeScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseHuaweiCloudeyeMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing Cloudeye metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing Cloudeye metadata: %s", err))
 	}
 
 	return &huaweiCloudeyeScaler{
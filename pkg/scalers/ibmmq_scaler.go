@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/metrics/pkg/apis/external_metrics"
 
+	prommetrics "github.com/kedacore/keda/v2/pkg/metrics"
 	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
@@ -63,12 +64,12 @@ type Parameters struct {
 func NewIBMMQScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseIBMMQMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing IBM MQ metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing IBM MQ metadata: %s", err))
 	}
 
 	return &IBMMQScaler{
@@ -180,6 +181,7 @@ func (s *IBMMQScaler) getQueueDepthViaHTTP(ctx context.Context) (int64, error) {
 	}
 	client := kedautil.CreateHTTPClient(s.defaultHTTPTimeout, false)
 	client.Transport = tr
+	prommetrics.InstrumentHTTPClient(client, "ibmmq")
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -0,0 +1,303 @@
+package scalers
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-imap"
+	imapclient "github.com/emersion/go-imap/client"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	defaultImapTargetMessageCount = 10
+	defaultImapMailbox            = "INBOX"
+
+	imapAuthModePassword = "password"
+	imapAuthModeOAuth2   = "oauth2"
+
+	imapTLSEnable   = "enable"
+	imapTLSStartTLS = "starttls"
+	imapTLSDisable  = "disable"
+
+	defaultImapTLSPort = 993
+	defaultImapPort    = 143
+
+	imapXOAuth2 = "XOAUTH2"
+)
+
+var imapLog = logf.Log.WithName("imap_scaler")
+
+type imapScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *imapMetadata
+	timeout    time.Duration
+}
+
+type imapMetadata struct {
+	host                   string
+	port                   int
+	tls                    string
+	unsafeSsl              bool
+	mailbox                string
+	targetMessageCount     int64
+	activationMessageCount int64
+	scalerIndex            int
+
+	// authentication
+	authMode    string
+	username    string
+	password    string
+	accessToken string
+}
+
+// xoauth2Client is the SASL client of the XOAUTH2 mechanism used by Gmail and Office 365,
+// https://developers.google.com/gmail/imap/xoauth2-protocol
+type xoauth2Client struct {
+	username    string
+	accessToken string
+}
+
+func (c *xoauth2Client) Start() (string, []byte, error) {
+	return imapXOAuth2, []byte(fmt.Sprintf("user=%s\x01auth=Bearer %s\x01\x01", c.username, c.accessToken)), nil
+}
+
+func (c *xoauth2Client) Next(challenge []byte) ([]byte, error) {
+	// the server sends the error details as a challenge, answered with an empty response
+	return nil, fmt.Errorf("XOAUTH2 authentication failed: %s", string(challenge))
+}
+
+// NewImapScaler creates a new imapScaler, counting the unread messages of an IMAP mailbox
+func NewImapScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseImapMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing imap metadata: %s", err))
+	}
+
+	return &imapScaler{
+		metricType: metricType,
+		metadata:   meta,
+		timeout:    config.GlobalHTTPTimeout,
+	}, nil
+}
+
+func parseImapMetadata(config *ScalerConfig) (*imapMetadata, error) {
+	meta := imapMetadata{
+		targetMessageCount: defaultImapTargetMessageCount,
+		mailbox:            defaultImapMailbox,
+		tls:                imapTLSEnable,
+		authMode:           imapAuthModePassword,
+	}
+
+	if val, ok := config.TriggerMetadata["host"]; ok && val != "" {
+		meta.host = val
+	} else {
+		return nil, fmt.Errorf("no host given")
+	}
+
+	if val, ok := config.AuthParams["tls"]; ok && val != "" {
+		meta.tls = strings.TrimSpace(val)
+	} else if val, ok := config.TriggerMetadata["tls"]; ok && val != "" {
+		meta.tls = strings.TrimSpace(val)
+	}
+	switch meta.tls {
+	case imapTLSEnable:
+		meta.port = defaultImapTLSPort
+	case imapTLSStartTLS, imapTLSDisable:
+		meta.port = defaultImapPort
+	default:
+		return nil, fmt.Errorf("err incorrect value for tls is given: %s", meta.tls)
+	}
+
+	if val, ok := config.TriggerMetadata["port"]; ok && val != "" {
+		port, err := strconv.Atoi(val)
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("invalid port %s", val)
+		}
+		meta.port = port
+	}
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok && val != "" {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("unsafeSsl parsing error %s", err.Error())
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	if val, ok := config.TriggerMetadata["mailbox"]; ok && val != "" {
+		meta.mailbox = val
+	}
+
+	if val, ok := config.TriggerMetadata["targetMessageCount"]; ok && val != "" {
+		targetMessageCount, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetMessageCount parsing error %s", err.Error())
+		}
+		meta.targetMessageCount = targetMessageCount
+	}
+
+	if val, ok := config.TriggerMetadata["activationMessageCount"]; ok && val != "" {
+		activationMessageCount, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationMessageCount parsing error %s", err.Error())
+		}
+		meta.activationMessageCount = activationMessageCount
+	}
+
+	meta.username = config.AuthParams["username"]
+	if meta.username == "" {
+		meta.username = config.TriggerMetadata["username"]
+	}
+	if meta.username == "" {
+		return nil, errors.New("no username given")
+	}
+
+	if val, ok := config.TriggerMetadata["authMode"]; ok && val != "" {
+		meta.authMode = strings.TrimSpace(val)
+	}
+
+	switch meta.authMode {
+	case imapAuthModePassword:
+		if len(config.AuthParams["password"]) == 0 {
+			return nil, errors.New("no password given")
+		}
+		meta.password = config.AuthParams["password"]
+	case imapAuthModeOAuth2:
+		if len(config.AuthParams["accessToken"]) == 0 {
+			return nil, errors.New("no accessToken given")
+		}
+		meta.accessToken = config.AuthParams["accessToken"]
+	default:
+		return nil, fmt.Errorf("err incorrect value for authMode is given: %s", meta.authMode)
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// connect opens an authenticated IMAP session, the caller logs out once done
+func (s *imapScaler) connect() (*imapclient.Client, error) {
+	addr := net.JoinHostPort(s.metadata.host, strconv.Itoa(s.metadata.port))
+	dialer := &net.Dialer{Timeout: s.timeout}
+	tlsConfig := &tls.Config{ServerName: s.metadata.host, InsecureSkipVerify: s.metadata.unsafeSsl}
+
+	var c *imapclient.Client
+	var err error
+	if s.metadata.tls == imapTLSEnable {
+		c, err = imapclient.DialWithDialerTLS(dialer, addr, tlsConfig)
+	} else {
+		c, err = imapclient.DialWithDialer(dialer, addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.Timeout = s.timeout
+
+	if s.metadata.tls == imapTLSStartTLS {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("error starting tls: %s", err)
+		}
+	}
+
+	switch s.metadata.authMode {
+	case imapAuthModeOAuth2:
+		err = c.Authenticate(&xoauth2Client{username: s.metadata.username, accessToken: s.metadata.accessToken})
+	default:
+		err = c.Login(s.metadata.username, s.metadata.password)
+	}
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("error authenticating: %s", err)
+	}
+	return c, nil
+}
+
+// getUnreadMessageCount returns the number of messages of the mailbox without the \Seen flag
+func (s *imapScaler) getUnreadMessageCount(ctx context.Context) (int64, error) {
+	c, err := s.connect()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := c.Logout(); err != nil {
+			imapLog.V(1).Info("error logging out", "error", err.Error())
+		}
+	}()
+
+	// the IMAP client doesn't take a context, closing the connection stops the pending command when the context is done
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.Close()
+		case <-done:
+		}
+	}()
+
+	status, err := c.Status(s.metadata.mailbox, []imap.StatusItem{imap.StatusUnseen})
+	if err != nil {
+		return 0, fmt.Errorf("error getting the status of mailbox %s: %s", s.metadata.mailbox, err)
+	}
+	return int64(status.Unseen), nil
+}
+
+// IsActive returns true if there are more unread messages than activationMessageCount
+func (s *imapScaler) IsActive(ctx context.Context) (bool, error) {
+	count, err := s.getUnreadMessageCount(ctx)
+	if err != nil {
+		imapLog.Error(err, "error counting unread messages", "host", s.metadata.host, "mailbox", s.metadata.mailbox)
+		return false, err
+	}
+
+	return count > s.metadata.activationMessageCount, nil
+}
+
+// Close does nothing, a session is opened for each query
+func (s *imapScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *imapScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("imap-%s-%s", s.metadata.host, s.metadata.mailbox))),
+		},
+		Target: GetMetricTarget(s.metricType, s.metadata.targetMessageCount),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of unread messages of the mailbox
+func (s *imapScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	count, err := s.getUnreadMessageCount(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error counting unread messages: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(count))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,232 @@
+package scalers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/backend"
+	"github.com/emersion/go-imap/backend/memory"
+	imapserver "github.com/emersion/go-imap/server"
+	"github.com/emersion/go-sasl"
+	"github.com/stretchr/testify/assert"
+)
+
+type parseImapMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type imapMetricIdentifier struct {
+	metadataTestData *parseImapMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testImapMetadata = []parseImapMetadataTestData{
+	// only required properties
+	{map[string]string{"host": "imap.example.com"}, map[string]string{"username": "jobs@example.com", "password": "secret"}, false},
+	// all properties with oauth2
+	{map[string]string{"host": "outlook.office365.com", "port": "1993", "mailbox": "Orders", "targetMessageCount": "20", "activationMessageCount": "2",
+		"unsafeSsl": "true", "authMode": "oauth2", "username": "jobs@example.com"}, map[string]string{"accessToken": "token", "tls": "starttls"}, false},
+	// tls disabled
+	{map[string]string{"host": "imap.example.com", "tls": "disable"}, map[string]string{"username": "jobs", "password": "secret"}, false},
+	// missing host
+	{map[string]string{}, map[string]string{"username": "jobs@example.com", "password": "secret"}, true},
+	// missing username
+	{map[string]string{"host": "imap.example.com"}, map[string]string{"password": "secret"}, true},
+	// missing password
+	{map[string]string{"host": "imap.example.com"}, map[string]string{"username": "jobs@example.com"}, true},
+	// missing accessToken
+	{map[string]string{"host": "imap.example.com", "authMode": "oauth2"}, map[string]string{"username": "jobs@example.com"}, true},
+	// invalid authMode
+	{map[string]string{"host": "imap.example.com", "authMode": "ntlm"}, map[string]string{"username": "jobs@example.com", "password": "secret"}, true},
+	// invalid tls
+	{map[string]string{"host": "imap.example.com", "tls": "true"}, map[string]string{"username": "jobs@example.com", "password": "secret"}, true},
+	// invalid port
+	{map[string]string{"host": "imap.example.com", "port": "a"}, map[string]string{"username": "jobs@example.com", "password": "secret"}, true},
+	// invalid counts
+	{map[string]string{"host": "imap.example.com", "targetMessageCount": "a"}, map[string]string{"username": "jobs@example.com", "password": "secret"}, true},
+	{map[string]string{"host": "imap.example.com", "activationMessageCount": "a"}, map[string]string{"username": "jobs@example.com", "password": "secret"}, true},
+	// invalid unsafeSsl
+	{map[string]string{"host": "imap.example.com", "unsafeSsl": "a"}, map[string]string{"username": "jobs@example.com", "password": "secret"}, true},
+}
+
+var imapMetricIdentifiers = []imapMetricIdentifier{
+	{&testImapMetadata[0], 0, "s0-imap-imap-example-com-INBOX"},
+	{&testImapMetadata[1], 1, "s1-imap-outlook-office365-com-Orders"},
+}
+
+func TestImapParseMetadata(t *testing.T) {
+	for _, testData := range testImapMetadata {
+		_, err := parseImapMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestImapGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range imapMetricIdentifiers {
+		meta, err := parseImapMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockImapScaler := imapScaler{metadata: meta}
+
+		metricSpec := mockImapScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+// testImapUser and testImapMailbox count the unseen messages in the STATUS responses of the memory backend
+type testImapUser struct {
+	backend.User
+}
+
+func (u *testImapUser) GetMailbox(name string) (backend.Mailbox, error) {
+	mailbox, err := u.User.GetMailbox(name)
+	if err != nil {
+		return nil, err
+	}
+	return &testImapMailbox{Mailbox: mailbox}, nil
+}
+
+type testImapMailbox struct {
+	backend.Mailbox
+}
+
+func (m *testImapMailbox) Status(items []imap.StatusItem) (*imap.MailboxStatus, error) {
+	status, err := m.Mailbox.Status(items)
+	if err != nil {
+		return nil, err
+	}
+	unseen, err := m.Mailbox.SearchMessages(false, &imap.SearchCriteria{WithoutFlags: []string{imap.SeenFlag}})
+	if err != nil {
+		return nil, err
+	}
+	status.Unseen = uint32(len(unseen))
+	return status, nil
+}
+
+type testImapBackend struct {
+	*memory.Backend
+}
+
+func (b *testImapBackend) Login(connInfo *imap.ConnInfo, username, password string) (backend.User, error) {
+	user, err := b.Backend.Login(connInfo, username, password)
+	if err != nil {
+		return nil, err
+	}
+	return &testImapUser{User: user}, nil
+}
+
+// testXOAuth2Server accepts the token "token" for the user of the memory backend
+type testXOAuth2Server struct {
+	conn    imapserver.Conn
+	backend *testImapBackend
+}
+
+func (s *testXOAuth2Server) Next(response []byte) ([]byte, bool, error) {
+	parts := strings.Split(string(response), "\x01")
+	if len(parts) < 2 || parts[1] != "auth=Bearer token" {
+		return nil, true, errors.New("invalid token")
+	}
+	user, err := s.backend.Login(s.conn.Info(), strings.TrimPrefix(parts[0], "user="), "password")
+	if err != nil {
+		return nil, true, err
+	}
+	s.conn.Context().State = imap.AuthenticatedState
+	s.conn.Context().User = user
+	return nil, true, nil
+}
+
+// startImapTestServer serves the memory backend, its INBOX holds a seen message and the messages added here
+func startImapTestServer(t *testing.T, unseenMessages int) string {
+	be := &testImapBackend{Backend: memory.New()}
+	user, err := be.Backend.Login(nil, "username", "password")
+	assert.NoError(t, err)
+	inbox, err := user.GetMailbox("INBOX")
+	assert.NoError(t, err)
+	for i := 0; i < unseenMessages; i++ {
+		assert.NoError(t, inbox.CreateMessage(nil, time.Now(), bytes.NewBufferString("Subject: order\r\n\r\norder")))
+	}
+
+	server := imapserver.New(be)
+	server.AllowInsecureAuth = true
+	server.ErrorLog = testImapErrorLog{}
+	server.EnableAuth(imapXOAuth2, func(conn imapserver.Conn) sasl.Server {
+		return &testXOAuth2Server{conn: conn, backend: be}
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(func() { server.Close() })
+
+	return listener.Addr().String()
+}
+
+type testImapErrorLog struct{}
+
+func (testImapErrorLog) Printf(string, ...interface{}) {}
+func (testImapErrorLog) Println(...interface{})        {}
+
+func TestImapGetUnreadMessageCount(t *testing.T) {
+	addr := startImapTestServer(t, 3)
+	host, port, err := net.SplitHostPort(addr)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		metadata   map[string]string
+		authParams map[string]string
+		count      int64
+		isActive   bool
+		isError    bool
+	}{
+		{map[string]string{}, map[string]string{"password": "password"}, 3, true, false},
+		{map[string]string{"activationMessageCount": "3"}, map[string]string{"password": "password"}, 3, false, false},
+		{map[string]string{"authMode": "oauth2"}, map[string]string{"accessToken": "token"}, 3, true, false},
+		// missing mailbox
+		{map[string]string{"mailbox": "Orders"}, map[string]string{"password": "password"}, 0, false, true},
+		// invalid credentials
+		{map[string]string{}, map[string]string{"password": "wrong"}, 0, false, true},
+		{map[string]string{"authMode": "oauth2"}, map[string]string{"accessToken": "expired"}, 0, false, true},
+	}
+
+	for _, testCase := range testCases {
+		testCase.metadata["host"] = host
+		testCase.metadata["port"] = port
+		testCase.metadata["tls"] = "disable"
+		testCase.authParams["username"] = "username"
+		meta, err := parseImapMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, AuthParams: testCase.authParams})
+		assert.NoError(t, err)
+		s := imapScaler{metadata: meta, timeout: 5 * time.Second}
+
+		count, err := s.getUnreadMessageCount(context.Background())
+		if testCase.isError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.count, count, "metadata %v", testCase.metadata)
+
+		isActive, err := s.IsActive(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.isActive, isActive, "metadata %v", testCase.metadata)
+	}
+}
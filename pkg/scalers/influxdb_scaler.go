@@ -39,12 +39,12 @@ var influxDBLog = logf.Log.WithName("influxdb_scaler")
 func NewInfluxDBScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseInfluxDBMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing influxdb metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing influxdb metadata: %s", err))
 	}
 
 	influxDBLog.Info("starting up influxdb client")
@@ -0,0 +1,114 @@
+//go:build integration
+// +build integration
+
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The integration tests run the scalers against real backends started in containers, between the unit tests and
+// the e2e tests: they catch regressions of the client libraries without a Kubernetes cluster.
+// The containers are managed by shelling out to the docker CLI, which must be on the PATH and reach a daemon, instead
+// of adding a dependency on testcontainers-go. They run with: go test -tags integration -run Integration ./pkg/scalers/...
+
+const (
+	integrationStartTimeout = 2 * time.Minute
+	integrationPollInterval = time.Second
+)
+
+// integrationContainer is a container started for an integration test, removed when the test ends
+type integrationContainer struct {
+	id string
+	// hostPort is the port of the host mapped to the port of the container
+	hostPort int
+}
+
+// address returns the host:port the container port is reachable at
+func (c *integrationContainer) address() string {
+	return fmt.Sprintf("localhost:%d", c.hostPort)
+}
+
+// startIntegrationContainer runs the image with its containerPort published on a free port of the host, the
+// placeholder {{hostPort}} in the env and args is replaced by this port for backends advertising their address
+func startIntegrationContainer(t *testing.T, image string, containerPort int, env []string, args ...string) *integrationContainer {
+	t.Helper()
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skip("skipping as docker is not available")
+	}
+
+	hostPort := freeIntegrationPort(t)
+	runArgs := []string{"run", "--detach", "--rm", "--publish", fmt.Sprintf("%d:%d", hostPort, containerPort)}
+	for _, e := range env {
+		runArgs = append(runArgs, "--env", strings.ReplaceAll(e, "{{hostPort}}", strconv.Itoa(hostPort)))
+	}
+	runArgs = append(runArgs, image)
+	for _, arg := range args {
+		runArgs = append(runArgs, strings.ReplaceAll(arg, "{{hostPort}}", strconv.Itoa(hostPort)))
+	}
+
+	out, err := exec.Command("docker", runArgs...).CombinedOutput()
+	require.NoErrorf(t, err, "cannot start %s - %s", image, out)
+	container := &integrationContainer{id: strings.TrimSpace(string(out)), hostPort: hostPort}
+	t.Cleanup(func() {
+		if out, err := exec.Command("docker", "rm", "--force", container.id).CombinedOutput(); err != nil {
+			t.Logf("cannot remove container %s - %s", container.id, out)
+		}
+	})
+	return container
+}
+
+func freeIntegrationPort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// waitForIntegration retries f until it succeeds, the backends accept connections a while after they started
+func waitForIntegration(t *testing.T, what string, f func(ctx context.Context) error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), integrationStartTimeout)
+	defer cancel()
+
+	var err error
+	for {
+		if err = f(ctx); err == nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			require.NoErrorf(t, err, "timed out waiting for %s", what)
+			return
+		case <-time.After(integrationPollInterval):
+		}
+	}
+}
+
+// assertIntegrationMetric asserts the scaler returns the expected metric value and activity
+func assertIntegrationMetric(t *testing.T, scaler Scaler, expected float64) {
+	t.Helper()
+	ctx := context.Background()
+	metricName := scaler.GetMetricSpecForScaling(ctx)[0].External.Metric.Name
+
+	metrics, err := scaler.GetMetrics(ctx, metricName, nil)
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	assert.Equal(t, metricName, metrics[0].MetricName)
+	assert.Equal(t, expected, metrics[0].Value.AsApproximateFloat64())
+
+	isActive, err := scaler.IsActive(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, expected > 0, isActive)
+}
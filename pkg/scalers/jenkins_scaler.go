@@ -0,0 +1,297 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	jenkinsDefaultTargetQueueLength = 1
+	jenkinsQueuePath                = "/queue/api/json?tree=items[buildable,why]"
+	jenkinsCrumbIssuerPath          = "/crumbIssuer/api/json"
+)
+
+// jenkinsWhyLabelPatterns extract the label expression the item waits for from the why of the queue items, e.g.
+// "Waiting for next available executor on ‘linux’" or "There are no nodes with the label ‘linux && docker’"
+var jenkinsWhyLabelPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`‘([^’]+)’`),
+	regexp.MustCompile(`'([^']+)'`),
+	regexp.MustCompile(`executor on (\S+)$`),
+}
+
+type jenkinsScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *jenkinsMetadata
+	httpClient *http.Client
+
+	// crumb sent with the requests once Jenkins rejected one without it, the crumb is bound to the session cookie
+	crumbLock  sync.Mutex
+	crumbField string
+	crumb      string
+}
+
+type jenkinsMetadata struct {
+	jenkinsURL        string
+	label             string
+	targetQueueLength float64
+
+	// auth
+	username string
+	apiToken string
+
+	unsafeSsl   bool
+	scalerIndex int
+}
+
+type jenkinsQueue struct {
+	Items []jenkinsQueueItem `json:"items"`
+}
+
+type jenkinsQueueItem struct {
+	Buildable bool   `json:"buildable"`
+	Why       string `json:"why"`
+}
+
+type jenkinsCrumb struct {
+	Crumb             string `json:"crumb"`
+	CrumbRequestField string `json:"crumbRequestField"`
+}
+
+// jenkinsMetricNameInvalidChars are the characters of the label expressions not allowed in the metric names
+var jenkinsMetricNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9-]+`)
+
+var jenkinsLog = logf.Log.WithName("jenkins_scaler")
+
+// NewJenkinsScaler creates a new jenkinsScaler
+func NewJenkinsScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseJenkinsMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing jenkins metadata: %s", err))
+	}
+
+	httpClient := createHTTPClient(config, config.GlobalHTTPTimeout, meta.unsafeSsl)
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Jar = jar
+
+	return &jenkinsScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: httpClient,
+	}, nil
+}
+
+func parseJenkinsMetadata(config *ScalerConfig) (*jenkinsMetadata, error) {
+	meta := jenkinsMetadata{}
+
+	switch {
+	case config.AuthParams["jenkinsURL"] != "":
+		meta.jenkinsURL = config.AuthParams["jenkinsURL"]
+	case config.TriggerMetadata["jenkinsURL"] != "":
+		meta.jenkinsURL = config.TriggerMetadata["jenkinsURL"]
+	case config.TriggerMetadata["jenkinsURLFromEnv"] != "":
+		meta.jenkinsURL = config.ResolvedEnv[config.TriggerMetadata["jenkinsURLFromEnv"]]
+	default:
+		return nil, errors.New("no jenkinsURL given")
+	}
+	meta.jenkinsURL = strings.TrimSuffix(meta.jenkinsURL, "/")
+
+	meta.label = normalizeJenkinsLabel(config.TriggerMetadata["label"])
+
+	meta.targetQueueLength = jenkinsDefaultTargetQueueLength
+	if val, ok := config.TriggerMetadata["targetQueueLength"]; ok {
+		targetQueueLength, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetQueueLength parsing error %s", err.Error())
+		}
+		if targetQueueLength <= 0 {
+			return nil, errors.New("targetQueueLength must be greater than 0")
+		}
+		meta.targetQueueLength = targetQueueLength
+	}
+
+	meta.username = config.AuthParams["username"]
+	meta.apiToken = config.AuthParams["apiToken"]
+	if (meta.username == "") != (meta.apiToken == "") {
+		return nil, errors.New("username and apiToken must be given together")
+	}
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("unsafeSsl parsing error %s", err.Error())
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// IsActive returns true if there are buildable items waiting for the agents
+func (s *jenkinsScaler) IsActive(ctx context.Context) (bool, error) {
+	items, err := s.getQueueLength(ctx)
+	if err != nil {
+		jenkinsLog.Error(err, "error getting jenkins queue length")
+		return false, err
+	}
+	return items > 0, nil
+}
+
+func (s *jenkinsScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *jenkinsScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	metricName := "jenkins-queue"
+	if s.metadata.label != "" {
+		metricName = fmt.Sprintf("%s-%s", metricName, jenkinsMetricNameInvalidChars.ReplaceAllString(s.metadata.label, "-"))
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(metricName)),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetQueueLength),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of buildable items waiting for the agents
+func (s *jenkinsScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	items, err := s.getQueueLength(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting jenkins queue length: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(items))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+func (s *jenkinsScaler) getQueueLength(ctx context.Context) (int64, error) {
+	var queue jenkinsQueue
+	if err := s.getJSON(ctx, jenkinsQueuePath, &queue); err != nil {
+		return -1, err
+	}
+	return countJenkinsBuildableItems(queue.Items, s.metadata.label), nil
+}
+
+// getJSON requests the Jenkins API, a request rejected for a missing or expired crumb is retried with a new one
+func (s *jenkinsScaler) getJSON(ctx context.Context, path string, v interface{}) error {
+	s.crumbLock.Lock()
+	defer s.crumbLock.Unlock()
+
+	status, body, err := s.doRequest(ctx, path, true)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusForbidden && strings.Contains(strings.ToLower(string(body)), "crumb") {
+		jenkinsLog.V(1).Info("jenkins rejected the request without a valid crumb, requesting a new one")
+		if err := s.refreshCrumb(ctx); err != nil {
+			return err
+		}
+		status, body, err = s.doRequest(ctx, path, true)
+		if err != nil {
+			return err
+		}
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("error requesting jenkins API status: %d, response: %s", status, body)
+	}
+	return json.Unmarshal(body, v)
+}
+
+func (s *jenkinsScaler) refreshCrumb(ctx context.Context) error {
+	s.crumbField, s.crumb = "", ""
+	status, body, err := s.doRequest(ctx, jenkinsCrumbIssuerPath, false)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("error requesting jenkins crumb status: %d, response: %s", status, body)
+	}
+	var crumb jenkinsCrumb
+	if err := json.Unmarshal(body, &crumb); err != nil {
+		return err
+	}
+	s.crumbField, s.crumb = crumb.CrumbRequestField, crumb.Crumb
+	return nil
+}
+
+func (s *jenkinsScaler) doRequest(ctx context.Context, path string, withCrumb bool) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.metadata.jenkinsURL+path, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	if s.metadata.username != "" {
+		req.SetBasicAuth(s.metadata.username, s.metadata.apiToken)
+	}
+	if withCrumb && s.crumb != "" {
+		req.Header.Set(s.crumbField, s.crumb)
+	}
+
+	r, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer r.Body.Close()
+
+	body, err := ioutil.ReadAll(r.Body)
+	return r.StatusCode, body, err
+}
+
+// countJenkinsBuildableItems counts the buildable items waiting for the label expression, all of them if label is empty
+func countJenkinsBuildableItems(items []jenkinsQueueItem, label string) int64 {
+	var count int64
+	for _, item := range items {
+		if !item.Buildable {
+			continue
+		}
+		if label == "" || getJenkinsItemLabel(item.Why) == label {
+			count++
+		}
+	}
+	return count
+}
+
+// getJenkinsItemLabel returns the normalized label expression found in the why of a queue item
+func getJenkinsItemLabel(why string) string {
+	for _, pattern := range jenkinsWhyLabelPatterns {
+		if match := pattern.FindStringSubmatch(why); match != nil {
+			return normalizeJenkinsLabel(match[1])
+		}
+	}
+	return ""
+}
+
+// normalizeJenkinsLabel removes the whitespaces of a label expression so "linux&&docker" matches "linux && docker"
+func normalizeJenkinsLabel(label string) string {
+	return strings.Join(strings.Fields(label), "")
+}
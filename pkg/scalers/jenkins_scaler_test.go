@@ -0,0 +1,129 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseJenkinsMetadataTestData struct {
+	metadata   map[string]string
+	isError    bool
+	authParams map[string]string
+}
+
+type jenkinsMetricIdentifier struct {
+	metadataTestData *parseJenkinsMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testJenkinsResolvedEnv = map[string]string{"JENKINS_URL": "https://jenkins.example.com"}
+
+var testJenkinsMetadata = []parseJenkinsMetadataTestData{
+	// only url
+	{map[string]string{"jenkinsURL": "https://jenkins.example.com/"}, false, map[string]string{}},
+	// all properties
+	{map[string]string{"jenkinsURLFromEnv": "JENKINS_URL", "label": "linux && docker", "targetQueueLength": "2", "unsafeSsl": "true"}, false, map[string]string{"username": "keda", "apiToken": "token"}},
+	// url from auth params
+	{map[string]string{"label": "linux"}, false, map[string]string{"jenkinsURL": "https://jenkins.example.com"}},
+	// missing url
+	{map[string]string{}, true, map[string]string{}},
+	// invalid targetQueueLength
+	{map[string]string{"jenkinsURL": "https://jenkins.example.com", "targetQueueLength": "a"}, true, map[string]string{}},
+	{map[string]string{"jenkinsURL": "https://jenkins.example.com", "targetQueueLength": "0"}, true, map[string]string{}},
+	// invalid unsafeSsl
+	{map[string]string{"jenkinsURL": "https://jenkins.example.com", "unsafeSsl": "a"}, true, map[string]string{}},
+	// username without apiToken
+	{map[string]string{"jenkinsURL": "https://jenkins.example.com"}, true, map[string]string{"username": "keda"}},
+}
+
+var jenkinsMetricIdentifiers = []jenkinsMetricIdentifier{
+	{&testJenkinsMetadata[0], 0, "s0-jenkins-queue"},
+	{&testJenkinsMetadata[1], 1, "s1-jenkins-queue-linux-docker"},
+}
+
+func TestJenkinsParseMetadata(t *testing.T) {
+	for _, testData := range testJenkinsMetadata {
+		_, err := parseJenkinsMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams, ResolvedEnv: testJenkinsResolvedEnv})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestJenkinsGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range jenkinsMetricIdentifiers {
+		meta, err := parseJenkinsMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ResolvedEnv: testJenkinsResolvedEnv, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockJenkinsScaler := jenkinsScaler{metadata: meta}
+
+		metricSpec := mockJenkinsScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestCountJenkinsBuildableItems(t *testing.T) {
+	items := []jenkinsQueueItem{
+		{Buildable: true, Why: "Waiting for next available executor on ‘linux’"},
+		{Buildable: true, Why: "There are no nodes with the label ‘linux&&docker’"},
+		{Buildable: true, Why: "All nodes of label 'linux && docker' are offline"},
+		{Buildable: true, Why: "Waiting for next available executor on windows"},
+		{Buildable: true, Why: "Waiting for next available executor"},
+		{Buildable: false, Why: "Build #2 is already in progress (ETA: 1 min)"},
+	}
+
+	assert.Equal(t, int64(5), countJenkinsBuildableItems(items, ""))
+	assert.Equal(t, int64(1), countJenkinsBuildableItems(items, "linux"))
+	assert.Equal(t, int64(2), countJenkinsBuildableItems(items, normalizeJenkinsLabel("linux && docker")))
+	assert.Equal(t, int64(1), countJenkinsBuildableItems(items, "windows"))
+}
+
+func TestJenkinsGetQueueLengthWithCrumb(t *testing.T) {
+	crumbRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		assert.Equal(t, "keda", username)
+		assert.Equal(t, "token", password)
+		switch r.URL.Path {
+		case "/crumbIssuer/api/json":
+			crumbRequests++
+			http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: "session", Path: "/"})
+			fmt.Fprint(w, `{"crumb":"crumb-value","crumbRequestField":"Jenkins-Crumb"}`)
+		case "/queue/api/json":
+			cookie, err := r.Cookie("JSESSIONID")
+			if r.Header.Get("Jenkins-Crumb") != "crumb-value" || err != nil || cookie.Value != "session" {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, "No valid crumb was included in the request")
+				return
+			}
+			fmt.Fprint(w, `{"items":[{"buildable":true,"why":"Waiting for next available executor on ‘linux’"},{"buildable":true,"why":"Waiting for next available executor on ‘windows’"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	s, err := NewJenkinsScaler(&ScalerConfig{TriggerMetadata: map[string]string{"jenkinsURL": server.URL, "label": "linux"}, AuthParams: map[string]string{"username": "keda", "apiToken": "token"}})
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		length, err := s.(*jenkinsScaler).getQueueLength(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), length)
+	}
+	// the crumb is reused as long as it is accepted
+	assert.Equal(t, 1, crumbRequests)
+}
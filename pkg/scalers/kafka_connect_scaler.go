@@ -0,0 +1,367 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	defaultKafkaConnectTargetValue = 10
+	defaultKafkaConnectTaskStates  = "RUNNING,UNASSIGNED"
+	// defaultKafkaConnectLagMetricName is the records read by the sink tasks and not committed yet, as exported by the
+	// Prometheus JMX exporter of the workers
+	defaultKafkaConnectLagMetricName = "kafka_connect_sink_task_metrics_sink_record_active_count"
+
+	kafkaConnectMetricTasks = "tasks"
+	kafkaConnectMetricLag   = "lag"
+)
+
+var kafkaConnectLog = logf.Log.WithName("kafka_connect_scaler")
+
+type kafkaConnectScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *kafkaConnectMetadata
+	httpClient *http.Client
+}
+
+type kafkaConnectMetadata struct {
+	connectURL            string
+	connector             string
+	metric                string
+	taskStates            map[string]bool
+	metricsURL            string
+	lagMetricName         string
+	targetValue           int64
+	activationTargetValue int64
+	unsafeSsl             bool
+	scalerIndex           int
+
+	// basic authentication
+	username string
+	password string
+
+	// TLS authentication
+	ca   string
+	cert string
+	key  string
+}
+
+// kafkaConnectStatus is the status of a connector returned by /connectors/<name>/status
+type kafkaConnectStatus struct {
+	Name  string `json:"name"`
+	Tasks []struct {
+		ID    int    `json:"id"`
+		State string `json:"state"`
+	} `json:"tasks"`
+}
+
+// NewKafkaConnectScaler creates a new kafkaConnectScaler, reading the tasks of the connectors from the Kafka Connect
+// REST API or the records of the tasks not processed yet from the metrics of the workers
+func NewKafkaConnectScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseKafkaConnectMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing kafka connect metadata: %s", err))
+	}
+
+	httpClient := createHTTPClient(config, config.GlobalHTTPTimeout, meta.unsafeSsl)
+	if meta.cert != "" || meta.ca != "" {
+		tlsConfig, err := kedautil.NewTLSConfig(meta.cert, meta.key, meta.ca)
+		if err != nil {
+			return nil, err
+		}
+		if meta.unsafeSsl {
+			tlsConfig.InsecureSkipVerify = true
+		}
+		httpClient.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	}
+
+	return &kafkaConnectScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: httpClient,
+	}, nil
+}
+
+func parseKafkaConnectMetadata(config *ScalerConfig) (*kafkaConnectMetadata, error) {
+	meta := kafkaConnectMetadata{
+		metric:        kafkaConnectMetricTasks,
+		taskStates:    map[string]bool{},
+		lagMetricName: defaultKafkaConnectLagMetricName,
+		targetValue:   defaultKafkaConnectTargetValue,
+	}
+
+	if val, ok := config.TriggerMetadata["connectURL"]; ok && val != "" {
+		if _, err := url.ParseRequestURI(val); err != nil {
+			return nil, fmt.Errorf("invalid connectURL: %s", err)
+		}
+		meta.connectURL = strings.TrimSuffix(val, "/")
+	} else {
+		return nil, fmt.Errorf("no connectURL given")
+	}
+
+	meta.connector = config.TriggerMetadata["connector"]
+
+	if val, ok := config.TriggerMetadata["metric"]; ok && val != "" {
+		meta.metric = strings.TrimSpace(val)
+	}
+	switch meta.metric {
+	case kafkaConnectMetricTasks:
+		taskStates := defaultKafkaConnectTaskStates
+		if val, ok := config.TriggerMetadata["taskStates"]; ok && val != "" {
+			taskStates = val
+		}
+		for _, state := range strings.Split(taskStates, ",") {
+			state = strings.ToUpper(strings.TrimSpace(state))
+			switch state {
+			case "RUNNING", "UNASSIGNED", "PAUSED", "FAILED", "RESTARTING":
+				meta.taskStates[state] = true
+			default:
+				return nil, fmt.Errorf("err incorrect value for taskStates is given: %s", state)
+			}
+		}
+	case kafkaConnectMetricLag:
+		if val, ok := config.TriggerMetadata["metricsURL"]; ok && val != "" {
+			if _, err := url.ParseRequestURI(val); err != nil {
+				return nil, fmt.Errorf("invalid metricsURL: %s", err)
+			}
+			meta.metricsURL = val
+		} else {
+			return nil, fmt.Errorf("no metricsURL given")
+		}
+		if val, ok := config.TriggerMetadata["lagMetricName"]; ok && val != "" {
+			meta.lagMetricName = val
+		}
+	default:
+		return nil, fmt.Errorf("err incorrect value for metric is given: %s", meta.metric)
+	}
+
+	if val, ok := config.TriggerMetadata["targetValue"]; ok && val != "" {
+		targetValue, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetValue parsing error %s", err.Error())
+		}
+		meta.targetValue = targetValue
+	}
+
+	if val, ok := config.TriggerMetadata["activationTargetValue"]; ok && val != "" {
+		activationTargetValue, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationTargetValue parsing error %s", err.Error())
+		}
+		meta.activationTargetValue = activationTargetValue
+	}
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok && val != "" {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("unsafeSsl parsing error %s", err.Error())
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	meta.username = config.AuthParams["username"]
+	meta.password = config.AuthParams["password"]
+	if meta.username != "" && meta.password == "" {
+		return nil, errors.New("no password given")
+	}
+
+	meta.ca = config.AuthParams["ca"]
+	meta.cert = config.AuthParams["cert"]
+	meta.key = config.AuthParams["key"]
+	if (meta.cert == "") != (meta.key == "") {
+		return nil, errors.New("cert and key must be given together")
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+func (s *kafkaConnectScaler) get(ctx context.Context, endpoint string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.metadata.username != "" {
+		req.SetBasicAuth(s.metadata.username, s.metadata.password)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d: %s", endpoint, resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+// getConnectorStatuses returns the status of the connector, or of all the connectors if none is given
+func (s *kafkaConnectScaler) getConnectorStatuses(ctx context.Context) ([]kafkaConnectStatus, error) {
+	if s.metadata.connector != "" {
+		body, err := s.get(ctx, fmt.Sprintf("%s/connectors/%s/status", s.metadata.connectURL, url.PathEscape(s.metadata.connector)))
+		if err != nil {
+			return nil, err
+		}
+		status := kafkaConnectStatus{}
+		if err := json.Unmarshal(body, &status); err != nil {
+			return nil, fmt.Errorf("error parsing the connector status: %s", err)
+		}
+		return []kafkaConnectStatus{status}, nil
+	}
+
+	body, err := s.get(ctx, fmt.Sprintf("%s/connectors?expand=status", s.metadata.connectURL))
+	if err != nil {
+		return nil, err
+	}
+	connectors := map[string]struct {
+		Status kafkaConnectStatus `json:"status"`
+	}{}
+	if err := json.Unmarshal(body, &connectors); err != nil {
+		return nil, fmt.Errorf("error parsing the connector statuses: %s", err)
+	}
+	statuses := make([]kafkaConnectStatus, 0, len(connectors))
+	for _, connector := range connectors {
+		statuses = append(statuses, connector.Status)
+	}
+	return statuses, nil
+}
+
+// getTasks returns the tasks of the connectors in the taskStates
+func (s *kafkaConnectScaler) getTasks(ctx context.Context) (int64, error) {
+	statuses, err := s.getConnectorStatuses(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var tasks int64
+	for _, status := range statuses {
+		for _, task := range status.Tasks {
+			if s.metadata.taskStates[task.State] {
+				tasks++
+			}
+		}
+	}
+	return tasks, nil
+}
+
+// getLag sums the samples of lagMetricName of the connector, or of all the connectors if none is given
+func (s *kafkaConnectScaler) getLag(ctx context.Context) (int64, error) {
+	body, err := s.get(ctx, s.metadata.metricsURL)
+	if err != nil {
+		return 0, err
+	}
+
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(strings.NewReader(string(body)))
+	if err != nil {
+		return 0, fmt.Errorf("error parsing the metrics: %s", err)
+	}
+	family, ok := families[s.metadata.lagMetricName]
+	if !ok {
+		return 0, fmt.Errorf("metric %s not found", s.metadata.lagMetricName)
+	}
+
+	var lag float64
+	for _, metric := range family.GetMetric() {
+		if s.metadata.connector != "" && !kafkaConnectMetricHasConnector(metric.GetLabel(), s.metadata.connector) {
+			continue
+		}
+		switch {
+		case metric.GetGauge() != nil:
+			lag += metric.GetGauge().GetValue()
+		case metric.GetUntyped() != nil:
+			lag += metric.GetUntyped().GetValue()
+		case metric.GetCounter() != nil:
+			lag += metric.GetCounter().GetValue()
+		}
+	}
+	return int64(lag), nil
+}
+
+func kafkaConnectMetricHasConnector(labelPairs []*dto.LabelPair, connector string) bool {
+	for _, label := range labelPairs {
+		if label.GetName() == "connector" && label.GetValue() == connector {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *kafkaConnectScaler) getValue(ctx context.Context) (int64, error) {
+	if s.metadata.metric == kafkaConnectMetricLag {
+		return s.getLag(ctx)
+	}
+	return s.getTasks(ctx)
+}
+
+// IsActive returns true if the value of the metric is over activationTargetValue
+func (s *kafkaConnectScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		kafkaConnectLog.Error(err, "error getting kafka connect metric")
+		return false, err
+	}
+
+	return value > s.metadata.activationTargetValue, nil
+}
+
+func (s *kafkaConnectScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *kafkaConnectScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	connector := s.metadata.connector
+	if connector == "" {
+		connector = "all"
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("kafka-connect-%s-%s", connector, s.metadata.metric))),
+		},
+		Target: GetMetricTarget(s.metricType, s.metadata.targetValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the tasks or the lag of the connectors
+func (s *kafkaConnectScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting kafka connect metric: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(value))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,156 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testKafkaConnectStatus = `{"name":"sink","connector":{"state":"RUNNING","worker_id":"10.0.0.1:8083"},"tasks":[
+{"id":0,"state":"RUNNING","worker_id":"10.0.0.1:8083"},{"id":1,"state":"UNASSIGNED","worker_id":"10.0.0.2:8083"},
+{"id":2,"state":"FAILED","worker_id":"10.0.0.2:8083","trace":"error"}],"type":"sink"}`
+
+const testKafkaConnectStatuses = `{"sink":{"status":` + testKafkaConnectStatus + `},
+"source":{"status":{"name":"source","connector":{"state":"RUNNING"},"tasks":[{"id":0,"state":"RUNNING"}],"type":"source"}}}`
+
+const testKafkaConnectMetrics = `# HELP kafka_connect_sink_task_metrics_sink_record_active_count records not committed yet
+# TYPE kafka_connect_sink_task_metrics_sink_record_active_count untyped
+kafka_connect_sink_task_metrics_sink_record_active_count{connector="sink",task="0",} 120.0
+kafka_connect_sink_task_metrics_sink_record_active_count{connector="sink",task="1",} 30.0
+kafka_connect_sink_task_metrics_sink_record_active_count{connector="other",task="0",} 7.0
+`
+
+type parseKafkaConnectMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type kafkaConnectMetricIdentifier struct {
+	metadataTestData *parseKafkaConnectMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testKafkaConnectMetadata = []parseKafkaConnectMetadataTestData{
+	// only required properties
+	{map[string]string{"connectURL": "http://connect:8083"}, map[string]string{}, false},
+	// tasks of a connector
+	{map[string]string{"connectURL": "https://connect:8443", "connector": "sink", "taskStates": "running, failed", "targetValue": "2", "activationTargetValue": "1", "unsafeSsl": "true"},
+		map[string]string{"username": "admin", "password": "secret"}, false},
+	// lag of a connector
+	{map[string]string{"connectURL": "http://connect:8083", "connector": "sink", "metric": "lag", "metricsURL": "http://connect:9404/metrics", "lagMetricName": "lag"},
+		map[string]string{}, false},
+	// TLS
+	{map[string]string{"connectURL": "https://connect:8443"}, map[string]string{"ca": "ca", "cert": "cert", "key": "key"}, false},
+	// missing connectURL
+	{map[string]string{}, map[string]string{}, true},
+	// invalid connectURL
+	{map[string]string{"connectURL": "connect"}, map[string]string{}, true},
+	// invalid metric
+	{map[string]string{"connectURL": "http://connect:8083", "metric": "records"}, map[string]string{}, true},
+	// invalid taskStates
+	{map[string]string{"connectURL": "http://connect:8083", "taskStates": "RUNNING,STOPPED"}, map[string]string{}, true},
+	// lag without metricsURL
+	{map[string]string{"connectURL": "http://connect:8083", "metric": "lag"}, map[string]string{}, true},
+	// invalid values
+	{map[string]string{"connectURL": "http://connect:8083", "targetValue": "a"}, map[string]string{}, true},
+	{map[string]string{"connectURL": "http://connect:8083", "activationTargetValue": "a"}, map[string]string{}, true},
+	{map[string]string{"connectURL": "http://connect:8083", "unsafeSsl": "a"}, map[string]string{}, true},
+	// missing password
+	{map[string]string{"connectURL": "http://connect:8083"}, map[string]string{"username": "admin"}, true},
+	// cert without key
+	{map[string]string{"connectURL": "http://connect:8083"}, map[string]string{"cert": "cert"}, true},
+}
+
+var kafkaConnectMetricIdentifiers = []kafkaConnectMetricIdentifier{
+	{&testKafkaConnectMetadata[0], 0, "s0-kafka-connect-all-tasks"},
+	{&testKafkaConnectMetadata[1], 1, "s1-kafka-connect-sink-tasks"},
+	{&testKafkaConnectMetadata[2], 2, "s2-kafka-connect-sink-lag"},
+}
+
+func TestKafkaConnectParseMetadata(t *testing.T) {
+	for _, testData := range testKafkaConnectMetadata {
+		_, err := parseKafkaConnectMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestKafkaConnectGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range kafkaConnectMetricIdentifiers {
+		meta, err := parseKafkaConnectMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockKafkaConnectScaler := kafkaConnectScaler{metadata: meta}
+
+		metricSpec := mockKafkaConnectScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestKafkaConnectGetValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, _ := r.BasicAuth(); username != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/connectors/sink/status":
+			fmt.Fprint(w, testKafkaConnectStatus)
+		case r.URL.Path == "/connectors" && r.URL.Query().Get("expand") == "status":
+			fmt.Fprint(w, testKafkaConnectStatuses)
+		case r.URL.Path == "/metrics":
+			fmt.Fprint(w, testKafkaConnectMetrics)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		metadata map[string]string
+		value    int64
+		isActive bool
+		isError  bool
+	}{
+		{map[string]string{"connectURL": server.URL, "connector": "sink"}, 2, true, false},
+		{map[string]string{"connectURL": server.URL, "connector": "sink", "taskStates": "FAILED"}, 1, true, false},
+		{map[string]string{"connectURL": server.URL}, 3, true, false},
+		{map[string]string{"connectURL": server.URL, "activationTargetValue": "3"}, 3, false, false},
+		{map[string]string{"connectURL": server.URL, "connector": "sink", "metric": "lag", "metricsURL": server.URL + "/metrics"}, 150, true, false},
+		{map[string]string{"connectURL": server.URL, "metric": "lag", "metricsURL": server.URL + "/metrics"}, 157, true, false},
+		{map[string]string{"connectURL": server.URL, "metric": "lag", "metricsURL": server.URL + "/metrics", "lagMetricName": "missing"}, 0, false, true},
+		{map[string]string{"connectURL": server.URL, "connector": "missing"}, 0, false, true},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parseKafkaConnectMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, AuthParams: map[string]string{"username": "admin", "password": "secret"}})
+		assert.NoError(t, err)
+		s := kafkaConnectScaler{metadata: meta, httpClient: http.DefaultClient}
+
+		value, err := s.getValue(context.Background())
+		if testCase.isError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.value, value, "metadata %v", testCase.metadata)
+
+		isActive, err := s.IsActive(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.isActive, isActive, "metadata %v", testCase.metadata)
+	}
+}
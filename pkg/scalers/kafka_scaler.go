@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Shopify/sarama"
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
@@ -14,6 +17,8 @@ import (
 	"k8s.io/metrics/pkg/apis/external_metrics"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/kedacore/keda/v2/pkg/scalers/authentication"
 	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
@@ -22,6 +27,10 @@ type kafkaScaler struct {
 	metadata   kafkaMetadata
 	client     sarama.Client
 	admin      sarama.ClusterAdmin
+	// rateTracker keeps the latest offsets of the partitions between the polls to compute the produce rate
+	rateTracker *kafkaRateTracker
+	// persistentLagTracker keeps the committed offsets of the partitions between the polls to find the persistent lag
+	persistentLagTracker *kafkaPersistentLagTracker
 }
 
 type kafkaMetadata struct {
@@ -33,24 +42,60 @@ type kafkaMetadata struct {
 	allowIdleConsumers bool
 	version            sarama.KafkaVersion
 
+	// topicPattern matches the names of the topics, resolved at each poll
+	topicPattern *regexp.Regexp
+
+	// scaleOn is the metric of the topics: the lag, the lag ratio (the lag divided by the produce rate, the
+	// seconds to consume the lag) or the produce rate (messages per second) over produceRateWindow
+	scaleOn              kafkaScaleOn
+	lagRatioThreshold    float64
+	produceRateThreshold float64
+	produceRateWindow    time.Duration
+
 	// If an invalid offset is found, whether to scale to 1 (false - the default) so consumption can
 	// occur or scale to 0 (true). See discussion in https://github.com/kedacore/keda/issues/2612
 	scaleToZeroOnInvalidOffset bool
 
+	// The lag of the partitions whose committed offset hasn't advanced for persistentLagPolls polls, usually
+	// stuck partitions or stale offsets, is excluded from the metric, not from the activation
+	excludePersistentLag bool
+	persistentLagPolls   int
+	// Whether to limit the replicas to the number of partitions with lag instead of the number of partitions
+	limitToPartitionsWithLag bool
+
 	// SASL
 	saslType kafkaSaslType
 	username string
 	password string
 
+	// OAUTHBEARER, username and password are the client ID and secret
+	oauthTokenEndpointURI string
+	scopes                []string
+	oauthExtensions       map[string]string
+
+	// AWS MSK IAM
+	awsRegion        string
+	awsAuthorization awsAuthorizationMetadata
+
 	// TLS
 	enableTLS bool
 	cert      string
 	key       string
 	ca        string
 
+	// SPIFFE
+	enableSpiffe   bool
+	spiffeServerID string
+
 	scalerIndex int
 }
 
+// kafkaInternalTopics are the internal topics of the brokers, never matched by topicPattern
+var kafkaInternalTopics = map[string]bool{
+	"__consumer_offsets":  true,
+	"__transaction_state": true,
+}
+
 type offsetResetPolicy string
 
 const (
@@ -58,6 +103,14 @@ const (
 	earliest offsetResetPolicy = "earliest"
 )
 
+type kafkaScaleOn string
+
+const (
+	kafkaScaleOnLag         kafkaScaleOn = "lag"
+	kafkaScaleOnLagRatio    kafkaScaleOn = "lagRatio"
+	kafkaScaleOnProduceRate kafkaScaleOn = "produceRate"
+)
+
 type kafkaSaslType string
 
 // supported SASL types
@@ -66,6 +119,8 @@ const (
 	KafkaSASLTypePlaintext   kafkaSaslType = "plaintext"
 	KafkaSASLTypeSCRAMSHA256 kafkaSaslType = "scram_sha256"
 	KafkaSASLTypeSCRAMSHA512 kafkaSaslType = "scram_sha512"
+	KafkaSASLTypeOAuthbearer kafkaSaslType = "oauthbearer"
+	KafkaSASLTypeMskIam      kafkaSaslType = "aws_msk_iam"
 )
 
 const (
@@ -74,32 +129,48 @@ const (
 	defaultKafkaLagThreshold = 10
 	defaultOffsetResetPolicy = latest
 	invalidOffset            = -1
+	// kafkaConsumerProtocolType is the protocol type of the consumer groups using the classic protocol
+	kafkaConsumerProtocolType = "consumer"
+)
+
+const (
+	// defaultKafkaLagRatioThreshold is the seconds to consume the lag at the produce rate
+	defaultKafkaLagRatioThreshold = 60
+	// defaultKafkaProduceRateThreshold is the messages produced per second
+	defaultKafkaProduceRateThreshold = 100
+	defaultKafkaProduceRateWindow    = 5 * time.Minute
+	defaultKafkaPersistentLagPolls   = 1
 )
 
 var kafkaLog = logf.Log.WithName("kafka_scaler")
 
 // NewKafkaScaler creates a new kafkaScaler
-func NewKafkaScaler(config *ScalerConfig) (Scaler, error) {
+func NewKafkaScaler(ctx context.Context, config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	kafkaMetadata, err := parseKafkaMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing kafka metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing kafka metadata: %s", err))
 	}
 
-	client, admin, err := getKafkaClients(kafkaMetadata)
+	client, admin, err := getKafkaClients(ctx, kafkaMetadata)
 	if err != nil {
 		return nil, err
 	}
 
 	return &kafkaScaler{
-		client:     client,
-		admin:      admin,
-		metricType: metricType,
-		metadata:   kafkaMetadata,
+		client:      client,
+		admin:       admin,
+		metricType:  metricType,
+		metadata:    kafkaMetadata,
+		rateTracker: newKafkaRateTracker(kafkaMetadata.produceRateWindow),
+		persistentLagTracker: &kafkaPersistentLagTracker{
+			polls:   kafkaMetadata.persistentLagPolls,
+			offsets: map[string]map[int32]kafkaCommittedOffset{},
+		},
 	}, nil
 }
 
@@ -109,7 +180,8 @@ func parseKafkaAuthParams(config *ScalerConfig, meta *kafkaMetadata) error {
 		val = strings.TrimSpace(val)
 		mode := kafkaSaslType(val)
 
-		if mode == KafkaSASLTypePlaintext || mode == KafkaSASLTypeSCRAMSHA256 || mode == KafkaSASLTypeSCRAMSHA512 {
+		switch mode {
+		case KafkaSASLTypePlaintext, KafkaSASLTypeSCRAMSHA256, KafkaSASLTypeSCRAMSHA512, KafkaSASLTypeOAuthbearer:
 			if config.AuthParams["username"] == "" {
 				return errors.New("no username given")
 			}
@@ -119,10 +191,27 @@ func parseKafkaAuthParams(config *ScalerConfig, meta *kafkaMetadata) error {
 				return errors.New("no password given")
 			}
 			meta.password = strings.TrimSpace(config.AuthParams["password"])
-			meta.saslType = mode
-		} else {
+		case KafkaSASLTypeMskIam:
+			if val, ok := config.TriggerMetadata["awsRegion"]; ok && val != "" {
+				meta.awsRegion = val
+			} else {
+				return errors.New("no awsRegion given")
+			}
+			auth, err := getAwsAuthorization(config.AuthParams, config.TriggerMetadata, config.ResolvedEnv)
+			if err != nil {
+				return err
+			}
+			meta.awsAuthorization = auth
+		default:
 			return fmt.Errorf("err SASL mode %s given", mode)
 		}
+		meta.saslType = mode
+
+		if mode == KafkaSASLTypeOAuthbearer {
+			if err := parseKafkaOAuthbearerParams(config, meta); err != nil {
+				return err
+			}
+		}
 	}
 
 	meta.enableTLS = false
@@ -147,6 +236,43 @@ func parseKafkaAuthParams(config *ScalerConfig, meta *kafkaMetadata) error {
 		}
 	}
 
+	if config.PodIdentity.Provider == kedav1alpha1.PodIdentityProviderSpiffe {
+		if meta.cert != "" {
+			return errors.New("client certificate can't be set together with spiffe pod identity")
+		}
+		meta.enableSpiffe = true
+		meta.spiffeServerID = config.TriggerMetadata[authentication.SpiffeServerIDKey]
+	}
+
+	return nil
+}
+
+// parseKafkaOAuthbearerParams parses the token endpoint of the client credentials grant, the scopes and the
+// extensions of the SASL/OAUTHBEARER mechanism, like the logicalCluster and identityPoolId of Confluent Cloud
+func parseKafkaOAuthbearerParams(config *ScalerConfig, meta *kafkaMetadata) error {
+	if config.AuthParams["oauthTokenEndpointUri"] == "" {
+		return errors.New("no oauth token endpoint uri given")
+	}
+	meta.oauthTokenEndpointURI = strings.TrimSpace(config.AuthParams["oauthTokenEndpointUri"])
+
+	meta.scopes = nil
+	for _, scope := range strings.Split(config.AuthParams["scopes"], ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			meta.scopes = append(meta.scopes, scope)
+		}
+	}
+
+	meta.oauthExtensions = nil
+	if val := config.AuthParams["oauthExtensions"]; val != "" {
+		meta.oauthExtensions = map[string]string{}
+		for _, extension := range strings.Split(val, ",") {
+			keyValue := strings.SplitN(strings.TrimSpace(extension), "=", 2)
+			if len(keyValue) != 2 || keyValue[0] == "" {
+				return fmt.Errorf("err incorrect value for oauthExtensions is given: %s", extension)
+			}
+			meta.oauthExtensions[keyValue[0]] = keyValue[1]
+		}
+	}
 	return nil
 }
 
@@ -177,6 +303,19 @@ func parseKafkaMetadata(config *ScalerConfig) (kafkaMetadata, error) {
 		meta.topic = config.TriggerMetadata["topic"]
 	default:
 		meta.topic = ""
+	}
+
+	if val, ok := config.TriggerMetadata["topicPattern"]; ok && val != "" {
+		if meta.topic != "" {
+			return meta, errors.New("topic and topicPattern can't be both given")
+		}
+		// the pattern matches the whole name, like the pattern subscriptions of the consumers
+		pattern, err := regexp.Compile("^(?:" + val + ")$")
+		if err != nil {
+			return meta, fmt.Errorf("error parsing topicPattern: %s", err)
+		}
+		meta.topicPattern = pattern
+	} else if meta.topic == "" {
 		kafkaLog.V(1).Info(fmt.Sprintf("consumer group %s has no topic specified, "+
 			"will use all topics subscribed by the consumer group for scaling", meta.group))
 	}
@@ -201,6 +340,51 @@ func parseKafkaMetadata(config *ScalerConfig) (kafkaMetadata, error) {
 		meta.lagThreshold = t
 	}
 
+	meta.scaleOn = kafkaScaleOnLag
+	if val, ok := config.TriggerMetadata["scaleOn"]; ok && val != "" {
+		scaleOn := kafkaScaleOn(val)
+		if scaleOn != kafkaScaleOnLag && scaleOn != kafkaScaleOnLagRatio && scaleOn != kafkaScaleOnProduceRate {
+			return meta, fmt.Errorf("err incorrect value for scaleOn is given: %s", val)
+		}
+		meta.scaleOn = scaleOn
+	}
+
+	meta.lagRatioThreshold = defaultKafkaLagRatioThreshold
+	if val, ok := config.TriggerMetadata["lagRatioThreshold"]; ok && val != "" {
+		t, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return meta, fmt.Errorf("error parsing lagRatioThreshold: %s", err)
+		}
+		if t <= 0 {
+			return meta, errors.New("lagRatioThreshold must be greater than 0")
+		}
+		meta.lagRatioThreshold = t
+	}
+
+	meta.produceRateThreshold = defaultKafkaProduceRateThreshold
+	if val, ok := config.TriggerMetadata["produceRateThreshold"]; ok && val != "" {
+		t, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return meta, fmt.Errorf("error parsing produceRateThreshold: %s", err)
+		}
+		if t <= 0 {
+			return meta, errors.New("produceRateThreshold must be greater than 0")
+		}
+		meta.produceRateThreshold = t
+	}
+
+	meta.produceRateWindow = defaultKafkaProduceRateWindow
+	if val, ok := config.TriggerMetadata["produceRateWindowSeconds"]; ok && val != "" {
+		t, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return meta, fmt.Errorf("error parsing produceRateWindowSeconds: %s", err)
+		}
+		if t <= 0 {
+			return meta, errors.New("produceRateWindowSeconds must be greater than 0")
+		}
+		meta.produceRateWindow = time.Duration(t) * time.Second
+	}
+
 	if err := parseKafkaAuthParams(config, &meta); err != nil {
 		return meta, err
 	}
@@ -214,6 +398,39 @@ func parseKafkaMetadata(config *ScalerConfig) (kafkaMetadata, error) {
 		meta.allowIdleConsumers = t
 	}
 
+	meta.excludePersistentLag = false
+	if val, ok := config.TriggerMetadata["excludePersistentLag"]; ok {
+		t, err := strconv.ParseBool(val)
+		if err != nil {
+			return meta, fmt.Errorf("error parsing excludePersistentLag: %s", err)
+		}
+		meta.excludePersistentLag = t
+	}
+
+	meta.persistentLagPolls = defaultKafkaPersistentLagPolls
+	if val, ok := config.TriggerMetadata["persistentLagPolls"]; ok && val != "" {
+		t, err := strconv.Atoi(val)
+		if err != nil {
+			return meta, fmt.Errorf("error parsing persistentLagPolls: %s", err)
+		}
+		if t <= 0 {
+			return meta, errors.New("persistentLagPolls must be greater than 0")
+		}
+		meta.persistentLagPolls = t
+	}
+
+	meta.limitToPartitionsWithLag = false
+	if val, ok := config.TriggerMetadata["limitToPartitionsWithLag"]; ok {
+		t, err := strconv.ParseBool(val)
+		if err != nil {
+			return meta, fmt.Errorf("error parsing limitToPartitionsWithLag: %s", err)
+		}
+		if t && meta.allowIdleConsumers {
+			return meta, errors.New("allowIdleConsumers and limitToPartitionsWithLag can't be both true")
+		}
+		meta.limitToPartitionsWithLag = t
+	}
+
 	meta.scaleToZeroOnInvalidOffset = false
 	if val, ok := config.TriggerMetadata["scaleToZeroOnInvalidOffset"]; ok {
 		t, err := strconv.ParseBool(val)
@@ -247,6 +464,10 @@ func (s *kafkaScaler) IsActive(ctx context.Context) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	if s.metadata.scaleOn != kafkaScaleOnLag {
+		// one more sample of the offsets for the produce rate
+		s.rateTracker.add(time.Now(), producerOffsets)
+	}
 
 	for topic, partitionsOffsets := range producerOffsets {
 		for partitionID := range partitionsOffsets {
@@ -265,7 +486,7 @@ func (s *kafkaScaler) IsActive(ctx context.Context) (bool, error) {
 	return false, nil
 }
 
-func getKafkaClients(metadata kafkaMetadata) (sarama.Client, sarama.ClusterAdmin, error) {
+func getKafkaClients(ctx context.Context, metadata kafkaMetadata) (sarama.Client, sarama.ClusterAdmin, error) {
 	config := sarama.NewConfig()
 	config.Version = metadata.version
 
@@ -284,6 +505,15 @@ func getKafkaClients(metadata kafkaMetadata) (sarama.Client, sarama.ClusterAdmin
 		config.Net.TLS.Config = tlsConfig
 	}
 
+	if metadata.enableSpiffe {
+		config.Net.TLS.Enable = true
+		tlsConfig, err := authentication.NewSpiffeTLSConfig(ctx, metadata.spiffeServerID)
+		if err != nil {
+			return nil, nil, err
+		}
+		config.Net.TLS.Config = tlsConfig
+	}
+
 	if metadata.saslType == KafkaSASLTypePlaintext {
 		config.Net.SASL.Mechanism = sarama.SASLTypePlaintext
 	}
@@ -298,6 +528,18 @@ func getKafkaClients(metadata kafkaMetadata) (sarama.Client, sarama.ClusterAdmin
 		config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
 	}
 
+	if metadata.saslType == KafkaSASLTypeOAuthbearer {
+		config.Net.SASL.Mechanism = sarama.SASLTypeOAuth
+		config.Net.SASL.TokenProvider = newKafkaOAuthBearerTokenProvider(metadata.username, metadata.password, metadata.oauthTokenEndpointURI, metadata.scopes, metadata.oauthExtensions)
+	}
+
+	if metadata.saslType == KafkaSASLTypeMskIam {
+		// MSK serves the IAM access control on TLS only
+		config.Net.TLS.Enable = true
+		config.Net.SASL.Mechanism = sarama.SASLTypeOAuth
+		config.Net.SASL.TokenProvider = newKafkaMSKIAMTokenProvider(metadata.awsRegion, metadata.awsAuthorization)
+	}
+
 	client, err := sarama.NewClient(metadata.bootstrapServers, config)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error creating kafka client: %s", err)
@@ -317,16 +559,41 @@ func getKafkaClients(metadata kafkaMetadata) (sarama.Client, sarama.ClusterAdmin
 func (s *kafkaScaler) getTopicPartitions() (map[string][]int32, error) {
 	var topicsToDescribe = make([]string, 0)
 
+	switch {
+	case s.metadata.topicPattern != nil:
+		topics, err := s.admin.ListTopics()
+		if err != nil {
+			return nil, fmt.Errorf("error listing topics: %s", err)
+		}
+		topicsToDescribe = getTopicsMatchingPattern(topics, s.metadata.topicPattern)
+		if len(topicsToDescribe) == 0 {
+			kafkaLog.V(1).Info(fmt.Sprintf("no topic matching the topicPattern %s", s.metadata.topicPattern))
+			return map[string][]int32{}, nil
+		}
 	// when no topic is specified, query to cg group to fetch all subscribed topics
-	if s.metadata.topic == "" {
+	case s.metadata.topic == "":
 		listCGOffsetResponse, err := s.admin.ListConsumerGroupOffsets(s.metadata.group, nil)
 		if err != nil {
 			return nil, fmt.Errorf("error listing cg offset: %s", err)
 		}
+		topics := map[string]bool{}
 		for topicName := range listCGOffsetResponse.Blocks {
+			topics[topicName] = true
 			topicsToDescribe = append(topicsToDescribe, topicName)
 		}
-	} else {
+
+		// the topics assigned to the members may not have committed offsets yet
+		assignedTopics, err := s.getAssignedTopics()
+		if err != nil {
+			return nil, err
+		}
+		for _, topicName := range assignedTopics {
+			if !topics[topicName] {
+				topics[topicName] = true
+				topicsToDescribe = append(topicsToDescribe, topicName)
+			}
+		}
+	default:
 		topicsToDescribe = []string{s.metadata.topic}
 	}
 
@@ -351,6 +618,67 @@ func (s *kafkaScaler) getTopicPartitions() (map[string][]int32, error) {
 	return topicPartitions, nil
 }
 
+// getTopicsMatchingPattern returns the sorted names of the topics matching the pattern, but the internal topics
+func getTopicsMatchingPattern(topics map[string]sarama.TopicDetail, pattern *regexp.Regexp) []string {
+	matchingTopics := []string{}
+	for topic := range topics {
+		if kafkaInternalTopics[topic] || !pattern.MatchString(topic) {
+			continue
+		}
+		matchingTopics = append(matchingTopics, topic)
+	}
+	sort.Strings(matchingTopics)
+	return matchingTopics
+}
+
+// getAssignedTopics returns the topics assigned to the members of the consumer group.
+// Groups using the consumer protocol of KIP-848 can't be described through DescribeGroups,
+// brokers report them as not found, so only the committed offsets are used for them.
+func (s *kafkaScaler) getAssignedTopics() ([]string, error) {
+	descriptions, err := s.admin.DescribeConsumerGroups([]string{s.metadata.group})
+	if err != nil {
+		return nil, fmt.Errorf("error describing consumer group: %s", err)
+	}
+	if len(descriptions) != 1 {
+		return nil, fmt.Errorf("expected only 1 consumer group description, got %d", len(descriptions))
+	}
+	return getTopicsFromGroupDescription(descriptions[0], s.metadata.group), nil
+}
+
+// getTopicsFromGroupDescription returns the topics assigned to the members of a classic consumer group,
+// nothing is returned for the groups the describe doesn't cover so the committed offsets are used instead
+func getTopicsFromGroupDescription(description *sarama.GroupDescription, group string) []string {
+	switch {
+	case description.Err == sarama.ErrGroupIDNotFound:
+		kafkaLog.V(1).Info(fmt.Sprintf("consumer group %s is not a classic group, it may use the consumer protocol of KIP-848, "+
+			"falling back to the committed offsets", group))
+		return nil
+	case description.Err != sarama.ErrNoError:
+		kafkaLog.V(1).Info(fmt.Sprintf("error describing consumer group %s: %s, falling back to the committed offsets", group, description.Err))
+		return nil
+	case description.ProtocolType != "" && description.ProtocolType != kafkaConsumerProtocolType:
+		kafkaLog.V(1).Info(fmt.Sprintf("consumer group %s uses the %s protocol type, falling back to the committed offsets", group, description.ProtocolType))
+		return nil
+	}
+
+	var topics []string
+	seen := map[string]bool{}
+	for memberID, member := range description.Members {
+		assignment, err := member.GetMemberAssignment()
+		if err != nil || assignment == nil {
+			kafkaLog.V(1).Info(fmt.Sprintf("skipping unreadable assignment of member %s in consumer group %s", memberID, group))
+			continue
+		}
+		for topic := range assignment.Topics {
+			if !seen[topic] {
+				seen[topic] = true
+				topics = append(topics, topic)
+			}
+		}
+	}
+	return topics
+}
+
 func (s *kafkaScaler) getConsumerOffsets(topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error) {
 	offsets, err := s.admin.ListConsumerGroupOffsets(s.metadata.group, topicPartitions)
 	if err != nil {
@@ -412,7 +740,11 @@ func (s *kafkaScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricS
 		Metric: v2beta2.MetricIdentifier{
 			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(metricName)),
 		},
-		Target: GetMetricTarget(s.metricType, s.metadata.lagThreshold),
+	}
+	if s.metadata.scaleOn == kafkaScaleOnLag {
+		externalMetric.Target = GetMetricTarget(s.metricType, s.metadata.lagThreshold)
+	} else {
+		externalMetric.Target = GetMetricTargetMili(s.metricType, s.metadata.threshold())
 	}
 	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: kafkaMetricType}
 	return []v2beta2.MetricSpec{metricSpec}
@@ -468,24 +800,49 @@ func (s *kafkaScaler) GetMetrics(ctx context.Context, metricName string, metricS
 
 	totalLag := int64(0)
 	totalTopicPartitions := int64(0)
+	partitionsWithLag := int64(0)
 
 	for topic, partitionsOffsets := range producerOffsets {
 		for partition := range partitionsOffsets {
 			lag, _ := s.getLagForPartition(topic, partition, consumerOffsets, producerOffsets)
+			if s.metadata.excludePersistentLag {
+				if block := consumerOffsets.GetBlock(topic, partition); block != nil && s.persistentLagTracker.isPersistent(topic, partition, block.Offset, lag) {
+					kafkaLog.V(1).Info(fmt.Sprintf("Group %s has a persistent lag of %d for topic %s and partition %d, excluding it", s.metadata.group, lag, topic, partition))
+					lag = 0
+				}
+			}
 			totalLag += lag
+			if lag > 0 {
+				partitionsWithLag++
+			}
 		}
 		totalTopicPartitions += (int64)(len(partitionsOffsets))
 	}
 	kafkaLog.V(1).Info(fmt.Sprintf("Kafka scaler: Providing metrics based on totalLag %v, topicPartitions %v, threshold %v", totalLag, len(topicPartitions), s.metadata.lagThreshold))
 
-	if !s.metadata.allowIdleConsumers {
-		// don't scale out beyond the number of topicPartitions
-		if (totalLag / s.metadata.lagThreshold) > totalTopicPartitions {
-			totalLag = totalTopicPartitions * s.metadata.lagThreshold
-		}
+	if s.metadata.limitToPartitionsWithLag {
+		// don't scale out beyond the number of partitions with lag
+		totalTopicPartitions = partitionsWithLag
 	}
 
-	metric := GenerateMetricInMili(metricName, float64(totalLag))
+	var metric external_metrics.ExternalMetricValue
+	if s.metadata.scaleOn == kafkaScaleOnLag {
+		if !s.metadata.allowIdleConsumers {
+			// don't scale out beyond the number of topicPartitions
+			if (totalLag / s.metadata.lagThreshold) > totalTopicPartitions {
+				totalLag = totalTopicPartitions * s.metadata.lagThreshold
+			}
+		}
+		metric = GenerateMetricInMili(metricName, float64(totalLag))
+	} else {
+		value := s.getRateMetricValue(time.Now(), totalLag, producerOffsets)
+		threshold := s.metadata.threshold()
+		if !s.metadata.allowIdleConsumers && value/threshold > float64(totalTopicPartitions) {
+			// don't scale out beyond the number of topicPartitions
+			value = float64(totalTopicPartitions) * threshold
+		}
+		metric = GenerateMetricInMili(metricName, value)
+	}
 
 	return append([]external_metrics.ExternalMetricValue{}, metric), nil
 }
@@ -555,3 +912,111 @@ func (s *kafkaScaler) getProducerOffsets(topicPartitions map[string][]int32) (ma
 
 	return topicPartitionsOffsets, nil
 }
+
+// threshold returns the target of the lag ratio or of the produce rate
+func (m *kafkaMetadata) threshold() float64 {
+	if m.scaleOn == kafkaScaleOnProduceRate {
+		return m.produceRateThreshold
+	}
+	return m.lagRatioThreshold
+}
+
+// getRateMetricValue returns the lag ratio or the produce rate of the topics. The produce rate is floored to a
+// message per second in the lag ratio, the lag ratio is the lag until the scaler has polled the offsets twice.
+func (s *kafkaScaler) getRateMetricValue(now time.Time, totalLag int64, producerOffsets map[string]map[int32]int64) float64 {
+	rate, ok := s.rateTracker.add(now, producerOffsets)
+	if ok {
+		kafkaLog.V(1).Info(fmt.Sprintf("Kafka scaler: produce rate %v over %v", rate, s.metadata.produceRateWindow))
+	} else {
+		kafkaLog.V(1).Info(fmt.Sprintf("Kafka scaler: no produce rate for group %s yet, waiting for the next poll", s.metadata.group))
+	}
+
+	if s.metadata.scaleOn == kafkaScaleOnProduceRate {
+		return rate
+	}
+	if rate < 1 {
+		rate = 1
+	}
+	return float64(totalLag) / rate
+}
+
+// kafkaOffsetSample is the latest offsets of the partitions of the topics at a time
+type kafkaOffsetSample struct {
+	time    time.Time
+	offsets map[string]map[int32]int64
+}
+
+// kafkaRateTracker computes the produce rate of the topics from the samples of the latest offsets of their
+// partitions taken at each poll over a window
+type kafkaRateTracker struct {
+	mutex   sync.Mutex
+	window  time.Duration
+	samples []kafkaOffsetSample
+}
+
+func newKafkaRateTracker(window time.Duration) *kafkaRateTracker {
+	return &kafkaRateTracker{window: window}
+}
+
+// add records the latest offsets of the partitions and returns the messages produced per second since the
+// latest sample older than the window, or since the oldest sample if none is older. false is returned without
+// previous sample.
+func (t *kafkaRateTracker) add(now time.Time, offsets map[string]map[int32]int64) (float64, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.samples = append(t.samples, kafkaOffsetSample{time: now, offsets: offsets})
+	windowStart := now.Add(-t.window)
+	for len(t.samples) > 2 && !t.samples[1].time.After(windowStart) {
+		t.samples = t.samples[1:]
+	}
+
+	baseline := t.samples[0]
+	elapsed := now.Sub(baseline.time).Seconds()
+	if len(t.samples) < 2 || elapsed <= 0 {
+		return 0, false
+	}
+
+	// the partitions added since the baseline are skipped, like the offsets going back when a topic is recreated
+	var produced int64
+	for topic, partitionsOffsets := range offsets {
+		for partitionID, offset := range partitionsOffsets {
+			if baselineOffset, found := baseline.offsets[topic][partitionID]; found && offset > baselineOffset {
+				produced += offset - baselineOffset
+			}
+		}
+	}
+	return float64(produced) / elapsed, true
+}
+
+// kafkaCommittedOffset is the committed offset of a partition and the polls it hasn't advanced with lag
+type kafkaCommittedOffset struct {
+	offset         int64
+	unchangedPolls int
+}
+
+// kafkaPersistentLagTracker finds the partitions whose committed offset hasn't advanced for a number of polls
+// while they have lag
+type kafkaPersistentLagTracker struct {
+	mutex   sync.Mutex
+	polls   int
+	offsets map[string]map[int32]kafkaCommittedOffset
+}
+
+// isPersistent records the committed offset of a partition and returns whether its lag is persistent
+func (t *kafkaPersistentLagTracker) isPersistent(topic string, partitionID int32, offset int64, lag int64) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, found := t.offsets[topic]; !found {
+		t.offsets[topic] = map[int32]kafkaCommittedOffset{}
+	}
+	previous, found := t.offsets[topic][partitionID]
+	current := kafkaCommittedOffset{offset: offset}
+	if found && lag > 0 && previous.offset == offset {
+		current.unchangedPolls = previous.unchangedPolls + 1
+	}
+	t.offsets[topic][partitionID] = current
+
+	return current.unchangedPolls >= t.polls
+}
@@ -0,0 +1,83 @@
+//go:build integration
+// +build integration
+
+package scalers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafkaScalerIntegration(t *testing.T) {
+	// KRaft single node cluster, advertising the port of the host so the clients can reach the broker
+	container := startIntegrationContainer(t, "bitnami/kafka:3.2", 9092, []string{
+		"KAFKA_ENABLE_KRAFT=yes",
+		"KAFKA_CFG_PROCESS_ROLES=broker,controller",
+		"KAFKA_CFG_NODE_ID=1",
+		"KAFKA_CFG_CONTROLLER_QUORUM_VOTERS=1@127.0.0.1:9093",
+		"KAFKA_CFG_LISTENERS=PLAINTEXT://:9092,CONTROLLER://:9093",
+		"KAFKA_CFG_ADVERTISED_LISTENERS=PLAINTEXT://localhost:{{hostPort}}",
+		"KAFKA_CFG_CONTROLLER_LISTENER_NAMES=CONTROLLER",
+		"KAFKA_CFG_LISTENER_SECURITY_PROTOCOL_MAP=CONTROLLER:PLAINTEXT,PLAINTEXT:PLAINTEXT",
+		"KAFKA_BROKER_ID=1",
+		"ALLOW_PLAINTEXT_LISTENER=yes",
+	})
+
+	config := sarama.NewConfig()
+	config.Version = sarama.V1_0_0_0
+	config.Producer.Return.Successes = true
+	var admin sarama.ClusterAdmin
+	waitForIntegration(t, "kafka", func(context.Context) error {
+		var err error
+		admin, err = sarama.NewClusterAdmin([]string{container.address()}, config)
+		return err
+	})
+	defer admin.Close()
+	require.NoError(t, admin.CreateTopic("jobs", &sarama.TopicDetail{NumPartitions: 1, ReplicationFactor: 1}, false))
+
+	producer, err := sarama.NewSyncProducer([]string{container.address()}, config)
+	require.NoError(t, err)
+	defer producer.Close()
+	waitForIntegration(t, "kafka topic", func(context.Context) error {
+		_, _, err := producer.SendMessage(&sarama.ProducerMessage{Topic: "jobs", Value: sarama.StringEncoder("job 0")})
+		return err
+	})
+	for i := 1; i < 3; i++ {
+		_, _, err := producer.SendMessage(&sarama.ProducerMessage{Topic: "jobs", Value: sarama.StringEncoder("job")})
+		require.NoError(t, err)
+	}
+
+	ctx := context.Background()
+	scaler, err := NewKafkaScaler(ctx, &ScalerConfig{
+		TriggerMetadata: map[string]string{"bootstrapServers": container.address(), "consumerGroup": "workers", "topic": "jobs",
+			"lagThreshold": "10", "offsetResetPolicy": "earliest"},
+		AuthParams:  map[string]string{},
+		ResolvedEnv: map[string]string{},
+	})
+	require.NoError(t, err)
+	defer scaler.Close(ctx)
+
+	// without committed offset, the lag of the group is all the messages with the earliest policy
+	assertIntegrationMetric(t, scaler, 3)
+
+	offsetManager, err := sarama.NewOffsetManagerFromClient("workers", newIntegrationKafkaClient(t, container.address(), config))
+	require.NoError(t, err)
+	partitionOffsetManager, err := offsetManager.ManagePartition("jobs", 0)
+	require.NoError(t, err)
+	partitionOffsetManager.MarkOffset(3, "")
+	require.NoError(t, partitionOffsetManager.Close())
+	offsetManager.Commit()
+	require.NoError(t, offsetManager.Close())
+
+	assertIntegrationMetric(t, scaler, 0)
+}
+
+func newIntegrationKafkaClient(t *testing.T, address string, config *sarama.Config) sarama.Client {
+	client, err := sarama.NewClient([]string{address}, config)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
@@ -4,6 +4,12 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 )
 
 type parseKafkaMetadataTestData struct {
@@ -72,6 +78,12 @@ var parseKafkaMetadataTestDataset = []parseKafkaMetadataTestData{
 	{map[string]string{"bootstrapServers": "foobar:9092", "consumerGroup": "my-group", "topic": "my-topic", "allowIdleConsumers": "true"}, false, 1, []string{"foobar:9092"}, "my-group", "my-topic", offsetResetPolicy("latest"), true},
 	// success, version supported
 	{map[string]string{"bootstrapServers": "foobar:9092", "consumerGroup": "my-group", "topic": "my-topic", "allowIdleConsumers": "true", "version": "1.0.0"}, false, 1, []string{"foobar:9092"}, "my-group", "my-topic", offsetResetPolicy("latest"), true},
+	// success, topicPattern
+	{map[string]string{"bootstrapServers": "foobar:9092", "consumerGroup": "my-group", "topicPattern": "orders-.*"}, false, 1, []string{"foobar:9092"}, "my-group", "", offsetResetPolicy("latest"), false},
+	// failure, topic and topicPattern
+	{map[string]string{"bootstrapServers": "foobar:9092", "consumerGroup": "my-group", "topic": "my-topic", "topicPattern": "orders-.*"}, true, 1, []string{"foobar:9092"}, "my-group", "my-topic", offsetResetPolicy("latest"), false},
+	// failure, topicPattern malformed
+	{map[string]string{"bootstrapServers": "foobar:9092", "consumerGroup": "my-group", "topicPattern": "orders-(.*"}, true, 1, []string{"foobar:9092"}, "my-group", "", offsetResetPolicy("latest"), false},
 }
 
 var parseKafkaAuthParamsTestDataset = []parseKafkaAuthParamsTestData{
@@ -115,12 +127,23 @@ var parseKafkaAuthParamsTestDataset = []parseKafkaAuthParamsTestData{
 	{map[string]string{"sasl": "plaintext", "username": "admin", "password": "admin", "tls": "enable", "ca": "caaa", "key": "keey"}, true, false},
 	// failure, SASL + TLS, missing key
 	{map[string]string{"sasl": "plaintext", "username": "admin", "password": "admin", "tls": "enable", "ca": "caaa", "cert": "ceert"}, true, false},
+	// success, SASL OAUTHBEARER
+	{map[string]string{"sasl": "oauthbearer", "username": "client", "password": "secret", "oauthTokenEndpointUri": "https://idp.example.com/oauth2/token"}, false, false},
+	// success, SASL OAUTHBEARER + TLS with scopes and extensions
+	{map[string]string{"sasl": "oauthbearer", "username": "client", "password": "secret", "oauthTokenEndpointUri": "https://idp.example.com/oauth2/token", "scopes": "kafka, offline", "oauthExtensions": "logicalCluster=lkc-abc,identityPoolId=pool-1", "tls": "enable"}, false, true},
+	// failure, SASL OAUTHBEARER missing token endpoint
+	{map[string]string{"sasl": "oauthbearer", "username": "client", "password": "secret"}, true, false},
+	// failure, SASL OAUTHBEARER missing client secret
+	{map[string]string{"sasl": "oauthbearer", "username": "client", "oauthTokenEndpointUri": "https://idp.example.com/oauth2/token"}, true, false},
+	// failure, SASL OAUTHBEARER incorrect extensions
+	{map[string]string{"sasl": "oauthbearer", "username": "client", "password": "secret", "oauthTokenEndpointUri": "https://idp.example.com/oauth2/token", "oauthExtensions": "logicalCluster"}, true, false},
 }
 
 var kafkaMetricIdentifiers = []kafkaMetricIdentifier{
 	{&parseKafkaMetadataTestDataset[4], 0, "s0-kafka-my-topic"},
 	{&parseKafkaMetadataTestDataset[4], 1, "s1-kafka-my-topic"},
 	{&parseKafkaMetadataTestDataset[2], 1, "s1-kafka-my-group-topics"},
+	{&parseKafkaMetadataTestDataset[12], 2, "s2-kafka-my-group-topics"},
 }
 
 func TestGetBrokers(t *testing.T) {
@@ -205,13 +228,35 @@ func TestKafkaAuthParams(t *testing.T) {
 	}
 }
 
+func TestKafkaSpiffePodIdentity(t *testing.T) {
+	podIdentity := kedav1alpha1.AuthPodIdentity{Provider: kedav1alpha1.PodIdentityProviderSpiffe}
+	metadata := map[string]string{"bootstrapServers": "broker1:9092", "consumerGroup": "my-group", "topic": "my-topic", "spiffeServerID": "spiffe://example.org/kafka"}
+
+	meta, err := parseKafkaMetadata(&ScalerConfig{TriggerMetadata: metadata, AuthParams: map[string]string{}, PodIdentity: podIdentity})
+	if err != nil {
+		t.Fatal("Expected success but got error", err)
+	}
+	if !meta.enableSpiffe {
+		t.Error("Expected enableSpiffe to be set")
+	}
+	if meta.spiffeServerID != "spiffe://example.org/kafka" {
+		t.Errorf("Expected spiffeServerID to be set but got %s", meta.spiffeServerID)
+	}
+
+	// a static client certificate conflicts with the SVID
+	_, err = parseKafkaMetadata(&ScalerConfig{TriggerMetadata: metadata, AuthParams: map[string]string{"tls": "enable", "cert": "ceert", "key": "keey"}, PodIdentity: podIdentity})
+	if err == nil {
+		t.Error("Expected error but got success")
+	}
+}
+
 func TestKafkaGetMetricSpecForScaling(t *testing.T) {
 	for _, testData := range kafkaMetricIdentifiers {
 		meta, err := parseKafkaMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: validWithAuthParams, ScalerIndex: testData.scalerIndex})
 		if err != nil {
 			t.Fatal("Could not parse metadata:", err)
 		}
-		mockKafkaScaler := kafkaScaler{"", meta, nil, nil}
+		mockKafkaScaler := kafkaScaler{metadata: meta}
 
 		metricSpec := mockKafkaScaler.GetMetricSpecForScaling(context.Background())
 		metricName := metricSpec[0].External.Metric.Name
@@ -220,3 +265,284 @@ func TestKafkaGetMetricSpecForScaling(t *testing.T) {
 		}
 	}
 }
+
+func TestKafkaGetTopicsFromGroupDescription(t *testing.T) {
+	// version 0 assignment of partition 0 of the "orders" topic, without user data
+	assignment := []byte{0, 0, 0, 0, 0, 1, 0, 6, 'o', 'r', 'd', 'e', 'r', 's', 0, 0, 0, 1, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff}
+
+	description := &sarama.GroupDescription{
+		Err:          sarama.ErrNoError,
+		State:        "Stable",
+		ProtocolType: "consumer",
+		Members: map[string]*sarama.GroupMemberDescription{
+			"member-1": {MemberAssignment: assignment},
+			"member-2": {MemberAssignment: assignment},
+		},
+	}
+	if topics := getTopicsFromGroupDescription(description, "my-group"); !reflect.DeepEqual(topics, []string{"orders"}) {
+		t.Errorf("Expected the orders topic but got %v", topics)
+	}
+
+	// groups using the consumer protocol of KIP-848 aren't described as classic groups
+	description = &sarama.GroupDescription{Err: sarama.ErrGroupIDNotFound}
+	if topics := getTopicsFromGroupDescription(description, "my-group"); topics != nil {
+		t.Errorf("Expected no topic but got %v", topics)
+	}
+
+	description = &sarama.GroupDescription{Err: sarama.ErrNoError, ProtocolType: "connect"}
+	if topics := getTopicsFromGroupDescription(description, "my-group"); topics != nil {
+		t.Errorf("Expected no topic but got %v", topics)
+	}
+
+	description = &sarama.GroupDescription{Err: sarama.ErrNoError, State: "Empty", ProtocolType: "consumer"}
+	if topics := getTopicsFromGroupDescription(description, "my-group"); topics != nil {
+		t.Errorf("Expected no topic but got %v", topics)
+	}
+}
+
+func TestKafkaScaleOnMetadata(t *testing.T) {
+	testCases := []struct {
+		metadata map[string]string
+		isError  bool
+	}{
+		{map[string]string{"scaleOn": "lag"}, false},
+		{map[string]string{"scaleOn": "lagRatio", "lagRatioThreshold": "30.5", "produceRateWindowSeconds": "60"}, false},
+		{map[string]string{"scaleOn": "produceRate", "produceRateThreshold": "500"}, false},
+		{map[string]string{"scaleOn": "throughput"}, true},
+		{map[string]string{"scaleOn": "lagRatio", "lagRatioThreshold": "a"}, true},
+		{map[string]string{"scaleOn": "lagRatio", "lagRatioThreshold": "0"}, true},
+		{map[string]string{"scaleOn": "produceRate", "produceRateThreshold": "-1"}, true},
+		{map[string]string{"scaleOn": "produceRate", "produceRateWindowSeconds": "1m"}, true},
+		{map[string]string{"scaleOn": "produceRate", "produceRateWindowSeconds": "0"}, true},
+	}
+
+	for _, testCase := range testCases {
+		metadata := map[string]string{"bootstrapServers": "broker1:9092", "consumerGroup": "my-group", "topic": "my-topic"}
+		for key, value := range testCase.metadata {
+			metadata[key] = value
+		}
+		_, err := parseKafkaMetadata(&ScalerConfig{TriggerMetadata: metadata, AuthParams: validWithoutAuthParams})
+		if err != nil && !testCase.isError {
+			t.Errorf("Expected success for %v but got error %s", testCase.metadata, err)
+		}
+		if testCase.isError && err == nil {
+			t.Errorf("Expected error for %v but got success", testCase.metadata)
+		}
+	}
+
+	metadata := map[string]string{"bootstrapServers": "broker1:9092", "consumerGroup": "my-group", "topic": "my-topic", "scaleOn": "lagRatio", "lagRatioThreshold": "2.5"}
+	meta, err := parseKafkaMetadata(&ScalerConfig{TriggerMetadata: metadata, AuthParams: validWithoutAuthParams})
+	if err != nil {
+		t.Fatal("Could not parse metadata:", err)
+	}
+	mockKafkaScaler := kafkaScaler{metricType: v2beta2.AverageValueMetricType, metadata: meta}
+	metricSpec := mockKafkaScaler.GetMetricSpecForScaling(context.Background())
+	if target := metricSpec[0].External.Target.AverageValue.MilliValue(); target != 2500 {
+		t.Errorf("Expected a target of 2500m but got %dm", target)
+	}
+}
+
+func TestKafkaRateTracker(t *testing.T) {
+	start := time.Now()
+	tracker := newKafkaRateTracker(time.Minute)
+
+	if _, ok := tracker.add(start, map[string]map[int32]int64{"my-topic": {0: 100, 1: 200}}); ok {
+		t.Error("Expected no rate without previous sample")
+	}
+
+	// 300 messages in 30s, the new partition is skipped
+	rate, ok := tracker.add(start.Add(30*time.Second), map[string]map[int32]int64{"my-topic": {0: 250, 1: 350, 2: 1000}})
+	if !ok || rate != 10 {
+		t.Errorf("Expected a rate of 10 but got %v", rate)
+	}
+
+	// 600 messages in 60s
+	rate, _ = tracker.add(start.Add(60*time.Second), map[string]map[int32]int64{"my-topic": {0: 400, 1: 500}})
+	if rate != 10 {
+		t.Errorf("Expected a rate of 10 but got %v", rate)
+	}
+
+	// the first sample is out of the window, 600 messages in 60s since the second one
+	rate, _ = tracker.add(start.Add(90*time.Second), map[string]map[int32]int64{"my-topic": {0: 550, 1: 650}})
+	if rate != 10 {
+		t.Errorf("Expected a rate of 10 but got %v", rate)
+	}
+	if len(tracker.samples) != 3 {
+		t.Errorf("Expected 3 samples but got %d", len(tracker.samples))
+	}
+}
+
+func TestKafkaGetRateMetricValue(t *testing.T) {
+	start := time.Now()
+	testCases := []struct {
+		scaleOn  kafkaScaleOn
+		lag      int64
+		offset   int64
+		expected float64
+	}{
+		// 1200 messages in 60s
+		{kafkaScaleOnProduceRate, 500, 1200, 20},
+		{kafkaScaleOnLagRatio, 500, 1200, 25},
+		// the produce rate is floored to a message per second
+		{kafkaScaleOnLagRatio, 500, 0, 500},
+	}
+
+	for _, testCase := range testCases {
+		s := kafkaScaler{metadata: kafkaMetadata{scaleOn: testCase.scaleOn}, rateTracker: newKafkaRateTracker(time.Minute)}
+		// the lag until the offsets are polled twice
+		if value := s.getRateMetricValue(start, testCase.lag, map[string]map[int32]int64{"my-topic": {0: 0}}); testCase.scaleOn == kafkaScaleOnLagRatio && value != float64(testCase.lag) {
+			t.Errorf("Expected %v but got %v", testCase.lag, value)
+		}
+		value := s.getRateMetricValue(start.Add(time.Minute), testCase.lag, map[string]map[int32]int64{"my-topic": {0: testCase.offset}})
+		if value != testCase.expected {
+			t.Errorf("Expected %v for %s but got %v", testCase.expected, testCase.scaleOn, value)
+		}
+	}
+}
+
+func TestKafkaOAuthbearerAuthParams(t *testing.T) {
+	authParams := map[string]string{"sasl": "oauthbearer", "username": "client", "password": "secret", "oauthTokenEndpointUri": "https://idp.example.com/oauth2/token", "scopes": "kafka, offline", "oauthExtensions": "logicalCluster=lkc-abc,identityPoolId=pool-1"}
+	meta, err := parseKafkaMetadata(&ScalerConfig{TriggerMetadata: validKafkaMetadata, AuthParams: authParams})
+	if err != nil {
+		t.Fatal("Expected success but got error", err)
+	}
+	if meta.saslType != KafkaSASLTypeOAuthbearer || meta.oauthTokenEndpointURI != "https://idp.example.com/oauth2/token" {
+		t.Errorf("Expected the oauthbearer SASL type and token endpoint but got %s and %s", meta.saslType, meta.oauthTokenEndpointURI)
+	}
+	if !reflect.DeepEqual(meta.scopes, []string{"kafka", "offline"}) {
+		t.Errorf("Expected scopes kafka and offline but got %v", meta.scopes)
+	}
+	if !reflect.DeepEqual(meta.oauthExtensions, map[string]string{"logicalCluster": "lkc-abc", "identityPoolId": "pool-1"}) {
+		t.Errorf("Expected the logicalCluster and identityPoolId extensions but got %v", meta.oauthExtensions)
+	}
+}
+
+func TestKafkaMSKIAMAuthParams(t *testing.T) {
+	testCases := []struct {
+		metadata   map[string]string
+		authParams map[string]string
+		isError    bool
+	}{
+		// success, static credentials
+		{map[string]string{"awsRegion": "eu-west-1"}, map[string]string{"sasl": "aws_msk_iam", "awsAccessKeyId": "none", "awsSecretAccessKey": "none"}, false},
+		// success, role
+		{map[string]string{"awsRegion": "eu-west-1"}, map[string]string{"sasl": "aws_msk_iam", "awsRoleArn": "arn:aws:iam::123456789012:role/keda"}, false},
+		// success, role of the operator
+		{map[string]string{"awsRegion": "eu-west-1", "identityOwner": "operator"}, map[string]string{"sasl": "aws_msk_iam"}, false},
+		// failure, missing region
+		{map[string]string{}, map[string]string{"sasl": "aws_msk_iam", "awsRoleArn": "arn:aws:iam::123456789012:role/keda"}, true},
+		// failure, missing credentials
+		{map[string]string{"awsRegion": "eu-west-1"}, map[string]string{"sasl": "aws_msk_iam"}, true},
+	}
+
+	for _, testCase := range testCases {
+		metadata := map[string]string{"bootstrapServers": "b-1.msk.kafka.eu-west-1.amazonaws.com:9098", "consumerGroup": "my-group", "topic": "my-topic"}
+		for key, value := range testCase.metadata {
+			metadata[key] = value
+		}
+		meta, err := parseKafkaMetadata(&ScalerConfig{TriggerMetadata: metadata, AuthParams: testCase.authParams})
+		if err != nil && !testCase.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testCase.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+		if err == nil && (meta.saslType != KafkaSASLTypeMskIam || meta.awsRegion != "eu-west-1") {
+			t.Errorf("Expected the aws_msk_iam SASL type in eu-west-1 but got %s in %s", meta.saslType, meta.awsRegion)
+		}
+	}
+}
+
+func TestKafkaPersistentLagMetadata(t *testing.T) {
+	testCases := []struct {
+		metadata map[string]string
+		isError  bool
+	}{
+		{map[string]string{"excludePersistentLag": "true", "persistentLagPolls": "3", "limitToPartitionsWithLag": "true"}, false},
+		{map[string]string{"excludePersistentLag": "yes"}, true},
+		{map[string]string{"excludePersistentLag": "true", "persistentLagPolls": "a"}, true},
+		{map[string]string{"excludePersistentLag": "true", "persistentLagPolls": "0"}, true},
+		{map[string]string{"limitToPartitionsWithLag": "1"}, false},
+		{map[string]string{"limitToPartitionsWithLag": "no"}, true},
+		{map[string]string{"limitToPartitionsWithLag": "true", "allowIdleConsumers": "true"}, true},
+	}
+
+	for _, testCase := range testCases {
+		metadata := map[string]string{"bootstrapServers": "broker1:9092", "consumerGroup": "my-group", "topic": "my-topic"}
+		for key, value := range testCase.metadata {
+			metadata[key] = value
+		}
+		_, err := parseKafkaMetadata(&ScalerConfig{TriggerMetadata: metadata, AuthParams: validWithoutAuthParams})
+		if err != nil && !testCase.isError {
+			t.Errorf("Expected success for %v but got error %s", testCase.metadata, err)
+		}
+		if testCase.isError && err == nil {
+			t.Errorf("Expected error for %v but got success", testCase.metadata)
+		}
+	}
+}
+
+func TestKafkaPersistentLagTracker(t *testing.T) {
+	tracker := &kafkaPersistentLagTracker{polls: 2, offsets: map[string]map[int32]kafkaCommittedOffset{}}
+
+	polls := []struct {
+		offset     int64
+		lag        int64
+		persistent bool
+	}{
+		{100, 10, false},
+		{100, 10, false},
+		// the offset hasn't advanced for 2 polls
+		{100, 12, true},
+		{100, 15, true},
+		// the offset advanced
+		{105, 10, false},
+		{105, 10, false},
+		// no more lag
+		{105, 0, false},
+		{105, 0, false},
+	}
+
+	for i, poll := range polls {
+		if persistent := tracker.isPersistent("my-topic", 0, poll.offset, poll.lag); persistent != poll.persistent {
+			t.Errorf("Expected persistent %t at poll %d but got %t", poll.persistent, i, persistent)
+		}
+	}
+
+	// the partitions are tracked separately
+	if tracker.isPersistent("my-topic", 1, 100, 10) || tracker.isPersistent("other-topic", 0, 100, 10) {
+		t.Error("Expected no persistent lag for the new partitions")
+	}
+}
+
+func TestKafkaGetTopicsMatchingPattern(t *testing.T) {
+	topics := map[string]sarama.TopicDetail{
+		"orders-eu":           {NumPartitions: 3},
+		"orders-us":           {NumPartitions: 6},
+		"orders-us-retry":     {NumPartitions: 1},
+		"payments":            {NumPartitions: 3},
+		"__consumer_offsets":  {NumPartitions: 50},
+		"__transaction_state": {NumPartitions: 50},
+	}
+
+	testCases := []struct {
+		topicPattern string
+		expected     []string
+	}{
+		{"orders-.*", []string{"orders-eu", "orders-us", "orders-us-retry"}},
+		// the pattern matches the whole name
+		{"orders-(eu|us)", []string{"orders-eu", "orders-us"}},
+		{"orders", []string{}},
+		{".*", []string{"orders-eu", "orders-us", "orders-us-retry", "payments"}},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parseKafkaMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"bootstrapServers": "foobar:9092", "consumerGroup": "my-group", "topicPattern": testCase.topicPattern}})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		if matchingTopics := getTopicsMatchingPattern(topics, meta.topicPattern); !reflect.DeepEqual(matchingTopics, testCase.expected) {
+			t.Errorf("Expected %v for %s but got %v", testCase.expected, testCase.topicPattern, matchingTopics)
+		}
+	}
+}
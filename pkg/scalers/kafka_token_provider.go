@@ -0,0 +1,118 @@
+package scalers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	// kafkaTokenRequestTimeout bounds the token requests, the broker connections wait for them
+	kafkaTokenRequestTimeout = 10 * time.Second
+
+	kafkaMSKIAMService   = "kafka-cluster"
+	kafkaMSKIAMAction    = "kafka-cluster:Connect"
+	kafkaMSKIAMExpiry    = 15 * time.Minute
+	kafkaMSKIAMUserAgent = "keda-kafka-scaler"
+)
+
+// kafkaOAuthBearerTokenProvider gets the access tokens of the SASL/OAUTHBEARER mechanism from an OIDC token
+// endpoint with the client credentials grant, reusing them until they expire
+type kafkaOAuthBearerTokenProvider struct {
+	tokenSource oauth2.TokenSource
+	extensions  map[string]string
+}
+
+func newKafkaOAuthBearerTokenProvider(clientID, clientSecret, tokenEndpointURI string, scopes []string, extensions map[string]string) sarama.AccessTokenProvider {
+	config := clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     tokenEndpointURI,
+		Scopes:       scopes,
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Timeout: kafkaTokenRequestTimeout})
+
+	return &kafkaOAuthBearerTokenProvider{
+		tokenSource: config.TokenSource(ctx),
+		extensions:  extensions,
+	}
+}
+
+// Token returns an access token of the client
+func (p *kafkaOAuthBearerTokenProvider) Token() (*sarama.AccessToken, error) {
+	token, err := p.tokenSource.Token()
+	if err != nil {
+		return nil, fmt.Errorf("error getting oauth token: %s", err)
+	}
+	return &sarama.AccessToken{Token: token.AccessToken, Extensions: p.extensions}, nil
+}
+
+// kafkaMSKIAMTokenProvider generates the tokens of the AWS MSK IAM access control through SASL/OAUTHBEARER, a
+// URL of the kafka-cluster:Connect action presigned with the credentials of the trigger
+type kafkaMSKIAMTokenProvider struct {
+	region      string
+	credentials *credentials.Credentials
+}
+
+func newKafkaMSKIAMTokenProvider(region string, authorization awsAuthorizationMetadata) sarama.AccessTokenProvider {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	}))
+
+	creds := sess.Config.Credentials
+	if authorization.podIdentityOwner {
+		creds = credentials.NewStaticCredentials(authorization.awsAccessKeyID, authorization.awsSecretAccessKey, authorization.awsSessionToken)
+
+		if authorization.awsRoleArn != "" {
+			creds = stscreds.NewCredentials(sess, authorization.awsRoleArn)
+		}
+	}
+
+	return &kafkaMSKIAMTokenProvider{
+		region:      region,
+		credentials: creds,
+	}
+}
+
+// Token returns a token presigned now, valid for 15 minutes
+func (p *kafkaMSKIAMTokenProvider) Token() (*sarama.AccessToken, error) {
+	token, err := p.presign(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("error signing msk iam token: %s", err)
+	}
+	return &sarama.AccessToken{Token: token}, nil
+}
+
+// presign returns the presigned URL of the kafka-cluster:Connect action encoded in unpadded base64url
+func (p *kafkaMSKIAMTokenProvider) presign(signTime time.Time) (string, error) {
+	endpoint := fmt.Sprintf("https://kafka.%s.amazonaws.com/", p.region)
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	query := req.URL.Query()
+	query.Set("Action", kafkaMSKIAMAction)
+	req.URL.RawQuery = query.Encode()
+
+	if _, err := v4.NewSigner(p.credentials).Presign(req, nil, kafkaMSKIAMService, p.region, kafkaMSKIAMExpiry, signTime); err != nil {
+		return "", err
+	}
+
+	// the user agent isn't signed, it's added to the presigned URL
+	query = req.URL.Query()
+	query.Set("User-Agent", kafkaMSKIAMUserAgent)
+	req.URL.RawQuery = query.Encode()
+
+	return base64.RawURLEncoding.EncodeToString([]byte(req.URL.String())), nil
+}
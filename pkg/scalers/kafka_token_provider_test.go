@@ -0,0 +1,71 @@
+package scalers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKafkaOAuthBearerTokenProvider(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		clientID, clientSecret, _ := r.BasicAuth()
+		if err := r.ParseForm(); err != nil || clientID != "client" || clientSecret != "secret" ||
+			r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("scope") != "kafka offline" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token","token_type":"bearer","expires_in":3600}`)
+	}))
+	defer server.Close()
+
+	extensions := map[string]string{"logicalCluster": "lkc-abc"}
+	provider := newKafkaOAuthBearerTokenProvider("client", "secret", server.URL, []string{"kafka", "offline"}, extensions)
+	for i := 0; i < 2; i++ {
+		token, err := provider.Token()
+		assert.NoError(t, err)
+		assert.Equal(t, "token", token.Token)
+		assert.Equal(t, extensions, token.Extensions)
+	}
+	// the token is reused until it expires
+	assert.Equal(t, 1, requests)
+
+	provider = newKafkaOAuthBearerTokenProvider("client", "wrong", server.URL, []string{"kafka", "offline"}, nil)
+	_, err := provider.Token()
+	assert.Error(t, err)
+}
+
+func TestKafkaMSKIAMTokenProvider(t *testing.T) {
+	provider := &kafkaMSKIAMTokenProvider{
+		region:      "eu-west-1",
+		credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+	}
+	signTime := time.Date(2022, time.July, 1, 12, 0, 0, 0, time.UTC)
+
+	token, err := provider.presign(signTime)
+	assert.NoError(t, err)
+
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	assert.NoError(t, err)
+	presignedURL, err := url.Parse(string(decoded))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "kafka.eu-west-1.amazonaws.com", presignedURL.Host)
+	query := presignedURL.Query()
+	assert.Equal(t, "kafka-cluster:Connect", query.Get("Action"))
+	assert.Equal(t, "AWS4-HMAC-SHA256", query.Get("X-Amz-Algorithm"))
+	assert.Equal(t, "AKIDEXAMPLE/20220701/eu-west-1/kafka-cluster/aws4_request", query.Get("X-Amz-Credential"))
+	assert.Equal(t, "20220701T120000Z", query.Get("X-Amz-Date"))
+	assert.Equal(t, "900", query.Get("X-Amz-Expires"))
+	assert.NotEmpty(t, query.Get("X-Amz-Signature"))
+	assert.Equal(t, "keda-kafka-scaler", query.Get("User-Agent"))
+}
@@ -0,0 +1,241 @@
+package scalers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+type kubernetesObjectCountScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *kubernetesObjectCountMetadata
+	kubeClient client.Client
+}
+
+type kubernetesObjectCountMetadata struct {
+	gvk           schema.GroupVersionKind
+	labelSelector labels.Selector
+	fieldSelector fields.Selector
+	namespace     string
+	// namespaces the objects are counted in instead of the namespace of the ScaledObject, all of them if it's ["*"]
+	namespaces []string
+	// the objects are only counted if the JSONPath finds a value, or finds jsonPathValue if it's set
+	jsonPath      string
+	jsonPathValue string
+	value         float64
+	scalerIndex   int
+}
+
+// NewKubernetesObjectCountScaler creates a new kubernetesObjectCountScaler
+func NewKubernetesObjectCountScaler(kubeClient client.Client, config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseKubernetesObjectCountMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing kubernetes object count metadata: %s", err))
+	}
+
+	return &kubernetesObjectCountScaler{
+		metricType: metricType,
+		metadata:   meta,
+		kubeClient: kubeClient,
+	}, nil
+}
+
+func parseKubernetesObjectCountMetadata(config *ScalerConfig) (*kubernetesObjectCountMetadata, error) {
+	meta := &kubernetesObjectCountMetadata{}
+	var err error
+	meta.namespace = config.Namespace
+
+	apiVersion := config.TriggerMetadata["apiVersion"]
+	if apiVersion == "" {
+		return nil, errors.New("no apiVersion given")
+	}
+	groupVersion, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apiVersion %s: %s", apiVersion, err)
+	}
+	kind := config.TriggerMetadata["kind"]
+	if kind == "" {
+		return nil, errors.New("no kind given")
+	}
+	meta.gvk = groupVersion.WithKind(kind)
+
+	meta.labelSelector, err = labels.Parse(config.TriggerMetadata["labelSelector"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid labelSelector: %s", err)
+	}
+	meta.fieldSelector, err = fields.ParseSelector(config.TriggerMetadata["fieldSelector"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid fieldSelector: %s", err)
+	}
+
+	if val, ok := config.TriggerMetadata[namespacesKey]; ok && val != "" {
+		meta.namespaces = splitAndTrim(val)
+		for _, namespace := range meta.namespaces {
+			if namespace == allNamespaces && len(meta.namespaces) > 1 {
+				return nil, fmt.Errorf("namespaces can't list other namespaces with %s", allNamespaces)
+			}
+			if namespace == "" {
+				return nil, fmt.Errorf("invalid namespaces %s", val)
+			}
+		}
+	}
+
+	if val, ok := config.TriggerMetadata["jsonPath"]; ok && val != "" {
+		// accept both .status.phase and {.status.phase}
+		if !strings.Contains(val, "{") {
+			val = fmt.Sprintf("{%s}", val)
+		}
+		if _, err := newObjectCountJSONPath(val); err != nil {
+			return nil, fmt.Errorf("invalid jsonPath: %s", err)
+		}
+		meta.jsonPath = val
+	}
+	if val, ok := config.TriggerMetadata["jsonPathValue"]; ok && val != "" {
+		if meta.jsonPath == "" {
+			return nil, errors.New("jsonPathValue requires jsonPath")
+		}
+		meta.jsonPathValue = val
+	}
+
+	meta.value, err = strconv.ParseFloat(config.TriggerMetadata[valueKey], 64)
+	if err != nil || meta.value <= 0 {
+		return nil, fmt.Errorf("value must be a number greater than 0")
+	}
+	meta.scalerIndex = config.ScalerIndex
+	return meta, nil
+}
+
+// newObjectCountJSONPath parses the JSONPath, a parsed JSONPath can't be shared by concurrent evaluations
+func newObjectCountJSONPath(template string) (*jsonpath.JSONPath, error) {
+	path := jsonpath.New("object-count").AllowMissingKeys(true)
+	if err := path.Parse(template); err != nil {
+		return nil, err
+	}
+	return path, nil
+}
+
+// IsActive returns true if there are objects counted
+func (s *kubernetesObjectCountScaler) IsActive(ctx context.Context) (bool, error) {
+	count, err := s.getObjectCount(ctx)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Close no need for kubernetes object count scaler
+func (s *kubernetesObjectCountScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *kubernetesObjectCountScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("objects-%s", strings.ToLower(s.metadata.gvk.Kind)))),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.value),
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of objects counted
+func (s *kubernetesObjectCountScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	count, err := s.getObjectCount(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error counting kubernetes objects: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(count))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// getObjectCount lists the objects as unstructured, so any kind can be counted as long as KEDA is allowed to list it
+func (s *kubernetesObjectCountScaler) getObjectCount(ctx context.Context) (int64, error) {
+	namespaces := []string{s.metadata.namespace}
+	if len(s.metadata.namespaces) > 0 {
+		namespaces = s.metadata.namespaces
+	}
+
+	var path *jsonpath.JSONPath
+	if s.metadata.jsonPath != "" {
+		var err error
+		if path, err = newObjectCountJSONPath(s.metadata.jsonPath); err != nil {
+			return 0, err
+		}
+	}
+
+	var count int64
+	for _, namespace := range namespaces {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(s.metadata.gvk.GroupVersion().WithKind(s.metadata.gvk.Kind + "List"))
+		listOptions := client.ListOptions{}
+		if !s.metadata.labelSelector.Empty() {
+			listOptions.LabelSelector = s.metadata.labelSelector
+		}
+		if !s.metadata.fieldSelector.Empty() {
+			listOptions.FieldSelector = s.metadata.fieldSelector
+		}
+		// an empty namespace lists the objects of all the namespaces, it's ignored for cluster scoped kinds
+		if namespace != allNamespaces {
+			listOptions.Namespace = namespace
+		}
+
+		if err := s.kubeClient.List(ctx, list, &listOptions); err != nil {
+			return 0, err
+		}
+
+		for _, item := range list.Items {
+			matches, err := s.matchesJSONPath(path, item)
+			if err != nil {
+				return 0, err
+			}
+			if matches {
+				count++
+			}
+		}
+	}
+
+	return count, nil
+}
+
+func (s *kubernetesObjectCountScaler) matchesJSONPath(path *jsonpath.JSONPath, object unstructured.Unstructured) (bool, error) {
+	if path == nil {
+		return true, nil
+	}
+	results, err := path.FindResults(object.UnstructuredContent())
+	if err != nil {
+		return false, fmt.Errorf("error evaluating jsonPath on %s/%s: %s", object.GetNamespace(), object.GetName(), err)
+	}
+	for _, result := range results {
+		for _, value := range result {
+			if !value.IsValid() || !value.CanInterface() || value.Interface() == nil {
+				continue
+			}
+			if s.metadata.jsonPathValue == "" || fmt.Sprint(value.Interface()) == s.metadata.jsonPathValue {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
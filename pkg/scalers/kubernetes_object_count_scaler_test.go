@@ -0,0 +1,127 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type parseKubernetesObjectCountMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+type kubernetesObjectCountMetricIdentifier struct {
+	metadataTestData *parseKubernetesObjectCountMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testKubernetesObjectCountMetadata = []parseKubernetesObjectCountMetadataTestData{
+	// core kind
+	{map[string]string{"apiVersion": "v1", "kind": "PersistentVolumeClaim", "value": "1"}, false},
+	// custom kind with all properties
+	{map[string]string{"apiVersion": "example.com/v1alpha1", "kind": "Job", "value": "5", "labelSelector": "app=demo", "fieldSelector": "metadata.name!=ignored",
+		"namespaces": "team-a,team-b", "jsonPath": ".status.state", "jsonPathValue": "Queued"}, false},
+	// jsonPath with braces
+	{map[string]string{"apiVersion": "v1", "kind": "PersistentVolumeClaim", "value": "1", "jsonPath": "{.status.phase}"}, false},
+	// all namespaces
+	{map[string]string{"apiVersion": "v1", "kind": "PersistentVolumeClaim", "value": "1", "namespaces": "*"}, false},
+	// missing apiVersion
+	{map[string]string{"kind": "PersistentVolumeClaim", "value": "1"}, true},
+	// invalid apiVersion
+	{map[string]string{"apiVersion": "example.com/v1/v2", "kind": "PersistentVolumeClaim", "value": "1"}, true},
+	// missing kind
+	{map[string]string{"apiVersion": "v1", "value": "1"}, true},
+	// invalid selectors
+	{map[string]string{"apiVersion": "v1", "kind": "PersistentVolumeClaim", "value": "1", "labelSelector": "app in ("}, true},
+	{map[string]string{"apiVersion": "v1", "kind": "PersistentVolumeClaim", "value": "1", "fieldSelector": "status.phase"}, true},
+	// invalid namespaces
+	{map[string]string{"apiVersion": "v1", "kind": "PersistentVolumeClaim", "value": "1", "namespaces": "*,team-a"}, true},
+	// invalid jsonPath
+	{map[string]string{"apiVersion": "v1", "kind": "PersistentVolumeClaim", "value": "1", "jsonPath": "{.status[}"}, true},
+	// jsonPathValue without jsonPath
+	{map[string]string{"apiVersion": "v1", "kind": "PersistentVolumeClaim", "value": "1", "jsonPathValue": "Pending"}, true},
+	// invalid value
+	{map[string]string{"apiVersion": "v1", "kind": "PersistentVolumeClaim"}, true},
+	{map[string]string{"apiVersion": "v1", "kind": "PersistentVolumeClaim", "value": "0"}, true},
+}
+
+var kubernetesObjectCountMetricIdentifiers = []kubernetesObjectCountMetricIdentifier{
+	{&testKubernetesObjectCountMetadata[0], 0, "s0-objects-persistentvolumeclaim"},
+	{&testKubernetesObjectCountMetadata[1], 1, "s1-objects-job"},
+}
+
+func TestKubernetesObjectCountParseMetadata(t *testing.T) {
+	for _, testData := range testKubernetesObjectCountMetadata {
+		_, err := parseKubernetesObjectCountMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, Namespace: "test"})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestKubernetesObjectCountGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range kubernetesObjectCountMetricIdentifiers {
+		meta, err := parseKubernetesObjectCountMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, Namespace: "test", ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockKubernetesObjectCountScaler := kubernetesObjectCountScaler{metadata: meta}
+
+		metricSpec := mockKubernetesObjectCountScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func createPVC(name, namespace string, phase v1.PersistentVolumeClaimPhase, labels map[string]string) *v1.PersistentVolumeClaim {
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Status:     v1.PersistentVolumeClaimStatus{Phase: phase},
+	}
+}
+
+func TestKubernetesObjectCountGetObjectCount(t *testing.T) {
+	kubeClient := fake.NewClientBuilder().WithRuntimeObjects(
+		createPVC("data-0", "test", v1.ClaimPending, map[string]string{"app": "demo"}),
+		createPVC("data-1", "test", v1.ClaimBound, map[string]string{"app": "demo"}),
+		createPVC("data-2", "test", v1.ClaimPending, map[string]string{"app": "other"}),
+		createPVC("data-3", "team-a", v1.ClaimPending, map[string]string{"app": "demo"}),
+	).Build()
+
+	testCases := []struct {
+		metadata map[string]string
+		count    int64
+	}{
+		{map[string]string{}, 3},
+		{map[string]string{"labelSelector": "app=demo"}, 2},
+		{map[string]string{"jsonPath": ".status.phase", "jsonPathValue": "Pending"}, 2},
+		{map[string]string{"labelSelector": "app=demo", "jsonPath": ".status.phase", "jsonPathValue": "Pending"}, 1},
+		{map[string]string{"jsonPath": ".status.unknown"}, 0},
+		{map[string]string{"namespaces": "*", "jsonPath": ".status.phase", "jsonPathValue": "Pending"}, 3},
+		{map[string]string{"namespaces": "team-a,team-b"}, 1},
+	}
+
+	for _, testCase := range testCases {
+		metadata := map[string]string{"apiVersion": "v1", "kind": "PersistentVolumeClaim", "value": "1"}
+		for key, value := range testCase.metadata {
+			metadata[key] = value
+		}
+		s, err := NewKubernetesObjectCountScaler(kubeClient, &ScalerConfig{TriggerMetadata: metadata, Namespace: "test"})
+		assert.NoError(t, err)
+
+		count, err := s.(*kubernetesObjectCountScaler).getObjectCount(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.count, count, "metadata %v", testCase.metadata)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
@@ -24,6 +25,13 @@ const (
 	kubernetesWorkloadMetricType = "External"
 	podSelectorKey               = "podSelector"
 	valueKey                     = "value"
+	namespacesKey                = "namespaces"
+	allNamespaces                = "*"
+
+	// crossNamespaceEnv allows the triggers to count the pods of other namespaces than the one of their ScaledObject,
+	// it must be set on both the operator and the metrics server. It's disabled by default as it exposes the pod
+	// counts of the other namespaces, which may belong to other tenants.
+	crossNamespaceEnv = "KEDA_WORKLOAD_SCALER_CROSS_NAMESPACE"
 )
 
 var phasesCountedAsTerminated = []corev1.PodPhase{
@@ -34,6 +42,8 @@ var phasesCountedAsTerminated = []corev1.PodPhase{
 type kubernetesWorkloadMetadata struct {
 	podSelector labels.Selector
 	namespace   string
+	// namespaces the pods are counted in instead of the namespace of the ScaledObject, all of them if it's ["*"]
+	namespaces  []string
 	value       float64
 	scalerIndex int
 }
@@ -42,12 +52,12 @@ type kubernetesWorkloadMetadata struct {
 func NewKubernetesWorkloadScaler(kubeClient client.Client, config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, parseErr := parseWorkloadMetadata(config)
 	if parseErr != nil {
-		return nil, fmt.Errorf("error parsing kubernetes workload metadata: %s", parseErr)
+		return nil, NewPermanentError(fmt.Errorf("error parsing kubernetes workload metadata: %s", parseErr))
 	}
 
 	return &kubernetesWorkloadScaler{
@@ -69,6 +79,29 @@ func parseWorkloadMetadata(config *ScalerConfig) (*kubernetesWorkloadMetadata, e
 	if err != nil || meta.value == 0 {
 		return nil, fmt.Errorf("value must be an integer greater than 0")
 	}
+	if val, ok := config.TriggerMetadata[namespacesKey]; ok && val != "" {
+		crossNamespace, err := kedautil.ResolveOsEnvBool(crossNamespaceEnv, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", crossNamespaceEnv, err)
+		}
+		// a namespace listed twice would count its pods twice
+		seen := map[string]bool{}
+		for _, namespace := range splitAndTrim(val) {
+			if namespace == "" {
+				return nil, fmt.Errorf("invalid namespaces %s", val)
+			}
+			if namespace != meta.namespace && !crossNamespace {
+				return nil, fmt.Errorf("namespaces can't list other namespaces than %s unless %s is enabled", meta.namespace, crossNamespaceEnv)
+			}
+			if !seen[namespace] {
+				seen[namespace] = true
+				meta.namespaces = append(meta.namespaces, namespace)
+			}
+		}
+		if seen[allNamespaces] && len(meta.namespaces) > 1 {
+			return nil, fmt.Errorf("namespaces can't list other namespaces with %s", allNamespaces)
+		}
+	}
 	meta.scalerIndex = config.ScalerIndex
 	return meta, nil
 }
@@ -93,7 +126,7 @@ func (s *kubernetesWorkloadScaler) Close(context.Context) error {
 func (s *kubernetesWorkloadScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
 	externalMetric := &v2beta2.ExternalMetricSource{
 		Metric: v2beta2.MetricIdentifier{
-			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("workload-%s", s.metadata.namespace))),
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("workload-%s", s.metadata.metricNamespace()))),
 		},
 		Target: GetMetricTargetMili(s.metricType, s.metadata.value),
 	}
@@ -113,23 +146,45 @@ func (s *kubernetesWorkloadScaler) GetMetrics(ctx context.Context, metricName st
 	return append([]external_metrics.ExternalMetricValue{}, metric), nil
 }
 
-func (s *kubernetesWorkloadScaler) getMetricValue(ctx context.Context) (int64, error) {
-	podList := &corev1.PodList{}
-	listOptions := client.ListOptions{}
-	listOptions.LabelSelector = s.metadata.podSelector
-	listOptions.Namespace = s.metadata.namespace
-	opts := []client.ListOption{
-		&listOptions,
+// metricNamespace returns the namespaces the pods are counted in, as used in the metric name
+func (m *kubernetesWorkloadMetadata) metricNamespace() string {
+	switch {
+	case len(m.namespaces) == 0:
+		return m.namespace
+	case m.namespaces[0] == allNamespaces:
+		return "all-namespaces"
+	default:
+		return strings.Join(m.namespaces, "-")
 	}
+}
 
-	err := s.kubeClient.List(ctx, podList, opts...)
-	if err != nil {
-		return 0, err
+func (s *kubernetesWorkloadScaler) getMetricValue(ctx context.Context) (int64, error) {
+	namespaces := []string{s.metadata.namespace}
+	if len(s.metadata.namespaces) > 0 {
+		namespaces = s.metadata.namespaces
 	}
 
 	var count int64
-	for _, pod := range podList.Items {
-		count += getCountValue(pod)
+	for _, namespace := range namespaces {
+		podList := &corev1.PodList{}
+		listOptions := client.ListOptions{}
+		listOptions.LabelSelector = s.metadata.podSelector
+		// an empty namespace lists the pods of all the namespaces
+		if namespace != allNamespaces {
+			listOptions.Namespace = namespace
+		}
+		opts := []client.ListOption{
+			&listOptions,
+		}
+
+		err := s.kubeClient.List(ctx, podList, opts...)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, pod := range podList.Items {
+			count += getCountValue(pod)
+		}
 	}
 
 	return count, nil
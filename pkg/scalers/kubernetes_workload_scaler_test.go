@@ -31,9 +31,15 @@ var parseWorkloadMetadataTestDataset = []workloadMetadataTestData{
 	{map[string]string{"value": "a", "podSelector": "app=demo"}, "default", true},
 	{map[string]string{"value": "0", "podSelector": "app=demo"}, "test", true},
 	{map[string]string{"value": "0", "podSelector": "app=demo"}, "default", true},
+	{map[string]string{"value": "1", "podSelector": "app=demo", "namespaces": "team-a, team-b"}, "test", false},
+	{map[string]string{"value": "1", "podSelector": "app=demo", "namespaces": "*"}, "test", false},
+	{map[string]string{"value": "1", "podSelector": "app=demo", "namespaces": "*,team-a"}, "test", true},
+	{map[string]string{"value": "1", "podSelector": "app=demo", "namespaces": "team-a,,team-b"}, "test", true},
+	{map[string]string{"value": "1", "podSelector": "app=demo", "namespaces": "*,*"}, "test", false},
 }
 
 func TestParseWorkloadMetadata(t *testing.T) {
+	t.Setenv(crossNamespaceEnv, "true")
 	for _, testData := range parseWorkloadMetadataTestDataset {
 		_, err := parseWorkloadMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, Namespace: testData.namespace})
 		if err != nil && !testData.isError {
@@ -100,9 +106,14 @@ var getMetricSpecForScalingTestDataset = []workloadGetMetricSpecForScalingTestDa
 	{parseWorkloadMetadataTestDataset[2].metadata, parseWorkloadMetadataTestDataset[2].namespace, 2, "s2-workload-test"},
 	// "podSelector": "app in (demo1, demo2),deploy in (deploy1, deploy2)", "namespace": "test"
 	{parseWorkloadMetadataTestDataset[3].metadata, parseWorkloadMetadataTestDataset[3].namespace, 3, "s3-workload-test"},
+	// "podSelector": "app=demo", "namespaces": "team-a, team-b"
+	{parseWorkloadMetadataTestDataset[12].metadata, parseWorkloadMetadataTestDataset[12].namespace, 4, "s4-workload-team-a-team-b"},
+	// "podSelector": "app=demo", "namespaces": "*"
+	{parseWorkloadMetadataTestDataset[13].metadata, parseWorkloadMetadataTestDataset[13].namespace, 5, "s5-workload-all-namespaces"},
 }
 
 func TestWorkloadGetMetricSpecForScaling(t *testing.T) {
+	t.Setenv(crossNamespaceEnv, "true")
 	for _, testData := range getMetricSpecForScalingTestDataset {
 		s, _ := NewKubernetesWorkloadScaler(
 			fake.NewClientBuilder().Build(),
@@ -194,3 +205,74 @@ func TestWorkloadPhase(t *testing.T) {
 		}
 	}
 }
+
+func TestWorkloadCountAcrossNamespaces(t *testing.T) {
+	t.Setenv(crossNamespaceEnv, "true")
+	list := &v1.PodList{}
+	for i, namespace := range []string{"default", "team-a", "team-a", "team-b", "team-c"} {
+		list.Items = append(list.Items, v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("demo-pod-v%d", i),
+				Namespace: namespace,
+				Labels:    map[string]string{"app": "demo"},
+			},
+		})
+	}
+
+	testCases := map[string]int64{
+		"":                     1,
+		"team-a,team-b":        3,
+		"team-a,team-b,team-a": 3,
+		"*":                    5,
+	}
+	for namespaces, expected := range testCases {
+		s, err := NewKubernetesWorkloadScaler(
+			fake.NewClientBuilder().WithRuntimeObjects(list).Build(),
+			&ScalerConfig{
+				TriggerMetadata:   map[string]string{"podSelector": "app=demo", "value": "1", "namespaces": namespaces},
+				AuthParams:        map[string]string{},
+				GlobalHTTPTimeout: 1000 * time.Millisecond,
+				Namespace:         "default",
+			},
+		)
+		if err != nil {
+			t.Fatalf("Failed to create test scaler -- %v", err)
+		}
+		count, err := s.(*kubernetesWorkloadScaler).getMetricValue(context.TODO())
+		if err != nil {
+			t.Errorf("Failed to count pods -- %v", err)
+		}
+		if count != expected {
+			t.Errorf("Expected %d pods for namespaces '%s' but got %d", expected, namespaces, count)
+		}
+	}
+}
+
+func TestWorkloadCrossNamespaceDisabled(t *testing.T) {
+	testCases := []struct {
+		namespaces string
+		isError    bool
+	}{
+		{"test", false},
+		{"test, test", false},
+		{"team-a", true},
+		{"test,team-a", true},
+		{"*", true},
+	}
+	for _, testCase := range testCases {
+		meta, err := parseWorkloadMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"value": "1", "podSelector": "app=demo", "namespaces": testCase.namespaces}, Namespace: "test"})
+		if testCase.isError {
+			if err == nil {
+				t.Errorf("Expected error for namespaces '%s' but got success", testCase.namespaces)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Expected success for namespaces '%s' but got error %s", testCase.namespaces, err)
+			continue
+		}
+		if len(meta.namespaces) != 1 {
+			t.Errorf("Expected namespaces '%s' to be deduplicated but got %v", testCase.namespaces, meta.namespaces)
+		}
+	}
+}
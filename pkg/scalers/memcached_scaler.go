@@ -0,0 +1,389 @@
+package scalers
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+// memcached binary protocol, see https://github.com/memcached/memcached/wiki/BinaryProtocolRevamped
+const (
+	memcachedRequestMagic  = 0x80
+	memcachedResponseMagic = 0x81
+	memcachedHeaderLength  = 24
+
+	memcachedOpGet      = 0x00
+	memcachedOpStat     = 0x10
+	memcachedOpSASLAuth = 0x21
+
+	memcachedStatusOK          = 0x0000
+	memcachedStatusKeyNotFound = 0x0001
+	memcachedStatusAuthError   = 0x0020
+
+	memcachedDefaultTimeout = 5 * time.Second
+
+	// memcachedMinRateInterval is the minimum time between two samples of a rate, IsActive and GetMetrics
+	// are called back to back and a rate over a few milliseconds is meaningless
+	memcachedMinRateInterval = time.Second
+)
+
+type memcachedScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *memcachedMetadata
+	timeout    time.Duration
+
+	// last sample of the counter when the rate is computed across polls
+	sampleLock sync.Mutex
+	lastSample *memcachedSample
+	lastRate   float64
+}
+
+type memcachedSample struct {
+	value float64
+	at    time.Time
+}
+
+type memcachedMetadata struct {
+	address     string
+	stat        string
+	statGroup   string
+	key         string
+	targetValue float64
+	// rate scales on the per second rate of the counter between two polls instead of its value,
+	// e.g. evictions or get_misses to measure the pressure on the cache
+	rate        bool
+	metricName  string
+	scalerIndex int
+
+	username string
+	password string
+}
+
+type memcachedResponse struct {
+	status uint16
+	key    string
+	value  []byte
+}
+
+var memcachedLog = logf.Log.WithName("memcached_scaler")
+
+// NewMemcachedScaler creates a new memcached scaler
+func NewMemcachedScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseMemcachedMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing memcached metadata: %s", err))
+	}
+
+	timeout := config.GlobalHTTPTimeout
+	if timeout <= 0 {
+		timeout = memcachedDefaultTimeout
+	}
+
+	return &memcachedScaler{
+		metricType: metricType,
+		metadata:   meta,
+		timeout:    timeout,
+	}, nil
+}
+
+func parseMemcachedMetadata(config *ScalerConfig) (*memcachedMetadata, error) {
+	meta := memcachedMetadata{}
+
+	address, err := GetFromAuthOrMeta(config, "address")
+	if err != nil {
+		return nil, err
+	}
+	meta.address = address
+
+	meta.stat = config.TriggerMetadata["stat"]
+	meta.key = config.TriggerMetadata["key"]
+	switch {
+	case meta.stat == "" && meta.key == "":
+		return nil, errors.New("no stat or key given")
+	case meta.stat != "" && meta.key != "":
+		return nil, errors.New("stat and key are mutually exclusive")
+	}
+	meta.statGroup = config.TriggerMetadata["statGroup"]
+	if meta.statGroup != "" && meta.stat == "" {
+		return nil, errors.New("statGroup can only be used with stat")
+	}
+
+	if val, ok := config.TriggerMetadata["targetValue"]; ok {
+		targetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetValue parsing error %s", err.Error())
+		}
+		meta.targetValue = targetValue
+	} else {
+		return nil, errors.New("no targetValue given")
+	}
+
+	if val, ok := config.TriggerMetadata["rate"]; ok {
+		rate, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("rate parsing error %s", err.Error())
+		}
+		meta.rate = rate
+	}
+
+	meta.username = config.AuthParams["username"]
+	if config.AuthParams["password"] != "" {
+		meta.password = config.AuthParams["password"]
+	} else if config.TriggerMetadata["passwordFromEnv"] != "" {
+		meta.password = config.ResolvedEnv[config.TriggerMetadata["passwordFromEnv"]]
+	}
+	if meta.username != "" && meta.password == "" {
+		return nil, errors.New("no password given")
+	}
+
+	if meta.stat != "" {
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("memcached-%s", meta.stat))
+	} else {
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("memcached-%s", meta.key))
+	}
+	if meta.rate {
+		meta.metricName = fmt.Sprintf("%s-rate", meta.metricName)
+	}
+	meta.scalerIndex = config.ScalerIndex
+
+	return &meta, nil
+}
+
+// Close does nothing in case of memcachedScaler, a connection is opened for each read
+func (s *memcachedScaler) Close(context.Context) error {
+	return nil
+}
+
+// IsActive returns true if the stat, the counter or their rate is greater than zero
+func (s *memcachedScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getMetricValue(ctx)
+	if err != nil {
+		memcachedLog.Error(err, "error inspecting memcached")
+		return false, err
+	}
+	return value > 0, nil
+}
+
+// getMetricValue returns the value read from memcached, or its rate since the previous poll when rate is set
+func (s *memcachedScaler) getMetricValue(ctx context.Context) (float64, error) {
+	value, err := s.getValue(ctx)
+	if err != nil || !s.metadata.rate {
+		return value, err
+	}
+	return s.updateRate(value, time.Now()), nil
+}
+
+// updateRate records the sample and returns the per second rate since the previous one, the first poll
+// has no rate yet and a counter going backwards, after a restart of memcached, starts over from zero
+func (s *memcachedScaler) updateRate(value float64, now time.Time) float64 {
+	s.sampleLock.Lock()
+	defer s.sampleLock.Unlock()
+
+	if s.lastSample == nil {
+		s.lastSample = &memcachedSample{value: value, at: now}
+		return 0
+	}
+
+	elapsed := now.Sub(s.lastSample.at)
+	if elapsed < memcachedMinRateInterval {
+		return s.lastRate
+	}
+
+	s.lastRate = 0
+	if value >= s.lastSample.value {
+		s.lastRate = (value - s.lastSample.value) / elapsed.Seconds()
+	}
+	s.lastSample = &memcachedSample{value: value, at: now}
+	return s.lastRate
+}
+
+// getValue authenticates if needed, then reads the stat or the counter key in a single round trip
+func (s *memcachedScaler) getValue(ctx context.Context) (float64, error) {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.metadata.address)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		return 0, err
+	}
+	reader := bufio.NewReader(conn)
+
+	if s.metadata.username != "" {
+		if err := memcachedSASLAuth(conn, reader, s.metadata.username, s.metadata.password); err != nil {
+			return 0, err
+		}
+	}
+
+	var raw string
+	if s.metadata.stat != "" {
+		raw, err = memcachedGetStat(conn, reader, s.metadata.statGroup, s.metadata.stat)
+	} else {
+		raw, err = memcachedGetKey(conn, reader, s.metadata.key)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil {
+		return 0, fmt.Errorf("value %s is not numeric: %s", raw, err)
+	}
+	return value, nil
+}
+
+func memcachedSASLAuth(w io.Writer, r io.Reader, username, password string) error {
+	// PLAIN mechanism: authzid \0 authcid \0 passwd
+	if err := writeMemcachedRequest(w, memcachedOpSASLAuth, "PLAIN", []byte("\x00"+username+"\x00"+password)); err != nil {
+		return err
+	}
+	resp, err := readMemcachedResponse(r)
+	if err != nil {
+		return err
+	}
+	switch resp.status {
+	case memcachedStatusOK:
+		return nil
+	case memcachedStatusAuthError:
+		return errors.New("memcached authentication failed")
+	default:
+		return fmt.Errorf("memcached authentication returned status %#04x: %s", resp.status, string(resp.value))
+	}
+}
+
+func memcachedGetStat(w io.Writer, r io.Reader, group, stat string) (string, error) {
+	if err := writeMemcachedRequest(w, memcachedOpStat, group, nil); err != nil {
+		return "", err
+	}
+
+	// the server sends one packet per stat and terminates the list with an empty key
+	value, found := "", false
+	for {
+		resp, err := readMemcachedResponse(r)
+		if err != nil {
+			return "", err
+		}
+		if resp.status != memcachedStatusOK {
+			return "", fmt.Errorf("memcached stat returned status %#04x: %s", resp.status, string(resp.value))
+		}
+		if resp.key == "" {
+			break
+		}
+		if resp.key == stat {
+			value, found = string(resp.value), true
+		}
+	}
+
+	if !found {
+		return "", fmt.Errorf("stat %s not found", stat)
+	}
+	return value, nil
+}
+
+func memcachedGetKey(w io.Writer, r io.Reader, key string) (string, error) {
+	if err := writeMemcachedRequest(w, memcachedOpGet, key, nil); err != nil {
+		return "", err
+	}
+	resp, err := readMemcachedResponse(r)
+	if err != nil {
+		return "", err
+	}
+	switch resp.status {
+	case memcachedStatusOK:
+		return string(resp.value), nil
+	case memcachedStatusKeyNotFound:
+		// a counter that hasn't been created yet means there is nothing to process
+		return "0", nil
+	default:
+		return "", fmt.Errorf("memcached get returned status %#04x: %s", resp.status, string(resp.value))
+	}
+}
+
+func writeMemcachedRequest(w io.Writer, opcode byte, key string, value []byte) error {
+	packet := make([]byte, memcachedHeaderLength+len(key)+len(value))
+	packet[0] = memcachedRequestMagic
+	packet[1] = opcode
+	binary.BigEndian.PutUint16(packet[2:4], uint16(len(key)))
+	binary.BigEndian.PutUint32(packet[8:12], uint32(len(key)+len(value)))
+	copy(packet[memcachedHeaderLength:], key)
+	copy(packet[memcachedHeaderLength+len(key):], value)
+
+	_, err := w.Write(packet)
+	return err
+}
+
+func readMemcachedResponse(r io.Reader) (*memcachedResponse, error) {
+	header := make([]byte, memcachedHeaderLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("error reading memcached response: %s", err)
+	}
+	if header[0] != memcachedResponseMagic {
+		return nil, fmt.Errorf("invalid memcached response magic %#02x", header[0])
+	}
+
+	keyLength := int(binary.BigEndian.Uint16(header[2:4]))
+	extrasLength := int(header[4])
+	bodyLength := int(binary.BigEndian.Uint32(header[8:12]))
+	if keyLength+extrasLength > bodyLength {
+		return nil, errors.New("invalid memcached response length")
+	}
+
+	body := make([]byte, bodyLength)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("error reading memcached response: %s", err)
+	}
+
+	return &memcachedResponse{
+		status: binary.BigEndian.Uint16(header[6:8]),
+		key:    string(body[extrasLength : extrasLength+keyLength]),
+		value:  body[extrasLength+keyLength:],
+	}, nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *memcachedScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, s.metadata.metricName),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *memcachedScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getMetricValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting memcached: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, value)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
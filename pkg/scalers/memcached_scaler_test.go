@@ -0,0 +1,234 @@
+package scalers
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+type parseMemcachedMetadataTestData struct {
+	metadata    map[string]string
+	authParams  map[string]string
+	raisesError bool
+}
+
+type memcachedMetricIdentifier struct {
+	metadataTestData *parseMemcachedMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testMemcachedMetadata = []parseMemcachedMetadataTestData{
+	// nothing passed
+	{map[string]string{}, map[string]string{}, true},
+	// properly formed stat
+	{map[string]string{"address": "memcached:11211", "stat": "curr_items", "targetValue": "100"}, map[string]string{}, false},
+	// properly formed key
+	{map[string]string{"address": "memcached:11211", "key": "jobs:pending", "targetValue": "10"}, map[string]string{}, false},
+	// stat in group
+	{map[string]string{"address": "memcached:11211", "stat": "items:1:number", "statGroup": "items", "targetValue": "10"}, map[string]string{}, false},
+	// no address
+	{map[string]string{"stat": "curr_items", "targetValue": "100"}, map[string]string{}, true},
+	// neither stat nor key
+	{map[string]string{"address": "memcached:11211", "targetValue": "100"}, map[string]string{}, true},
+	// both stat and key
+	{map[string]string{"address": "memcached:11211", "stat": "curr_items", "key": "jobs:pending", "targetValue": "100"}, map[string]string{}, true},
+	// statGroup with key
+	{map[string]string{"address": "memcached:11211", "key": "jobs:pending", "statGroup": "items", "targetValue": "100"}, map[string]string{}, true},
+	// invalid targetValue
+	{map[string]string{"address": "memcached:11211", "stat": "curr_items", "targetValue": "a"}, map[string]string{}, true},
+	// sasl auth
+	{map[string]string{"address": "memcached:11211", "stat": "curr_items", "targetValue": "100"}, map[string]string{"username": "keda", "password": "secret"}, false},
+	// username without password
+	{map[string]string{"address": "memcached:11211", "stat": "curr_items", "targetValue": "100"}, map[string]string{"username": "keda"}, true},
+	// rate of a stat
+	{map[string]string{"address": "memcached:11211", "stat": "evictions", "rate": "true", "targetValue": "50"}, map[string]string{}, false},
+	// invalid rate
+	{map[string]string{"address": "memcached:11211", "stat": "evictions", "rate": "a", "targetValue": "50"}, map[string]string{}, true},
+}
+
+var memcachedMetricIdentifiers = []memcachedMetricIdentifier{
+	{&testMemcachedMetadata[1], 0, "s0-memcached-curr_items"},
+	{&testMemcachedMetadata[2], 1, "s1-memcached-jobs-pending"},
+	{&testMemcachedMetadata[11], 2, "s2-memcached-evictions-rate"},
+}
+
+func TestParseMemcachedMetadata(t *testing.T) {
+	for idx, testData := range testMemcachedMetadata {
+		_, err := parseMemcachedMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.raisesError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if err == nil && testData.raisesError {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+	}
+}
+
+func TestMemcachedGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range memcachedMetricIdentifiers {
+		meta, err := parseMemcachedMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockMemcachedScaler := memcachedScaler{metadata: meta, timeout: time.Second}
+
+		metricSpec := mockMemcachedScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func writeMemcachedTestResponse(w io.Writer, status uint16, key, value string) {
+	packet := make([]byte, memcachedHeaderLength+len(key)+len(value))
+	packet[0] = memcachedResponseMagic
+	binary.BigEndian.PutUint16(packet[2:4], uint16(len(key)))
+	binary.BigEndian.PutUint16(packet[6:8], status)
+	binary.BigEndian.PutUint32(packet[8:12], uint32(len(key)+len(value)))
+	copy(packet[memcachedHeaderLength:], key)
+	copy(packet[memcachedHeaderLength+len(key):], value)
+	_, _ = w.Write(packet)
+}
+
+// startMemcachedTestServer serves the binary protocol for the stat, get and sasl auth opcodes
+func startMemcachedTestServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					header := make([]byte, memcachedHeaderLength)
+					if _, err := io.ReadFull(reader, header); err != nil {
+						return
+					}
+					keyLength := int(binary.BigEndian.Uint16(header[2:4]))
+					body := make([]byte, binary.BigEndian.Uint32(header[8:12]))
+					if _, err := io.ReadFull(reader, body); err != nil {
+						return
+					}
+					key, value := string(body[:keyLength]), string(body[keyLength:])
+
+					switch header[1] {
+					case memcachedOpSASLAuth:
+						if key == "PLAIN" && value == "\x00keda\x00secret" {
+							writeMemcachedTestResponse(conn, memcachedStatusOK, "", "Authenticated")
+						} else {
+							writeMemcachedTestResponse(conn, memcachedStatusAuthError, "", "Auth failure")
+						}
+					case memcachedOpStat:
+						if key == "items" {
+							writeMemcachedTestResponse(conn, memcachedStatusOK, "items:1:number", "3")
+						} else {
+							writeMemcachedTestResponse(conn, memcachedStatusOK, "pid", "1")
+							writeMemcachedTestResponse(conn, memcachedStatusOK, "curr_items", "42")
+						}
+						writeMemcachedTestResponse(conn, memcachedStatusOK, "", "")
+					case memcachedOpGet:
+						switch key {
+						case "jobs:pending":
+							// get responses carry 4 bytes of flags as extras
+							packet := make([]byte, memcachedHeaderLength+4+2)
+							packet[0] = memcachedResponseMagic
+							packet[4] = 4
+							binary.BigEndian.PutUint32(packet[8:12], 6)
+							copy(packet[memcachedHeaderLength+4:], "17")
+							_, _ = conn.Write(packet)
+						case "jobs:name":
+							writeMemcachedTestResponse(conn, memcachedStatusOK, "", "jobs")
+						default:
+							writeMemcachedTestResponse(conn, memcachedStatusKeyNotFound, "", "Not found")
+						}
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestMemcachedGetValue(t *testing.T) {
+	address := startMemcachedTestServer(t)
+
+	tests := []struct {
+		metadata    map[string]string
+		authParams  map[string]string
+		expected    float64
+		raisesError bool
+	}{
+		{map[string]string{"address": address, "stat": "curr_items", "targetValue": "1"}, map[string]string{}, 42, false},
+		{map[string]string{"address": address, "stat": "items:1:number", "statGroup": "items", "targetValue": "1"}, map[string]string{}, 3, false},
+		{map[string]string{"address": address, "stat": "missing", "targetValue": "1"}, map[string]string{}, 0, true},
+		{map[string]string{"address": address, "key": "jobs:pending", "targetValue": "1"}, map[string]string{}, 17, false},
+		{map[string]string{"address": address, "key": "jobs:missing", "targetValue": "1"}, map[string]string{}, 0, false},
+		{map[string]string{"address": address, "key": "jobs:name", "targetValue": "1"}, map[string]string{}, 0, true},
+		{map[string]string{"address": address, "stat": "curr_items", "targetValue": "1"}, map[string]string{"username": "keda", "password": "secret"}, 42, false},
+		{map[string]string{"address": address, "stat": "curr_items", "targetValue": "1"}, map[string]string{"username": "keda", "password": "wrong"}, 0, true},
+	}
+
+	for idx, test := range tests {
+		meta, err := parseMemcachedMetadata(&ScalerConfig{TriggerMetadata: test.metadata, AuthParams: test.authParams})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		scaler := memcachedScaler{metadata: meta, timeout: time.Second}
+
+		value, err := scaler.getValue(context.Background())
+		if err != nil && !test.raisesError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if err == nil && test.raisesError {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+		if value != test.expected {
+			t.Errorf("Expected %f but got %f for test %d", test.expected, value, idx)
+		}
+	}
+}
+
+func TestMemcachedUpdateRate(t *testing.T) {
+	scaler := memcachedScaler{metadata: &memcachedMetadata{rate: true}}
+	start := time.Now()
+
+	tests := []struct {
+		value    float64
+		at       time.Duration
+		expected float64
+	}{
+		// first sample, no rate yet
+		{100, 0, 0},
+		// 50 evictions in 10 seconds
+		{150, 10 * time.Second, 5},
+		// polled again right away, the previous rate is kept
+		{151, 10*time.Second + 10*time.Millisecond, 5},
+		// 100 evictions in 5 seconds
+		{250, 15 * time.Second, 20},
+		// memcached restarted, the counter went backwards
+		{3, 20 * time.Second, 0},
+		{13, 30 * time.Second, 1},
+	}
+
+	for idx, test := range tests {
+		rate := scaler.updateRate(test.value, start.Add(test.at))
+		if rate != test.expected {
+			t.Errorf("Expected %f but got %f for test %d", test.expected, rate, idx)
+		}
+	}
+}
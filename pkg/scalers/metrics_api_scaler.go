@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	neturl "net/url"
@@ -68,15 +69,15 @@ var httpLog = logf.Log.WithName("metrics_api_scaler")
 func NewMetricsAPIScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseMetricsAPIMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing metric API metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing metric API metadata: %s", err))
 	}
 
-	httpClient := kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, false)
+	httpClient := createHTTPClient(config, config.GlobalHTTPTimeout, false)
 
 	if meta.enableTLS || len(meta.ca) > 0 {
 		config, err := kedautil.NewTLSConfig(meta.cert, meta.key, meta.ca)
@@ -276,6 +277,11 @@ func (s *metricsAPIScaler) GetMetrics(ctx context.Context, metricName string, me
 }
 
 func getMetricAPIServerRequest(ctx context.Context, meta *metricsAPIScalerMetadata) (*http.Request, error) {
+	return newMetricsAPIRequest(ctx, meta, "GET", nil)
+}
+
+// newMetricsAPIRequest creates a request to the url of the metadata, authenticated with its authMode
+func newMetricsAPIRequest(ctx context.Context, meta *metricsAPIScalerMetadata, method string, body io.Reader) (*http.Request, error) {
 	var req *http.Request
 	var err error
 
@@ -291,13 +297,13 @@ func getMetricAPIServerRequest(ctx context.Context, meta *metricsAPIScalerMetada
 			}
 
 			url.RawQuery = queryString.Encode()
-			req, err = http.NewRequestWithContext(ctx, "GET", url.String(), nil)
+			req, err = http.NewRequestWithContext(ctx, method, url.String(), body)
 			if err != nil {
 				return nil, err
 			}
 		} else {
 			// default behaviour is to use header method
-			req, err = http.NewRequestWithContext(ctx, "GET", meta.url, nil)
+			req, err = http.NewRequestWithContext(ctx, method, meta.url, body)
 			if err != nil {
 				return nil, err
 			}
@@ -309,20 +315,20 @@ func getMetricAPIServerRequest(ctx context.Context, meta *metricsAPIScalerMetada
 			}
 		}
 	case meta.enableBaseAuth:
-		req, err = http.NewRequestWithContext(ctx, "GET", meta.url, nil)
+		req, err = http.NewRequestWithContext(ctx, method, meta.url, body)
 		if err != nil {
 			return nil, err
 		}
 
 		req.SetBasicAuth(meta.username, meta.password)
 	case meta.enableBearerAuth:
-		req, err = http.NewRequestWithContext(ctx, "GET", meta.url, nil)
+		req, err = http.NewRequestWithContext(ctx, method, meta.url, body)
 		if err != nil {
 			return nil, err
 		}
 		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", meta.bearerToken))
 	default:
-		req, err = http.NewRequestWithContext(ctx, "GET", meta.url, nil)
+		req, err = http.NewRequestWithContext(ctx, method, meta.url, body)
 		if err != nil {
 			return nil, err
 		}
@@ -77,7 +77,7 @@ var mongoDBLog = logf.Log.WithName("mongodb_scaler")
 func NewMongoDBScaler(ctx context.Context, config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, mongoDBDefaultTimeOut)
@@ -85,7 +85,7 @@ func NewMongoDBScaler(ctx context.Context, config *ScalerConfig) (Scaler, error)
 
 	meta, connStr, err := parseMongoDBMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parsing mongoDB metadata, because of %v", err)
+		return nil, NewPermanentError(fmt.Errorf("failed to parsing mongoDB metadata, because of %v", err))
 	}
 
 	opt := options.Client().ApplyURI(connStr)
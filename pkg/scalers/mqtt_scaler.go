@@ -0,0 +1,348 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	mqttBrokerTypeEMQX       = "emqx"
+	mqttBrokerTypeSys        = "sys"
+	mqttDefaultSysTopic      = "$SYS/broker/store/messages/count"
+	mqttQueueLengthDefault   = 10
+	mqttEMQXPageLimit        = 1000
+	mqttConnectTimeout       = 10 * time.Second
+	mqttSysValueWaitDuration = 15 * time.Second
+)
+
+type mqttScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *mqttMetadata
+	httpClient *http.Client
+
+	// the $SYS client keeps the last value published on the topic
+	client    mqtt.Client
+	valueLock sync.Mutex
+	value     float64
+	hasValue  bool
+	received  chan struct{}
+}
+
+type mqttMetadata struct {
+	brokerType  string
+	apiURL      string // EMQX REST API
+	brokerURL   string // broker publishing the $SYS topic
+	topic       string
+	shareGroup  string
+	sysTopic    string
+	clientID    string
+	username    string
+	password    string
+	queueLength float64
+	scalerIndex int
+}
+
+// mqttEMQXSubscriptions holds the fields of the EMQX subscriptions API used by the scaler
+type mqttEMQXSubscriptions struct {
+	Data []struct {
+		ClientID string `json:"clientid"`
+	} `json:"data"`
+	Meta struct {
+		HasNext bool `json:"hasnext"`
+	} `json:"meta"`
+}
+
+// mqttEMQXClient holds the fields of the EMQX clients API used by the scaler
+type mqttEMQXClient struct {
+	MqueueLen   int64 `json:"mqueue_len"`
+	InflightCnt int64 `json:"inflight_cnt"`
+}
+
+var mqttLog = logf.Log.WithName("mqtt_scaler")
+
+// NewMQTTScaler creates a new mqttScaler
+func NewMQTTScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseMQTTMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing mqtt metadata: %s", err))
+	}
+
+	s := &mqttScaler{
+		metricType: metricType,
+		metadata:   meta,
+		received:   make(chan struct{}),
+	}
+
+	if meta.brokerType == mqttBrokerTypeEMQX {
+		s.httpClient = createHTTPClient(config, config.GlobalHTTPTimeout, false)
+		return s, nil
+	}
+
+	if err := s.subscribeSysTopic(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func parseMQTTMetadata(config *ScalerConfig) (*mqttMetadata, error) {
+	meta := mqttMetadata{}
+
+	meta.brokerType = mqttBrokerTypeSys
+	if val, ok := config.TriggerMetadata["brokerType"]; ok && val != "" {
+		meta.brokerType = val
+	}
+
+	switch meta.brokerType {
+	case mqttBrokerTypeEMQX:
+		apiURL, err := GetFromAuthOrMeta(config, "apiURL")
+		if err != nil {
+			return nil, err
+		}
+		meta.apiURL = strings.TrimSuffix(apiURL, "/")
+
+		if val, ok := config.TriggerMetadata["topic"]; ok && val != "" {
+			meta.topic = val
+		} else {
+			return nil, errors.New("no topic given")
+		}
+		meta.shareGroup = config.TriggerMetadata["shareGroup"]
+	case mqttBrokerTypeSys:
+		brokerURL, err := GetFromAuthOrMeta(config, "brokerURL")
+		if err != nil {
+			return nil, err
+		}
+		meta.brokerURL = brokerURL
+
+		meta.sysTopic = mqttDefaultSysTopic
+		if val, ok := config.TriggerMetadata["sysTopic"]; ok && val != "" {
+			meta.sysTopic = val
+		}
+		if !strings.HasPrefix(meta.sysTopic, "$SYS/") {
+			return nil, fmt.Errorf("sysTopic must be a $SYS topic, got %s", meta.sysTopic)
+		}
+
+		meta.clientID = fmt.Sprintf("keda-%d", time.Now().UnixNano())
+		if val, ok := config.TriggerMetadata["clientID"]; ok && val != "" {
+			meta.clientID = val
+		}
+	default:
+		return nil, fmt.Errorf("brokerType must be either %s or %s, got %s", mqttBrokerTypeEMQX, mqttBrokerTypeSys, meta.brokerType)
+	}
+
+	meta.username = config.AuthParams["username"]
+	meta.password = config.AuthParams["password"]
+
+	meta.queueLength = mqttQueueLengthDefault
+	if val, ok := config.TriggerMetadata["queueLength"]; ok {
+		queueLength, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("queueLength parsing error %s", err.Error())
+		}
+		if queueLength <= 0 {
+			return nil, errors.New("queueLength must be greater than 0")
+		}
+		meta.queueLength = queueLength
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// subscribeSysTopic connects to the broker and keeps the last value published on the $SYS topic
+func (s *mqttScaler) subscribeSysTopic() error {
+	opts := mqtt.NewClientOptions().
+		AddBroker(s.metadata.brokerURL).
+		SetClientID(s.metadata.clientID).
+		SetUsername(s.metadata.username).
+		SetPassword(s.metadata.password).
+		SetConnectTimeout(mqttConnectTimeout).
+		SetAutoReconnect(true).
+		SetCleanSession(true)
+	// resubscribe on reconnection as the session is not kept
+	opts.SetOnConnectHandler(func(client mqtt.Client) {
+		token := client.Subscribe(s.metadata.sysTopic, 0, s.onSysMessage)
+		if token.WaitTimeout(mqttConnectTimeout) && token.Error() != nil {
+			mqttLog.Error(token.Error(), "error subscribing to $SYS topic", "topic", s.metadata.sysTopic)
+		}
+	})
+
+	s.client = mqtt.NewClient(opts)
+	token := s.client.Connect()
+	if !token.WaitTimeout(mqttConnectTimeout) {
+		return fmt.Errorf("timeout connecting to mqtt broker %s", s.metadata.brokerURL)
+	}
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("error connecting to mqtt broker: %s", err)
+	}
+	return nil
+}
+
+func (s *mqttScaler) onSysMessage(_ mqtt.Client, msg mqtt.Message) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(string(msg.Payload())), 64)
+	if err != nil {
+		mqttLog.V(1).Info("skipping unreadable $SYS value", "topic", msg.Topic(), "error", err.Error())
+		return
+	}
+
+	s.valueLock.Lock()
+	defer s.valueLock.Unlock()
+	s.value = value
+	if !s.hasValue {
+		s.hasValue = true
+		close(s.received)
+	}
+}
+
+// IsActive returns true if there are queued or inflight messages
+func (s *mqttScaler) IsActive(ctx context.Context) (bool, error) {
+	messages, err := s.getQueuedMessages(ctx)
+	if err != nil {
+		mqttLog.Error(err, "error getting queued mqtt messages")
+		return false, err
+	}
+	return messages > 0, nil
+}
+
+func (s *mqttScaler) Close(context.Context) error {
+	if s.client != nil && s.client.IsConnected() {
+		s.client.Disconnect(250)
+	}
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *mqttScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	var metricName string
+	if s.metadata.brokerType == mqttBrokerTypeEMQX {
+		metricName = fmt.Sprintf("mqtt-%s", s.metadata.topic)
+		if s.metadata.shareGroup != "" {
+			metricName = fmt.Sprintf("mqtt-%s-%s", s.metadata.shareGroup, s.metadata.topic)
+		}
+	} else {
+		metricName = fmt.Sprintf("mqtt-%s", strings.TrimPrefix(s.metadata.sysTopic, "$SYS/"))
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(metricName)),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.queueLength),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of queued and inflight messages
+func (s *mqttScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	messages, err := s.getQueuedMessages(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting queued mqtt messages: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, messages)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+func (s *mqttScaler) getQueuedMessages(ctx context.Context) (float64, error) {
+	if s.metadata.brokerType == mqttBrokerTypeEMQX {
+		return s.getEMQXQueuedMessages(ctx)
+	}
+	return s.getSysValue(ctx)
+}
+
+// getSysValue returns the last value of the $SYS topic, waiting for the first one to be published
+func (s *mqttScaler) getSysValue(ctx context.Context) (float64, error) {
+	select {
+	case <-s.received:
+	case <-time.After(mqttSysValueWaitDuration):
+		return -1, fmt.Errorf("no value published on %s yet", s.metadata.sysTopic)
+	case <-ctx.Done():
+		return -1, ctx.Err()
+	}
+
+	s.valueLock.Lock()
+	defer s.valueLock.Unlock()
+	return s.value, nil
+}
+
+// getEMQXQueuedMessages sums the queued and inflight messages of the sessions subscribed to the topic,
+// the sessions of disconnected persistent clients are kept by EMQX so the messages are counted at zero replicas
+func (s *mqttScaler) getEMQXQueuedMessages(ctx context.Context) (float64, error) {
+	clientIDs := map[string]bool{}
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("topic", s.metadata.topic)
+		if s.metadata.shareGroup != "" {
+			query.Set("share_group", s.metadata.shareGroup)
+		}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("limit", strconv.Itoa(mqttEMQXPageLimit))
+
+		subscriptions := mqttEMQXSubscriptions{}
+		if err := s.getEMQXJSON(ctx, "/api/v5/subscriptions?"+query.Encode(), &subscriptions); err != nil {
+			return -1, err
+		}
+		for _, subscription := range subscriptions.Data {
+			clientIDs[subscription.ClientID] = true
+		}
+		if !subscriptions.Meta.HasNext {
+			break
+		}
+	}
+
+	var messages int64
+	for clientID := range clientIDs {
+		client := mqttEMQXClient{}
+		if err := s.getEMQXJSON(ctx, "/api/v5/clients/"+url.PathEscape(clientID), &client); err != nil {
+			return -1, err
+		}
+		messages += client.MqueueLen + client.InflightCnt
+	}
+	return float64(messages), nil
+}
+
+func (s *mqttScaler) getEMQXJSON(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.metadata.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	if s.metadata.username != "" {
+		req.SetBasicAuth(s.metadata.username, s.metadata.password)
+	}
+
+	r, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(r.Body)
+		return fmt.Errorf("error requesting EMQX API status: %s, response: %s", r.Status, body)
+	}
+	return json.NewDecoder(r.Body).Decode(v)
+}
@@ -0,0 +1,117 @@
+package scalers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseMQTTMetadataTestData struct {
+	metadata   map[string]string
+	isError    bool
+	authParams map[string]string
+}
+
+type mqttMetricIdentifier struct {
+	metadataTestData *parseMQTTMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testMQTTMetadata = []parseMQTTMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true, map[string]string{}},
+	// properly formed $SYS
+	{map[string]string{"brokerURL": "tcp://mosquitto:1883", "queueLength": "100"}, false, map[string]string{}},
+	// properly formed emqx with a shared subscription
+	{map[string]string{"brokerType": "emqx", "apiURL": "http://emqx:18083", "topic": "sensors/+/data", "shareGroup": "workers"}, false, map[string]string{"username": "key", "password": "secret"}},
+	// custom $SYS topic
+	{map[string]string{"brokerURL": "tcp://mosquitto:1883", "sysTopic": "$SYS/broker/messages/stored", "clientID": "keda"}, false, map[string]string{}},
+	// unknown brokerType
+	{map[string]string{"brokerType": "hivemq", "brokerURL": "tcp://hivemq:1883"}, true, map[string]string{}},
+	// emqx without apiURL
+	{map[string]string{"brokerType": "emqx", "topic": "sensors"}, true, map[string]string{}},
+	// emqx without topic
+	{map[string]string{"brokerType": "emqx", "apiURL": "http://emqx:18083"}, true, map[string]string{}},
+	// $SYS without brokerURL
+	{map[string]string{"sysTopic": "$SYS/broker/messages/stored"}, true, map[string]string{}},
+	// not a $SYS topic
+	{map[string]string{"brokerURL": "tcp://mosquitto:1883", "sysTopic": "sensors/data"}, true, map[string]string{}},
+	// improperly formed queueLength
+	{map[string]string{"brokerURL": "tcp://mosquitto:1883", "queueLength": "AA"}, true, map[string]string{}},
+	// zero queueLength
+	{map[string]string{"brokerURL": "tcp://mosquitto:1883", "queueLength": "0"}, true, map[string]string{}},
+}
+
+var mqttMetricIdentifiers = []mqttMetricIdentifier{
+	{&testMQTTMetadata[1], 0, "s0-mqtt-broker-store-messages-count"},
+	{&testMQTTMetadata[2], 1, "s1-mqtt-workers-sensors-+-data"},
+}
+
+func TestMQTTParseMetadata(t *testing.T) {
+	for idx, testData := range testMQTTMetadata {
+		_, err := parseMQTTMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+	}
+}
+
+func TestMQTTGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range mqttMetricIdentifiers {
+		meta, err := parseMQTTMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockMQTTScaler := mqttScaler{metadata: meta}
+
+		metricSpec := mockMQTTScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestMQTTGetEMQXQueuedMessages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "key" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v5/subscriptions":
+			assert.Equal(t, "sensors/+/data", r.URL.Query().Get("topic"))
+			assert.Equal(t, "workers", r.URL.Query().Get("share_group"))
+			if r.URL.Query().Get("page") == "1" {
+				_, _ = w.Write([]byte(`{"data": [{"clientid": "worker-1"}, {"clientid": "worker-2"}], "meta": {"hasnext": true}}`))
+			} else {
+				_, _ = w.Write([]byte(`{"data": [{"clientid": "worker-1"}], "meta": {"hasnext": false}}`))
+			}
+		case "/api/v5/clients/worker-1":
+			_, _ = w.Write([]byte(`{"clientid": "worker-1", "mqueue_len": 10, "inflight_cnt": 2}`))
+		case "/api/v5/clients/worker-2":
+			_, _ = w.Write([]byte(`{"clientid": "worker-2", "mqueue_len": 5, "inflight_cnt": 0}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	meta, err := parseMQTTMetadata(&ScalerConfig{
+		TriggerMetadata: map[string]string{"brokerType": "emqx", "apiURL": server.URL + "/", "topic": "sensors/+/data", "shareGroup": "workers"},
+		AuthParams:      map[string]string{"username": "key", "password": "secret"},
+	})
+	assert.NoError(t, err)
+	s := mqttScaler{metadata: meta, httpClient: server.Client()}
+
+	messages, err := s.getQueuedMessages(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, float64(17), messages)
+}
@@ -65,12 +65,12 @@ var mssqlLog = logf.Log.WithName("mssql_scaler")
 func NewMSSQLScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseMSSQLMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing mssql metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing mssql metadata: %s", err))
 	}
 
 	conn, err := newMSSQLConnection(meta)
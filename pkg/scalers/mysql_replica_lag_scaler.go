@@ -0,0 +1,436 @@
+package scalers
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	mySQLReplicaLagSecondsBehindSource = "secondsBehindSource"
+	mySQLReplicaLagGtidGap             = "gtidGap"
+
+	defaultMySQLReplicaTargetLag = 30
+)
+
+var mySQLReplicaLagLog = logf.Log.WithName("mysql_replica_lag_scaler")
+
+type mySQLReplicaLagScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *mySQLReplicaLagMetadata
+	connection *sql.DB
+}
+
+type mySQLReplicaLagMetadata struct {
+	connectionString    string
+	lagMetric           string
+	channel             string
+	targetLag           int64
+	activationTargetLag int64
+	scalerIndex         int
+}
+
+// NewMySQLReplicaLagScaler creates a new mySQLReplicaLagScaler, reading the replication lag of a MySQL replica
+func NewMySQLReplicaLagScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseMySQLReplicaLagMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing MySQL replica lag metadata: %s", err))
+	}
+
+	conn, err := newMySQLConnection(&mySQLMetadata{connectionString: meta.connectionString})
+	if err != nil {
+		return nil, fmt.Errorf("error establishing MySQL connection: %s", err)
+	}
+	return &mySQLReplicaLagScaler{
+		metricType: metricType,
+		metadata:   meta,
+		connection: conn,
+	}, nil
+}
+
+func parseMySQLReplicaLagMetadata(config *ScalerConfig) (*mySQLReplicaLagMetadata, error) {
+	meta := mySQLReplicaLagMetadata{
+		lagMetric: mySQLReplicaLagSecondsBehindSource,
+		targetLag: defaultMySQLReplicaTargetLag,
+	}
+
+	if val, ok := config.TriggerMetadata["lagMetric"]; ok && val != "" {
+		meta.lagMetric = strings.TrimSpace(val)
+	}
+	switch meta.lagMetric {
+	case mySQLReplicaLagSecondsBehindSource, mySQLReplicaLagGtidGap:
+	default:
+		return nil, fmt.Errorf("err incorrect value for lagMetric is given: %s", meta.lagMetric)
+	}
+
+	meta.channel = config.TriggerMetadata["channel"]
+
+	if val, ok := config.TriggerMetadata["targetLag"]; ok && val != "" {
+		targetLag, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetLag parsing error %s", err.Error())
+		}
+		meta.targetLag = targetLag
+	}
+
+	if val, ok := config.TriggerMetadata["activationTargetLag"]; ok && val != "" {
+		activationTargetLag, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationTargetLag parsing error %s", err.Error())
+		}
+		meta.activationTargetLag = activationTargetLag
+	}
+
+	connectionString, err := parseMySQLReplicaConnectionString(config)
+	if err != nil {
+		return nil, err
+	}
+	meta.connectionString = connectionString
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// parseMySQLReplicaConnectionString returns the DSN of the replica with the TLS and server public key configurations
+// registered to the driver
+func parseMySQLReplicaConnectionString(config *ScalerConfig) (string, error) {
+	var dsn *mysql.Config
+	switch {
+	case config.AuthParams["connectionString"] != "":
+		parsed, err := mysql.ParseDSN(config.AuthParams["connectionString"])
+		if err != nil {
+			return "", fmt.Errorf("invalid connectionString: %s", err)
+		}
+		dsn = parsed
+	case config.TriggerMetadata["connectionStringFromEnv"] != "":
+		parsed, err := mysql.ParseDSN(config.ResolvedEnv[config.TriggerMetadata["connectionStringFromEnv"]])
+		if err != nil {
+			return "", fmt.Errorf("invalid connectionString: %s", err)
+		}
+		dsn = parsed
+	default:
+		host, err := GetFromAuthOrMeta(config, "host")
+		if err != nil {
+			return "", err
+		}
+		port, err := GetFromAuthOrMeta(config, "port")
+		if err != nil {
+			return "", err
+		}
+		username, err := GetFromAuthOrMeta(config, "username")
+		if err != nil {
+			return "", err
+		}
+
+		var password string
+		if config.AuthParams["password"] != "" {
+			password = config.AuthParams["password"]
+		} else if config.TriggerMetadata["passwordFromEnv"] != "" {
+			password = config.ResolvedEnv[config.TriggerMetadata["passwordFromEnv"]]
+		}
+		if len(password) == 0 {
+			return "", fmt.Errorf("no password given")
+		}
+
+		dsn = mysql.NewConfig()
+		dsn.Addr = fmt.Sprintf("%s:%s", host, port)
+		dsn.User = username
+		dsn.Passwd = password
+		dsn.Net = "tcp"
+	}
+
+	if val, ok := config.TriggerMetadata["tls"]; ok && val != "" {
+		switch val {
+		case "true", "false", "skip-verify", "preferred":
+			dsn.TLSConfig = val
+		default:
+			return "", fmt.Errorf("err incorrect value for tls is given: %s", val)
+		}
+	}
+	ca, cert, key := config.AuthParams["ca"], config.AuthParams["cert"], config.AuthParams["key"]
+	if ca != "" || cert != "" {
+		if (cert == "") != (key == "") {
+			return "", errors.New("cert and key must be given together")
+		}
+		name, err := registerMySQLTLSConfig(ca, cert, key, dsn.Addr, dsn.TLSConfig == "skip-verify")
+		if err != nil {
+			return "", err
+		}
+		dsn.TLSConfig = name
+	}
+
+	// the server public key encrypts the password of caching_sha2_password and sha256_password accounts on connections
+	// without TLS, the driver requests it from the server when none is given
+	if val := config.AuthParams["serverPubKey"]; val != "" {
+		name, err := registerMySQLServerPubKey(val)
+		if err != nil {
+			return "", err
+		}
+		dsn.ServerPubKey = name
+	}
+
+	return dsn.FormatDSN(), nil
+}
+
+// registerMySQLTLSConfig registers the TLS configuration of a CA and a client certificate to the driver, under a name
+// derived from the configuration so the scalers of the same replica share it
+func registerMySQLTLSConfig(ca, cert, key, addr string, skipVerify bool) (string, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: skipVerify}
+	if host := strings.Split(addr, ":")[0]; host != "" {
+		tlsConfig.ServerName = host
+	}
+	if ca != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(ca)) {
+			return "", errors.New("invalid ca")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cert != "" {
+		pair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+		if err != nil {
+			return "", fmt.Errorf("error parse X509KeyPair: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	name := fmt.Sprintf("keda-%x", sha256.Sum256([]byte(strings.Join([]string{ca, cert, key, addr, strconv.FormatBool(skipVerify)}, "\x00"))))
+	if err := mysql.RegisterTLSConfig(name, tlsConfig); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// registerMySQLServerPubKey registers a PEM encoded RSA public key to the driver
+func registerMySQLServerPubKey(pubKey string) (string, error) {
+	block, _ := pem.Decode([]byte(pubKey))
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return "", errors.New("invalid serverPubKey")
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("invalid serverPubKey: %s", err)
+	}
+	rsaKey, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return "", errors.New("serverPubKey isn't a RSA public key")
+	}
+
+	name := fmt.Sprintf("keda-%x", sha256.Sum256([]byte(pubKey)))
+	mysql.RegisterServerPubKey(name, rsaKey)
+	return name, nil
+}
+
+// getReplicaStatus returns the columns of the replication channels, SHOW SLAVE STATUS before MySQL 8.0.22
+func (s *mySQLReplicaLagScaler) getReplicaStatus(ctx context.Context) ([]map[string]sql.NullString, error) {
+	rows, err := s.connection.QueryContext(ctx, "SHOW REPLICA STATUS")
+	if err != nil {
+		var mySQLErr *mysql.MySQLError
+		if !errors.As(err, &mySQLErr) {
+			return nil, err
+		}
+		rows, err = s.connection.QueryContext(ctx, "SHOW SLAVE STATUS")
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var statuses []map[string]sql.NullString
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		status := map[string]sql.NullString{}
+		for i, column := range columns {
+			status[column] = values[i]
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, rows.Err()
+}
+
+// replicaStatusColumn returns a column of the replica status by its name from MySQL 8.0.22 or its legacy name
+func replicaStatusColumn(status map[string]sql.NullString, name, legacyName string) sql.NullString {
+	if val, ok := status[name]; ok {
+		return val
+	}
+	return status[legacyName]
+}
+
+// secondsBehindSource returns the highest Seconds_Behind_Source of the channels
+func secondsBehindSource(statuses []map[string]sql.NullString) (int64, error) {
+	var lag int64
+	for _, status := range statuses {
+		seconds := replicaStatusColumn(status, "Seconds_Behind_Source", "Seconds_Behind_Master")
+		if !seconds.Valid {
+			channel := status["Channel_Name"].String
+			return 0, fmt.Errorf("replication of channel %q isn't running", channel)
+		}
+		value, err := strconv.ParseInt(seconds.String, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing Seconds_Behind_Source: %s", err)
+		}
+		if value > lag {
+			lag = value
+		}
+	}
+	return lag, nil
+}
+
+// countGtidSet returns the number of transactions of a GTID set, e.g. 3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5:11,
+// 4C11FA47-71CA-11E1-9E33-C80AA9429562:23 holds 7
+func countGtidSet(gtidSet string) (int64, error) {
+	var count int64
+	gtidSet = strings.Join(strings.Fields(gtidSet), "")
+	if gtidSet == "" {
+		return 0, nil
+	}
+	for _, sourceSet := range strings.Split(gtidSet, ",") {
+		parts := strings.Split(sourceSet, ":")
+		if len(parts) < 2 {
+			return 0, fmt.Errorf("invalid GTID set %s", sourceSet)
+		}
+		for _, interval := range parts[1:] {
+			bounds := strings.SplitN(interval, "-", 2)
+			start, err := strconv.ParseInt(bounds[0], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid GTID interval %s", interval)
+			}
+			end := start
+			if len(bounds) == 2 {
+				if end, err = strconv.ParseInt(bounds[1], 10, 64); err != nil || end < start {
+					return 0, fmt.Errorf("invalid GTID interval %s", interval)
+				}
+			}
+			count += end - start + 1
+		}
+	}
+	return count, nil
+}
+
+// getGtidGap returns the transactions the replica received and didn't apply yet
+func (s *mySQLReplicaLagScaler) getGtidGap(ctx context.Context, statuses []map[string]sql.NullString) (int64, error) {
+	var gap int64
+	for _, status := range statuses {
+		retrieved := status["Retrieved_Gtid_Set"].String
+		if retrieved == "" {
+			continue
+		}
+		var pending string
+		if err := s.connection.QueryRowContext(ctx, "SELECT GTID_SUBTRACT(?, @@GLOBAL.gtid_executed)", retrieved).Scan(&pending); err != nil {
+			return 0, fmt.Errorf("could not query MySQL: %s", err)
+		}
+		count, err := countGtidSet(pending)
+		if err != nil {
+			return 0, err
+		}
+		gap += count
+	}
+	return gap, nil
+}
+
+// getLag returns the lag of the replica in seconds or transactions
+func (s *mySQLReplicaLagScaler) getLag(ctx context.Context) (int64, error) {
+	statuses, err := s.getReplicaStatus(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("could not query MySQL replica status: %s", err)
+	}
+	if s.metadata.channel != "" {
+		var channelStatuses []map[string]sql.NullString
+		for _, status := range statuses {
+			if status["Channel_Name"].String == s.metadata.channel {
+				channelStatuses = append(channelStatuses, status)
+			}
+		}
+		statuses = channelStatuses
+	}
+	if len(statuses) == 0 {
+		return 0, errors.New("the server isn't a replica")
+	}
+
+	if s.metadata.lagMetric == mySQLReplicaLagGtidGap {
+		return s.getGtidGap(ctx, statuses)
+	}
+	return secondsBehindSource(statuses)
+}
+
+// IsActive returns true if the lag is over activationTargetLag
+func (s *mySQLReplicaLagScaler) IsActive(ctx context.Context) (bool, error) {
+	lag, err := s.getLag(ctx)
+	if err != nil {
+		mySQLReplicaLagLog.Error(err, "error getting MySQL replica lag")
+		return false, err
+	}
+
+	return lag > s.metadata.activationTargetLag, nil
+}
+
+// Close disposes of MySQL connections
+func (s *mySQLReplicaLagScaler) Close(context.Context) error {
+	if err := s.connection.Close(); err != nil {
+		mySQLReplicaLagLog.Error(err, "Error closing MySQL connection")
+		return err
+	}
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *mySQLReplicaLagScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	metricName := fmt.Sprintf("mysql-replica-%s", s.metadata.lagMetric)
+	if s.metadata.channel != "" {
+		metricName = fmt.Sprintf("mysql-replica-%s-%s", s.metadata.channel, s.metadata.lagMetric)
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(metricName)),
+		},
+		Target: GetMetricTarget(s.metricType, s.metadata.targetLag),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the lag of the replica
+func (s *mySQLReplicaLagScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	lag, err := s.getLag(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting MySQL replica lag: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(lag))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
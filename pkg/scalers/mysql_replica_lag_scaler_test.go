@@ -0,0 +1,142 @@
+package scalers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"database/sql"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseMySQLReplicaLagMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type mySQLReplicaLagMetricIdentifier struct {
+	metadataTestData *parseMySQLReplicaLagMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testMySQLReplicaLagMetadata = []parseMySQLReplicaLagMetadataTestData{
+	// connection string
+	{map[string]string{}, map[string]string{"connectionString": "keda:secret@tcp(replica:3306)/"}, false},
+	// connection parameters
+	{map[string]string{"host": "replica", "port": "3306", "username": "keda", "lagMetric": "gtidGap", "channel": "source_1", "targetLag": "100", "activationTargetLag": "10", "tls": "skip-verify"},
+		map[string]string{"password": "secret"}, false},
+	// invalid connectionString
+	{map[string]string{}, map[string]string{"connectionString": "replica:3306"}, true},
+	// missing password
+	{map[string]string{"host": "replica", "port": "3306", "username": "keda"}, map[string]string{}, true},
+	// missing host
+	{map[string]string{"port": "3306", "username": "keda"}, map[string]string{"password": "secret"}, true},
+	// invalid lagMetric
+	{map[string]string{"lagMetric": "relayLogSpace"}, map[string]string{"connectionString": "keda:secret@tcp(replica:3306)/"}, true},
+	// invalid lags
+	{map[string]string{"targetLag": "a"}, map[string]string{"connectionString": "keda:secret@tcp(replica:3306)/"}, true},
+	{map[string]string{"activationTargetLag": "a"}, map[string]string{"connectionString": "keda:secret@tcp(replica:3306)/"}, true},
+	// invalid tls
+	{map[string]string{"tls": "required"}, map[string]string{"connectionString": "keda:secret@tcp(replica:3306)/"}, true},
+	// invalid ca
+	{map[string]string{}, map[string]string{"connectionString": "keda:secret@tcp(replica:3306)/", "ca": "ca"}, true},
+	// cert without key
+	{map[string]string{}, map[string]string{"connectionString": "keda:secret@tcp(replica:3306)/", "cert": "cert"}, true},
+	// invalid serverPubKey
+	{map[string]string{}, map[string]string{"connectionString": "keda:secret@tcp(replica:3306)/", "serverPubKey": "key"}, true},
+}
+
+var mySQLReplicaLagMetricIdentifiers = []mySQLReplicaLagMetricIdentifier{
+	{&testMySQLReplicaLagMetadata[0], 0, "s0-mysql-replica-secondsBehindSource"},
+	{&testMySQLReplicaLagMetadata[1], 1, "s1-mysql-replica-source_1-gtidGap"},
+}
+
+func TestMySQLReplicaLagParseMetadata(t *testing.T) {
+	for _, testData := range testMySQLReplicaLagMetadata {
+		_, err := parseMySQLReplicaLagMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestMySQLReplicaLagServerPubKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	pubKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	meta, err := parseMySQLReplicaLagMetadata(&ScalerConfig{TriggerMetadata: map[string]string{},
+		AuthParams: map[string]string{"connectionString": "keda:secret@tcp(replica:3306)/", "serverPubKey": pubKey}})
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(meta.connectionString, "serverPubKey=keda-"), meta.connectionString)
+}
+
+func TestMySQLReplicaLagGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range mySQLReplicaLagMetricIdentifiers {
+		meta, err := parseMySQLReplicaLagMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockMySQLReplicaLagScaler := mySQLReplicaLagScaler{metadata: meta}
+
+		metricSpec := mockMySQLReplicaLagScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestMySQLSecondsBehindSource(t *testing.T) {
+	lag, err := secondsBehindSource([]map[string]sql.NullString{
+		{"Channel_Name": {String: "", Valid: true}, "Seconds_Behind_Source": {String: "12", Valid: true}},
+		{"Channel_Name": {String: "source_2", Valid: true}, "Seconds_Behind_Source": {String: "40", Valid: true}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(40), lag)
+
+	// before MySQL 8.0.22
+	lag, err = secondsBehindSource([]map[string]sql.NullString{{"Seconds_Behind_Master": {String: "3", Valid: true}}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), lag)
+
+	// the replication is stopped
+	_, err = secondsBehindSource([]map[string]sql.NullString{{"Seconds_Behind_Source": {}}})
+	assert.Error(t, err)
+}
+
+func TestCountGtidSet(t *testing.T) {
+	testCases := []struct {
+		gtidSet string
+		count   int64
+		isError bool
+	}{
+		{"", 0, false},
+		{"3E11FA47-71CA-11E1-9E33-C80AA9429562:23", 1, false},
+		{"3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5:11,\n4C11FA47-71CA-11E1-9E33-C80AA9429562:23", 7, false},
+		{"3E11FA47-71CA-11E1-9E33-C80AA9429562", 0, true},
+		{"3E11FA47-71CA-11E1-9E33-C80AA9429562:5-1", 0, true},
+		{"3E11FA47-71CA-11E1-9E33-C80AA9429562:a", 0, true},
+	}
+
+	for _, testCase := range testCases {
+		count, err := countGtidSet(testCase.gtidSet)
+		if testCase.isError {
+			assert.Error(t, err, testCase.gtidSet)
+			continue
+		}
+		assert.NoError(t, err, testCase.gtidSet)
+		assert.Equal(t, testCase.count, count, testCase.gtidSet)
+	}
+}
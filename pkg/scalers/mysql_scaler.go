@@ -40,12 +40,12 @@ var mySQLLog = logf.Log.WithName("mysql_scaler")
 func NewMySQLScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseMySQLMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing MySQL metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing MySQL metadata: %s", err))
 	}
 
 	conn, err := newMySQLConnection(meta)
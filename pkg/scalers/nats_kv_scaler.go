@@ -0,0 +1,286 @@
+package scalers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	natsKVBucketTypeKV      = "kv"
+	natsKVBucketTypeObject  = "object"
+	natsKVEntryCountDefault = 5
+	natsKVTokenSeparator    = "."
+)
+
+type natsKVScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *natsKVMetadata
+	conn       *nats.Conn
+	js         nats.JetStreamContext
+}
+
+type natsKVMetadata struct {
+	natsServerURL string
+	bucket        string
+	bucketType    string
+	keyPrefix     string
+	entryCount    float64
+
+	// auth
+	username string
+	password string
+	token    string
+
+	// TLS
+	enableTLS bool
+	cert      string
+	key       string
+	ca        string
+
+	scalerIndex int
+}
+
+var natsKVLog = logf.Log.WithName("nats_kv_scaler")
+
+// NewNATSKVScaler creates a new natsKVScaler
+func NewNATSKVScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseNATSKVMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing nats kv metadata: %s", err))
+	}
+
+	opts := []nats.Option{nats.Name("keda-nats-kv-scaler")}
+	switch {
+	case meta.token != "":
+		opts = append(opts, nats.Token(meta.token))
+	case meta.username != "":
+		opts = append(opts, nats.UserInfo(meta.username, meta.password))
+	}
+	if meta.enableTLS {
+		tlsConfig, err := kedautil.NewTLSConfig(meta.cert, meta.key, meta.ca)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, nats.Secure(tlsConfig))
+	}
+
+	conn, err := nats.Connect(meta.natsServerURL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to nats: %s", err)
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error getting jetstream context: %s", err)
+	}
+
+	return &natsKVScaler{
+		metricType: metricType,
+		metadata:   meta,
+		conn:       conn,
+		js:         js,
+	}, nil
+}
+
+func parseNATSKVMetadata(config *ScalerConfig) (*natsKVMetadata, error) {
+	meta := natsKVMetadata{}
+
+	switch {
+	case config.AuthParams["natsServerURL"] != "":
+		meta.natsServerURL = config.AuthParams["natsServerURL"]
+	case config.TriggerMetadata["natsServerURL"] != "":
+		meta.natsServerURL = config.TriggerMetadata["natsServerURL"]
+	case config.TriggerMetadata["natsServerURLFromEnv"] != "":
+		meta.natsServerURL = config.ResolvedEnv[config.TriggerMetadata["natsServerURLFromEnv"]]
+	default:
+		return nil, errors.New("no natsServerURL given")
+	}
+
+	if val, ok := config.TriggerMetadata["bucket"]; ok && val != "" {
+		meta.bucket = val
+	} else {
+		return nil, errors.New("no bucket given")
+	}
+
+	meta.bucketType = natsKVBucketTypeKV
+	if val, ok := config.TriggerMetadata["bucketType"]; ok && val != "" {
+		meta.bucketType = val
+	}
+	if meta.bucketType != natsKVBucketTypeKV && meta.bucketType != natsKVBucketTypeObject {
+		return nil, fmt.Errorf("bucketType must be either %s or %s, got %s", natsKVBucketTypeKV, natsKVBucketTypeObject, meta.bucketType)
+	}
+
+	meta.keyPrefix = config.TriggerMetadata["keyPrefix"]
+
+	meta.entryCount = natsKVEntryCountDefault
+	if val, ok := config.TriggerMetadata["entryCount"]; ok {
+		entryCount, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("entryCount parsing error %s", err.Error())
+		}
+		if entryCount <= 0 {
+			return nil, errors.New("entryCount must be greater than 0")
+		}
+		meta.entryCount = entryCount
+	}
+
+	meta.username = config.AuthParams["username"]
+	meta.password = config.AuthParams["password"]
+	meta.token = config.AuthParams["token"]
+	if meta.username != "" && meta.password == "" {
+		return nil, errors.New("no password given")
+	}
+
+	if val, ok := config.AuthParams["tls"]; ok {
+		val = strings.TrimSpace(val)
+		switch val {
+		case "enable":
+			meta.enableTLS = true
+			meta.ca = config.AuthParams["ca"]
+			meta.cert = config.AuthParams["cert"]
+			meta.key = config.AuthParams["key"]
+			if (meta.cert == "") != (meta.key == "") {
+				return nil, errors.New("cert and key must be given together")
+			}
+		case "disable":
+			meta.enableTLS = false
+		default:
+			return nil, fmt.Errorf("err incorrect value for TLS given: %s", val)
+		}
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// IsActive returns true if there are entries matching the key prefix
+func (s *natsKVScaler) IsActive(ctx context.Context) (bool, error) {
+	entries, err := s.getEntryCount(ctx)
+	if err != nil {
+		natsKVLog.Error(err, "error counting nats bucket entries")
+		return false, err
+	}
+	return entries > 0, nil
+}
+
+func (s *natsKVScaler) Close(context.Context) error {
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *natsKVScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	metricName := fmt.Sprintf("nats-%s-%s", s.metadata.bucketType, s.metadata.bucket)
+	if s.metadata.keyPrefix != "" {
+		metricName = fmt.Sprintf("%s-%s", metricName, s.metadata.keyPrefix)
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(metricName)),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.entryCount),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of entries matching the key prefix
+func (s *natsKVScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	entries, err := s.getEntryCount(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error counting nats bucket entries: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(entries))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+func (s *natsKVScaler) getEntryCount(ctx context.Context) (int64, error) {
+	if s.metadata.bucketType == natsKVBucketTypeObject {
+		return s.getObjectCount(ctx)
+	}
+	return s.getKeyCount(ctx)
+}
+
+// getKeyCount counts the live keys of the bucket matching the prefix, deleted and purged keys are skipped
+func (s *natsKVScaler) getKeyCount(ctx context.Context) (int64, error) {
+	kv, err := s.js.KeyValue(s.metadata.bucket)
+	if err != nil {
+		return -1, err
+	}
+
+	watcher, err := kv.Watch(getNATSKVWatchPattern(s.metadata.keyPrefix), nats.IgnoreDeletes(), nats.MetaOnly(), nats.Context(ctx))
+	if err != nil {
+		return -1, err
+	}
+	defer watcher.Stop()
+
+	var count int64
+	for {
+		select {
+		case entry, ok := <-watcher.Updates():
+			// the watcher sends nil once all the current values are delivered
+			if !ok || entry == nil {
+				return count, nil
+			}
+			if strings.HasPrefix(entry.Key(), s.metadata.keyPrefix) {
+				count++
+			}
+		case <-ctx.Done():
+			return -1, ctx.Err()
+		}
+	}
+}
+
+// getObjectCount counts the objects of the bucket whose name matches the prefix
+func (s *natsKVScaler) getObjectCount(ctx context.Context) (int64, error) {
+	obs, err := s.js.ObjectStore(s.metadata.bucket)
+	if err != nil {
+		return -1, err
+	}
+
+	objects, err := obs.List(nats.Context(ctx))
+	if errors.Is(err, nats.ErrNoObjectsFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return -1, err
+	}
+
+	var count int64
+	for _, object := range objects {
+		if !object.Deleted && strings.HasPrefix(object.Name, s.metadata.keyPrefix) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// getNATSKVWatchPattern returns the key pattern to watch, a prefix made of whole tokens is filtered by the server
+func getNATSKVWatchPattern(keyPrefix string) string {
+	if keyPrefix != "" && strings.HasSuffix(keyPrefix, natsKVTokenSeparator) {
+		return keyPrefix + nats.AllKeys
+	}
+	return nats.AllKeys
+}
@@ -0,0 +1,91 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseNATSKVMetadataTestData struct {
+	metadata   map[string]string
+	isError    bool
+	authParams map[string]string
+}
+
+type natsKVMetricIdentifier struct {
+	metadataTestData *parseNATSKVMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testNATSKVMetadata = []parseNATSKVMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true, map[string]string{}},
+	// properly formed kv
+	{map[string]string{"natsServerURL": "nats://nats:4222", "bucket": "jobs", "keyPrefix": "pending.", "entryCount": "10"}, false, map[string]string{}},
+	// properly formed object store
+	{map[string]string{"natsServerURL": "nats://nats:4222", "bucket": "uploads", "bucketType": "object"}, false, map[string]string{}},
+	// server url and credentials from authParams
+	{map[string]string{"bucket": "jobs"}, false, map[string]string{"natsServerURL": "nats://nats:4222", "username": "user", "password": "secret"}},
+	// token
+	{map[string]string{"natsServerURL": "nats://nats:4222", "bucket": "jobs"}, false, map[string]string{"token": "secret"}},
+	// tls
+	{map[string]string{"natsServerURL": "tls://nats:4222", "bucket": "jobs"}, false, map[string]string{"tls": "enable", "ca": "caaa", "cert": "ceert", "key": "keey"}},
+	// no server url
+	{map[string]string{"bucket": "jobs"}, true, map[string]string{}},
+	// no bucket
+	{map[string]string{"natsServerURL": "nats://nats:4222"}, true, map[string]string{}},
+	// unknown bucketType
+	{map[string]string{"natsServerURL": "nats://nats:4222", "bucket": "jobs", "bucketType": "stream"}, true, map[string]string{}},
+	// improperly formed entryCount
+	{map[string]string{"natsServerURL": "nats://nats:4222", "bucket": "jobs", "entryCount": "AA"}, true, map[string]string{}},
+	// zero entryCount
+	{map[string]string{"natsServerURL": "nats://nats:4222", "bucket": "jobs", "entryCount": "0"}, true, map[string]string{}},
+	// username without password
+	{map[string]string{"natsServerURL": "nats://nats:4222", "bucket": "jobs"}, true, map[string]string{"username": "user"}},
+	// cert without key
+	{map[string]string{"natsServerURL": "tls://nats:4222", "bucket": "jobs"}, true, map[string]string{"tls": "enable", "cert": "ceert"}},
+	// incorrect tls value
+	{map[string]string{"natsServerURL": "tls://nats:4222", "bucket": "jobs"}, true, map[string]string{"tls": "yes"}},
+}
+
+var natsKVMetricIdentifiers = []natsKVMetricIdentifier{
+	{&testNATSKVMetadata[1], 0, "s0-nats-kv-jobs-pending-"},
+	{&testNATSKVMetadata[2], 1, "s1-nats-object-uploads"},
+}
+
+func TestNATSKVParseMetadata(t *testing.T) {
+	for idx, testData := range testNATSKVMetadata {
+		_, err := parseNATSKVMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+	}
+}
+
+func TestNATSKVGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range natsKVMetricIdentifiers {
+		meta, err := parseNATSKVMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockNATSKVScaler := natsKVScaler{metadata: meta}
+
+		metricSpec := mockNATSKVScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestGetNATSKVWatchPattern(t *testing.T) {
+	assert.Equal(t, ">", getNATSKVWatchPattern(""))
+	assert.Equal(t, "pending.>", getNATSKVWatchPattern("pending."))
+	// a partial token can't be filtered by the server
+	assert.Equal(t, ">", getNATSKVWatchPattern("pending-"))
+}
@@ -0,0 +1,269 @@
+package scalers
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+type neo4jScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *neo4jMetadata
+	driver     neo4j.Driver
+}
+
+type neo4jMetadata struct {
+	uri         string
+	username    string
+	password    string
+	database    string
+	query       string
+	targetValue float64
+	metricName  string
+	scalerIndex int
+
+	encrypted bool
+	unsafeSsl bool
+	ca        string
+}
+
+var neo4jLog = logf.Log.WithName("neo4j_scaler")
+
+// NewNeo4jScaler creates a new neo4j scaler
+func NewNeo4jScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseNeo4jMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing neo4j metadata: %s", err))
+	}
+
+	driver, err := newNeo4jDriver(meta)
+	if err != nil {
+		return nil, fmt.Errorf("error establishing neo4j connection: %s", err)
+	}
+
+	return &neo4jScaler{
+		metricType: metricType,
+		metadata:   meta,
+		driver:     driver,
+	}, nil
+}
+
+func parseNeo4jMetadata(config *ScalerConfig) (*neo4jMetadata, error) {
+	meta := neo4jMetadata{}
+
+	uri, err := GetFromAuthOrMeta(config, "uri")
+	if err != nil {
+		return nil, err
+	}
+
+	if val, ok := config.TriggerMetadata["query"]; ok && val != "" {
+		meta.query = val
+	} else {
+		return nil, fmt.Errorf("no query given")
+	}
+
+	if val, ok := config.TriggerMetadata["targetValue"]; ok {
+		targetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetValue parsing error %s", err.Error())
+		}
+		meta.targetValue = targetValue
+	} else {
+		return nil, fmt.Errorf("no targetValue given")
+	}
+
+	meta.database = config.TriggerMetadata["database"]
+
+	meta.username = config.AuthParams["username"]
+	if config.AuthParams["password"] != "" {
+		meta.password = config.AuthParams["password"]
+	} else if config.TriggerMetadata["passwordFromEnv"] != "" {
+		meta.password = config.ResolvedEnv[config.TriggerMetadata["passwordFromEnv"]]
+	}
+	if meta.username != "" && meta.password == "" {
+		return nil, errors.New("no password given")
+	}
+
+	if val, ok := config.TriggerMetadata["encrypted"]; ok {
+		encrypted, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing encrypted: %s", err)
+		}
+		meta.encrypted = encrypted
+	}
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing unsafeSsl: %s", err)
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	meta.ca = config.AuthParams["ca"]
+
+	meta.uri, err = neo4jEncryptedURI(uri, meta.encrypted || meta.unsafeSsl || meta.ca != "", meta.unsafeSsl)
+	if err != nil {
+		return nil, err
+	}
+
+	if val, ok := config.TriggerMetadata["metricName"]; ok && val != "" {
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("neo4j-%s", val))
+	} else {
+		meta.metricName = kedautil.NormalizeString("neo4j")
+	}
+	meta.scalerIndex = config.ScalerIndex
+
+	return &meta, nil
+}
+
+// neo4jEncryptedURI validates the uri and, when encryption is requested, switches it to the
+// matching secure scheme (+s verifies the server certificate, +ssc accepts self-signed ones).
+// A uri that already carries a secure scheme is left untouched.
+func neo4jEncryptedURI(uri string, encrypted, unsafeSsl bool) (string, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("error parsing uri: %s", err)
+	}
+
+	scheme := parsed.Scheme
+	base := strings.SplitN(scheme, "+", 2)[0]
+	if base != "bolt" && base != "neo4j" {
+		return "", fmt.Errorf("unsupported uri scheme %s, must be bolt or neo4j", scheme)
+	}
+
+	if !encrypted || strings.Contains(scheme, "+") {
+		return uri, nil
+	}
+
+	if unsafeSsl {
+		parsed.Scheme = base + "+ssc"
+	} else {
+		parsed.Scheme = base + "+s"
+	}
+	return parsed.String(), nil
+}
+
+func newNeo4jDriver(meta *neo4jMetadata) (neo4j.Driver, error) {
+	auth := neo4j.NoAuth()
+	if meta.username != "" {
+		auth = neo4j.BasicAuth(meta.username, meta.password, "")
+	}
+
+	driver, err := neo4j.NewDriver(meta.uri, auth, func(c *neo4j.Config) {
+		if meta.ca != "" {
+			pool := x509.NewCertPool()
+			pool.AppendCertsFromPEM([]byte(meta.ca))
+			c.RootCAs = pool
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := driver.VerifyConnectivity(); err != nil {
+		_ = driver.Close()
+		return nil, err
+	}
+	return driver, nil
+}
+
+// Close disposes of neo4j connections
+func (s *neo4jScaler) Close(context.Context) error {
+	if err := s.driver.Close(); err != nil {
+		neo4jLog.Error(err, "error closing neo4j connection")
+		return err
+	}
+	return nil
+}
+
+// IsActive returns true if the query returns a value greater than zero
+func (s *neo4jScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getQueryResult()
+	if err != nil {
+		neo4jLog.Error(err, "error inspecting neo4j")
+		return false, err
+	}
+	return value > 0, nil
+}
+
+// getQueryResult runs the cypher query in a read transaction and returns its single numeric value
+func (s *neo4jScaler) getQueryResult() (float64, error) {
+	session := s.driver.NewSession(neo4j.SessionConfig{
+		AccessMode:   neo4j.AccessModeRead,
+		DatabaseName: s.metadata.database,
+	})
+	defer session.Close()
+
+	value, err := session.ReadTransaction(func(tx neo4j.Transaction) (interface{}, error) {
+		record, err := neo4j.Single(tx.Run(s.metadata.query, nil))
+		if err != nil {
+			return nil, err
+		}
+		if len(record.Values) != 1 {
+			return nil, fmt.Errorf("query must return a single column, got %d", len(record.Values))
+		}
+		return record.Values[0], nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error running neo4j query: %s", err)
+	}
+
+	return neo4jValueToFloat(value)
+}
+
+func neo4jValueToFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case nil:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("query must return a numeric value, got %T", value)
+	}
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *neo4jScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, s.metadata.metricName),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *neo4jScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getQueryResult()
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting neo4j: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, value)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
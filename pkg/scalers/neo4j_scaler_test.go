@@ -0,0 +1,124 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+)
+
+type parseNeo4jMetadataTestData struct {
+	metadata    map[string]string
+	authParams  map[string]string
+	raisesError bool
+}
+
+type neo4jMetricIdentifier struct {
+	metadataTestData *parseNeo4jMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testNeo4jAuthParams = map[string]string{"username": "neo4j", "password": "password"}
+
+var testNeo4jMetadata = []parseNeo4jMetadataTestData{
+	// nothing passed
+	{map[string]string{}, testNeo4jAuthParams, true},
+	// properly formed
+	{map[string]string{"uri": "neo4j://neo4j:7687", "query": "MATCH (n:Job {processed: false}) RETURN count(n)", "targetValue": "10"}, testNeo4jAuthParams, false},
+	// properly formed with metricName and database
+	{map[string]string{"uri": "bolt://neo4j:7687", "query": "MATCH (n:Job) RETURN count(n)", "targetValue": "10", "database": "work", "metricName": "jobs"}, testNeo4jAuthParams, false},
+	// uri from authParams
+	{map[string]string{"query": "MATCH (n:Job) RETURN count(n)", "targetValue": "10"}, map[string]string{"uri": "neo4j://neo4j:7687"}, false},
+	// no query
+	{map[string]string{"uri": "neo4j://neo4j:7687", "targetValue": "10"}, testNeo4jAuthParams, true},
+	// invalid targetValue
+	{map[string]string{"uri": "neo4j://neo4j:7687", "query": "MATCH (n:Job) RETURN count(n)", "targetValue": "a"}, testNeo4jAuthParams, true},
+	// unsupported scheme
+	{map[string]string{"uri": "http://neo4j:7474", "query": "MATCH (n:Job) RETURN count(n)", "targetValue": "10"}, testNeo4jAuthParams, true},
+	// username without password
+	{map[string]string{"uri": "neo4j://neo4j:7687", "query": "MATCH (n:Job) RETURN count(n)", "targetValue": "10"}, map[string]string{"username": "neo4j"}, true},
+	// invalid encrypted
+	{map[string]string{"uri": "neo4j://neo4j:7687", "query": "MATCH (n:Job) RETURN count(n)", "targetValue": "10", "encrypted": "yes"}, testNeo4jAuthParams, true},
+}
+
+var neo4jMetricIdentifiers = []neo4jMetricIdentifier{
+	{&testNeo4jMetadata[1], 0, "s0-neo4j"},
+	{&testNeo4jMetadata[2], 1, "s1-neo4j-jobs"},
+}
+
+func TestParseNeo4jMetadata(t *testing.T) {
+	for idx, testData := range testNeo4jMetadata {
+		_, err := parseNeo4jMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.raisesError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if err == nil && testData.raisesError {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+	}
+}
+
+func TestNeo4jGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range neo4jMetricIdentifiers {
+		meta, err := parseNeo4jMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockNeo4jScaler := neo4jScaler{"", meta, nil}
+
+		metricSpec := mockNeo4jScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestNeo4jEncryptedURI(t *testing.T) {
+	tests := []struct {
+		uri       string
+		encrypted bool
+		unsafeSsl bool
+		expected  string
+	}{
+		{"neo4j://neo4j:7687", false, false, "neo4j://neo4j:7687"},
+		{"neo4j://neo4j:7687", true, false, "neo4j+s://neo4j:7687"},
+		{"bolt://neo4j:7687", true, true, "bolt+ssc://neo4j:7687"},
+		{"neo4j+s://neo4j:7687", true, true, "neo4j+s://neo4j:7687"},
+	}
+
+	for _, test := range tests {
+		uri, err := neo4jEncryptedURI(test.uri, test.encrypted, test.unsafeSsl)
+		if err != nil {
+			t.Errorf("Expected success but got error for %s: %s", test.uri, err)
+		}
+		if uri != test.expected {
+			t.Errorf("Expected %s but got %s", test.expected, uri)
+		}
+	}
+}
+
+func TestNeo4jValueToFloat(t *testing.T) {
+	tests := []struct {
+		value       interface{}
+		expected    float64
+		raisesError bool
+	}{
+		{int64(42), 42, false},
+		{float64(1.5), 1.5, false},
+		{nil, 0, false},
+		{"42", 0, true},
+	}
+
+	for _, test := range tests {
+		value, err := neo4jValueToFloat(test.value)
+		if err != nil && !test.raisesError {
+			t.Errorf("Expected success but got error for %v: %s", test.value, err)
+		}
+		if err == nil && test.raisesError {
+			t.Errorf("Expected error but got success for %v", test.value)
+		}
+		if value != test.expected {
+			t.Errorf("Expected %f but got %f", test.expected, value)
+		}
+	}
+}
@@ -47,12 +47,12 @@ var newrelicLog = logf.Log.WithName(fmt.Sprintf("%s_scaler", scalerName))
 func NewNewRelicScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseNewRelicMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing %s metadata: %s", scalerName, err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing %s metadata: %s", scalerName, err))
 	}
 
 	nrClient, err := newrelic.New(
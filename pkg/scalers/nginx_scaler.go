@@ -0,0 +1,251 @@
+package scalers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	defaultNginxTargetConnections = 100
+
+	nginxConnectionStateActive  = "active"
+	nginxConnectionStateReading = "reading"
+	nginxConnectionStateWriting = "writing"
+	nginxConnectionStateWaiting = "waiting"
+)
+
+var nginxLog = logf.Log.WithName("nginx_scaler")
+
+type nginxScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *nginxMetadata
+	httpClient *http.Client
+}
+
+type nginxMetadata struct {
+	statusURL             string
+	connectionState       string
+	targetConnections     int64
+	activationConnections int64
+	unsafeSsl             bool
+	scalerIndex           int
+
+	// authentication of the status page
+	username string
+	password string
+}
+
+// nginxStubStatus holds the connections reported by the stub_status module
+type nginxStubStatus struct {
+	active  int64
+	reading int64
+	writing int64
+	waiting int64
+}
+
+// NewNginxScaler creates a new nginxScaler, reading the connections of the nginx stub_status page
+func NewNginxScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseNginxMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing nginx metadata: %s", err))
+	}
+
+	return &nginxScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, meta.unsafeSsl),
+	}, nil
+}
+
+func parseNginxMetadata(config *ScalerConfig) (*nginxMetadata, error) {
+	meta := nginxMetadata{
+		targetConnections: defaultNginxTargetConnections,
+		connectionState:   nginxConnectionStateActive,
+	}
+
+	if val, ok := config.TriggerMetadata["statusURL"]; ok && val != "" {
+		if _, err := url.ParseRequestURI(val); err != nil {
+			return nil, fmt.Errorf("invalid statusURL: %s", err)
+		}
+		meta.statusURL = val
+	} else {
+		return nil, fmt.Errorf("no statusURL given")
+	}
+
+	if val, ok := config.TriggerMetadata["connectionState"]; ok && val != "" {
+		meta.connectionState = strings.TrimSpace(val)
+	}
+	switch meta.connectionState {
+	case nginxConnectionStateActive, nginxConnectionStateReading, nginxConnectionStateWriting, nginxConnectionStateWaiting:
+	default:
+		return nil, fmt.Errorf("err incorrect value for connectionState is given: %s", meta.connectionState)
+	}
+
+	if val, ok := config.TriggerMetadata["targetConnections"]; ok && val != "" {
+		targetConnections, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetConnections parsing error %s", err.Error())
+		}
+		meta.targetConnections = targetConnections
+	}
+
+	if val, ok := config.TriggerMetadata["activationConnections"]; ok && val != "" {
+		activationConnections, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationConnections parsing error %s", err.Error())
+		}
+		meta.activationConnections = activationConnections
+	}
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok && val != "" {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("unsafeSsl parsing error %s", err.Error())
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	meta.username = config.AuthParams["username"]
+	meta.password = config.AuthParams["password"]
+	if meta.username != "" && meta.password == "" {
+		return nil, errors.New("no password given")
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// parseNginxStubStatus parses the stub_status page:
+//
+//	Active connections: 291
+//	server accepts handled requests
+//	 16630948 16630948 31070465
+//	Reading: 6 Writing: 179 Waiting: 106
+func parseNginxStubStatus(body string) (*nginxStubStatus, error) {
+	status := nginxStubStatus{}
+	foundActive, foundStates := false, false
+
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "Active connections:"):
+			active, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "Active connections:")), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing the active connections: %s", err)
+			}
+			status.active = active
+			foundActive = true
+		case strings.HasPrefix(line, "Reading:"):
+			if _, err := fmt.Sscanf(line, "Reading: %d Writing: %d Waiting: %d", &status.reading, &status.writing, &status.waiting); err != nil {
+				return nil, fmt.Errorf("error parsing the connection states: %s", err)
+			}
+			foundStates = true
+		}
+	}
+	if !foundActive || !foundStates {
+		return nil, errors.New("invalid stub_status response")
+	}
+	return &status, nil
+}
+
+// getConnections returns the connections in the connectionState
+func (s *nginxScaler) getConnections(ctx context.Context) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.metadata.statusURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	if s.metadata.username != "" {
+		req.SetBasicAuth(s.metadata.username, s.metadata.password)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("nginx status returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	status, err := parseNginxStubStatus(string(body))
+	if err != nil {
+		return 0, err
+	}
+
+	switch s.metadata.connectionState {
+	case nginxConnectionStateReading:
+		return status.reading, nil
+	case nginxConnectionStateWriting:
+		return status.writing, nil
+	case nginxConnectionStateWaiting:
+		return status.waiting, nil
+	default:
+		return status.active, nil
+	}
+}
+
+// IsActive returns true if there are more connections than activationConnections
+func (s *nginxScaler) IsActive(ctx context.Context) (bool, error) {
+	connections, err := s.getConnections(ctx)
+	if err != nil {
+		nginxLog.Error(err, "error getting nginx connections")
+		return false, err
+	}
+
+	return connections > s.metadata.activationConnections, nil
+}
+
+func (s *nginxScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *nginxScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("nginx-%s-connections", s.metadata.connectionState))),
+		},
+		Target: GetMetricTarget(s.metricType, s.metadata.targetConnections),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the connections in the connectionState
+func (s *nginxScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	connections, err := s.getConnections(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting nginx connections: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(connections))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,137 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testNginxStubStatus = `Active connections: 291
+server accepts handled requests
+ 16630948 16630948 31070465
+Reading: 6 Writing: 179 Waiting: 106
+`
+
+type parseNginxMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type nginxMetricIdentifier struct {
+	metadataTestData *parseNginxMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testNginxMetadata = []parseNginxMetadataTestData{
+	// only required properties
+	{map[string]string{"statusURL": "http://nginx:8080/nginx_status"}, map[string]string{}, false},
+	// all properties
+	{map[string]string{"statusURL": "https://nginx:8443/nginx_status", "connectionState": "writing", "targetConnections": "50", "activationConnections": "5", "unsafeSsl": "true"},
+		map[string]string{"username": "admin", "password": "secret"}, false},
+	// missing password
+	{map[string]string{"statusURL": "http://nginx:8080/nginx_status"}, map[string]string{"username": "admin"}, true},
+	// missing statusURL
+	{map[string]string{}, map[string]string{}, true},
+	// invalid statusURL
+	{map[string]string{"statusURL": "nginx_status"}, map[string]string{}, true},
+	// invalid connectionState
+	{map[string]string{"statusURL": "http://nginx:8080/nginx_status", "connectionState": "idle"}, map[string]string{}, true},
+	// invalid counts
+	{map[string]string{"statusURL": "http://nginx:8080/nginx_status", "targetConnections": "a"}, map[string]string{}, true},
+	{map[string]string{"statusURL": "http://nginx:8080/nginx_status", "activationConnections": "a"}, map[string]string{}, true},
+	// invalid unsafeSsl
+	{map[string]string{"statusURL": "http://nginx:8080/nginx_status", "unsafeSsl": "a"}, map[string]string{}, true},
+}
+
+var nginxMetricIdentifiers = []nginxMetricIdentifier{
+	{&testNginxMetadata[0], 0, "s0-nginx-active-connections"},
+	{&testNginxMetadata[1], 1, "s1-nginx-writing-connections"},
+}
+
+func TestNginxParseMetadata(t *testing.T) {
+	for _, testData := range testNginxMetadata {
+		_, err := parseNginxMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestNginxGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range nginxMetricIdentifiers {
+		meta, err := parseNginxMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockNginxScaler := nginxScaler{metadata: meta}
+
+		metricSpec := mockNginxScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestParseNginxStubStatus(t *testing.T) {
+	status, err := parseNginxStubStatus(testNginxStubStatus)
+	assert.NoError(t, err)
+	assert.Equal(t, nginxStubStatus{active: 291, reading: 6, writing: 179, waiting: 106}, *status)
+
+	_, err = parseNginxStubStatus("<html>not found</html>")
+	assert.Error(t, err)
+
+	_, err = parseNginxStubStatus("Active connections: 1\nReading: a Writing: 0 Waiting: 0\n")
+	assert.Error(t, err)
+}
+
+func TestNginxGetConnections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/nginx_status" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, testNginxStubStatus)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		metadata    map[string]string
+		connections int64
+		isActive    bool
+		isError     bool
+	}{
+		{map[string]string{"statusURL": server.URL + "/nginx_status"}, 291, true, false},
+		{map[string]string{"statusURL": server.URL + "/nginx_status", "connectionState": "reading"}, 6, true, false},
+		{map[string]string{"statusURL": server.URL + "/nginx_status", "connectionState": "writing", "activationConnections": "179"}, 179, false, false},
+		{map[string]string{"statusURL": server.URL + "/nginx_status", "connectionState": "waiting"}, 106, true, false},
+		{map[string]string{"statusURL": server.URL + "/status"}, 0, false, true},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parseNginxMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, AuthParams: map[string]string{}})
+		assert.NoError(t, err)
+		s := nginxScaler{metadata: meta, httpClient: http.DefaultClient}
+
+		connections, err := s.getConnections(context.Background())
+		if testCase.isError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.connections, connections, "metadata %v", testCase.metadata)
+
+		isActive, err := s.IsActive(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.isActive, isActive, "metadata %v", testCase.metadata)
+	}
+}
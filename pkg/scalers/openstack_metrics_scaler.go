@@ -66,19 +66,19 @@ func NewOpenstackMetricScaler(ctx context.Context, config *ScalerConfig) (Scaler
 
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	openstackMetricMetadata, err := parseOpenstackMetricMetadata(config)
 
 	if err != nil {
-		return nil, fmt.Errorf("error parsing openstack Metric metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing openstack Metric metadata: %s", err))
 	}
 
 	authMetadata, err := parseOpenstackMetricAuthenticationMetadata(config)
 
 	if err != nil {
-		return nil, fmt.Errorf("error parsing openstack metric authentication metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing openstack metric authentication metadata: %s", err))
 	}
 
 	// User choose the "application_credentials" authentication method
@@ -183,19 +183,19 @@ func NewOpenstackSwiftScaler(ctx context.Context, config *ScalerConfig) (Scaler,
 
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	openstackSwiftMetadata, err := parseOpenstackSwiftMetadata(config)
 
 	if err != nil {
-		return nil, fmt.Errorf("error parsing swift metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing swift metadata: %s", err))
 	}
 
 	authMetadata, err := parseOpenstackSwiftAuthenticationMetadata(config)
 
 	if err != nil {
-		return nil, fmt.Errorf("error parsing swift authentication metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing swift authentication metadata: %s", err))
 	}
 
 	// User chose the "application_credentials" authentication method
@@ -0,0 +1,208 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kedacore/keda/v2/pkg/otlp"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	defaultOTLPMaxAge = 5 * time.Minute
+)
+
+type otlpScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *otlpMetadata
+	httpClient *http.Client
+}
+
+type otlpMetadata struct {
+	receiverURL string
+	metricName  string
+	attributes  map[string]string
+	maxAge      time.Duration
+	targetValue float64
+	scalerIndex int
+}
+
+var otlpLog = logf.Log.WithName("otlp_scaler")
+
+// NewOTLPScaler creates a new otlpScaler, scaling on the latest values of a metric pushed to the OTLP receiver of the
+// operator, which is enabled with --otlp-receiver-bind-address
+func NewOTLPScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseOTLPMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing otlp metadata: %s", err))
+	}
+
+	return &otlpScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, false),
+	}, nil
+}
+
+func parseOTLPMetadata(config *ScalerConfig) (*otlpMetadata, error) {
+	meta := otlpMetadata{
+		attributes: map[string]string{},
+		maxAge:     defaultOTLPMaxAge,
+	}
+
+	if val, ok := config.TriggerMetadata["receiverURL"]; ok && val != "" {
+		if _, err := url.ParseRequestURI(val); err != nil {
+			return nil, fmt.Errorf("invalid receiverURL: %s", err)
+		}
+		meta.receiverURL = strings.TrimSuffix(val, "/")
+	} else {
+		return nil, fmt.Errorf("no receiverURL given in metadata")
+	}
+
+	if val, ok := config.TriggerMetadata["metricName"]; ok && val != "" {
+		meta.metricName = val
+	} else {
+		return nil, fmt.Errorf("no metricName given in metadata")
+	}
+
+	if val, ok := config.TriggerMetadata["attributes"]; ok && val != "" {
+		for _, attribute := range splitAndTrim(val) {
+			kv := strings.SplitN(attribute, "=", 2)
+			if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+				return nil, fmt.Errorf("invalid attribute %q, expected key=value", attribute)
+			}
+			meta.attributes[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+
+	if val, ok := config.TriggerMetadata["maxAge"]; ok && val != "" {
+		maxAge, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("maxAge parsing error %s", err.Error())
+		}
+		if maxAge <= 0 || time.Duration(maxAge)*time.Second > otlp.SeriesRetention {
+			return nil, fmt.Errorf("maxAge must be between 1 and %d seconds", int(otlp.SeriesRetention.Seconds()))
+		}
+		meta.maxAge = time.Duration(maxAge) * time.Second
+	}
+
+	if val, ok := config.TriggerMetadata["targetValue"]; ok && val != "" {
+		targetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetValue parsing error %s", err.Error())
+		}
+		meta.targetValue = targetValue
+	} else {
+		return nil, fmt.Errorf("no targetValue given in metadata")
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// getMetricValue returns the sum of the latest values of the series of the metric matching the attributes
+func (s *otlpScaler) getMetricValue(ctx context.Context) (float64, error) {
+	value, series, err := queryPushedMetric(ctx, s.httpClient, s.metadata.receiverURL, s.metadata.metricName, s.metadata.attributes, s.metadata.maxAge, "")
+	if err != nil {
+		return 0, err
+	}
+	if series == 0 {
+		otlpLog.V(1).Info("No recent data point of the metric", "metricName", s.metadata.metricName, "attributes", s.metadata.attributes)
+	}
+	return value, nil
+}
+
+// queryPushedMetric queries the latest values of a metric pushed to the OTLP receiver or the StatsD listener of the
+// operator, it returns the sum of the values of the series having the attributes and the number of these series
+func queryPushedMetric(ctx context.Context, httpClient *http.Client, baseURL string, name string, attributes map[string]string, maxAge time.Duration, token string) (float64, int, error) {
+	query := url.Values{}
+	query.Set("name", name)
+	query.Set("maxAge", maxAge.String())
+	for key, value := range attributes {
+		query.Add("attribute", fmt.Sprintf("%s=%s", key, value))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s%s?%s", baseURL, otlp.QueryPath, query.Encode()), nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	r, err := httpClient.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(r.Body)
+		return 0, 0, fmt.Errorf("%s returned %d, response: %s", baseURL, r.StatusCode, b)
+	}
+
+	var response otlp.QueryResponse
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		return 0, 0, fmt.Errorf("error decoding %s response: %s", baseURL, err)
+	}
+	return response.Value, response.Series, nil
+}
+
+// Close does nothing in case of otlpScaler
+func (s *otlpScaler) Close(context.Context) error {
+	return nil
+}
+
+// IsActive returns true if the latest value of the metric is greater than 0
+func (s *otlpScaler) IsActive(ctx context.Context) (bool, error) {
+	v, err := s.getMetricValue(ctx)
+	if err != nil {
+		otlpLog.Error(err, "error getting otlp metric value")
+		return false, err
+	}
+
+	return v > 0.0, nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *otlpScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("otlp-%s", s.metadata.metricName))),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the latest value of the metric
+func (s *otlpScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	val, err := s.getMetricValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error requesting otlp receiver: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, val)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,123 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kedacore/keda/v2/pkg/otlp"
+)
+
+type parseOTLPMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+type otlpMetricIdentifier struct {
+	metadataTestData *parseOTLPMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testOTLPMetadata = []parseOTLPMetadataTestData{
+	// only required properties
+	{map[string]string{"receiverURL": "http://keda-operator.keda:4318", "metricName": "queue_depth", "targetValue": "10"}, false},
+	// attributes and maxAge
+	{map[string]string{"receiverURL": "http://keda-operator.keda:4318/", "metricName": "app.queue.depth", "targetValue": "10", "attributes": "queue=orders, service.name=worker", "maxAge": "60"}, false},
+	// missing receiverURL
+	{map[string]string{"metricName": "queue_depth", "targetValue": "10"}, true},
+	// invalid receiverURL
+	{map[string]string{"receiverURL": "keda-operator", "metricName": "queue_depth", "targetValue": "10"}, true},
+	// missing metricName
+	{map[string]string{"receiverURL": "http://keda-operator.keda:4318", "targetValue": "10"}, true},
+	// invalid attributes
+	{map[string]string{"receiverURL": "http://keda-operator.keda:4318", "metricName": "queue_depth", "targetValue": "10", "attributes": "queue"}, true},
+	{map[string]string{"receiverURL": "http://keda-operator.keda:4318", "metricName": "queue_depth", "targetValue": "10", "attributes": "=orders"}, true},
+	// invalid maxAge
+	{map[string]string{"receiverURL": "http://keda-operator.keda:4318", "metricName": "queue_depth", "targetValue": "10", "maxAge": "a"}, true},
+	{map[string]string{"receiverURL": "http://keda-operator.keda:4318", "metricName": "queue_depth", "targetValue": "10", "maxAge": "0"}, true},
+	{map[string]string{"receiverURL": "http://keda-operator.keda:4318", "metricName": "queue_depth", "targetValue": "10", "maxAge": "7200"}, true},
+	// missing targetValue
+	{map[string]string{"receiverURL": "http://keda-operator.keda:4318", "metricName": "queue_depth"}, true},
+	// invalid targetValue
+	{map[string]string{"receiverURL": "http://keda-operator.keda:4318", "metricName": "queue_depth", "targetValue": "a"}, true},
+}
+
+var otlpMetricIdentifiers = []otlpMetricIdentifier{
+	{&testOTLPMetadata[0], 0, "s0-otlp-queue_depth"},
+	{&testOTLPMetadata[1], 1, "s1-otlp-app-queue-depth"},
+}
+
+func TestOTLPParseMetadata(t *testing.T) {
+	for _, testData := range testOTLPMetadata {
+		_, err := parseOTLPMetadata(&ScalerConfig{TriggerMetadata: testData.metadata})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestOTLPGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range otlpMetricIdentifiers {
+		meta, err := parseOTLPMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockOTLPScaler := otlpScaler{metadata: meta}
+
+		metricSpec := mockOTLPScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestOTLPGetMetricValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, otlp.QueryPath, r.URL.Path)
+		assert.Equal(t, "1m0s", r.URL.Query().Get("maxAge"))
+		switch r.URL.Query().Get("name") {
+		case "queue_depth":
+			assert.ElementsMatch(t, []string{"queue=orders", "service.name=worker"}, r.URL.Query()["attribute"])
+			fmt.Fprint(w, `{"value":7.5,"series":2}`)
+		case "unknown":
+			fmt.Fprint(w, `{"value":0,"series":0}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		metricName string
+		value      float64
+		isError    bool
+	}{
+		{"queue_depth", 7.5, false},
+		{"unknown", 0, false},
+		{"invalid", 0, true},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parseOTLPMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"receiverURL": server.URL, "metricName": testCase.metricName,
+			"targetValue": "10", "attributes": "queue=orders,service.name=worker", "maxAge": "60"}})
+		assert.NoError(t, err)
+		s := otlpScaler{metadata: meta, httpClient: http.DefaultClient}
+
+		value, err := s.getMetricValue(context.Background())
+		if testCase.isError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.value, value)
+	}
+}
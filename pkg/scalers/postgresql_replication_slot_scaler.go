@@ -0,0 +1,188 @@
+package scalers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	// defaultPostgreSQLSlotTargetLagBytes is the size of a WAL segment
+	defaultPostgreSQLSlotTargetLagBytes = 16 * 1024 * 1024
+
+	postgreSQLSlotLagTypeConfirmedFlush = "confirmedFlush"
+	postgreSQLSlotLagTypeReplay         = "replay"
+)
+
+const (
+	// postgreSQLSlotConfirmedFlushLagQuery returns the WAL bytes written since the position the consumer of the slot
+	// confirmed, the restart position of the physical slots which don't confirm any
+	postgreSQLSlotConfirmedFlushLagQuery = `SELECT COALESCE(pg_wal_lsn_diff(pg_current_wal_lsn(), COALESCE(confirmed_flush_lsn, restart_lsn)), 0)
+FROM pg_replication_slots WHERE slot_name = $1`
+	// postgreSQLSlotReplayLagQuery returns the WAL bytes written since the position the standby streaming from the
+	// slot replayed, the confirmed position when no standby is connected
+	postgreSQLSlotReplayLagQuery = `SELECT COALESCE(pg_wal_lsn_diff(pg_current_wal_lsn(), COALESCE(r.replay_lsn, s.confirmed_flush_lsn, s.restart_lsn)), 0)
+FROM pg_replication_slots s LEFT JOIN pg_stat_replication r ON r.pid = s.active_pid WHERE s.slot_name = $1`
+)
+
+var postgreSQLSlotLog = logf.Log.WithName("postgresql_replication_slot_scaler")
+
+type postgreSQLSlotScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *postgreSQLSlotMetadata
+	connection *sql.DB
+}
+
+type postgreSQLSlotMetadata struct {
+	connection         string
+	slotName           string
+	lagType            string
+	targetLagBytes     int64
+	activationLagBytes int64
+	scalerIndex        int
+}
+
+// NewPostgreSQLReplicationSlotScaler creates a new postgreSQLSlotScaler, reading the lag in bytes of the consumer
+// of a replication slot
+func NewPostgreSQLReplicationSlotScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parsePostgreSQLSlotMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing postgreSQL replication slot metadata: %s", err))
+	}
+
+	conn, err := getConnection(meta.connection)
+	if err != nil {
+		return nil, fmt.Errorf("error establishing postgreSQL connection: %s", err)
+	}
+	return &postgreSQLSlotScaler{
+		metricType: metricType,
+		metadata:   meta,
+		connection: conn,
+	}, nil
+}
+
+func parsePostgreSQLSlotMetadata(config *ScalerConfig) (*postgreSQLSlotMetadata, error) {
+	meta := postgreSQLSlotMetadata{
+		lagType:        postgreSQLSlotLagTypeConfirmedFlush,
+		targetLagBytes: defaultPostgreSQLSlotTargetLagBytes,
+	}
+
+	if val, ok := config.TriggerMetadata["slotName"]; ok && val != "" {
+		meta.slotName = val
+	} else {
+		return nil, errors.New("no slotName given")
+	}
+
+	if val, ok := config.TriggerMetadata["lagType"]; ok && val != "" {
+		meta.lagType = strings.TrimSpace(val)
+	}
+	switch meta.lagType {
+	case postgreSQLSlotLagTypeConfirmedFlush, postgreSQLSlotLagTypeReplay:
+	default:
+		return nil, fmt.Errorf("err incorrect value for lagType is given: %s", meta.lagType)
+	}
+
+	if val, ok := config.TriggerMetadata["targetLagBytes"]; ok && val != "" {
+		targetLagBytes, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetLagBytes parsing error %s", err.Error())
+		}
+		meta.targetLagBytes = targetLagBytes
+	}
+
+	if val, ok := config.TriggerMetadata["activationLagBytes"]; ok && val != "" {
+		activationLagBytes, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationLagBytes parsing error %s", err.Error())
+		}
+		meta.activationLagBytes = activationLagBytes
+	}
+
+	connection, err := parsePostgreSQLConnection(config)
+	if err != nil {
+		return nil, err
+	}
+	meta.connection = connection
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// getLagBytes returns the lag in bytes of the consumer of the slot
+func (s *postgreSQLSlotScaler) getLagBytes(ctx context.Context) (int64, error) {
+	query := postgreSQLSlotConfirmedFlushLagQuery
+	if s.metadata.lagType == postgreSQLSlotLagTypeReplay {
+		query = postgreSQLSlotReplayLagQuery
+	}
+
+	var lag float64
+	err := s.connection.QueryRowContext(ctx, query, s.metadata.slotName).Scan(&lag)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("replication slot %s not found", s.metadata.slotName)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not query postgreSQL: %s", err)
+	}
+	return int64(lag), nil
+}
+
+// IsActive returns true if the lag is over activationLagBytes
+func (s *postgreSQLSlotScaler) IsActive(ctx context.Context) (bool, error) {
+	lag, err := s.getLagBytes(ctx)
+	if err != nil {
+		postgreSQLSlotLog.Error(err, "error getting replication slot lag")
+		return false, err
+	}
+
+	return lag > s.metadata.activationLagBytes, nil
+}
+
+// Close closes the connection to PostgreSQL
+func (s *postgreSQLSlotScaler) Close(context.Context) error {
+	if err := s.connection.Close(); err != nil {
+		postgreSQLSlotLog.Error(err, "error closing postgreSQL connection")
+		return err
+	}
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *postgreSQLSlotScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("postgresql-slot-%s", s.metadata.slotName))),
+		},
+		Target: GetMetricTarget(s.metricType, s.metadata.targetLagBytes),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the lag in bytes of the consumer of the slot
+func (s *postgreSQLSlotScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	lag, err := s.getLagBytes(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting replication slot lag: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(lag))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -36,15 +36,15 @@ var postgreSQLLog = logf.Log.WithName("postgreSQL_scaler")
 func NewPostgreSQLScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parsePostgreSQLMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing postgreSQL metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing postgreSQL metadata: %s", err))
 	}
 
-	conn, err := getConnection(meta)
+	conn, err := getConnection(meta.connection)
 	if err != nil {
 		return nil, fmt.Errorf("error establishing postgreSQL connection: %s", err)
 	}
@@ -74,35 +74,52 @@ func parsePostgreSQLMetadata(config *ScalerConfig) (*postgreSQLMetadata, error)
 		return nil, fmt.Errorf("no targetQueryValue given")
 	}
 
+	connection, err := parsePostgreSQLConnection(config)
+	if err != nil {
+		return nil, err
+	}
+	meta.connection = connection
+
+	if val, ok := config.TriggerMetadata["metricName"]; ok {
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("postgresql-%s", val))
+	} else {
+		meta.metricName = kedautil.NormalizeString("postgresql")
+	}
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// parsePostgreSQLConnection returns the connection string of the connection parameters, given as a whole or by parts
+func parsePostgreSQLConnection(config *ScalerConfig) (string, error) {
 	switch {
 	case config.AuthParams["connection"] != "":
-		meta.connection = config.AuthParams["connection"]
+		return config.AuthParams["connection"], nil
 	case config.TriggerMetadata["connectionFromEnv"] != "":
-		meta.connection = config.ResolvedEnv[config.TriggerMetadata["connectionFromEnv"]]
+		return config.ResolvedEnv[config.TriggerMetadata["connectionFromEnv"]], nil
 	default:
 		host, err := GetFromAuthOrMeta(config, "host")
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 
 		port, err := GetFromAuthOrMeta(config, "port")
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 
 		userName, err := GetFromAuthOrMeta(config, "userName")
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 
 		dbName, err := GetFromAuthOrMeta(config, "dbName")
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 
 		sslmode, err := GetFromAuthOrMeta(config, "sslmode")
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 
 		var password string
@@ -112,7 +129,7 @@ func parsePostgreSQLMetadata(config *ScalerConfig) (*postgreSQLMetadata, error)
 			password = config.ResolvedEnv[config.TriggerMetadata["passwordFromEnv"]]
 		}
 
-		meta.connection = fmt.Sprintf(
+		return fmt.Sprintf(
 			"host=%s port=%s user=%s dbname=%s sslmode=%s password=%s",
 			host,
 			port,
@@ -120,20 +137,12 @@ func parsePostgreSQLMetadata(config *ScalerConfig) (*postgreSQLMetadata, error)
 			dbName,
 			sslmode,
 			password,
-		)
-	}
-
-	if val, ok := config.TriggerMetadata["metricName"]; ok {
-		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("postgresql-%s", val))
-	} else {
-		meta.metricName = kedautil.NormalizeString("postgresql")
+		), nil
 	}
-	meta.scalerIndex = config.ScalerIndex
-	return &meta, nil
 }
 
-func getConnection(meta *postgreSQLMetadata) (*sql.DB, error) {
-	db, err := sql.Open("postgres", meta.connection)
+func getConnection(connection string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", connection)
 	if err != nil {
 		postgreSQLLog.Error(err, fmt.Sprintf("Found error opening postgreSQL: %s", err))
 		return nil, err
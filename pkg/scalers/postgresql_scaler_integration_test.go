@@ -0,0 +1,45 @@
+//go:build integration
+// +build integration
+
+package scalers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgreSQLScalerIntegration(t *testing.T) {
+	container := startIntegrationContainer(t, "postgres:14-alpine", 5432, []string{"POSTGRES_PASSWORD=keda"})
+	connection := fmt.Sprintf("postgresql://postgres:keda@%s/postgres?sslmode=disable", container.address())
+	db, err := sql.Open("postgres", connection)
+	require.NoError(t, err)
+	defer db.Close()
+	waitForIntegration(t, "postgresql", func(ctx context.Context) error {
+		_, err := db.ExecContext(ctx, "SELECT 1")
+		return err
+	})
+
+	ctx := context.Background()
+	_, err = db.ExecContext(ctx, "CREATE TABLE jobs (id serial PRIMARY KEY, done boolean NOT NULL)")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "INSERT INTO jobs (done) VALUES (false), (false), (true)")
+	require.NoError(t, err)
+
+	scaler, err := NewPostgreSQLScaler(&ScalerConfig{
+		TriggerMetadata: map[string]string{"query": "SELECT COUNT(*) FROM jobs WHERE NOT done", "targetQueryValue": "1"},
+		AuthParams:      map[string]string{"connection": connection},
+		ResolvedEnv:     map[string]string{},
+	})
+	require.NoError(t, err)
+	defer scaler.Close(ctx)
+
+	assertIntegrationMetric(t, scaler, 2)
+
+	_, err = db.ExecContext(ctx, "UPDATE jobs SET done = true")
+	require.NoError(t, err)
+	assertIntegrationMetric(t, scaler, 0)
+}
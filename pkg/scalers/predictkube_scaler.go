@@ -143,7 +143,7 @@ func NewPredictKubeScaler(ctx context.Context, config *ScalerConfig) (*PredictKu
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
 		predictKubeLog.Error(err, "error getting scaler metric type")
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	s.metricType = metricType
@@ -151,7 +151,7 @@ func NewPredictKubeScaler(ctx context.Context, config *ScalerConfig) (*PredictKu
 	meta, err := parsePredictKubeMetadata(config)
 	if err != nil {
 		predictKubeLog.Error(err, "error parsing PredictKube metadata")
-		return nil, fmt.Errorf("error parsing PredictKube metadata: %3s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing PredictKube metadata: %3s", err))
 	}
 
 	s.metadata = meta
@@ -0,0 +1,225 @@
+package scalers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"unicode"
+)
+
+// errPromDivisionByZero is returned by the expressions dividing by zero, e.g. backlog / rate while idle
+var errPromDivisionByZero = errors.New("division by zero")
+
+// promExpression is a node of the arithmetic expression combining the values of the named queries of a trigger,
+// e.g. (backlog / rate) * 1.2
+type promExpression interface {
+	evaluate(values map[string]float64) (float64, error)
+	// queryNames adds the names of the queries referenced by the node to names
+	queryNames(names map[string]bool)
+}
+
+type promNumber float64
+
+func (n promNumber) evaluate(map[string]float64) (float64, error) {
+	return float64(n), nil
+}
+
+func (n promNumber) queryNames(map[string]bool) {}
+
+type promQueryReference string
+
+func (r promQueryReference) evaluate(values map[string]float64) (float64, error) {
+	value, ok := values[string(r)]
+	if !ok {
+		return 0, fmt.Errorf("no value for query %s", string(r))
+	}
+	return value, nil
+}
+
+func (r promQueryReference) queryNames(names map[string]bool) {
+	names[string(r)] = true
+}
+
+type promNegation struct {
+	operand promExpression
+}
+
+func (n promNegation) evaluate(values map[string]float64) (float64, error) {
+	value, err := n.operand.evaluate(values)
+	if err != nil {
+		return 0, err
+	}
+	return -value, nil
+}
+
+func (n promNegation) queryNames(names map[string]bool) {
+	n.operand.queryNames(names)
+}
+
+type promBinaryExpression struct {
+	operator rune
+	left     promExpression
+	right    promExpression
+}
+
+func (e promBinaryExpression) evaluate(values map[string]float64) (float64, error) {
+	left, err := e.left.evaluate(values)
+	if err != nil {
+		return 0, err
+	}
+	right, err := e.right.evaluate(values)
+	if err != nil {
+		return 0, err
+	}
+
+	switch e.operator {
+	case '+':
+		return left + right, nil
+	case '-':
+		return left - right, nil
+	case '*':
+		return left * right, nil
+	default:
+		if right == 0 {
+			return 0, errPromDivisionByZero
+		}
+		return left / right, nil
+	}
+}
+
+func (e promBinaryExpression) queryNames(names map[string]bool) {
+	e.left.queryNames(names)
+	e.right.queryNames(names)
+}
+
+// promExpressionParser is a recursive descent parser of the expressions:
+//
+//	expression = term { ("+" | "-") term }
+//	term       = unary { ("*" | "/") unary }
+//	unary      = "-" unary | primary
+//	primary    = number | name | "(" expression ")"
+type promExpressionParser struct {
+	input []rune
+	pos   int
+}
+
+// parsePromExpression parses the arithmetic expression of the named queries
+func parsePromExpression(input string) (promExpression, error) {
+	p := &promExpressionParser{input: []rune(input)}
+	expression, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != 0 {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.peek(), p.pos)
+	}
+	return expression, nil
+}
+
+// peek returns the next rune after the spaces, 0 at the end of the input
+func (p *promExpressionParser) peek() rune {
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+	if p.pos == len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *promExpressionParser) parseExpression() (promExpression, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for operator := p.peek(); operator == '+' || operator == '-'; operator = p.peek() {
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = promBinaryExpression{operator: operator, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *promExpressionParser) parseTerm() (promExpression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for operator := p.peek(); operator == '*' || operator == '/'; operator = p.peek() {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = promBinaryExpression{operator: operator, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *promExpressionParser) parseUnary() (promExpression, error) {
+	if p.peek() == '-' {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return promNegation{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *promExpressionParser) parsePrimary() (promExpression, error) {
+	next := p.peek()
+	switch {
+	case next == 0:
+		return nil, errors.New("unexpected end of expression")
+	case next == '(':
+		p.pos++
+		expression, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("missing ) at position %d", p.pos)
+		}
+		p.pos++
+		return expression, nil
+	case unicode.IsDigit(next) || next == '.':
+		start := p.pos
+		for p.pos < len(p.input) && (unicode.IsDigit(p.input[p.pos]) || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		value, err := strconv.ParseFloat(string(p.input[start:p.pos]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s at position %d", string(p.input[start:p.pos]), start)
+		}
+		return promNumber(value), nil
+	case isPromQueryNameRune(next, true):
+		start := p.pos
+		for p.pos < len(p.input) && isPromQueryNameRune(p.input[p.pos], p.pos == start) {
+			p.pos++
+		}
+		return promQueryReference(p.input[start:p.pos]), nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", next, p.pos)
+	}
+}
+
+// isPromQueryNameRune returns whether r is valid in the name of a query, names are made of letters, digits and
+// underscores and don't start with a digit
+func isPromQueryNameRune(r rune, first bool) bool {
+	return r == '_' || unicode.IsLetter(r) || (!first && unicode.IsDigit(r))
+}
+
+// isValidPromQueryName returns whether name can be referenced by the expressions
+func isValidPromQueryName(name string) bool {
+	for i, r := range name {
+		if !isPromQueryNameRune(r, i == 0) {
+			return false
+		}
+	}
+	return name != ""
+}
@@ -0,0 +1,66 @@
+package scalers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePromExpression(t *testing.T) {
+	values := map[string]float64{"backlog": 120, "rate": 40, "error_rate_5m": 0}
+
+	testCases := []struct {
+		expression string
+		expected   float64
+		isError    bool
+	}{
+		{"backlog", 120, false},
+		{"(backlog / rate) * 1.2", 3.6, false},
+		{"backlog / rate * 1.2", 3.6, false},
+		{"backlog - rate - 20", 60, false},
+		{"backlog + rate * 2", 200, false},
+		{"-backlog + 2 * -(rate - 50)", -100, false},
+		{"  backlog/rate  ", 3, false},
+		{"backlog / error_rate_5m", 0, true},
+		{"backlog / missing", 0, true},
+		{"", 0, true},
+		{"backlog +", 0, true},
+		{"(backlog / rate", 0, true},
+		{"backlog rate", 0, true},
+		{"backlog % rate", 0, true},
+		{"1.2.3 * backlog", 0, true},
+	}
+
+	for _, testCase := range testCases {
+		expression, err := parsePromExpression(testCase.expression)
+		if err == nil {
+			var value float64
+			value, err = expression.evaluate(values)
+			if err == nil {
+				assert.InDelta(t, testCase.expected, value, 1e-9, "expression %s", testCase.expression)
+			}
+		}
+		if testCase.isError {
+			assert.Error(t, err, "expression %s", testCase.expression)
+		} else {
+			assert.NoError(t, err, "expression %s", testCase.expression)
+		}
+	}
+}
+
+func TestPromExpressionQueryNames(t *testing.T) {
+	expression, err := parsePromExpression("(backlog + retries) / rate * 1.2 - backlog")
+	assert.NoError(t, err)
+
+	names := map[string]bool{}
+	expression.queryNames(names)
+	assert.Equal(t, map[string]bool{"backlog": true, "retries": true, "rate": true}, names)
+}
+
+func TestIsValidPromQueryName(t *testing.T) {
+	assert.True(t, isValidPromQueryName("backlog"))
+	assert.True(t, isValidPromQueryName("_rate_5m"))
+	assert.False(t, isValidPromQueryName(""))
+	assert.False(t, isValidPromQueryName("5m_rate"))
+	assert.False(t, isValidPromQueryName("rate-5m"))
+}
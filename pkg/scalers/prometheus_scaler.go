@@ -3,19 +3,29 @@ package scalers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	url_pkg "net/url"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
 	"k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/metrics/pkg/apis/external_metrics"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	"github.com/kedacore/keda/v2/pkg/scalers/authentication"
+	"github.com/kedacore/keda/v2/pkg/scalers/azure"
 	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
@@ -23,6 +33,9 @@ const (
 	promServerAddress    = "serverAddress"
 	promMetricName       = "metricName"
 	promQuery            = "query"
+	promNamedQueryPrefix = "query."
+	promQueryExpression  = "queryExpression"
+	promDivisionByZero   = "divisionByZeroValue"
 	promThreshold        = "threshold"
 	promNamespace        = "namespace"
 	promCortexScopeOrgID = "cortexOrgID"
@@ -30,6 +43,13 @@ const (
 	ignoreNullValues     = "ignoreNullValues"
 )
 
+const (
+	// promAwsService is the SigV4 service name of Amazon Managed Service for Prometheus
+	promAwsService = "aps"
+	// promAzureResource is the AAD resource of Azure Monitor managed service for Prometheus
+	promAzureResource = "https://prometheus.monitor.azure.com"
+)
+
 var (
 	defaultIgnoreNullValues = true
 )
@@ -38,6 +58,7 @@ type prometheusScaler struct {
 	metricType v2beta2.MetricTargetType
 	metadata   *prometheusMetadata
 	httpClient *http.Client
+	awsSigner  *v4.Signer
 }
 
 type prometheusMetadata struct {
@@ -54,6 +75,20 @@ type prometheusMetadata struct {
 	// change to false/f if can not accept prometheus return null values
 	// https://github.com/kedacore/keda/issues/3065
 	ignoreNullValues bool
+	// mTLS with the operator's SPIFFE identity, enabled through the spiffe pod identity provider
+	enableSpiffe   bool
+	spiffeServerID string
+	// SigV4 signing of the queries, enabled by awsRegion for Amazon Managed Service for Prometheus
+	awsRegion        string
+	awsAuthorization awsAuthorizationMetadata
+	// AAD tokens of the azure pod identities, for Azure Monitor managed service for Prometheus
+	azurePodIdentity kedav1alpha1.AuthPodIdentity
+
+	// named queries combined by queryExpression, instead of query
+	namedQueries    map[string]string
+	queryExpression promExpression
+	// divisionByZeroValue is the value of queryExpression when it divides by zero, it's an error if nil
+	divisionByZeroValue *float64
 }
 
 type promQueryResult struct {
@@ -71,18 +106,18 @@ type promQueryResult struct {
 var prometheusLog = logf.Log.WithName("prometheus_scaler")
 
 // NewPrometheusScaler creates a new prometheusScaler
-func NewPrometheusScaler(config *ScalerConfig) (Scaler, error) {
+func NewPrometheusScaler(ctx context.Context, config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parsePrometheusMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing prometheus metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing prometheus metadata: %s", err))
 	}
 
-	httpClient := kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, false)
+	httpClient := createHTTPClient(config, config.GlobalHTTPTimeout, false)
 
 	if meta.prometheusAuth != nil && (meta.prometheusAuth.CA != "" || meta.prometheusAuth.EnableTLS) {
 		// create http.RoundTripper with auth settings from ScalerConfig
@@ -95,13 +130,44 @@ func NewPrometheusScaler(config *ScalerConfig) (Scaler, error) {
 		}
 	}
 
+	if meta.enableSpiffe {
+		tlsConfig, err := authentication.NewSpiffeTLSConfig(ctx, meta.spiffeServerID)
+		if err != nil {
+			return nil, err
+		}
+		httpClient.Transport = &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment}
+	}
+
+	var awsSigner *v4.Signer
+	if meta.awsRegion != "" {
+		awsSigner = newPrometheusAwsSigner(meta.awsRegion, meta.awsAuthorization)
+	}
+
 	return &prometheusScaler{
 		metricType: metricType,
 		metadata:   meta,
 		httpClient: httpClient,
+		awsSigner:  awsSigner,
 	}, nil
 }
 
+func newPrometheusAwsSigner(region string, authorization awsAuthorizationMetadata) *v4.Signer {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	}))
+
+	creds := sess.Config.Credentials
+	if authorization.podIdentityOwner {
+		creds = credentials.NewStaticCredentials(authorization.awsAccessKeyID, authorization.awsSecretAccessKey, authorization.awsSessionToken)
+
+		if authorization.awsRoleArn != "" {
+			creds = stscreds.NewCredentials(sess, authorization.awsRoleArn)
+		}
+	}
+
+	return v4.NewSigner(creds)
+}
+
 func parsePrometheusMetadata(config *ScalerConfig) (meta *prometheusMetadata, err error) {
 	meta = &prometheusMetadata{}
 
@@ -113,8 +179,10 @@ func parsePrometheusMetadata(config *ScalerConfig) (meta *prometheusMetadata, er
 
 	if val, ok := config.TriggerMetadata[promQuery]; ok && val != "" {
 		meta.query = val
-	} else {
-		return nil, fmt.Errorf("no %s given", promQuery)
+	}
+
+	if err := parsePrometheusNamedQueries(config, meta); err != nil {
+		return nil, err
 	}
 
 	if val, ok := config.TriggerMetadata[promMetricName]; ok && val != "" {
@@ -160,9 +228,98 @@ func parsePrometheusMetadata(config *ScalerConfig) (meta *prometheusMetadata, er
 		return nil, err
 	}
 
+	if config.PodIdentity.Provider == kedav1alpha1.PodIdentityProviderSpiffe {
+		if meta.prometheusAuth != nil && meta.prometheusAuth.EnableTLS {
+			return nil, fmt.Errorf("tls authMode can't be set together with spiffe pod identity")
+		}
+		meta.enableSpiffe = true
+		meta.spiffeServerID = config.TriggerMetadata[authentication.SpiffeServerIDKey]
+	}
+
+	if val, ok := config.TriggerMetadata["awsRegion"]; ok && val != "" {
+		meta.awsRegion = val
+		meta.awsAuthorization, err = getAwsAuthorization(config.AuthParams, config.TriggerMetadata, config.ResolvedEnv)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config.PodIdentity.Provider == kedav1alpha1.PodIdentityProviderAzure || config.PodIdentity.Provider == kedav1alpha1.PodIdentityProviderAzureWorkload {
+		if meta.awsRegion != "" {
+			return nil, fmt.Errorf("awsRegion can't be set together with azure pod identity")
+		}
+		meta.azurePodIdentity = config.PodIdentity
+	}
+
+	if (meta.awsRegion != "" || meta.azurePodIdentity.Provider != "") &&
+		meta.prometheusAuth != nil && (meta.prometheusAuth.EnableBearerAuth || meta.prometheusAuth.EnableBasicAuth) {
+		return nil, fmt.Errorf("bearer and basic authModes can't be set together with awsRegion or azure pod identity")
+	}
+
 	return meta, nil
 }
 
+// parsePrometheusNamedQueries parses the query.<name> queries and the queryExpression combining their values
+func parsePrometheusNamedQueries(config *ScalerConfig, meta *prometheusMetadata) error {
+	meta.namedQueries = map[string]string{}
+	for key, val := range config.TriggerMetadata {
+		if !strings.HasPrefix(key, promNamedQueryPrefix) || val == "" {
+			continue
+		}
+		name := strings.TrimPrefix(key, promNamedQueryPrefix)
+		if !isValidPromQueryName(name) {
+			return fmt.Errorf("invalid query name %s, names are made of letters, digits and underscores", name)
+		}
+		meta.namedQueries[name] = val
+	}
+
+	val, ok := config.TriggerMetadata[promQueryExpression]
+	switch {
+	case len(meta.namedQueries) == 0 && meta.query == "":
+		return fmt.Errorf("no %s given", promQuery)
+	case len(meta.namedQueries) == 0:
+		if ok && val != "" {
+			return fmt.Errorf("%s is only supported with named queries", promQueryExpression)
+		}
+		if config.TriggerMetadata[promDivisionByZero] != "" {
+			return fmt.Errorf("%s is only supported with %s", promDivisionByZero, promQueryExpression)
+		}
+		return nil
+	case meta.query != "":
+		return fmt.Errorf("%s can't be set together with named queries", promQuery)
+	case !ok || val == "":
+		return fmt.Errorf("no %s given", promQueryExpression)
+	}
+
+	expression, err := parsePromExpression(val)
+	if err != nil {
+		return fmt.Errorf("error parsing %s: %s", promQueryExpression, err)
+	}
+	names := map[string]bool{}
+	expression.queryNames(names)
+	for name := range names {
+		if _, ok := meta.namedQueries[name]; !ok {
+			return fmt.Errorf("%s references the query %s which isn't given", promQueryExpression, name)
+		}
+	}
+	for name := range meta.namedQueries {
+		if !names[name] {
+			return fmt.Errorf("query %s isn't used by %s", name, promQueryExpression)
+		}
+	}
+	meta.queryExpression = expression
+
+	if val, ok := config.TriggerMetadata[promDivisionByZero]; ok && val != "" {
+		divisionByZeroValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return fmt.Errorf("error parsing %s: %s", promDivisionByZero, err)
+		}
+		meta.divisionByZeroValue = &divisionByZeroValue
+	}
+
+	return nil
+}
+
 func (s *prometheusScaler) IsActive(ctx context.Context) (bool, error) {
 	val, err := s.ExecutePromQuery(ctx)
 	if err != nil {
@@ -191,9 +348,40 @@ func (s *prometheusScaler) GetMetricSpecForScaling(context.Context) []v2beta2.Me
 	return []v2beta2.MetricSpec{metricSpec}
 }
 
+// ExecutePromQuery returns the value of the query, or the value of the expression of the named queries
 func (s *prometheusScaler) ExecutePromQuery(ctx context.Context) (float64, error) {
+	if s.metadata.queryExpression == nil {
+		return s.executePromQuery(ctx, s.metadata.query)
+	}
+
+	names := make([]string, 0, len(s.metadata.namedQueries))
+	for name := range s.metadata.namedQueries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := map[string]float64{}
+	for _, name := range names {
+		val, err := s.executePromQuery(ctx, s.metadata.namedQueries[name])
+		if err != nil {
+			return -1, err
+		}
+		values[name] = val
+	}
+
+	val, err := s.metadata.queryExpression.evaluate(values)
+	if errors.Is(err, errPromDivisionByZero) && s.metadata.divisionByZeroValue != nil {
+		return *s.metadata.divisionByZeroValue, nil
+	}
+	if err != nil {
+		return -1, fmt.Errorf("error evaluating %s: %s", promQueryExpression, err)
+	}
+	return val, nil
+}
+
+func (s *prometheusScaler) executePromQuery(ctx context.Context, query string) (float64, error) {
 	t := time.Now().UTC().Format(time.RFC3339)
-	queryEscaped := url_pkg.QueryEscape(s.metadata.query)
+	queryEscaped := url_pkg.QueryEscape(query)
 	url := fmt.Sprintf("%s/api/v1/query?query=%s&time=%s", s.metadata.serverAddress, queryEscaped, t)
 
 	// set 'namespace' parameter for namespaced Prometheus requests (eg. for Thanos Querier)
@@ -212,10 +400,29 @@ func (s *prometheusScaler) ExecutePromQuery(ctx context.Context) (float64, error
 		req.SetBasicAuth(s.metadata.prometheusAuth.Username, s.metadata.prometheusAuth.Password)
 	}
 
+	if s.metadata.prometheusAuth != nil && s.metadata.prometheusAuth.EnableCustomAuth {
+		req.Header.Add(s.metadata.prometheusAuth.CustomAuthHeader, s.metadata.prometheusAuth.CustomAuthValue)
+	}
+
 	if s.metadata.cortexOrgID != "" {
 		req.Header.Add(promCortexHeaderKey, s.metadata.cortexOrgID)
 	}
 
+	if s.metadata.azurePodIdentity.Provider != "" {
+		token, err := s.getAzureADToken(ctx)
+		if err != nil {
+			return -1, fmt.Errorf("error getting azure ad token: %s", err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	// the request is signed last, the signature covers its headers
+	if s.awsSigner != nil {
+		if _, err := s.awsSigner.Sign(req, nil, promAwsService, s.metadata.awsRegion, time.Now()); err != nil {
+			return -1, fmt.Errorf("error signing prometheus request: %s", err)
+		}
+	}
+
 	r, err := s.httpClient.Do(req)
 	if err != nil {
 		return -1, err
@@ -246,7 +453,7 @@ func (s *prometheusScaler) ExecutePromQuery(ctx context.Context) (float64, error
 		}
 		return -1, fmt.Errorf("prometheus metrics %s target may be lost, the result is empty", s.metadata.metricName)
 	} else if len(result.Data.Result) > 1 {
-		return -1, fmt.Errorf("prometheus query %s returned multiple elements", s.metadata.query)
+		return -1, fmt.Errorf("prometheus query %s returned multiple elements", query)
 	}
 
 	valueLen := len(result.Data.Result[0].Value)
@@ -256,7 +463,7 @@ func (s *prometheusScaler) ExecutePromQuery(ctx context.Context) (float64, error
 		}
 		return -1, fmt.Errorf("prometheus metrics %s target may be lost, the value list is empty", s.metadata.metricName)
 	} else if valueLen < 2 {
-		return -1, fmt.Errorf("prometheus query %s didn't return enough values", s.metadata.query)
+		return -1, fmt.Errorf("prometheus query %s didn't return enough values", query)
 	}
 
 	val := result.Data.Result[0].Value[1]
@@ -272,6 +479,25 @@ func (s *prometheusScaler) ExecutePromQuery(ctx context.Context) (float64, error
 	return v, nil
 }
 
+func (s *prometheusScaler) getAzureADToken(ctx context.Context) (string, error) {
+	var token azure.AADToken
+	var err error
+
+	resource := azure.GetPodIdentityResource(s.metadata.azurePodIdentity, promAzureResource)
+	switch s.metadata.azurePodIdentity.Provider {
+	case kedav1alpha1.PodIdentityProviderAzure:
+		token, err = azure.GetAzureADPodIdentityToken(ctx, s.httpClient, s.metadata.azurePodIdentity.IdentityID, resource)
+	case kedav1alpha1.PodIdentityProviderAzureWorkload:
+		token, err = azure.GetAzureADWorkloadIdentityToken(ctx, s.metadata.azurePodIdentity.IdentityID, resource)
+	default:
+		err = fmt.Errorf("unknown pod identity provider")
+	}
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
 func (s *prometheusScaler) GetMetrics(ctx context.Context, metricName string, _ labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
 	val, err := s.ExecutePromQuery(ctx)
 	if err != nil {
@@ -7,7 +7,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
 	"github.com/stretchr/testify/assert"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 )
 
 type parsePrometheusMetadataTestData struct {
@@ -41,6 +45,28 @@ var testPromMetadata = []parsePrometheusMetadataTestData{
 	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": ""}, true},
 	// ignoreNullValues with wrong value
 	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "ignoreNullValues": "xxxx"}, true},
+	// named queries
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "jobs", "threshold": "10", "query.backlog": "sum(jobs_queued)", "query.rate": "sum(rate(jobs_done[5m]))", "queryExpression": "(backlog / rate) * 1.2"}, false},
+	// named queries without queryExpression
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "jobs", "threshold": "10", "query.backlog": "sum(jobs_queued)"}, true},
+	// named queries and query
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "jobs", "threshold": "10", "query": "up", "query.backlog": "sum(jobs_queued)", "queryExpression": "backlog"}, true},
+	// divisionByZeroValue
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "jobs", "threshold": "10", "query.backlog": "sum(jobs_queued)", "query.rate": "sum(rate(jobs_done[5m]))", "queryExpression": "backlog / rate", "divisionByZeroValue": "0"}, false},
+	// invalid divisionByZeroValue
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "jobs", "threshold": "10", "query.backlog": "sum(jobs_queued)", "query.rate": "sum(rate(jobs_done[5m]))", "queryExpression": "backlog / rate", "divisionByZeroValue": "zero"}, true},
+	// divisionByZeroValue without queryExpression
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "jobs", "threshold": "10", "query": "up", "divisionByZeroValue": "0"}, true},
+	// queryExpression without named queries
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "jobs", "threshold": "10", "query": "up", "queryExpression": "up * 2"}, true},
+	// invalid query name
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "jobs", "threshold": "10", "query.job-backlog": "sum(jobs_queued)", "queryExpression": "backlog"}, true},
+	// invalid queryExpression
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "jobs", "threshold": "10", "query.backlog": "sum(jobs_queued)", "queryExpression": "(backlog"}, true},
+	// queryExpression referencing a missing query
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "jobs", "threshold": "10", "query.backlog": "sum(jobs_queued)", "queryExpression": "backlog / rate"}, true},
+	// query not used by queryExpression
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "jobs", "threshold": "10", "query.backlog": "sum(jobs_queued)", "query.rate": "sum(rate(jobs_done[5m]))", "queryExpression": "backlog"}, true},
 }
 
 var prometheusMetricIdentifiers = []prometheusMetricIdentifier{
@@ -75,6 +101,14 @@ var testPrometheusAuthMetadata = []prometheusAuthMetadataTestData{
 	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "authModes": "tls, basic"}, map[string]string{"ca": "caaa", "cert": "ceert", "key": "keey", "username": "user", "password": "pass"}, false},
 
 	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "authModes": "tls,basic"}, map[string]string{"username": "user", "password": "pass"}, true},
+	// success custom
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "authModes": "custom"}, map[string]string{"customAuthHeader": "X-Scope-OrgID", "customAuthValue": "tenant-1"}, false},
+	// fail custom with no header
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "authModes": "custom"}, map[string]string{"customAuthValue": "tenant-1"}, true},
+	// fail custom with no value
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "authModes": "custom"}, map[string]string{"customAuthHeader": "X-Scope-OrgID"}, true},
+	// success bearer and custom
+	{map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up", "authModes": "bearer,custom"}, map[string]string{"bearerToken": "tooooken", "customAuthHeader": "X-Scope-OrgID", "customAuthValue": "tenant-1"}, false},
 }
 
 func TestPrometheusParseMetadata(t *testing.T) {
@@ -122,13 +156,96 @@ func TestPrometheusScalerAuthParams(t *testing.T) {
 		if err == nil {
 			if (meta.prometheusAuth.EnableBearerAuth && !strings.Contains(testData.metadata["authModes"], "bearer")) ||
 				(meta.prometheusAuth.EnableBasicAuth && !strings.Contains(testData.metadata["authModes"], "basic")) ||
-				(meta.prometheusAuth.EnableTLS && !strings.Contains(testData.metadata["authModes"], "tls")) {
+				(meta.prometheusAuth.EnableTLS && !strings.Contains(testData.metadata["authModes"], "tls")) ||
+				(meta.prometheusAuth.EnableCustomAuth && !strings.Contains(testData.metadata["authModes"], "custom")) {
 				t.Error("wrong auth mode detected")
 			}
 		}
 	}
 }
 
+func TestPrometheusScalerCloudAuthParams(t *testing.T) {
+	baseMetadata := map[string]string{"serverAddress": "http://localhost:9090", "metricName": "http_requests_total", "threshold": "100", "query": "up"}
+	withMetadata := func(extra map[string]string) map[string]string {
+		metadata := map[string]string{}
+		for k, v := range baseMetadata {
+			metadata[k] = v
+		}
+		for k, v := range extra {
+			metadata[k] = v
+		}
+		return metadata
+	}
+	awsCredentials := map[string]string{"awsAccessKeyID": "none", "awsSecretAccessKey": "none"}
+	azureIdentity := kedav1alpha1.AuthPodIdentity{Provider: kedav1alpha1.PodIdentityProviderAzureWorkload}
+
+	testCases := []struct {
+		metadata    map[string]string
+		authParams  map[string]string
+		podIdentity kedav1alpha1.AuthPodIdentity
+		isError     bool
+	}{
+		// sigv4 with credentials
+		{withMetadata(map[string]string{"awsRegion": "eu-west-1"}), awsCredentials, kedav1alpha1.AuthPodIdentity{}, false},
+		// sigv4 with the identity of the operator
+		{withMetadata(map[string]string{"awsRegion": "eu-west-1", "identityOwner": "operator"}), map[string]string{}, kedav1alpha1.AuthPodIdentity{}, false},
+		// sigv4 without credentials
+		{withMetadata(map[string]string{"awsRegion": "eu-west-1"}), map[string]string{}, kedav1alpha1.AuthPodIdentity{}, true},
+		// sigv4 and bearer
+		{withMetadata(map[string]string{"awsRegion": "eu-west-1", "authModes": "bearer"}), map[string]string{"bearerToken": "tooooken", "awsAccessKeyID": "none", "awsSecretAccessKey": "none"}, kedav1alpha1.AuthPodIdentity{}, true},
+		// sigv4 and tls
+		{withMetadata(map[string]string{"awsRegion": "eu-west-1", "authModes": "tls"}), map[string]string{"cert": "ceert", "key": "keey", "awsAccessKeyID": "none", "awsSecretAccessKey": "none"}, kedav1alpha1.AuthPodIdentity{}, false},
+		// azure pod identity
+		{baseMetadata, map[string]string{}, kedav1alpha1.AuthPodIdentity{Provider: kedav1alpha1.PodIdentityProviderAzure}, false},
+		// azure workload identity
+		{baseMetadata, map[string]string{}, azureIdentity, false},
+		// azure and basic
+		{withMetadata(map[string]string{"authModes": "basic"}), map[string]string{"username": "user"}, azureIdentity, true},
+		// azure and sigv4
+		{withMetadata(map[string]string{"awsRegion": "eu-west-1"}), awsCredentials, azureIdentity, true},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parsePrometheusMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, AuthParams: testCase.authParams, PodIdentity: testCase.podIdentity})
+		if testCase.isError {
+			assert.Error(t, err, "metadata %v", testCase.metadata)
+			continue
+		}
+		assert.NoError(t, err, "metadata %v", testCase.metadata)
+		assert.Equal(t, testCase.metadata["awsRegion"], meta.awsRegion)
+		assert.Equal(t, testCase.podIdentity.Provider, meta.azurePodIdentity.Provider)
+	}
+}
+
+func TestPrometheusScalerAwsSigV4(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		authorization := request.Header.Get("Authorization")
+		assert.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKID/"), authorization)
+		assert.Contains(t, authorization, "/eu-west-1/aps/aws4_request")
+		assert.Contains(t, authorization, "x-scope-orgid")
+		assert.NotEmpty(t, request.Header.Get("X-Amz-Date"))
+		if _, err := writer.Write([]byte(`{"data":{"result":[{"value": ["1", "2"]}]}}`)); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer server.Close()
+
+	scaler := prometheusScaler{
+		metadata: &prometheusMetadata{
+			serverAddress:    server.URL,
+			cortexOrgID:      "my-org",
+			ignoreNullValues: true,
+			awsRegion:        "eu-west-1",
+		},
+		httpClient: http.DefaultClient,
+		awsSigner:  v4.NewSigner(credentials.NewStaticCredentials("AKID", "SECRET", "")),
+	}
+
+	value, err := scaler.ExecutePromQuery(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), value)
+}
+
 type prometheusQromQueryResultTestData struct {
 	name             string
 	bodyStr          string
@@ -277,3 +394,90 @@ func TestPrometheusScalerCortexHeader(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+func TestPrometheusScalerAuthHeaders(t *testing.T) {
+	testCases := []struct {
+		authModes  string
+		authParams map[string]string
+		header     string
+		expected   string
+	}{
+		{"bearer", map[string]string{"bearerToken": "tooooken"}, "Authorization", "Bearer tooooken"},
+		{"basic", map[string]string{"username": "user", "password": "pass"}, "Authorization", "Basic dXNlcjpwYXNz"},
+		{"custom", map[string]string{"customAuthHeader": "X-Scope-OrgID", "customAuthValue": "tenant-1"}, "X-Scope-OrgID", "tenant-1"},
+		{"bearer,custom", map[string]string{"bearerToken": "tooooken", "customAuthHeader": "X-Api-Key", "customAuthValue": "key"}, "X-Api-Key", "key"},
+	}
+
+	for _, testCase := range testCases {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, testCase.expected, request.Header.Get(testCase.header), "authModes %s", testCase.authModes)
+			if _, err := writer.Write([]byte(`{"data":{"result":[]}}`)); err != nil {
+				t.Fatal(err)
+			}
+		}))
+
+		meta, err := parsePrometheusMetadata(&ScalerConfig{
+			TriggerMetadata: map[string]string{"serverAddress": server.URL, "metricName": "http_requests_total", "threshold": "100", "query": "up", "authModes": testCase.authModes},
+			AuthParams:      testCase.authParams,
+		})
+		assert.NoError(t, err)
+
+		scaler := prometheusScaler{metadata: meta, httpClient: http.DefaultClient}
+		_, err = scaler.ExecutePromQuery(context.TODO())
+		assert.NoError(t, err)
+
+		server.Close()
+	}
+}
+
+func TestPrometheusScalerNamedQueries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var body string
+		switch request.URL.Query().Get("query") {
+		case "sum(jobs_queued)":
+			body = `{"data":{"result":[{"value": ["1", "120"]}]}}`
+		case "sum(rate(jobs_done[5m]))":
+			body = `{"data":{"result":[{"value": ["1", "40"]}]}}`
+		case "sum(jobs_failed)", "sum(idle_jobs_queued)":
+			body = `{"data":{"result":[]}}`
+		default:
+			writer.WriteHeader(http.StatusBadRequest)
+		}
+		if _, err := writer.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		metadata map[string]string
+		expected float64
+		isError  bool
+	}{
+		{map[string]string{"query.backlog": "sum(jobs_queued)", "query.rate": "sum(rate(jobs_done[5m]))", "queryExpression": "(backlog / rate) * 1.2"}, 3.6, false},
+		{map[string]string{"query.backlog": "sum(jobs_queued)", "query.failed": "sum(jobs_failed)", "queryExpression": "backlog - failed"}, 120, false},
+		{map[string]string{"query.backlog": "sum(jobs_queued)", "query.failed": "sum(jobs_failed)", "queryExpression": "backlog / failed"}, -1, true},
+		// idle, nothing queued and nothing processed
+		{map[string]string{"query.backlog": "sum(idle_jobs_queued)", "query.rate": "sum(jobs_failed)", "queryExpression": "backlog / rate", "divisionByZeroValue": "0"}, 0, false},
+		{map[string]string{"query.backlog": "sum(jobs_queued)", "query.failed": "sum(jobs_failed)", "queryExpression": "backlog / failed + 1", "divisionByZeroValue": "50"}, 50, false},
+		{map[string]string{"query.backlog": "sum(jobs_queued)", "query.invalid": "sum(", "queryExpression": "backlog + invalid"}, -1, true},
+	}
+
+	for _, testCase := range testCases {
+		metadata := map[string]string{"serverAddress": server.URL, "metricName": "jobs", "threshold": "10"}
+		for k, v := range testCase.metadata {
+			metadata[k] = v
+		}
+		meta, err := parsePrometheusMetadata(&ScalerConfig{TriggerMetadata: metadata})
+		assert.NoError(t, err)
+
+		scaler := prometheusScaler{metadata: meta, httpClient: http.DefaultClient}
+		value, err := scaler.ExecutePromQuery(context.TODO())
+		assert.InDelta(t, testCase.expected, value, 1e-9, "queryExpression %s", testCase.metadata["queryExpression"])
+		if testCase.isError {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
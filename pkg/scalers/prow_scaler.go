@@ -0,0 +1,242 @@
+package scalers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	prowAPIVersion      = "prow.k8s.io/v1"
+	prowResourceProwJob = "prowjobs"
+	prowResourceJob     = "jobs"
+	prowStateTriggered  = "triggered"
+	prowStatePending    = "pending"
+	prowTargetJobCount  = 1
+	prowJobListKind     = "ProwJobList"
+)
+
+// prowJobTypes are the types of the ProwJobs, a ProwJob of any type is counted without jobType
+var prowJobTypes = map[string]bool{
+	"presubmit":  true,
+	"postsubmit": true,
+	"periodic":   true,
+	"batch":      true,
+}
+
+type prowScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *prowMetadata
+	kubeClient client.Client
+}
+
+type prowMetadata struct {
+	resource                 string
+	namespace                string
+	labelSelector            labels.Selector
+	states                   map[string]bool
+	jobType                  string
+	targetJobCount           float64
+	activationTargetJobCount float64
+	scalerIndex              int
+}
+
+var prowLog = logf.Log.WithName("prow_scaler")
+
+// NewProwScaler creates a new prowScaler, counting the ProwJobs or the Jobs waiting to be scheduled or running
+func NewProwScaler(kubeClient client.Client, config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseProwMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing prow metadata: %s", err))
+	}
+
+	return &prowScaler{
+		metricType: metricType,
+		metadata:   meta,
+		kubeClient: kubeClient,
+	}, nil
+}
+
+func parseProwMetadata(config *ScalerConfig) (*prowMetadata, error) {
+	meta := prowMetadata{}
+
+	meta.resource = prowResourceProwJob
+	if val, ok := config.TriggerMetadata["resource"]; ok && val != "" {
+		meta.resource = strings.ToLower(val)
+	}
+	if meta.resource != prowResourceProwJob && meta.resource != prowResourceJob {
+		return nil, fmt.Errorf("resource must be either %s or %s, got %s", prowResourceProwJob, prowResourceJob, meta.resource)
+	}
+
+	meta.namespace = config.Namespace
+	if val, ok := config.TriggerMetadata["namespace"]; ok && val != "" {
+		meta.namespace = val
+	}
+
+	meta.labelSelector = labels.Everything()
+	if val, ok := config.TriggerMetadata["labelSelector"]; ok && val != "" {
+		selector, err := labels.Parse(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid labelSelector: %s", err)
+		}
+		meta.labelSelector = selector
+	}
+
+	meta.states = map[string]bool{prowStateTriggered: true, prowStatePending: true}
+	if val, ok := config.TriggerMetadata["states"]; ok && val != "" {
+		meta.states = map[string]bool{}
+		for _, state := range strings.Split(val, ",") {
+			state = strings.ToLower(strings.TrimSpace(state))
+			if state != prowStateTriggered && state != prowStatePending {
+				return nil, fmt.Errorf("states must be a list of %s and %s, got %s", prowStateTriggered, prowStatePending, state)
+			}
+			meta.states[state] = true
+		}
+	}
+
+	if val, ok := config.TriggerMetadata["jobType"]; ok && val != "" {
+		if meta.resource != prowResourceProwJob {
+			return nil, fmt.Errorf("jobType is only supported by the %s resource", prowResourceProwJob)
+		}
+		if !prowJobTypes[val] {
+			return nil, fmt.Errorf("err incorrect value for jobType is given: %s", val)
+		}
+		meta.jobType = val
+	}
+
+	meta.targetJobCount = prowTargetJobCount
+	if val, ok := config.TriggerMetadata["targetJobCount"]; ok && val != "" {
+		targetJobCount, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetJobCount parsing error %s", err.Error())
+		}
+		if targetJobCount <= 0 {
+			return nil, errors.New("targetJobCount must be greater than 0")
+		}
+		meta.targetJobCount = targetJobCount
+	}
+
+	if val, ok := config.TriggerMetadata["activationTargetJobCount"]; ok && val != "" {
+		activationTargetJobCount, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationTargetJobCount parsing error %s", err.Error())
+		}
+		meta.activationTargetJobCount = activationTargetJobCount
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// IsActive returns true if the count of the jobs in the counted states is over activationTargetJobCount
+func (s *prowScaler) IsActive(ctx context.Context) (bool, error) {
+	jobs, err := s.getJobCount(ctx)
+	if err != nil {
+		prowLog.Error(err, "error counting prow jobs")
+		return false, err
+	}
+	return float64(jobs) > s.metadata.activationTargetJobCount, nil
+}
+
+// Close no need for prow scaler
+func (s *prowScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *prowScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("prow-%s-%s", s.metadata.resource, s.metadata.namespace))),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetJobCount),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of jobs in the counted states
+func (s *prowScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	jobs, err := s.getJobCount(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error counting prow jobs: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(jobs))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+func (s *prowScaler) getJobCount(ctx context.Context) (int64, error) {
+	listOptions := &client.ListOptions{
+		Namespace:     s.metadata.namespace,
+		LabelSelector: s.metadata.labelSelector,
+	}
+
+	var count int64
+	if s.metadata.resource == prowResourceJob {
+		jobList := &batchv1.JobList{}
+		if err := s.kubeClient.List(ctx, jobList, listOptions); err != nil {
+			return 0, err
+		}
+		for _, job := range jobList.Items {
+			if s.metadata.states[getProwKubernetesJobState(job)] {
+				count++
+			}
+		}
+		return count, nil
+	}
+
+	prowJobList := &unstructured.UnstructuredList{}
+	prowJobList.SetGroupVersionKind(schema.FromAPIVersionAndKind(prowAPIVersion, prowJobListKind))
+	if err := s.kubeClient.List(ctx, prowJobList, listOptions); err != nil {
+		return 0, err
+	}
+	for _, prowJob := range prowJobList.Items {
+		if s.metadata.jobType != "" {
+			if jobType, _, _ := unstructured.NestedString(prowJob.Object, "spec", "type"); jobType != s.metadata.jobType {
+				continue
+			}
+		}
+		// the state of the ProwJobs is triggered until the pod of the job is created, then pending until the job ends
+		if state, _, _ := unstructured.NestedString(prowJob.Object, "status", "state"); s.metadata.states[state] {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// getProwKubernetesJobState returns the state of a Job like the state of a ProwJob: triggered until a pod of the
+// job runs, pending while it runs and an empty string once the job is complete or failed
+func getProwKubernetesJobState(job batchv1.Job) string {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			return ""
+		}
+	}
+	if job.Status.StartTime == nil || job.Status.Active == 0 {
+		return prowStateTriggered
+	}
+	return prowStatePending
+}
@@ -0,0 +1,150 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type parseProwMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+type prowMetricIdentifier struct {
+	metadataTestData *parseProwMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testProwMetadata = []parseProwMetadataTestData{
+	// defaults
+	{map[string]string{}, false},
+	// all properties
+	{map[string]string{"resource": "prowjobs", "namespace": "test-pods", "labelSelector": "prow.k8s.io/job=pull-build", "states": "triggered, pending", "jobType": "presubmit", "targetJobCount": "2", "activationTargetJobCount": "1"}, false},
+	// jobs
+	{map[string]string{"resource": "Jobs", "namespace": "ci"}, false},
+	// invalid resource
+	{map[string]string{"resource": "pods"}, true},
+	// invalid labelSelector
+	{map[string]string{"labelSelector": "app in (a"}, true},
+	// invalid state
+	{map[string]string{"states": "pending,success"}, true},
+	// invalid jobType
+	{map[string]string{"jobType": "nightly"}, true},
+	{map[string]string{"resource": "jobs", "jobType": "periodic"}, true},
+	// invalid targetJobCount
+	{map[string]string{"targetJobCount": "a"}, true},
+	{map[string]string{"targetJobCount": "0"}, true},
+	// invalid activationTargetJobCount
+	{map[string]string{"activationTargetJobCount": "a"}, true},
+}
+
+var prowMetricIdentifiers = []prowMetricIdentifier{
+	{&testProwMetadata[0], 0, "s0-prow-prowjobs-default"},
+	{&testProwMetadata[2], 1, "s1-prow-jobs-ci"},
+}
+
+func TestProwParseMetadata(t *testing.T) {
+	for _, testData := range testProwMetadata {
+		_, err := parseProwMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, Namespace: "default"})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestProwGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range prowMetricIdentifiers {
+		meta, err := parseProwMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, Namespace: "default", ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockProwScaler := prowScaler{metadata: meta}
+
+		metricSpec := mockProwScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func newProwJob(name string, labels map[string]string, jobType, state string) runtime.Object {
+	prowJob := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": prowAPIVersion,
+		"kind":       "ProwJob",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		"spec":       map[string]interface{}{"type": jobType},
+		"status":     map[string]interface{}{"state": state},
+	}}
+	prowJob.SetLabels(labels)
+	return prowJob
+}
+
+func newProwKubernetesJob(name string, active int32, started bool, finished batchv1.JobConditionType) runtime.Object {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "build"}},
+		Status:     batchv1.JobStatus{Active: active},
+	}
+	if started {
+		startTime := metav1.Now()
+		job.Status.StartTime = &startTime
+	}
+	if finished != "" {
+		job.Status.Conditions = []batchv1.JobCondition{{Type: finished, Status: corev1.ConditionTrue}}
+	}
+	return job
+}
+
+func TestProwGetJobCount(t *testing.T) {
+	pullBuild := map[string]string{"prow.k8s.io/job": "pull-build"}
+	objects := []runtime.Object{
+		newProwJob("triggered", pullBuild, "presubmit", "triggered"),
+		newProwJob("pending", pullBuild, "presubmit", "pending"),
+		newProwJob("batch", pullBuild, "batch", "pending"),
+		newProwJob("success", pullBuild, "presubmit", "success"),
+		newProwJob("failure", pullBuild, "presubmit", "failure"),
+		newProwJob("aborted", pullBuild, "presubmit", "aborted"),
+		newProwJob("periodic", map[string]string{"prow.k8s.io/job": "periodic-cleanup"}, "periodic", "triggered"),
+		newProwKubernetesJob("created", 0, false, ""),
+		newProwKubernetesJob("running", 1, true, ""),
+		newProwKubernetesJob("complete", 0, true, batchv1.JobComplete),
+		newProwKubernetesJob("failed", 0, true, batchv1.JobFailed),
+	}
+
+	testCases := []struct {
+		metadata map[string]string
+		expected int64
+	}{
+		{map[string]string{"labelSelector": "prow.k8s.io/job=pull-build"}, 3},
+		{map[string]string{"labelSelector": "prow.k8s.io/job=pull-build", "states": "triggered"}, 1},
+		{map[string]string{"labelSelector": "prow.k8s.io/job=pull-build", "jobType": "presubmit"}, 2},
+		{map[string]string{}, 4},
+		{map[string]string{"jobType": "periodic", "states": "pending"}, 0},
+		{map[string]string{"namespace": "other"}, 0},
+		{map[string]string{"resource": "jobs"}, 2},
+		{map[string]string{"resource": "jobs", "states": "pending"}, 1},
+		{map[string]string{"resource": "jobs", "labelSelector": "app=test"}, 0},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parseProwMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, Namespace: "default"})
+		assert.NoError(t, err)
+		s := prowScaler{metadata: meta, kubeClient: fake.NewClientBuilder().WithRuntimeObjects(objects...).Build()}
+
+		count, err := s.getJobCount(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.expected, count, "metadata %v", testCase.metadata)
+	}
+}
@@ -2,6 +2,7 @@ package scalers
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -15,8 +16,10 @@ import (
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/metrics/pkg/apis/external_metrics"
+	"nhooyr.io/websocket"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	prommetrics "github.com/kedacore/keda/v2/pkg/metrics"
 	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
@@ -32,6 +35,7 @@ const (
 	rabbitValueTriggerConfigName = "value"
 	rabbitModeQueueLength        = "QueueLength"
 	rabbitModeMessageRate        = "MessageRate"
+	rabbitModeReadyMessages      = "ReadyMessages"
 	defaultRabbitMQQueueLength   = 20
 	rabbitMetricType             = "External"
 	rabbitRootVhostPath          = "/%2F"
@@ -44,6 +48,16 @@ const (
 	defaultProtocol = autoProtocol
 )
 
+const (
+	// amqpWebSocketScheme and amqpsWebSocketScheme tunnel AMQP through a WebSocket gateway, the path of the URL is the WebSocket path
+	amqpWebSocketScheme         = "amqp+ws"
+	amqpsWebSocketScheme        = "amqps+ws"
+	rabbitWebSocketDialTimeout  = 30 * time.Second
+	rabbitAMQPHeartbeat         = 10 * time.Second
+	rabbitAMQPDefaultVhost      = "/"
+	rabbitAMQPDefaultCredential = "guest"
+)
+
 const (
 	sumOperation     = "sum"
 	avgOperation     = "avg"
@@ -61,7 +75,7 @@ type rabbitMQScaler struct {
 
 type rabbitMQMetadata struct {
 	queueName             string
-	mode                  string        // QueueLength or MessageRate
+	mode                  string        // QueueLength, ReadyMessages or MessageRate
 	value                 float64       // trigger value (queue length or publish/sec. rate)
 	host                  string        // connection string for either HTTP or AMQP protocol
 	protocol              string        // either http or amqp protocol
@@ -72,6 +86,8 @@ type rabbitMQMetadata struct {
 	operation             string        // specify the operation to apply in case of multiples queues
 	metricName            string        // custom metric name for trigger
 	timeout               time.Duration // custom http timeout for a specific trigger
+	overWebSocket         bool          // specify if AMQP is tunneled through a WebSocket gateway
+	tlsServerName         string        // override the server name used for SNI and certificate validation
 	scalerIndex           int           // scaler index
 }
 
@@ -104,18 +120,30 @@ func NewRabbitMQScaler(config *ScalerConfig) (Scaler, error) {
 
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 	s.metricType = metricType
 
 	meta, err := parseRabbitMQMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing rabbitmq metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing rabbitmq metadata: %s", err))
 	}
 	s.metadata = meta
-	s.httpClient = kedautil.CreateHTTPClient(meta.timeout, false)
 
-	if meta.protocol == amqpProtocol {
+	httpClient := kedautil.CreateHTTPClient(meta.timeout, false)
+	if meta.tlsServerName != "" {
+		httpClient.Transport.(*http.Transport).TLSClientConfig.ServerName = meta.tlsServerName
+	}
+	s.httpClient = prommetrics.InstrumentHTTPClient(httpClient, config.TriggerType)
+
+	if meta.protocol == amqpProtocol && meta.overWebSocket {
+		conn, ch, err := getWebSocketConnectionAndChannel(meta)
+		if err != nil {
+			return nil, fmt.Errorf("error establishing rabbitmq connection over websocket: %s", err)
+		}
+		s.connection = conn
+		s.channel = ch
+	} else if meta.protocol == amqpProtocol {
 		// Override vhost if requested.
 		host := meta.host
 		if meta.vhostName != nil {
@@ -127,7 +155,7 @@ func NewRabbitMQScaler(config *ScalerConfig) (Scaler, error) {
 			host = hostURI.String()
 		}
 
-		conn, ch, err := getConnectionAndChannel(host)
+		conn, ch, err := getConnectionAndChannel(host, meta.tlsServerName)
 		if err != nil {
 			return nil, fmt.Errorf("error establishing rabbitmq connection: %s", err)
 		}
@@ -172,7 +200,7 @@ func parseRabbitMQMetadata(config *ScalerConfig) (*rabbitMQMetadata, error) {
 			return nil, fmt.Errorf("can't parse host to find protocol: %s", err)
 		}
 		switch parsedURL.Scheme {
-		case "amqp", "amqps":
+		case "amqp", "amqps", amqpWebSocketScheme, amqpsWebSocketScheme:
 			meta.protocol = amqpProtocol
 		case "http", "https":
 			meta.protocol = httpProtocol
@@ -181,6 +209,22 @@ func parseRabbitMQMetadata(config *ScalerConfig) (*rabbitMQMetadata, error) {
 		}
 	}
 
+	if meta.protocol == amqpProtocol {
+		parsedURL, err := url.Parse(meta.host)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse host: %s", err)
+		}
+		meta.overWebSocket = parsedURL.Scheme == amqpWebSocketScheme || parsedURL.Scheme == amqpsWebSocketScheme
+	}
+
+	// Resolve tlsServerName, for brokers reachable through a gateway with a different hostname
+	if val, ok := config.AuthParams["tlsServerName"]; ok {
+		meta.tlsServerName = val
+	}
+	if val, ok := config.TriggerMetadata["tlsServerName"]; ok {
+		meta.tlsServerName = val
+	}
+
 	// Resolve queueName
 	if val, ok := config.TriggerMetadata["queueName"]; ok {
 		meta.queueName = val
@@ -276,6 +320,9 @@ func parseRabbitMQHttpProtocolMetadata(config *ScalerConfig, meta *rabbitMQMetad
 	// Resolve operation
 	meta.operation = defaultOperation
 	if val, ok := config.TriggerMetadata["operation"]; ok {
+		if val != sumOperation && val != avgOperation && val != maxOperation {
+			return fmt.Errorf("operation %s must be one of %s, %s, %s", val, sumOperation, avgOperation, maxOperation)
+		}
 		meta.operation = val
 	}
 
@@ -324,10 +371,12 @@ func parseTrigger(meta *rabbitMQMetadata, config *ScalerConfig) (*rabbitMQMetada
 	switch mode {
 	case rabbitModeQueueLength:
 		meta.mode = rabbitModeQueueLength
+	case rabbitModeReadyMessages:
+		meta.mode = rabbitModeReadyMessages
 	case rabbitModeMessageRate:
 		meta.mode = rabbitModeMessageRate
 	default:
-		return nil, fmt.Errorf("trigger mode %s must be one of %s, %s", mode, rabbitModeQueueLength, rabbitModeMessageRate)
+		return nil, fmt.Errorf("trigger mode %s must be one of %s, %s, %s", mode, rabbitModeQueueLength, rabbitModeReadyMessages, rabbitModeMessageRate)
 	}
 	triggerValue, err := strconv.ParseFloat(value, 64)
 	if err != nil {
@@ -342,12 +391,95 @@ func parseTrigger(meta *rabbitMQMetadata, config *ScalerConfig) (*rabbitMQMetada
 	return meta, nil
 }
 
-func getConnectionAndChannel(host string) (*amqp.Connection, *amqp.Channel, error) {
-	conn, err := amqp.Dial(host)
+func getConnectionAndChannel(host string, tlsServerName string) (*amqp.Connection, *amqp.Channel, error) {
+	var conn *amqp.Connection
+	var err error
+	if tlsServerName != "" {
+		conn, err = amqp.DialTLS(host, &tls.Config{ServerName: tlsServerName})
+	} else {
+		conn, err = amqp.Dial(host)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return openChannel(conn)
+}
+
+// getWebSocketConnectionAndChannel opens the AMQP connection over a WebSocket, the credentials are taken from the host
+// and the vhost from vhostName as the path of the host is the WebSocket path
+func getWebSocketConnectionAndChannel(meta *rabbitMQMetadata) (*amqp.Connection, *amqp.Channel, error) {
+	wsURL, amqpConfig, err := getRabbitMQWebSocketConfig(meta)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	opts := &websocket.DialOptions{}
+	if meta.tlsServerName != "" {
+		opts.HTTPClient = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{ServerName: meta.tlsServerName},
+				Proxy:           http.ProxyFromEnvironment,
+			},
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rabbitWebSocketDialTimeout)
+	defer cancel()
+	ws, _, err := websocket.Dial(ctx, wsURL, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the connection outlives the dial, so it must not be bound to its context
+	conn, err := amqp.Open(websocket.NetConn(context.Background(), ws, websocket.MessageBinary), amqpConfig)
+	if err != nil {
+		ws.Close(websocket.StatusInternalError, "")
+		return nil, nil, err
+	}
+
+	return openChannel(conn)
+}
+
+// getRabbitMQWebSocketConfig returns the WebSocket url of the gateway and the AMQP config of the connection
+func getRabbitMQWebSocketConfig(meta *rabbitMQMetadata) (string, amqp.Config, error) {
+	parsedURL, err := url.Parse(meta.host)
+	if err != nil {
+		return "", amqp.Config{}, err
+	}
+
+	username, password := rabbitAMQPDefaultCredential, rabbitAMQPDefaultCredential
+	if parsedURL.User != nil {
+		username = parsedURL.User.Username()
+		if val, ok := parsedURL.User.Password(); ok {
+			password = val
+		}
+	}
+	parsedURL.User = nil
+
+	switch parsedURL.Scheme {
+	case amqpWebSocketScheme:
+		parsedURL.Scheme = "ws"
+	case amqpsWebSocketScheme:
+		parsedURL.Scheme = "wss"
+	default:
+		return "", amqp.Config{}, fmt.Errorf("the host scheme must be %s or %s to connect over websocket", amqpWebSocketScheme, amqpsWebSocketScheme)
+	}
+
+	vhost := rabbitAMQPDefaultVhost
+	if meta.vhostName != nil && *meta.vhostName != "" {
+		vhost = *meta.vhostName
+	}
+
+	return parsedURL.String(), amqp.Config{
+		SASL:      []amqp.Authentication{&amqp.PlainAuth{Username: username, Password: password}},
+		Vhost:     vhost,
+		Heartbeat: rabbitAMQPHeartbeat,
+		Locale:    "en_US",
+	}, nil
+}
+
+func openChannel(conn *amqp.Connection) (*amqp.Connection, *amqp.Channel, error) {
 	channel, err := conn.Channel()
 	if err != nil {
 		return nil, nil, err
@@ -375,7 +507,7 @@ func (s *rabbitMQScaler) IsActive(ctx context.Context) (bool, error) {
 		return false, s.anonimizeRabbitMQError(err)
 	}
 
-	if s.metadata.mode == rabbitModeQueueLength {
+	if s.metadata.mode != rabbitModeMessageRate {
 		return messages > 0, nil
 	}
 	return publishRate > 0 || messages > 0, nil
@@ -388,7 +520,7 @@ func (s *rabbitMQScaler) getQueueStatus() (int64, float64, error) {
 			return -1, -1, err
 		}
 
-		if s.metadata.excludeUnacknowledged {
+		if s.metadata.excludeUnacknowledged || s.metadata.mode == rabbitModeReadyMessages {
 			// messages count includes only ready
 			return int64(info.MessagesReady), info.MessageStat.PublishDetail.Rate, nil
 		}
@@ -396,6 +528,7 @@ func (s *rabbitMQScaler) getQueueStatus() (int64, float64, error) {
 		return int64(info.Messages), info.MessageStat.PublishDetail.Rate, nil
 	}
 
+	// the message count of AMQP includes only ready
 	items, err := s.channel.QueueInspect(s.metadata.queueName)
 	if err != nil {
 		return -1, -1, err
@@ -404,34 +537,44 @@ func (s *rabbitMQScaler) getQueueStatus() (int64, float64, error) {
 	return int64(items.Messages), 0, nil
 }
 
-func getJSON(s *rabbitMQScaler, url string) (queueInfo, error) {
-	var result queueInfo
+func getJSON(s *rabbitMQScaler, url string, result interface{}) error {
 	r, err := s.httpClient.Get(url)
 	if err != nil {
-		return result, err
+		return err
 	}
 	defer r.Body.Close()
 
 	if r.StatusCode == 200 {
-		if s.metadata.useRegex {
-			var queues regexQueueInfo
-			err = json.NewDecoder(r.Body).Decode(&queues)
-			if err != nil {
-				return queueInfo{}, err
-			}
-			if queues.TotalPages > 1 {
-				return queueInfo{}, fmt.Errorf("regex matches more queues than can be recovered at once")
-			}
-			result, err := getComposedQueue(s, queues.Queues)
-			return result, err
+		return json.NewDecoder(r.Body).Decode(result)
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	return fmt.Errorf("error requesting rabbitMQ API status: %s, response: %s, from: %s", r.Status, body, url)
+}
+
+// getRegexQueuesInfo returns the queues matching the regex composed with the operation, requesting all the
+// pages of the matching queues
+func getRegexQueuesInfo(s *rabbitMQScaler, managementURL string, vhost string) (*queueInfo, error) {
+	var queues []queueInfo
+	for page := 1; ; page++ {
+		getQueuesManagementURI := fmt.Sprintf("%s/api/queues%s?page=%d&use_regex=true&pagination=false&name=%s&page_size=%d", managementURL, vhost, page, url.QueryEscape(s.metadata.queueName), s.metadata.pageSize)
+
+		var result regexQueueInfo
+		if err := getJSON(s, getQueuesManagementURI, &result); err != nil {
+			return nil, err
 		}
+		queues = append(queues, result.Queues...)
 
-		err = json.NewDecoder(r.Body).Decode(&result)
-		return result, err
+		if page >= result.TotalPages {
+			break
+		}
 	}
 
-	body, _ := ioutil.ReadAll(r.Body)
-	return result, fmt.Errorf("error requesting rabbitMQ API status: %s, response: %s, from: %s", r.Status, body, url)
+	info, err := getComposedQueue(s, queues)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
 }
 
 func (s *rabbitMQScaler) getQueueInfoViaHTTP() (*queueInfo, error) {
@@ -468,15 +611,14 @@ func (s *rabbitMQScaler) getQueueInfoViaHTTP() (*queueInfo, error) {
 	// Clear URL path to get the correct host.
 	parsedURL.Path = ""
 
-	var getQueueInfoManagementURI string
 	if s.metadata.useRegex {
-		getQueueInfoManagementURI = fmt.Sprintf("%s/api/queues%s?page=1&use_regex=true&pagination=false&name=%s&page_size=%d", parsedURL.String(), vhost, url.QueryEscape(s.metadata.queueName), s.metadata.pageSize)
-	} else {
-		getQueueInfoManagementURI = fmt.Sprintf("%s/api/queues%s/%s", parsedURL.String(), vhost, url.QueryEscape(s.metadata.queueName))
+		return getRegexQueuesInfo(s, parsedURL.String(), vhost)
 	}
 
+	getQueueInfoManagementURI := fmt.Sprintf("%s/api/queues%s/%s", parsedURL.String(), vhost, url.QueryEscape(s.metadata.queueName))
+
 	var info queueInfo
-	info, err = getJSON(s, getQueueInfoManagementURI)
+	err = getJSON(s, getQueueInfoManagementURI, &info)
 
 	if err != nil {
 		return nil, err
@@ -508,10 +650,10 @@ func (s *rabbitMQScaler) GetMetrics(ctx context.Context, metricName string, metr
 	}
 
 	var metric external_metrics.ExternalMetricValue
-	if s.metadata.mode == rabbitModeQueueLength {
-		metric = GenerateMetricInMili(metricName, float64(messages))
-	} else {
+	if s.metadata.mode == rabbitModeMessageRate {
 		metric = GenerateMetricInMili(metricName, publishRate)
+	} else {
+		metric = GenerateMetricInMili(metricName, float64(messages))
 	}
 
 	return append([]external_metrics.ExternalMetricValue{}, metric), nil
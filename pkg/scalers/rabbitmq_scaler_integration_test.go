@@ -0,0 +1,60 @@
+//go:build integration
+// +build integration
+
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRabbitMQScalerIntegration(t *testing.T) {
+	container := startIntegrationContainer(t, "rabbitmq:3-alpine", 5672, nil)
+	host := fmt.Sprintf("amqp://guest:guest@%s/", container.address())
+	var conn *amqp.Connection
+	waitForIntegration(t, "rabbitmq", func(context.Context) error {
+		var err error
+		conn, err = amqp.Dial(host)
+		return err
+	})
+	defer conn.Close()
+
+	channel, err := conn.Channel()
+	require.NoError(t, err)
+	defer channel.Close()
+	_, err = channel.QueueDeclare("jobs", false, false, false, false, nil)
+	require.NoError(t, err)
+	for i := 0; i < 4; i++ {
+		require.NoError(t, channel.Publish("", "jobs", false, false, amqp.Publishing{Body: []byte(fmt.Sprintf("job %d", i))}))
+	}
+
+	ctx := context.Background()
+	scaler, err := NewRabbitMQScaler(&ScalerConfig{
+		TriggerMetadata: map[string]string{"queueName": "jobs", "mode": "QueueLength", "value": "2", "protocol": "amqp"},
+		AuthParams:      map[string]string{"host": host},
+		ResolvedEnv:     map[string]string{},
+	})
+	require.NoError(t, err)
+	defer scaler.Close(ctx)
+
+	// the published messages are counted once routed to the queue
+	waitForIntegration(t, "rabbitmq messages", func(context.Context) error {
+		queue, err := channel.QueueInspect("jobs")
+		if err != nil {
+			return err
+		}
+		if queue.Messages != 4 {
+			return fmt.Errorf("%d messages in the queue", queue.Messages)
+		}
+		return nil
+	})
+	assertIntegrationMetric(t, scaler, 4)
+
+	_, err = channel.QueuePurge("jobs", false)
+	require.NoError(t, err)
+	assertIntegrationMetric(t, scaler, 0)
+}
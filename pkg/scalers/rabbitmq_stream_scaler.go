@@ -0,0 +1,268 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	rabbitStreamLagThresholdDefault = 1000
+	rabbitStreamDefaultVhost        = "/"
+)
+
+type rabbitMQStreamScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *rabbitMQStreamMetadata
+	httpClient *http.Client
+}
+
+type rabbitMQStreamMetadata struct {
+	host         string // url of the management API, with the credentials
+	vhostName    string
+	streamName   string
+	consumerName string // only count the consumers with this name, the reference used for offset tracking
+	lagThreshold float64
+	operation    string
+
+	// If no consumer is connected the lag can't be known, whether to scale to 1 (false - the default)
+	// when the stream has messages so a consumer can start, or to scale to 0 (true)
+	scaleToZeroWithoutConsumers bool
+
+	scalerIndex int
+}
+
+// rabbitMQStreamConsumer holds the fields of a stream consumer of the management API used by the scaler
+type rabbitMQStreamConsumer struct {
+	OffsetLag  int64                    `json:"offset_lag"`
+	Queue      rabbitMQStreamQueue      `json:"queue"`
+	Properties rabbitMQStreamProperties `json:"properties"`
+}
+
+type rabbitMQStreamQueue struct {
+	Name  string `json:"name"`
+	Vhost string `json:"vhost"`
+}
+
+type rabbitMQStreamProperties struct {
+	Name string `json:"name"`
+}
+
+var rabbitMQStreamLog = logf.Log.WithName("rabbitmq_stream_scaler")
+
+// NewRabbitMQStreamScaler creates a new rabbitMQStreamScaler
+func NewRabbitMQStreamScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseRabbitMQStreamMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing rabbitmq stream metadata: %s", err))
+	}
+
+	return &rabbitMQStreamScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, false),
+	}, nil
+}
+
+func parseRabbitMQStreamMetadata(config *ScalerConfig) (*rabbitMQStreamMetadata, error) {
+	meta := rabbitMQStreamMetadata{}
+
+	switch {
+	case config.AuthParams["host"] != "":
+		meta.host = config.AuthParams["host"]
+	case config.TriggerMetadata["host"] != "":
+		meta.host = config.TriggerMetadata["host"]
+	case config.TriggerMetadata["hostFromEnv"] != "":
+		meta.host = config.ResolvedEnv[config.TriggerMetadata["hostFromEnv"]]
+	default:
+		return nil, errors.New("no host setting given")
+	}
+
+	parsedURL, err := url.Parse(meta.host)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse host: %s", err)
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return nil, fmt.Errorf("host must be the http:// or https:// url of the management API, got %s", parsedURL.Scheme)
+	}
+
+	if val, ok := config.TriggerMetadata["streamName"]; ok && val != "" {
+		meta.streamName = val
+	} else {
+		return nil, errors.New("no stream name given")
+	}
+
+	meta.vhostName = rabbitStreamDefaultVhost
+	if val, ok := config.TriggerMetadata["vhostName"]; ok && val != "" {
+		meta.vhostName = val
+	}
+
+	meta.consumerName = config.TriggerMetadata["consumerName"]
+
+	meta.lagThreshold = rabbitStreamLagThresholdDefault
+	if val, ok := config.TriggerMetadata["lagThreshold"]; ok {
+		lagThreshold, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("lagThreshold parsing error %s", err.Error())
+		}
+		if lagThreshold <= 0 {
+			return nil, errors.New("lagThreshold must be greater than 0")
+		}
+		meta.lagThreshold = lagThreshold
+	}
+
+	meta.operation = sumOperation
+	if val, ok := config.TriggerMetadata["operation"]; ok && val != "" {
+		meta.operation = val
+	}
+	if meta.operation != sumOperation && meta.operation != maxOperation {
+		return nil, fmt.Errorf("operation must be either %s or %s, got %s", sumOperation, maxOperation, meta.operation)
+	}
+
+	if val, ok := config.TriggerMetadata["scaleToZeroWithoutConsumers"]; ok {
+		scaleToZero, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("scaleToZeroWithoutConsumers parsing error %s", err.Error())
+		}
+		meta.scaleToZeroWithoutConsumers = scaleToZero
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// IsActive returns true if the consumers of the stream are lagging
+func (s *rabbitMQStreamScaler) IsActive(ctx context.Context) (bool, error) {
+	lag, err := s.getLag(ctx)
+	if err != nil {
+		rabbitMQStreamLog.Error(err, "error inspecting rabbitmq stream")
+		return false, err
+	}
+	return lag > 0, nil
+}
+
+func (s *rabbitMQStreamScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *rabbitMQStreamScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	metricName := fmt.Sprintf("rabbitmq-stream-%s", s.metadata.streamName)
+	if s.metadata.consumerName != "" {
+		metricName = fmt.Sprintf("%s-%s", metricName, s.metadata.consumerName)
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(metricName)),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.lagThreshold),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the offset lag of the consumers of the stream
+func (s *rabbitMQStreamScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	lag, err := s.getLag(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting rabbitmq stream: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(lag))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// getLag returns the offset lag of the consumers of the stream, when none is connected
+// it returns 1 if the stream has messages unless scaleToZeroWithoutConsumers is set
+func (s *rabbitMQStreamScaler) getLag(ctx context.Context) (int64, error) {
+	var consumers []rabbitMQStreamConsumer
+	if err := s.getJSON(ctx, fmt.Sprintf("/api/stream/consumers/%s", url.PathEscape(s.metadata.vhostName)), &consumers); err != nil {
+		return -1, err
+	}
+
+	lag, found := getRabbitMQStreamLag(consumers, s.metadata)
+	if found {
+		return lag, nil
+	}
+
+	if s.metadata.scaleToZeroWithoutConsumers {
+		return 0, nil
+	}
+	var stream queueInfo
+	if err := s.getJSON(ctx, fmt.Sprintf("/api/queues/%s/%s", url.PathEscape(s.metadata.vhostName), url.PathEscape(s.metadata.streamName)), &stream); err != nil {
+		return -1, err
+	}
+	rabbitMQStreamLog.V(1).Info(fmt.Sprintf("no consumer connected to stream %s, the stream has %d messages", s.metadata.streamName, stream.Messages))
+	if stream.Messages > 0 {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (s *rabbitMQStreamScaler) getJSON(ctx context.Context, path string, v interface{}) error {
+	parsedURL, err := url.Parse(s.metadata.host)
+	if err != nil {
+		return err
+	}
+	endpoint := strings.TrimSuffix(parsedURL.String(), "/") + path
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	r, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(r.Body)
+		return fmt.Errorf("error requesting rabbitMQ API status: %s, response: %s", r.Status, body)
+	}
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// getRabbitMQStreamLag applies the operation to the lag of the consumers of the stream, the boolean is false if there is none
+func getRabbitMQStreamLag(consumers []rabbitMQStreamConsumer, meta *rabbitMQStreamMetadata) (int64, bool) {
+	var lag int64
+	found := false
+	for _, consumer := range consumers {
+		if consumer.Queue.Name != meta.streamName || consumer.Queue.Vhost != meta.vhostName {
+			continue
+		}
+		if meta.consumerName != "" && consumer.Properties.Name != meta.consumerName {
+			continue
+		}
+		found = true
+		if meta.operation == maxOperation {
+			if consumer.OffsetLag > lag {
+				lag = consumer.OffsetLag
+			}
+		} else {
+			lag += consumer.OffsetLag
+		}
+	}
+	return lag, found
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	v2beta2 "k8s.io/api/autoscaling/v2beta2"
@@ -20,6 +21,9 @@ const (
 	defaultTargetListLength = 5
 	defaultDBIdx            = 0
 	defaultEnableTLS        = false
+
+	redisScoreUnitSeconds      = "seconds"
+	redisScoreUnitMilliseconds = "milliseconds"
 )
 
 type redisAddressParser func(metadata, resolvedEnv, authParams map[string]string) (redisConnectionInfo, error)
@@ -49,6 +53,11 @@ type redisMetadata struct {
 	databaseIndex    int
 	connectionInfo   redisConnectionInfo
 	scalerIndex      int
+
+	// countDueItems counts the members of a sorted set whose score is a timestamp not after now,
+	// the items due for processing of a delayed job queue, instead of the length of the list
+	countDueItems bool
+	scoreUnit     string // seconds if not set
 }
 
 var redisLog = logf.Log.WithName("redis_scaler")
@@ -68,29 +77,50 @@ func NewRedisScaler(ctx context.Context, isClustered, isSentinel bool, config *S
 
 		return redis.call(cmd[listType], listName)
 	`
+	dueItemsLuaScript := `
+		local zsetName = KEYS[1]
+		local listType = redis.call('type', zsetName).ok
+		if listType == 'none' then
+			return 0
+		end
+		if listType ~= 'zset' then
+			return redis.error_reply(zsetName .. ' is a ' .. listType .. ', countDueItems requires a sorted set')
+		end
+
+		return redis.call('zcount', zsetName, '-inf', ARGV[1])
+	`
 
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	if isClustered {
 		meta, err := parseRedisMetadata(config, parseRedisClusterAddress)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing redis metadata: %s", err)
+			return nil, NewPermanentError(fmt.Errorf("error parsing redis metadata: %s", err))
+		}
+		if meta.countDueItems {
+			luaScript = dueItemsLuaScript
 		}
 		return createClusteredRedisScaler(ctx, meta, luaScript, metricType)
 	} else if isSentinel {
 		meta, err := parseRedisMetadata(config, parseRedisSentinelAddress)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing redis metadata: %s", err)
+			return nil, NewPermanentError(fmt.Errorf("error parsing redis metadata: %s", err))
+		}
+		if meta.countDueItems {
+			luaScript = dueItemsLuaScript
 		}
 		return createSentinelRedisScaler(ctx, meta, luaScript, metricType)
 	}
 
 	meta, err := parseRedisMetadata(config, parseRedisAddress)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing redis metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing redis metadata: %s", err))
+	}
+	if meta.countDueItems {
+		luaScript = dueItemsLuaScript
 	}
 	return createRedisScaler(ctx, meta, luaScript, metricType)
 }
@@ -110,7 +140,7 @@ func createClusteredRedisScaler(ctx context.Context, meta *redisMetadata, script
 	}
 
 	listLengthFn := func(ctx context.Context) (int64, error) {
-		cmd := client.Eval(ctx, script, []string{meta.listName})
+		cmd := client.Eval(ctx, script, []string{meta.listName}, getRedisScriptArgs(meta, time.Now())...)
 		if cmd.Err() != nil {
 			return -1, cmd.Err()
 		}
@@ -154,7 +184,7 @@ func createRedisScalerWithClient(client *redis.Client, meta *redisMetadata, scri
 	}
 
 	listLengthFn := func(ctx context.Context) (int64, error) {
-		cmd := client.Eval(ctx, script, []string{meta.listName})
+		cmd := client.Eval(ctx, script, []string{meta.listName}, getRedisScriptArgs(meta, time.Now())...)
 		if cmd.Err() != nil {
 			return -1, cmd.Err()
 		}
@@ -202,6 +232,21 @@ func parseRedisMetadata(config *ScalerConfig, parserFn redisAddressParser) (*red
 		}
 		meta.databaseIndex = int(dbIndex)
 	}
+
+	if val, ok := config.TriggerMetadata["countDueItems"]; ok {
+		countDueItems, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("countDueItems parsing error %s", err.Error())
+		}
+		meta.countDueItems = countDueItems
+	}
+
+	if val, ok := config.TriggerMetadata["scoreUnit"]; ok && val != "" {
+		if val != redisScoreUnitSeconds && val != redisScoreUnitMilliseconds {
+			return nil, fmt.Errorf("scoreUnit must be either %s or %s, got %s", redisScoreUnitSeconds, redisScoreUnitMilliseconds, val)
+		}
+		meta.scoreUnit = val
+	}
 	meta.scalerIndex = config.ScalerIndex
 	return &meta, nil
 }
@@ -251,6 +296,17 @@ func (s *redisScaler) GetMetrics(ctx context.Context, metricName string, metricS
 	return append([]external_metrics.ExternalMetricValue{}, metric), nil
 }
 
+// getRedisScriptArgs returns the arguments of the script, the current timestamp in the unit of the scores when counting due items
+func getRedisScriptArgs(meta *redisMetadata, now time.Time) []interface{} {
+	if !meta.countDueItems {
+		return nil
+	}
+	if meta.scoreUnit == redisScoreUnitMilliseconds {
+		return []interface{}{now.UnixNano() / int64(time.Millisecond)}
+	}
+	return []interface{}{now.Unix()}
+}
+
 func parseRedisAddress(metadata, resolvedEnv, authParams map[string]string) (redisConnectionInfo, error) {
 	info := redisConnectionInfo{}
 	switch {
@@ -0,0 +1,37 @@
+//go:build integration
+// +build integration
+
+package scalers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisScalerIntegration(t *testing.T) {
+	container := startIntegrationContainer(t, "redis:7-alpine", 6379, nil)
+	client := redis.NewClient(&redis.Options{Addr: container.address()})
+	defer client.Close()
+	waitForIntegration(t, "redis", func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	})
+
+	ctx := context.Background()
+	require.NoError(t, client.RPush(ctx, "jobs", "a", "b", "c").Err())
+
+	scaler, err := NewRedisScaler(ctx, false, false, &ScalerConfig{
+		TriggerMetadata: map[string]string{"address": container.address(), "listName": "jobs", "listLength": "2"},
+		AuthParams:      map[string]string{},
+		ResolvedEnv:     map[string]string{},
+	})
+	require.NoError(t, err)
+	defer scaler.Close(ctx)
+
+	assertIntegrationMetric(t, scaler, 3)
+
+	require.NoError(t, client.Del(ctx, "jobs").Err())
+	assertIntegrationMetric(t, scaler, 0)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -713,3 +714,65 @@ func TestParseRedisSentinelMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRedisDueItemsMetadata(t *testing.T) {
+	cases := []struct {
+		name          string
+		metadata      map[string]string
+		wantErr       bool
+		countDueItems bool
+		scoreUnit     string
+	}{
+		{
+			name:     "list length by default",
+			metadata: map[string]string{"listName": "jobs"},
+		},
+		{
+			name:          "due items in seconds",
+			metadata:      map[string]string{"listName": "scheduled", "countDueItems": "true"},
+			countDueItems: true,
+		},
+		{
+			name:          "due items in milliseconds",
+			metadata:      map[string]string{"listName": "scheduled", "countDueItems": "true", "scoreUnit": "milliseconds"},
+			countDueItems: true,
+			scoreUnit:     redisScoreUnitMilliseconds,
+		},
+		{
+			name:     "invalid countDueItems",
+			metadata: map[string]string{"listName": "scheduled", "countDueItems": "yes please"},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid scoreUnit",
+			metadata: map[string]string{"listName": "scheduled", "countDueItems": "true", "scoreUnit": "minutes"},
+			wantErr:  true,
+		},
+	}
+
+	for _, testCase := range cases {
+		c := testCase
+		t.Run(c.name, func(t *testing.T) {
+			config := &ScalerConfig{
+				TriggerMetadata: c.metadata,
+				AuthParams:      map[string]string{"address": "localhost:6379"},
+			}
+			meta, err := parseRedisMetadata(config, parseRedisAddress)
+			if c.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.countDueItems, meta.countDueItems)
+			assert.Equal(t, c.scoreUnit, meta.scoreUnit)
+		})
+	}
+}
+
+func TestGetRedisScriptArgs(t *testing.T) {
+	now := time.Unix(1650000000, 500*int64(time.Millisecond))
+
+	assert.Nil(t, getRedisScriptArgs(&redisMetadata{}, now))
+	assert.Equal(t, []interface{}{int64(1650000000)}, getRedisScriptArgs(&redisMetadata{countDueItems: true}, now))
+	assert.Equal(t, []interface{}{int64(1650000000500)}, getRedisScriptArgs(&redisMetadata{countDueItems: true, scoreUnit: redisScoreUnitMilliseconds}, now))
+}
@@ -50,25 +50,25 @@ var redisStreamsLog = logf.Log.WithName("redis_streams_scaler")
 func NewRedisStreamsScaler(ctx context.Context, isClustered, isSentinel bool, config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	if isClustered {
 		meta, err := parseRedisStreamsMetadata(config, parseRedisClusterAddress)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing redis streams metadata: %s", err)
+			return nil, NewPermanentError(fmt.Errorf("error parsing redis streams metadata: %s", err))
 		}
 		return createClusteredRedisStreamsScaler(ctx, meta, metricType)
 	} else if isSentinel {
 		meta, err := parseRedisStreamsMetadata(config, parseRedisSentinelAddress)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing redis streams metadata: %s", err)
+			return nil, NewPermanentError(fmt.Errorf("error parsing redis streams metadata: %s", err))
 		}
 		return createSentinelRedisStreamsScaler(ctx, meta, metricType)
 	}
 	meta, err := parseRedisStreamsMetadata(config, parseRedisAddress)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing redis streams metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing redis streams metadata: %s", err))
 	}
 	return createRedisStreamsScaler(ctx, meta, metricType)
 }
@@ -0,0 +1,348 @@
+package scalers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	defaultS3BucketTargetObjectCount = 5
+	defaultS3BucketRegion            = "us-east-1"
+)
+
+var s3BucketLog = logf.Log.WithName("s3_bucket_scaler")
+
+type s3BucketScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *s3BucketMetadata
+	s3Client   s3iface.S3API
+}
+
+// s3BucketPushScaler also activates the ScaledObject as soon as an object is created under the prefix, with the bucket
+// notifications streamed by MinIO
+type s3BucketPushScaler struct {
+	s3BucketScaler
+
+	credentials *credentials.Credentials
+	httpClient  *http.Client
+}
+
+type s3BucketMetadata struct {
+	bucketName            string
+	prefix                string
+	endpoint              string
+	forcePathStyle        bool
+	awsRegion             string
+	targetObjectCount     int64
+	activationObjectCount int64
+	listenNotifications   bool
+	unsafeSsl             bool
+	awsAuthorization      awsAuthorizationMetadata
+	scalerIndex           int
+}
+
+// NewS3BucketScaler creates a new s3BucketScaler, counting the objects of a bucket of S3 or of an S3-compatible store
+// like MinIO
+func NewS3BucketScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseS3BucketMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing s3 bucket metadata: %s", err))
+	}
+
+	httpClient := createHTTPClient(config, config.GlobalHTTPTimeout, meta.unsafeSsl)
+	sess, creds := createS3Session(meta, httpClient)
+	scaler := s3BucketScaler{
+		metricType: metricType,
+		metadata:   meta,
+		s3Client:   s3.New(sess, &aws.Config{Credentials: creds}),
+	}
+	if !meta.listenNotifications {
+		return &scaler, nil
+	}
+
+	// the notifications are streamed over a long-lived request
+	streamClient := *httpClient
+	streamClient.Timeout = 0
+	return &s3BucketPushScaler{
+		s3BucketScaler: scaler,
+		credentials:    creds,
+		httpClient:     &streamClient,
+	}, nil
+}
+
+func parseS3BucketMetadata(config *ScalerConfig) (*s3BucketMetadata, error) {
+	meta := s3BucketMetadata{
+		awsRegion:         defaultS3BucketRegion,
+		targetObjectCount: defaultS3BucketTargetObjectCount,
+	}
+
+	if val, ok := config.TriggerMetadata["bucketName"]; ok && val != "" {
+		meta.bucketName = val
+	} else {
+		return nil, fmt.Errorf("no bucketName given")
+	}
+
+	meta.prefix = config.TriggerMetadata["prefix"]
+
+	if val, ok := config.TriggerMetadata["endpoint"]; ok && val != "" {
+		if _, err := url.ParseRequestURI(val); err != nil {
+			return nil, fmt.Errorf("invalid endpoint: %s", err)
+		}
+		meta.endpoint = strings.TrimSuffix(val, "/")
+	}
+
+	if val, ok := config.TriggerMetadata["forcePathStyle"]; ok && val != "" {
+		forcePathStyle, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("forcePathStyle parsing error %s", err.Error())
+		}
+		meta.forcePathStyle = forcePathStyle
+	}
+
+	if val, ok := config.TriggerMetadata["awsRegion"]; ok && val != "" {
+		meta.awsRegion = val
+	}
+
+	if val, ok := config.TriggerMetadata["targetObjectCount"]; ok && val != "" {
+		targetObjectCount, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetObjectCount parsing error %s", err.Error())
+		}
+		meta.targetObjectCount = targetObjectCount
+	}
+
+	if val, ok := config.TriggerMetadata["activationObjectCount"]; ok && val != "" {
+		activationObjectCount, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationObjectCount parsing error %s", err.Error())
+		}
+		meta.activationObjectCount = activationObjectCount
+	}
+
+	if val, ok := config.TriggerMetadata["listenNotifications"]; ok && val != "" {
+		listenNotifications, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("listenNotifications parsing error %s", err.Error())
+		}
+		// the notifications are listened with the ListenBucketNotification extension of MinIO, AWS S3 has no equivalent
+		if listenNotifications && meta.endpoint == "" {
+			return nil, fmt.Errorf("listenNotifications requires the endpoint of MinIO")
+		}
+		meta.listenNotifications = listenNotifications
+	}
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok && val != "" {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("unsafeSsl parsing error %s", err.Error())
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	auth, err := getAwsAuthorization(config.AuthParams, config.TriggerMetadata, config.ResolvedEnv)
+	if err != nil {
+		return nil, err
+	}
+	meta.awsAuthorization = auth
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+func createS3Session(metadata *s3BucketMetadata, httpClient *http.Client) (*session.Session, *credentials.Credentials) {
+	config := &aws.Config{
+		Region:           aws.String(metadata.awsRegion),
+		S3ForcePathStyle: aws.Bool(metadata.forcePathStyle),
+		HTTPClient:       httpClient,
+	}
+	if metadata.endpoint != "" {
+		config.Endpoint = aws.String(metadata.endpoint)
+	}
+	sess := session.Must(session.NewSession(config))
+
+	creds := sess.Config.Credentials
+	if metadata.awsAuthorization.podIdentityOwner {
+		creds = credentials.NewStaticCredentials(metadata.awsAuthorization.awsAccessKeyID, metadata.awsAuthorization.awsSecretAccessKey, metadata.awsAuthorization.awsSessionToken)
+
+		if metadata.awsAuthorization.awsRoleArn != "" {
+			creds = stscreds.NewCredentials(sess, metadata.awsAuthorization.awsRoleArn)
+		}
+	}
+	return sess, creds
+}
+
+// getObjectCount returns the number of objects under the prefix, the keys ending with a slash being folder markers
+func (s *s3BucketScaler) getObjectCount(ctx context.Context) (int64, error) {
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.metadata.bucketName),
+	}
+	if s.metadata.prefix != "" {
+		input.Prefix = aws.String(s.metadata.prefix)
+	}
+
+	var count int64
+	err := s.s3Client.ListObjectsV2PagesWithContext(ctx, input, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			if !strings.HasSuffix(aws.StringValue(object.Key), "/") {
+				count++
+			}
+		}
+		return true
+	})
+	return count, err
+}
+
+// IsActive returns true if there are more objects under the prefix than activationObjectCount
+func (s *s3BucketScaler) IsActive(ctx context.Context) (bool, error) {
+	count, err := s.getObjectCount(ctx)
+	if err != nil {
+		s3BucketLog.Error(err, "error counting s3 bucket objects")
+		return false, err
+	}
+
+	return count > s.metadata.activationObjectCount, nil
+}
+
+// Close does nothing in case of s3BucketScaler
+func (s *s3BucketScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *s3BucketScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	metricName := fmt.Sprintf("s3-%s", s.metadata.bucketName)
+	if prefix := strings.Trim(s.metadata.prefix, "/"); prefix != "" {
+		metricName = fmt.Sprintf("%s-%s", metricName, prefix)
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(metricName)),
+		},
+		Target: GetMetricTarget(s.metricType, s.metadata.targetObjectCount),
+	}
+	metricSpec := v2beta2.MetricSpec{External: externalMetric, Type: externalMetricType}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of objects under the prefix
+func (s *s3BucketScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	count, err := s.getObjectCount(ctx)
+	if err != nil {
+		s3BucketLog.Error(err, "error counting s3 bucket objects")
+		return []external_metrics.ExternalMetricValue{}, err
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(count))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+// Run activates the ScaledObject on the creation of each object under the prefix, the deactivation is left to the
+// polling of the object count
+func (s *s3BucketPushScaler) Run(ctx context.Context, active chan<- bool) {
+	defer close(active)
+
+	// reconnect on errors, backing off from 2 seconds to a minute
+	retryDuration := 2 * time.Second
+	for {
+		if err := s.listenNotifications(ctx, active); err != nil && ctx.Err() == nil {
+			s3BucketLog.Error(err, "error listening to the bucket notifications", "bucketName", s.metadata.bucketName)
+		} else {
+			retryDuration = 2 * time.Second
+		}
+
+		backoffTimer := time.NewTimer(retryDuration)
+		select {
+		case <-ctx.Done():
+			backoffTimer.Stop()
+			return
+		case <-backoffTimer.C:
+		}
+		retryDuration *= 2
+		if retryDuration > time.Minute {
+			retryDuration = time.Minute
+		}
+	}
+}
+
+// s3BucketNotification is an event of the ListenBucketNotification stream of MinIO
+type s3BucketNotification struct {
+	Records []struct {
+		EventName string `json:"eventName"`
+	} `json:"Records"`
+}
+
+// listenNotifications streams the object creations under the prefix until the stream or the context ends
+func (s *s3BucketPushScaler) listenNotifications(ctx context.Context, active chan<- bool) error {
+	query := url.Values{}
+	query.Set("events", "s3:ObjectCreated:*")
+	if s.metadata.prefix != "" {
+		query.Set("prefix", s.metadata.prefix)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%s?%s", s.metadata.endpoint, url.PathEscape(s.metadata.bucketName), query.Encode()), nil)
+	if err != nil {
+		return err
+	}
+	if _, err := v4.NewSigner(s.credentials).Sign(req, nil, "s3", s.metadata.awsRegion, time.Now()); err != nil {
+		return err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("listening to the bucket notifications returned %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		// MinIO keeps the stream alive with blank lines
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var notification s3BucketNotification
+		if err := json.Unmarshal([]byte(line), &notification); err != nil {
+			s3BucketLog.V(1).Info("Ignoring invalid bucket notification", "notification", line, "error", err.Error())
+			continue
+		}
+		if len(notification.Records) == 0 {
+			continue
+		}
+		select {
+		case active <- true:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return scanner.Err()
+}
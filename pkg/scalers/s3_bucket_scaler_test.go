@@ -0,0 +1,200 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/stretchr/testify/assert"
+)
+
+var testS3BucketAuthParams = map[string]string{"awsAccessKeyID": "minio", "awsSecretAccessKey": "minio123"}
+
+type parseS3BucketMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type s3BucketMetricIdentifier struct {
+	metadataTestData *parseS3BucketMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testS3BucketMetadata = []parseS3BucketMetadataTestData{
+	// only required properties
+	{map[string]string{"bucketName": "uploads"}, testS3BucketAuthParams, false},
+	// MinIO with all properties
+	{map[string]string{"bucketName": "uploads", "prefix": "incoming/", "endpoint": "http://minio.minio:9000", "forcePathStyle": "true", "awsRegion": "eu-west-1",
+		"targetObjectCount": "10", "activationObjectCount": "2", "listenNotifications": "true", "unsafeSsl": "true"}, testS3BucketAuthParams, false},
+	// operator identity
+	{map[string]string{"bucketName": "uploads", "identityOwner": "operator"}, map[string]string{}, false},
+	// missing bucketName
+	{map[string]string{"prefix": "incoming/"}, testS3BucketAuthParams, true},
+	// invalid endpoint
+	{map[string]string{"bucketName": "uploads", "endpoint": "minio"}, testS3BucketAuthParams, true},
+	// invalid booleans
+	{map[string]string{"bucketName": "uploads", "forcePathStyle": "a"}, testS3BucketAuthParams, true},
+	{map[string]string{"bucketName": "uploads", "endpoint": "http://minio.minio:9000", "listenNotifications": "a"}, testS3BucketAuthParams, true},
+	{map[string]string{"bucketName": "uploads", "unsafeSsl": "a"}, testS3BucketAuthParams, true},
+	// notifications without MinIO endpoint
+	{map[string]string{"bucketName": "uploads", "listenNotifications": "true"}, testS3BucketAuthParams, true},
+	// invalid counts
+	{map[string]string{"bucketName": "uploads", "targetObjectCount": "a"}, testS3BucketAuthParams, true},
+	{map[string]string{"bucketName": "uploads", "activationObjectCount": "a"}, testS3BucketAuthParams, true},
+	// missing credentials
+	{map[string]string{"bucketName": "uploads"}, map[string]string{}, true},
+}
+
+var s3BucketMetricIdentifiers = []s3BucketMetricIdentifier{
+	{&testS3BucketMetadata[0], 0, "s0-s3-uploads"},
+	{&testS3BucketMetadata[1], 1, "s1-s3-uploads-incoming"},
+}
+
+func TestS3BucketParseMetadata(t *testing.T) {
+	for _, testData := range testS3BucketMetadata {
+		_, err := parseS3BucketMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestS3BucketGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range s3BucketMetricIdentifiers {
+		meta, err := parseS3BucketMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockS3BucketScaler := s3BucketScaler{metadata: meta}
+
+		metricSpec := mockS3BucketScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestS3BucketNotificationsReturnPushScaler(t *testing.T) {
+	// a custom CA bundle can't be loaded into the instrumented http client
+	t.Setenv("AWS_CA_BUNDLE", "")
+
+	s, err := NewS3BucketScaler(&ScalerConfig{TriggerMetadata: testS3BucketMetadata[0].metadata, AuthParams: testS3BucketAuthParams})
+	assert.NoError(t, err)
+	_, isPushScaler := s.(PushScaler)
+	assert.False(t, isPushScaler)
+
+	s, err = NewS3BucketScaler(&ScalerConfig{TriggerMetadata: testS3BucketMetadata[1].metadata, AuthParams: testS3BucketAuthParams})
+	assert.NoError(t, err)
+	_, isPushScaler = s.(PushScaler)
+	assert.True(t, isPushScaler)
+}
+
+type mockS3BucketClient struct {
+	s3iface.S3API
+	keys []string
+}
+
+func (m *mockS3BucketClient) ListObjectsV2PagesWithContext(_ aws.Context, input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool, _ ...request.Option) error {
+	var page []*s3.Object
+	for i, key := range m.keys {
+		if !strings.HasPrefix(key, aws.StringValue(input.Prefix)) {
+			continue
+		}
+		page = append(page, &s3.Object{Key: aws.String(key)})
+		// pages of 2 objects
+		if len(page) == 2 || i == len(m.keys)-1 {
+			if !fn(&s3.ListObjectsV2Output{Contents: page}, i == len(m.keys)-1) {
+				return nil
+			}
+			page = nil
+		}
+	}
+	if len(page) > 0 {
+		fn(&s3.ListObjectsV2Output{Contents: page}, true)
+	}
+	return nil
+}
+
+func TestS3BucketGetObjectCount(t *testing.T) {
+	client := &mockS3BucketClient{keys: []string{"incoming/", "incoming/a.csv", "incoming/b.csv", "incoming/nested/", "incoming/nested/c.csv", "processed/d.csv"}}
+
+	testCases := []struct {
+		prefix     string
+		activation string
+		count      int64
+		isActive   bool
+	}{
+		{"", "0", 4, true},
+		{"incoming/", "0", 3, true},
+		{"incoming/", "3", 3, false},
+		{"archive/", "0", 0, false},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parseS3BucketMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"bucketName": "uploads", "prefix": testCase.prefix, "activationObjectCount": testCase.activation}, AuthParams: testS3BucketAuthParams})
+		assert.NoError(t, err)
+		s := s3BucketScaler{metadata: meta, s3Client: client}
+
+		count, err := s.getObjectCount(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.count, count, "prefix %s", testCase.prefix)
+
+		isActive, err := s.IsActive(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.isActive, isActive, "prefix %s", testCase.prefix)
+	}
+}
+
+func TestS3BucketPushScalerRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/uploads", r.URL.Path)
+		assert.Equal(t, "s3:ObjectCreated:*", r.URL.Query().Get("events"))
+		assert.Equal(t, "incoming/", r.URL.Query().Get("prefix"))
+		assert.Contains(t, r.Header.Get("Authorization"), "Credential=minio/")
+
+		fmt.Fprintln(w, " ")
+		fmt.Fprintln(w, `{"Records":[{"eventName":"s3:ObjectCreated:Put"}]}`)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	meta, err := parseS3BucketMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"bucketName": "uploads", "prefix": "incoming/", "endpoint": server.URL,
+		"forcePathStyle": "true", "listenNotifications": "true"}, AuthParams: testS3BucketAuthParams})
+	assert.NoError(t, err)
+	s := &s3BucketPushScaler{
+		s3BucketScaler: s3BucketScaler{metadata: meta},
+		credentials:    credentials.NewStaticCredentials("minio", "minio123", ""),
+		httpClient:     http.DefaultClient,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	active := make(chan bool)
+	go s.Run(ctx, active)
+
+	select {
+	case isActive := <-active:
+		assert.True(t, isActive)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no activation on the bucket notification")
+	}
+
+	cancel()
+	for range active {
+	}
+}
@@ -0,0 +1,219 @@
+package scalers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+
+	// SAP HANA driver required for this scaler
+	_ "github.com/SAP/go-hdb/driver"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+type sapHanaScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *sapHanaMetadata
+	connection *sql.DB
+}
+
+type sapHanaMetadata struct {
+	connectionString string
+	host             string
+	port             string
+	username         string
+	password         string
+	tlsServerName    string
+	query            string
+	targetQueryValue float64
+	metricName       string
+	scalerIndex      int
+}
+
+var sapHanaLog = logf.Log.WithName("sap_hana_scaler")
+
+// NewSapHanaScaler creates a new SAP HANA scaler
+func NewSapHanaScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseSapHanaMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing SAP HANA metadata: %s", err))
+	}
+
+	conn, err := newSapHanaConnection(meta)
+	if err != nil {
+		return nil, fmt.Errorf("error establishing SAP HANA connection: %s", err)
+	}
+	return &sapHanaScaler{
+		metricType: metricType,
+		metadata:   meta,
+		connection: conn,
+	}, nil
+}
+
+func parseSapHanaMetadata(config *ScalerConfig) (*sapHanaMetadata, error) {
+	meta := sapHanaMetadata{}
+
+	if val, ok := config.TriggerMetadata["query"]; ok {
+		meta.query = val
+	} else {
+		return nil, fmt.Errorf("no query given")
+	}
+
+	if val, ok := config.TriggerMetadata["targetQueryValue"]; ok {
+		targetQueryValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetQueryValue parsing error %s", err.Error())
+		}
+		meta.targetQueryValue = targetQueryValue
+	} else {
+		return nil, fmt.Errorf("no targetQueryValue given")
+	}
+
+	switch {
+	case config.AuthParams["connectionString"] != "":
+		meta.connectionString = config.AuthParams["connectionString"]
+	case config.TriggerMetadata["connectionStringFromEnv"] != "":
+		meta.connectionString = config.ResolvedEnv[config.TriggerMetadata["connectionStringFromEnv"]]
+	default:
+		var err error
+		meta.host, err = GetFromAuthOrMeta(config, "host")
+		if err != nil {
+			return nil, err
+		}
+
+		meta.port, err = GetFromAuthOrMeta(config, "port")
+		if err != nil {
+			return nil, err
+		}
+
+		meta.username, err = GetFromAuthOrMeta(config, "username")
+		if err != nil {
+			return nil, err
+		}
+
+		if config.AuthParams["password"] != "" {
+			meta.password = config.AuthParams["password"]
+		} else if config.TriggerMetadata["passwordFromEnv"] != "" {
+			meta.password = config.ResolvedEnv[config.TriggerMetadata["passwordFromEnv"]]
+		}
+
+		if len(meta.password) == 0 {
+			return nil, fmt.Errorf("no password given")
+		}
+
+		if val, ok := config.TriggerMetadata["tlsServerName"]; ok {
+			meta.tlsServerName = val
+		}
+	}
+
+	if val, ok := config.TriggerMetadata["metricName"]; ok {
+		meta.metricName = kedautil.NormalizeString(fmt.Sprintf("sap-hana-%s", val))
+	} else {
+		meta.metricName = kedautil.NormalizeString("sap-hana")
+	}
+	meta.scalerIndex = config.ScalerIndex
+
+	return &meta, nil
+}
+
+// sapHanaMetadataToConnectionStr builds the go-hdb DSN for the given metadata
+func sapHanaMetadataToConnectionStr(meta *sapHanaMetadata) string {
+	if meta.connectionString != "" {
+		return meta.connectionString
+	}
+
+	dsn := url.URL{
+		Scheme: "hdb",
+		User:   url.UserPassword(meta.username, meta.password),
+		Host:   net.JoinHostPort(meta.host, meta.port),
+	}
+	if meta.tlsServerName != "" {
+		query := url.Values{}
+		query.Set("TLSServerName", meta.tlsServerName)
+		dsn.RawQuery = query.Encode()
+	}
+	return dsn.String()
+}
+
+// newSapHanaConnection creates SAP HANA db connection
+func newSapHanaConnection(meta *sapHanaMetadata) (*sql.DB, error) {
+	db, err := sql.Open("hdb", sapHanaMetadataToConnectionStr(meta))
+	if err != nil {
+		sapHanaLog.Error(err, fmt.Sprintf("Found error when opening connection: %s", err))
+		return nil, err
+	}
+	err = db.Ping()
+	if err != nil {
+		sapHanaLog.Error(err, fmt.Sprintf("Found error when pinging database: %s", err))
+		return nil, err
+	}
+	return db, nil
+}
+
+// Close disposes of SAP HANA connections
+func (s *sapHanaScaler) Close(context.Context) error {
+	err := s.connection.Close()
+	if err != nil {
+		sapHanaLog.Error(err, "Error closing SAP HANA connection")
+		return err
+	}
+	return nil
+}
+
+// IsActive returns true if there are pending work items to be processed
+func (s *sapHanaScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getQueryResult(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error inspecting SAP HANA: %s", err)
+	}
+	return value > 0, nil
+}
+
+// getQueryResult returns result of the scaler query
+func (s *sapHanaScaler) getQueryResult(ctx context.Context) (float64, error) {
+	var value float64
+	err := s.connection.QueryRowContext(ctx, s.metadata.query).Scan(&value)
+	if err != nil {
+		sapHanaLog.Error(err, fmt.Sprintf("Could not query SAP HANA database: %s", err))
+		return 0, fmt.Errorf("could not query SAP HANA database: %s", err)
+	}
+	return value, nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *sapHanaScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, s.metadata.metricName),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetQueryValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *sapHanaScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	num, err := s.getQueryResult(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting SAP HANA: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, num)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -18,7 +18,9 @@ package scalers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -30,6 +32,8 @@ import (
 	"k8s.io/metrics/pkg/apis/external_metrics"
 
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	prommetrics "github.com/kedacore/keda/v2/pkg/metrics"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
 func init() {
@@ -88,10 +92,51 @@ type ScalerConfig struct {
 	// ScalerIndex
 	ScalerIndex int
 
+	// TriggerName is the name of the trigger, set in the spec or generated, it doesn't depend on the trigger position
+	// nor on its targets
+	TriggerName string
+
+	// TriggerType is the type of the trigger, used to label the API calls made by the scaler
+	TriggerType string
+
 	// MetricType
 	MetricType v2beta2.MetricTargetType
 }
 
+// PermanentError wraps a scaler construction error that won't go away by retrying, like an invalid metadata,
+// the scaler is not built again until the spec of the ScaledObject or ScaledJob changes
+type PermanentError struct {
+	err error
+}
+
+// NewPermanentError marks err as permanent
+func NewPermanentError(err error) error {
+	return &PermanentError{err: err}
+}
+
+func (e *PermanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.err
+}
+
+// IsPermanentError returns true if err, or any error it wraps, is a PermanentError
+func IsPermanentError(err error) bool {
+	var permanentErr *PermanentError
+	return errors.As(err, &permanentErr)
+}
+
+// AsRetryableError removes the permanent mark of err if it's a PermanentError, for the errors that may go away
+// without the spec changing
+func AsRetryableError(err error) error {
+	if permanentErr, ok := err.(*PermanentError); ok {
+		return permanentErr.err
+	}
+	return err
+}
+
 // GetFromAuthOrMeta helps getting a field from Auth or Meta sections
 func GetFromAuthOrMeta(config *ScalerConfig, field string) (string, error) {
 	var result string
@@ -107,6 +152,11 @@ func GetFromAuthOrMeta(config *ScalerConfig, field string) (string, error) {
 	return result, err
 }
 
+// createHTTPClient creates an HTTP client for the scaler, its requests are counted in the API call metrics of the trigger type
+func createHTTPClient(config *ScalerConfig, timeout time.Duration, unsafeSsl bool) *http.Client {
+	return prommetrics.InstrumentHTTPClient(kedautil.CreateHTTPClient(timeout, unsafeSsl), config.TriggerType)
+}
+
 // GenerateMetricNameWithIndex helps adding the index prefix to the metric name
 func GenerateMetricNameWithIndex(scalerIndex int, metricName string) string {
 	return fmt.Sprintf("s%d-%s", scalerIndex, metricName)
@@ -1,6 +1,7 @@
 package scalers
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -122,3 +123,29 @@ func TestRemoveIndexFromMetricName(t *testing.T) {
 		}
 	}
 }
+
+func TestIsPermanentError(t *testing.T) {
+	permanentErr := NewPermanentError(errors.New("invalid metadata"))
+
+	if !IsPermanentError(permanentErr) {
+		t.Error("Expected permanent error")
+	}
+	if !IsPermanentError(fmt.Errorf("error building scaler: %w", permanentErr)) {
+		t.Error("Expected wrapped permanent error")
+	}
+	if IsPermanentError(errors.New("connection refused")) {
+		t.Error("Expected transient error")
+	}
+	if permanentErr.Error() != "invalid metadata" {
+		t.Errorf("Expected - invalid metadata, Got - %s", permanentErr.Error())
+	}
+}
+
+func TestAsRetryableError(t *testing.T) {
+	if IsPermanentError(AsRetryableError(NewPermanentError(errors.New("invalid metadata")))) {
+		t.Error("Expected the permanent mark to be removed")
+	}
+	if err := errors.New("connection refused"); AsRetryableError(err) != err {
+		t.Error("Expected a retryable error to be left untouched")
+	}
+}
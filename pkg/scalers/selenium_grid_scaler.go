@@ -75,16 +75,16 @@ var seleniumGridLog = logf.Log.WithName("selenium_grid_scaler")
 func NewSeleniumGridScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	meta, err := parseSeleniumGridScalerMetadata(config)
 
 	if err != nil {
-		return nil, fmt.Errorf("error parsing selenium grid metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing selenium grid metadata: %s", err))
 	}
 
-	httpClient := kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, meta.unsafeSsl)
+	httpClient := createHTTPClient(config, config.GlobalHTTPTimeout, meta.unsafeSsl)
 
 	return &seleniumGridScaler{
 		metricType: metricType,
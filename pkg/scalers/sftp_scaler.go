@@ -0,0 +1,391 @@
+package scalers
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jlaffaye/ftp"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	defaultSftpTargetFileCount = 5
+
+	sftpProtocolSftp = "sftp"
+	sftpProtocolFtp  = "ftp"
+	sftpProtocolFtps = "ftps"
+
+	defaultSftpPort         = 22
+	defaultFtpPort          = 21
+	defaultFtpsImplicitPort = 990
+)
+
+var sftpLog = logf.Log.WithName("sftp_scaler")
+
+type sftpScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *sftpMetadata
+	timeout    time.Duration
+}
+
+type sftpMetadata struct {
+	host                string
+	port                int
+	protocol            string
+	implicitTLS         bool
+	directory           string
+	pattern             string
+	targetFileCount     int64
+	activationFileCount int64
+	unsafeSsl           bool
+	scalerIndex         int
+
+	// authentication
+	username             string
+	password             string
+	privateKey           string
+	privateKeyPassphrase string
+	hostKey              string
+}
+
+// NewSftpScaler creates a new sftpScaler, counting the files of a directory of a SFTP, FTP or FTPS server
+func NewSftpScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseSftpMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing sftp metadata: %s", err))
+	}
+
+	return &sftpScaler{
+		metricType: metricType,
+		metadata:   meta,
+		timeout:    config.GlobalHTTPTimeout,
+	}, nil
+}
+
+func parseSftpMetadata(config *ScalerConfig) (*sftpMetadata, error) {
+	meta := sftpMetadata{
+		targetFileCount: defaultSftpTargetFileCount,
+		protocol:        sftpProtocolSftp,
+	}
+
+	if val, ok := config.TriggerMetadata["host"]; ok && val != "" {
+		meta.host = val
+	} else {
+		return nil, fmt.Errorf("no host given")
+	}
+
+	if val, ok := config.TriggerMetadata["protocol"]; ok && val != "" {
+		meta.protocol = strings.ToLower(strings.TrimSpace(val))
+	}
+	switch meta.protocol {
+	case sftpProtocolSftp:
+		meta.port = defaultSftpPort
+	case sftpProtocolFtp:
+		meta.port = defaultFtpPort
+	case sftpProtocolFtps:
+		if val, ok := config.TriggerMetadata["implicitTls"]; ok && val != "" {
+			implicitTLS, err := strconv.ParseBool(val)
+			if err != nil {
+				return nil, fmt.Errorf("implicitTls parsing error %s", err.Error())
+			}
+			meta.implicitTLS = implicitTLS
+		}
+		meta.port = defaultFtpPort
+		if meta.implicitTLS {
+			meta.port = defaultFtpsImplicitPort
+		}
+	default:
+		return nil, fmt.Errorf("err incorrect value for protocol is given: %s", meta.protocol)
+	}
+
+	if val, ok := config.TriggerMetadata["port"]; ok && val != "" {
+		port, err := strconv.Atoi(val)
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("invalid port %s", val)
+		}
+		meta.port = port
+	}
+
+	if val, ok := config.TriggerMetadata["directory"]; ok && val != "" {
+		meta.directory = val
+	} else {
+		return nil, fmt.Errorf("no directory given")
+	}
+
+	if val, ok := config.TriggerMetadata["pattern"]; ok && val != "" {
+		if _, err := path.Match(val, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %s", val, err)
+		}
+		meta.pattern = val
+	}
+
+	if val, ok := config.TriggerMetadata["targetFileCount"]; ok && val != "" {
+		targetFileCount, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetFileCount parsing error %s", err.Error())
+		}
+		meta.targetFileCount = targetFileCount
+	}
+
+	if val, ok := config.TriggerMetadata["activationFileCount"]; ok && val != "" {
+		activationFileCount, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationFileCount parsing error %s", err.Error())
+		}
+		meta.activationFileCount = activationFileCount
+	}
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok && val != "" {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("unsafeSsl parsing error %s", err.Error())
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	meta.username = config.AuthParams["username"]
+	if meta.username == "" {
+		meta.username = config.TriggerMetadata["username"]
+	}
+	if meta.username == "" {
+		return nil, errors.New("no username given")
+	}
+	meta.password = config.AuthParams["password"]
+
+	if meta.protocol == sftpProtocolSftp {
+		meta.privateKey = config.AuthParams["privateKey"]
+		meta.privateKeyPassphrase = config.AuthParams["privateKeyPassphrase"]
+		if meta.password == "" && meta.privateKey == "" {
+			return nil, errors.New("no password or privateKey given")
+		}
+
+		// the public key of the server, in the authorized_keys format
+		meta.hostKey = config.AuthParams["hostKey"]
+		if meta.hostKey == "" {
+			meta.hostKey = config.TriggerMetadata["hostKey"]
+		}
+		if meta.hostKey == "" && !meta.unsafeSsl {
+			return nil, errors.New("no hostKey given, unsafeSsl must be set to skip the host key verification")
+		}
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// sshClientConfig returns the configuration of the ssh connection of the sftp protocol
+func (s *sftpScaler) sshClientConfig() (*ssh.ClientConfig, error) {
+	config := &ssh.ClientConfig{
+		User:    s.metadata.username,
+		Timeout: s.timeout,
+	}
+
+	if s.metadata.privateKey != "" {
+		var signer ssh.Signer
+		var err error
+		if s.metadata.privateKeyPassphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(s.metadata.privateKey), []byte(s.metadata.privateKeyPassphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey([]byte(s.metadata.privateKey))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid privateKey: %s", err)
+		}
+		config.Auth = append(config.Auth, ssh.PublicKeys(signer))
+	}
+	if s.metadata.password != "" {
+		config.Auth = append(config.Auth, ssh.Password(s.metadata.password))
+	}
+
+	if s.metadata.hostKey == "" {
+		// the host key is only missing when unsafeSsl is set
+		config.HostKeyCallback = ssh.InsecureIgnoreHostKey()
+		return config, nil
+	}
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(s.metadata.hostKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hostKey: %s", err)
+	}
+	config.HostKeyCallback = ssh.FixedHostKey(hostKey)
+	return config, nil
+}
+
+// listSftpFiles returns the names of the regular files of the directory with the sftp protocol
+func (s *sftpScaler) listSftpFiles(ctx context.Context, addr string) ([]string, error) {
+	config, err := s.sshClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := net.Dialer{Timeout: s.timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	// the deadline bounds the handshake and the listing, the connection is not reused
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = netConn.SetDeadline(deadline)
+	} else if s.timeout > 0 {
+		_ = netConn.SetDeadline(time.Now().Add(s.timeout))
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(netConn, addr, config)
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+	defer sshClient.Close()
+
+	sftpClient, err := sftp.NewClient(sshClient)
+	if err != nil {
+		return nil, err
+	}
+	defer sftpClient.Close()
+
+	entries, err := sftpClient.ReadDir(s.metadata.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Mode().IsRegular() {
+			files = append(files, entry.Name())
+		}
+	}
+	return files, nil
+}
+
+// listFtpFiles returns the names of the files of the directory with the ftp and ftps protocols
+func (s *sftpScaler) listFtpFiles(ctx context.Context, addr string) ([]string, error) {
+	options := []ftp.DialOption{ftp.DialWithContext(ctx)}
+	if s.timeout > 0 {
+		options = append(options, ftp.DialWithTimeout(s.timeout))
+	}
+	if s.metadata.protocol == sftpProtocolFtps {
+		tlsConfig := &tls.Config{ServerName: s.metadata.host, InsecureSkipVerify: s.metadata.unsafeSsl}
+		if s.metadata.implicitTLS {
+			options = append(options, ftp.DialWithTLS(tlsConfig))
+		} else {
+			options = append(options, ftp.DialWithExplicitTLS(tlsConfig))
+		}
+	}
+
+	conn, err := ftp.Dial(addr, options...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := conn.Quit(); err != nil {
+			sftpLog.V(1).Info("error closing the ftp connection", "error", err.Error())
+		}
+	}()
+
+	if err := conn.Login(s.metadata.username, s.metadata.password); err != nil {
+		return nil, err
+	}
+
+	entries, err := conn.List(s.metadata.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type == ftp.EntryTypeFile {
+			// some servers return the path of the entries in the listing
+			files = append(files, path.Base(entry.Name))
+		}
+	}
+	return files, nil
+}
+
+// getFileCount returns the number of files directly in the directory, matching the pattern if there is one
+func (s *sftpScaler) getFileCount(ctx context.Context) (int64, error) {
+	addr := net.JoinHostPort(s.metadata.host, strconv.Itoa(s.metadata.port))
+
+	var files []string
+	var err error
+	if s.metadata.protocol == sftpProtocolSftp {
+		files, err = s.listSftpFiles(ctx, addr)
+	} else {
+		files, err = s.listFtpFiles(ctx, addr)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for _, file := range files {
+		if s.metadata.pattern != "" {
+			// the pattern was validated when parsing the metadata
+			if matched, _ := path.Match(s.metadata.pattern, file); !matched {
+				continue
+			}
+		}
+		count++
+	}
+	return count, nil
+}
+
+// IsActive returns true if there are more files in the directory than activationFileCount
+func (s *sftpScaler) IsActive(ctx context.Context) (bool, error) {
+	count, err := s.getFileCount(ctx)
+	if err != nil {
+		sftpLog.Error(err, "error counting files", "protocol", s.metadata.protocol, "host", s.metadata.host)
+		return false, err
+	}
+
+	return count > s.metadata.activationFileCount, nil
+}
+
+// Close does nothing, a connection is opened for each query
+func (s *sftpScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *sftpScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("%s-%s-%s", s.metadata.protocol, s.metadata.host, strings.Trim(s.metadata.directory, "/")))),
+		},
+		Target: GetMetricTarget(s.metricType, s.metadata.targetFileCount),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of files in the directory
+func (s *sftpScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	count, err := s.getFileCount(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error counting %s files: %s", s.metadata.protocol, err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(count))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
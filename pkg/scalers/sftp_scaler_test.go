@@ -0,0 +1,346 @@
+package scalers
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+const testSftpHostKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl"
+
+type parseSftpMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type sftpMetricIdentifier struct {
+	metadataTestData *parseSftpMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testSftpMetadata = []parseSftpMetadataTestData{
+	// sftp with a password
+	{map[string]string{"host": "partner.example.com", "directory": "/outgoing"}, map[string]string{"username": "keda", "password": "secret", "hostKey": testSftpHostKey}, false},
+	// sftp with all properties
+	{map[string]string{"host": "partner.example.com", "port": "2222", "protocol": "sftp", "directory": "/outgoing/orders/", "pattern": "*.csv", "targetFileCount": "10",
+		"activationFileCount": "2", "username": "keda", "hostKey": testSftpHostKey}, map[string]string{"privateKey": "key", "privateKeyPassphrase": "passphrase"}, false},
+	// sftp skipping the host key verification
+	{map[string]string{"host": "partner.example.com", "directory": "/outgoing", "unsafeSsl": "true"}, map[string]string{"username": "keda", "password": "secret"}, false},
+	// ftp
+	{map[string]string{"host": "partner.example.com", "protocol": "ftp", "directory": "/outgoing"}, map[string]string{"username": "anonymous"}, false},
+	// ftps explicit and implicit
+	{map[string]string{"host": "partner.example.com", "protocol": "ftps", "directory": "/outgoing"}, map[string]string{"username": "keda", "password": "secret"}, false},
+	{map[string]string{"host": "partner.example.com", "protocol": "ftps", "implicitTls": "true", "directory": "/outgoing"}, map[string]string{"username": "keda", "password": "secret"}, false},
+	// sftp without host key
+	{map[string]string{"host": "partner.example.com", "directory": "/outgoing"}, map[string]string{"username": "keda", "password": "secret"}, true},
+	// sftp without credentials
+	{map[string]string{"host": "partner.example.com", "directory": "/outgoing"}, map[string]string{"username": "keda", "hostKey": testSftpHostKey}, true},
+	// missing username
+	{map[string]string{"host": "partner.example.com", "protocol": "ftp", "directory": "/outgoing"}, map[string]string{}, true},
+	// missing host
+	{map[string]string{"directory": "/outgoing"}, map[string]string{"username": "keda", "password": "secret", "hostKey": testSftpHostKey}, true},
+	// missing directory
+	{map[string]string{"host": "partner.example.com"}, map[string]string{"username": "keda", "password": "secret", "hostKey": testSftpHostKey}, true},
+	// invalid protocol
+	{map[string]string{"host": "partner.example.com", "protocol": "scp", "directory": "/outgoing"}, map[string]string{"username": "keda", "password": "secret"}, true},
+	// invalid port
+	{map[string]string{"host": "partner.example.com", "port": "a", "directory": "/outgoing"}, map[string]string{"username": "keda", "password": "secret", "hostKey": testSftpHostKey}, true},
+	{map[string]string{"host": "partner.example.com", "port": "70000", "directory": "/outgoing"}, map[string]string{"username": "keda", "password": "secret", "hostKey": testSftpHostKey}, true},
+	// invalid pattern
+	{map[string]string{"host": "partner.example.com", "directory": "/outgoing", "pattern": "[a"}, map[string]string{"username": "keda", "password": "secret", "hostKey": testSftpHostKey}, true},
+	// invalid counts
+	{map[string]string{"host": "partner.example.com", "directory": "/outgoing", "targetFileCount": "a"}, map[string]string{"username": "keda", "password": "secret", "hostKey": testSftpHostKey}, true},
+	{map[string]string{"host": "partner.example.com", "directory": "/outgoing", "activationFileCount": "a"}, map[string]string{"username": "keda", "password": "secret", "hostKey": testSftpHostKey}, true},
+	// invalid booleans
+	{map[string]string{"host": "partner.example.com", "directory": "/outgoing", "unsafeSsl": "a"}, map[string]string{"username": "keda", "password": "secret"}, true},
+	{map[string]string{"host": "partner.example.com", "protocol": "ftps", "implicitTls": "a", "directory": "/outgoing"}, map[string]string{"username": "keda", "password": "secret"}, true},
+}
+
+var sftpMetricIdentifiers = []sftpMetricIdentifier{
+	{&testSftpMetadata[0], 0, "s0-sftp-partner-example-com-outgoing"},
+	{&testSftpMetadata[1], 1, "s1-sftp-partner-example-com-outgoing-orders"},
+	{&testSftpMetadata[3], 2, "s2-ftp-partner-example-com-outgoing"},
+}
+
+func TestSftpParseMetadata(t *testing.T) {
+	for _, testData := range testSftpMetadata {
+		_, err := parseSftpMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestSftpDefaultPorts(t *testing.T) {
+	expectedPorts := map[int]int{0: 22, 1: 2222, 3: 21, 4: 21, 5: 990}
+	for i, port := range expectedPorts {
+		meta, err := parseSftpMetadata(&ScalerConfig{TriggerMetadata: testSftpMetadata[i].metadata, AuthParams: testSftpMetadata[i].authParams})
+		assert.NoError(t, err)
+		assert.Equal(t, port, meta.port, "metadata %v", testSftpMetadata[i].metadata)
+	}
+}
+
+func TestSftpGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range sftpMetricIdentifiers {
+		meta, err := parseSftpMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockSftpScaler := sftpScaler{metadata: meta}
+
+		metricSpec := mockSftpScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+// createSftpTestDirectory creates a directory with 2 csv files, a txt file and a sub directory
+func createSftpTestDirectory(t *testing.T) string {
+	dir := t.TempDir()
+	for _, name := range []string{"a.csv", "b.csv", "c.txt"} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0600))
+	}
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "archive.csv"), 0700))
+	return dir
+}
+
+// startSftpTestServer serves the local file system with sftp, for the keda user with the secret password
+func startSftpTestServer(t *testing.T) (string, string) {
+	_, hostPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostPrivateKey)
+	assert.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == "keda" && string(password) == "secret" {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("invalid credentials")
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSftpTestConn(conn, config)
+		}
+	}()
+
+	return listener.Addr().String(), string(ssh.MarshalAuthorizedKey(hostSigner.PublicKey()))
+}
+
+func serveSftpTestConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				_ = req.Reply(req.Type == "subsystem" && string(req.Payload[4:]) == "sftp", nil)
+			}
+		}()
+		server, err := sftp.NewServer(channel)
+		if err != nil {
+			return
+		}
+		_ = server.Serve()
+		server.Close()
+	}
+}
+
+func TestSftpGetFileCount(t *testing.T) {
+	dir := createSftpTestDirectory(t)
+	addr, hostKey := startSftpTestServer(t)
+	host, port, err := net.SplitHostPort(addr)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		metadata   map[string]string
+		authParams map[string]string
+		count      int64
+		isActive   bool
+		isError    bool
+	}{
+		{map[string]string{}, map[string]string{"password": "secret", "hostKey": hostKey}, 3, true, false},
+		{map[string]string{"pattern": "*.csv"}, map[string]string{"password": "secret", "hostKey": hostKey}, 2, true, false},
+		{map[string]string{"pattern": "*.csv", "activationFileCount": "2"}, map[string]string{"password": "secret", "hostKey": hostKey}, 2, false, false},
+		{map[string]string{"unsafeSsl": "true"}, map[string]string{"password": "secret"}, 3, true, false},
+		// wrong password
+		{map[string]string{}, map[string]string{"password": "wrong", "hostKey": hostKey}, 0, false, true},
+		// wrong host key
+		{map[string]string{}, map[string]string{"password": "secret", "hostKey": testSftpHostKey}, 0, false, true},
+	}
+
+	for _, testCase := range testCases {
+		testCase.metadata["host"] = host
+		testCase.metadata["port"] = port
+		testCase.metadata["directory"] = dir
+		testCase.authParams["username"] = "keda"
+		meta, err := parseSftpMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, AuthParams: testCase.authParams})
+		assert.NoError(t, err)
+		s := sftpScaler{metadata: meta}
+
+		count, err := s.getFileCount(context.Background())
+		if testCase.isError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.count, count, "metadata %v", testCase.metadata)
+
+		isActive, err := s.IsActive(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.isActive, isActive, "metadata %v", testCase.metadata)
+	}
+}
+
+// startFtpTestServer answers the commands of a plain ftp listing of /outgoing, without FEAT support
+func startFtpTestServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	listing := []string{
+		"-rw-r--r--    1 ftp      ftp          1024 Jan 01 10:00 a.csv",
+		"-rw-r--r--    1 ftp      ftp          1024 Jan 01 10:00 b.csv",
+		"-rw-r--r--    1 ftp      ftp          1024 Jan 01 10:00 c.txt",
+		"drwxr-xr-x    2 ftp      ftp          4096 Jan 01 10:00 archive.csv",
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFtpTestConn(conn, listing)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func serveFtpTestConn(conn net.Conn, listing []string) {
+	defer conn.Close()
+	var dataListener net.Listener
+	reader := bufio.NewReader(conn)
+	fmt.Fprint(conn, "220 ready\r\n")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.Fields(strings.TrimSpace(line))
+		switch command[0] {
+		case "USER":
+			fmt.Fprint(conn, "331 password required\r\n")
+		case "PASS":
+			if command[1] != "secret" {
+				fmt.Fprint(conn, "530 login incorrect\r\n")
+				continue
+			}
+			fmt.Fprint(conn, "230 logged in\r\n")
+		case "TYPE":
+			fmt.Fprint(conn, "200 type set\r\n")
+		case "EPSV":
+			dataListener, err = net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(conn, "229 Entering Extended Passive Mode (|||%d|)\r\n", dataListener.Addr().(*net.TCPAddr).Port)
+		case "LIST":
+			if dataListener == nil || len(command) < 2 || command[1] != "/outgoing" {
+				fmt.Fprint(conn, "550 not found\r\n")
+				continue
+			}
+			fmt.Fprint(conn, "150 listing\r\n")
+			dataConn, err := dataListener.Accept()
+			if err != nil {
+				return
+			}
+			for _, entry := range listing {
+				fmt.Fprintf(dataConn, "%s\r\n", entry)
+			}
+			dataConn.Close()
+			dataListener.Close()
+			dataListener = nil
+			fmt.Fprint(conn, "226 done\r\n")
+		case "QUIT":
+			fmt.Fprint(conn, "221 bye\r\n")
+			return
+		default:
+			fmt.Fprint(conn, "502 not implemented\r\n")
+		}
+	}
+}
+
+func TestFtpGetFileCount(t *testing.T) {
+	addr := startFtpTestServer(t)
+	host, port, err := net.SplitHostPort(addr)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		directory string
+		pattern   string
+		password  string
+		count     int64
+		isError   bool
+	}{
+		{"/outgoing", "", "secret", 3, false},
+		{"/outgoing", "*.csv", "secret", 2, false},
+		{"/missing", "", "secret", 0, true},
+		{"/outgoing", "", "wrong", 0, true},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parseSftpMetadata(&ScalerConfig{
+			TriggerMetadata: map[string]string{"host": host, "port": port, "protocol": "ftp", "directory": testCase.directory, "pattern": testCase.pattern},
+			AuthParams:      map[string]string{"username": "keda", "password": testCase.password},
+		})
+		assert.NoError(t, err)
+		s := sftpScaler{metadata: meta}
+
+		count, err := s.getFileCount(context.Background())
+		if testCase.isError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.count, count, "directory %s, pattern %s", testCase.directory, testCase.pattern)
+	}
+}
@@ -0,0 +1,327 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	sidekiqMetricQueueSize    = "queueSize"
+	sidekiqMetricLatency      = "latency"
+	sidekiqDefaultQueue       = "default"
+	sidekiqQueueLengthDefault = 5
+	sidekiqLatencySecsDefault = 10
+	sidekiqQueueKeyPrefix     = "queue:"
+	sidekiqScheduledSetKey    = "schedule"
+	sidekiqRetrySetKey        = "retry"
+	sidekiqNamespaceSeparator = ":"
+)
+
+type sidekiqScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *sidekiqMetadata
+	client     redis.UniversalClient
+}
+
+type sidekiqMetadata struct {
+	queues           []string
+	namespace        string
+	metric           string
+	targetValue      float64
+	includeScheduled bool
+	includeRetry     bool
+	databaseIndex    int
+	connectionInfo   redisConnectionInfo
+	scalerIndex      int
+}
+
+// sidekiqJob holds the fields of a Sidekiq job payload used by the scaler
+type sidekiqJob struct {
+	Queue      string  `json:"queue"`
+	EnqueuedAt float64 `json:"enqueued_at"`
+}
+
+var sidekiqLog = logf.Log.WithName("sidekiq_scaler")
+
+// NewSidekiqScaler creates a new sidekiqScaler
+func NewSidekiqScaler(ctx context.Context, isClustered, isSentinel bool, config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	switch {
+	case isClustered:
+		meta, err := parseSidekiqMetadata(config, parseRedisClusterAddress)
+		if err != nil {
+			return nil, NewPermanentError(fmt.Errorf("error parsing sidekiq metadata: %s", err))
+		}
+		client, err := getRedisClusterClient(ctx, meta.connectionInfo)
+		if err != nil {
+			return nil, fmt.Errorf("connection to redis cluster failed: %s", err)
+		}
+		return &sidekiqScaler{metricType: metricType, metadata: meta, client: client}, nil
+	case isSentinel:
+		meta, err := parseSidekiqMetadata(config, parseRedisSentinelAddress)
+		if err != nil {
+			return nil, NewPermanentError(fmt.Errorf("error parsing sidekiq metadata: %s", err))
+		}
+		client, err := getRedisSentinelClient(ctx, meta.connectionInfo, meta.databaseIndex)
+		if err != nil {
+			return nil, fmt.Errorf("connection to redis sentinel failed: %s", err)
+		}
+		return &sidekiqScaler{metricType: metricType, metadata: meta, client: client}, nil
+	}
+
+	meta, err := parseSidekiqMetadata(config, parseRedisAddress)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing sidekiq metadata: %s", err))
+	}
+	client, err := getRedisClient(ctx, meta.connectionInfo, meta.databaseIndex)
+	if err != nil {
+		return nil, fmt.Errorf("connection to redis failed: %s", err)
+	}
+	return &sidekiqScaler{metricType: metricType, metadata: meta, client: client}, nil
+}
+
+func parseSidekiqMetadata(config *ScalerConfig, parserFn redisAddressParser) (*sidekiqMetadata, error) {
+	connInfo, err := parserFn(config.TriggerMetadata, config.ResolvedEnv, config.AuthParams)
+	if err != nil {
+		return nil, err
+	}
+	meta := sidekiqMetadata{
+		connectionInfo: connInfo,
+	}
+
+	meta.queues = []string{sidekiqDefaultQueue}
+	if val, ok := config.TriggerMetadata["queues"]; ok && val != "" {
+		meta.queues = splitAndTrim(val)
+	}
+
+	// redis-namespace prefixes every key with "<namespace>:"
+	meta.namespace = config.TriggerMetadata["namespace"]
+
+	meta.metric = sidekiqMetricQueueSize
+	if val, ok := config.TriggerMetadata["metric"]; ok && val != "" {
+		meta.metric = val
+	}
+
+	switch meta.metric {
+	case sidekiqMetricQueueSize:
+		meta.targetValue = sidekiqQueueLengthDefault
+		if val, ok := config.TriggerMetadata["queueLength"]; ok {
+			queueLength, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return nil, fmt.Errorf("queueLength parsing error %s", err.Error())
+			}
+			meta.targetValue = queueLength
+		}
+	case sidekiqMetricLatency:
+		meta.targetValue = sidekiqLatencySecsDefault
+		if val, ok := config.TriggerMetadata["latencySeconds"]; ok {
+			latency, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return nil, fmt.Errorf("latencySeconds parsing error %s", err.Error())
+			}
+			meta.targetValue = latency
+		}
+	default:
+		return nil, fmt.Errorf("metric must be either %s or %s, got %s", sidekiqMetricQueueSize, sidekiqMetricLatency, meta.metric)
+	}
+	if meta.targetValue <= 0 {
+		return nil, errors.New("target value must be greater than 0")
+	}
+
+	if val, ok := config.TriggerMetadata["includeScheduled"]; ok {
+		includeScheduled, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("includeScheduled parsing error %s", err.Error())
+		}
+		meta.includeScheduled = includeScheduled
+	}
+
+	if val, ok := config.TriggerMetadata["includeRetry"]; ok {
+		includeRetry, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("includeRetry parsing error %s", err.Error())
+		}
+		meta.includeRetry = includeRetry
+	}
+
+	if meta.metric == sidekiqMetricLatency && (meta.includeScheduled || meta.includeRetry) {
+		return nil, fmt.Errorf("includeScheduled and includeRetry can only be used with the %s metric", sidekiqMetricQueueSize)
+	}
+
+	meta.databaseIndex = defaultDBIdx
+	if val, ok := config.TriggerMetadata["databaseIndex"]; ok {
+		dbIndex, err := strconv.ParseInt(val, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("databaseIndex: parsing error %s", err.Error())
+		}
+		meta.databaseIndex = int(dbIndex)
+	}
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// IsActive checks if there are jobs waiting in any of the queues
+func (s *sidekiqScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getMetricValue(ctx)
+	if err != nil {
+		sidekiqLog.Error(err, "error inspecting sidekiq queues")
+		return false, err
+	}
+
+	return value > 0, nil
+}
+
+func (s *sidekiqScaler) Close(context.Context) error {
+	if err := s.client.Close(); err != nil {
+		sidekiqLog.Error(err, "error closing redis client")
+		return err
+	}
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *sidekiqScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	metricName := kedautil.NormalizeString(fmt.Sprintf("sidekiq-%s-%s", s.metadata.metric, strings.Join(s.metadata.queues, "-")))
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, metricName),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the size or the latency of the queues
+func (s *sidekiqScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getMetricValue(ctx)
+	if err != nil {
+		sidekiqLog.Error(err, "error inspecting sidekiq queues")
+		return []external_metrics.ExternalMetricValue{}, err
+	}
+
+	metric := GenerateMetricInMili(metricName, value)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+func (s *sidekiqScaler) getMetricValue(ctx context.Context) (float64, error) {
+	if s.metadata.metric == sidekiqMetricLatency {
+		return s.getLatency(ctx)
+	}
+	return s.getQueueSize(ctx)
+}
+
+// getQueueSize sums the length of the queues, plus the scheduled and retry jobs which are due for them if requested
+func (s *sidekiqScaler) getQueueSize(ctx context.Context) (float64, error) {
+	var size int64
+	for _, queue := range s.metadata.queues {
+		length, err := s.client.LLen(ctx, s.key(sidekiqQueueKeyPrefix+queue)).Result()
+		if err != nil {
+			return -1, err
+		}
+		size += length
+	}
+
+	var sets []string
+	if s.metadata.includeScheduled {
+		sets = append(sets, sidekiqScheduledSetKey)
+	}
+	if s.metadata.includeRetry {
+		sets = append(sets, sidekiqRetrySetKey)
+	}
+	for _, set := range sets {
+		// the sets are scored by the time the job is due and shared by all the queues
+		jobs, err := s.client.ZRangeByScore(ctx, s.key(set), &redis.ZRangeBy{
+			Min: "-inf",
+			Max: strconv.FormatInt(time.Now().Unix(), 10),
+		}).Result()
+		if err != nil {
+			return -1, err
+		}
+		size += int64(countSidekiqJobsInQueues(jobs, s.metadata.queues))
+	}
+
+	return float64(size), nil
+}
+
+// getLatency returns the highest latency of the queues, in seconds
+func (s *sidekiqScaler) getLatency(ctx context.Context) (float64, error) {
+	var latency float64
+	now := time.Now()
+	for _, queue := range s.metadata.queues {
+		// jobs are pushed on the left, so the oldest one is the last of the list
+		jobs, err := s.client.LRange(ctx, s.key(sidekiqQueueKeyPrefix+queue), -1, -1).Result()
+		if err != nil {
+			return -1, err
+		}
+		if len(jobs) == 0 {
+			continue
+		}
+		queueLatency, err := getSidekiqJobLatency(jobs[0], now)
+		if err != nil {
+			return -1, fmt.Errorf("error reading the oldest job of queue %s: %s", queue, err)
+		}
+		latency = math.Max(latency, queueLatency)
+	}
+	return latency, nil
+}
+
+func (s *sidekiqScaler) key(name string) string {
+	if s.metadata.namespace == "" {
+		return name
+	}
+	return s.metadata.namespace + sidekiqNamespaceSeparator + name
+}
+
+// getSidekiqJobLatency returns for how long the job has been enqueued, in seconds
+func getSidekiqJobLatency(payload string, now time.Time) (float64, error) {
+	job := sidekiqJob{}
+	if err := json.Unmarshal([]byte(payload), &job); err != nil {
+		return 0, err
+	}
+	if job.EnqueuedAt == 0 {
+		return 0, errors.New("job has no enqueued_at")
+	}
+	enqueuedAt := time.Unix(0, int64(job.EnqueuedAt*float64(time.Second)))
+	return math.Max(0, now.Sub(enqueuedAt).Seconds()), nil
+}
+
+// countSidekiqJobsInQueues counts the jobs of the scheduled or retry set which belong to one of the queues
+func countSidekiqJobsInQueues(payloads []string, queues []string) int {
+	count := 0
+	for _, payload := range payloads {
+		job := sidekiqJob{}
+		if err := json.Unmarshal([]byte(payload), &job); err != nil {
+			sidekiqLog.V(1).Info("skipping unreadable sidekiq job", "error", err.Error())
+			continue
+		}
+		for _, queue := range queues {
+			if job.Queue == queue {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,134 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type parseSidekiqMetadataTestData struct {
+	metadata   map[string]string
+	isError    bool
+	authParams map[string]string
+}
+
+type sidekiqMetricIdentifier struct {
+	metadataTestData *parseSidekiqMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testSidekiqMetadata = []parseSidekiqMetadataTestData{
+	// nothing passed
+	{map[string]string{}, true, map[string]string{}},
+	// only address, default queue and metric
+	{map[string]string{"address": "redis:6379"}, false, map[string]string{}},
+	// properly formed queueSize
+	{map[string]string{"address": "redis:6379", "queues": "critical, default", "queueLength": "10", "includeScheduled": "true", "includeRetry": "true"}, false, map[string]string{}},
+	// properly formed latency
+	{map[string]string{"address": "redis:6379", "queues": "mailers", "metric": "latency", "latencySeconds": "30", "namespace": "myapp"}, false, map[string]string{}},
+	// address from authParams
+	{map[string]string{"queues": "default"}, false, map[string]string{"address": "redis:6379", "password": "secret"}},
+	// unknown metric
+	{map[string]string{"address": "redis:6379", "metric": "throughput"}, true, map[string]string{}},
+	// improperly formed queueLength
+	{map[string]string{"address": "redis:6379", "queueLength": "AA"}, true, map[string]string{}},
+	// zero queueLength
+	{map[string]string{"address": "redis:6379", "queueLength": "0"}, true, map[string]string{}},
+	// improperly formed latencySeconds
+	{map[string]string{"address": "redis:6379", "metric": "latency", "latencySeconds": "AA"}, true, map[string]string{}},
+	// improperly formed includeScheduled
+	{map[string]string{"address": "redis:6379", "includeScheduled": "yes"}, true, map[string]string{}},
+	// improperly formed includeRetry
+	{map[string]string{"address": "redis:6379", "includeRetry": "yes"}, true, map[string]string{}},
+	// includeRetry with latency
+	{map[string]string{"address": "redis:6379", "metric": "latency", "includeRetry": "true"}, true, map[string]string{}},
+	// improperly formed databaseIndex
+	{map[string]string{"address": "redis:6379", "databaseIndex": "AA"}, true, map[string]string{}},
+}
+
+var sidekiqMetricIdentifiers = []sidekiqMetricIdentifier{
+	{&testSidekiqMetadata[2], 0, "s0-sidekiq-queueSize-critical-default"},
+	{&testSidekiqMetadata[3], 1, "s1-sidekiq-latency-mailers"},
+}
+
+func TestSidekiqParseMetadata(t *testing.T) {
+	for idx, testData := range testSidekiqMetadata {
+		_, err := parseSidekiqMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams}, parseRedisAddress)
+		if err != nil && !testData.isError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if testData.isError && err == nil {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+	}
+}
+
+func TestSidekiqParseClusterAndSentinelMetadata(t *testing.T) {
+	meta, err := parseSidekiqMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"addresses": "redis-0:6379, redis-1:6379"}}, parseRedisClusterAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"redis-0:6379", "redis-1:6379"}, meta.connectionInfo.addresses)
+
+	meta, err = parseSidekiqMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"addresses": "sentinel:26379", "sentinelMaster": "mymaster"}}, parseRedisSentinelAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, "mymaster", meta.connectionInfo.sentinelMaster)
+}
+
+func TestSidekiqGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range sidekiqMetricIdentifiers {
+		meta, err := parseSidekiqMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex}, parseRedisAddress)
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockSidekiqScaler := sidekiqScaler{"", meta, nil}
+
+		metricSpec := mockSidekiqScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestSidekiqKey(t *testing.T) {
+	s := sidekiqScaler{metadata: &sidekiqMetadata{}}
+	assert.Equal(t, "queue:default", s.key("queue:default"))
+
+	s.metadata.namespace = "myapp"
+	assert.Equal(t, "myapp:queue:default", s.key("queue:default"))
+}
+
+func TestGetSidekiqJobLatency(t *testing.T) {
+	now := time.Unix(1650000030, 500000000)
+
+	latency, err := getSidekiqJobLatency(`{"class":"HardWorker","queue":"default","enqueued_at":1650000000.5}`, now)
+	assert.NoError(t, err)
+	assert.InDelta(t, 30, latency, 0.001)
+
+	// enqueued_at slightly in the future because of clock skew
+	latency, err = getSidekiqJobLatency(`{"class":"HardWorker","queue":"default","enqueued_at":1650000031}`, now)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), latency)
+
+	_, err = getSidekiqJobLatency(`{"class":"HardWorker","queue":"default"}`, now)
+	assert.Error(t, err)
+
+	_, err = getSidekiqJobLatency(`not a job`, now)
+	assert.Error(t, err)
+}
+
+func TestCountSidekiqJobsInQueues(t *testing.T) {
+	jobs := []string{
+		`{"class":"HardWorker","queue":"default"}`,
+		`{"class":"Mailer","queue":"mailers"}`,
+		`{"class":"HardWorker","queue":"default"}`,
+		`{"class":"Report","queue":"low"}`,
+		`not a job`,
+	}
+
+	assert.Equal(t, 2, countSidekiqJobsInQueues(jobs, []string{"default"}))
+	assert.Equal(t, 3, countSidekiqJobsInQueues(jobs, []string{"default", "mailers"}))
+	assert.Equal(t, 0, countSidekiqJobsInQueues(jobs, []string{"critical"}))
+}
@@ -111,18 +111,18 @@ var solaceLog = logf.Log.WithName(solaceScalerID + "_scaler")
 //	Constructor for SolaceScaler
 func NewSolaceScaler(config *ScalerConfig) (Scaler, error) {
 	// Create HTTP Client
-	httpClient := kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, false)
+	httpClient := createHTTPClient(config, config.GlobalHTTPTimeout, false)
 
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	// Parse Solace Metadata
 	solaceMetadata, err := parseSolaceMetadata(config)
 	if err != nil {
 		solaceLog.Error(err, "Error parsing Solace Trigger Metadata or missing values")
-		return nil, err
+		return nil, NewPermanentError(err)
 	}
 
 	return &SolaceScaler{
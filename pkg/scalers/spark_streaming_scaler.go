@@ -0,0 +1,326 @@
+package scalers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	sparkMetricProcessingRatio = "processingRatio"
+	sparkMetricInputRate       = "inputRate"
+	sparkMetricBacklog         = "backlog"
+
+	// sparkOffsetsBehindLatestMetric is the metric of the Kafka source of Structured Streaming, since Spark 3.2
+	sparkOffsetsBehindLatestMetric = "maxOffsetsBehindLatest"
+)
+
+var sparkStreamingLog = logf.Log.WithName("spark_streaming_scaler")
+
+type sparkStreamingScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *sparkStreamingMetadata
+	httpClient *http.Client
+}
+
+type sparkStreamingMetadata struct {
+	sparkURL              string
+	applicationID         string
+	progressURL           string
+	metric                string
+	targetValue           float64
+	activationTargetValue float64
+	unsafeSsl             bool
+	username              string
+	password              string
+	scalerIndex           int
+}
+
+// sparkApplication is an application of /api/v1/applications
+type sparkApplication struct {
+	ID string `json:"id"`
+}
+
+// sparkStreamingStatistics is the response of /api/v1/applications/<id>/streaming/statistics, of the DStreams
+type sparkStreamingStatistics struct {
+	BatchDuration     float64  `json:"batchDuration"`
+	NumActiveBatches  float64  `json:"numActiveBatches"`
+	AvgInputRate      *float64 `json:"avgInputRate"`
+	AvgProcessingTime *float64 `json:"avgProcessingTime"`
+}
+
+// sparkStreamingQueryProgress is the JSON of a StreamingQueryProgress of Structured Streaming
+type sparkStreamingQueryProgress struct {
+	InputRowsPerSecond     float64 `json:"inputRowsPerSecond"`
+	ProcessedRowsPerSecond float64 `json:"processedRowsPerSecond"`
+	Sources                []struct {
+		Description string            `json:"description"`
+		Metrics     map[string]string `json:"metrics"`
+	} `json:"sources"`
+}
+
+// NewSparkStreamingScaler creates a new sparkStreamingScaler, comparing the input rate of a Spark streaming
+// application to its processing rate, from the REST API of Spark or the progress of a Structured Streaming query
+func NewSparkStreamingScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseSparkStreamingMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing spark streaming metadata: %s", err))
+	}
+
+	return &sparkStreamingScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, meta.unsafeSsl),
+	}, nil
+}
+
+func parseSparkStreamingMetadata(config *ScalerConfig) (*sparkStreamingMetadata, error) {
+	meta := sparkStreamingMetadata{
+		metric: sparkMetricProcessingRatio,
+	}
+
+	// the progress of a Structured Streaming query, e.g. served by the application from StreamingQuery.lastProgress,
+	// replaces the REST API of the DStreams
+	if val, ok := config.TriggerMetadata["progressURL"]; ok && val != "" {
+		if _, err := url.ParseRequestURI(val); err != nil {
+			return nil, fmt.Errorf("invalid progressURL: %s", err)
+		}
+		meta.progressURL = val
+	} else if val, ok := config.TriggerMetadata["sparkURL"]; ok && val != "" {
+		if _, err := url.ParseRequestURI(val); err != nil {
+			return nil, fmt.Errorf("invalid sparkURL: %s", err)
+		}
+		meta.sparkURL = strings.TrimSuffix(val, "/")
+		meta.applicationID = config.TriggerMetadata["applicationId"]
+	} else {
+		return nil, errors.New("no sparkURL or progressURL given")
+	}
+
+	if val, ok := config.TriggerMetadata["metric"]; ok && val != "" {
+		meta.metric = strings.TrimSpace(val)
+	}
+	switch meta.metric {
+	case sparkMetricProcessingRatio, sparkMetricInputRate, sparkMetricBacklog:
+	default:
+		return nil, fmt.Errorf("err incorrect value for metric is given: %s", meta.metric)
+	}
+
+	if val, ok := config.TriggerMetadata["targetValue"]; ok && val != "" {
+		targetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetValue parsing error %s", err.Error())
+		}
+		meta.targetValue = targetValue
+	} else {
+		return nil, errors.New("no targetValue given")
+	}
+
+	if val, ok := config.TriggerMetadata["activationTargetValue"]; ok && val != "" {
+		activationTargetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationTargetValue parsing error %s", err.Error())
+		}
+		meta.activationTargetValue = activationTargetValue
+	}
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok && val != "" {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("unsafeSsl parsing error %s", err.Error())
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	meta.username = config.AuthParams["username"]
+	meta.password = config.AuthParams["password"]
+	if meta.username != "" && meta.password == "" {
+		return nil, errors.New("no password given")
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+func (s *sparkStreamingScaler) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if s.metadata.username != "" {
+		req.SetBasicAuth(s.metadata.username, s.metadata.password)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d: %s", url, resp.StatusCode, string(body))
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error decoding %s response: %s", url, err)
+	}
+	return nil
+}
+
+// getStatisticsValue returns the value of the metric from the statistics of the DStreams of the application, the
+// processing ratio being the processing time of the batches over their interval
+func (s *sparkStreamingScaler) getStatisticsValue(ctx context.Context) (float64, error) {
+	applicationID := s.metadata.applicationID
+	if applicationID == "" {
+		// the UI of a driver serves its single application
+		var applications []sparkApplication
+		if err := s.getJSON(ctx, fmt.Sprintf("%s/api/v1/applications", s.metadata.sparkURL), &applications); err != nil {
+			return 0, err
+		}
+		if len(applications) == 0 {
+			return 0, errors.New("no spark application found")
+		}
+		applicationID = applications[0].ID
+	}
+
+	var statistics sparkStreamingStatistics
+	if err := s.getJSON(ctx, fmt.Sprintf("%s/api/v1/applications/%s/streaming/statistics", s.metadata.sparkURL, url.PathEscape(applicationID)), &statistics); err != nil {
+		return 0, err
+	}
+
+	switch s.metadata.metric {
+	case sparkMetricInputRate:
+		if statistics.AvgInputRate == nil {
+			return 0, nil
+		}
+		return *statistics.AvgInputRate, nil
+	case sparkMetricBacklog:
+		return statistics.NumActiveBatches, nil
+	default:
+		if statistics.AvgProcessingTime == nil || statistics.BatchDuration <= 0 {
+			return 0, nil
+		}
+		return *statistics.AvgProcessingTime / statistics.BatchDuration, nil
+	}
+}
+
+// getProgressValue returns the value of the metric from the progress of a Structured Streaming query, the backlog
+// being the offsets behind the latest ones of the Kafka sources
+func (s *sparkStreamingScaler) getProgressValue(ctx context.Context) (float64, error) {
+	var raw json.RawMessage
+	if err := s.getJSON(ctx, s.metadata.progressURL, &raw); err != nil {
+		return 0, err
+	}
+	// the latest progress of StreamingQuery.recentProgress is the last one
+	var progress sparkStreamingQueryProgress
+	var recentProgress []sparkStreamingQueryProgress
+	if err := json.Unmarshal(raw, &recentProgress); err == nil {
+		if len(recentProgress) == 0 {
+			return 0, errors.New("no streaming query progress found")
+		}
+		progress = recentProgress[len(recentProgress)-1]
+	} else if err := json.Unmarshal(raw, &progress); err != nil {
+		return 0, fmt.Errorf("error decoding the streaming query progress: %s", err)
+	}
+
+	switch s.metadata.metric {
+	case sparkMetricInputRate:
+		return progress.InputRowsPerSecond, nil
+	case sparkMetricBacklog:
+		backlog, found := 0.0, false
+		for _, source := range progress.Sources {
+			val, ok := source.Metrics[sparkOffsetsBehindLatestMetric]
+			if !ok {
+				continue
+			}
+			offsets, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid %s of source %s: %s", sparkOffsetsBehindLatestMetric, source.Description, err)
+			}
+			backlog += offsets
+			found = true
+		}
+		if !found {
+			return 0, fmt.Errorf("no source reports %s", sparkOffsetsBehindLatestMetric)
+		}
+		return backlog, nil
+	default:
+		// an idle query processes no rows
+		if progress.ProcessedRowsPerSecond <= 0 {
+			return 0, nil
+		}
+		return progress.InputRowsPerSecond / progress.ProcessedRowsPerSecond, nil
+	}
+}
+
+func (s *sparkStreamingScaler) getValue(ctx context.Context) (float64, error) {
+	if s.metadata.progressURL != "" {
+		return s.getProgressValue(ctx)
+	}
+	return s.getStatisticsValue(ctx)
+}
+
+// IsActive returns true if the value of the metric is over activationTargetValue
+func (s *sparkStreamingScaler) IsActive(ctx context.Context) (bool, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		sparkStreamingLog.Error(err, "error getting spark streaming metric")
+		return false, err
+	}
+
+	return value > s.metadata.activationTargetValue, nil
+}
+
+func (s *sparkStreamingScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *sparkStreamingScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	metricName := fmt.Sprintf("spark-streaming-%s", s.metadata.metric)
+	if s.metadata.applicationID != "" {
+		metricName = fmt.Sprintf("spark-streaming-%s-%s", s.metadata.applicationID, s.metadata.metric)
+	}
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(metricName)),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the processing ratio, the input rate or the backlog of the streaming application
+func (s *sparkStreamingScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	value, err := s.getValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error getting spark streaming metric: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, value)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,140 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSparkStreamingStatistics = `{"startTime":"2022-06-01T10:00:00.000GMT","batchDuration":2000,"numReceivers":1,"numActiveBatches":3,
+"numProcessedRecords":10000,"avgInputRate":250.5,"avgSchedulingDelay":1200,"avgProcessingTime":3000,"avgTotalDelay":4200}`
+
+const testSparkStreamingProgress = `{"id":"8c57e1ec","batchId":42,"numInputRows":1500,"inputRowsPerSecond":300.0,"processedRowsPerSecond":200.0,
+"sources":[{"description":"KafkaV2[Subscribe[orders]]","metrics":{"avgOffsetsBehindLatest":"40.0","maxOffsetsBehindLatest":"120","minOffsetsBehindLatest":"0"}},
+{"description":"KafkaV2[Subscribe[payments]]","metrics":{"maxOffsetsBehindLatest":"30"}}]}`
+
+type parseSparkStreamingMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type sparkStreamingMetricIdentifier struct {
+	metadataTestData *parseSparkStreamingMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testSparkStreamingMetadata = []parseSparkStreamingMetadataTestData{
+	// only required properties
+	{map[string]string{"sparkURL": "http://spark-driver:4040", "targetValue": "1"}, map[string]string{}, false},
+	// application and metric
+	{map[string]string{"sparkURL": "https://spark-history:18080/", "applicationId": "app-20220601100000-0001", "metric": "backlog", "targetValue": "2", "activationTargetValue": "1", "unsafeSsl": "true"}, map[string]string{"username": "user", "password": "pass"}, false},
+	// progress of a structured streaming query
+	{map[string]string{"progressURL": "http://orders-stream:8080/progress", "metric": "inputRate", "targetValue": "500"}, map[string]string{}, false},
+	// missing sparkURL and progressURL
+	{map[string]string{"targetValue": "1"}, map[string]string{}, true},
+	// invalid urls
+	{map[string]string{"sparkURL": "spark-driver", "targetValue": "1"}, map[string]string{}, true},
+	{map[string]string{"progressURL": "orders-stream", "targetValue": "1"}, map[string]string{}, true},
+	// invalid metric
+	{map[string]string{"sparkURL": "http://spark-driver:4040", "metric": "latency", "targetValue": "1"}, map[string]string{}, true},
+	// missing targetValue
+	{map[string]string{"sparkURL": "http://spark-driver:4040"}, map[string]string{}, true},
+	// invalid values
+	{map[string]string{"sparkURL": "http://spark-driver:4040", "targetValue": "a"}, map[string]string{}, true},
+	{map[string]string{"sparkURL": "http://spark-driver:4040", "targetValue": "1", "activationTargetValue": "a"}, map[string]string{}, true},
+	{map[string]string{"sparkURL": "http://spark-driver:4040", "targetValue": "1", "unsafeSsl": "a"}, map[string]string{}, true},
+	// username without password
+	{map[string]string{"sparkURL": "http://spark-driver:4040", "targetValue": "1"}, map[string]string{"username": "user"}, true},
+}
+
+var sparkStreamingMetricIdentifiers = []sparkStreamingMetricIdentifier{
+	{&testSparkStreamingMetadata[0], 0, "s0-spark-streaming-processingRatio"},
+	{&testSparkStreamingMetadata[1], 1, "s1-spark-streaming-app-20220601100000-0001-backlog"},
+}
+
+func TestSparkStreamingParseMetadata(t *testing.T) {
+	for _, testData := range testSparkStreamingMetadata {
+		_, err := parseSparkStreamingMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestSparkStreamingGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range sparkStreamingMetricIdentifiers {
+		meta, err := parseSparkStreamingMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockSparkStreamingScaler := sparkStreamingScaler{metadata: meta}
+
+		metricSpec := mockSparkStreamingScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestSparkStreamingGetValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/applications":
+			fmt.Fprint(w, `[{"id":"app-20220601100000-0001","name":"orders"}]`)
+		case "/api/v1/applications/app-20220601100000-0001/streaming/statistics":
+			fmt.Fprint(w, testSparkStreamingStatistics)
+		case "/progress":
+			fmt.Fprint(w, testSparkStreamingProgress)
+		case "/recent-progress":
+			fmt.Fprintf(w, `[{"inputRowsPerSecond":10.0,"processedRowsPerSecond":100.0},%s]`, testSparkStreamingProgress)
+		case "/idle-progress":
+			fmt.Fprint(w, `{"inputRowsPerSecond":0.0,"processedRowsPerSecond":0.0,"sources":[{"description":"FileStreamSource","metrics":{}}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		metadata map[string]string
+		value    float64
+		isError  bool
+	}{
+		{map[string]string{"sparkURL": server.URL, "metric": "processingRatio"}, 1.5, false},
+		{map[string]string{"sparkURL": server.URL, "applicationId": "app-20220601100000-0001", "metric": "inputRate"}, 250.5, false},
+		{map[string]string{"sparkURL": server.URL, "metric": "backlog"}, 3, false},
+		{map[string]string{"sparkURL": server.URL, "applicationId": "missing"}, 0, true},
+		{map[string]string{"progressURL": server.URL + "/progress", "metric": "processingRatio"}, 1.5, false},
+		{map[string]string{"progressURL": server.URL + "/progress", "metric": "inputRate"}, 300, false},
+		{map[string]string{"progressURL": server.URL + "/progress", "metric": "backlog"}, 150, false},
+		{map[string]string{"progressURL": server.URL + "/recent-progress", "metric": "processingRatio"}, 1.5, false},
+		{map[string]string{"progressURL": server.URL + "/idle-progress", "metric": "processingRatio"}, 0, false},
+		{map[string]string{"progressURL": server.URL + "/idle-progress", "metric": "backlog"}, 0, true},
+		{map[string]string{"progressURL": server.URL + "/missing"}, 0, true},
+	}
+
+	for _, testCase := range testCases {
+		testCase.metadata["targetValue"] = "1"
+		meta, err := parseSparkStreamingMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, AuthParams: map[string]string{}})
+		assert.NoError(t, err)
+		s := sparkStreamingScaler{metadata: meta, httpClient: http.DefaultClient}
+
+		value, err := s.getValue(context.Background())
+		if testCase.isError {
+			assert.Error(t, err, "metadata %v", testCase.metadata)
+			continue
+		}
+		assert.NoError(t, err, "metadata %v", testCase.metadata)
+		assert.Equal(t, testCase.value, value, "metadata %v", testCase.metadata)
+	}
+}
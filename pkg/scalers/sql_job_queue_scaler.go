@@ -0,0 +1,351 @@
+package scalers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	sqlJobQueueDriverPostgreSQL = "postgresql"
+	sqlJobQueueDriverMySQL      = "mysql"
+
+	sqlJobQueuePresetRiver          = "river"
+	sqlJobQueuePresetQue            = "que"
+	sqlJobQueuePresetDelayedJob     = "delayed_job"
+	sqlJobQueuePresetGraphileWorker = "graphile-worker"
+	sqlJobQueuePresetCustom         = "custom"
+
+	sqlJobQueueQueueLengthDefault      = 5
+	sqlJobQueueOldestAgeSecondsDefault = 60
+
+	// sqlJobQueueTablePlaceholder is replaced by the table in the query templates
+	sqlJobQueueTablePlaceholder = "{table}"
+)
+
+// sqlJobQueueTableRegex validates the table names inserted in the queries, optionally qualified by their schema
+var sqlJobQueueTableRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// sqlJobQueuePreset describes how a job queue library stores its jobs
+type sqlJobQueuePreset struct {
+	table string
+	// unclaimed selects the jobs which are ready to run and not claimed by a worker yet, per driver
+	unclaimed map[string]string
+	// now is the current time in the time zone of availableAt, per driver
+	now         map[string]string
+	queueColumn string
+	// availableAt is the column holding the time the job became or becomes available
+	availableAt string
+}
+
+var sqlJobQueuePresets = map[string]sqlJobQueuePreset{
+	sqlJobQueuePresetRiver: {
+		table:       "river_job",
+		unclaimed:   map[string]string{sqlJobQueueDriverPostgreSQL: "state = 'available' AND scheduled_at <= NOW()"},
+		now:         map[string]string{sqlJobQueueDriverPostgreSQL: "NOW()"},
+		queueColumn: "queue",
+		availableAt: "scheduled_at",
+	},
+	// Que claims the jobs with advisory locks on their id
+	sqlJobQueuePresetQue: {
+		table: "que_jobs",
+		unclaimed: map[string]string{sqlJobQueueDriverPostgreSQL: "finished_at IS NULL AND expired_at IS NULL AND run_at <= NOW() " +
+			"AND id NOT IN (SELECT objid::bigint FROM pg_locks WHERE locktype = 'advisory' AND objsubid = 1)"},
+		now:         map[string]string{sqlJobQueueDriverPostgreSQL: "NOW()"},
+		queueColumn: "queue",
+		availableAt: "run_at",
+	},
+	// Delayed::Job stores the times in UTC without time zone
+	sqlJobQueuePresetDelayedJob: {
+		table: "delayed_jobs",
+		unclaimed: map[string]string{
+			sqlJobQueueDriverPostgreSQL: "locked_at IS NULL AND failed_at IS NULL AND run_at <= (NOW() AT TIME ZONE 'UTC')",
+			sqlJobQueueDriverMySQL:      "locked_at IS NULL AND failed_at IS NULL AND run_at <= UTC_TIMESTAMP()",
+		},
+		now: map[string]string{
+			sqlJobQueueDriverPostgreSQL: "(NOW() AT TIME ZONE 'UTC')",
+			sqlJobQueueDriverMySQL:      "UTC_TIMESTAMP()",
+		},
+		queueColumn: "queue",
+		availableAt: "run_at",
+	},
+	sqlJobQueuePresetGraphileWorker: {
+		table:       "graphile_worker.jobs",
+		unclaimed:   map[string]string{sqlJobQueueDriverPostgreSQL: "locked_at IS NULL AND run_at <= NOW() AND attempts < max_attempts"},
+		now:         map[string]string{sqlJobQueueDriverPostgreSQL: "NOW()"},
+		queueColumn: "queue_name",
+		availableAt: "run_at",
+	},
+}
+
+type sqlJobQueueScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *sqlJobQueueMetadata
+	connection *sql.DB
+}
+
+type sqlJobQueueMetadata struct {
+	driver                 string
+	connection             string
+	preset                 string
+	queue                  string
+	countQuery             string
+	oldestAgeQuery         string
+	queueLength            float64
+	includeOldestAge       bool
+	targetOldestAgeSeconds float64
+	metricName             string
+	scalerIndex            int
+}
+
+var sqlJobQueueLog = logf.Log.WithName("sql_job_queue_scaler")
+
+// NewSQLJobQueueScaler creates a new sqlJobQueueScaler
+func NewSQLJobQueueScaler(ctx context.Context, config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseSQLJobQueueMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing sql job queue metadata: %s", err))
+	}
+
+	driverName := "postgres"
+	if meta.driver == sqlJobQueueDriverMySQL {
+		driverName = "mysql"
+	}
+	db, err := sql.Open(driverName, meta.connection)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s connection: %s", meta.driver, err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error establishing %s connection: %s", meta.driver, err)
+	}
+
+	return &sqlJobQueueScaler{
+		metricType: metricType,
+		metadata:   meta,
+		connection: db,
+	}, nil
+}
+
+func parseSQLJobQueueMetadata(config *ScalerConfig) (*sqlJobQueueMetadata, error) {
+	meta := sqlJobQueueMetadata{}
+
+	meta.driver = config.TriggerMetadata["driver"]
+	if meta.driver != sqlJobQueueDriverPostgreSQL && meta.driver != sqlJobQueueDriverMySQL {
+		return nil, fmt.Errorf("driver must be either %s or %s, got %q", sqlJobQueueDriverPostgreSQL, sqlJobQueueDriverMySQL, meta.driver)
+	}
+
+	switch {
+	case config.AuthParams["connection"] != "":
+		meta.connection = config.AuthParams["connection"]
+	case config.TriggerMetadata["connectionFromEnv"] != "":
+		meta.connection = config.ResolvedEnv[config.TriggerMetadata["connectionFromEnv"]]
+	default:
+		return nil, errors.New("no connection given")
+	}
+
+	meta.queue = config.TriggerMetadata["queue"]
+
+	meta.queueLength = sqlJobQueueQueueLengthDefault
+	if val, ok := config.TriggerMetadata["queueLength"]; ok {
+		queueLength, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("queueLength parsing error %s", err.Error())
+		}
+		if queueLength <= 0 {
+			return nil, errors.New("queueLength must be greater than 0")
+		}
+		meta.queueLength = queueLength
+	}
+
+	if val, ok := config.TriggerMetadata["includeOldestAge"]; ok {
+		includeOldestAge, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("includeOldestAge parsing error %s", err.Error())
+		}
+		meta.includeOldestAge = includeOldestAge
+	}
+
+	meta.targetOldestAgeSeconds = sqlJobQueueOldestAgeSecondsDefault
+	if val, ok := config.TriggerMetadata["targetOldestAgeSeconds"]; ok {
+		targetOldestAge, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetOldestAgeSeconds parsing error %s", err.Error())
+		}
+		if targetOldestAge <= 0 {
+			return nil, errors.New("targetOldestAgeSeconds must be greater than 0")
+		}
+		meta.targetOldestAgeSeconds = targetOldestAge
+	}
+
+	meta.preset = sqlJobQueuePresetCustom
+	if val, ok := config.TriggerMetadata["preset"]; ok && val != "" {
+		meta.preset = val
+	}
+
+	table := config.TriggerMetadata["table"]
+	if table != "" && !sqlJobQueueTableRegex.MatchString(table) {
+		return nil, fmt.Errorf("table %q is not a valid table name", table)
+	}
+
+	if meta.preset == sqlJobQueuePresetCustom {
+		if err := parseSQLJobQueueCustomQueries(config, &meta, table); err != nil {
+			return nil, err
+		}
+	} else {
+		preset, ok := sqlJobQueuePresets[meta.preset]
+		if !ok {
+			return nil, fmt.Errorf("unknown preset %s", meta.preset)
+		}
+		if _, ok := preset.unclaimed[meta.driver]; !ok {
+			return nil, fmt.Errorf("preset %s doesn't support the %s driver", meta.preset, meta.driver)
+		}
+		if table == "" {
+			table = preset.table
+		}
+		meta.countQuery, meta.oldestAgeQuery = buildSQLJobQueueQueries(preset, meta.driver, table, meta.queue != "")
+	}
+
+	metricName := fmt.Sprintf("sql-job-queue-%s", meta.preset)
+	if meta.queue != "" {
+		metricName = fmt.Sprintf("%s-%s", metricName, meta.queue)
+	}
+	meta.metricName = kedautil.NormalizeString(metricName)
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// parseSQLJobQueueCustomQueries reads the query templates, the queue is bound to their first parameter when it's set
+func parseSQLJobQueueCustomQueries(config *ScalerConfig, meta *sqlJobQueueMetadata, table string) error {
+	meta.countQuery = config.TriggerMetadata["countQuery"]
+	if meta.countQuery == "" {
+		return fmt.Errorf("no countQuery given for the %s preset", sqlJobQueuePresetCustom)
+	}
+
+	meta.oldestAgeQuery = config.TriggerMetadata["oldestAgeQuery"]
+	if meta.includeOldestAge && meta.oldestAgeQuery == "" {
+		return errors.New("no oldestAgeQuery given while includeOldestAge is set")
+	}
+
+	if strings.Contains(meta.countQuery+meta.oldestAgeQuery, sqlJobQueueTablePlaceholder) {
+		if table == "" {
+			return fmt.Errorf("no table given for the %s placeholder", sqlJobQueueTablePlaceholder)
+		}
+		meta.countQuery = strings.ReplaceAll(meta.countQuery, sqlJobQueueTablePlaceholder, table)
+		meta.oldestAgeQuery = strings.ReplaceAll(meta.oldestAgeQuery, sqlJobQueueTablePlaceholder, table)
+	}
+	return nil
+}
+
+// buildSQLJobQueueQueries returns the queries counting the unclaimed jobs of the preset and returning the age of the oldest one in seconds
+func buildSQLJobQueueQueries(preset sqlJobQueuePreset, driver, table string, filterQueue bool) (string, string) {
+	where := preset.unclaimed[driver]
+	if filterQueue {
+		placeholder := "$1"
+		if driver == sqlJobQueueDriverMySQL {
+			placeholder = "?"
+		}
+		where = fmt.Sprintf("%s AND %s = %s", where, preset.queueColumn, placeholder)
+	}
+
+	age := fmt.Sprintf("COALESCE(EXTRACT(EPOCH FROM (%s - MIN(%s))), 0)", preset.now[driver], preset.availableAt)
+	if driver == sqlJobQueueDriverMySQL {
+		age = fmt.Sprintf("COALESCE(TIMESTAMPDIFF(SECOND, MIN(%s), %s), 0)", preset.availableAt, preset.now[driver])
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", table, where)
+	oldestAgeQuery := fmt.Sprintf("SELECT %s FROM %s WHERE %s", age, table, where)
+	return countQuery, oldestAgeQuery
+}
+
+// Close disposes of the database connection
+func (s *sqlJobQueueScaler) Close(context.Context) error {
+	if err := s.connection.Close(); err != nil {
+		sqlJobQueueLog.Error(err, "error closing database connection")
+		return err
+	}
+	return nil
+}
+
+// IsActive returns true if there are unclaimed jobs
+func (s *sqlJobQueueScaler) IsActive(ctx context.Context) (bool, error) {
+	jobs, err := s.runQuery(ctx, s.metadata.countQuery)
+	if err != nil {
+		sqlJobQueueLog.Error(err, "error counting unclaimed jobs")
+		return false, err
+	}
+	return jobs > 0, nil
+}
+
+// GetMetricSpecForScaling returns the metric spec of the unclaimed jobs, and of the age of the oldest one if requested
+func (s *sqlJobQueueScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	metricSpecs := []v2beta2.MetricSpec{
+		s.getMetricSpec(s.metadata.metricName, s.metadata.queueLength),
+	}
+	if s.metadata.includeOldestAge {
+		metricSpecs = append(metricSpecs, s.getMetricSpec(s.getOldestAgeMetricName(), s.metadata.targetOldestAgeSeconds))
+	}
+	return metricSpecs
+}
+
+func (s *sqlJobQueueScaler) getMetricSpec(metricName string, target float64) v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, metricName),
+		},
+		Target: GetMetricTargetMili(s.metricType, target),
+	}
+	return v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+}
+
+func (s *sqlJobQueueScaler) getOldestAgeMetricName() string {
+	return fmt.Sprintf("%s-oldest-age", s.metadata.metricName)
+}
+
+// GetMetrics returns the number of unclaimed jobs or the age of the oldest one, depending on the metric name
+func (s *sqlJobQueueScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	query := s.metadata.countQuery
+	if s.metadata.includeOldestAge && metricName == GenerateMetricNameWithIndex(s.metadata.scalerIndex, s.getOldestAgeMetricName()) {
+		query = s.metadata.oldestAgeQuery
+	}
+
+	value, err := s.runQuery(ctx, query)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting sql job queue: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, value)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+func (s *sqlJobQueueScaler) runQuery(ctx context.Context, query string) (float64, error) {
+	var args []interface{}
+	if s.metadata.queue != "" {
+		args = append(args, s.metadata.queue)
+	}
+
+	var value sql.NullFloat64
+	if err := s.connection.QueryRowContext(ctx, query, args...).Scan(&value); err != nil {
+		return -1, err
+	}
+	return value.Float64, nil
+}
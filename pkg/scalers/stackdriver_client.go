@@ -26,7 +26,7 @@ type StackDriverClient struct {
 }
 
 // NewStackDriverClient creates a new stackdriver client with the credentials that are passed
-func NewStackDriverClient(ctx context.Context, credentials string) (*StackDriverClient, error) {
+func NewStackDriverClient(ctx context.Context, credentials string, opts ...option.ClientOption) (*StackDriverClient, error) {
 	var gcpCredentials GoogleApplicationCredentials
 
 	if err := json.Unmarshal([]byte(credentials), &gcpCredentials); err != nil {
@@ -35,7 +35,7 @@ func NewStackDriverClient(ctx context.Context, credentials string) (*StackDriver
 
 	clientOption := option.WithCredentialsJSON([]byte(credentials))
 
-	client, err := monitoring.NewMetricClient(ctx, clientOption)
+	client, err := monitoring.NewMetricClient(ctx, append([]option.ClientOption{clientOption}, opts...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -47,8 +47,8 @@ func NewStackDriverClient(ctx context.Context, credentials string) (*StackDriver
 }
 
 // NewStackDriverClient creates a new stackdriver client with the credentials underlying
-func NewStackDriverClientPodIdentity(ctx context.Context) (*StackDriverClient, error) {
-	client, err := monitoring.NewMetricClient(ctx)
+func NewStackDriverClientPodIdentity(ctx context.Context, opts ...option.ClientOption) (*StackDriverClient, error) {
+	client, err := monitoring.NewMetricClient(ctx, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -63,19 +63,19 @@ var stanLog = logf.Log.WithName("stan_scaler")
 func NewStanScaler(config *ScalerConfig) (Scaler, error) {
 	metricType, err := GetMetricTargetType(config)
 	if err != nil {
-		return nil, fmt.Errorf("error getting scaler metric type: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
 	}
 
 	stanMetadata, err := parseStanMetadata(config)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing stan metadata: %s", err)
+		return nil, NewPermanentError(fmt.Errorf("error parsing stan metadata: %s", err))
 	}
 
 	return &stanScaler{
 		channelInfo: &monitorChannelInfo{},
 		metricType:  metricType,
 		metadata:    stanMetadata,
-		httpClient:  kedautil.CreateHTTPClient(config.GlobalHTTPTimeout, false),
+		httpClient:  createHTTPClient(config, config.GlobalHTTPTimeout, false),
 	}, nil
 }
 
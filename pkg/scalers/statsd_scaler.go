@@ -0,0 +1,185 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kedacore/keda/v2/pkg/otlp"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	defaultStatsDTTL = 5 * time.Minute
+)
+
+type statsdScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *statsdMetadata
+	httpClient *http.Client
+}
+
+type statsdMetadata struct {
+	listenerURL     string
+	metricName      string
+	tags            map[string]string
+	ttl             time.Duration
+	targetValue     float64
+	activationValue float64
+	scalerIndex     int
+}
+
+var statsdLog = logf.Log.WithName("statsd_scaler")
+
+// NewStatsDScaler creates a new statsdScaler, scaling on the latest value of a gauge sent to the StatsD listener of the
+// operator, which is enabled with --statsd-bind-address
+func NewStatsDScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseStatsDMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing statsd metadata: %s", err))
+	}
+
+	return &statsdScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, false),
+	}, nil
+}
+
+func parseStatsDMetadata(config *ScalerConfig) (*statsdMetadata, error) {
+	meta := statsdMetadata{
+		tags: map[string]string{},
+		ttl:  defaultStatsDTTL,
+	}
+
+	if val, ok := config.TriggerMetadata["listenerURL"]; ok && val != "" {
+		if _, err := url.ParseRequestURI(val); err != nil {
+			return nil, fmt.Errorf("invalid listenerURL: %s", err)
+		}
+		meta.listenerURL = strings.TrimSuffix(val, "/")
+	} else {
+		return nil, fmt.Errorf("no listenerURL given in metadata")
+	}
+
+	if val, ok := config.TriggerMetadata["metricName"]; ok && val != "" {
+		meta.metricName = val
+	} else {
+		return nil, fmt.Errorf("no metricName given in metadata")
+	}
+
+	// the tags use the DogStatsD syntax, a tag without value matches the gauges sent with this bare tag
+	if val, ok := config.TriggerMetadata["tags"]; ok && val != "" {
+		for _, tag := range splitAndTrim(val) {
+			kv := strings.SplitN(tag, ":", 2)
+			if kv[0] == "" {
+				return nil, fmt.Errorf("invalid tag %q, expected key:value", tag)
+			}
+			if len(kv) == 2 {
+				meta.tags[kv[0]] = kv[1]
+			} else {
+				meta.tags[kv[0]] = ""
+			}
+		}
+	}
+
+	if val, ok := config.TriggerMetadata["ttl"]; ok && val != "" {
+		ttl, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("ttl parsing error %s", err.Error())
+		}
+		if ttl <= 0 || time.Duration(ttl)*time.Second > otlp.SeriesRetention {
+			return nil, fmt.Errorf("ttl must be between 1 and %d seconds", int(otlp.SeriesRetention.Seconds()))
+		}
+		meta.ttl = time.Duration(ttl) * time.Second
+	}
+
+	if val, ok := config.TriggerMetadata["targetValue"]; ok && val != "" {
+		targetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetValue parsing error %s", err.Error())
+		}
+		meta.targetValue = targetValue
+	} else {
+		return nil, fmt.Errorf("no targetValue given in metadata")
+	}
+
+	if val, ok := config.TriggerMetadata["activationValue"]; ok && val != "" {
+		activationValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationValue parsing error %s", err.Error())
+		}
+		meta.activationValue = activationValue
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// getMetricValue returns the latest value of the gauge, summed over the series having the tags, or 0 if the gauge
+// wasn't sent within the ttl
+func (s *statsdScaler) getMetricValue(ctx context.Context) (float64, error) {
+	value, series, err := queryPushedMetric(ctx, s.httpClient, s.metadata.listenerURL, s.metadata.metricName, s.metadata.tags, s.metadata.ttl, "")
+	if err != nil {
+		return 0, err
+	}
+	if series == 0 {
+		statsdLog.V(1).Info("No recent value of the gauge", "metricName", s.metadata.metricName, "tags", s.metadata.tags)
+	}
+	return value, nil
+}
+
+// Close does nothing in case of statsdScaler
+func (s *statsdScaler) Close(context.Context) error {
+	return nil
+}
+
+// IsActive returns true if the latest value of the gauge is greater than activationValue
+func (s *statsdScaler) IsActive(ctx context.Context) (bool, error) {
+	v, err := s.getMetricValue(ctx)
+	if err != nil {
+		statsdLog.Error(err, "error getting statsd gauge value")
+		return false, err
+	}
+
+	return v > s.metadata.activationValue, nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *statsdScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("statsd-%s", s.metadata.metricName))),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the latest value of the gauge
+func (s *statsdScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	val, err := s.getMetricValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error requesting statsd listener: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, val)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
@@ -0,0 +1,124 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kedacore/keda/v2/pkg/otlp"
+)
+
+type parseStatsDMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+type statsdMetricIdentifier struct {
+	metadataTestData *parseStatsDMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testStatsDMetadata = []parseStatsDMetadataTestData{
+	// only required properties
+	{map[string]string{"listenerURL": "http://keda-operator.keda:8125", "metricName": "queue_depth", "targetValue": "10"}, false},
+	// tags, ttl and activationValue
+	{map[string]string{"listenerURL": "http://keda-operator.keda:8125", "metricName": "app.queue.depth", "targetValue": "10", "tags": "queue:orders, canary", "ttl": "60", "activationValue": "2"}, false},
+	// missing listenerURL
+	{map[string]string{"metricName": "queue_depth", "targetValue": "10"}, true},
+	// invalid listenerURL
+	{map[string]string{"listenerURL": "keda-operator", "metricName": "queue_depth", "targetValue": "10"}, true},
+	// missing metricName
+	{map[string]string{"listenerURL": "http://keda-operator.keda:8125", "targetValue": "10"}, true},
+	// invalid tags
+	{map[string]string{"listenerURL": "http://keda-operator.keda:8125", "metricName": "queue_depth", "targetValue": "10", "tags": ":orders"}, true},
+	// invalid ttl
+	{map[string]string{"listenerURL": "http://keda-operator.keda:8125", "metricName": "queue_depth", "targetValue": "10", "ttl": "a"}, true},
+	{map[string]string{"listenerURL": "http://keda-operator.keda:8125", "metricName": "queue_depth", "targetValue": "10", "ttl": "0"}, true},
+	{map[string]string{"listenerURL": "http://keda-operator.keda:8125", "metricName": "queue_depth", "targetValue": "10", "ttl": "7200"}, true},
+	// missing targetValue
+	{map[string]string{"listenerURL": "http://keda-operator.keda:8125", "metricName": "queue_depth"}, true},
+	// invalid activationValue
+	{map[string]string{"listenerURL": "http://keda-operator.keda:8125", "metricName": "queue_depth", "targetValue": "10", "activationValue": "a"}, true},
+}
+
+var statsdMetricIdentifiers = []statsdMetricIdentifier{
+	{&testStatsDMetadata[0], 0, "s0-statsd-queue_depth"},
+	{&testStatsDMetadata[1], 1, "s1-statsd-app-queue-depth"},
+}
+
+func TestStatsDParseMetadata(t *testing.T) {
+	for _, testData := range testStatsDMetadata {
+		_, err := parseStatsDMetadata(&ScalerConfig{TriggerMetadata: testData.metadata})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestStatsDGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range statsdMetricIdentifiers {
+		meta, err := parseStatsDMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockStatsDScaler := statsdScaler{metadata: meta}
+
+		metricSpec := mockStatsDScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestStatsDIsActive(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, otlp.QueryPath, r.URL.Path)
+		assert.Equal(t, "1m0s", r.URL.Query().Get("maxAge"))
+		assert.ElementsMatch(t, []string{"queue=orders", "canary="}, r.URL.Query()["attribute"])
+		switch r.URL.Query().Get("name") {
+		case "queue_depth":
+			fmt.Fprint(w, `{"value":3,"series":1}`)
+		case "stale":
+			fmt.Fprint(w, `{"value":0,"series":0}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		metricName      string
+		activationValue string
+		isActive        bool
+		isError         bool
+	}{
+		{"queue_depth", "0", true, false},
+		{"queue_depth", "3", false, false},
+		{"stale", "0", false, false},
+		{"invalid", "0", false, true},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parseStatsDMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"listenerURL": server.URL, "metricName": testCase.metricName,
+			"targetValue": "10", "tags": "queue:orders,canary", "ttl": "60", "activationValue": testCase.activationValue}})
+		assert.NoError(t, err)
+		s := statsdScaler{metadata: meta, httpClient: http.DefaultClient}
+
+		isActive, err := s.IsActive(context.Background())
+		if testCase.isError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.isActive, isActive, "metric %s, activationValue %s", testCase.metricName, testCase.activationValue)
+	}
+}
@@ -0,0 +1,212 @@
+package scalers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	tektonAPIVersion          = "tekton.dev/v1beta1"
+	tektonResourcePipelineRun = "pipelineruns"
+	tektonResourceTaskRun     = "taskruns"
+	tektonStatePending        = "pending"
+	tektonStateRunning        = "running"
+	tektonTargetRunCount      = 1
+)
+
+// tektonResourceKinds maps the resource metadata values to the list kinds of the Tekton API
+var tektonResourceKinds = map[string]string{
+	tektonResourcePipelineRun: "PipelineRunList",
+	tektonResourceTaskRun:     "TaskRunList",
+}
+
+type tektonScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *tektonMetadata
+	kubeClient client.Client
+}
+
+type tektonMetadata struct {
+	resource       string
+	namespace      string
+	labelSelector  labels.Selector
+	states         map[string]bool
+	targetRunCount float64
+	scalerIndex    int
+}
+
+var tektonLog = logf.Log.WithName("tekton_scaler")
+
+// NewTektonScaler creates a new tektonScaler
+func NewTektonScaler(kubeClient client.Client, config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseTektonMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing tekton metadata: %s", err))
+	}
+
+	return &tektonScaler{
+		metricType: metricType,
+		metadata:   meta,
+		kubeClient: kubeClient,
+	}, nil
+}
+
+func parseTektonMetadata(config *ScalerConfig) (*tektonMetadata, error) {
+	meta := tektonMetadata{}
+
+	meta.resource = tektonResourcePipelineRun
+	if val, ok := config.TriggerMetadata["resource"]; ok && val != "" {
+		meta.resource = strings.ToLower(val)
+	}
+	if _, ok := tektonResourceKinds[meta.resource]; !ok {
+		return nil, fmt.Errorf("resource must be either %s or %s, got %s", tektonResourcePipelineRun, tektonResourceTaskRun, meta.resource)
+	}
+
+	meta.namespace = config.Namespace
+	if val, ok := config.TriggerMetadata["namespace"]; ok && val != "" {
+		meta.namespace = val
+	}
+
+	meta.labelSelector = labels.Everything()
+	if val, ok := config.TriggerMetadata["labelSelector"]; ok && val != "" {
+		selector, err := labels.Parse(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid labelSelector: %s", err)
+		}
+		meta.labelSelector = selector
+	}
+
+	meta.states = map[string]bool{tektonStatePending: true, tektonStateRunning: true}
+	if val, ok := config.TriggerMetadata["states"]; ok && val != "" {
+		meta.states = map[string]bool{}
+		for _, state := range strings.Split(val, ",") {
+			state = strings.ToLower(strings.TrimSpace(state))
+			if state != tektonStatePending && state != tektonStateRunning {
+				return nil, fmt.Errorf("states must be a list of %s and %s, got %s", tektonStatePending, tektonStateRunning, state)
+			}
+			meta.states[state] = true
+		}
+	}
+
+	meta.targetRunCount = tektonTargetRunCount
+	if val, ok := config.TriggerMetadata["targetRunCount"]; ok {
+		targetRunCount, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetRunCount parsing error %s", err.Error())
+		}
+		if targetRunCount <= 0 {
+			return nil, errors.New("targetRunCount must be greater than 0")
+		}
+		meta.targetRunCount = targetRunCount
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// IsActive returns true if there are runs in the counted states
+func (s *tektonScaler) IsActive(ctx context.Context) (bool, error) {
+	runs, err := s.getRunCount(ctx)
+	if err != nil {
+		tektonLog.Error(err, "error counting tekton runs")
+		return false, err
+	}
+	return runs > 0, nil
+}
+
+// Close no need for tekton scaler
+func (s *tektonScaler) Close(context.Context) error {
+	return nil
+}
+
+// GetMetricSpecForScaling returns the metric spec for the HPA
+func (s *tektonScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("tekton-%s-%s", s.metadata.resource, s.metadata.namespace))),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetRunCount),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the number of runs in the counted states
+func (s *tektonScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	runs, err := s.getRunCount(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error counting tekton runs: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, float64(runs))
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
+
+func (s *tektonScaler) getRunCount(ctx context.Context) (int64, error) {
+	runList := &unstructured.UnstructuredList{}
+	runList.SetGroupVersionKind(schema.FromAPIVersionAndKind(tektonAPIVersion, tektonResourceKinds[s.metadata.resource]))
+
+	err := s.kubeClient.List(ctx, runList, &client.ListOptions{
+		Namespace:     s.metadata.namespace,
+		LabelSelector: s.metadata.labelSelector,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for _, run := range runList.Items {
+		if s.metadata.states[getTektonRunState(run)] {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// getTektonRunState returns pending for the runs not started yet, running for the started ones
+// and an empty string for the completed ones, based on their Succeeded condition
+func getTektonRunState(run unstructured.Unstructured) string {
+	// a PipelineRun created with spec.status PipelineRunPending waits to be started
+	if specStatus, _, _ := unstructured.NestedString(run.Object, "spec", "status"); specStatus == "PipelineRunPending" {
+		return tektonStatePending
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(run.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Succeeded" {
+			continue
+		}
+		if condition["status"] != "Unknown" {
+			return ""
+		}
+		if reason, _ := condition["reason"].(string); reason == "Pending" || reason == "PipelineRunPending" {
+			return tektonStatePending
+		}
+		return tektonStateRunning
+	}
+
+	// the controller didn't pick the run yet
+	return tektonStatePending
+}
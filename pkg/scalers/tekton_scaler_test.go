@@ -0,0 +1,123 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type parseTektonMetadataTestData struct {
+	metadata map[string]string
+	isError  bool
+}
+
+type tektonMetricIdentifier struct {
+	metadataTestData *parseTektonMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testTektonMetadata = []parseTektonMetadataTestData{
+	// defaults
+	{map[string]string{}, false},
+	// all properties
+	{map[string]string{"resource": "taskruns", "namespace": "ci", "labelSelector": "tekton.dev/pipeline=build", "states": "pending, running", "targetRunCount": "2"}, false},
+	// invalid resource
+	{map[string]string{"resource": "pipelines"}, true},
+	// invalid labelSelector
+	{map[string]string{"labelSelector": "app in (a"}, true},
+	// invalid state
+	{map[string]string{"states": "pending,succeeded"}, true},
+	// invalid targetRunCount
+	{map[string]string{"targetRunCount": "a"}, true},
+	{map[string]string{"targetRunCount": "0"}, true},
+}
+
+var tektonMetricIdentifiers = []tektonMetricIdentifier{
+	{&testTektonMetadata[0], 0, "s0-tekton-pipelineruns-default"},
+	{&testTektonMetadata[1], 1, "s1-tekton-taskruns-ci"},
+}
+
+func TestTektonParseMetadata(t *testing.T) {
+	for _, testData := range testTektonMetadata {
+		_, err := parseTektonMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, Namespace: "default"})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestTektonGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range tektonMetricIdentifiers {
+		meta, err := parseTektonMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, Namespace: "default", ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockTektonScaler := tektonScaler{metadata: meta}
+
+		metricSpec := mockTektonScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func newTektonRun(kind, name string, labels map[string]string, specStatus string, condition map[string]interface{}) runtime.Object {
+	run := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": tektonAPIVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		"spec":       map[string]interface{}{},
+	}}
+	run.SetLabels(labels)
+	if specStatus != "" {
+		_ = unstructured.SetNestedField(run.Object, specStatus, "spec", "status")
+	}
+	if condition != nil {
+		_ = unstructured.SetNestedSlice(run.Object, []interface{}{condition}, "status", "conditions")
+	}
+	return run
+}
+
+func TestTektonGetRunCount(t *testing.T) {
+	build := map[string]string{"tekton.dev/pipeline": "build"}
+	runs := []runtime.Object{
+		newTektonRun("PipelineRun", "queued", build, "PipelineRunPending", nil),
+		newTektonRun("PipelineRun", "new", build, "", nil),
+		newTektonRun("PipelineRun", "running", build, "", map[string]interface{}{"type": "Succeeded", "status": "Unknown", "reason": "Running"}),
+		newTektonRun("PipelineRun", "succeeded", build, "", map[string]interface{}{"type": "Succeeded", "status": "True", "reason": "Succeeded"}),
+		newTektonRun("PipelineRun", "failed", build, "", map[string]interface{}{"type": "Succeeded", "status": "False", "reason": "Failed"}),
+		newTektonRun("PipelineRun", "other", map[string]string{"tekton.dev/pipeline": "deploy"}, "", nil),
+		newTektonRun("TaskRun", "task", build, "", map[string]interface{}{"type": "Succeeded", "status": "Unknown", "reason": "Pending"}),
+	}
+
+	testCases := []struct {
+		metadata map[string]string
+		expected int64
+	}{
+		{map[string]string{"labelSelector": "tekton.dev/pipeline=build"}, 3},
+		{map[string]string{"labelSelector": "tekton.dev/pipeline=build", "states": "pending"}, 2},
+		{map[string]string{"labelSelector": "tekton.dev/pipeline=build", "states": "running"}, 1},
+		{map[string]string{}, 4},
+		{map[string]string{"resource": "taskruns"}, 1},
+		{map[string]string{"namespace": "other"}, 0},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parseTektonMetadata(&ScalerConfig{TriggerMetadata: testCase.metadata, Namespace: "default"})
+		assert.NoError(t, err)
+		s := tektonScaler{metadata: meta, kubeClient: fake.NewClientBuilder().WithRuntimeObjects(runs...).Build()}
+
+		count, err := s.getRunCount(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.expected, count, "metadata %v", testCase.metadata)
+	}
+}
@@ -0,0 +1,192 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kedacore/keda/v2/pkg/otlp"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+	"github.com/kedacore/keda/v2/pkg/webhook"
+)
+
+const (
+	defaultWebhookTTL = 5 * time.Minute
+)
+
+type webhookScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *webhookMetadata
+	httpClient *http.Client
+}
+
+type webhookMetadata struct {
+	receiverURL     string
+	namespace       string
+	webhookName     string
+	token           string
+	ttl             time.Duration
+	targetValue     float64
+	activationValue float64
+	unsafeSsl       bool
+	scalerIndex     int
+}
+
+var webhookLog = logf.Log.WithName("webhook_scaler")
+
+// NewWebhookScaler creates a new webhookScaler, scaling on the latest value external systems POST to a webhook of the
+// webhook receiver of the operator, which is enabled with --webhook-receiver-bind-address. The token of the webhook,
+// the token key of its keda-webhook-<name> Secret, is given through a TriggerAuthentication.
+func NewWebhookScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseWebhookMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing webhook metadata: %s", err))
+	}
+
+	return &webhookScaler{
+		metricType: metricType,
+		metadata:   meta,
+		httpClient: createHTTPClient(config, config.GlobalHTTPTimeout, meta.unsafeSsl),
+	}, nil
+}
+
+func parseWebhookMetadata(config *ScalerConfig) (*webhookMetadata, error) {
+	meta := webhookMetadata{
+		ttl: defaultWebhookTTL,
+	}
+
+	if val, ok := config.TriggerMetadata["receiverURL"]; ok && val != "" {
+		if _, err := url.ParseRequestURI(val); err != nil {
+			return nil, fmt.Errorf("invalid receiverURL: %s", err)
+		}
+		meta.receiverURL = strings.TrimSuffix(val, "/")
+	} else {
+		return nil, fmt.Errorf("no receiverURL given in metadata")
+	}
+
+	// a trigger only reads the webhooks of the namespace of its ScaledObject
+	meta.namespace = config.Namespace
+	if val, ok := config.TriggerMetadata["webhookName"]; ok && val != "" {
+		if strings.Contains(val, "/") {
+			return nil, fmt.Errorf("invalid webhookName %q", val)
+		}
+		meta.webhookName = val
+	} else {
+		return nil, fmt.Errorf("no webhookName given in metadata")
+	}
+
+	// the receiver only serves the values of a webhook to the holders of its token
+	if val, ok := config.AuthParams["token"]; ok && val != "" {
+		meta.token = val
+	} else {
+		return nil, fmt.Errorf("no token given in the auth params of the webhook")
+	}
+
+	if val, ok := config.TriggerMetadata["ttl"]; ok && val != "" {
+		ttl, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("ttl parsing error %s", err.Error())
+		}
+		if ttl <= 0 || time.Duration(ttl)*time.Second > otlp.SeriesRetention {
+			return nil, fmt.Errorf("ttl must be between 1 and %d seconds", int(otlp.SeriesRetention.Seconds()))
+		}
+		meta.ttl = time.Duration(ttl) * time.Second
+	}
+
+	if val, ok := config.TriggerMetadata["targetValue"]; ok && val != "" {
+		targetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetValue parsing error %s", err.Error())
+		}
+		meta.targetValue = targetValue
+	} else {
+		return nil, fmt.Errorf("no targetValue given in metadata")
+	}
+
+	if val, ok := config.TriggerMetadata["activationValue"]; ok && val != "" {
+		activationValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("activationValue parsing error %s", err.Error())
+		}
+		meta.activationValue = activationValue
+	}
+
+	if val, ok := config.TriggerMetadata["unsafeSsl"]; ok && val != "" {
+		unsafeSsl, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("unsafeSsl parsing error %s", err.Error())
+		}
+		meta.unsafeSsl = unsafeSsl
+	}
+
+	meta.scalerIndex = config.ScalerIndex
+	return &meta, nil
+}
+
+// getMetricValue returns the latest value pushed to the webhook, or 0 if no value was pushed within the ttl
+func (s *webhookScaler) getMetricValue(ctx context.Context) (float64, error) {
+	value, series, err := queryPushedMetric(ctx, s.httpClient, s.metadata.receiverURL, webhook.MetricName(s.metadata.namespace, s.metadata.webhookName), nil, s.metadata.ttl, s.metadata.token)
+	if err != nil {
+		return 0, err
+	}
+	if series == 0 {
+		webhookLog.V(1).Info("No recent value of the webhook", "namespace", s.metadata.namespace, "webhookName", s.metadata.webhookName)
+	}
+	return value, nil
+}
+
+// Close does nothing in case of webhookScaler
+func (s *webhookScaler) Close(context.Context) error {
+	return nil
+}
+
+// IsActive returns true if the latest value of the webhook is greater than activationValue
+func (s *webhookScaler) IsActive(ctx context.Context) (bool, error) {
+	v, err := s.getMetricValue(ctx)
+	if err != nil {
+		webhookLog.Error(err, "error getting webhook value")
+		return false, err
+	}
+
+	return v > s.metadata.activationValue, nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *webhookScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, kedautil.NormalizeString(fmt.Sprintf("webhook-%s", s.metadata.webhookName))),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns the latest value of the webhook
+func (s *webhookScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	val, err := s.getMetricValue(ctx)
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error requesting webhook receiver: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, val)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
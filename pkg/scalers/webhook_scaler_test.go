@@ -0,0 +1,132 @@
+package scalers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kedacore/keda/v2/pkg/otlp"
+)
+
+type parseWebhookMetadataTestData struct {
+	metadata   map[string]string
+	authParams map[string]string
+	isError    bool
+}
+
+type webhookMetricIdentifier struct {
+	metadataTestData *parseWebhookMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testWebhookAuthParams = map[string]string{"token": "s3cret"}
+
+var testWebhookMetadata = []parseWebhookMetadataTestData{
+	// only required properties
+	{map[string]string{"receiverURL": "https://keda-operator.keda:9443", "webhookName": "twilio", "targetValue": "10"}, testWebhookAuthParams, false},
+	// ttl, activationValue and unsafeSsl
+	{map[string]string{"receiverURL": "https://keda-operator.keda:9443/", "webhookName": "sms.backlog", "targetValue": "10", "ttl": "60", "activationValue": "2", "unsafeSsl": "true"}, testWebhookAuthParams, false},
+	// missing receiverURL
+	{map[string]string{"webhookName": "twilio", "targetValue": "10"}, testWebhookAuthParams, true},
+	// invalid receiverURL
+	{map[string]string{"receiverURL": "keda-operator", "webhookName": "twilio", "targetValue": "10"}, testWebhookAuthParams, true},
+	// missing webhookName
+	{map[string]string{"receiverURL": "https://keda-operator.keda:9443", "targetValue": "10"}, testWebhookAuthParams, true},
+	// webhookName of another namespace
+	{map[string]string{"receiverURL": "https://keda-operator.keda:9443", "webhookName": "payments/stripe", "targetValue": "10"}, testWebhookAuthParams, true},
+	// invalid ttl
+	{map[string]string{"receiverURL": "https://keda-operator.keda:9443", "webhookName": "twilio", "targetValue": "10", "ttl": "a"}, testWebhookAuthParams, true},
+	{map[string]string{"receiverURL": "https://keda-operator.keda:9443", "webhookName": "twilio", "targetValue": "10", "ttl": "0"}, testWebhookAuthParams, true},
+	{map[string]string{"receiverURL": "https://keda-operator.keda:9443", "webhookName": "twilio", "targetValue": "10", "ttl": "7200"}, testWebhookAuthParams, true},
+	// missing targetValue
+	{map[string]string{"receiverURL": "https://keda-operator.keda:9443", "webhookName": "twilio"}, testWebhookAuthParams, true},
+	// invalid activationValue
+	{map[string]string{"receiverURL": "https://keda-operator.keda:9443", "webhookName": "twilio", "targetValue": "10", "activationValue": "a"}, testWebhookAuthParams, true},
+	// missing token
+	{map[string]string{"receiverURL": "https://keda-operator.keda:9443", "webhookName": "twilio", "targetValue": "10"}, map[string]string{}, true},
+	// invalid unsafeSsl
+	{map[string]string{"receiverURL": "https://keda-operator.keda:9443", "webhookName": "twilio", "targetValue": "10", "unsafeSsl": "a"}, testWebhookAuthParams, true},
+}
+
+var webhookMetricIdentifiers = []webhookMetricIdentifier{
+	{&testWebhookMetadata[0], 0, "s0-webhook-twilio"},
+	{&testWebhookMetadata[1], 1, "s1-webhook-sms-backlog"},
+}
+
+func TestWebhookParseMetadata(t *testing.T) {
+	for _, testData := range testWebhookMetadata {
+		_, err := parseWebhookMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams, Namespace: "sms"})
+		if err != nil && !testData.isError {
+			t.Error("Expected success but got error", err)
+		}
+		if testData.isError && err == nil {
+			t.Error("Expected error but got success")
+		}
+	}
+}
+
+func TestWebhookGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range webhookMetricIdentifiers {
+		meta, err := parseWebhookMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, Namespace: "sms", ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockWebhookScaler := webhookScaler{metadata: meta}
+
+		metricSpec := mockWebhookScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
+
+func TestWebhookIsActive(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, otlp.QueryPath, r.URL.Path)
+		assert.Equal(t, "Bearer s3cret", r.Header.Get("Authorization"))
+		assert.Equal(t, "1m0s", r.URL.Query().Get("maxAge"))
+		assert.Empty(t, r.URL.Query()["attribute"])
+		switch r.URL.Query().Get("name") {
+		case "sms/twilio":
+			fmt.Fprint(w, `{"value":3,"series":1}`)
+		case "sms/expired":
+			fmt.Fprint(w, `{"value":0,"series":0}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		webhookName     string
+		activationValue string
+		isActive        bool
+		isError         bool
+	}{
+		{"twilio", "0", true, false},
+		{"twilio", "3", false, false},
+		{"expired", "0", false, false},
+		{"invalid", "0", false, true},
+	}
+
+	for _, testCase := range testCases {
+		meta, err := parseWebhookMetadata(&ScalerConfig{TriggerMetadata: map[string]string{"receiverURL": server.URL, "webhookName": testCase.webhookName,
+			"targetValue": "10", "ttl": "60", "activationValue": testCase.activationValue}, AuthParams: testWebhookAuthParams, Namespace: "sms"})
+		assert.NoError(t, err)
+		s := webhookScaler{metadata: meta, httpClient: http.DefaultClient}
+
+		isActive, err := s.IsActive(context.Background())
+		if testCase.isError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.isActive, isActive, "webhook %s, activationValue %s", testCase.webhookName, testCase.activationValue)
+	}
+}
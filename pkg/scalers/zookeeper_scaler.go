@@ -0,0 +1,246 @@
+package scalers
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const defaultZookeeperSessionTimeout = 10
+
+type zookeeperScaler struct {
+	metricType v2beta2.MetricTargetType
+	metadata   *zookeeperMetadata
+	conn       *zk.Conn
+}
+
+type zookeeperMetadata struct {
+	hosts          []string
+	path           string
+	targetValue    float64
+	sessionTimeout int
+	metricName     string
+	scalerIndex    int
+
+	username string
+	password string
+
+	enableTLS bool
+	cert      string
+	key       string
+	ca        string
+}
+
+var zookeeperLog = logf.Log.WithName("zookeeper_scaler")
+
+// zookeeperLogger forwards the zk client logs to the scaler logger instead of the standard log package
+type zookeeperLogger struct{}
+
+func (zookeeperLogger) Printf(format string, args ...interface{}) {
+	zookeeperLog.V(1).Info(fmt.Sprintf(format, args...))
+}
+
+// NewZookeeperScaler creates a new zookeeper scaler
+func NewZookeeperScaler(config *ScalerConfig) (Scaler, error) {
+	metricType, err := GetMetricTargetType(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error getting scaler metric type: %s", err))
+	}
+
+	meta, err := parseZookeeperMetadata(config)
+	if err != nil {
+		return nil, NewPermanentError(fmt.Errorf("error parsing zookeeper metadata: %s", err))
+	}
+
+	conn, err := getZookeeperConnection(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return &zookeeperScaler{
+		metricType: metricType,
+		metadata:   meta,
+		conn:       conn,
+	}, nil
+}
+
+func parseZookeeperMetadata(config *ScalerConfig) (*zookeeperMetadata, error) {
+	meta := zookeeperMetadata{}
+
+	hosts, err := GetFromAuthOrMeta(config, "hosts")
+	if err != nil {
+		return nil, err
+	}
+	for _, host := range strings.Split(hosts, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			meta.hosts = append(meta.hosts, host)
+		}
+	}
+	if len(meta.hosts) == 0 {
+		return nil, errors.New("no hosts given")
+	}
+
+	if val, ok := config.TriggerMetadata["path"]; ok && val != "" {
+		if !strings.HasPrefix(val, "/") {
+			return nil, fmt.Errorf("path must be absolute, got %s", val)
+		}
+		meta.path = val
+	} else {
+		return nil, errors.New("no path given")
+	}
+
+	if val, ok := config.TriggerMetadata["targetValue"]; ok {
+		targetValue, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("targetValue parsing error %s", err.Error())
+		}
+		meta.targetValue = targetValue
+	} else {
+		return nil, errors.New("no targetValue given")
+	}
+
+	meta.sessionTimeout = defaultZookeeperSessionTimeout
+	if val, ok := config.TriggerMetadata["sessionTimeout"]; ok {
+		sessionTimeout, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("sessionTimeout parsing error %s", err.Error())
+		}
+		if sessionTimeout <= 0 {
+			return nil, errors.New("sessionTimeout must be greater than 0")
+		}
+		meta.sessionTimeout = sessionTimeout
+	}
+
+	meta.username = config.AuthParams["username"]
+	meta.password = config.AuthParams["password"]
+	if meta.username != "" && meta.password == "" {
+		return nil, errors.New("no password given")
+	}
+
+	if val, ok := config.AuthParams["tls"]; ok {
+		val = strings.TrimSpace(val)
+
+		if val == "enable" {
+			certGiven := config.AuthParams["cert"] != ""
+			keyGiven := config.AuthParams["key"] != ""
+			if certGiven && !keyGiven {
+				return nil, errors.New("key must be provided with cert")
+			}
+			if keyGiven && !certGiven {
+				return nil, errors.New("cert must be provided with key")
+			}
+			meta.ca = config.AuthParams["ca"]
+			meta.cert = config.AuthParams["cert"]
+			meta.key = config.AuthParams["key"]
+			meta.enableTLS = true
+		} else if val != "disable" {
+			return nil, fmt.Errorf("err incorrect value for TLS given: %s", val)
+		}
+	}
+
+	meta.metricName = kedautil.NormalizeString(fmt.Sprintf("zookeeper-%s", meta.path))
+	meta.scalerIndex = config.ScalerIndex
+
+	return &meta, nil
+}
+
+func getZookeeperConnection(meta *zookeeperMetadata) (*zk.Conn, error) {
+	dialer := net.DialTimeout
+	if meta.enableTLS {
+		tlsConfig, err := kedautil.NewTLSConfig(meta.cert, meta.key, meta.ca)
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		dialer = func(network, address string, timeout time.Duration) (net.Conn, error) {
+			return tls.DialWithDialer(&net.Dialer{Timeout: timeout}, network, address, tlsConfig)
+		}
+	}
+
+	conn, _, err := zk.Connect(meta.hosts, time.Duration(meta.sessionTimeout)*time.Second,
+		zk.WithDialer(dialer), zk.WithLogger(zookeeperLogger{}))
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to zookeeper: %s", err)
+	}
+
+	if meta.username != "" {
+		if err := conn.AddAuth("digest", []byte(fmt.Sprintf("%s:%s", meta.username, meta.password))); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error authenticating to zookeeper: %s", err)
+		}
+	}
+
+	return conn, nil
+}
+
+// Close closes the zookeeper session
+func (s *zookeeperScaler) Close(context.Context) error {
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	return nil
+}
+
+// IsActive returns true if the znode has children
+func (s *zookeeperScaler) IsActive(ctx context.Context) (bool, error) {
+	count, err := s.getChildrenCount()
+	if err != nil {
+		zookeeperLog.Error(err, "error inspecting zookeeper")
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// getChildrenCount returns the number of children of the znode, read from its stat
+// so that the children don't have to be listed
+func (s *zookeeperScaler) getChildrenCount() (float64, error) {
+	exists, stat, err := s.conn.Exists(s.metadata.path)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, fmt.Errorf("znode %s doesn't exist", s.metadata.path)
+	}
+	return float64(stat.NumChildren), nil
+}
+
+// GetMetricSpecForScaling returns the MetricSpec for the Horizontal Pod Autoscaler
+func (s *zookeeperScaler) GetMetricSpecForScaling(context.Context) []v2beta2.MetricSpec {
+	externalMetric := &v2beta2.ExternalMetricSource{
+		Metric: v2beta2.MetricIdentifier{
+			Name: GenerateMetricNameWithIndex(s.metadata.scalerIndex, s.metadata.metricName),
+		},
+		Target: GetMetricTargetMili(s.metricType, s.metadata.targetValue),
+	}
+	metricSpec := v2beta2.MetricSpec{
+		External: externalMetric, Type: externalMetricType,
+	}
+	return []v2beta2.MetricSpec{metricSpec}
+}
+
+// GetMetrics returns value for a supported metric and an error if there is a problem getting the metric
+func (s *zookeeperScaler) GetMetrics(ctx context.Context, metricName string, metricSelector labels.Selector) ([]external_metrics.ExternalMetricValue, error) {
+	count, err := s.getChildrenCount()
+	if err != nil {
+		return []external_metrics.ExternalMetricValue{}, fmt.Errorf("error inspecting zookeeper: %s", err)
+	}
+
+	metric := GenerateMetricInMili(metricName, count)
+
+	return append([]external_metrics.ExternalMetricValue{}, metric), nil
+}
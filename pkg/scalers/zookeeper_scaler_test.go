@@ -0,0 +1,82 @@
+package scalers
+
+import (
+	"context"
+	"testing"
+)
+
+type parseZookeeperMetadataTestData struct {
+	metadata    map[string]string
+	authParams  map[string]string
+	raisesError bool
+}
+
+type zookeeperMetricIdentifier struct {
+	metadataTestData *parseZookeeperMetadataTestData
+	scalerIndex      int
+	name             string
+}
+
+var testZookeeperMetadata = []parseZookeeperMetadataTestData{
+	// nothing passed
+	{map[string]string{}, map[string]string{}, true},
+	// properly formed
+	{map[string]string{"hosts": "zookeeper:2181", "path": "/queues/jobs", "targetValue": "10"}, map[string]string{}, false},
+	// several hosts and sessionTimeout
+	{map[string]string{"hosts": "zk-0:2181, zk-1:2181", "path": "/queue", "targetValue": "5", "sessionTimeout": "30"}, map[string]string{}, false},
+	// hosts from authParams
+	{map[string]string{"path": "/queue", "targetValue": "5"}, map[string]string{"hosts": "zookeeper:2181"}, false},
+	// no hosts
+	{map[string]string{"path": "/queue", "targetValue": "5"}, map[string]string{}, true},
+	// no path
+	{map[string]string{"hosts": "zookeeper:2181", "targetValue": "5"}, map[string]string{}, true},
+	// relative path
+	{map[string]string{"hosts": "zookeeper:2181", "path": "queue", "targetValue": "5"}, map[string]string{}, true},
+	// invalid targetValue
+	{map[string]string{"hosts": "zookeeper:2181", "path": "/queue", "targetValue": "a"}, map[string]string{}, true},
+	// invalid sessionTimeout
+	{map[string]string{"hosts": "zookeeper:2181", "path": "/queue", "targetValue": "5", "sessionTimeout": "0"}, map[string]string{}, true},
+	// digest auth
+	{map[string]string{"hosts": "zookeeper:2181", "path": "/queue", "targetValue": "5"}, map[string]string{"username": "keda", "password": "secret"}, false},
+	// username without password
+	{map[string]string{"hosts": "zookeeper:2181", "path": "/queue", "targetValue": "5"}, map[string]string{"username": "keda"}, true},
+	// tls
+	{map[string]string{"hosts": "zookeeper:2281", "path": "/queue", "targetValue": "5"}, map[string]string{"tls": "enable", "ca": "caaa", "cert": "ceert", "key": "keey"}, false},
+	// key without cert
+	{map[string]string{"hosts": "zookeeper:2281", "path": "/queue", "targetValue": "5"}, map[string]string{"tls": "enable", "key": "keey"}, true},
+	// invalid tls value
+	{map[string]string{"hosts": "zookeeper:2281", "path": "/queue", "targetValue": "5"}, map[string]string{"tls": "yes"}, true},
+}
+
+var zookeeperMetricIdentifiers = []zookeeperMetricIdentifier{
+	{&testZookeeperMetadata[1], 0, "s0-zookeeper--queues-jobs"},
+	{&testZookeeperMetadata[2], 1, "s1-zookeeper--queue"},
+}
+
+func TestParseZookeeperMetadata(t *testing.T) {
+	for idx, testData := range testZookeeperMetadata {
+		_, err := parseZookeeperMetadata(&ScalerConfig{TriggerMetadata: testData.metadata, AuthParams: testData.authParams})
+		if err != nil && !testData.raisesError {
+			t.Errorf("Expected success but got error for test %d: %s", idx, err)
+		}
+		if err == nil && testData.raisesError {
+			t.Errorf("Expected error but got success for test %d", idx)
+		}
+	}
+}
+
+func TestZookeeperGetMetricSpecForScaling(t *testing.T) {
+	for _, testData := range zookeeperMetricIdentifiers {
+		meta, err := parseZookeeperMetadata(&ScalerConfig{TriggerMetadata: testData.metadataTestData.metadata, AuthParams: testData.metadataTestData.authParams, ScalerIndex: testData.scalerIndex})
+		if err != nil {
+			t.Fatal("Could not parse metadata:", err)
+		}
+		mockZookeeperScaler := zookeeperScaler{"", meta, nil}
+
+		metricSpec := mockZookeeperScaler.GetMetricSpecForScaling(context.Background())
+		metricName := metricSpec[0].External.Metric.Name
+		if metricName != testData.name {
+			t.Error("Wrong External metric source name:", metricName)
+		}
+	}
+}
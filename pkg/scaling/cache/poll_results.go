@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/metrics/pkg/apis/external_metrics"
+)
+
+// recordPollMetrics makes the scale loops of the ScaledObjects read the metric values of their triggers on every poll,
+// so the query API serves them without querying the scalers itself
+var recordPollMetrics int32
+
+// SetRecordPollMetrics enables or disables reading the metric values of the triggers in the scale loops of the
+// ScaledObjects, the activity of the triggers is always recorded
+func SetRecordPollMetrics(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&recordPollMetrics, v)
+}
+
+// IsRecordingPollMetrics returns whether the scale loops read the metric values of the triggers
+func IsRecordingPollMetrics() bool {
+	return atomic.LoadInt32(&recordPollMetrics) == 1
+}
+
+// PollResult is the result of the last poll of a scaler by the scale loop
+type PollResult struct {
+	PolledAt time.Time
+	IsActive bool
+	// Error is the error of IsActive, or of GetMetrics if the metric values are recorded
+	Error error
+	// Metrics are the metric values by metric name exposed to the HPA, only recorded with SetRecordPollMetrics
+	Metrics map[string][]external_metrics.ExternalMetricValue
+}
+
+// GetPollResult returns the result of the last poll of the scaler with id, false if it wasn't polled yet
+func (c *ScalersCache) GetPollResult(id int) (PollResult, bool) {
+	c.pollResultsLock.Lock()
+	defer c.pollResultsLock.Unlock()
+
+	result, ok := c.pollResults[id]
+	return result, ok
+}
+
+// recordPollResult records the activity of the scaler with id polled by the scale loop, and its metric values if enabled
+func (c *ScalersCache) recordPollResult(ctx context.Context, id int, scaledObjectName string, isActive bool, err error) {
+	result := PollResult{PolledAt: time.Now(), IsActive: isActive, Error: err}
+	if err == nil && IsRecordingPollMetrics() {
+		result.Metrics, result.Error = c.readPollMetrics(ctx, id, scaledObjectName)
+	}
+
+	c.pollResultsLock.Lock()
+	defer c.pollResultsLock.Unlock()
+
+	if c.pollResults == nil {
+		c.pollResults = map[int]PollResult{}
+	}
+	c.pollResults[id] = result
+}
+
+func (c *ScalersCache) readPollMetrics(ctx context.Context, id int, scaledObjectName string) (map[string][]external_metrics.ExternalMetricValue, error) {
+	metricSpecs, err := c.GetMetricSpecForScalingForScaler(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{"scaledobject.keda.sh/name": scaledObjectName})
+	metrics := map[string][]external_metrics.ExternalMetricValue{}
+	for _, metricSpec := range metricSpecs {
+		// cpu/memory resource metrics are read by the HPA from the metrics server
+		if metricSpec.External == nil {
+			continue
+		}
+		values, err := c.GetMetricsForScaler(ctx, id, metricSpec.External.Metric.Name, selector)
+		if err != nil {
+			return metrics, err
+		}
+		metrics[metricSpec.External.Metric.Name] = values
+	}
+	return metrics, nil
+}
@@ -20,6 +20,9 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"k8s.io/api/autoscaling/v2beta2"
@@ -39,11 +42,81 @@ type ScalersCache struct {
 	Scalers    []ScalerBuilder
 	Logger     logr.Logger
 	Recorder   record.EventRecorder
+
+	// metric values of the scalers using cached metrics, by metric name exposed to the HPA
+	cachedMetricsLock sync.Mutex
+	cachedMetrics     map[string]cachedMetricValues
+
+	// results of the last poll of the scalers by the scale loop, by scaler id
+	pollResultsLock sync.Mutex
+	pollResults     map[int]PollResult
+}
+
+type cachedMetricValues struct {
+	values []external_metrics.ExternalMetricValue
+	readAt time.Time
 }
 
 type ScalerBuilder struct {
 	Scaler  scalers.Scaler
 	Factory func() (scalers.Scaler, error)
+	// TriggerName identifies the trigger in the events and the Prometheus metrics, set in the spec or generated
+	TriggerName string
+	// MetricNamePrefix replaces the "s<ScalerIndex>" prefix of the metric names generated by the scaler, it's
+	// the name set in the spec so unnamed triggers keep their metric names; they are left untouched if it's empty
+	MetricNamePrefix string
+	// MetricNameOverride replaces the whole name of the metric generated by the scaler, it takes
+	// precedence over MetricNamePrefix
+	MetricNameOverride string
+	ScalerIndex        int
+	// MetricsCacheTTL is how long the metric values read from the scaler are served to the HPA
+	// before reading them again, they are read on every request if it's 0
+	MetricsCacheTTL time.Duration
+}
+
+// externalMetricName returns the name of a metric of the scaler as exposed to the HPA
+func (b ScalerBuilder) externalMetricName(metricName string) string {
+	if b.MetricNameOverride != "" {
+		return b.MetricNameOverride
+	}
+	if b.MetricNamePrefix == "" {
+		return metricName
+	}
+	return fmt.Sprintf("%s-%s", b.MetricNamePrefix, strings.TrimPrefix(metricName, fmt.Sprintf("s%d-", b.ScalerIndex)))
+}
+
+// scalerMetricName returns the name the scaler uses for a metric exposed to the HPA
+func (b ScalerBuilder) scalerMetricName(ctx context.Context, metricName string) string {
+	if b.MetricNamePrefix == "" && b.MetricNameOverride == "" {
+		return metricName
+	}
+	for _, spec := range b.Scaler.GetMetricSpecForScaling(ctx) {
+		if spec.External != nil && strings.EqualFold(b.externalMetricName(spec.External.Metric.Name), metricName) {
+			return spec.External.Metric.Name
+		}
+	}
+	return metricName
+}
+
+// eventMessage prefixes the error with the trigger name, if there is one
+func (b ScalerBuilder) eventMessage(err error) string {
+	if b.TriggerName == "" {
+		return err.Error()
+	}
+	return fmt.Sprintf("trigger %s: %s", b.TriggerName, err)
+}
+
+// externalMetricSpecs renames the external metrics of specs to the names exposed to the HPA
+func (b ScalerBuilder) externalMetricSpecs(specs []v2beta2.MetricSpec) []v2beta2.MetricSpec {
+	result := make([]v2beta2.MetricSpec, 0, len(specs))
+	for _, spec := range specs {
+		if spec.External != nil {
+			spec.External = spec.External.DeepCopy()
+			spec.External.Metric.Name = b.externalMetricName(spec.External.Metric.Name)
+		}
+		result = append(result, spec)
+	}
+	return result
 }
 
 func (c *ScalersCache) GetScalers() []scalers.Scaler {
@@ -68,17 +141,68 @@ func (c *ScalersCache) GetMetricsForScaler(ctx context.Context, id int, metricNa
 	if id < 0 || id >= len(c.Scalers) {
 		return nil, fmt.Errorf("scaler with id %d not found. Len = %d", id, len(c.Scalers))
 	}
-	m, err := c.Scalers[id].Scaler.GetMetrics(ctx, metricName, metricSelector)
-	if err == nil {
-		return m, nil
+	sb := c.Scalers[id]
+	if sb.MetricsCacheTTL > 0 {
+		if m, ok := c.getCachedMetrics(metricName, sb.MetricsCacheTTL); ok {
+			return m, nil
+		}
 	}
 
-	ns, err := c.refreshScaler(ctx, id)
+	scalerMetricName := sb.scalerMetricName(ctx, metricName)
+	m, err := sb.Scaler.GetMetrics(ctx, scalerMetricName, metricSelector)
 	if err != nil {
-		return nil, err
+		var ns scalers.Scaler
+		ns, err = c.refreshScaler(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		m, err = ns.GetMetrics(ctx, scalerMetricName, metricSelector)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range m {
+		m[i].MetricName = sb.externalMetricName(m[i].MetricName)
+	}
+	if sb.MetricsCacheTTL > 0 {
+		c.setCachedMetrics(metricName, m)
+	}
+	return m, nil
+}
+
+// getCachedMetrics returns a copy of the metric values cached less than ttl ago
+func (c *ScalersCache) getCachedMetrics(metricName string, ttl time.Duration) ([]external_metrics.ExternalMetricValue, bool) {
+	c.cachedMetricsLock.Lock()
+	defer c.cachedMetricsLock.Unlock()
+
+	cached, ok := c.cachedMetrics[metricName]
+	if !ok || time.Since(cached.readAt) >= ttl {
+		return nil, false
 	}
+	return append([]external_metrics.ExternalMetricValue{}, cached.values...), true
+}
+
+func (c *ScalersCache) setCachedMetrics(metricName string, values []external_metrics.ExternalMetricValue) {
+	c.cachedMetricsLock.Lock()
+	defer c.cachedMetricsLock.Unlock()
 
-	return ns.GetMetrics(ctx, metricName, metricSelector)
+	if c.cachedMetrics == nil {
+		c.cachedMetrics = map[string]cachedMetricValues{}
+	}
+	c.cachedMetrics[metricName] = cachedMetricValues{
+		values: append([]external_metrics.ExternalMetricValue{}, values...),
+		readAt: time.Now(),
+	}
+}
+
+// GetMetricSpecForScalingForScaler returns the MetricSpecs of a scaler, with the metric names exposed to the HPA
+func (c *ScalersCache) GetMetricSpecForScalingForScaler(ctx context.Context, id int) ([]v2beta2.MetricSpec, error) {
+	if id < 0 || id >= len(c.Scalers) {
+		return nil, fmt.Errorf("scaler with id %d not found. Len = %d", id, len(c.Scalers))
+	}
+	sb := c.Scalers[id]
+	return sb.externalMetricSpecs(sb.Scaler.GetMetricSpecForScaling(ctx)), nil
 }
 
 func (c *ScalersCache) IsScaledObjectActive(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject) (bool, bool, []external_metrics.ExternalMetricValue) {
@@ -94,6 +218,7 @@ func (c *ScalersCache) IsScaledObjectActive(ctx context.Context, scaledObject *k
 				isTriggerActive, err = ns.IsActive(ctx)
 			}
 		}
+		c.recordPollResult(ctx, i, scaledObject.Name, isTriggerActive, err)
 
 		logger := c.Logger.WithValues("scaledobject.Name", scaledObject.Name, "scaledObject.Namespace", scaledObject.Namespace,
 			"scaleTarget.Name", scaledObject.Spec.ScaleTargetRef.Name)
@@ -101,11 +226,11 @@ func (c *ScalersCache) IsScaledObjectActive(ctx context.Context, scaledObject *k
 		if err != nil {
 			isError = true
 			logger.Error(err, "Error getting scale decision")
-			c.Recorder.Event(scaledObject, corev1.EventTypeWarning, eventreason.KEDAScalerFailed, err.Error())
+			c.Recorder.Event(scaledObject, corev1.EventTypeWarning, eventreason.KEDAScalerFailed, s.eventMessage(err))
 		} else if isTriggerActive {
 			isActive = true
 			if externalMetricsSpec := s.Scaler.GetMetricSpecForScaling(ctx)[0].External; externalMetricsSpec != nil {
-				logger.V(1).Info("Scaler for scaledObject is active", "Metrics Name", externalMetricsSpec.Metric.Name)
+				logger.V(1).Info("Scaler for scaledObject is active", "Metrics Name", s.externalMetricName(externalMetricsSpec.Metric.Name))
 			}
 			if resourceMetricsSpec := s.Scaler.GetMetricSpecForScaling(ctx)[0].Resource; resourceMetricsSpec != nil {
 				logger.V(1).Info("Scaler for scaledObject is active", "Metrics Name", resourceMetricsSpec.Name)
@@ -203,8 +328,13 @@ func (c *ScalersCache) refreshScaler(ctx context.Context, id int) (scalers.Scale
 	}
 
 	c.Scalers[id] = ScalerBuilder{
-		Scaler:  ns,
-		Factory: sb.Factory,
+		Scaler:             ns,
+		Factory:            sb.Factory,
+		TriggerName:        sb.TriggerName,
+		MetricNamePrefix:   sb.MetricNamePrefix,
+		MetricNameOverride: sb.MetricNameOverride,
+		ScalerIndex:        sb.ScalerIndex,
+		MetricsCacheTTL:    sb.MetricsCacheTTL,
 	}
 	sb.Scaler.Close(ctx)
 
@@ -214,7 +344,7 @@ func (c *ScalersCache) refreshScaler(ctx context.Context, id int) (scalers.Scale
 func (c *ScalersCache) GetMetricSpecForScaling(ctx context.Context) []v2beta2.MetricSpec {
 	var spec []v2beta2.MetricSpec
 	for _, s := range c.Scalers {
-		spec = append(spec, s.Scaler.GetMetricSpecForScaling(ctx)...)
+		spec = append(spec, s.externalMetricSpecs(s.Scaler.GetMetricSpecForScaling(ctx))...)
 	}
 	return spec
 }
@@ -245,7 +375,7 @@ func (c *ScalersCache) getScaledJobMetrics(ctx context.Context, scaledJob *kedav
 		maxValue := float64(0)
 		scalerType := fmt.Sprintf("%T:", s)
 
-		scalerLogger := c.Logger.WithValues("ScaledJob", scaledJob.Name, "Scaler", scalerType)
+		scalerLogger := c.Logger.WithValues("ScaledJob", scaledJob.Name, "Scaler", scalerType, "Trigger", s.TriggerName)
 
 		metricSpecs := s.Scaler.GetMetricSpecForScaling(ctx)
 
@@ -266,7 +396,7 @@ func (c *ScalersCache) getScaledJobMetrics(ctx context.Context, scaledJob *kedav
 
 		if err != nil {
 			scalerLogger.V(1).Info("Error getting scaler.IsActive, but continue", "Error", err)
-			c.Recorder.Event(scaledJob, corev1.EventTypeWarning, eventreason.KEDAScalerFailed, err.Error())
+			c.Recorder.Event(scaledJob, corev1.EventTypeWarning, eventreason.KEDAScalerFailed, s.eventMessage(err))
 			continue
 		}
 
@@ -275,7 +405,7 @@ func (c *ScalersCache) getScaledJobMetrics(ctx context.Context, scaledJob *kedav
 		metrics, err := s.Scaler.GetMetrics(ctx, metricSpecs[0].External.Metric.Name, nil)
 		if err != nil {
 			scalerLogger.V(1).Info("Error getting scaler metrics, but continue", "Error", err)
-			c.Recorder.Event(scaledJob, corev1.EventTypeWarning, eventreason.KEDAScalerFailed, err.Error())
+			c.Recorder.Event(scaledJob, corev1.EventTypeWarning, eventreason.KEDAScalerFailed, s.eventMessage(err))
 			continue
 		}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/golang/mock/gomock"
@@ -254,3 +255,94 @@ func createScaler(ctrl *gomock.Controller, queueLength int64, averageValue int64
 	scaler.EXPECT().Close(gomock.Any())
 	return scaler
 }
+
+func TestMetricNamePrefixReplacesIndexInMetricNames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	scaler := mock_scalers.NewMockScaler(ctrl)
+	scaler.EXPECT().GetMetricSpecForScaling(gomock.Any()).Return([]v2beta2.MetricSpec{createMetricSpec(10, "s1-queueLength")}).AnyTimes()
+	scaler.EXPECT().GetMetrics(gomock.Any(), "s1-queueLength", nil).Return([]external_metrics.ExternalMetricValue{{MetricName: "s1-queueLength"}}, nil)
+
+	cache := ScalersCache{
+		Scalers: []ScalerBuilder{
+			{},
+			{Scaler: scaler, TriggerName: "orders", MetricNamePrefix: "orders", ScalerIndex: 1},
+		},
+		Logger:   logr.Discard(),
+		Recorder: record.NewFakeRecorder(1),
+	}
+
+	specs, err := cache.GetMetricSpecForScalingForScaler(context.TODO(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "orders-queueLength", specs[0].External.Metric.Name)
+
+	metrics, err := cache.GetMetricsForScaler(context.TODO(), 1, "orders-queueLength", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "orders-queueLength", metrics[0].MetricName)
+}
+
+func TestUnnamedTriggerKeepsIndexInMetricNames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	scaler := mock_scalers.NewMockScaler(ctrl)
+	scaler.EXPECT().GetMetricSpecForScaling(gomock.Any()).Return([]v2beta2.MetricSpec{createMetricSpec(10, "s0-queueLength")}).AnyTimes()
+
+	cache := ScalersCache{
+		Scalers: []ScalerBuilder{
+			{Scaler: scaler, TriggerName: "rabbitmq-0a1b2c3d"},
+		},
+		Logger:   logr.Discard(),
+		Recorder: record.NewFakeRecorder(1),
+	}
+
+	specs, err := cache.GetMetricSpecForScalingForScaler(context.TODO(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "s0-queueLength", specs[0].External.Metric.Name)
+}
+
+func TestMetricNameOverrideReplacesMetricName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	scaler := mock_scalers.NewMockScaler(ctrl)
+	scaler.EXPECT().GetMetricSpecForScaling(gomock.Any()).Return([]v2beta2.MetricSpec{createMetricSpec(10, "s0-aws-sqs-orders")}).AnyTimes()
+	scaler.EXPECT().GetMetrics(gomock.Any(), "s0-aws-sqs-orders", nil).Return([]external_metrics.ExternalMetricValue{{MetricName: "s0-aws-sqs-orders"}}, nil)
+
+	cache := ScalersCache{
+		Scalers: []ScalerBuilder{
+			{Scaler: scaler, TriggerName: "orders", MetricNamePrefix: "orders", MetricNameOverride: "sqs_orders_backlog"},
+		},
+		Logger:   logr.Discard(),
+		Recorder: record.NewFakeRecorder(1),
+	}
+
+	specs, err := cache.GetMetricSpecForScalingForScaler(context.TODO(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "sqs_orders_backlog", specs[0].External.Metric.Name)
+
+	metrics, err := cache.GetMetricsForScaler(context.TODO(), 0, "sqs_orders_backlog", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "sqs_orders_backlog", metrics[0].MetricName)
+}
+
+func TestCachedMetricsAreServedUntilTheyExpire(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	scaler := mock_scalers.NewMockScaler(ctrl)
+	// read once for the two first requests, then again once the cached values expired
+	scaler.EXPECT().GetMetrics(gomock.Any(), "s0-queueLength", nil).Return([]external_metrics.ExternalMetricValue{{MetricName: "s0-queueLength", Value: *resource.NewQuantity(5, resource.DecimalSI)}}, nil).Times(2)
+
+	cache := ScalersCache{
+		Scalers: []ScalerBuilder{
+			{Scaler: scaler, MetricsCacheTTL: time.Minute},
+		},
+		Logger:   logr.Discard(),
+		Recorder: record.NewFakeRecorder(1),
+	}
+
+	for i := 0; i < 2; i++ {
+		metrics, err := cache.GetMetricsForScaler(context.TODO(), 0, "s0-queueLength", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), metrics[0].Value.Value())
+	}
+
+	cache.cachedMetrics["s0-queueLength"] = cachedMetricValues{readAt: time.Now().Add(-2 * time.Minute)}
+	metrics, err := cache.GetMetricsForScaler(context.TODO(), 0, "s0-queueLength", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), metrics[0].Value.Value())
+}
@@ -19,6 +19,8 @@ package executor
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,6 +31,7 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
 const (
@@ -48,6 +51,10 @@ type scaleExecutor struct {
 	reconcilerScheme *runtime.Scheme
 	logger           logr.Logger
 	recorder         record.EventRecorder
+
+	// activation time of the ScaledObjects scaled from zero, by namespace/name, until their scale target has a ready replica
+	scaleFromZeroTracking     sync.Map
+	readyReplicasPollInterval time.Duration
 }
 
 // NewScaleExecutor creates a ScaleExecutor object
@@ -58,6 +65,8 @@ func NewScaleExecutor(client runtimeclient.Client, scaleClient scale.ScalesGette
 		reconcilerScheme: reconcilerScheme,
 		logger:           logf.Log.WithName("scaleexecutor"),
 		recorder:         recorder,
+
+		readyReplicasPollInterval: defaultReadyReplicasPollInterval,
 	}
 }
 
@@ -79,7 +88,9 @@ func (e *scaleExecutor) updateLastActiveTime(ctx context.Context, logger logr.Lo
 		return err
 	}
 
-	err := e.client.Status().Patch(ctx, runtimeObj, patch)
+	err := kedautil.RetryWrite(ctx, func() error {
+		return e.client.Status().Patch(ctx, runtimeObj, patch)
+	})
 	if err != nil {
 		logger.Error(err, "Failed to patch Objects Status")
 	}
@@ -103,7 +114,9 @@ func (e *scaleExecutor) setCondition(ctx context.Context, logger logr.Logger, ob
 		return err
 	}
 
-	err := e.client.Status().Patch(ctx, runtimeObj, patch)
+	err := kedautil.RetryWrite(ctx, func() error {
+		return e.client.Status().Patch(ctx, runtimeObj, patch)
+	})
 	if err != nil {
 		logger.Error(err, "Failed to patch Objects Status")
 	}
@@ -130,3 +143,19 @@ func (e *scaleExecutor) setFallbackCondition(ctx context.Context, logger logr.Lo
 	}
 	return e.setCondition(ctx, logger, object, status, reason, message, fallback)
 }
+
+// setScalingDisabledCondition reports on the Active condition that the scaling is disabled cluster-wide, the status
+// still telling whether the triggers are active
+func (e *scaleExecutor) setScalingDisabledCondition(ctx context.Context, logger logr.Logger, object interface{}, conditions kedav1alpha1.Conditions, isActive bool) {
+	status := metav1.ConditionFalse
+	if isActive {
+		status = metav1.ConditionTrue
+	}
+	condition := conditions.GetActiveCondition()
+	if condition.Status == status && condition.Reason == ScalingDisabledReason {
+		return
+	}
+	if err := e.setActiveCondition(ctx, logger, object, status, ScalingDisabledReason, ScalingDisabledMessage); err != nil {
+		logger.Error(err, "Error setting active condition when scaling is disabled")
+	}
+}
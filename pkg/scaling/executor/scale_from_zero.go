@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	prommetrics "github.com/kedacore/keda/v2/pkg/metrics"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const (
+	// defaultReadyReplicasPollInterval is how often the ready replicas of the scale target are checked after a scale from zero
+	defaultReadyReplicasPollInterval = time.Second
+	// scaleFromZeroTimeout is how long we wait for a ready replica before giving up measuring the scale from zero
+	scaleFromZeroTimeout = 15 * time.Minute
+)
+
+// trackScaleFromZero waits for the scale target of the ScaledObject to have a ready replica, then records the time
+// elapsed since activationTime in the scale from zero metric and in the ScaledObject status.
+// It returns immediately if the scale from zero of the ScaledObject is already tracked.
+func (e *scaleExecutor) trackScaleFromZero(ctx context.Context, logger logr.Logger, scaledObject *kedav1alpha1.ScaledObject, activationTime time.Time) {
+	key := fmt.Sprintf("%s/%s", scaledObject.Namespace, scaledObject.Name)
+	if _, tracked := e.scaleFromZeroTracking.LoadOrStore(key, activationTime); tracked {
+		return
+	}
+	defer e.scaleFromZeroTracking.Delete(key)
+
+	ctx, cancel := context.WithTimeout(ctx, scaleFromZeroTimeout)
+	defer cancel()
+	ticker := time.NewTicker(e.readyReplicasPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.V(1).Info("Stopped waiting for a ready replica of the scale target after the scale from zero")
+			return
+		case <-ticker.C:
+		}
+
+		readyReplicas, err := e.getReadyReplicasOnScaleTarget(ctx, scaledObject)
+		if err != nil {
+			logger.V(1).Info("Unable to read the ready replicas of the scale target", "error", err.Error())
+			continue
+		}
+		if readyReplicas < 1 {
+			continue
+		}
+
+		duration := time.Since(activationTime)
+		logger.V(1).Info("Scale target has a ready replica after the scale from zero", "duration", duration)
+		prommetrics.RecordScaleFromZeroDuration(scaledObject.Namespace, scaledObject.Name, duration)
+		if err := e.updateLastScaleFromZeroDuration(ctx, scaledObject, duration); err != nil {
+			logger.Error(err, "Error updating the last scale from zero duration")
+		}
+		return
+	}
+}
+
+// getReadyReplicasOnScaleTarget reads status.readyReplicas of the scale target
+func (e *scaleExecutor) getReadyReplicasOnScaleTarget(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject) (int32, error) {
+	key := client.ObjectKey{Namespace: scaledObject.Namespace, Name: scaledObject.Spec.ScaleTargetRef.Name}
+	targetGVKR := scaledObject.Status.ScaleTargetGVKR
+	switch {
+	case targetGVKR.Group == "apps" && targetGVKR.Kind == "Deployment":
+		deployment := &appsv1.Deployment{}
+		if err := e.client.Get(ctx, key, deployment); err != nil {
+			return -1, err
+		}
+		return deployment.Status.ReadyReplicas, nil
+	case targetGVKR.Group == "apps" && targetGVKR.Kind == "StatefulSet":
+		statefulSet := &appsv1.StatefulSet{}
+		if err := e.client.Get(ctx, key, statefulSet); err != nil {
+			return -1, err
+		}
+		return statefulSet.Status.ReadyReplicas, nil
+	}
+
+	unstruct := &unstructured.Unstructured{}
+	unstruct.SetGroupVersionKind(targetGVKR.GroupVersionKind())
+	if err := e.client.Get(ctx, key, unstruct); err != nil {
+		return -1, err
+	}
+	readyReplicas, found, err := unstructured.NestedInt64(unstruct.Object, "status", "readyReplicas")
+	if err != nil {
+		return -1, err
+	}
+	if !found {
+		return -1, fmt.Errorf("%s %s doesn't report status.readyReplicas", targetGVKR.Kind, key)
+	}
+	return int32(readyReplicas), nil
+}
+
+func (e *scaleExecutor) updateLastScaleFromZeroDuration(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject, duration time.Duration) error {
+	return kedautil.RetryWrite(ctx, func() error {
+		// the ScaledObject may have been updated while we were waiting for the scale target
+		latest := &kedav1alpha1.ScaledObject{}
+		if err := e.client.Get(ctx, client.ObjectKeyFromObject(scaledObject), latest); err != nil {
+			return err
+		}
+		patch := client.MergeFrom(latest.DeepCopy())
+		latest.Status.LastScaleFromZeroDuration = &metav1.Duration{Duration: duration}
+		return e.client.Status().Patch(ctx, latest, patch)
+	})
+}
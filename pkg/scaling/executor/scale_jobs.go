@@ -30,6 +30,8 @@ import (
 
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	"github.com/kedacore/keda/v2/pkg/eventreason"
+	"github.com/kedacore/keda/v2/pkg/scaling/resolver"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
 	version "github.com/kedacore/keda/v2/version"
 )
 
@@ -41,6 +43,13 @@ const (
 func (e *scaleExecutor) RequestJobScale(ctx context.Context, scaledJob *kedav1alpha1.ScaledJob, isActive bool, scaleTo int64, maxScale int64) {
 	logger := e.logger.WithValues("scaledJob.Name", scaledJob.Name, "scaledJob.Namespace", scaledJob.Namespace)
 
+	// The scaling is frozen cluster-wide, no Job is created whatever the triggers report
+	if IsScalingDisabled() {
+		logger.V(1).Info(ScalingDisabledMessage)
+		e.setScalingDisabledCondition(ctx, logger, scaledJob, scaledJob.Status.Conditions, isActive)
+		return
+	}
+
 	runningJobCount := e.getRunningJobCount(ctx, scaledJob)
 	pendingJobCount := e.getPendingJobCount(ctx, scaledJob)
 	logger.Info("Scaling Jobs", "Number of running Jobs", runningJobCount)
@@ -87,11 +96,16 @@ func (e *scaleExecutor) RequestJobScale(ctx context.Context, scaledJob *kedav1al
 }
 
 func (e *scaleExecutor) createJobs(ctx context.Context, logger logr.Logger, scaledJob *kedav1alpha1.ScaledJob, scaleTo int64, maxScale int64) {
-	scaledJob.Spec.JobTargetRef.Template.GenerateName = scaledJob.GetName() + "-"
-	if scaledJob.Spec.JobTargetRef.Template.Labels == nil {
-		scaledJob.Spec.JobTargetRef.Template.Labels = map[string]string{}
+	jobSpec, err := resolver.ResolveJobSpec(ctx, e.client, scaledJob)
+	if err != nil {
+		logger.Error(err, "Failed to resolve the Job template")
+		return
+	}
+	jobSpec.Template.GenerateName = scaledJob.GetName() + "-"
+	if jobSpec.Template.Labels == nil {
+		jobSpec.Template.Labels = map[string]string{}
 	}
-	scaledJob.Spec.JobTargetRef.Template.Labels["scaledjob.keda.sh/name"] = scaledJob.GetName()
+	jobSpec.Template.Labels["scaledjob.keda.sh/name"] = scaledJob.GetName()
 
 	logger.Info("Creating jobs", "Effective number of max jobs", maxScale)
 
@@ -118,7 +132,7 @@ func (e *scaleExecutor) createJobs(ctx context.Context, logger logr.Logger, scal
 				Namespace:    scaledJob.GetNamespace(),
 				Labels:       labels,
 			},
-			Spec: *scaledJob.Spec.JobTargetRef.DeepCopy(),
+			Spec: *jobSpec.DeepCopy(),
 		}
 
 		// Job doesn't allow RestartPolicyAlways, it seems like this value is set by the client as a default one,
@@ -134,7 +148,9 @@ func (e *scaleExecutor) createJobs(ctx context.Context, logger logr.Logger, scal
 			logger.Error(err, "Failed to set ScaledJob as the owner of the new Job")
 		}
 
-		err = e.client.Create(ctx, job)
+		err = kedautil.RetryWrite(ctx, func() error {
+			return e.client.Create(ctx, job)
+		})
 		if err != nil {
 			logger.Error(err, "Failed to create a new Job")
 		}
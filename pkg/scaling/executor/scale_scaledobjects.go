@@ -18,6 +18,7 @@ package executor
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"time"
 
@@ -25,12 +26,16 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	kedacontrollerutil "github.com/kedacore/keda/v2/controllers/keda/util"
 	"github.com/kedacore/keda/v2/pkg/eventreason"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
 )
 
 func (e *scaleExecutor) RequestScale(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject, isActive bool, isError bool) {
@@ -42,11 +47,12 @@ func (e *scaleExecutor) RequestScale(ctx context.Context, scaledObject *kedav1al
 	// to reduce API calls. Everything else uses the scale subresource.
 	var currentScale *autoscalingv1.Scale
 	var currentReplicas int32
+	var deployment *appsv1.Deployment
 	targetName := scaledObject.Spec.ScaleTargetRef.Name
 	targetGVKR := scaledObject.Status.ScaleTargetGVKR
 	switch {
 	case targetGVKR.Group == "apps" && targetGVKR.Kind == "Deployment":
-		deployment := &appsv1.Deployment{}
+		deployment = &appsv1.Deployment{}
 		err := e.client.Get(ctx, client.ObjectKey{Name: targetName, Namespace: scaledObject.Namespace}, deployment)
 		if err != nil {
 			logger.Error(err, "Error getting information on the current Scale (ie. replicas count) on the scaleTarget")
@@ -61,6 +67,13 @@ func (e *scaleExecutor) RequestScale(ctx context.Context, scaledObject *kedav1al
 			return
 		}
 		currentReplicas = *statefulSet.Spec.Replicas
+	case scaledObject.GetScaleStrategy() == kedav1alpha1.ScaleStrategyReplicas:
+		var err error
+		currentReplicas, err = e.getReplicasOnScaleTarget(ctx, scaledObject)
+		if err != nil {
+			logger.Error(err, "Error getting information on the current Scale (ie. replicas count) on the scaleTarget")
+			return
+		}
 	default:
 		var err error
 		currentScale, err = e.getScaleTargetScale(ctx, scaledObject)
@@ -81,6 +94,13 @@ func (e *scaleExecutor) RequestScale(ctx context.Context, scaledObject *kedav1al
 		}
 	}
 
+	// The scaling is frozen cluster-wide, the target keeps its replicas whatever the triggers report
+	if IsScalingDisabled() {
+		logger.V(1).Info(ScalingDisabledMessage)
+		e.setScalingDisabledCondition(ctx, logger, scaledObject, scaledObject.Status.Conditions, isActive)
+		return
+	}
+
 	// Check if we are paused, and if we are then update the scale to the desired count.
 	pausedCount, err := GetPausedReplicaCount(scaledObject)
 	if err != nil {
@@ -116,6 +136,22 @@ func (e *scaleExecutor) RequestScale(ctx context.Context, scaledObject *kedav1al
 		return
 	}
 
+	// Check if the Deployment is paused or rolling out, and if we should wait for the rollout before changing the replicas
+	if msg := getDeferredScalingMessage(scaledObject, deployment); msg != "" {
+		logger.V(1).Info(msg)
+		activeCondition := scaledObject.Status.Conditions.GetActiveCondition()
+		if activeCondition.Reason != "ScalingDeferred" || activeCondition.Message != msg {
+			status := metav1.ConditionFalse
+			if isActive {
+				status = metav1.ConditionTrue
+			}
+			if err := e.setActiveCondition(ctx, logger, scaledObject, status, "ScalingDeferred", msg); err != nil {
+				logger.Error(err, "Error setting active condition when scaling is deferred")
+			}
+		}
+		return
+	}
+
 	// if scaledObject.Spec.MinReplicaCount is not set, then set the default value (0)
 	minReplicas := int32(0)
 	if scaledObject.Spec.MinReplicaCount != nil {
@@ -304,6 +340,7 @@ func (e *scaleExecutor) scaleFromZeroOrIdle(ctx context.Context, logger logr.Log
 		replicas = 1
 	}
 
+	activationTime := time.Now()
 	currentReplicas, err := e.updateScaleOnScaleTarget(ctx, scaledObject, scale, replicas)
 
 	if err == nil {
@@ -311,6 +348,9 @@ func (e *scaleExecutor) scaleFromZeroOrIdle(ctx context.Context, logger logr.Log
 			"Original Replicas Count", currentReplicas,
 			"New Replicas Count", replicas)
 		e.recorder.Eventf(scaledObject, corev1.EventTypeNormal, eventreason.KEDAScaleTargetActivated, "Scaled %s %s/%s from %d to %d", scaledObject.Status.ScaleTargetKind, scaledObject.Namespace, scaledObject.Spec.ScaleTargetRef.Name, currentReplicas, replicas)
+		if currentReplicas == 0 {
+			go e.trackScaleFromZero(ctx, logger, scaledObject.DeepCopy(), activationTime)
+		}
 
 		// Scale was successful. Update lastScaleTime and lastActiveTime on the scaledObject
 		if err := e.updateLastActiveTime(ctx, logger, scaledObject); err != nil {
@@ -327,23 +367,132 @@ func (e *scaleExecutor) getScaleTargetScale(ctx context.Context, scaledObject *k
 }
 
 func (e *scaleExecutor) updateScaleOnScaleTarget(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject, scale *autoscalingv1.Scale, replicas int32) (int32, error) {
-	if scale == nil {
-		// Wasn't retrieved earlier, grab it now.
-		var err error
-		scale, err = e.getScaleTargetScale(ctx, scaledObject)
-		if err != nil {
-			return -1, err
+	if scaledObject.GetScaleStrategy() == kedav1alpha1.ScaleStrategyReplicas {
+		return e.patchReplicasOnScaleTarget(ctx, scaledObject, replicas)
+	}
+
+	var currentReplicas int32
+	err := kedautil.RetryWrite(ctx, func() error {
+		if scale == nil {
+			// Wasn't retrieved earlier or changed since, grab it now.
+			var err error
+			scale, err = e.getScaleTargetScale(ctx, scaledObject)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Update with requested repliacs.
+		currentReplicas = scale.Spec.Replicas
+		scale.Spec.Replicas = replicas
+
+		_, err := e.scaleClient.Scales(scaledObject.Namespace).Update(ctx, scaledObject.Status.ScaleTargetGVKR.GroupResource(), scale, metav1.UpdateOptions{})
+		if apierrors.IsConflict(err) {
+			scale = nil
 		}
+		return err
+	})
+	if err != nil && scale == nil {
+		return -1, err
 	}
+	if err == nil && scaledObject.GetScaleStrategy() == kedav1alpha1.ScaleStrategyAuto {
+		e.checkReplicasFollowScale(ctx, scaledObject, replicas)
+	}
+	return currentReplicas, err
+}
 
-	// Update with requested repliacs.
-	currentReplicas := scale.Spec.Replicas
-	scale.Spec.Replicas = replicas
+// getReplicasOnScaleTarget reads spec.replicas of the scale target
+func (e *scaleExecutor) getReplicasOnScaleTarget(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject) (int32, error) {
+	unstruct := &unstructured.Unstructured{}
+	unstruct.SetGroupVersionKind(scaledObject.Status.ScaleTargetGVKR.GroupVersionKind())
+	if err := e.client.Get(ctx, client.ObjectKey{Namespace: scaledObject.Namespace, Name: scaledObject.Spec.ScaleTargetRef.Name}, unstruct); err != nil {
+		return -1, err
+	}
 
-	_, err := e.scaleClient.Scales(scaledObject.Namespace).Update(ctx, scaledObject.Status.ScaleTargetGVKR.GroupResource(), scale, metav1.UpdateOptions{})
+	replicas, found, err := unstructured.NestedInt64(unstruct.Object, "spec", "replicas")
+	if err != nil {
+		return -1, err
+	}
+	if !found {
+		// spec.replicas is usually defaulted to 1 when omitted
+		return 1, nil
+	}
+	return int32(replicas), nil
+}
+
+// patchReplicasOnScaleTarget sets spec.replicas of the scale target, bypassing the /scale subresource
+func (e *scaleExecutor) patchReplicasOnScaleTarget(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject, replicas int32) (int32, error) {
+	currentReplicas, err := e.getReplicasOnScaleTarget(ctx, scaledObject)
+	if err != nil {
+		return -1, err
+	}
+
+	unstruct := &unstructured.Unstructured{}
+	unstruct.SetGroupVersionKind(scaledObject.Status.ScaleTargetGVKR.GroupVersionKind())
+	unstruct.SetNamespace(scaledObject.Namespace)
+	unstruct.SetName(scaledObject.Spec.ScaleTargetRef.Name)
+	patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas)))
+	err = kedautil.RetryWrite(ctx, func() error {
+		return e.client.Patch(ctx, unstruct, patch)
+	})
+	if apierrors.IsForbidden(err) {
+		return currentReplicas, fmt.Errorf("KEDA isn't allowed to patch the scale target, the replicas scaleStrategy requires a ClusterRole labeled %s: \"true\" granting patch on %s: %s",
+			ScaleTargetPatchRoleLabel, scaledObject.Status.ScaleTargetGVKR.GroupResource(), err)
+	}
 	return currentReplicas, err
 }
 
+const (
+	// ReplicasMatchReason is the reason of the ReplicasMismatch condition once spec.replicas follows the /scale subresource again
+	ReplicasMatchReason = "ScaleTargetReplicasMatch"
+	// ReplicasMatchMessage is the message of the ReplicasMismatch condition once spec.replicas follows the /scale subresource again
+	ReplicasMatchMessage = "spec.replicas of the scale target follows its /scale subresource"
+
+	// ScaleTargetPatchRoleLabel labels the ClusterRoles aggregated to keda-operator-scale-target, granting KEDA to patch the scale targets
+	ScaleTargetPatchRoleLabel = "keda.sh/aggregate-to-keda-operator-scale-target"
+)
+
+// checkReplicasFollowScale warns if spec.replicas of the scale target doesn't match what was just set through the /scale subresource,
+// which happens with custom resources mapping /scale to another field. Deployments and StatefulSets are skipped, they are consistent.
+// The mismatch is reported with an event and the ReplicasMismatch condition of the ScaledObject, which is cleared once they match again.
+func (e *scaleExecutor) checkReplicasFollowScale(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject, replicas int32) {
+	if scaledObject.Status.ScaleTargetGVKR.Group == "apps" {
+		return
+	}
+
+	specReplicas, err := e.getReplicasOnScaleTarget(ctx, scaledObject)
+	if err != nil {
+		e.logger.V(1).Info("Unable to read spec.replicas of the scale target", "error", err.Error())
+		return
+	}
+
+	condition := scaledObject.Status.Conditions.GetReplicasMismatchCondition()
+	if specReplicas == replicas {
+		if condition.IsTrue() {
+			e.setReplicasMismatchCondition(ctx, scaledObject, metav1.ConditionFalse, ReplicasMatchReason, ReplicasMatchMessage)
+		}
+		return
+	}
+
+	msg := fmt.Sprintf("%s %s/%s has spec.replicas=%d after scaling it to %d through the /scale subresource, consider setting advanced.scaleStrategy",
+		scaledObject.Status.ScaleTargetKind, scaledObject.Namespace, scaledObject.Spec.ScaleTargetRef.Name, specReplicas, replicas)
+	e.recorder.Event(scaledObject, corev1.EventTypeWarning, eventreason.KEDAScaleTargetReplicasMismatch, msg)
+	if !condition.IsTrue() || condition.Message != msg {
+		e.setReplicasMismatchCondition(ctx, scaledObject, metav1.ConditionTrue, eventreason.KEDAScaleTargetReplicasMismatch, msg)
+	}
+}
+
+func (e *scaleExecutor) setReplicasMismatchCondition(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject, status metav1.ConditionStatus, reason string, message string) {
+	patch := client.MergeFrom(scaledObject.DeepCopy())
+	scaledObject.Status.Conditions.SetReplicasMismatchCondition(status, reason, message)
+	err := kedautil.RetryWrite(ctx, func() error {
+		return e.client.Status().Patch(ctx, scaledObject, patch)
+	})
+	if err != nil {
+		e.logger.Error(err, "Failed to patch ScaledObject Status")
+	}
+}
+
 // getIdleOrMinimumReplicaCount returns true if the second value returned is from IdleReplicaCount
 // it returns false if it is from MinReplicaCount followed by the actual value
 func getIdleOrMinimumReplicaCount(scaledObject *kedav1alpha1.ScaledObject) (bool, int32) {
@@ -358,6 +507,25 @@ func getIdleOrMinimumReplicaCount(scaledObject *kedav1alpha1.ScaledObject) (bool
 	return false, *scaledObject.Spec.MinReplicaCount
 }
 
+// getDeferredScalingMessage returns why the replica changes on the Deployment are deferred, or an empty string if they aren't.
+// Changing the replicas of a paused or rolling out Deployment makes the Deployment controller spread the change
+// proportionally across the old and new ReplicaSets, so with deferScalingDuringRollout we wait for the rollout to be over.
+func getDeferredScalingMessage(scaledObject *kedav1alpha1.ScaledObject, deployment *appsv1.Deployment) string {
+	if deployment == nil || scaledObject.Spec.Advanced == nil || !scaledObject.Spec.Advanced.DeferScalingDuringRollout {
+		return ""
+	}
+
+	switch {
+	case deployment.Spec.Paused:
+		return "Scaling is deferred because the Deployment rollout is paused"
+	case deployment.Generation > deployment.Status.ObservedGeneration,
+		deployment.Status.UpdatedReplicas < deployment.Status.Replicas:
+		return "Scaling is deferred because the Deployment is being rolled out"
+	default:
+		return ""
+	}
+}
+
 // GetPausedReplicaCount returns the paused replica count of the ScaledObject.
 // If not paused, it returns nil.
 func GetPausedReplicaCount(scaledObject *kedav1alpha1.ScaledObject) (*int32, error) {
@@ -18,16 +18,24 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/kedacore/keda/v2/pkg/eventreason"
 	"github.com/kedacore/keda/v2/pkg/mock/mock_client"
 	"github.com/kedacore/keda/v2/pkg/mock/mock_scale"
 )
@@ -268,7 +276,10 @@ func TestScaleFromMinReplicasWhenActive(t *testing.T) {
 	client.EXPECT().Status().Times(2).Return(statusWriter).Times(3)
 	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(3)
 
-	scaleExecutor.RequestScale(context.TODO(), &scaledObject, true, false)
+	// stops tracking the scale from zero
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scaleExecutor.RequestScale(ctx, &scaledObject, true, false)
 
 	assert.Equal(t, int32(1), scale.Spec.Replicas)
 	condition := scaledObject.Status.Conditions.GetActiveCondition()
@@ -392,7 +403,10 @@ func TestScaleFromIdleToMinReplicasWhenActive(t *testing.T) {
 	client.EXPECT().Status().Times(2).Return(statusWriter).Times(3)
 	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(3)
 
-	scaleExecutor.RequestScale(context.TODO(), &scaledObject, true, false)
+	// stops tracking the scale from zero
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scaleExecutor.RequestScale(ctx, &scaledObject, true, false)
 
 	assert.Equal(t, minReplicas, scale.Spec.Replicas)
 	condition := scaledObject.Status.Conditions.GetActiveCondition()
@@ -460,3 +474,392 @@ func TestScaleToPausedReplicasCount(t *testing.T) {
 	condition := scaledObject.Status.Conditions.GetActiveCondition()
 	assert.Equal(t, false, condition.IsTrue())
 }
+
+func TestDeferScalingDuringRollout(t *testing.T) {
+	tests := []struct {
+		name       string
+		deployment appsv1.Deployment
+		deferred   bool
+	}{
+		{
+			name:       "paused",
+			deployment: appsv1.Deployment{Spec: appsv1.DeploymentSpec{Paused: true}},
+			deferred:   true,
+		},
+		{
+			name: "new generation not observed yet",
+			deployment: appsv1.Deployment{
+				ObjectMeta: v1.ObjectMeta{Generation: 3},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 2},
+			},
+			deferred: true,
+		},
+		{
+			name: "old replicas still running",
+			deployment: appsv1.Deployment{
+				ObjectMeta: v1.ObjectMeta{Generation: 3},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 3, Replicas: 4, UpdatedReplicas: 2},
+			},
+			deferred: true,
+		},
+		{
+			name: "rollout complete",
+			deployment: appsv1.Deployment{
+				ObjectMeta: v1.ObjectMeta{Generation: 3},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 3, Replicas: 2, UpdatedReplicas: 2},
+			},
+			deferred: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scaledObject := &v1alpha1.ScaledObject{
+				Spec: v1alpha1.ScaledObjectSpec{
+					Advanced: &v1alpha1.AdvancedConfig{DeferScalingDuringRollout: true},
+				},
+			}
+			assert.Equal(t, test.deferred, getDeferredScalingMessage(scaledObject, &test.deployment) != "")
+
+			scaledObject.Spec.Advanced.DeferScalingDuringRollout = false
+			assert.Equal(t, "", getDeferredScalingMessage(scaledObject, &test.deployment))
+		})
+	}
+}
+
+func TestNoScaleFromZeroWhenDeploymentIsPaused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock_client.NewMockClient(ctrl)
+	recorder := record.NewFakeRecorder(1)
+	mockScaleClient := mock_scale.NewMockScalesGetter(ctrl)
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+
+	scaleExecutor := NewScaleExecutor(client, mockScaleClient, nil, recorder)
+
+	scaledObject := v1alpha1.ScaledObject{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "name",
+			Namespace: "namespace",
+		},
+		Spec: v1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &v1alpha1.ScaleTarget{
+				Name: "name",
+			},
+			Advanced: &v1alpha1.AdvancedConfig{
+				DeferScalingDuringRollout: true,
+			},
+		},
+		Status: v1alpha1.ScaledObjectStatus{
+			ScaleTargetGVKR: &v1alpha1.GroupVersionKindResource{
+				Group: "apps",
+				Kind:  "Deployment",
+			},
+		},
+	}
+
+	scaledObject.Status.Conditions = *v1alpha1.GetInitializedConditions()
+	scaledObject.Status.Conditions.SetReadyCondition(v1.ConditionTrue, "", "")
+
+	numberOfReplicas := int32(0)
+
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &numberOfReplicas,
+			Paused:   true,
+		},
+	})
+
+	// the scale subresource must not be touched
+	mockScaleClient.EXPECT().Scales(gomock.Any()).Times(0)
+
+	client.EXPECT().Status().Times(1).Return(statusWriter)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+
+	scaleExecutor.RequestScale(context.TODO(), &scaledObject, true, false)
+
+	condition := scaledObject.Status.Conditions.GetActiveCondition()
+	assert.Equal(t, true, condition.IsTrue())
+	assert.Equal(t, "ScalingDeferred", condition.Reason)
+}
+
+func TestScaleThroughSpecReplicasWithReplicasStrategy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock_client.NewMockClient(ctrl)
+	recorder := record.NewFakeRecorder(1)
+	mockScaleClient := mock_scale.NewMockScalesGetter(ctrl)
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+
+	scaleExecutor := NewScaleExecutor(client, mockScaleClient, nil, recorder)
+
+	minReplicas := int32(0)
+
+	scaledObject := v1alpha1.ScaledObject{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "name",
+			Namespace: "namespace",
+		},
+		Spec: v1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &v1alpha1.ScaleTarget{
+				Name: "name",
+			},
+			MinReplicaCount: &minReplicas,
+			Advanced: &v1alpha1.AdvancedConfig{
+				ScaleStrategy: v1alpha1.ScaleStrategyReplicas,
+			},
+		},
+		Status: v1alpha1.ScaledObjectStatus{
+			ScaleTargetGVKR: &v1alpha1.GroupVersionKindResource{
+				Group:   "example.com",
+				Version: "v1",
+				Kind:    "Worker",
+			},
+		},
+	}
+
+	scaledObject.Status.Conditions = *v1alpha1.GetInitializedConditions()
+
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).DoAndReturn(
+		func(_ context.Context, _ runtimeclient.ObjectKey, obj runtimeclient.Object) error {
+			return unstructured.SetNestedField(obj.(*unstructured.Unstructured).Object, int64(10), "spec", "replicas")
+		})
+
+	var patchData []byte
+	client.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, obj runtimeclient.Object, patch runtimeclient.Patch, _ ...runtimeclient.PatchOption) error {
+			assert.Equal(t, types.MergePatchType, patch.Type())
+			var err error
+			patchData, err = patch.Data(obj)
+			return err
+		})
+
+	client.EXPECT().Status().Return(statusWriter).Times(2)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+
+	scaleExecutor.RequestScale(context.TODO(), &scaledObject, false, false)
+
+	assert.JSONEq(t, `{"spec":{"replicas":0}}`, string(patchData))
+	condition := scaledObject.Status.Conditions.GetActiveCondition()
+	assert.Equal(t, true, condition.IsFalse())
+}
+
+func TestWarnWhenSpecReplicasDoesNotFollowScale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock_client.NewMockClient(ctrl)
+	recorder := record.NewFakeRecorder(1)
+	mockScaleClient := mock_scale.NewMockScalesGetter(ctrl)
+	mockScaleInterface := mock_scale.NewMockScaleInterface(ctrl)
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+
+	e := NewScaleExecutor(client, mockScaleClient, nil, recorder).(*scaleExecutor)
+
+	scaledObject := v1alpha1.ScaledObject{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "name",
+			Namespace: "namespace",
+		},
+		Spec: v1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &v1alpha1.ScaleTarget{
+				Name: "name",
+			},
+		},
+		Status: v1alpha1.ScaledObjectStatus{
+			ScaleTargetGVKR: &v1alpha1.GroupVersionKindResource{
+				Group:   "example.com",
+				Version: "v1",
+				Kind:    "Worker",
+			},
+		},
+	}
+
+	scale := &autoscalingv1.Scale{
+		Spec: autoscalingv1.ScaleSpec{
+			Replicas: 2,
+		},
+	}
+
+	specReplicas := int64(2)
+	mockScaleClient.EXPECT().Scales(gomock.Any()).Return(mockScaleInterface).Times(2)
+	mockScaleInterface.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Eq(scale), gomock.Any()).Times(2)
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).DoAndReturn(
+		func(_ context.Context, _ runtimeclient.ObjectKey, obj runtimeclient.Object) error {
+			return unstructured.SetNestedField(obj.(*unstructured.Unstructured).Object, specReplicas, "spec", "replicas")
+		})
+	client.EXPECT().Status().Return(statusWriter).Times(2)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+
+	_, err := e.updateScaleOnScaleTarget(context.TODO(), &scaledObject, scale, 5)
+	assert.NoError(t, err)
+
+	event := <-recorder.Events
+	assert.Contains(t, event, eventreason.KEDAScaleTargetReplicasMismatch)
+	condition := scaledObject.Status.Conditions.GetReplicasMismatchCondition()
+	assert.True(t, condition.IsTrue())
+	assert.Equal(t, eventreason.KEDAScaleTargetReplicasMismatch, condition.Reason)
+
+	// the condition is cleared once spec.replicas follows the /scale subresource
+	specReplicas = 5
+	_, err = e.updateScaleOnScaleTarget(context.TODO(), &scaledObject, scale, 5)
+	assert.NoError(t, err)
+	assert.Len(t, recorder.Events, 0)
+	condition = scaledObject.Status.Conditions.GetReplicasMismatchCondition()
+	assert.True(t, condition.IsFalse())
+	assert.Equal(t, ReplicasMatchReason, condition.Reason)
+
+	// the check is skipped when the /scale subresource is used explicitly
+	scaledObject.Spec.Advanced = &v1alpha1.AdvancedConfig{ScaleStrategy: v1alpha1.ScaleStrategyScale}
+	mockScaleClient.EXPECT().Scales(gomock.Any()).Return(mockScaleInterface)
+	mockScaleInterface.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Eq(scale), gomock.Any())
+
+	_, err = e.updateScaleOnScaleTarget(context.TODO(), &scaledObject, scale, 5)
+	assert.NoError(t, err)
+	assert.Len(t, recorder.Events, 0)
+}
+
+func TestScaleIsFrozenWhenScalingDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock_client.NewMockClient(ctrl)
+	recorder := record.NewFakeRecorder(1)
+	mockScaleClient := mock_scale.NewMockScalesGetter(ctrl)
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+
+	scaleExecutor := NewScaleExecutor(client, mockScaleClient, nil, recorder)
+
+	SetScalingDisabled(true)
+	defer SetScalingDisabled(false)
+
+	scaledObject := v1alpha1.ScaledObject{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "name",
+			Namespace: "namespace",
+		},
+		Spec: v1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &v1alpha1.ScaleTarget{
+				Name: "name",
+			},
+		},
+		Status: v1alpha1.ScaledObjectStatus{
+			ScaleTargetGVKR: &v1alpha1.GroupVersionKindResource{
+				Group: "apps",
+				Kind:  "Deployment",
+			},
+		},
+	}
+
+	scaledObject.Status.Conditions = *v1alpha1.GetInitializedConditions()
+
+	numberOfReplicas := int32(0)
+
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).SetArg(2, appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &numberOfReplicas,
+		},
+	}).Times(2)
+
+	// the Ready and Active conditions are updated, the scale target isn't
+	mockScaleClient.EXPECT().Scales(gomock.Any()).Times(0)
+	client.EXPECT().Status().Times(2).Return(statusWriter)
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+
+	scaleExecutor.RequestScale(context.TODO(), &scaledObject, true, false)
+
+	condition := scaledObject.Status.Conditions.GetActiveCondition()
+	assert.True(t, condition.IsTrue())
+	assert.Equal(t, ScalingDisabledReason, condition.Reason)
+
+	// the condition is only patched when it changes
+	scaleExecutor.RequestScale(context.TODO(), &scaledObject, true, false)
+}
+
+func TestTrackScaleFromZeroRecordsDuration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock_client.NewMockClient(ctrl)
+	statusWriter := mock_client.NewMockStatusWriter(ctrl)
+
+	scaleExecutor := NewScaleExecutor(client, nil, nil, record.NewFakeRecorder(1)).(*scaleExecutor)
+	scaleExecutor.readyReplicasPollInterval = time.Millisecond
+
+	scaledObject := v1alpha1.ScaledObject{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "name",
+			Namespace: "namespace",
+		},
+		Spec: v1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &v1alpha1.ScaleTarget{
+				Name: "name",
+			},
+		},
+		Status: v1alpha1.ScaledObjectStatus{
+			ScaleTargetGVKR: &v1alpha1.GroupVersionKindResource{
+				Group: "apps",
+				Kind:  "Deployment",
+			},
+		},
+	}
+
+	// no ready replica on the first check
+	gomock.InOrder(
+		client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&appsv1.Deployment{})).SetArg(2, appsv1.Deployment{}),
+		client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&appsv1.Deployment{})).SetArg(2, appsv1.Deployment{
+			Status: appsv1.DeploymentStatus{ReadyReplicas: 1},
+		}),
+	)
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(&v1alpha1.ScaledObject{})).SetArg(2, scaledObject)
+	client.EXPECT().Status().Return(statusWriter)
+
+	var duration *v1.Duration
+	statusWriter.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, obj runtimeclient.Object, _ runtimeclient.Patch, _ ...runtimeclient.PatchOption) error {
+			duration = obj.(*v1alpha1.ScaledObject).Status.LastScaleFromZeroDuration
+			return nil
+		})
+
+	scaleExecutor.trackScaleFromZero(context.TODO(), scaleExecutor.logger, &scaledObject, time.Now().Add(-time.Minute))
+
+	assert.NotNil(t, duration)
+	assert.GreaterOrEqual(t, duration.Duration, time.Minute)
+}
+
+func TestPatchReplicasOnScaleTargetForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock_client.NewMockClient(ctrl)
+	recorder := record.NewFakeRecorder(1)
+	mockScaleClient := mock_scale.NewMockScalesGetter(ctrl)
+
+	e := NewScaleExecutor(client, mockScaleClient, nil, recorder).(*scaleExecutor)
+
+	scaledObject := v1alpha1.ScaledObject{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "name",
+			Namespace: "namespace",
+		},
+		Spec: v1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &v1alpha1.ScaleTarget{
+				Name: "name",
+			},
+			Advanced: &v1alpha1.AdvancedConfig{
+				ScaleStrategy: v1alpha1.ScaleStrategyReplicas,
+			},
+		},
+		Status: v1alpha1.ScaledObjectStatus{
+			ScaleTargetGVKR: &v1alpha1.GroupVersionKindResource{
+				Group:    "example.com",
+				Version:  "v1",
+				Kind:     "Worker",
+				Resource: "workers",
+			},
+		},
+	}
+
+	client.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ runtimeclient.ObjectKey, obj runtimeclient.Object) error {
+			return unstructured.SetNestedField(obj.(*unstructured.Unstructured).Object, int64(3), "spec", "replicas")
+		})
+	client.EXPECT().Patch(gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		apierrors.NewForbidden(schema.GroupResource{Group: "example.com", Resource: "workers"}, "name", errors.New("no patch verb")))
+
+	currentReplicas, err := e.patchReplicasOnScaleTarget(context.TODO(), &scaledObject, 5)
+	assert.Equal(t, int32(3), currentReplicas)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), ScaleTargetPatchRoleLabel)
+	assert.Contains(t, err.Error(), "workers.example.com")
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package executor
+
+import (
+	"sync/atomic"
+)
+
+const (
+	// ScalingDisabledReason is the reason of the Active condition of the ScaledObjects and ScaledJobs while the
+	// scaling is disabled cluster-wide
+	ScalingDisabledReason = "ScalingDisabled"
+	// ScalingDisabledMessage is the message of the Active condition while the scaling is disabled cluster-wide
+	ScalingDisabledMessage = "Scaling is disabled cluster-wide by the KEDA operator"
+)
+
+// scalingDisabled is the cluster-wide emergency stop, it's shared by the scale handlers of the ScaledObjects and
+// ScaledJobs of the operator
+var scalingDisabled int32
+
+// SetScalingDisabled freezes or resumes the scaling actions of all the ScaledObjects and ScaledJobs, the scalers are
+// still polled and their metrics served while the scaling is disabled
+func SetScalingDisabled(disabled bool) {
+	var v int32
+	if disabled {
+		v = 1
+	}
+	atomic.StoreInt32(&scalingDisabled, v)
+}
+
+// IsScalingDisabled returns true if the scaling is disabled cluster-wide
+func IsScalingDisabled() bool {
+	return atomic.LoadInt32(&scalingDisabled) == 1
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	"github.com/go-logr/logr"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+// AwsSsmParameterStoreProvider is the name of the secret provider reading AWS SSM Parameter Store parameters
+const AwsSsmParameterStoreProvider = "aws-ssm-parameter-store"
+
+func init() {
+	if err := RegisterSecretProvider(AwsSsmParameterStoreProvider, newAwsSsmSecretProvider); err != nil {
+		panic(err)
+	}
+}
+
+// awsSsmSecretProvider reads the parameters of AWS SSM Parameter Store, SecureString parameters are decrypted
+// with their KMS key unless the withDecryption config is false
+type awsSsmSecretProvider struct {
+	client         ssmiface.SSMAPI
+	withDecryption bool
+}
+
+// newAwsSsmSecretProvider authenticates with the awsAccessKeyID and awsSecretAccessKey credentials, or with the
+// identity of the operator with the aws-eks and aws-kiam pod identities. The awsRoleArn config is assumed if set.
+func newAwsSsmSecretProvider(_ context.Context, _ logr.Logger, config SecretProviderConfig) (SecretProvider, error) {
+	region := config.Config["awsRegion"]
+	if region == "" {
+		return nil, errors.New("no awsRegion given")
+	}
+
+	awsConfig := &aws.Config{Region: aws.String(region)}
+	if val, ok := config.Config["useFipsEndpoint"]; ok && val != "" {
+		useFipsEndpoint, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("useFipsEndpoint parsing error %s", err.Error())
+		}
+		if useFipsEndpoint {
+			awsConfig.UseFIPSEndpoint = endpoints.FIPSEndpointStateEnabled
+		}
+	}
+	if endpoint := config.Config["awsEndpoint"]; endpoint != "" {
+		awsConfig.Endpoint = aws.String(endpoint)
+	}
+
+	withDecryption := true
+	if val, ok := config.Config["withDecryption"]; ok && val != "" {
+		parsed, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("withDecryption parsing error %s", err.Error())
+		}
+		withDecryption = parsed
+	}
+
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	accessKeyID, secretAccessKey := config.Credentials["awsAccessKeyID"], config.Credentials["awsSecretAccessKey"]
+	switch {
+	case accessKeyID != "" && secretAccessKey != "":
+		awsConfig.Credentials = credentials.NewStaticCredentials(accessKeyID, secretAccessKey, config.Credentials["awsSessionToken"])
+	case config.PodIdentity.Provider == kedav1alpha1.PodIdentityProviderAwsEKS || config.PodIdentity.Provider == kedav1alpha1.PodIdentityProviderAwsKiam:
+		// the default credential chain of the session uses the identity of the operator
+	default:
+		return nil, errors.New("awsAccessKeyID and awsSecretAccessKey credentials or an aws-eks or aws-kiam pod identity are required")
+	}
+	if roleArn := config.Config["awsRoleArn"]; roleArn != "" {
+		if awsConfig.Credentials != nil {
+			sess = sess.Copy(&aws.Config{Credentials: awsConfig.Credentials})
+		}
+		awsConfig.Credentials = stscreds.NewCredentials(sess, roleArn)
+	}
+
+	return &awsSsmSecretProvider{
+		client:         ssm.New(sess, awsConfig),
+		withDecryption: withDecryption,
+	}, nil
+}
+
+// Read returns the value of the parameter named key, version selects a version or a label of the parameter
+func (p *awsSsmSecretProvider) Read(ctx context.Context, key, version string) (string, error) {
+	name := key
+	if version != "" {
+		name = fmt.Sprintf("%s:%s", key, version)
+	}
+
+	output, err := p.client.GetParameterWithContext(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(p.withDecryption),
+	})
+	if err != nil {
+		return "", err
+	}
+	if output.Parameter == nil || output.Parameter.Value == nil {
+		return "", fmt.Errorf("parameter %s has no value", name)
+	}
+	return *output.Parameter.Value, nil
+}
+
+func (p *awsSsmSecretProvider) Close() {}
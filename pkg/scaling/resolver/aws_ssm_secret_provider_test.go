@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	"github.com/go-logr/logr"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+type mockSsmClient struct {
+	ssmiface.SSMAPI
+	parameters map[string]string
+	decrypted  bool
+}
+
+func (m *mockSsmClient) GetParameterWithContext(_ aws.Context, input *ssm.GetParameterInput, _ ...request.Option) (*ssm.GetParameterOutput, error) {
+	m.decrypted = *input.WithDecryption
+	value, ok := m.parameters[*input.Name]
+	if !ok {
+		return nil, awserr.New(ssm.ErrCodeParameterNotFound, "parameter not found", nil)
+	}
+	return &ssm.GetParameterOutput{Parameter: &ssm.Parameter{Name: input.Name, Value: aws.String(value)}}, nil
+}
+
+type parseAwsSsmConfigTestData struct {
+	name        string
+	config      map[string]string
+	credentials map[string]string
+	podIdentity kedav1alpha1.PodIdentityProvider
+	isError     bool
+}
+
+var testAwsSsmConfigs = []parseAwsSsmConfigTestData{
+	{"static credentials", map[string]string{"awsRegion": "eu-west-1"}, map[string]string{"awsAccessKeyID": "id", "awsSecretAccessKey": "key"}, "", false},
+	{"pod identity", map[string]string{"awsRegion": "eu-west-1"}, map[string]string{}, kedav1alpha1.PodIdentityProviderAwsEKS, false},
+	{"fips with role", map[string]string{"awsRegion": "us-gov-west-1", "useFipsEndpoint": "true", "awsRoleArn": "arn:aws:iam::123456789012:role/keda"},
+		map[string]string{"awsAccessKeyID": "id", "awsSecretAccessKey": "key", "awsSessionToken": "token"}, "", false},
+	{"custom endpoint", map[string]string{"awsRegion": "eu-west-1", "awsEndpoint": "http://localstack:4566", "withDecryption": "false"}, map[string]string{}, kedav1alpha1.PodIdentityProviderAwsKiam, false},
+	{"missing region", map[string]string{}, map[string]string{"awsAccessKeyID": "id", "awsSecretAccessKey": "key"}, "", true},
+	{"missing credentials", map[string]string{"awsRegion": "eu-west-1"}, map[string]string{"awsAccessKeyID": "id"}, "", true},
+	{"unsupported pod identity", map[string]string{"awsRegion": "eu-west-1"}, map[string]string{}, kedav1alpha1.PodIdentityProviderAzure, true},
+	{"invalid useFipsEndpoint", map[string]string{"awsRegion": "eu-west-1", "useFipsEndpoint": "a"}, map[string]string{"awsAccessKeyID": "id", "awsSecretAccessKey": "key"}, "", true},
+	{"invalid withDecryption", map[string]string{"awsRegion": "eu-west-1", "withDecryption": "a"}, map[string]string{"awsAccessKeyID": "id", "awsSecretAccessKey": "key"}, "", true},
+}
+
+func TestNewAwsSsmSecretProvider(t *testing.T) {
+	t.Setenv("AWS_CA_BUNDLE", "")
+
+	for _, testData := range testAwsSsmConfigs {
+		provider, err := newAwsSsmSecretProvider(context.Background(), logr.Discard(), SecretProviderConfig{
+			Config:      testData.config,
+			Credentials: testData.credentials,
+			PodIdentity: kedav1alpha1.AuthPodIdentity{Provider: testData.podIdentity},
+		})
+		if testData.isError {
+			if err == nil {
+				t.Errorf("%s: Expected error but got success", testData.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Expected success but got error: %s", testData.name, err)
+			continue
+		}
+		if decrypt := testData.config["withDecryption"] != "false"; provider.(*awsSsmSecretProvider).withDecryption != decrypt {
+			t.Errorf("%s: Expected withDecryption %v", testData.name, decrypt)
+		}
+	}
+}
+
+func TestAwsSsmSecretProviderRead(t *testing.T) {
+	client := &mockSsmClient{parameters: map[string]string{
+		"/app/db/password":      "latest",
+		"/app/db/password:3":    "v3",
+		"/app/db/password:prod": "labeled",
+	}}
+	provider := &awsSsmSecretProvider{client: client, withDecryption: true}
+
+	tests := []struct {
+		key     string
+		version string
+		value   string
+		isError bool
+	}{
+		{"/app/db/password", "", "latest", false},
+		{"/app/db/password", "3", "v3", false},
+		{"/app/db/password", "prod", "labeled", false},
+		{"/app/db/user", "", "", true},
+	}
+
+	for _, test := range tests {
+		value, err := provider.Read(context.Background(), test.key, test.version)
+		if test.isError {
+			if err == nil {
+				t.Errorf("Expected error reading %s but got success", test.key)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Expected success reading %s:%s but got error: %s", test.key, test.version, err)
+		}
+		if value != test.value {
+			t.Errorf("Expected %s reading %s:%s but got %s", test.value, test.key, test.version, value)
+		}
+		if !client.decrypted {
+			t.Error("Expected the parameter to be decrypted")
+		}
+	}
+}
+
+func TestAwsSsmSecretProviderIsRegistered(t *testing.T) {
+	if _, err := getSecretProviderFactory(AwsSsmParameterStoreProvider); err != nil {
+		t.Errorf("Expected the %s provider to be registered: %s", AwsSsmParameterStoreProvider, err)
+	}
+}
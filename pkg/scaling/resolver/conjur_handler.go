@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	kedautil "github.com/kedacore/keda/v2/pkg/util"
+)
+
+const conjurHTTPTimeout = 10 * time.Second
+
+// ConjurHandler is specification of CyberArk Conjur
+type ConjurHandler struct {
+	conjur     *kedav1alpha1.Conjur
+	httpClient *http.Client
+	token      string
+}
+
+// NewConjurHandler creates a ConjurHandler object
+func NewConjurHandler(c *kedav1alpha1.Conjur) *ConjurHandler {
+	return &ConjurHandler{
+		conjur: c,
+	}
+}
+
+// Initialize the Conjur client and exchange the configured credential for an access token
+func (ch *ConjurHandler) Initialize(ctx context.Context, client client.Client, logger logr.Logger, triggerNamespace string) error {
+	ch.httpClient = kedautil.CreateHTTPClient(conjurHTTPTimeout, false)
+	if ch.conjur.CACert != nil {
+		caCert := resolveAuthSecret(ctx, client, logger, ch.conjur.CACert.SecretKeyRef.Name, triggerNamespace, ch.conjur.CACert.SecretKeyRef.Key)
+		if caCert == "" {
+			return errors.New("could not get Conjur CA certificate")
+		}
+		tlsConfig, err := kedautil.NewTLSConfig("", "", caCert)
+		if err != nil {
+			return err
+		}
+		ch.httpClient.Transport = &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment}
+	}
+
+	req, err := ch.authenticationRequest(ctx, client, logger, triggerNamespace)
+	if err != nil {
+		return err
+	}
+
+	body, err := ch.do(req)
+	if err != nil {
+		return fmt.Errorf("error authenticating to Conjur: %s", err)
+	}
+
+	// Conjur returns the raw access token, it has to be sent back base64 encoded
+	ch.token = base64.StdEncoding.EncodeToString(body)
+
+	return nil
+}
+
+func (ch *ConjurHandler) authenticationRequest(ctx context.Context, client client.Client, logger logr.Logger, triggerNamespace string) (*http.Request, error) {
+	address := strings.TrimSuffix(ch.conjur.Address, "/")
+
+	switch ch.conjur.Authentication {
+	case kedav1alpha1.ConjurAuthenticationAPIKey:
+		if len(ch.conjur.Credential.Login) == 0 {
+			return nil, errors.New("conjur login not in config")
+		}
+
+		if ch.conjur.Credential.APIKey == nil {
+			return nil, errors.New("conjur api key not in config")
+		}
+
+		apiKey := resolveAuthSecret(ctx, client, logger, ch.conjur.Credential.APIKey.SecretKeyRef.Name, triggerNamespace, ch.conjur.Credential.APIKey.SecretKeyRef.Key)
+		if apiKey == "" {
+			return nil, errors.New("could not get Conjur api key")
+		}
+
+		authURL := fmt.Sprintf("%s/authn/%s/%s/authenticate", address, url.PathEscape(ch.conjur.Account), url.PathEscape(ch.conjur.Credential.Login))
+		return http.NewRequestWithContext(ctx, "POST", authURL, strings.NewReader(apiKey))
+	case kedav1alpha1.ConjurAuthenticationJWT:
+		if len(ch.conjur.ServiceID) == 0 {
+			return nil, errors.New("conjur jwt authenticator service id not in config")
+		}
+
+		if len(ch.conjur.Credential.ServiceAccount) == 0 {
+			return nil, errors.New("k8s SA file not in config")
+		}
+
+		// Get the JWT from POD
+		jwt, err := ioutil.ReadFile(ch.conjur.Credential.ServiceAccount)
+		if err != nil {
+			return nil, err
+		}
+
+		authURL := fmt.Sprintf("%s/authn-jwt/%s/%s", address, url.PathEscape(ch.conjur.ServiceID), url.PathEscape(ch.conjur.Account))
+		// the host identity is optional, by default Conjur resolves it from the token claims
+		if len(ch.conjur.Credential.Login) > 0 {
+			authURL = fmt.Sprintf("%s/%s", authURL, url.PathEscape(ch.conjur.Credential.Login))
+		}
+		authURL += "/authenticate"
+
+		data := url.Values{}
+		data.Set("jwt", strings.TrimSpace(string(jwt)))
+		req, err := http.NewRequestWithContext(ctx, "POST", authURL, strings.NewReader(data.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	default:
+		return nil, fmt.Errorf("conjur auth method %s is not supported", ch.conjur.Authentication)
+	}
+}
+
+// Read returns the value of the given Conjur variable
+func (ch *ConjurHandler) Read(ctx context.Context, variableID string) (string, error) {
+	secretURL := fmt.Sprintf("%s/secrets/%s/variable/%s", strings.TrimSuffix(ch.conjur.Address, "/"),
+		url.PathEscape(ch.conjur.Account), url.PathEscape(variableID))
+	req, err := http.NewRequestWithContext(ctx, "GET", secretURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Token token=\"%s\"", ch.token))
+
+	body, err := ch.do(req)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+func (ch *ConjurHandler) do(req *http.Request) ([]byte, error) {
+	resp, err := ch.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("conjur returned %d: %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
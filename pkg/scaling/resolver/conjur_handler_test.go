@@ -0,0 +1,167 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+const (
+	conjurTestAccount = "keda"
+	conjurTestToken   = `{"protected":"p","payload":"p","signature":"s"}`
+)
+
+func newConjurTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/authn/keda/host/app/authenticate":
+			body, _ := ioutil.ReadAll(r.Body)
+			if string(body) != "apikey" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(conjurTestToken))
+		case "/authn-jwt/k8s/keda/authenticate":
+			if r.FormValue("jwt") != "jwt-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(conjurTestToken))
+		case "/secrets/keda/variable/apps/db/password":
+			expected := "Token token=\"" + base64.StdEncoding.EncodeToString([]byte(conjurTestToken)) + "\""
+			if r.Header.Get("Authorization") != expected {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte("s3cr3t"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestConjurHandler(t *testing.T) {
+	server := newConjurTestServer()
+	defer server.Close()
+
+	jwtFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(jwtFile, []byte("jwt-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	apiKeyRef := &kedav1alpha1.ValueFromSecret{SecretKeyRef: kedav1alpha1.SecretKeyRef{Name: "conjur", Key: "apiKey"}}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "conjur", Namespace: namespace},
+		Data:       map[string][]byte{"apiKey": []byte("apikey"), "wrongKey": []byte("wrong")},
+	}
+
+	tests := []struct {
+		name       string
+		conjur     kedav1alpha1.Conjur
+		variableID string
+		expected   string
+		isError    bool
+	}{
+		{
+			name: "api key",
+			conjur: kedav1alpha1.Conjur{
+				Authentication: kedav1alpha1.ConjurAuthenticationAPIKey,
+				Credential:     kedav1alpha1.ConjurCredential{Login: "host/app", APIKey: apiKeyRef},
+			},
+			variableID: "apps/db/password",
+			expected:   "s3cr3t",
+		},
+		{
+			name: "jwt",
+			conjur: kedav1alpha1.Conjur{
+				Authentication: kedav1alpha1.ConjurAuthenticationJWT,
+				ServiceID:      "k8s",
+				Credential:     kedav1alpha1.ConjurCredential{ServiceAccount: jwtFile},
+			},
+			variableID: "apps/db/password",
+			expected:   "s3cr3t",
+		},
+		{
+			name: "wrong api key",
+			conjur: kedav1alpha1.Conjur{
+				Authentication: kedav1alpha1.ConjurAuthenticationAPIKey,
+				Credential: kedav1alpha1.ConjurCredential{Login: "host/app",
+					APIKey: &kedav1alpha1.ValueFromSecret{SecretKeyRef: kedav1alpha1.SecretKeyRef{Name: "conjur", Key: "wrongKey"}}},
+			},
+			isError: true,
+		},
+		{
+			name: "jwt without service id",
+			conjur: kedav1alpha1.Conjur{
+				Authentication: kedav1alpha1.ConjurAuthenticationJWT,
+				Credential:     kedav1alpha1.ConjurCredential{ServiceAccount: jwtFile},
+			},
+			isError: true,
+		},
+		{
+			name: "unsupported authentication",
+			conjur: kedav1alpha1.Conjur{
+				Authentication: "token",
+			},
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conjur := test.conjur
+			conjur.Address = server.URL
+			conjur.Account = conjurTestAccount
+
+			handler := NewConjurHandler(&conjur)
+			client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(secret).Build()
+			err := handler.Initialize(context.Background(), client, logf.Log.WithName("test"), namespace)
+			if test.isError {
+				if err == nil {
+					t.Error("Expected error but got success")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected success but got error: %s", err)
+			}
+
+			value, err := handler.Read(context.Background(), test.variableID)
+			if err != nil {
+				t.Fatalf("Expected success but got error: %s", err)
+			}
+			if value != test.expected {
+				t.Errorf("Expected %s but got %s", test.expected, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+// DefaultJobTemplateKey is the key of the ConfigMap holding the Job manifest if fromTemplateRef.key isn't set
+const DefaultJobTemplateKey = "job.yaml"
+
+// ResolveJobSpec returns a copy of the spec of the Jobs created by the ScaledJob, read from the resource referenced
+// by jobTargetRef.fromTemplateRef if it's set. The referenced resources are read through the client, which serves
+// them from the informer cache of the operator.
+func ResolveJobSpec(ctx context.Context, kubeClient client.Client, scaledJob *kedav1alpha1.ScaledJob) (*batchv1.JobSpec, error) {
+	jobTargetRef := scaledJob.Spec.JobTargetRef
+	if jobTargetRef == nil {
+		return nil, fmt.Errorf("scaledJob.spec.jobTargetRef is not set")
+	}
+	templateRef := jobTargetRef.FromTemplateRef
+	if templateRef == nil {
+		return jobTargetRef.JobSpec.DeepCopy(), nil
+	}
+	if len(jobTargetRef.Template.Spec.Containers) > 0 {
+		return nil, fmt.Errorf("scaledJob.spec.jobTargetRef can't set both a template and fromTemplateRef")
+	}
+
+	key := client.ObjectKey{Namespace: scaledJob.Namespace, Name: templateRef.Name}
+	switch templateRef.Kind {
+	case "ConfigMap":
+		configMap := &corev1.ConfigMap{}
+		if err := kubeClient.Get(ctx, key, configMap); err != nil {
+			return nil, fmt.Errorf("error getting the Job template ConfigMap %s: %s", templateRef.Name, err)
+		}
+		dataKey := templateRef.Key
+		if dataKey == "" {
+			dataKey = DefaultJobTemplateKey
+		}
+		manifest, ok := configMap.Data[dataKey]
+		if !ok {
+			return nil, fmt.Errorf("the Job template ConfigMap %s has no key %s", templateRef.Name, dataKey)
+		}
+		return parseJobManifest(manifest)
+	case "CronJob":
+		cronJob := &batchv1.CronJob{}
+		if err := kubeClient.Get(ctx, key, cronJob); err != nil {
+			return nil, fmt.Errorf("error getting the Job template CronJob %s: %s", templateRef.Name, err)
+		}
+		return cronJob.Spec.JobTemplate.Spec.DeepCopy(), nil
+	default:
+		return nil, fmt.Errorf("unknown kind %s in scaledJob.spec.jobTargetRef.fromTemplateRef, ConfigMap or CronJob expected", templateRef.Kind)
+	}
+}
+
+// parseJobManifest reads the spec of a Job manifest, a bare JobSpec is accepted as well
+func parseJobManifest(manifest string) (*batchv1.JobSpec, error) {
+	job := &batchv1.Job{}
+	if err := yaml.Unmarshal([]byte(manifest), job); err != nil {
+		return nil, fmt.Errorf("error parsing the Job template: %s", err)
+	}
+	if job.Kind == "Job" {
+		return &job.Spec, nil
+	}
+
+	jobSpec := &batchv1.JobSpec{}
+	if err := yaml.Unmarshal([]byte(manifest), jobSpec); err != nil {
+		return nil, fmt.Errorf("error parsing the Job template: %s", err)
+	}
+	if len(jobSpec.Template.Spec.Containers) == 0 {
+		return nil, fmt.Errorf("the Job template has no containers")
+	}
+	return jobSpec, nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+const (
+	jobTemplateManifest = `apiVersion: batch/v1
+kind: Job
+spec:
+  backoffLimit: 2
+  template:
+    spec:
+      containers:
+      - name: worker
+        image: worker:v1
+`
+	jobSpecTemplateManifest = `template:
+  spec:
+    containers:
+    - name: worker
+      image: worker:v2
+`
+)
+
+func TestResolveJobSpec(t *testing.T) {
+	inlineSpec := batchv1.JobSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "worker", Image: "worker:inline"}}},
+		},
+	}
+	existing := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "job-templates", Namespace: namespace},
+			Data:       map[string]string{DefaultJobTemplateKey: jobTemplateManifest, "spec.yaml": jobSpecTemplateManifest, "invalid.yaml": "image: worker"},
+		},
+		&batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: namespace},
+			Spec: batchv1.CronJobSpec{
+				JobTemplate: batchv1.JobTemplateSpec{
+					Spec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "worker", Image: "worker:cron"}}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		jobTargetRef  *kedav1alpha1.JobTargetRef
+		expectedImage string
+		isError       bool
+	}{
+		{
+			name:          "inline spec",
+			jobTargetRef:  &kedav1alpha1.JobTargetRef{JobSpec: inlineSpec},
+			expectedImage: "worker:inline",
+		},
+		{
+			name:          "job manifest in configmap default key",
+			jobTargetRef:  &kedav1alpha1.JobTargetRef{FromTemplateRef: &kedav1alpha1.JobTemplateRef{Kind: "ConfigMap", Name: "job-templates"}},
+			expectedImage: "worker:v1",
+		},
+		{
+			name:          "job spec in configmap key",
+			jobTargetRef:  &kedav1alpha1.JobTargetRef{FromTemplateRef: &kedav1alpha1.JobTemplateRef{Kind: "ConfigMap", Name: "job-templates", Key: "spec.yaml"}},
+			expectedImage: "worker:v2",
+		},
+		{
+			name:          "job template of cronjob",
+			jobTargetRef:  &kedav1alpha1.JobTargetRef{FromTemplateRef: &kedav1alpha1.JobTemplateRef{Kind: "CronJob", Name: "nightly"}},
+			expectedImage: "worker:cron",
+		},
+		{
+			name:         "missing configmap key",
+			jobTargetRef: &kedav1alpha1.JobTargetRef{FromTemplateRef: &kedav1alpha1.JobTemplateRef{Kind: "ConfigMap", Name: "job-templates", Key: "missing.yaml"}},
+			isError:      true,
+		},
+		{
+			name:         "configmap key without containers",
+			jobTargetRef: &kedav1alpha1.JobTargetRef{FromTemplateRef: &kedav1alpha1.JobTemplateRef{Kind: "ConfigMap", Name: "job-templates", Key: "invalid.yaml"}},
+			isError:      true,
+		},
+		{
+			name:         "missing cronjob",
+			jobTargetRef: &kedav1alpha1.JobTargetRef{FromTemplateRef: &kedav1alpha1.JobTemplateRef{Kind: "CronJob", Name: "hourly"}},
+			isError:      true,
+		},
+		{
+			name:         "both inline template and fromTemplateRef",
+			jobTargetRef: &kedav1alpha1.JobTargetRef{JobSpec: inlineSpec, FromTemplateRef: &kedav1alpha1.JobTemplateRef{Kind: "CronJob", Name: "nightly"}},
+			isError:      true,
+		},
+		{
+			name:    "missing jobTargetRef",
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			scaledJob := &kedav1alpha1.ScaledJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: namespace},
+				Spec:       kedav1alpha1.ScaledJobSpec{JobTargetRef: test.jobTargetRef},
+			}
+			client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(existing...).Build()
+
+			jobSpec, err := ResolveJobSpec(context.TODO(), client, scaledJob)
+			if test.isError {
+				if err == nil {
+					t.Error("Expected error but got success")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected success but got error: %s", err)
+			}
+			if image := jobSpec.Template.Spec.Containers[0].Image; image != test.expectedImage {
+				t.Errorf("Expected image %s but got %s", test.expectedImage, image)
+			}
+		})
+	}
+}
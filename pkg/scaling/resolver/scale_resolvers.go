@@ -95,7 +95,12 @@ func ResolveScaleTargetPodSpec(ctx context.Context, kubeClient client.Client, lo
 
 		return &podTemplateSpec, obj.Spec.ScaleTargetRef.EnvSourceContainerName, nil
 	case *kedav1alpha1.ScaledJob:
-		return &obj.Spec.JobTargetRef.Template, obj.Spec.EnvSourceContainerName, nil
+		jobSpec, err := ResolveJobSpec(ctx, kubeClient, obj)
+		if err != nil {
+			logger.Error(err, "Error resolving the Job template")
+			return nil, "", err
+		}
+		return &jobSpec.Template, obj.Spec.EnvSourceContainerName, nil
 	default:
 		return nil, "", fmt.Errorf("unknown scalable object type %v", scalableObject)
 	}
@@ -135,8 +140,13 @@ func ResolveAuthRefAndPodIdentity(ctx context.Context, client client.Client, log
 	namespace string) (map[string]string, kedav1alpha1.AuthPodIdentity, error) {
 	if podTemplateSpec != nil {
 		authParams, podIdentity := resolveAuthRef(ctx, client, logger, triggerAuthRef, &podTemplateSpec.Spec, namespace)
+		podIdentity = overridePodIdentity(podIdentity, triggerAuthRef)
 
-		if podIdentity.Provider == kedav1alpha1.PodIdentityProviderAwsEKS {
+		switch {
+		case podIdentity.Provider == kedav1alpha1.PodIdentityProviderAwsEKS && podIdentity.IdentityID != "":
+			// the role set on the trigger takes precedence over the one of the service account
+			authParams["awsRoleArn"] = podIdentity.IdentityID
+		case podIdentity.Provider == kedav1alpha1.PodIdentityProviderAwsEKS:
 			serviceAccountName := podTemplateSpec.Spec.ServiceAccountName
 			serviceAccount := &corev1.ServiceAccount{}
 			err := client.Get(ctx, types.NamespacedName{Name: serviceAccountName, Namespace: namespace}, serviceAccount)
@@ -145,7 +155,9 @@ func ResolveAuthRefAndPodIdentity(ctx context.Context, client client.Client, log
 					fmt.Errorf("error getting service account: '%s', error: %s", serviceAccountName, err)
 			}
 			authParams["awsRoleArn"] = serviceAccount.Annotations[kedav1alpha1.PodIdentityAnnotationEKS]
-		} else if podIdentity.Provider == kedav1alpha1.PodIdentityProviderAwsKiam {
+		case podIdentity.Provider == kedav1alpha1.PodIdentityProviderAwsKiam && podIdentity.IdentityID != "":
+			authParams["awsRoleArn"] = podIdentity.IdentityID
+		case podIdentity.Provider == kedav1alpha1.PodIdentityProviderAwsKiam:
 			authParams["awsRoleArn"] = podTemplateSpec.ObjectMeta.Annotations[kedav1alpha1.PodIdentityAnnotationKiam]
 		}
 		return authParams, podIdentity, nil
@@ -155,6 +167,21 @@ func ResolveAuthRefAndPodIdentity(ctx context.Context, client client.Client, log
 	return authParams, kedav1alpha1.AuthPodIdentity{Provider: kedav1alpha1.PodIdentityProviderNone}, nil
 }
 
+// overridePodIdentity applies the identity set on the trigger authenticationRef, if any,
+// to the pod identity resolved from the TriggerAuthentication
+func overridePodIdentity(podIdentity kedav1alpha1.AuthPodIdentity, triggerAuthRef *kedav1alpha1.ScaledObjectAuthRef) kedav1alpha1.AuthPodIdentity {
+	if triggerAuthRef == nil || triggerAuthRef.PodIdentity == nil {
+		return podIdentity
+	}
+	if triggerAuthRef.PodIdentity.IdentityID != "" {
+		podIdentity.IdentityID = triggerAuthRef.PodIdentity.IdentityID
+	}
+	if triggerAuthRef.PodIdentity.Audience != "" {
+		podIdentity.Audience = triggerAuthRef.PodIdentity.Audience
+	}
+	return podIdentity
+}
+
 // resolveAuthRef provides authentication parameters needed authenticate scaler with the environment.
 // based on authentication method defined in TriggerAuthentication, authParams and podIdentity is returned
 func resolveAuthRef(ctx context.Context, client client.Client, logger logr.Logger,
@@ -232,6 +259,34 @@ func resolveAuthRef(ctx context.Context, client client.Client, logger logr.Logge
 					}
 				}
 			}
+			if triggerAuthSpec.Conjur != nil && len(triggerAuthSpec.Conjur.Secrets) > 0 {
+				conjur := NewConjurHandler(triggerAuthSpec.Conjur)
+				err := conjur.Initialize(ctx, client, logger, triggerNamespace)
+				if err != nil {
+					logger.Error(err, "Error authenticating to Conjur", "triggerAuthRef.Name", triggerAuthRef.Name)
+				} else {
+					for _, secret := range triggerAuthSpec.Conjur.Secrets {
+						res, err := conjur.Read(ctx, secret.VariableID)
+						if err != nil {
+							logger.Error(err, "Error trying to read secret from Conjur", "triggerAuthRef.Name", triggerAuthRef.Name,
+								"secret.VariableID", secret.VariableID)
+						} else {
+							result[secret.Parameter] = res
+						}
+					}
+				}
+			}
+			for _, provider := range triggerAuthSpec.ExternalSecretProviders {
+				secrets, err := resolveExternalSecretProvider(ctx, client, logger, provider, podIdentity, triggerNamespace)
+				if err != nil {
+					logger.Error(err, "Error resolving secrets from secret provider", "triggerAuthRef.Name", triggerAuthRef.Name,
+						"provider", provider.Name)
+					continue
+				}
+				for parameter, value := range secrets {
+					result[parameter] = value
+				}
+			}
 		}
 	}
 
@@ -240,7 +295,9 @@ func resolveAuthRef(ctx context.Context, client client.Client, logger logr.Logge
 
 var clusterObjectNamespaceCache *string
 
-func getClusterObjectNamespace() (string, error) {
+// GetClusterObjectNamespace returns the namespace of the cluster-scoped objects of KEDA, the namespace of the operator
+// unless KEDA_CLUSTER_OBJECT_NAMESPACE is set
+func GetClusterObjectNamespace() (string, error) {
 	// Check if a cached value is available.
 	if clusterObjectNamespaceCache != nil {
 		return *clusterObjectNamespaceCache, nil
@@ -268,7 +325,7 @@ func getTriggerAuthSpec(ctx context.Context, client client.Client, triggerAuthRe
 		}
 		return &triggerAuth.Spec, namespace, nil
 	} else if triggerAuthRef.Kind == "ClusterTriggerAuthentication" {
-		clusterNamespace, err := getClusterObjectNamespace()
+		clusterNamespace, err := GetClusterObjectNamespace()
 		if err != nil {
 			return nil, "", err
 		}
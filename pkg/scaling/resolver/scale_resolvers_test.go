@@ -349,6 +349,73 @@ func TestResolveAuthRef(t *testing.T) {
 	}
 }
 
+func TestResolveAuthRefAndPodIdentityOverride(t *testing.T) {
+	triggerAuth := &kedav1alpha1.TriggerAuthentication{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      triggerAuthenticationName,
+		},
+		Spec: kedav1alpha1.TriggerAuthenticationSpec{
+			PodIdentity: &kedav1alpha1.AuthPodIdentity{
+				Provider: kedav1alpha1.PodIdentityProviderAwsKiam,
+			},
+		},
+	}
+	podTemplateSpec := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{kedav1alpha1.PodIdentityAnnotationKiam: "arn:aws:iam::123456789012:role/default"},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		soar                *kedav1alpha1.ScaledObjectAuthRef
+		expectedRoleArn     string
+		expectedPodIdentity kedav1alpha1.AuthPodIdentity
+	}{
+		{
+			name:                "no override",
+			soar:                &kedav1alpha1.ScaledObjectAuthRef{Name: triggerAuthenticationName},
+			expectedRoleArn:     "arn:aws:iam::123456789012:role/default",
+			expectedPodIdentity: kedav1alpha1.AuthPodIdentity{Provider: kedav1alpha1.PodIdentityProviderAwsKiam},
+		},
+		{
+			name: "identity override",
+			soar: &kedav1alpha1.ScaledObjectAuthRef{
+				Name:        triggerAuthenticationName,
+				PodIdentity: &kedav1alpha1.AuthPodIdentityOverride{IdentityID: "arn:aws:iam::123456789012:role/queue", Audience: "sts.amazonaws.com"},
+			},
+			expectedRoleArn: "arn:aws:iam::123456789012:role/queue",
+			expectedPodIdentity: kedav1alpha1.AuthPodIdentity{
+				Provider:   kedav1alpha1.PodIdentityProviderAwsKiam,
+				IdentityID: "arn:aws:iam::123456789012:role/queue",
+				Audience:   "sts.amazonaws.com",
+			},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			gotMap, gotPodIdentity, err := ResolveAuthRefAndPodIdentity(
+				context.Background(),
+				fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(triggerAuth).Build(),
+				logf.Log.WithName("test"),
+				test.soar,
+				podTemplateSpec,
+				namespace)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if gotMap["awsRoleArn"] != test.expectedRoleArn {
+				t.Errorf("Unexpected awsRoleArn, wanted: %q got: %q", test.expectedRoleArn, gotMap["awsRoleArn"])
+			}
+			if gotPodIdentity != test.expectedPodIdentity {
+				t.Errorf("Unexpected podidentity, wanted: %q got: %q", test.expectedPodIdentity, gotPodIdentity)
+			}
+		})
+	}
+}
+
 func TestResolveDependentEnv(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+// SecretProvider reads secrets from an external secret store, e.g. Akeyless, Doppler or 1Password Connect
+type SecretProvider interface {
+	// Read returns the value of the secret stored under key, version is empty for the latest one
+	Read(ctx context.Context, key, version string) (string, error)
+	// Close releases the resources held by the provider
+	Close()
+}
+
+// SecretProviderConfig is passed to a SecretProviderFactory when a TriggerAuthentication
+// references the provider
+type SecretProviderConfig struct {
+	// Config is the free form provider configuration from the TriggerAuthentication
+	Config map[string]string
+	// Credentials holds the resolved values of the referenced Kubernetes secrets, keyed by parameter
+	Credentials map[string]string
+	// PodIdentity is the pod identity set on the TriggerAuthentication
+	PodIdentity kedav1alpha1.AuthPodIdentity
+	// Namespace is the namespace the TriggerAuthentication credentials are resolved from
+	Namespace string
+}
+
+// SecretProviderFactory creates an authenticated SecretProvider
+type SecretProviderFactory func(ctx context.Context, logger logr.Logger, config SecretProviderConfig) (SecretProvider, error)
+
+var (
+	secretProviders     = map[string]SecretProviderFactory{}
+	secretProvidersLock sync.RWMutex
+)
+
+// RegisterSecretProvider makes a secret provider available under the given name to
+// the externalSecretProviders section of TriggerAuthentication. It is meant to be
+// called from the init function of the package implementing the provider.
+func RegisterSecretProvider(name string, factory SecretProviderFactory) error {
+	secretProvidersLock.Lock()
+	defer secretProvidersLock.Unlock()
+
+	if name == "" {
+		return fmt.Errorf("secret provider name must not be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("secret provider %s has no factory", name)
+	}
+	if _, ok := secretProviders[name]; ok {
+		return fmt.Errorf("secret provider %s is already registered", name)
+	}
+	secretProviders[name] = factory
+	return nil
+}
+
+func getSecretProviderFactory(name string) (SecretProviderFactory, error) {
+	secretProvidersLock.RLock()
+	defer secretProvidersLock.RUnlock()
+
+	factory, ok := secretProviders[name]
+	if !ok {
+		return nil, fmt.Errorf("secret provider %s is not registered", name)
+	}
+	return factory, nil
+}
+
+// resolveExternalSecretProvider returns the parameters read through a registered secret provider
+func resolveExternalSecretProvider(ctx context.Context, client client.Client, logger logr.Logger,
+	provider kedav1alpha1.ExternalSecretProvider, podIdentity kedav1alpha1.AuthPodIdentity, triggerNamespace string) (map[string]string, error) {
+	factory, err := getSecretProviderFactory(provider.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := make(map[string]string, len(provider.Credentials))
+	for _, e := range provider.Credentials {
+		credentials[e.Parameter] = resolveAuthSecret(ctx, client, logger, e.Name, triggerNamespace, e.Key)
+	}
+
+	secretProvider, err := factory(ctx, logger, SecretProviderConfig{
+		Config:      provider.Config,
+		Credentials: credentials,
+		PodIdentity: podIdentity,
+		Namespace:   triggerNamespace,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error initializing secret provider %s: %s", provider.Name, err)
+	}
+	defer secretProvider.Close()
+
+	result := make(map[string]string, len(provider.Secrets))
+	for _, secret := range provider.Secrets {
+		value, err := secretProvider.Read(ctx, secret.Key, secret.Version)
+		if err != nil {
+			logger.Error(err, "Error trying to read secret from secret provider", "provider", provider.Name,
+				"secret.Key", secret.Key, "secret.Version", secret.Version)
+			continue
+		}
+		result[secret.Parameter] = value
+	}
+	return result, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+type testSecretProvider struct {
+	secrets map[string]string
+	closed  *bool
+}
+
+func (p *testSecretProvider) Read(_ context.Context, key, version string) (string, error) {
+	value, ok := p.secrets[key+version]
+	if !ok {
+		return "", fmt.Errorf("secret %s not found", key)
+	}
+	return value, nil
+}
+
+func (p *testSecretProvider) Close() {
+	*p.closed = true
+}
+
+func TestRegisterSecretProvider(t *testing.T) {
+	factory := func(context.Context, logr.Logger, SecretProviderConfig) (SecretProvider, error) {
+		return nil, nil
+	}
+
+	if err := RegisterSecretProvider("test-register", factory); err != nil {
+		t.Fatalf("Expected success but got error: %s", err)
+	}
+	if err := RegisterSecretProvider("test-register", factory); err == nil {
+		t.Error("Expected error registering the same provider twice but got success")
+	}
+	if err := RegisterSecretProvider("", factory); err == nil {
+		t.Error("Expected error registering a provider without name but got success")
+	}
+	if err := RegisterSecretProvider("test-register-nil", nil); err == nil {
+		t.Error("Expected error registering a provider without factory but got success")
+	}
+}
+
+func TestResolveExternalSecretProvider(t *testing.T) {
+	closed := false
+	var receivedConfig SecretProviderConfig
+	err := RegisterSecretProvider("test-resolve", func(_ context.Context, _ logr.Logger, config SecretProviderConfig) (SecretProvider, error) {
+		receivedConfig = config
+		if config.Credentials["token"] != "s3cr3t" {
+			return nil, fmt.Errorf("invalid token")
+		}
+		return &testSecretProvider{
+			secrets: map[string]string{"/db/password": "pass", "/db/password2": "pass-v2"},
+			closed:  &closed,
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "provider", Namespace: namespace},
+		Data:       map[string][]byte{"token": []byte("s3cr3t")},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(secret).Build()
+
+	provider := kedav1alpha1.ExternalSecretProvider{
+		Name:        "test-resolve",
+		Config:      map[string]string{"gatewayUrl": "https://gateway"},
+		Credentials: []kedav1alpha1.AuthSecretTargetRef{{Parameter: "token", Name: "provider", Key: "token"}},
+		Secrets: []kedav1alpha1.ExternalSecret{
+			{Parameter: "password", Key: "/db/password"},
+			{Parameter: "passwordV2", Key: "/db/password", Version: "2"},
+			{Parameter: "missing", Key: "/db/missing"},
+		},
+	}
+
+	result, err := resolveExternalSecretProvider(context.Background(), client, logf.Log.WithName("test"), provider, kedav1alpha1.AuthPodIdentity{}, namespace)
+	if err != nil {
+		t.Fatalf("Expected success but got error: %s", err)
+	}
+
+	expected := map[string]string{"password": "pass", "passwordV2": "pass-v2"}
+	if len(result) != len(expected) {
+		t.Errorf("Expected %v but got %v", expected, result)
+	}
+	for k, v := range expected {
+		if result[k] != v {
+			t.Errorf("Expected %s for %s but got %s", v, k, result[k])
+		}
+	}
+	if receivedConfig.Config["gatewayUrl"] != "https://gateway" || receivedConfig.Namespace != namespace {
+		t.Errorf("Unexpected provider config %v", receivedConfig)
+	}
+	if !closed {
+		t.Error("Expected provider to be closed")
+	}
+
+	provider.Name = "not-registered"
+	if _, err := resolveExternalSecretProvider(context.Background(), client, logf.Log.WithName("test"), provider, kedav1alpha1.AuthPodIdentity{}, namespace); err == nil {
+		t.Error("Expected error for an unregistered provider but got success")
+	}
+}
@@ -19,6 +19,7 @@ package scaling
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	"github.com/kedacore/keda/v2/pkg/eventreason"
@@ -46,6 +48,10 @@ type ScaleHandler interface {
 	DeleteScalableObject(ctx context.Context, scalableObject interface{}) error
 	GetScalersCache(ctx context.Context, scalableObject interface{}) (*cache.ScalersCache, error)
 	ClearScalersCache(ctx context.Context, scalableObject interface{}) error
+	// Shutdown stops the scale loops, waits for their in-flight scale operations and closes the scalers
+	Shutdown(ctx context.Context)
+	// WatchScaleLoops restarts the stalled scale loops until the context is done
+	WatchScaleLoops(ctx context.Context, stallFactor int)
 }
 
 type scaleHandler struct {
@@ -56,7 +62,24 @@ type scaleHandler struct {
 	globalHTTPTimeout time.Duration
 	recorder          record.EventRecorder
 	scalerCaches      map[string]*cache.ScalersCache
+	permanentErrors   map[string]permanentBuildError
 	lock              *sync.RWMutex
+	// scaleLoops tracks the goroutines of the scale loops and push scalers, to wait for them on Shutdown
+	scaleLoops *sync.WaitGroup
+	// stopped is set on Shutdown, guarded by lock, no scale loop is started afterwards
+	stopped bool
+	// scaleLoopWatches tracks the progress of the scale loops for the watchdog, guarded by lock
+	scaleLoopWatches map[string]*scaleLoopWatch
+}
+
+// scaleOperationTimeout bounds a scale operation, which isn't canceled with its scale loop to not be left half-applied
+const scaleOperationTimeout = 30 * time.Second
+
+// permanentBuildError remembers a permanent scaler construction error for the generation it was seen on,
+// so the scalers aren't built again on every poll until the spec changes
+type permanentBuildError struct {
+	generation int64
+	err        error
 }
 
 // NewScaleHandler creates a ScaleHandler object
@@ -69,7 +92,9 @@ func NewScaleHandler(client client.Client, scaleClient scale.ScalesGetter, recon
 		globalHTTPTimeout: globalHTTPTimeout,
 		recorder:          recorder,
 		scalerCaches:      map[string]*cache.ScalersCache{},
+		permanentErrors:   map[string]permanentBuildError{},
 		lock:              &sync.RWMutex{},
+		scaleLoops:        &sync.WaitGroup{},
 	}
 }
 
@@ -99,14 +124,32 @@ func (h *scaleHandler) HandleScalableObject(ctx context.Context, scalableObject
 	scalingMutex := &sync.Mutex{}
 
 	// passing deep copy of ScaledObject/ScaledJob to the scaleLoop go routines, it's a precaution to not have global objects shared between threads
+	var pushScalersObject, scaleLoopObject, watchedObject interface{}
 	switch obj := scalableObject.(type) {
 	case *kedav1alpha1.ScaledObject:
-		go h.startPushScalers(ctx, withTriggers, obj.DeepCopy(), scalingMutex)
-		go h.startScaleLoop(ctx, withTriggers, obj.DeepCopy(), scalingMutex)
+		pushScalersObject, scaleLoopObject, watchedObject = obj.DeepCopy(), obj.DeepCopy(), obj.DeepCopy()
 	case *kedav1alpha1.ScaledJob:
-		go h.startPushScalers(ctx, withTriggers, obj.DeepCopy(), scalingMutex)
-		go h.startScaleLoop(ctx, withTriggers, obj.DeepCopy(), scalingMutex)
+		pushScalersObject, scaleLoopObject, watchedObject = obj.DeepCopy(), obj.DeepCopy(), obj.DeepCopy()
+	default:
+		return nil
 	}
+	h.lock.Lock()
+	if h.stopped {
+		h.lock.Unlock()
+		cancel()
+		return nil
+	}
+	h.scaleLoops.Add(2)
+	h.lock.Unlock()
+	watch := h.watchScaleLoop(key, withTriggers, watchedObject)
+	go func() {
+		defer h.scaleLoops.Done()
+		h.startPushScalers(ctx, withTriggers, pushScalersObject, scalingMutex)
+	}()
+	go func() {
+		defer h.scaleLoops.Done()
+		h.startScaleLoop(ctx, withTriggers, scaleLoopObject, scalingMutex, watch)
+	}()
 	return nil
 }
 
@@ -125,6 +168,10 @@ func (h *scaleHandler) DeleteScalableObject(ctx context.Context, scalableObject
 			cancel()
 		}
 		h.scaleLoopContexts.Delete(key)
+		// the loop may be stuck and not stop right away, it isn't restarted by the watchdog anymore
+		h.lock.Lock()
+		delete(h.scaleLoopWatches, key)
+		h.lock.Unlock()
 		err := h.ClearScalersCache(ctx, scalableObject)
 		if err != nil {
 			h.logger.Error(err, "error clearing scalers cache")
@@ -138,15 +185,18 @@ func (h *scaleHandler) DeleteScalableObject(ctx context.Context, scalableObject
 }
 
 // startScaleLoop blocks forever and checks the scaledObject based on its pollingInterval
-func (h *scaleHandler) startScaleLoop(ctx context.Context, withTriggers *kedav1alpha1.WithTriggers, scalableObject interface{}, scalingMutex sync.Locker) {
+func (h *scaleHandler) startScaleLoop(ctx context.Context, withTriggers *kedav1alpha1.WithTriggers, scalableObject interface{}, scalingMutex sync.Locker, watch *scaleLoopWatch) {
 	logger := h.logger.WithValues("type", withTriggers.Kind, "namespace", withTriggers.Namespace, "name", withTriggers.Name)
 
 	pollingInterval := withTriggers.GetPollingInterval()
 	logger.V(1).Info("Watching with pollingInterval", "PollingInterval", pollingInterval)
 
+	defer h.unwatchScaleLoop(withTriggers.GenerateIdenitifier(), watch)
+
 	for {
 		tmr := time.NewTimer(pollingInterval)
 		h.checkScalers(ctx, scalableObject, scalingMutex)
+		watch.beat()
 
 		select {
 		case <-tmr.C:
@@ -176,6 +226,10 @@ func (h *scaleHandler) GetScalersCache(ctx context.Context, scalableObject inter
 		h.lock.RUnlock()
 		return cache, nil
 	}
+	if permanentErr, ok := h.permanentErrors[key]; ok && permanentErr.generation == withTriggers.Generation {
+		h.lock.RUnlock()
+		return nil, permanentErr.err
+	}
 	h.lock.RUnlock()
 
 	h.lock.Lock()
@@ -185,20 +239,27 @@ func (h *scaleHandler) GetScalersCache(ctx context.Context, scalableObject inter
 	} else if ok {
 		cache.Close(ctx)
 	}
+	if permanentErr, ok := h.permanentErrors[key]; ok && permanentErr.generation == withTriggers.Generation {
+		return nil, permanentErr.err
+	}
+	delete(h.permanentErrors, key)
 
 	podTemplateSpec, containerName, err := resolver.ResolveScaleTargetPodSpec(ctx, h.client, h.logger, scalableObject)
 	if err != nil {
 		return nil, err
 	}
 
-	scalers, err := h.buildScalers(ctx, withTriggers, podTemplateSpec, containerName)
+	scalerBuilders, err := h.buildScalers(ctx, withTriggers, podTemplateSpec, containerName)
 	if err != nil {
+		if scalers.IsPermanentError(err) {
+			h.permanentErrors[key] = permanentBuildError{generation: withTriggers.Generation, err: err}
+		}
 		return nil, err
 	}
 
 	h.scalerCaches[key] = &cache.ScalersCache{
 		Generation: withTriggers.Generation,
-		Scalers:    scalers,
+		Scalers:    scalerBuilders,
 		Logger:     h.logger,
 		Recorder:   h.recorder,
 	}
@@ -221,6 +282,7 @@ func (h *scaleHandler) ClearScalersCache(ctx context.Context, scalableObject int
 		cache.Close(ctx)
 		delete(h.scalerCaches, key)
 	}
+	delete(h.permanentErrors, key)
 
 	return nil
 }
@@ -234,7 +296,9 @@ func (h *scaleHandler) startPushScalers(ctx context.Context, withTriggers *kedav
 	}
 
 	for _, ps := range cache.GetPushScalers() {
+		h.scaleLoops.Add(1)
 		go func(s scalers.PushScaler) {
+			defer h.scaleLoops.Done()
 			activeCh := make(chan bool)
 			go s.Run(ctx, activeCh)
 			defer s.Close(ctx)
@@ -246,7 +310,9 @@ func (h *scaleHandler) startPushScalers(ctx context.Context, withTriggers *kedav
 					scalingMutex.Lock()
 					switch obj := scalableObject.(type) {
 					case *kedav1alpha1.ScaledObject:
-						h.scaleExecutor.RequestScale(ctx, obj, active, false)
+						scaleCtx, cancel := newScaleOperationContext(ctx)
+						h.scaleExecutor.RequestScale(scaleCtx, obj, active, false)
+						cancel()
 					case *kedav1alpha1.ScaledJob:
 						h.logger.Info("Warning: External Push Scaler does not support ScaledJob", "object", scalableObject)
 					}
@@ -276,7 +342,13 @@ func (h *scaleHandler) checkScalers(ctx context.Context, scalableObject interfac
 			return
 		}
 		isActive, isError, _ := cache.IsScaledObjectActive(ctx, obj)
-		h.scaleExecutor.RequestScale(ctx, obj, isActive, isError)
+		// the scalers failed because the scale loop was canceled, not because of their source
+		if ctx.Err() != nil {
+			return
+		}
+		scaleCtx, cancel := newScaleOperationContext(ctx)
+		defer cancel()
+		h.scaleExecutor.RequestScale(scaleCtx, obj, isActive, isError)
 	case *kedav1alpha1.ScaledJob:
 		err = h.client.Get(ctx, types.NamespacedName{Name: obj.Name, Namespace: obj.Namespace}, obj)
 		if err != nil {
@@ -284,10 +356,92 @@ func (h *scaleHandler) checkScalers(ctx context.Context, scalableObject interfac
 			return
 		}
 		isActive, scaleTo, maxScale := cache.IsScaledJobActive(ctx, obj)
-		h.scaleExecutor.RequestJobScale(ctx, obj, isActive, scaleTo, maxScale)
+		if ctx.Err() != nil {
+			return
+		}
+		scaleCtx, cancel := newScaleOperationContext(ctx)
+		defer cancel()
+		h.scaleExecutor.RequestJobScale(scaleCtx, obj, isActive, scaleTo, maxScale)
 	}
 }
 
+// Shutdown cancels the scale loops and waits for the scale operations in progress to complete, so they aren't left
+// half-applied when the operator stops, then closes the connections of the scalers
+func (h *scaleHandler) Shutdown(ctx context.Context) {
+	h.lock.Lock()
+	h.stopped = true
+	h.lock.Unlock()
+
+	h.scaleLoopContexts.Range(func(key, value interface{}) bool {
+		if cancel, ok := value.(context.CancelFunc); ok {
+			cancel()
+		}
+		h.scaleLoopContexts.Delete(key)
+		return true
+	})
+
+	done := make(chan struct{})
+	go func() {
+		h.scaleLoops.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		h.logger.V(1).Info("Scale loops stopped")
+	case <-ctx.Done():
+		h.logger.Info("Timed out waiting for the scale loops to stop")
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for key, cache := range h.scalerCaches {
+		cache.Close(ctx)
+		delete(h.scalerCaches, key)
+	}
+}
+
+// NewShutdownRunnable returns a manager runnable shutting down the scale handler once the manager stops, the manager
+// waits for it up to its graceful shutdown timeout before releasing the leader lease and exiting
+func NewShutdownRunnable(h ScaleHandler, timeout time.Duration) manager.Runnable {
+	return manager.RunnableFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		h.Shutdown(shutdownCtx)
+		return nil
+	})
+}
+
+// newScaleOperationContext returns a context for a scale operation, with the values of the context of the scale loop
+// but not its cancellation: an operation started before the loop was canceled is completed
+func newScaleOperationContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(detachedContext{parent: ctx}, scaleOperationTimeout)
+}
+
+// detachedContext keeps the values of its parent but is never canceled
+type detachedContext struct {
+	parent context.Context
+}
+
+func (c detachedContext) Deadline() (time.Time, bool)       { return time.Time{}, false }
+func (c detachedContext) Done() <-chan struct{}             { return nil }
+func (c detachedContext) Err() error                        { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
+
+// triggerReadsResources returns whether the trigger reads its configuration from other resources than the spec:
+// a TriggerAuthentication, or the environment of the scale target through the *FromEnv metadata
+func triggerReadsResources(trigger kedav1alpha1.ScaleTriggers) bool {
+	if trigger.AuthenticationRef != nil {
+		return true
+	}
+	for key := range trigger.Metadata {
+		if strings.HasSuffix(key, "FromEnv") {
+			return true
+		}
+	}
+	return false
+}
+
 // buildScalers returns list of Scalers for the specified triggers
 func (h *scaleHandler) buildScalers(ctx context.Context, withTriggers *kedav1alpha1.WithTriggers, podTemplateSpec *corev1.PodTemplateSpec, containerName string) ([]cache.ScalerBuilder, error) {
 	logger := h.logger.WithValues("type", withTriggers.Kind, "namespace", withTriggers.Namespace, "name", withTriggers.Name)
@@ -295,8 +449,20 @@ func (h *scaleHandler) buildScalers(ctx context.Context, withTriggers *kedav1alp
 	resolvedEnv := make(map[string]string)
 	result := make([]cache.ScalerBuilder, 0, len(withTriggers.Spec.Triggers))
 
+	triggerNames, err := withTriggers.GetTriggerNames()
+	if err == nil {
+		err = withTriggers.ValidateMetricNameOverrides()
+	}
+	if err == nil {
+		err = withTriggers.ValidateTimeouts()
+	}
+	if err != nil {
+		h.recorder.Event(withTriggers, corev1.EventTypeWarning, eventreason.KEDAScalerFailed, err.Error())
+		return nil, scalers.NewPermanentError(err)
+	}
+
 	for i, t := range withTriggers.Spec.Triggers {
-		triggerIndex, trigger := i, t
+		triggerIndex, triggerName, trigger := i, triggerNames[i], t
 
 		factory := func() (scalers.Scaler, error) {
 			if podTemplateSpec != nil {
@@ -305,14 +471,20 @@ func (h *scaleHandler) buildScalers(ctx context.Context, withTriggers *kedav1alp
 					return nil, fmt.Errorf("error resolving secrets for ScaleTarget: %s", err)
 				}
 			}
+			globalHTTPTimeout := h.globalHTTPTimeout
+			if trigger.Timeout != nil {
+				globalHTTPTimeout = trigger.Timeout.Duration
+			}
 			config := &scalers.ScalerConfig{
 				Name:              withTriggers.Name,
 				Namespace:         withTriggers.Namespace,
 				TriggerMetadata:   trigger.Metadata,
 				ResolvedEnv:       resolvedEnv,
 				AuthParams:        make(map[string]string),
-				GlobalHTTPTimeout: h.globalHTTPTimeout,
+				GlobalHTTPTimeout: globalHTTPTimeout,
 				ScalerIndex:       triggerIndex,
+				TriggerName:       triggerName,
+				TriggerType:       trigger.Type,
 				MetricType:        trigger.MetricType,
 			}
 
@@ -321,13 +493,19 @@ func (h *scaleHandler) buildScalers(ctx context.Context, withTriggers *kedav1alp
 				return nil, err
 			}
 
-			return buildScaler(ctx, h.client, trigger.Type, config)
+			scaler, err := buildScaler(ctx, h.client, trigger.Type, config)
+			if triggerReadsResources(trigger) {
+				// the configuration error may come from the TriggerAuthentication or the environment of the scale
+				// target, which aren't watched, so the scaler is built again on the next poll
+				err = scalers.AsRetryableError(err)
+			}
+			return scaler, err
 		}
 
 		scaler, err := factory()
 		if err != nil {
-			h.recorder.Event(withTriggers, corev1.EventTypeWarning, eventreason.KEDAScalerFailed, err.Error())
-			h.logger.Error(err, "error resolving auth params", "scalerIndex", triggerIndex, "object", withTriggers)
+			h.recorder.Event(withTriggers, corev1.EventTypeWarning, eventreason.KEDAScalerFailed, fmt.Sprintf("trigger %s: %s", triggerName, err))
+			h.logger.Error(err, "error resolving auth params", "scalerIndex", triggerIndex, "triggerName", triggerName, "object", withTriggers)
 			if scaler != nil {
 				scaler.Close(ctx)
 			}
@@ -337,22 +515,47 @@ func (h *scaleHandler) buildScalers(ctx context.Context, withTriggers *kedav1alp
 			return nil, err
 		}
 
+		var metricsCacheTTL time.Duration
+		if trigger.UseCachedMetrics {
+			metricsCacheTTL = withTriggers.GetPollingInterval()
+		}
 		result = append(result, cache.ScalerBuilder{
-			Scaler:  scaler,
-			Factory: factory,
+			Scaler:             scaler,
+			Factory:            factory,
+			TriggerName:        triggerName,
+			MetricNamePrefix:   trigger.Name,
+			MetricNameOverride: trigger.MetricNameOverride,
+			ScalerIndex:        triggerIndex,
+			MetricsCacheTTL:    metricsCacheTTL,
 		})
 	}
 
 	return result, nil
 }
 
+// BuildScaler creates the scaler of a trigger type from a config already resolved,
+// the authentication and the environment of the scale target aren't resolved
+func BuildScaler(ctx context.Context, client client.Client, triggerType string, config *scalers.ScalerConfig) (scalers.Scaler, error) {
+	return buildScaler(ctx, client, triggerType, config)
+}
+
 func buildScaler(ctx context.Context, client client.Client, triggerType string, config *scalers.ScalerConfig) (scalers.Scaler, error) {
 	// TRIGGERS-START
 	switch triggerType {
 	case "activemq":
 		return scalers.NewActiveMQScaler(config)
+	case "airflow":
+		return scalers.NewAirflowScaler(ctx, config)
 	case "artemis-queue":
 		return scalers.NewArtemisQueueScaler(config)
+	case "asynq":
+		return scalers.NewAsynqScaler(ctx, false, false, config)
+	case "asynq-cluster":
+		return scalers.NewAsynqScaler(ctx, true, false, config)
+	case "asynq-sentinel":
+		return scalers.NewAsynqScaler(ctx, false, true, config)
+	case "aws-amazonmq":
+		return scalers.NewAwsAmazonMQScaler(config)
 	case "aws-cloudwatch":
 		return scalers.NewAwsCloudwatchScaler(config)
 	case "aws-dynamodb":
@@ -381,16 +584,46 @@ func buildScaler(ctx context.Context, client client.Client, triggerType string,
 		return scalers.NewAzureQueueScaler(config)
 	case "azure-servicebus":
 		return scalers.NewAzureServiceBusScaler(ctx, config)
+	case "azure-signalr":
+		return scalers.NewAzureSignalRScaler(config)
+	case "azure-table":
+		return scalers.NewAzureTableScaler(config)
+	case "beanstalkd":
+		return scalers.NewBeanstalkdScaler(config)
+	case "buildkite":
+		return scalers.NewBuildkiteScaler(config)
+	case "bullmq":
+		return scalers.NewBullMQScaler(ctx, false, false, config)
+	case "bullmq-cluster":
+		return scalers.NewBullMQScaler(ctx, true, false, config)
+	case "bullmq-sentinel":
+		return scalers.NewBullMQScaler(ctx, false, true, config)
 	case "cassandra":
 		return scalers.NewCassandraScaler(config)
+	case "celery":
+		return scalers.NewCeleryScaler(ctx, config)
+	case "circleci":
+		return scalers.NewCircleCIScaler(config)
+	case "cockroachdb":
+		return scalers.NewCockroachDBScaler(config)
+	case "consul":
+		return scalers.NewConsulScaler(config)
+	case "couchbase":
+		return scalers.NewCouchbaseScaler(config)
 	case "cpu":
 		return scalers.NewCPUMemoryScaler(corev1.ResourceCPU, config)
 	case "cron":
 		return scalers.NewCronScaler(config)
 	case "datadog":
 		return scalers.NewDatadogScaler(ctx, config)
+	case "debezium":
+		return scalers.NewDebeziumScaler(config)
 	case "elasticsearch":
 		return scalers.NewElasticsearchScaler(config)
+	case "envoy":
+		return scalers.NewEnvoyScaler(config)
+	case "etcd":
+		return scalers.NewEtcdScaler(config)
 	case "external":
 		return scalers.NewExternalScaler(config)
 	// TODO: use other way for test.
@@ -398,50 +631,96 @@ func buildScaler(ctx context.Context, client client.Client, triggerType string,
 		return scalers.NewExternalMockScaler(config)
 	case "external-push":
 		return scalers.NewExternalPushScaler(config)
+	case "flink":
+		return scalers.NewFlinkScaler(config)
 	case "gcp-pubsub":
 		return scalers.NewPubSubScaler(config)
 	case "gcp-stackdriver":
 		return scalers.NewStackdriverScaler(ctx, config)
 	case "gcp-storage":
 		return scalers.NewGcsScaler(config)
+	case "gearman":
+		return scalers.NewGearmanScaler(config)
+	case "github-runner":
+		return scalers.NewGitHubRunnerScaler(config)
+	case "gitlab-runner":
+		return scalers.NewGitLabRunnerScaler(config)
 	case "graphite":
 		return scalers.NewGraphiteScaler(config)
+	case "graphql":
+		return scalers.NewGraphQLScaler(config)
+	case "haproxy":
+		return scalers.NewHaproxyScaler(config)
+	case "harbor":
+		return scalers.NewHarborScaler(config)
+	case "hdfs":
+		return scalers.NewHdfsScaler(config)
 	case "huawei-cloudeye":
 		return scalers.NewHuaweiCloudeyeScaler(config)
 	case "ibmmq":
 		return scalers.NewIBMMQScaler(config)
+	case "imap":
+		return scalers.NewImapScaler(config)
 	case "influxdb":
 		return scalers.NewInfluxDBScaler(config)
+	case "jenkins":
+		return scalers.NewJenkinsScaler(config)
 	case "kafka":
-		return scalers.NewKafkaScaler(config)
+		return scalers.NewKafkaScaler(ctx, config)
+	case "kafka-connect":
+		return scalers.NewKafkaConnectScaler(config)
+	case "kubernetes-object-count":
+		return scalers.NewKubernetesObjectCountScaler(client, config)
 	case "kubernetes-workload":
 		return scalers.NewKubernetesWorkloadScaler(client, config)
 	case "liiklus":
 		return scalers.NewLiiklusScaler(config)
+	case "memcached":
+		return scalers.NewMemcachedScaler(config)
 	case "memory":
 		return scalers.NewCPUMemoryScaler(corev1.ResourceMemory, config)
 	case "metrics-api":
 		return scalers.NewMetricsAPIScaler(config)
 	case "mongodb":
 		return scalers.NewMongoDBScaler(ctx, config)
+	case "mqtt":
+		return scalers.NewMQTTScaler(config)
 	case "mssql":
 		return scalers.NewMSSQLScaler(config)
 	case "mysql":
 		return scalers.NewMySQLScaler(config)
+	case "mysql-replica-lag":
+		return scalers.NewMySQLReplicaLagScaler(config)
+	case "nats-kv":
+		return scalers.NewNATSKVScaler(config)
+	case "neo4j":
+		return scalers.NewNeo4jScaler(config)
 	case "new-relic":
 		return scalers.NewNewRelicScaler(config)
+	case "nginx":
+		return scalers.NewNginxScaler(config)
 	case "openstack-metric":
 		return scalers.NewOpenstackMetricScaler(ctx, config)
 	case "openstack-swift":
 		return scalers.NewOpenstackSwiftScaler(ctx, config)
+	case "oracle":
+		return scalers.NewOracleScaler(config)
+	case "otlp":
+		return scalers.NewOTLPScaler(config)
 	case "postgresql":
 		return scalers.NewPostgreSQLScaler(config)
+	case "postgresql-replication-slot":
+		return scalers.NewPostgreSQLReplicationSlotScaler(config)
 	case "predictkube":
 		return scalers.NewPredictKubeScaler(ctx, config)
 	case "prometheus":
-		return scalers.NewPrometheusScaler(config)
+		return scalers.NewPrometheusScaler(ctx, config)
+	case "prow":
+		return scalers.NewProwScaler(client, config)
 	case "rabbitmq":
 		return scalers.NewRabbitMQScaler(config)
+	case "rabbitmq-stream":
+		return scalers.NewRabbitMQStreamScaler(config)
 	case "redis":
 		return scalers.NewRedisScaler(ctx, false, false, config)
 	case "redis-cluster":
@@ -454,14 +733,38 @@ func buildScaler(ctx context.Context, client client.Client, triggerType string,
 		return scalers.NewRedisStreamsScaler(ctx, false, true, config)
 	case "redis-streams":
 		return scalers.NewRedisStreamsScaler(ctx, false, false, config)
+	case "s3-bucket":
+		return scalers.NewS3BucketScaler(config)
+	case "sap-hana":
+		return scalers.NewSapHanaScaler(config)
 	case "selenium-grid":
 		return scalers.NewSeleniumGridScaler(config)
+	case "sftp":
+		return scalers.NewSftpScaler(config)
+	case "sidekiq":
+		return scalers.NewSidekiqScaler(ctx, false, false, config)
+	case "sidekiq-cluster":
+		return scalers.NewSidekiqScaler(ctx, true, false, config)
+	case "sidekiq-sentinel":
+		return scalers.NewSidekiqScaler(ctx, false, true, config)
 	case "solace-event-queue":
 		return scalers.NewSolaceScaler(config)
+	case "spark-streaming":
+		return scalers.NewSparkStreamingScaler(config)
+	case "sql-job-queue":
+		return scalers.NewSQLJobQueueScaler(ctx, config)
 	case "stan":
 		return scalers.NewStanScaler(config)
+	case "statsd":
+		return scalers.NewStatsDScaler(config)
+	case "tekton":
+		return scalers.NewTektonScaler(client, config)
+	case "webhook":
+		return scalers.NewWebhookScaler(config)
+	case "zookeeper":
+		return scalers.NewZookeeperScaler(config)
 	default:
-		return nil, fmt.Errorf("no scaler found for type: %s", triggerType)
+		return nil, scalers.NewPermanentError(fmt.Errorf("no scaler found for type: %s", triggerType))
 	}
 	// TRIGGERS-END
 }
@@ -19,14 +19,20 @@ package scaling
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/api/autoscaling/v2beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
@@ -144,3 +150,176 @@ func createMetricSpec(averageValue int64) v2beta2.MetricSpec {
 		},
 	}
 }
+
+func TestGetScalersCachePermanentErrorNotRetriedUntilSpecChanges(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	handler := &scaleHandler{
+		logger:          logf.Log.WithName("scalehandler"),
+		recorder:        recorder,
+		scalerCaches:    map[string]*cache.ScalersCache{},
+		permanentErrors: map[string]permanentBuildError{},
+		lock:            &sync.RWMutex{},
+	}
+
+	scaledJob := &kedav1alpha1.ScaledJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test",
+			Namespace:  "test",
+			Generation: 1,
+		},
+		Spec: kedav1alpha1.ScaledJobSpec{
+			JobTargetRef: &kedav1alpha1.JobTargetRef{
+				JobSpec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "test"}},
+						},
+					},
+				},
+			},
+			Triggers: []kedav1alpha1.ScaleTriggers{{Type: "unknown"}},
+		},
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := handler.GetScalersCache(context.TODO(), scaledJob)
+		assert.True(t, scalers.IsPermanentError(err))
+	}
+	assert.Equal(t, 1, len(recorder.Events))
+
+	// a spec change builds the scalers again
+	scaledJob.Generation = 2
+	_, err := handler.GetScalersCache(context.TODO(), scaledJob)
+	assert.True(t, scalers.IsPermanentError(err))
+	assert.Equal(t, 2, len(recorder.Events))
+
+	// the error is forgotten once the object is removed
+	assert.Nil(t, handler.ClearScalersCache(context.TODO(), scaledJob))
+	_, err = handler.GetScalersCache(context.TODO(), scaledJob)
+	assert.True(t, scalers.IsPermanentError(err))
+	assert.Equal(t, 3, len(recorder.Events))
+}
+
+func TestGetScalersCacheConfigurationErrorFromResourcesRetried(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	handler := &scaleHandler{
+		logger:          logf.Log.WithName("scalehandler"),
+		recorder:        recorder,
+		scalerCaches:    map[string]*cache.ScalersCache{},
+		permanentErrors: map[string]permanentBuildError{},
+		lock:            &sync.RWMutex{},
+	}
+
+	// the invalid value may come from the environment of the job, which can change without the spec changing
+	scaledJob := &kedav1alpha1.ScaledJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test",
+			Namespace:  "test",
+			Generation: 1,
+		},
+		Spec: kedav1alpha1.ScaledJobSpec{
+			JobTargetRef: &kedav1alpha1.JobTargetRef{
+				JobSpec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "test"}},
+						},
+					},
+				},
+			},
+			Triggers: []kedav1alpha1.ScaleTriggers{{Type: "cron", Metadata: map[string]string{"timezoneFromEnv": "TZ"}}},
+		},
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := handler.GetScalersCache(context.TODO(), scaledJob)
+		assert.Error(t, err)
+		assert.False(t, scalers.IsPermanentError(err))
+	}
+	assert.Equal(t, 3, len(recorder.Events))
+}
+
+func TestTriggerReadsResources(t *testing.T) {
+	assert.False(t, triggerReadsResources(kedav1alpha1.ScaleTriggers{Type: "cron", Metadata: map[string]string{"timezone": "UTC"}}))
+	assert.True(t, triggerReadsResources(kedav1alpha1.ScaleTriggers{Type: "redis", Metadata: map[string]string{"addressFromEnv": "REDIS_ADDRESS"}}))
+	assert.True(t, triggerReadsResources(kedav1alpha1.ScaleTriggers{Type: "redis", AuthenticationRef: &kedav1alpha1.ScaledObjectAuthRef{Name: "redis-auth"}}))
+}
+
+// blockingScaleExecutor blocks the scale requests until released, recording the error of their context
+type blockingScaleExecutor struct {
+	started chan struct{}
+	release chan struct{}
+	ctxErr  error
+}
+
+func (e *blockingScaleExecutor) RequestScale(ctx context.Context, _ *kedav1alpha1.ScaledObject, _ bool, _ bool) {
+	close(e.started)
+	<-e.release
+	e.ctxErr = ctx.Err()
+}
+
+func (e *blockingScaleExecutor) RequestJobScale(context.Context, *kedav1alpha1.ScaledJob, bool, int64, int64) {
+}
+
+func TestShutdownWaitsForInFlightScaleOperations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	scaler := mock_scalers.NewMockScaler(ctrl)
+	scaler.EXPECT().IsActive(gomock.Any()).Return(false, nil).AnyTimes()
+	scaler.EXPECT().Close(gomock.Any()).Times(1)
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec: kedav1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: "test"},
+		},
+	}
+	scheme := runtime.NewScheme()
+	assert.NoError(t, kedav1alpha1.AddToScheme(scheme))
+	executor := &blockingScaleExecutor{started: make(chan struct{}), release: make(chan struct{})}
+	recorder := record.NewFakeRecorder(10)
+	handler := &scaleHandler{
+		client:            fake.NewClientBuilder().WithScheme(scheme).WithObjects(scaledObject.DeepCopy()).Build(),
+		logger:            logf.Log.WithName("scalehandler"),
+		scaleLoopContexts: &sync.Map{},
+		scaleExecutor:     executor,
+		recorder:          recorder,
+		scalerCaches:      map[string]*cache.ScalersCache{},
+		permanentErrors:   map[string]permanentBuildError{},
+		lock:              &sync.RWMutex{},
+		scaleLoops:        &sync.WaitGroup{},
+	}
+	withTriggers, err := asDuckWithTriggers(scaledObject)
+	assert.NoError(t, err)
+	handler.scalerCaches[withTriggers.GenerateIdenitifier()] = &cache.ScalersCache{
+		Scalers:  []cache.ScalerBuilder{{Scaler: scaler}},
+		Logger:   logf.Log.WithName("scalehandler"),
+		Recorder: recorder,
+	}
+
+	assert.NoError(t, handler.HandleScalableObject(context.Background(), scaledObject))
+	<-executor.started
+
+	stopped := make(chan struct{})
+	go func() {
+		handler.Shutdown(context.Background())
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		t.Fatal("Shutdown returned with a scale operation in progress")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(executor.release)
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown didn't return once the scale operation completed")
+	}
+	// the scale operation isn't canceled with its scale loop
+	assert.NoError(t, executor.ctxErr)
+	assert.Empty(t, handler.scalerCaches)
+
+	// no scale loop is started once shut down
+	assert.NoError(t, handler.HandleScalableObject(context.Background(), scaledObject))
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaling
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/kedacore/keda/v2/pkg/eventreason"
+	prommetrics "github.com/kedacore/keda/v2/pkg/metrics"
+)
+
+const (
+	// scaleLoopWatchdogInterval is the interval the watchdog checks the progress of the scale loops at
+	scaleLoopWatchdogInterval = 10 * time.Second
+	// minScaleLoopStallTimeout keeps the loops with a short pollingInterval from being restarted while a scale
+	// operation, bounded by scaleOperationTimeout, is in progress
+	minScaleLoopStallTimeout = 2 * scaleOperationTimeout
+)
+
+// scaleLoopWatch is the progress of a scale loop, the loop beats once per iteration. scalableObject is the object
+// the loop was started with, it's not updated by the loop and restarts it with the same key.
+type scaleLoopWatch struct {
+	withTriggers    *kedav1alpha1.WithTriggers
+	scalableObject  interface{}
+	pollingInterval time.Duration
+	// lastBeat is the unix time in nanoseconds of the last iteration of the loop
+	lastBeat int64
+}
+
+func (w *scaleLoopWatch) beat() {
+	atomic.StoreInt64(&w.lastBeat, time.Now().UnixNano())
+}
+
+// stalledFor returns how long the loop has been stuck, 0 if it isn't
+func (w *scaleLoopWatch) stalledFor(now time.Time, stallFactor int) time.Duration {
+	timeout := time.Duration(stallFactor) * w.pollingInterval
+	if timeout < minScaleLoopStallTimeout {
+		timeout = minScaleLoopStallTimeout
+	}
+	if since := now.Sub(time.Unix(0, atomic.LoadInt64(&w.lastBeat))); since > timeout {
+		return since
+	}
+	return 0
+}
+
+// watchScaleLoop registers a scale loop to the watchdog, replacing the watch of a previous loop of the same object
+func (h *scaleHandler) watchScaleLoop(key string, withTriggers *kedav1alpha1.WithTriggers, scalableObject interface{}) *scaleLoopWatch {
+	watch := &scaleLoopWatch{
+		withTriggers:    withTriggers,
+		scalableObject:  scalableObject,
+		pollingInterval: withTriggers.GetPollingInterval(),
+	}
+	watch.beat()
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.scaleLoopWatches == nil {
+		h.scaleLoopWatches = map[string]*scaleLoopWatch{}
+	}
+	h.scaleLoopWatches[key] = watch
+	return watch
+}
+
+// unwatchScaleLoop removes the watch of a stopped scale loop, unless it was replaced by a newer loop
+func (h *scaleHandler) unwatchScaleLoop(key string, watch *scaleLoopWatch) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.scaleLoopWatches[key] == watch {
+		delete(h.scaleLoopWatches, key)
+	}
+}
+
+// WatchScaleLoops restarts the scale loops which didn't complete an iteration within stallFactor times their
+// pollingInterval, e.g. with a scaler hung on a connection, until the context is done
+func (h *scaleHandler) WatchScaleLoops(ctx context.Context, stallFactor int) {
+	ticker := time.NewTicker(scaleLoopWatchdogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.restartStalledScaleLoops(ctx, time.Now(), stallFactor)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// restartStalledScaleLoops restarts the stalled scale loops with new scalers and returns their number
+func (h *scaleHandler) restartStalledScaleLoops(ctx context.Context, now time.Time, stallFactor int) int {
+	h.lock.RLock()
+	stalled := map[*scaleLoopWatch]time.Duration{}
+	for _, watch := range h.scaleLoopWatches {
+		if stalledFor := watch.stalledFor(now, stallFactor); stalledFor > 0 {
+			stalled[watch] = stalledFor
+		}
+	}
+	h.lock.RUnlock()
+
+	for watch, stalledFor := range stalled {
+		withTriggers := watch.withTriggers
+		h.logger.Info("Scale loop stalled, restarting it", "type", withTriggers.Kind, "namespace", withTriggers.Namespace,
+			"name", withTriggers.Name, "stalledFor", stalledFor.Round(time.Second).String())
+		prommetrics.RecordScaleLoopStalled(withTriggers.Kind, withTriggers.Namespace, withTriggers.Name)
+		h.recorder.Event(withTriggers, corev1.EventTypeWarning, eventreason.KEDAScaleLoopStalled,
+			fmt.Sprintf("Scale loop didn't complete for %s, restarting it", stalledFor.Round(time.Second)))
+
+		// closing the scalers releases the connections the stuck loop may be waiting on
+		if err := h.ClearScalersCache(ctx, watch.scalableObject); err != nil {
+			h.logger.Error(err, "error clearing scalers cache")
+		}
+		if err := h.HandleScalableObject(ctx, watch.scalableObject); err != nil {
+			h.logger.Error(err, "error restarting the scale loop")
+		}
+		// the new loop replaces the watch, until then the loop isn't restarted again
+		watch.beat()
+	}
+	return len(stalled)
+}
+
+// NewScaleLoopWatchdogRunnable returns a manager runnable restarting the stalled scale loops of the handler
+func NewScaleLoopWatchdogRunnable(h ScaleHandler, stallFactor int) manager.Runnable {
+	return manager.RunnableFunc(func(ctx context.Context) error {
+		h.WatchScaleLoops(ctx, stallFactor)
+		return nil
+	})
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaling
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	mock_scalers "github.com/kedacore/keda/v2/pkg/mock/mock_scaler"
+	"github.com/kedacore/keda/v2/pkg/scaling/cache"
+)
+
+func TestScaleLoopWatchStalledFor(t *testing.T) {
+	now := time.Now()
+	watch := &scaleLoopWatch{pollingInterval: time.Minute}
+	watch.beat()
+
+	assert.Zero(t, watch.stalledFor(now.Add(4*time.Minute), 5))
+	assert.True(t, watch.stalledFor(now.Add(6*time.Minute), 5) > 5*time.Minute)
+
+	// the loops polling often aren't restarted during a scale operation
+	watch.pollingInterval = time.Second
+	assert.Zero(t, watch.stalledFor(now.Add(30*time.Second), 5))
+	assert.True(t, watch.stalledFor(now.Add(2*time.Minute), 5) > 0)
+}
+
+func TestRestartStalledScaleLoops(t *testing.T) {
+	// the scaler hangs until its connection is closed
+	released := make(chan struct{})
+	checking := make(chan struct{}, 1)
+	ctrl := gomock.NewController(t)
+	scaler := mock_scalers.NewMockScaler(ctrl)
+	scaler.EXPECT().IsActive(gomock.Any()).DoAndReturn(func(context.Context) (bool, error) {
+		checking <- struct{}{}
+		<-released
+		return false, nil
+	}).Times(1)
+	scaler.EXPECT().Close(gomock.Any()).Do(func(context.Context) { close(released) }).Times(1)
+
+	scaledObject := &kedav1alpha1.ScaledObject{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+		Spec: kedav1alpha1.ScaledObjectSpec{
+			ScaleTargetRef: &kedav1alpha1.ScaleTarget{Name: "test"},
+		},
+		Status: kedav1alpha1.ScaledObjectStatus{
+			ScaleTargetGVKR: &kedav1alpha1.GroupVersionKindResource{Group: "apps", Version: "v1", Kind: "Deployment", Resource: "deployments"},
+		},
+	}
+	scheme := runtime.NewScheme()
+	assert.NoError(t, kedav1alpha1.AddToScheme(scheme))
+	recorder := record.NewFakeRecorder(10)
+	handler := &scaleHandler{
+		client:            fake.NewClientBuilder().WithScheme(scheme).WithObjects(scaledObject.DeepCopy()).Build(),
+		logger:            logf.Log.WithName("scalehandler"),
+		scaleLoopContexts: &sync.Map{},
+		scaleExecutor:     &blockingScaleExecutor{started: make(chan struct{}), release: make(chan struct{})},
+		recorder:          recorder,
+		scalerCaches:      map[string]*cache.ScalersCache{},
+		permanentErrors:   map[string]permanentBuildError{},
+		lock:              &sync.RWMutex{},
+		scaleLoops:        &sync.WaitGroup{},
+	}
+	withTriggers, err := asDuckWithTriggers(scaledObject)
+	assert.NoError(t, err)
+	key := withTriggers.GenerateIdenitifier()
+	handler.scalerCaches[key] = &cache.ScalersCache{
+		Scalers:  []cache.ScalerBuilder{{Scaler: scaler}},
+		Logger:   logf.Log.WithName("scalehandler"),
+		Recorder: recorder,
+	}
+	defer handler.Shutdown(context.Background())
+
+	assert.NoError(t, handler.HandleScalableObject(context.Background(), scaledObject))
+	<-checking
+	<-recorder.Events
+
+	// the loop is within its pollingInterval
+	assert.Equal(t, 0, handler.restartStalledScaleLoops(context.Background(), time.Now(), 5))
+
+	handler.lock.RLock()
+	stalledWatch := handler.scaleLoopWatches[key]
+	handler.lock.RUnlock()
+	assert.NotNil(t, stalledWatch)
+
+	assert.Equal(t, 1, handler.restartStalledScaleLoops(context.Background(), time.Now().Add(time.Hour), 5))
+	assert.Contains(t, <-recorder.Events, "KEDAScaleLoopStalled")
+
+	// the restarted loop replaces the watch of the stalled one
+	assert.Eventually(t, func() bool {
+		handler.lock.RLock()
+		defer handler.lock.RUnlock()
+		watch, ok := handler.scaleLoopWatches[key]
+		return ok && watch != stalledWatch
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// a deleted object isn't restarted anymore
+	assert.NoError(t, handler.DeleteScalableObject(context.Background(), scaledObject))
+	handler.lock.RLock()
+	assert.Empty(t, handler.scaleLoopWatches)
+	handler.lock.RUnlock()
+}
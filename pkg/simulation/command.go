@@ -0,0 +1,223 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/api/autoscaling/v2beta2"
+	"sigs.k8s.io/yaml"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+	"github.com/kedacore/keda/v2/pkg/scalers"
+	"github.com/kedacore/keda/v2/pkg/scaling"
+)
+
+// CommandName is the name of the operator subcommand running a simulation
+const CommandName = "simulate"
+
+// targetFlags collects the repeated --target <trigger>=<value> flags
+type targetFlags map[string]float64
+
+func (t targetFlags) String() string {
+	return fmt.Sprint(map[string]float64(t))
+}
+
+func (t targetFlags) Set(s string) error {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("expected <trigger>=<value>, got %s", s)
+	}
+	value, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return err
+	}
+	t[parts[0]] = value
+	return nil
+}
+
+// Run runs the simulate subcommand: it replays the metric values of a CSV or of a Prometheus range query
+// against a ScaledObject manifest and writes the replica timeline to out
+func Run(ctx context.Context, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(CommandName, flag.ContinueOnError)
+	scaledObjectFile := fs.String("scaledobject", "", "The ScaledObject manifest, in YAML or JSON.")
+	metricsFile := fs.String("metrics", "", "The metric values of the triggers: a CSV, or the JSON response of a Prometheus range query if the file ends with .json.")
+	initialReplicas := fs.Int("initial-replicas", 0, "The replicas of the scale target at the start of the simulation.")
+	step := fs.Duration("step", HPASyncPeriod, "The interval between two syncs of the HPA.")
+	output := fs.String("output", "csv", "The format of the timeline: csv or json.")
+	targets := targetFlags{}
+	fs.Var(targets, "target", "The target of the metric of a trigger, as <trigger name or index>=<value>. Overrides the target read from the trigger metadata, can be repeated.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *scaledObjectFile == "" || *metricsFile == "" {
+		return errors.New("--scaledobject and --metrics are required")
+	}
+
+	scaledObject, err := readScaledObject(*scaledObjectFile)
+	if err != nil {
+		return err
+	}
+	withTriggers := kedav1alpha1.WithTriggers{
+		ObjectMeta: scaledObject.ObjectMeta,
+		Spec:       kedav1alpha1.WithTriggersSpec{Triggers: scaledObject.Spec.Triggers},
+	}
+	triggerNames, err := withTriggers.GetTriggerNames()
+	if err != nil {
+		return err
+	}
+
+	triggerTargets, err := getTargets(ctx, scaledObject, triggerNames, targets)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(*metricsFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var samples []Sample
+	if strings.EqualFold(filepath.Ext(*metricsFile), ".json") {
+		samples, err = ParsePrometheusRange(f, triggerNames)
+	} else {
+		samples, err = ParseCSV(f, triggerNames)
+	}
+	if err != nil {
+		return fmt.Errorf("error reading %s: %s", *metricsFile, err)
+	}
+
+	steps := Simulate(scaledObject, triggerTargets, samples, int32(*initialReplicas), *step)
+	switch *output {
+	case "json":
+		return json.NewEncoder(out).Encode(steps)
+	case "csv":
+		return writeCSV(out, triggerNames, steps)
+	default:
+		return fmt.Errorf("output must be csv or json, got %s", *output)
+	}
+}
+
+func readScaledObject(path string) (*kedav1alpha1.ScaledObject, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	scaledObject := &kedav1alpha1.ScaledObject{}
+	if err := yaml.Unmarshal(data, scaledObject); err != nil {
+		return nil, fmt.Errorf("error reading the ScaledObject %s: %s", path, err)
+	}
+	if len(scaledObject.Spec.Triggers) == 0 {
+		return nil, fmt.Errorf("the ScaledObject %s has no trigger", path)
+	}
+	return scaledObject, nil
+}
+
+// getTargets returns the target of the metric of each trigger, the targets which aren't overridden are read from
+// the metric spec of the scaler. The scalers are created without their authentication, some connect to their source.
+func getTargets(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject, triggerNames []string, overrides targetFlags) ([]Target, error) {
+	for name := range overrides {
+		if _, err := triggerIndex(name, triggerNames); err != nil {
+			return nil, err
+		}
+	}
+
+	targets := make([]Target, len(triggerNames))
+	for i, trigger := range scaledObject.Spec.Triggers {
+		targetType := v2beta2.AverageValueMetricType
+		if trigger.MetricType != "" {
+			targetType = trigger.MetricType
+		}
+		if value, ok := overrides[triggerNames[i]]; ok {
+			targets[i] = Target{Type: targetType, Value: value}
+			continue
+		}
+		if value, ok := overrides[strconv.Itoa(i)]; ok {
+			targets[i] = Target{Type: targetType, Value: value}
+			continue
+		}
+
+		target, err := getScalerTarget(ctx, scaledObject, trigger, i, triggerNames[i])
+		if err != nil {
+			return nil, fmt.Errorf("error getting the target of trigger %s, set it with --target %s=<value>: %s", triggerNames[i], triggerNames[i], err)
+		}
+		targets[i] = target
+	}
+	return targets, nil
+}
+
+func getScalerTarget(ctx context.Context, scaledObject *kedav1alpha1.ScaledObject, trigger kedav1alpha1.ScaleTriggers, index int, triggerName string) (Target, error) {
+	config := &scalers.ScalerConfig{
+		Name:              scaledObject.Name,
+		Namespace:         scaledObject.Namespace,
+		TriggerMetadata:   trigger.Metadata,
+		ResolvedEnv:       map[string]string{},
+		AuthParams:        map[string]string{},
+		GlobalHTTPTimeout: 3 * time.Second,
+		ScalerIndex:       index,
+		TriggerName:       triggerName,
+		TriggerType:       trigger.Type,
+		MetricType:        trigger.MetricType,
+	}
+	scaler, err := scaling.BuildScaler(ctx, nil, trigger.Type, config)
+	if err != nil {
+		return Target{}, err
+	}
+	defer scaler.Close(ctx)
+
+	for _, spec := range scaler.GetMetricSpecForScaling(ctx) {
+		if spec.External == nil {
+			continue
+		}
+		switch {
+		case spec.External.Target.AverageValue != nil:
+			return Target{Type: v2beta2.AverageValueMetricType, Value: spec.External.Target.AverageValue.AsApproximateFloat64()}, nil
+		case spec.External.Target.Value != nil:
+			return Target{Type: v2beta2.ValueMetricType, Value: spec.External.Target.Value.AsApproximateFloat64()}, nil
+		}
+	}
+	return Target{}, fmt.Errorf("the %s scaler has no external metric", trigger.Type)
+}
+
+func writeCSV(out io.Writer, triggerNames []string, steps []Step) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(append([]string{"timestamp", "replicas", "active"}, triggerNames...)); err != nil {
+		return err
+	}
+	for _, step := range steps {
+		record := []string{step.Time.Format(time.RFC3339), strconv.Itoa(int(step.Replicas)), strconv.FormatBool(step.IsActive)}
+		for _, value := range step.Values {
+			record = append(record, strconv.FormatFloat(value, 'f', -1, 64))
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"math"
+	"time"
+
+	"k8s.io/api/autoscaling/v2beta2"
+)
+
+const (
+	// hpaTolerance is the default --horizontal-pod-autoscaler-tolerance of the kube-controller-manager
+	hpaTolerance = 0.1
+
+	defaultScaleDownStabilizationWindow = 300 * time.Second
+)
+
+// hpa replays the replica computation of the HorizontalPodAutoscaler controller for external metrics,
+// see pkg/controller/podautoscaler/horizontal.go of Kubernetes
+type hpa struct {
+	minReplicas int32
+	maxReplicas int32
+	scaleUp     *v2beta2.HPAScalingRules
+	scaleDown   *v2beta2.HPAScalingRules
+
+	recommendations []timestampedRecommendation
+	scaleUpEvents   []timestampedScaleEvent
+	scaleDownEvents []timestampedScaleEvent
+}
+
+type timestampedRecommendation struct {
+	replicas int32
+	time     time.Time
+}
+
+type timestampedScaleEvent struct {
+	replicaChange int32
+	time          time.Time
+}
+
+// metricValue is the value and the target of a metric of a trigger
+type metricValue struct {
+	value  float64
+	target Target
+}
+
+// newHPA creates the hpa with the behavior of the HPA generated for the ScaledObject, the Kubernetes defaults fill the missing rules
+func newHPA(minReplicas, maxReplicas int32, behavior *v2beta2.HorizontalPodAutoscalerBehavior) *hpa {
+	h := &hpa{
+		minReplicas: minReplicas,
+		maxReplicas: maxReplicas,
+		scaleUp:     defaultScaleUpRules(),
+		scaleDown:   defaultScaleDownRules(),
+	}
+	if behavior != nil {
+		h.scaleUp = mergeScalingRules(behavior.ScaleUp, h.scaleUp)
+		h.scaleDown = mergeScalingRules(behavior.ScaleDown, h.scaleDown)
+	}
+	return h
+}
+
+func defaultScaleUpRules() *v2beta2.HPAScalingRules {
+	window := int32(0)
+	selectPolicy := v2beta2.MaxPolicySelect
+	return &v2beta2.HPAScalingRules{
+		StabilizationWindowSeconds: &window,
+		SelectPolicy:               &selectPolicy,
+		Policies: []v2beta2.HPAScalingPolicy{
+			{Type: v2beta2.PodsScalingPolicy, Value: 4, PeriodSeconds: 15},
+			{Type: v2beta2.PercentScalingPolicy, Value: 100, PeriodSeconds: 15},
+		},
+	}
+}
+
+func defaultScaleDownRules() *v2beta2.HPAScalingRules {
+	window := int32(defaultScaleDownStabilizationWindow.Seconds())
+	selectPolicy := v2beta2.MaxPolicySelect
+	return &v2beta2.HPAScalingRules{
+		StabilizationWindowSeconds: &window,
+		SelectPolicy:               &selectPolicy,
+		Policies: []v2beta2.HPAScalingPolicy{
+			{Type: v2beta2.PercentScalingPolicy, Value: 100, PeriodSeconds: 15},
+		},
+	}
+}
+
+func mergeScalingRules(rules *v2beta2.HPAScalingRules, defaults *v2beta2.HPAScalingRules) *v2beta2.HPAScalingRules {
+	if rules == nil {
+		return defaults
+	}
+	merged := rules.DeepCopy()
+	if merged.StabilizationWindowSeconds == nil {
+		merged.StabilizationWindowSeconds = defaults.StabilizationWindowSeconds
+	}
+	if merged.SelectPolicy == nil {
+		merged.SelectPolicy = defaults.SelectPolicy
+	}
+	if len(merged.Policies) == 0 {
+		merged.Policies = defaults.Policies
+	}
+	return merged
+}
+
+// desiredReplicas returns the replicas the HPA sets at now for the metrics, the HPA doesn't scale a target with 0 replicas
+func (h *hpa) desiredReplicas(now time.Time, currentReplicas int32, metrics []metricValue) int32 {
+	if currentReplicas == 0 {
+		return 0
+	}
+	if currentReplicas < h.minReplicas {
+		return h.minReplicas
+	}
+	if currentReplicas > h.maxReplicas {
+		return h.maxReplicas
+	}
+
+	proposed := int32(0)
+	for _, metric := range metrics {
+		if replicas := metricDesiredReplicas(currentReplicas, metric); replicas > proposed {
+			proposed = replicas
+		}
+	}
+
+	stabilized := h.stabilizeRecommendation(now, currentReplicas, proposed)
+	desired := h.limitScaleRate(now, currentReplicas, stabilized)
+	if desired > currentReplicas {
+		h.scaleUpEvents = append(h.scaleUpEvents, timestampedScaleEvent{replicaChange: desired - currentReplicas, time: now})
+	} else if desired < currentReplicas {
+		h.scaleDownEvents = append(h.scaleDownEvents, timestampedScaleEvent{replicaChange: currentReplicas - desired, time: now})
+	}
+	return desired
+}
+
+// metricDesiredReplicas is the replica count the metric asks for, the current count if the usage is within the tolerance
+func metricDesiredReplicas(currentReplicas int32, metric metricValue) int32 {
+	if metric.target.Value <= 0 {
+		return currentReplicas
+	}
+	if metric.target.Type == v2beta2.ValueMetricType {
+		usageRatio := metric.value / metric.target.Value
+		if math.Abs(1.0-usageRatio) <= hpaTolerance {
+			return currentReplicas
+		}
+		return int32(math.Ceil(usageRatio * float64(currentReplicas)))
+	}
+	usageRatio := metric.value / (metric.target.Value * float64(currentReplicas))
+	if math.Abs(1.0-usageRatio) <= hpaTolerance {
+		return currentReplicas
+	}
+	return int32(math.Ceil(metric.value / metric.target.Value))
+}
+
+// stabilizeRecommendation keeps the highest recommendation of the scale down window and the lowest of the scale up window
+func (h *hpa) stabilizeRecommendation(now time.Time, currentReplicas int32, proposed int32) int32 {
+	upRecommendation, downRecommendation := proposed, proposed
+	upCutoff := now.Add(-time.Duration(*h.scaleUp.StabilizationWindowSeconds) * time.Second)
+	downCutoff := now.Add(-time.Duration(*h.scaleDown.StabilizationWindowSeconds) * time.Second)
+
+	recommendations := h.recommendations[:0]
+	for _, rec := range h.recommendations {
+		if rec.time.After(upCutoff) && rec.replicas < upRecommendation {
+			upRecommendation = rec.replicas
+		}
+		if rec.time.After(downCutoff) && rec.replicas > downRecommendation {
+			downRecommendation = rec.replicas
+		}
+		if rec.time.After(upCutoff) || rec.time.After(downCutoff) {
+			recommendations = append(recommendations, rec)
+		}
+	}
+	h.recommendations = append(recommendations, timestampedRecommendation{replicas: proposed, time: now})
+
+	recommendation := currentReplicas
+	if recommendation < upRecommendation {
+		recommendation = upRecommendation
+	}
+	if recommendation > downRecommendation {
+		recommendation = downRecommendation
+	}
+	return recommendation
+}
+
+// limitScaleRate applies the scaling policies and the min and max replica counts
+func (h *hpa) limitScaleRate(now time.Time, currentReplicas int32, desired int32) int32 {
+	switch {
+	case desired > currentReplicas:
+		limit := scaleUpLimit(now, currentReplicas, h.scaleUpEvents, h.scaleUp)
+		if limit < currentReplicas {
+			limit = currentReplicas
+		}
+		maximum := h.maxReplicas
+		if maximum > limit {
+			maximum = limit
+		}
+		if desired > maximum {
+			return maximum
+		}
+	case desired < currentReplicas:
+		limit := scaleDownLimit(now, currentReplicas, h.scaleDownEvents, h.scaleDown)
+		if limit > currentReplicas {
+			limit = currentReplicas
+		}
+		minimum := h.minReplicas
+		if minimum < limit {
+			minimum = limit
+		}
+		if desired < minimum {
+			return minimum
+		}
+	}
+	if desired < h.minReplicas {
+		return h.minReplicas
+	}
+	if desired > h.maxReplicas {
+		return h.maxReplicas
+	}
+	return desired
+}
+
+func scaleUpLimit(now time.Time, currentReplicas int32, events []timestampedScaleEvent, rules *v2beta2.HPAScalingRules) int32 {
+	if *rules.SelectPolicy == v2beta2.DisabledPolicySelect {
+		return currentReplicas
+	}
+	result := int32(math.MinInt32)
+	if *rules.SelectPolicy == v2beta2.MinPolicySelect {
+		result = math.MaxInt32
+	}
+	for _, policy := range rules.Policies {
+		periodStartReplicas := currentReplicas - replicaChangeInPeriod(now, events, policy.PeriodSeconds)
+		var proposed int32
+		if policy.Type == v2beta2.PodsScalingPolicy {
+			proposed = periodStartReplicas + policy.Value
+		} else {
+			proposed = int32(math.Ceil(float64(periodStartReplicas) * (1 + float64(policy.Value)/100)))
+		}
+		result = selectLimit(*rules.SelectPolicy == v2beta2.MinPolicySelect, result, proposed)
+	}
+	return result
+}
+
+func scaleDownLimit(now time.Time, currentReplicas int32, events []timestampedScaleEvent, rules *v2beta2.HPAScalingRules) int32 {
+	if *rules.SelectPolicy == v2beta2.DisabledPolicySelect {
+		return currentReplicas
+	}
+	// the policy allowing the largest change has the lowest limit
+	result := int32(math.MaxInt32)
+	if *rules.SelectPolicy == v2beta2.MinPolicySelect {
+		result = math.MinInt32
+	}
+	for _, policy := range rules.Policies {
+		periodStartReplicas := currentReplicas + replicaChangeInPeriod(now, events, policy.PeriodSeconds)
+		var proposed int32
+		if policy.Type == v2beta2.PodsScalingPolicy {
+			proposed = periodStartReplicas - policy.Value
+		} else {
+			proposed = int32(float64(periodStartReplicas) * (1 - float64(policy.Value)/100))
+		}
+		result = selectLimit(*rules.SelectPolicy != v2beta2.MinPolicySelect, result, proposed)
+	}
+	return result
+}
+
+// selectLimit returns the lowest of the limits if lowest is true, the highest otherwise
+func selectLimit(lowest bool, a int32, b int32) int32 {
+	if (b < a) == lowest {
+		return b
+	}
+	return a
+}
+
+func replicaChangeInPeriod(now time.Time, events []timestampedScaleEvent, periodSeconds int32) int32 {
+	cutoff := now.Add(-time.Duration(periodSeconds) * time.Second)
+	var change int32
+	for _, event := range events {
+		if event.time.After(cutoff) {
+			change += event.replicaChange
+		}
+	}
+	return change
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// point is a value of the metric of a trigger
+type point struct {
+	time  time.Time
+	value float64
+}
+
+// prometheusRangeResponse is the response of the /api/v1/query_range API of Prometheus
+type prometheusRangeResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][]interface{}   `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// ParseCSV reads the samples from a CSV with a header: "timestamp" then a column per trigger, named after the trigger
+// or its index. The timestamps are RFC 3339 or Unix seconds, an empty cell repeats the previous value of the trigger.
+func ParseCSV(r io.Reader, triggerNames []string) ([]Sample, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		return nil, errors.New("the CSV must have a header and at least one row")
+	}
+
+	columns := make([]int, len(records[0])-1)
+	for i, name := range records[0][1:] {
+		trigger, err := triggerIndex(strings.TrimSpace(name), triggerNames)
+		if err != nil {
+			return nil, err
+		}
+		columns[i] = trigger
+	}
+
+	series := make([][]point, len(triggerNames))
+	for line, record := range records[1:] {
+		t, err := parseTimestamp(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line+2, err)
+		}
+		for i, cell := range record[1:] {
+			if strings.TrimSpace(cell) == "" {
+				continue
+			}
+			value, err := strconv.ParseFloat(strings.TrimSpace(cell), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", line+2, err)
+			}
+			series[columns[i]] = append(series[columns[i]], point{time: t, value: value})
+		}
+	}
+	return mergeSeries(series, triggerNames)
+}
+
+// ParsePrometheusRange reads the samples from the response of a Prometheus range query, a series is assigned to the
+// trigger named by its "trigger" label, the series without this label are assigned to the triggers in order
+func ParsePrometheusRange(r io.Reader, triggerNames []string) ([]Sample, error) {
+	var response prometheusRangeResponse
+	if err := json.NewDecoder(r).Decode(&response); err != nil {
+		return nil, err
+	}
+	if response.Status != "success" || response.Data.ResultType != "matrix" {
+		return nil, fmt.Errorf("expected a successful range query with a matrix result, got status %s and result type %s", response.Status, response.Data.ResultType)
+	}
+
+	series := make([][]point, len(triggerNames))
+	next := 0
+	for _, result := range response.Data.Result {
+		trigger := next
+		if name, ok := result.Metric["trigger"]; ok {
+			var err error
+			if trigger, err = triggerIndex(name, triggerNames); err != nil {
+				return nil, err
+			}
+		} else {
+			next++
+		}
+		if trigger >= len(triggerNames) {
+			return nil, fmt.Errorf("the range query returned more series than the %d triggers", len(triggerNames))
+		}
+
+		for _, value := range result.Values {
+			if len(value) != 2 {
+				return nil, fmt.Errorf("invalid value %v", value)
+			}
+			timestamp, ok := value[0].(float64)
+			if !ok {
+				return nil, fmt.Errorf("invalid timestamp %v", value[0])
+			}
+			s, ok := value[1].(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid value %v", value[1])
+			}
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, err
+			}
+			series[trigger] = append(series[trigger], point{time: unixTime(timestamp), value: v})
+		}
+	}
+	return mergeSeries(series, triggerNames)
+}
+
+// mergeSeries creates a sample at each timestamp of the series, holding the last value of the other triggers
+func mergeSeries(series [][]point, triggerNames []string) ([]Sample, error) {
+	var times []time.Time
+	seen := map[time.Time]bool{}
+	for i, s := range series {
+		if len(s) == 0 {
+			return nil, fmt.Errorf("no value given for trigger %s", triggerNames[i])
+		}
+		sort.SliceStable(s, func(a, b int) bool { return s[a].time.Before(s[b].time) })
+		for _, p := range s {
+			if !seen[p.time] {
+				seen[p.time] = true
+				times = append(times, p.time)
+			}
+		}
+	}
+	sort.Slice(times, func(a, b int) bool { return times[a].Before(times[b]) })
+
+	samples := make([]Sample, 0, len(times))
+	next := make([]int, len(series))
+	last := make([]float64, len(series))
+	for _, t := range times {
+		for i, s := range series {
+			for next[i] < len(s) && !s[next[i]].time.After(t) {
+				last[i] = s[next[i]].value
+				next[i]++
+			}
+		}
+		samples = append(samples, Sample{Time: t, Values: append([]float64{}, last...)})
+	}
+	return samples, nil
+}
+
+// triggerIndex returns the index of the trigger with this name, or this index
+func triggerIndex(name string, triggerNames []string) (int, error) {
+	for i, triggerName := range triggerNames {
+		if triggerName == name {
+			return i, nil
+		}
+	}
+	if i, err := strconv.Atoi(name); err == nil && i >= 0 && i < len(triggerNames) {
+		return i, nil
+	}
+	return -1, fmt.Errorf("no trigger named %s, the triggers are %s", name, strings.Join(triggerNames, ", "))
+}
+
+func parseTimestamp(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %s, expected RFC 3339 or Unix seconds", s)
+	}
+	return unixTime(seconds), nil
+}
+
+func unixTime(seconds float64) time.Time {
+	whole, fraction := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(fraction*float64(time.Second))).UTC()
+}
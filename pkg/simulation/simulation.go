@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"time"
+
+	"k8s.io/api/autoscaling/v2beta2"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+const (
+	// HPASyncPeriod is the default --horizontal-pod-autoscaler-sync-period of the kube-controller-manager
+	HPASyncPeriod = 15 * time.Second
+
+	defaultCooldownPeriod  = 5 * 60
+	defaultMaxReplicaCount = 100
+)
+
+// Target is the target of the metric of a trigger
+type Target struct {
+	Type  v2beta2.MetricTargetType
+	Value float64
+}
+
+// Sample is the value of the metric of each trigger at a time, in the order of the triggers
+type Sample struct {
+	Time   time.Time
+	Values []float64
+}
+
+// Step is the state of the scale target after a sync of the HPA
+type Step struct {
+	Time     time.Time `json:"time"`
+	Values   []float64 `json:"values"`
+	IsActive bool      `json:"isActive"`
+	Replicas int32     `json:"replicas"`
+}
+
+// Simulate replays the samples against the ScaledObject: KEDA activates and deactivates the scale target on every
+// polling interval and the HPA computes the replicas on every step. The value of a sample holds until the next one.
+func Simulate(scaledObject *kedav1alpha1.ScaledObject, targets []Target, samples []Sample, initialReplicas int32, step time.Duration) []Step {
+	if len(samples) == 0 {
+		return nil
+	}
+	if step <= 0 {
+		step = HPASyncPeriod
+	}
+
+	withTriggers := kedav1alpha1.WithTriggers{Spec: kedav1alpha1.WithTriggersSpec{PollingInterval: scaledObject.Spec.PollingInterval}}
+	pollingInterval := withTriggers.GetPollingInterval()
+	cooldownPeriod := time.Duration(defaultCooldownPeriod) * time.Second
+	if scaledObject.Spec.CooldownPeriod != nil {
+		cooldownPeriod = time.Duration(*scaledObject.Spec.CooldownPeriod) * time.Second
+	}
+	minReplicas := int32(0)
+	if scaledObject.Spec.MinReplicaCount != nil {
+		minReplicas = *scaledObject.Spec.MinReplicaCount
+	}
+	maxReplicas := int32(defaultMaxReplicaCount)
+	if scaledObject.Spec.MaxReplicaCount != nil {
+		maxReplicas = *scaledObject.Spec.MaxReplicaCount
+	}
+	hpaMinReplicas := minReplicas
+	if hpaMinReplicas < 1 {
+		hpaMinReplicas = 1
+	}
+	var behavior *v2beta2.HorizontalPodAutoscalerBehavior
+	if scaledObject.Spec.Advanced != nil && scaledObject.Spec.Advanced.HorizontalPodAutoscalerConfig != nil {
+		behavior = scaledObject.Spec.Advanced.HorizontalPodAutoscalerConfig.Behavior
+	}
+	h := newHPA(hpaMinReplicas, maxReplicas, behavior)
+
+	replicas := initialReplicas
+	var lastActiveTime *time.Time
+	var lastPoll time.Time
+	sampleIndex := 0
+	end := samples[len(samples)-1].Time
+
+	var steps []Step
+	for now := samples[0].Time; !now.After(end); now = now.Add(step) {
+		for sampleIndex+1 < len(samples) && !samples[sampleIndex+1].Time.After(now) {
+			sampleIndex++
+		}
+		values := samples[sampleIndex].Values
+
+		isActive := false
+		for _, value := range values {
+			isActive = isActive || value > 0
+		}
+		if isActive {
+			activeTime := now
+			lastActiveTime = &activeTime
+		}
+
+		// the scale loop of KEDA
+		if now.Equal(samples[0].Time) || now.Sub(lastPoll) >= pollingInterval {
+			lastPoll = now
+			replicas = requestScale(scaledObject, replicas, minReplicas, isActive, lastActiveTime, cooldownPeriod, now)
+		}
+
+		// the HPA reads the metrics of all the triggers
+		metrics := make([]metricValue, 0, len(values))
+		for i, value := range values {
+			metrics = append(metrics, metricValue{value: value, target: targets[i]})
+		}
+		replicas = h.desiredReplicas(now, replicas, metrics)
+
+		steps = append(steps, Step{Time: now, Values: values, IsActive: isActive, Replicas: replicas})
+	}
+	return steps
+}
+
+// requestScale returns the replicas set by the scale executor of KEDA, see RequestScale in pkg/scaling/executor
+func requestScale(scaledObject *kedav1alpha1.ScaledObject, currentReplicas int32, minReplicas int32, isActive bool, lastActiveTime *time.Time, cooldownPeriod time.Duration, now time.Time) int32 {
+	idleReplicas := scaledObject.Spec.IdleReplicaCount
+	if isActive {
+		if (idleReplicas != nil && currentReplicas < minReplicas) || currentReplicas == 0 {
+			if minReplicas > 0 {
+				return minReplicas
+			}
+			return 1
+		}
+		return currentReplicas
+	}
+
+	switch {
+	case (idleReplicas != nil && currentReplicas > *idleReplicas) || (currentReplicas > 0 && minReplicas == 0):
+		if lastActiveTime == nil || lastActiveTime.Add(cooldownPeriod).Before(now) {
+			if idleReplicas != nil {
+				return *idleReplicas
+			}
+			return minReplicas
+		}
+	case currentReplicas < minReplicas && idleReplicas == nil:
+		return minReplicas
+	}
+	return currentReplicas
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/autoscaling/v2beta2"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
+)
+
+var simulationStart = time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+
+func replicasOf(steps []Step) []int32 {
+	replicas := make([]int32, 0, len(steps))
+	for _, step := range steps {
+		replicas = append(replicas, step.Replicas)
+	}
+	return replicas
+}
+
+func TestMetricDesiredReplicas(t *testing.T) {
+	averageValue := Target{Type: v2beta2.AverageValueMetricType, Value: 10}
+	value := Target{Type: v2beta2.ValueMetricType, Value: 10}
+
+	assert.Equal(t, int32(5), metricDesiredReplicas(2, metricValue{value: 45, target: averageValue}))
+	// within the tolerance
+	assert.Equal(t, int32(4), metricDesiredReplicas(4, metricValue{value: 42, target: averageValue}))
+	assert.Equal(t, int32(4), metricDesiredReplicas(2, metricValue{value: 20, target: value}))
+	assert.Equal(t, int32(2), metricDesiredReplicas(2, metricValue{value: 10.5, target: value}))
+}
+
+func TestHPAScaleUpPolicies(t *testing.T) {
+	h := newHPA(1, 100, nil)
+	target := Target{Type: v2beta2.AverageValueMetricType, Value: 1}
+
+	// the default policies allow 4 pods or doubling every 15 seconds
+	assert.Equal(t, int32(5), h.desiredReplicas(simulationStart, 1, []metricValue{{value: 50, target: target}}))
+	assert.Equal(t, int32(10), h.desiredReplicas(simulationStart.Add(15*time.Second), 5, []metricValue{{value: 50, target: target}}))
+	assert.Equal(t, int32(20), h.desiredReplicas(simulationStart.Add(30*time.Second), 10, []metricValue{{value: 50, target: target}}))
+}
+
+func TestHPAScaleDownStabilization(t *testing.T) {
+	window := int32(60)
+	h := newHPA(1, 100, &v2beta2.HorizontalPodAutoscalerBehavior{
+		ScaleDown: &v2beta2.HPAScalingRules{StabilizationWindowSeconds: &window},
+	})
+	target := Target{Type: v2beta2.AverageValueMetricType, Value: 1}
+
+	assert.Equal(t, int32(8), h.desiredReplicas(simulationStart, 8, []metricValue{{value: 8, target: target}}))
+	// the recommendation of 8 replicas holds for the window
+	assert.Equal(t, int32(8), h.desiredReplicas(simulationStart.Add(30*time.Second), 8, []metricValue{{value: 2, target: target}}))
+	assert.Equal(t, int32(2), h.desiredReplicas(simulationStart.Add(75*time.Second), 8, []metricValue{{value: 2, target: target}}))
+}
+
+func TestSimulateScaleFromAndToZero(t *testing.T) {
+	cooldownPeriod := int32(60)
+	pollingInterval := int32(15)
+	maxReplicaCount := int32(10)
+	scaledObject := &kedav1alpha1.ScaledObject{
+		Spec: kedav1alpha1.ScaledObjectSpec{
+			PollingInterval: &pollingInterval,
+			CooldownPeriod:  &cooldownPeriod,
+			MaxReplicaCount: &maxReplicaCount,
+			Triggers:        []kedav1alpha1.ScaleTriggers{{Type: "prometheus"}},
+		},
+	}
+	targets := []Target{{Type: v2beta2.AverageValueMetricType, Value: 10}}
+	samples := []Sample{
+		{Time: simulationStart, Values: []float64{0}},
+		{Time: simulationStart.Add(15 * time.Second), Values: []float64{30}},
+		{Time: simulationStart.Add(45 * time.Second), Values: []float64{0}},
+		{Time: simulationStart.Add(8 * time.Minute), Values: []float64{0}},
+	}
+
+	steps := Simulate(scaledObject, targets, samples, 0, 0)
+	replicas := replicasOf(steps)
+	assert.Equal(t, int32(0), replicas[0])
+	// activated by KEDA then scaled by the HPA
+	assert.Equal(t, int32(3), replicas[1])
+	assert.True(t, steps[1].IsActive)
+	// the HPA keeps the replicas for its scale down stabilization window, then KEDA deactivates the target
+	assert.Equal(t, int32(3), replicas[4])
+	assert.Equal(t, int32(0), replicas[len(replicas)-1])
+}
+
+func TestParseCSV(t *testing.T) {
+	csv := `timestamp,queue,1
+2022-05-01T00:00:00Z,1,
+2022-05-01T00:00:30Z,,5
+1651363290,3,7
+`
+	samples, err := ParseCSV(strings.NewReader(csv), []string{"queue", "lag"})
+	assert.NoError(t, err)
+	assert.Len(t, samples, 3)
+	assert.Equal(t, []float64{1, 0}, samples[0].Values)
+	assert.Equal(t, []float64{1, 5}, samples[1].Values)
+	assert.Equal(t, simulationStart.Add(90*time.Second), samples[2].Time)
+	assert.Equal(t, []float64{3, 7}, samples[2].Values)
+
+	_, err = ParseCSV(strings.NewReader("timestamp,unknown\n0,1\n"), []string{"queue"})
+	assert.Error(t, err)
+	_, err = ParseCSV(strings.NewReader("timestamp,queue\n0,1\n"), []string{"queue", "lag"})
+	assert.Error(t, err)
+}
+
+func TestParsePrometheusRange(t *testing.T) {
+	response := `{"status":"success","data":{"resultType":"matrix","result":[
+		{"metric":{"trigger":"lag"},"values":[[1651363200,"4"]]},
+		{"metric":{},"values":[[1651363200,"1"],[1651363215.5,"2"]]}
+	]}}`
+	samples, err := ParsePrometheusRange(strings.NewReader(response), []string{"queue", "lag"})
+	assert.NoError(t, err)
+	assert.Len(t, samples, 2)
+	assert.Equal(t, []float64{1, 4}, samples[0].Values)
+	assert.Equal(t, simulationStart.Add(15500*time.Millisecond), samples[1].Time)
+	assert.Equal(t, []float64{2, 4}, samples[1].Values)
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	scaledObjectFile := filepath.Join(dir, "scaledobject.yaml")
+	metricsFile := filepath.Join(dir, "metrics.csv")
+	assert.NoError(t, os.WriteFile(scaledObjectFile, []byte(`apiVersion: keda.sh/v1alpha1
+kind: ScaledObject
+metadata:
+  name: worker
+spec:
+  scaleTargetRef:
+    name: worker
+  minReplicaCount: 1
+  triggers:
+  - type: prometheus
+    name: requests
+    metadata:
+      serverAddress: http://prometheus:9090
+      metricName: http_requests
+      query: sum(rate(http_requests_total[1m]))
+      threshold: "50"
+  - type: external
+    name: queue
+    metadata:
+      scalerAddress: external:8080
+`), 0600))
+	assert.NoError(t, os.WriteFile(metricsFile, []byte("timestamp,requests,queue\n0,100,0\n15,200,0\n"), 0600))
+
+	var out bytes.Buffer
+	err := Run(context.Background(), []string{"--scaledobject", scaledObjectFile, "--metrics", metricsFile, "--target", "queue=5", "--initial-replicas", "1"}, &out)
+	assert.NoError(t, err)
+	assert.Equal(t, `timestamp,replicas,active,requests,queue
+1970-01-01T00:00:00Z,2,true,100,0
+1970-01-01T00:00:15Z,4,true,200,0
+`, out.String())
+
+	err = Run(context.Background(), []string{"--scaledobject", scaledObjectFile, "--metrics", metricsFile, "--target", "unknown=5"}, &out)
+	assert.Error(t, err)
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statsd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kedacore/keda/v2/pkg/otlp"
+)
+
+const (
+	// maxPacketSize is the largest UDP datagram
+	maxPacketSize   = 65535
+	evictInterval   = time.Minute
+	shutdownTimeout = 10 * time.Second
+)
+
+// Listener receives StatsD gauges over UDP and serves their latest values to the statsd scaler over HTTP, on the
+// TCP port of the same number. The DogStatsD tags are the attributes of the series, the other metric types are ignored.
+type Listener struct {
+	address string
+	store   *otlp.Store
+	logger  logr.Logger
+}
+
+// Gauge is a gauge read from a StatsD packet
+type Gauge struct {
+	Name  string
+	Tags  map[string]string
+	Value float64
+	// Delta is true if the value is added to the current value of the gauge, with the +/- sign of the StatsD syntax
+	Delta bool
+}
+
+// NewListener creates the Listener listening on address
+func NewListener(address string) *Listener {
+	return &Listener{
+		address: address,
+		store:   otlp.NewStore(),
+		logger:  logf.Log.WithName("statsd_listener"),
+	}
+}
+
+// Start serves the listener until the context is done, it implements manager.Runnable
+func (l *Listener) Start(ctx context.Context) error {
+	conn, err := net.ListenPacket("udp", l.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	srv := &http.Server{
+		Addr:    l.address,
+		Handler: l.handler(),
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		l.logger.Info("Starting StatsD listener", "address", l.address)
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+	}()
+	go func() {
+		buf := make([]byte, maxPacketSize)
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				if ctx.Err() == nil {
+					errs <- err
+				}
+				return
+			}
+			l.handlePacket(buf[:n])
+		}
+	}()
+
+	ticker := time.NewTicker(evictInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-errs:
+			_ = srv.Close()
+			return err
+		case <-ticker.C:
+			l.store.Evict(time.Now().Add(-otlp.SeriesRetention))
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			return srv.Shutdown(shutdownCtx)
+		}
+	}
+}
+
+// NeedLeaderElection returns false, the gauges are sent to all the replicas of the operator
+func (l *Listener) NeedLeaderElection() bool {
+	return false
+}
+
+func (l *Listener) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(otlp.QueryPath, otlp.QueryHandler(l.store, l.logger))
+	return mux
+}
+
+// handlePacket records the gauges of a packet, a packet holds a metric per line
+func (l *Listener) handlePacket(packet []byte) {
+	now := time.Now()
+	for _, line := range strings.Split(string(packet), "\n") {
+		gauge, ok, err := ParseGauge(line)
+		if err != nil {
+			l.logger.V(1).Info("Ignoring invalid StatsD metric", "metric", line, "error", err.Error())
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		value := gauge.Value
+		if gauge.Delta {
+			if latest, ok := l.store.Latest(gauge.Name, gauge.Tags); ok {
+				value += latest.Value
+			}
+		}
+		l.store.Record(otlp.DataPoint{Name: gauge.Name, Attributes: gauge.Tags, Value: value, Time: now})
+	}
+}
+
+// ParseGauge parses a StatsD line <name>:<value>|g[|@<sample rate>][|#<tag>:<value>,...], it returns false for the
+// empty lines and the other metric types
+func ParseGauge(line string) (Gauge, bool, error) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return Gauge{}, false, nil
+	}
+
+	fields := strings.Split(line, "|")
+	if len(fields) < 2 {
+		return Gauge{}, false, fmt.Errorf("no metric type")
+	}
+	if fields[1] != "g" {
+		return Gauge{}, false, nil
+	}
+
+	sep := strings.LastIndex(fields[0], ":")
+	if sep <= 0 {
+		return Gauge{}, false, fmt.Errorf("no metric name or value")
+	}
+	gauge := Gauge{Name: fields[0][:sep], Tags: map[string]string{}}
+	rawValue := fields[0][sep+1:]
+	gauge.Delta = strings.HasPrefix(rawValue, "+") || strings.HasPrefix(rawValue, "-")
+	value, err := strconv.ParseFloat(rawValue, 64)
+	if err != nil {
+		return Gauge{}, false, fmt.Errorf("invalid value: %s", err)
+	}
+	gauge.Value = value
+
+	for _, field := range fields[2:] {
+		if !strings.HasPrefix(field, "#") {
+			// sample rate, container id and timestamp of DogStatsD don't apply to gauges
+			continue
+		}
+		for _, tag := range strings.Split(field[1:], ",") {
+			if tag == "" {
+				continue
+			}
+			kv := strings.SplitN(tag, ":", 2)
+			if len(kv) == 2 {
+				gauge.Tags[kv[0]] = kv[1]
+			} else {
+				gauge.Tags[kv[0]] = ""
+			}
+		}
+	}
+	return gauge, true, nil
+}
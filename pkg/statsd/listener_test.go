@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statsd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kedacore/keda/v2/pkg/otlp"
+)
+
+func TestParseGauge(t *testing.T) {
+	testCases := []struct {
+		line    string
+		gauge   Gauge
+		ok      bool
+		isError bool
+	}{
+		{"queue.depth:42|g", Gauge{Name: "queue.depth", Tags: map[string]string{}, Value: 42}, true, false},
+		{" queue.depth:1.5|g|@0.5 ", Gauge{Name: "queue.depth", Tags: map[string]string{}, Value: 1.5}, true, false},
+		{"queue.depth:+3|g", Gauge{Name: "queue.depth", Tags: map[string]string{}, Value: 3, Delta: true}, true, false},
+		{"queue.depth:-2|g", Gauge{Name: "queue.depth", Tags: map[string]string{}, Value: -2, Delta: true}, true, false},
+		{"queue.depth:7|g|#queue:orders,env:prod,canary", Gauge{Name: "queue.depth", Tags: map[string]string{"queue": "orders", "env": "prod", "canary": ""}, Value: 7}, true, false},
+		{"requests:1|c", Gauge{}, false, false},
+		{"latency:320|ms|#queue:orders", Gauge{}, false, false},
+		{"", Gauge{}, false, false},
+		{"queue.depth", Gauge{}, false, true},
+		{"queue.depth|g", Gauge{}, false, true},
+		{":42|g", Gauge{}, false, true},
+		{"queue.depth:abc|g", Gauge{}, false, true},
+	}
+
+	for _, testCase := range testCases {
+		gauge, ok, err := ParseGauge(testCase.line)
+		if testCase.isError {
+			assert.Error(t, err, "line %q", testCase.line)
+			continue
+		}
+		assert.NoError(t, err, "line %q", testCase.line)
+		assert.Equal(t, testCase.ok, ok, "line %q", testCase.line)
+		if ok {
+			assert.Equal(t, testCase.gauge, gauge, "line %q", testCase.line)
+		}
+	}
+}
+
+func TestListener(t *testing.T) {
+	listener := NewListener("")
+	listener.handlePacket([]byte("queue.depth:5|g|#queue:orders\nqueue.depth:8|g|#queue:payments\nrequests:1|c\ninvalid"))
+	listener.handlePacket([]byte("queue.depth:+2|g|#queue:orders\nqueue.depth:-3|g|#queue:payments"))
+	// a delta of a gauge never sent is applied to 0
+	listener.handlePacket([]byte("workers:+4|g"))
+
+	server := httptest.NewServer(listener.handler())
+	defer server.Close()
+
+	testCases := []struct {
+		query    url.Values
+		response otlp.QueryResponse
+	}{
+		{url.Values{"name": {"queue.depth"}, "attribute": {"queue=orders"}}, otlp.QueryResponse{Value: 7, Series: 1}},
+		{url.Values{"name": {"queue.depth"}, "attribute": {"queue=payments"}}, otlp.QueryResponse{Value: 5, Series: 1}},
+		{url.Values{"name": {"queue.depth"}}, otlp.QueryResponse{Value: 12, Series: 2}},
+		{url.Values{"name": {"workers"}}, otlp.QueryResponse{Value: 4, Series: 1}},
+		{url.Values{"name": {"requests"}}, otlp.QueryResponse{Value: 0, Series: 0}},
+	}
+	for _, testCase := range testCases {
+		resp, err := http.Get(fmt.Sprintf("%s%s?%s", server.URL, otlp.QueryPath, testCase.query.Encode()))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var response otlp.QueryResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		resp.Body.Close()
+		assert.Equal(t, testCase.response, response, "query %v", testCase.query)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// WriteBackoff is the jittered backoff between the attempts of a write to the API server,
+// the jitter spreads the retries of the many ScaledObjects rejected at the same time
+var WriteBackoff = wait.Backoff{
+	Steps:    5,
+	Duration: 200 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.5,
+	Cap:      5 * time.Second,
+}
+
+// IsRetriableWriteError returns true if the write was rejected without being applied and can be sent again:
+// 429 when API Priority and Fairness or max-in-flight throttle the request, a conflict with a newer
+// version of the object, or a server timeout
+func IsRetriableWriteError(err error) bool {
+	return apierrors.IsTooManyRequests(err) || apierrors.IsConflict(err) || apierrors.IsServerTimeout(err)
+}
+
+// RetryWrite calls write until it succeeds, returns an error which is not retriable, the backoff is exhausted
+// or the context is done. The delay suggested by the API server through Retry-After is honored when longer
+// than the backoff. On a conflict write must read the object again before updating it.
+func RetryWrite(ctx context.Context, write func() error) error {
+	return retryWrite(ctx, WriteBackoff, write)
+}
+
+func retryWrite(ctx context.Context, backoff wait.Backoff, write func() error) error {
+	var err error
+	for {
+		err = write()
+		if err == nil || !IsRetriableWriteError(err) || backoff.Steps <= 1 {
+			return err
+		}
+
+		delay := backoff.Step()
+		if seconds, ok := apierrors.SuggestsClientDelay(err); ok {
+			if suggested := time.Duration(seconds) * time.Second; suggested > delay {
+				delay = suggested
+			}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
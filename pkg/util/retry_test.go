@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+var testWriteBackoff = wait.Backoff{Steps: 3, Duration: time.Millisecond, Factor: 1.0, Jitter: 0.1}
+
+func TestRetryWrite(t *testing.T) {
+	gr := schema.GroupResource{Group: "autoscaling", Resource: "horizontalpodautoscalers"}
+	tests := []struct {
+		comment       string
+		errs          []error
+		expectedCalls int
+		expectedError bool
+	}{
+		{comment: "succeeds at once", errs: []error{nil}, expectedCalls: 1},
+		{comment: "retries throttled writes", errs: []error{apierrors.NewTooManyRequests("throttled", 0), nil}, expectedCalls: 2},
+		{comment: "retries conflicts", errs: []error{apierrors.NewConflict(gr, "hpa", errors.New("modified")), nil}, expectedCalls: 2},
+		{comment: "retries server timeouts", errs: []error{apierrors.NewServerTimeout(gr, "update", 0), nil}, expectedCalls: 2},
+		{comment: "gives up after the backoff steps", errs: []error{apierrors.NewTooManyRequests("throttled", 0), apierrors.NewTooManyRequests("throttled", 0), apierrors.NewTooManyRequests("throttled", 0), nil}, expectedCalls: 3, expectedError: true},
+		{comment: "doesn't retry other errors", errs: []error{apierrors.NewForbidden(gr, "hpa", errors.New("denied")), nil}, expectedCalls: 1, expectedError: true},
+	}
+
+	for _, test := range tests {
+		calls := 0
+		err := retryWrite(context.Background(), testWriteBackoff, func() error {
+			err := test.errs[calls]
+			calls++
+			return err
+		})
+		if calls != test.expectedCalls {
+			t.Errorf("%s: expected %d calls but got %d", test.comment, test.expectedCalls, calls)
+		}
+		if (err != nil) != test.expectedError {
+			t.Errorf("%s: unexpected error %v", test.comment, err)
+		}
+	}
+}
+
+func TestRetryWriteStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := retryWrite(ctx, wait.Backoff{Steps: 5, Duration: time.Hour}, func() error {
+		calls++
+		return apierrors.NewTooManyRequests("throttled", 0)
+	})
+	if calls != 1 {
+		t.Errorf("expected 1 call but got %d", calls)
+	}
+	if !apierrors.IsTooManyRequests(err) {
+		t.Errorf("expected the last error but got %v", err)
+	}
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/tidwall/gjson"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/kedacore/keda/v2/pkg/otlp"
+)
+
+const (
+	// webhookPathPrefix is followed by <namespace>/webhooks/<name>
+	webhookPathPrefix = "/api/v1/namespaces/"
+
+	// SecretPrefix prefixes the name of the Secret holding the token of a webhook, in the namespace of the webhook
+	SecretPrefix = "keda-webhook-"
+	// SecretTokenKey is the key of the token in the Secret of a webhook
+	SecretTokenKey = "token"
+
+	maxRequestSize  = 1 << 20
+	evictInterval   = time.Minute
+	shutdownTimeout = 10 * time.Second
+)
+
+// Receiver receives the values external systems POST to the webhooks and serves their latest values to the webhook
+// scaler. Both the pushes and the queries of a webhook are authenticated with the token of the keda-webhook-<name>
+// Secret of its namespace.
+type Receiver struct {
+	address  string
+	certFile string
+	keyFile  string
+	client   client.Client
+	store    *otlp.Store
+	logger   logr.Logger
+}
+
+// NewReceiver creates the Receiver serving TLS on address, the tokens can't be sent in clear text
+func NewReceiver(address, certFile, keyFile string, client client.Client) (*Receiver, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("the webhook receiver requires a TLS certificate and key file")
+	}
+	return &Receiver{
+		address:  address,
+		certFile: certFile,
+		keyFile:  keyFile,
+		client:   client,
+		store:    otlp.NewStore(),
+		logger:   logf.Log.WithName("webhook_receiver"),
+	}, nil
+}
+
+// MetricName is the name of the series of the values of a webhook
+func MetricName(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+// Start serves the receiver until the context is done, it implements manager.Runnable
+func (r *Receiver) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:    r.address,
+		Handler: r.handler(),
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		r.logger.Info("Starting webhook receiver", "address", r.address)
+		if err := srv.ListenAndServeTLS(r.certFile, r.keyFile); !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+		close(errs)
+	}()
+
+	ticker := time.NewTicker(evictInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-errs:
+			return err
+		case <-ticker.C:
+			r.store.Evict(time.Now().Add(-otlp.SeriesRetention))
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			return srv.Shutdown(shutdownCtx)
+		}
+	}
+}
+
+// NeedLeaderElection returns true, the values are kept in the memory of the replica receiving them so only the
+// leader serves the receiver, the pushes and the queries of the webhook scalers then reach the same store
+func (r *Receiver) NeedLeaderElection() bool {
+	return true
+}
+
+func (r *Receiver) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(webhookPathPrefix, r.handleWebhook)
+	mux.HandleFunc(otlp.QueryPath, r.handleQuery(otlp.QueryHandler(r.store, r.logger)))
+	return mux
+}
+
+// handleQuery only serves the values of the webhook named by the name query parameter, <namespace>/<name>, to the
+// requests holding its token, a namespace can't read the values pushed to the webhooks of the others
+func (r *Receiver) handleQuery(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		parts := strings.Split(req.URL.Query().Get("name"), "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			http.Error(w, "name must be <namespace>/<name>", http.StatusBadRequest)
+			return
+		}
+		if !r.authorize(w, req, parts[0], parts[1]) {
+			return
+		}
+		next(w, req)
+	}
+}
+
+// handleWebhook serves POST /api/v1/namespaces/<namespace>/webhooks/<name>. The body is the value, or a JSON document
+// holding it at the valueLocation query parameter, a GJSON path.
+func (r *Receiver) handleWebhook(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, webhookPathPrefix), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] != "webhooks" || parts[2] == "" {
+		http.Error(w, "path must be /api/v1/namespaces/<namespace>/webhooks/<name>", http.StatusNotFound)
+		return
+	}
+	namespace, name := parts[0], parts[2]
+	if !r.authorize(w, req, namespace, name) {
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	value, err := ParseValue(body, req.URL.Query().Get("valueLocation"))
+	if err != nil {
+		r.logger.V(1).Info("Rejecting webhook value", "namespace", namespace, "name", name, "error", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.store.Record(otlp.DataPoint{Name: MetricName(namespace, name), Attributes: map[string]string{}, Value: value, Time: time.Now()})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorize checks the token of the request against the one of the webhook, it writes the error response and returns
+// false if it doesn't match
+func (r *Receiver) authorize(w http.ResponseWriter, req *http.Request, namespace, name string) bool {
+	token := requestToken(req)
+	if token == "" {
+		w.Header().Set("WWW-Authenticate", `Basic realm="keda"`)
+		http.Error(w, "a bearer token or a basic auth password is required", http.StatusUnauthorized)
+		return false
+	}
+	authenticated, err := r.authenticate(req.Context(), namespace, name, token)
+	if err != nil {
+		r.logger.Error(err, "error reading the token of the webhook", "namespace", namespace, "name", name)
+		http.Error(w, "error reading the token of the webhook", http.StatusInternalServerError)
+		return false
+	}
+	if !authenticated {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// authenticate compares the token to the one of the Secret of the webhook, a webhook without Secret can't be pushed to
+func (r *Receiver) authenticate(ctx context.Context, namespace, name, token string) (bool, error) {
+	secret := &corev1.Secret{}
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: SecretPrefix + name}, secret)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	expected := secret.Data[SecretTokenKey]
+	if len(expected) == 0 {
+		return false, nil
+	}
+	return subtle.ConstantTimeCompare(expected, []byte(token)) == 1, nil
+}
+
+// requestToken returns the bearer token or, for the systems only supporting credentials in the URL, the basic auth
+// password of the request
+func requestToken(req *http.Request) string {
+	if _, password, ok := req.BasicAuth(); ok {
+		return password
+	}
+	authorization := req.Header.Get("Authorization")
+	if token := strings.TrimPrefix(authorization, "Bearer "); token != authorization {
+		return token
+	}
+	return ""
+}
+
+// ParseValue parses the value of a body, at the GJSON path valueLocation if given
+func ParseValue(body []byte, valueLocation string) (float64, error) {
+	if valueLocation == "" {
+		value, err := strconv.ParseFloat(strings.TrimSpace(string(body)), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value: %s", err)
+		}
+		return value, nil
+	}
+
+	if !gjson.ValidBytes(body) {
+		return 0, errors.New("invalid JSON body")
+	}
+	r := gjson.GetBytes(body, valueLocation)
+	switch r.Type {
+	case gjson.Number:
+		return r.Num, nil
+	case gjson.String:
+		value, err := strconv.ParseFloat(r.Str, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value at %s: %s", valueLocation, err)
+		}
+		return value, nil
+	default:
+		return 0, fmt.Errorf("no number at %s", valueLocation)
+	}
+}
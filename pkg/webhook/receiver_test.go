@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The KEDA Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kedacore/keda/v2/pkg/otlp"
+)
+
+func TestParseValue(t *testing.T) {
+	testCases := []struct {
+		body          string
+		valueLocation string
+		value         float64
+		isError       bool
+	}{
+		{"42", "", 42, false},
+		{" 1.5\n", "", 1.5, false},
+		{`{"backlog": 12}`, "backlog", 12, false},
+		{`{"queue": {"size": "7"}}`, "queue.size", 7, false},
+		{`{"items": [{"count": 3}]}`, "items.0.count", 3, false},
+		{"", "", 0, true},
+		{"many", "", 0, true},
+		{`{"backlog": 12}`, "", 0, true},
+		{`{"backlog": 12}`, "size", 0, true},
+		{`{"backlog": "many"}`, "backlog", 0, true},
+		{`{"backlog": `, "backlog", 0, true},
+	}
+
+	for _, testCase := range testCases {
+		value, err := ParseValue([]byte(testCase.body), testCase.valueLocation)
+		if testCase.isError {
+			assert.Error(t, err, "body %q", testCase.body)
+			continue
+		}
+		assert.NoError(t, err, "body %q", testCase.body)
+		assert.Equal(t, testCase.value, value, "body %q", testCase.body)
+	}
+}
+
+func TestReceiver(t *testing.T) {
+	secrets := []corev1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "sms", Name: SecretPrefix + "twilio"},
+			Data:       map[string][]byte{SecretTokenKey: []byte("s3cret")},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "sms", Name: SecretPrefix + "no-token"},
+			Data:       map[string][]byte{},
+		},
+	}
+	client := fake.NewClientBuilder().WithObjects(&secrets[0], &secrets[1]).Build()
+	receiver, err := NewReceiver(":9443", "tls.crt", "tls.key", client)
+	assert.NoError(t, err)
+	server := httptest.NewServer(receiver.handler())
+	defer server.Close()
+
+	push := func(path, token, body string, basicAuth bool) int {
+		req, err := http.NewRequest(http.MethodPost, server.URL+path, strings.NewReader(body))
+		assert.NoError(t, err)
+		switch {
+		case token != "" && basicAuth:
+			req.SetBasicAuth("twilio", token)
+		case token != "":
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	pushTestCases := []struct {
+		path      string
+		token     string
+		body      string
+		basicAuth bool
+		status    int
+	}{
+		{"/api/v1/namespaces/sms/webhooks/twilio", "s3cret", "5", false, http.StatusNoContent},
+		{"/api/v1/namespaces/sms/webhooks/twilio?valueLocation=queue.size", "s3cret", `{"queue": {"size": 9}}`, true, http.StatusNoContent},
+		{"/api/v1/namespaces/sms/webhooks/twilio", "", "5", false, http.StatusUnauthorized},
+		{"/api/v1/namespaces/sms/webhooks/twilio", "wrong", "5", false, http.StatusUnauthorized},
+		{"/api/v1/namespaces/sms/webhooks/no-token", "s3cret", "5", false, http.StatusUnauthorized},
+		{"/api/v1/namespaces/sms/webhooks/stripe", "s3cret", "5", false, http.StatusUnauthorized},
+		{"/api/v1/namespaces/payments/webhooks/twilio", "s3cret", "5", false, http.StatusUnauthorized},
+		{"/api/v1/namespaces/sms/webhooks/twilio", "s3cret", "many", false, http.StatusBadRequest},
+		{"/api/v1/namespaces/sms/twilio", "s3cret", "5", false, http.StatusNotFound},
+	}
+	for _, testCase := range pushTestCases {
+		assert.Equal(t, testCase.status, push(testCase.path, testCase.token, testCase.body, testCase.basicAuth), "path %s", testCase.path)
+	}
+
+	resp, err := http.Get(server.URL + "/api/v1/namespaces/sms/webhooks/twilio")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	queryTestCases := []struct {
+		name     string
+		token    string
+		status   int
+		response otlp.QueryResponse
+	}{
+		{MetricName("sms", "twilio"), "s3cret", http.StatusOK, otlp.QueryResponse{Value: 9, Series: 1}},
+		{MetricName("sms", "twilio"), "", http.StatusUnauthorized, otlp.QueryResponse{}},
+		{MetricName("sms", "twilio"), "wrong", http.StatusUnauthorized, otlp.QueryResponse{}},
+		{MetricName("sms", "stripe"), "s3cret", http.StatusUnauthorized, otlp.QueryResponse{}},
+		{"twilio", "s3cret", http.StatusBadRequest, otlp.QueryResponse{}},
+	}
+	for _, testCase := range queryTestCases {
+		query := url.Values{"name": {testCase.name}}
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%s?%s", server.URL, otlp.QueryPath, query.Encode()), nil)
+		assert.NoError(t, err)
+		if testCase.token != "" {
+			req.Header.Set("Authorization", "Bearer "+testCase.token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.status, resp.StatusCode, "name %s", testCase.name)
+		if testCase.status == http.StatusOK {
+			var response otlp.QueryResponse
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+			assert.Equal(t, testCase.response, response, "name %s", testCase.name)
+		}
+		resp.Body.Close()
+	}
+}
+
+func TestNewReceiverRequiresTLS(t *testing.T) {
+	_, err := NewReceiver(":9443", "", "", nil)
+	assert.Error(t, err)
+	_, err = NewReceiver(":9443", "tls.crt", "", nil)
+	assert.Error(t, err)
+	r, err := NewReceiver(":9443", "tls.crt", "tls.key", nil)
+	assert.NoError(t, err)
+	assert.True(t, r.NeedLeaderElection())
+}
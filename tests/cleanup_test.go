@@ -20,6 +20,10 @@ func TestRemoveKEDA(t *testing.T) {
 	t.Log("KEDA removed successfully using 'make undeploy' command")
 }
 
+func TestSweepOrphanedCloudResources(t *testing.T) {
+	SweepAwsResources(t)
+}
+
 func TestRemoveWorkloadIdentityComponents(t *testing.T) {
 	if AzureRunWorkloadIdentityTests == "" || AzureRunWorkloadIdentityTests == "false" {
 		t.Skip("skipping as workload identity tests are disabled")
@@ -0,0 +1,214 @@
+//go:build e2e
+// +build e2e
+
+package helper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+)
+
+// Tags set on every cloud resource created by the e2e tests, so the resources leaked by aborted runs can be found
+// and deleted by SweepAwsResources.
+const (
+	CloudResourceRunIDTag     = "keda-e2e-run-id"
+	CloudResourceExpiresAtTag = "keda-e2e-expires-at"
+
+	defaultCloudResourceTTL = 6 * time.Hour
+)
+
+// Env variables used to tag and sweep cloud resources.
+var (
+	// E2ERunID identifies the e2e run, run-all.sh exports it so all the test processes of a run share it.
+	E2ERunID = getE2ERunID()
+	// CloudResourceTTL is how long the resources of a run are kept before being considered orphaned.
+	CloudResourceTTL = getCloudResourceTTL()
+
+	awsAccessKeyID     = os.Getenv("AWS_ACCESS_KEY")
+	awsSecretAccessKey = os.Getenv("AWS_SECRET_KEY")
+	awsRegion          = os.Getenv("AWS_REGION")
+)
+
+func getE2ERunID() string {
+	if id := os.Getenv("E2E_RUN_ID"); id != "" {
+		return id
+	}
+	if id := os.Getenv("GITHUB_RUN_ID"); id != "" {
+		return id
+	}
+	return fmt.Sprintf("local-%d", time.Now().Unix())
+}
+
+func getCloudResourceTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("E2E_CLOUD_RESOURCE_TTL")); err == nil && ttl > 0 {
+		return ttl
+	}
+	return defaultCloudResourceTTL
+}
+
+// CloudResourceTags returns the tags to set on a cloud resource created by a test
+func CloudResourceTags() map[string]string {
+	return map[string]string{
+		CloudResourceRunIDTag:     E2ERunID,
+		CloudResourceExpiresAtTag: strconv.FormatInt(time.Now().Add(CloudResourceTTL).Unix(), 10),
+	}
+}
+
+// AwsTags returns CloudResourceTags in the format of the SQS and Kinesis APIs
+func AwsTags() map[string]*string {
+	return aws.StringMap(CloudResourceTags())
+}
+
+// AwsDynamoDBTags returns CloudResourceTags in the format of the DynamoDB API
+func AwsDynamoDBTags() []*dynamodb.Tag {
+	var tags []*dynamodb.Tag
+	for key, value := range CloudResourceTags() {
+		tags = append(tags, &dynamodb.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return tags
+}
+
+// IsOrphanedCloudResource returns true if the tagged resource was created by this run or has expired.
+// Resources without the e2e tags aren't owned by the tests and are never orphaned.
+func IsOrphanedCloudResource(tags map[string]string, now time.Time) bool {
+	runID, ok := tags[CloudResourceRunIDTag]
+	if !ok {
+		return false
+	}
+	if runID == E2ERunID {
+		return true
+	}
+	expiresAt, err := strconv.ParseInt(tags[CloudResourceExpiresAtTag], 10, 64)
+	if err != nil {
+		// tagged by a run but without a valid expiry, consider it expired
+		return true
+	}
+	return now.Unix() >= expiresAt
+}
+
+// SweepAwsResources deletes the SQS queues, DynamoDB tables and Kinesis streams leaked by this run or by
+// expired runs
+func SweepAwsResources(t *testing.T) {
+	if awsAccessKeyID == "" || awsSecretAccessKey == "" || awsRegion == "" {
+		t.Skip("skipping as AWS credentials are not set")
+	}
+
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String(awsRegion),
+		Credentials: credentials.NewStaticCredentials(awsAccessKeyID, awsSecretAccessKey, ""),
+	}))
+	ctx := context.Background()
+	now := time.Now()
+
+	sweepSqsQueues(ctx, t, sqs.New(sess), now)
+	sweepDynamoDBTables(ctx, t, dynamodb.New(sess), now)
+	sweepKinesisStreams(ctx, t, kinesis.New(sess), now)
+}
+
+func sweepSqsQueues(ctx context.Context, t *testing.T, sqsClient *sqs.SQS, now time.Time) {
+	var queueURLs []*string
+	err := sqsClient.ListQueuesPagesWithContext(ctx, &sqs.ListQueuesInput{}, func(page *sqs.ListQueuesOutput, lastPage bool) bool {
+		queueURLs = append(queueURLs, page.QueueUrls...)
+		return true
+	})
+	assert.NoErrorf(t, err, "cannot list sqs queues - %s", err)
+
+	for _, queueURL := range queueURLs {
+		tags, err := sqsClient.ListQueueTagsWithContext(ctx, &sqs.ListQueueTagsInput{QueueUrl: queueURL})
+		if err != nil {
+			t.Logf("cannot list tags of sqs queue %s - %s", *queueURL, err)
+			continue
+		}
+		if !IsOrphanedCloudResource(aws.StringValueMap(tags.Tags), now) {
+			continue
+		}
+		t.Logf("deleting orphaned sqs queue %s", *queueURL)
+		_, err = sqsClient.DeleteQueueWithContext(ctx, &sqs.DeleteQueueInput{QueueUrl: queueURL})
+		assert.NoErrorf(t, err, "cannot delete sqs queue %s - %s", *queueURL, err)
+	}
+}
+
+func sweepDynamoDBTables(ctx context.Context, t *testing.T, dynamodbClient *dynamodb.DynamoDB, now time.Time) {
+	var tableNames []*string
+	err := dynamodbClient.ListTablesPagesWithContext(ctx, &dynamodb.ListTablesInput{}, func(page *dynamodb.ListTablesOutput, lastPage bool) bool {
+		tableNames = append(tableNames, page.TableNames...)
+		return true
+	})
+	assert.NoErrorf(t, err, "cannot list dynamodb tables - %s", err)
+
+	for _, tableName := range tableNames {
+		describe, err := dynamodbClient.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{TableName: tableName})
+		if err != nil {
+			t.Logf("cannot describe dynamodb table %s - %s", *tableName, err)
+			continue
+		}
+		tags, err := listDynamoDBTableTags(ctx, dynamodbClient, describe.Table.TableArn)
+		if err != nil {
+			t.Logf("cannot list tags of dynamodb table %s - %s", *tableName, err)
+			continue
+		}
+		if !IsOrphanedCloudResource(tags, now) {
+			continue
+		}
+		t.Logf("deleting orphaned dynamodb table %s", *tableName)
+		_, err = dynamodbClient.DeleteTableWithContext(ctx, &dynamodb.DeleteTableInput{TableName: tableName})
+		assert.NoErrorf(t, err, "cannot delete dynamodb table %s - %s", *tableName, err)
+	}
+}
+
+func listDynamoDBTableTags(ctx context.Context, dynamodbClient *dynamodb.DynamoDB, tableArn *string) (map[string]string, error) {
+	tags := map[string]string{}
+	input := &dynamodb.ListTagsOfResourceInput{ResourceArn: tableArn}
+	for {
+		page, err := dynamodbClient.ListTagsOfResourceWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range page.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		if page.NextToken == nil {
+			return tags, nil
+		}
+		input.NextToken = page.NextToken
+	}
+}
+
+func sweepKinesisStreams(ctx context.Context, t *testing.T, kinesisClient *kinesis.Kinesis, now time.Time) {
+	var streamNames []*string
+	err := kinesisClient.ListStreamsPagesWithContext(ctx, &kinesis.ListStreamsInput{}, func(page *kinesis.ListStreamsOutput, lastPage bool) bool {
+		streamNames = append(streamNames, page.StreamNames...)
+		return true
+	})
+	assert.NoErrorf(t, err, "cannot list kinesis streams - %s", err)
+
+	for _, streamName := range streamNames {
+		tags, err := kinesisClient.ListTagsForStreamWithContext(ctx, &kinesis.ListTagsForStreamInput{StreamName: streamName})
+		if err != nil {
+			t.Logf("cannot list tags of kinesis stream %s - %s", *streamName, err)
+			continue
+		}
+		streamTags := map[string]string{}
+		for _, tag := range tags.Tags {
+			streamTags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		if !IsOrphanedCloudResource(streamTags, now) {
+			continue
+		}
+		t.Logf("deleting orphaned kinesis stream %s", *streamName)
+		_, err = kinesisClient.DeleteStreamWithContext(ctx, &kinesis.DeleteStreamInput{StreamName: streamName})
+		assert.NoErrorf(t, err, "cannot delete kinesis stream %s - %s", *streamName, err)
+	}
+}
@@ -0,0 +1,171 @@
+//go:build e2e
+// +build e2e
+
+package helper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ReplicaSample is the replica count of a deployment at a point of time.
+type ReplicaSample struct {
+	Time     time.Time
+	Replicas int32
+}
+
+// ReplicaTimeline records the replica count of a deployment over time, so tests can assert how it scaled and not
+// only the replica count it ended up with, which hides oscillations.
+type ReplicaTimeline struct {
+	name      string
+	namespace string
+	start     time.Time
+
+	mu      sync.Mutex
+	samples []ReplicaSample
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// RecordDeploymentReplicaTimeline starts recording the replica count of a deployment every intervalSeconds,
+// until Stop is called.
+func RecordDeploymentReplicaTimeline(t *testing.T, kc *kubernetes.Clientset, name, namespace string, intervalSeconds int) *ReplicaTimeline {
+	timeline := &ReplicaTimeline{
+		name:      name,
+		namespace: namespace,
+		start:     time.Now(),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	go func() {
+		defer close(timeline.done)
+		ticker := time.NewTicker(time.Duration(intervalSeconds) * time.Second)
+		defer ticker.Stop()
+		for {
+			deployment, err := kc.AppsV1().Deployments(namespace).Get(context.Background(), name, metav1.GetOptions{})
+			if err != nil {
+				t.Logf("cannot get deployment %s for the replica timeline - %s", name, err)
+			} else {
+				timeline.mu.Lock()
+				timeline.samples = append(timeline.samples, ReplicaSample{Time: time.Now(), Replicas: deployment.Status.Replicas})
+				timeline.mu.Unlock()
+			}
+
+			select {
+			case <-timeline.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return timeline
+}
+
+// Stop stops the recording and returns the recorded samples.
+func (tl *ReplicaTimeline) Stop() []ReplicaSample {
+	select {
+	case <-tl.stop:
+	default:
+		close(tl.stop)
+	}
+	<-tl.done
+	return tl.Samples()
+}
+
+// Samples returns the samples recorded so far.
+func (tl *ReplicaTimeline) Samples() []ReplicaSample {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+	return append([]ReplicaSample{}, tl.samples...)
+}
+
+// Transitions returns the number of times the replica count changed.
+func (tl *ReplicaTimeline) Transitions() int {
+	samples := tl.Samples()
+	transitions := 0
+	for i := 1; i < len(samples); i++ {
+		if samples[i].Replicas != samples[i-1].Replicas {
+			transitions++
+		}
+	}
+	return transitions
+}
+
+// WaitForReplicaCount waits until the recorded replica count hits target or the timeout expires, it returns false on
+// timeout.
+func (tl *ReplicaTimeline) WaitForReplicaCount(target, timeoutSeconds int) bool {
+	deadline := time.Now().Add(time.Duration(timeoutSeconds) * time.Second)
+	for time.Now().Before(deadline) {
+		samples := tl.Samples()
+		if len(samples) > 0 && samples[len(samples)-1].Replicas == int32(target) {
+			return true
+		}
+		time.Sleep(time.Second)
+	}
+	return false
+}
+
+// AssertReplicasNeverExceeded asserts the replica count never went over max.
+func (tl *ReplicaTimeline) AssertReplicasNeverExceeded(t *testing.T, max int) bool {
+	for _, sample := range tl.Samples() {
+		if sample.Replicas > int32(max) {
+			return assert.Failf(t, "replica count exceeded the maximum",
+				"deployment %s/%s had %d replicas at %s, the maximum is %d - timeline: %s",
+				tl.namespace, tl.name, sample.Replicas, sample.Time.Sub(tl.start), max, tl)
+		}
+	}
+	return true
+}
+
+// AssertReplicasReachedWithin asserts the replica count hit target within withinSeconds of the start of the
+// recording.
+func (tl *ReplicaTimeline) AssertReplicasReachedWithin(t *testing.T, target, withinSeconds int) bool {
+	within := time.Duration(withinSeconds) * time.Second
+	for _, sample := range tl.Samples() {
+		if sample.Time.Sub(tl.start) > within {
+			break
+		}
+		if sample.Replicas == int32(target) {
+			return true
+		}
+	}
+	return assert.Failf(t, "replica count not reached in time",
+		"deployment %s/%s didn't reach %d replicas within %s - timeline: %s", tl.namespace, tl.name, target, within, tl)
+}
+
+// AssertReplicaTransitionsAtMost asserts the replica count didn't change more than maxTransitions times, to catch
+// flapping.
+func (tl *ReplicaTimeline) AssertReplicaTransitionsAtMost(t *testing.T, maxTransitions int) bool {
+	if transitions := tl.Transitions(); transitions > maxTransitions {
+		return assert.Failf(t, "replica count flapped",
+			"deployment %s/%s changed its replica count %d times, at most %d expected - timeline: %s",
+			tl.namespace, tl.name, transitions, maxTransitions, tl)
+	}
+	return true
+}
+
+// String returns the replica count changes, as offsets from the start of the recording.
+func (tl *ReplicaTimeline) String() string {
+	samples := tl.Samples()
+	s := ""
+	for i, sample := range samples {
+		if i > 0 && sample.Replicas == samples[i-1].Replicas {
+			continue
+		}
+		if s != "" {
+			s += ", "
+		}
+		s += fmt.Sprintf("%s=%d", sample.Time.Sub(tl.start).Round(time.Second), sample.Replicas)
+	}
+	return "[" + s + "]"
+}
@@ -5,6 +5,9 @@ set -u
 E2E_REGEX_GO="./scalers*${E2E_TEST_REGEX:-*_test.go}"
 E2E_REGEX_TS="./scalers*${E2E_TEST_REGEX:-*.test.ts}"
 
+# Shared by all the test processes to tag the cloud resources they create, see helper/cloud_resources.go
+export E2E_RUN_ID="${E2E_RUN_ID:-${GITHUB_RUN_ID:-local-$(date +%s)}}"
+
 DIR=$(dirname "$0")
 cd $DIR
 
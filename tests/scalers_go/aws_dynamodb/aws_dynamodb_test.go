@@ -214,6 +214,7 @@ func createDynamoDBTable(t *testing.T, dynamodbClient *dynamodb.DynamoDB) {
 			ReadCapacityUnits:  aws.Int64(5),
 			WriteCapacityUnits: aws.Int64(5),
 		},
+		Tags: AwsDynamoDBTags(),
 	})
 	assert.NoErrorf(t, err, "failed to create table - %s", err)
 	done := waitForTableActiveStatus(t, dynamodbClient)
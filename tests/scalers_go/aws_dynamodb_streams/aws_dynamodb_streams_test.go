@@ -214,6 +214,7 @@ func createTable(db dynamodbiface.DynamoDBAPI) error {
 		AttributeDefinitions: attributeDefinitions,
 		BillingMode:          aws.String("PAY_PER_REQUEST"),
 		StreamSpecification:  streamSpecification,
+		Tags:                 AwsDynamoDBTags(),
 	})
 	return err
 }
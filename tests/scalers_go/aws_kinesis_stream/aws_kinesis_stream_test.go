@@ -196,6 +196,12 @@ func createKinesisStream(t *testing.T, kinesisClient *kinesis.Kinesis) {
 	if !done {
 		assert.True(t, true, "failed to create kinesis")
 	}
+	// streams can't be tagged on creation, tag it for the sweeper once active
+	_, err = kinesisClient.AddTagsToStreamWithContext(context.Background(), &kinesis.AddTagsToStreamInput{
+		StreamName: &kinesisStreamName,
+		Tags:       AwsTags(),
+	})
+	assert.NoErrorf(t, err, "failed to tag stream - %s", err)
 }
 
 func waitForStreamActiveStatus(t *testing.T, kinesisClient *kinesis.Kinesis) bool {
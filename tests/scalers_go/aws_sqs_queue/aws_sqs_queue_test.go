@@ -154,6 +154,9 @@ func TestSqsScaler(t *testing.T) {
 
 func testScaleUp(t *testing.T, kc *kubernetes.Clientset, sqsClient *sqs.SQS, queueURL *string) {
 	t.Log("--- testing scale up ---")
+	timeline := RecordDeploymentReplicaTimeline(t, kc, deploymentName, testNamespace, 1)
+	defer timeline.Stop()
+
 	for i := 0; i < 10; i++ {
 		msg := fmt.Sprintf("Message - %d", i)
 		_, err := sqsClient.SendMessageWithContext(context.Background(), &sqs.SendMessageInput{
@@ -164,8 +167,12 @@ func testScaleUp(t *testing.T, kc *kubernetes.Clientset, sqsClient *sqs.SQS, que
 		assert.NoErrorf(t, err, "cannot send message - %s", err)
 	}
 
-	assert.True(t, WaitForDeploymentReplicaCount(t, kc, deploymentName, testNamespace, maxReplicaCount, 180, 1),
+	assert.True(t, timeline.WaitForReplicaCount(maxReplicaCount, 180),
 		"replica count should be 2 after 3 minutes")
+	timeline.Stop()
+	// the replicas only go up, one step per activation and one per HPA scale up at most
+	timeline.AssertReplicasNeverExceeded(t, maxReplicaCount)
+	timeline.AssertReplicaTransitionsAtMost(t, maxReplicaCount)
 }
 
 func testScaleDown(t *testing.T, kc *kubernetes.Clientset, sqsClient *sqs.SQS, queueURL *string) {
@@ -185,7 +192,9 @@ func createSqsQueue(t *testing.T, sqsClient *sqs.SQS) *sqs.CreateQueueOutput {
 		Attributes: map[string]*string{
 			"DelaySeconds":           aws.String("60"),
 			"MessageRetentionPeriod": aws.String("86400"),
-		}})
+		},
+		Tags: AwsTags(),
+	})
 	assert.NoErrorf(t, err, "failed to create queue - %s", err)
 	return queue
 }
@@ -0,0 +1,31 @@
+//go:build e2e
+// +build e2e
+
+package scenarios_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kedacore/keda/v2/tests/scenario"
+)
+
+// Load environment variables from .env file
+var _ = godotenv.Load("../../.env")
+
+// TestScenarios runs the scenario files of tests/scenarios
+func TestScenarios(t *testing.T) {
+	files, err := filepath.Glob("../../scenarios/*.yaml")
+	assert.NoErrorf(t, err, "cannot list scenarios - %s", err)
+
+	for _, file := range files {
+		file := file
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".yaml"), func(t *testing.T) {
+			scenario.Run(t, file)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+//go:build e2e
+// +build e2e
+
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sqs"
+
+	. "github.com/kedacore/keda/v2/tests/helper"
+)
+
+// Producer publishes the messages a scaler reads. The variables returned by Setup, e.g. the URL of a created queue,
+// are added to the data of the templates as .Producers.<producer name>.<variable name>.
+type Producer interface {
+	Setup(t *testing.T) (map[string]string, error)
+	Publish(t *testing.T, messages []string) error
+	// Purge removes the messages not consumed yet
+	Purge(t *testing.T) error
+	Cleanup(t *testing.T) error
+}
+
+// ProducerFactory creates a producer from the config of the scenario, with the templates of the values rendered
+type ProducerFactory func(config map[string]string) (Producer, error)
+
+var producerFactories = map[string]ProducerFactory{
+	"aws-sqs-queue": newAwsSqsQueueProducer,
+	"kafka":         newKafkaProducer,
+}
+
+// RegisterProducer adds a type of producer the scenarios can use
+func RegisterProducer(producerType string, factory ProducerFactory) {
+	producerFactories[producerType] = factory
+}
+
+func getProducerFactory(producerType string) (ProducerFactory, error) {
+	factory, ok := producerFactories[producerType]
+	if !ok {
+		return nil, fmt.Errorf("unknown producer type %s", producerType)
+	}
+	return factory, nil
+}
+
+func requiredConfig(config map[string]string, keys ...string) error {
+	for _, key := range keys {
+		if config[key] == "" {
+			return fmt.Errorf("no %s given", key)
+		}
+	}
+	return nil
+}
+
+// awsSqsQueueProducer creates the queue on setup and deletes it on cleanup, the AWS credentials default to the
+// AWS_ACCESS_KEY, AWS_SECRET_KEY and AWS_REGION environment variables of the tests
+type awsSqsQueueProducer struct {
+	queueName string
+	client    *sqs.SQS
+	queueURL  *string
+}
+
+func newAwsSqsQueueProducer(config map[string]string) (Producer, error) {
+	setDefault(config, "awsAccessKeyID", os.Getenv("AWS_ACCESS_KEY"))
+	setDefault(config, "awsSecretAccessKey", os.Getenv("AWS_SECRET_KEY"))
+	setDefault(config, "awsRegion", os.Getenv("AWS_REGION"))
+	if err := requiredConfig(config, "queueName", "awsAccessKeyID", "awsSecretAccessKey", "awsRegion"); err != nil {
+		return nil, err
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(config["awsRegion"]),
+		Credentials: credentials.NewStaticCredentials(config["awsAccessKeyID"], config["awsSecretAccessKey"], ""),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &awsSqsQueueProducer{queueName: config["queueName"], client: sqs.New(sess)}, nil
+}
+
+func (p *awsSqsQueueProducer) Setup(t *testing.T) (map[string]string, error) {
+	queue, err := p.client.CreateQueueWithContext(context.Background(), &sqs.CreateQueueInput{
+		QueueName: aws.String(p.queueName),
+		Attributes: map[string]*string{
+			"MessageRetentionPeriod": aws.String("86400"),
+		},
+		Tags: AwsTags(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create queue - %s", err)
+	}
+	p.queueURL = queue.QueueUrl
+	return map[string]string{"QueueURL": *queue.QueueUrl}, nil
+}
+
+func (p *awsSqsQueueProducer) Publish(t *testing.T, messages []string) error {
+	for _, message := range messages {
+		if _, err := p.client.SendMessageWithContext(context.Background(), &sqs.SendMessageInput{
+			QueueUrl:    p.queueURL,
+			MessageBody: aws.String(message),
+		}); err != nil {
+			return fmt.Errorf("cannot send message - %s", err)
+		}
+	}
+	return nil
+}
+
+func (p *awsSqsQueueProducer) Purge(t *testing.T) error {
+	_, err := p.client.PurgeQueueWithContext(context.Background(), &sqs.PurgeQueueInput{QueueUrl: p.queueURL})
+	return err
+}
+
+func (p *awsSqsQueueProducer) Cleanup(t *testing.T) error {
+	if p.queueURL == nil {
+		return nil
+	}
+	_, err := p.client.DeleteQueueWithContext(context.Background(), &sqs.DeleteQueueInput{QueueUrl: p.queueURL})
+	return err
+}
+
+// kafkaProducer runs the Kafka CLI in a client pod of the scenario, as the brokers usually aren't reachable from
+// the tests. The topic is created on the first publish, purging moves the offsets of the consumer group to the end
+// and needs the consumers of the group to be stopped.
+type kafkaProducer struct {
+	pod              string
+	namespace        string
+	bootstrapServers string
+	topic            string
+	group            string
+	partitions       string
+	topicCreated     bool
+}
+
+func newKafkaProducer(config map[string]string) (Producer, error) {
+	setDefault(config, "partitions", "1")
+	if err := requiredConfig(config, "pod", "namespace", "bootstrapServers", "topic", "consumerGroup"); err != nil {
+		return nil, err
+	}
+	return &kafkaProducer{
+		pod:              config["pod"],
+		namespace:        config["namespace"],
+		bootstrapServers: config["bootstrapServers"],
+		topic:            config["topic"],
+		group:            config["consumerGroup"],
+		partitions:       config["partitions"],
+	}, nil
+}
+
+func (p *kafkaProducer) Setup(t *testing.T) (map[string]string, error) {
+	return nil, nil
+}
+
+func (p *kafkaProducer) exec(t *testing.T, command string) error {
+	_, errOut, err := ExecCommandOnSpecificPod(t, p.pod, p.namespace, command)
+	if err != nil {
+		return fmt.Errorf("%s: %s", err, errOut)
+	}
+	return nil
+}
+
+func (p *kafkaProducer) Publish(t *testing.T, messages []string) error {
+	if !p.topicCreated {
+		if err := p.exec(t, fmt.Sprintf("kafka-topics --bootstrap-server %s --create --if-not-exists --topic %s --partitions %s",
+			p.bootstrapServers, p.topic, p.partitions)); err != nil {
+			return fmt.Errorf("cannot create topic - %s", err)
+		}
+		p.topicCreated = true
+	}
+
+	escaped := make([]string, len(messages))
+	for i, message := range messages {
+		escaped[i] = strings.ReplaceAll(message, "'", `'\''`)
+	}
+	if err := p.exec(t, fmt.Sprintf("printf '%%s\\n' '%s' | kafka-console-producer --broker-list %s --topic %s",
+		strings.Join(escaped, "' '"), p.bootstrapServers, p.topic)); err != nil {
+		return fmt.Errorf("cannot send messages - %s", err)
+	}
+	return nil
+}
+
+func (p *kafkaProducer) Purge(t *testing.T) error {
+	return p.exec(t, fmt.Sprintf("kafka-consumer-groups --bootstrap-server %s --group %s --topic %s --reset-offsets --to-latest --execute",
+		p.bootstrapServers, p.group, p.topic))
+}
+
+func (p *kafkaProducer) Cleanup(t *testing.T) error {
+	// the topic is deleted with the namespace of the cluster
+	return nil
+}
+
+func setDefault(config map[string]string, key, value string) {
+	if config[key] == "" {
+		config[key] = value
+	}
+}
@@ -0,0 +1,149 @@
+//go:build e2e
+// +build e2e
+
+package scenario
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes"
+
+	. "github.com/kedacore/keda/v2/tests/helper"
+)
+
+// Run runs the scenario of a YAML file: it sets up the producers, creates the namespace with the templates not
+// applied by a step, runs the steps and deletes the namespace and the resources of the producers
+func Run(t *testing.T, path string) {
+	scenario, err := Load(path)
+	if !assert.NoErrorf(t, err, "cannot load scenario - %s", err) {
+		return
+	}
+
+	data := scenario.templateData()
+	producers := map[string]Producer{}
+	defer func() {
+		for name, producer := range producers {
+			assert.NoErrorf(t, producer.Cleanup(t), "cannot clean up producer %s", name)
+		}
+	}()
+	for name, spec := range scenario.Producers {
+		producer, vars, err := setupProducer(t, spec, data)
+		if !assert.NoErrorf(t, err, "cannot set up producer %s - %s", name, err) {
+			return
+		}
+		producers[name] = producer
+		data["Producers"].(map[string]map[string]string)[name] = vars
+	}
+
+	kc := GetKubernetesClient(t)
+	CreateNamespace(t, kc, scenario.Namespace)
+	defer DeleteNamespace(t, kc, scenario.Namespace)
+	KubectlApplyMultipleWithTemplate(t, data, scenario.initialTemplates())
+
+	for i, step := range scenario.Steps {
+		name := step.Name
+		if name == "" {
+			name = fmt.Sprintf("step %d", i)
+		}
+		t.Logf("--- %s ---", name)
+		if !runStep(t, kc, scenario, step, data, producers) {
+			t.Errorf("scenario %s failed at %s", scenario.Name, name)
+			return
+		}
+	}
+}
+
+func setupProducer(t *testing.T, spec ProducerSpec, data map[string]interface{}) (Producer, map[string]string, error) {
+	factory, err := getProducerFactory(spec.Type)
+	if err != nil {
+		return nil, nil, err
+	}
+	config := map[string]string{}
+	for key, value := range spec.Config {
+		if config[key], err = render(value, data); err != nil {
+			return nil, nil, fmt.Errorf("cannot render %s - %s", key, err)
+		}
+	}
+	producer, err := factory(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	vars, err := producer.Setup(t)
+	if err != nil {
+		return producer, nil, err
+	}
+	if vars == nil {
+		vars = map[string]string{}
+	}
+	return producer, vars, nil
+}
+
+// runStep runs a step and returns whether it succeeded
+func runStep(t *testing.T, kc *kubernetes.Clientset, scenario *Scenario, step Step, data map[string]interface{},
+	producers map[string]Producer) bool {
+	switch {
+	case len(step.Apply) > 0:
+		for _, name := range step.Apply {
+			KubectlApplyWithTemplate(t, data, name, scenario.Templates[name])
+		}
+		return !t.Failed()
+	case len(step.Delete) > 0:
+		for _, name := range step.Delete {
+			KubectlDeleteWithTemplate(t, data, name, scenario.Templates[name])
+		}
+		return !t.Failed()
+	case step.Publish != nil:
+		messages, err := renderMessages(step.Publish, data)
+		if !assert.NoErrorf(t, err, "cannot render messages - %s", err) {
+			return false
+		}
+		err = producers[step.Publish.Producer].Publish(t, messages)
+		return assert.NoErrorf(t, err, "cannot publish messages - %s", err)
+	case step.Purge != nil:
+		err := producers[step.Purge.Producer].Purge(t)
+		return assert.NoErrorf(t, err, "cannot purge messages - %s", err)
+	default:
+		return expectReplicas(t, kc, scenario.Namespace, step.ExpectReplicas)
+	}
+}
+
+func renderMessages(step *PublishStep, data map[string]interface{}) ([]string, error) {
+	message := step.Message
+	if message == "" {
+		message = defaultMessage
+	}
+
+	messages := make([]string, step.Count)
+	for i := range messages {
+		messageData := map[string]interface{}{"Index": i}
+		for key, value := range data {
+			messageData[key] = value
+		}
+		var err error
+		if messages[i], err = render(message, messageData); err != nil {
+			return nil, err
+		}
+	}
+	return messages, nil
+}
+
+func expectReplicas(t *testing.T, kc *kubernetes.Clientset, namespace string, step *ExpectReplicasStep) bool {
+	timeout := step.TimeoutSeconds
+	if timeout == 0 {
+		timeout = defaultExpectReplicasTimeoutSeconds
+	}
+
+	if step.MaxReplicas == 0 {
+		return assert.Truef(t, WaitForDeploymentReplicaCount(t, kc, step.Deployment, namespace, step.Count, timeout, 1),
+			"replica count should be %d after %d seconds", step.Count, timeout)
+	}
+
+	timeline := RecordDeploymentReplicaTimeline(t, kc, step.Deployment, namespace, 1)
+	defer timeline.Stop()
+	reached := assert.Truef(t, timeline.WaitForReplicaCount(step.Count, timeout),
+		"replica count should be %d after %d seconds", step.Count, timeout)
+	timeline.Stop()
+	return timeline.AssertReplicasNeverExceeded(t, step.MaxReplicas) && reached
+}
@@ -0,0 +1,203 @@
+//go:build e2e
+// +build e2e
+
+package scenario
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"text/template"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Scenario is an e2e test described in a YAML file: the templates of the Kubernetes resources, the producers of the
+// messages the scaler reads and the steps publishing messages and expecting replica counts.
+type Scenario struct {
+	// Name is the name of the test, the namespace is <name>-ns unless set
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	// Variables are the data of the templates, ${ENV} references are expanded
+	Variables map[string]string `json:"variables,omitempty"`
+	// Templates are the Kubernetes resources by name, applied in the namespace before the steps unless listed by a step
+	Templates map[string]string       `json:"templates"`
+	Producers map[string]ProducerSpec `json:"producers,omitempty"`
+	Steps     []Step                  `json:"steps"`
+}
+
+// ProducerSpec configures a producer, the values of the config are templates
+type ProducerSpec struct {
+	Type   string            `json:"type"`
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// Step is an action of the scenario, exactly one of the fields is set
+type Step struct {
+	Name           string              `json:"name,omitempty"`
+	Apply          []string            `json:"apply,omitempty"`
+	Delete         []string            `json:"delete,omitempty"`
+	Publish        *PublishStep        `json:"publish,omitempty"`
+	Purge          *PurgeStep          `json:"purge,omitempty"`
+	ExpectReplicas *ExpectReplicasStep `json:"expectReplicas,omitempty"`
+}
+
+// PublishStep publishes count messages with a producer, the message is a template with the .Index of the message
+type PublishStep struct {
+	Producer string `json:"producer"`
+	Count    int    `json:"count"`
+	Message  string `json:"message,omitempty"`
+}
+
+// PurgeStep removes all the messages of a producer
+type PurgeStep struct {
+	Producer string `json:"producer"`
+}
+
+// ExpectReplicasStep waits for a deployment to have a replica count
+type ExpectReplicasStep struct {
+	Deployment     string `json:"deployment"`
+	Count          int    `json:"count"`
+	TimeoutSeconds int    `json:"timeoutSeconds,omitempty"`
+	// MaxReplicas fails the step if the deployment has more replicas while waiting, 0 disables the check
+	MaxReplicas int `json:"maxReplicas,omitempty"`
+}
+
+const (
+	defaultExpectReplicasTimeoutSeconds = 180
+	defaultMessage                      = "Message - {{.Index}}"
+)
+
+var templateNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Load reads and validates the scenario of a YAML file
+func Load(path string) (*Scenario, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse parses and validates a scenario
+func Parse(data []byte) (*Scenario, error) {
+	scenario := &Scenario{}
+	if err := yaml.UnmarshalStrict(data, scenario); err != nil {
+		return nil, fmt.Errorf("error parsing the scenario: %s", err)
+	}
+	if err := scenario.validate(); err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %s", scenario.Name, err)
+	}
+	if scenario.Namespace == "" {
+		scenario.Namespace = fmt.Sprintf("%s-ns", scenario.Name)
+	}
+	return scenario, nil
+}
+
+func (s *Scenario) validate() error {
+	if s.Name == "" {
+		return errors.New("no name given")
+	}
+	if len(s.Templates) == 0 {
+		return errors.New("no templates given")
+	}
+	for name := range s.Templates {
+		if !templateNameRegex.MatchString(name) {
+			return fmt.Errorf("invalid template name %s", name)
+		}
+	}
+	for name, producer := range s.Producers {
+		if _, err := getProducerFactory(producer.Type); err != nil {
+			return fmt.Errorf("producer %s: %s", name, err)
+		}
+	}
+	if len(s.Steps) == 0 {
+		return errors.New("no steps given")
+	}
+
+	for i, step := range s.Steps {
+		actions := 0
+		for _, set := range []bool{len(step.Apply) > 0, len(step.Delete) > 0, step.Publish != nil, step.Purge != nil, step.ExpectReplicas != nil} {
+			if set {
+				actions++
+			}
+		}
+		if actions != 1 {
+			return fmt.Errorf("step %d: exactly one of apply, delete, publish, purge and expectReplicas must be set", i)
+		}
+
+		for _, name := range append(append([]string{}, step.Apply...), step.Delete...) {
+			if _, ok := s.Templates[name]; !ok {
+				return fmt.Errorf("step %d: unknown template %s", i, name)
+			}
+		}
+		switch {
+		case step.Publish != nil:
+			if _, ok := s.Producers[step.Publish.Producer]; !ok {
+				return fmt.Errorf("step %d: unknown producer %s", i, step.Publish.Producer)
+			}
+			if step.Publish.Count <= 0 {
+				return fmt.Errorf("step %d: the count of messages must be positive", i)
+			}
+		case step.Purge != nil:
+			if _, ok := s.Producers[step.Purge.Producer]; !ok {
+				return fmt.Errorf("step %d: unknown producer %s", i, step.Purge.Producer)
+			}
+		case step.ExpectReplicas != nil:
+			if step.ExpectReplicas.Deployment == "" {
+				return fmt.Errorf("step %d: no deployment given", i)
+			}
+			if step.ExpectReplicas.Count < 0 || step.ExpectReplicas.TimeoutSeconds < 0 || step.ExpectReplicas.MaxReplicas < 0 {
+				return fmt.Errorf("step %d: the count, timeout and max replicas can't be negative", i)
+			}
+		}
+	}
+	return nil
+}
+
+// initialTemplates returns the templates which aren't applied by a step
+func (s *Scenario) initialTemplates() map[string]string {
+	applied := map[string]bool{}
+	for _, step := range s.Steps {
+		for _, name := range step.Apply {
+			applied[name] = true
+		}
+	}
+
+	templates := map[string]string{}
+	for name, tmpl := range s.Templates {
+		if !applied[name] {
+			templates[name] = tmpl
+		}
+	}
+	return templates
+}
+
+// templateData returns the data of the templates: the expanded variables with the name and namespace of the scenario,
+// the variables of the producers are added to .Producers.<producer name> once they are set up
+func (s *Scenario) templateData() map[string]interface{} {
+	data := map[string]interface{}{}
+	for name, value := range s.Variables {
+		data[name] = os.ExpandEnv(value)
+	}
+	data["Name"] = s.Name
+	data["Namespace"] = s.Namespace
+	data["Producers"] = map[string]map[string]string{}
+	return data
+}
+
+// render executes a template with the data
+func render(text string, data interface{}) (string, error) {
+	tmpl, err := template.New("scenario").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}